@@ -0,0 +1,195 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/prysmaticlabs/prysm/v4/config/params"
+)
+
+// Used Beacon API and relay API routes
+const (
+	requestProposerDutiesRoute     = "http://%s/eth/v1/validator/duties/proposer/%d"
+	requestRelayRegistrationsRoute = "%s/relay/v1/builder/validators"
+)
+
+type proposerDutiesResponse struct {
+	// DependentRoot changes if the duties were computed from a different chain, the duties of the next epoch are
+	// final once the epoch starts
+	DependentRoot string `json:"dependent_root"`
+	Data          []struct {
+		Pubkey         string `json:"pubkey"`
+		ValidatorIndex uint64 `json:"validator_index,string"`
+		Slot           uint64 `json:"slot,string"`
+	} `json:"data"`
+}
+
+type relayRegistration struct {
+	Slot           uint64 `json:"slot,string"`
+	ValidatorIndex uint64 `json:"validator_index,string"`
+}
+
+// sentDuties are the proposer duties of an epoch that were sent to the gateway
+type sentDuties struct {
+	dependentRoot string
+	// final is true if the duties were requested during their epoch, when they cannot change anymore
+	final bool
+}
+
+// ProposerDutiesClient polls the Beacon API for the proposers of the current and next epochs, and the relays
+// for the validators registered with them, and sends the duties to the gateway over the bridge
+type ProposerDutiesClient struct {
+	URL        string
+	relays     []string
+	log        *log.Entry
+	bridge     blockchain.Bridge
+	clock      *utils.SlotClock
+	ctx        context.Context
+	httpClient *http.Client
+	sentEpochs map[uint64]sentDuties
+}
+
+// NewProposerDutiesClient creates a new ProposerDutiesClient for the beacon API URL, scheduled by the slot clock
+func NewProposerDutiesClient(ctx context.Context, httpClient *http.Client, bridge blockchain.Bridge, url string, relays []string, clock *utils.SlotClock) *ProposerDutiesClient {
+	return &ProposerDutiesClient{
+		URL:    url,
+		relays: relays,
+		log: log.WithFields(log.Fields{
+			"connType":   "beaconApiProposerDuties",
+			"remoteAddr": url,
+		}),
+		bridge:     bridge,
+		clock:      clock,
+		ctx:        ctx,
+		httpClient: httpClient,
+		sentEpochs: make(map[uint64]sentDuties),
+	}
+}
+
+// Start polls the proposer duties every slot until the context is done
+func (c *ProposerDutiesClient) Start() {
+	go func() {
+		ticker := c.clock.Ticker(c.clock.SlotDuration())
+		defer ticker.Stop()
+		for {
+			c.update()
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.Alert():
+			}
+		}
+	}()
+}
+
+func (c *ProposerDutiesClient) currentEpoch() uint64 {
+	return c.clock.CurrentSlot() / uint64(params.BeaconConfig().SlotsPerEpoch)
+}
+
+// update sends the duties of the current and next epochs that were not sent yet, or that changed since they were
+// sent. The duties of the next epoch are refreshed until the epoch starts.
+func (c *ProposerDutiesClient) update() {
+	epoch := c.currentEpoch()
+	for sentEpoch := range c.sentEpochs {
+		if sentEpoch < epoch {
+			delete(c.sentEpochs, sentEpoch)
+		}
+	}
+
+	var registrations map[uint64][]string
+	for _, e := range []uint64{epoch, epoch + 1} {
+		sent, ok := c.sentEpochs[e]
+		if ok && sent.final {
+			continue
+		}
+
+		duties, dependentRoot, err := c.requestProposerDuties(e)
+		if err != nil {
+			c.log.Warnf("failed to request proposer duties for epoch %v: %v", e, err)
+			continue
+		}
+		final := e == epoch
+		if ok && sent.dependentRoot == dependentRoot {
+			c.sentEpochs[e] = sentDuties{dependentRoot: dependentRoot, final: final}
+			continue
+		}
+		if ok {
+			c.log.Debugf("proposer duties for epoch %v changed, dependent root %v replaced %v", e, dependentRoot, sent.dependentRoot)
+		}
+
+		if registrations == nil {
+			registrations = c.requestRelayRegistrations()
+		}
+		for i := range duties {
+			duties[i].Relays = registrations[duties[i].Slot]
+		}
+
+		if err = c.bridge.SendProposerDuties(duties); err != nil {
+			c.log.Errorf("failed to send proposer duties for epoch %v to gateway: %v", e, err)
+			continue
+		}
+		c.sentEpochs[e] = sentDuties{dependentRoot: dependentRoot, final: final}
+	}
+}
+
+func (c *ProposerDutiesClient) requestProposerDuties(epoch uint64) ([]blockchain.ProposerDuty, string, error) {
+	var response proposerDutiesResponse
+	if err := c.getJSON(fmt.Sprintf(requestProposerDutiesRoute, c.URL, epoch), &response); err != nil {
+		return nil, "", err
+	}
+
+	duties := make([]blockchain.ProposerDuty, 0, len(response.Data))
+	for _, duty := range response.Data {
+		duties = append(duties, blockchain.ProposerDuty{
+			Slot:           duty.Slot,
+			ValidatorIndex: duty.ValidatorIndex,
+			Pubkey:         duty.Pubkey,
+		})
+	}
+	return duties, response.DependentRoot, nil
+}
+
+// requestRelayRegistrations returns the relays each upcoming slot proposer is registered with, by slot
+func (c *ProposerDutiesClient) requestRelayRegistrations() map[uint64][]string {
+	registrations := make(map[uint64][]string)
+	for _, relay := range c.relays {
+		var response []relayRegistration
+		if err := c.getJSON(fmt.Sprintf(requestRelayRegistrationsRoute, strings.TrimSuffix(relay, "/")), &response); err != nil {
+			c.log.Debugf("failed to request validator registrations from relay %v: %v", relay, err)
+			continue
+		}
+		for _, registration := range response {
+			registrations[registration.Slot] = append(registrations[registration.Slot], relay)
+		}
+	}
+	return registrations
+}
+
+func (c *ProposerDutiesClient) getJSON(uri string, v interface{}) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return fmt.Errorf("error in creating request: %v", err)
+	}
+	req.Header.Set("accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending the request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status %v", resp.Status)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error in decoding the response body: %v", err)
+	}
+	return nil
+}
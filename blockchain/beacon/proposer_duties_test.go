@@ -0,0 +1,61 @@
+package beacon
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	httpclient "github.com/bloXroute-Labs/gateway/v2/utils/httpclient"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProposerDutiesClient_update(t *testing.T) {
+	httpClient := httpclient.Client(nil)
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	relay := "https://relay.test"
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("http://%s/eth/v1/validator/duties/proposer/1", url),
+		httpmock.NewStringResponder(http.StatusOK, `{"data":[{"pubkey":"0x01","validator_index":"7","slot":"32"},{"pubkey":"0x02","validator_index":"8","slot":"33"}]}`))
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("http://%s/eth/v1/validator/duties/proposer/2", url),
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodGet, relay+"/relay/v1/builder/validators",
+		httpmock.NewStringResponder(http.StatusOK, `[{"slot":"33","validator_index":"8","entry":{}}]`))
+
+	bxBridge := blockchain.NewBxBridge(nil, true)
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(32*12, 0))
+	client := NewProposerDutiesClient(ctx, httpClient, bxBridge, url, []string{relay}, utils.NewSlotClock(clock, time.Unix(0, 0), 12*time.Second, "", 0))
+
+	client.update()
+
+	duties := <-bxBridge.ReceiveProposerDuties()
+	require.Len(t, duties, 2)
+	assert.Equal(t, blockchain.ProposerDuty{Slot: 32, ValidatorIndex: 7, Pubkey: "0x01"}, duties[0])
+	assert.Equal(t, []string{relay}, duties[1].Relays)
+
+	// the next epoch is retried, the current epoch is not sent again
+	client.update()
+	assert.Len(t, bxBridge.ReceiveProposerDuties(), 0)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://%s/eth/v1/validator/duties/proposer/1", url)])
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()[fmt.Sprintf("GET http://%s/eth/v1/validator/duties/proposer/2", url)])
+
+	// the duties of the next epoch are sent again only if they changed
+	nextEpochDuties := func(dependentRoot string) httpmock.Responder {
+		return httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"dependent_root":"%v","data":[{"pubkey":"0x03","validator_index":"9","slot":"64"}]}`, dependentRoot))
+	}
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("http://%s/eth/v1/validator/duties/proposer/2", url), nextEpochDuties("0x0a"))
+	client.update()
+	require.Len(t, <-bxBridge.ReceiveProposerDuties(), 1)
+	client.update()
+	assert.Len(t, bxBridge.ReceiveProposerDuties(), 0)
+
+	httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf("http://%s/eth/v1/validator/duties/proposer/2", url), nextEpochDuties("0x0b"))
+	client.update()
+	require.Len(t, <-bxBridge.ReceiveProposerDuties(), 1)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/blockchain/network"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 // NoActiveBlockchainPeersAlert is used to send an alert to the gateway on initial liveliness check if no active blockchain peers
@@ -33,6 +34,12 @@ type BlockFromNode struct {
 	PeerEndpoint types.NodeEndpoint
 }
 
+// BlockHeaderFromNode is used to pass the header of a block from a node to the gateway before its bodies are known
+type BlockHeaderFromNode struct {
+	Header       *ethtypes.Header
+	PeerEndpoint types.NodeEndpoint
+}
+
 // BlockAnnouncement represents an available block from a given peer that can be requested
 type BlockAnnouncement struct {
 	Hash         types.SHA256Hash
@@ -40,6 +47,19 @@ type BlockAnnouncement struct {
 	PeerEndpoint types.NodeEndpoint
 }
 
+// Heartbeat is sent to the blockchain backend by its supervisor, the backend acknowledges it with its peers
+type Heartbeat struct {
+	Sequence uint64
+	Peers    []types.NodeEndpoint
+}
+
+// BlockBackfillRequest asks the blockchain backend for the blocks of the heights From to To, inclusive, the gateway
+// missed
+type BlockBackfillRequest struct {
+	From uint64
+	To   uint64
+}
+
 // ConnectionStatus represents blockchain connection status
 type ConnectionStatus struct {
 	PeerEndpoint types.NodeEndpoint
@@ -63,7 +83,9 @@ const (
 	statusBacklog            = 10
 )
 
-// Bridge represents the application interface over which messages are passed between the blockchain node and the BDN
+// Bridge represents the application interface over which messages are passed between the blockchain node and the BDN,
+// BxBridge passes them in-process and RemoteBridge to a blockchain backend running in another process or host, over a
+// stream or gRPC transport
 type Bridge interface {
 	Converter
 
@@ -77,17 +99,20 @@ type Bridge interface {
 
 	ReceiveNodeTransactions() <-chan Transactions
 	ReceiveBDNTransactions() <-chan Transactions
+	ReceivePaidBDNTransactions() <-chan Transactions
 	ReceiveTransactionHashesAnnouncement() <-chan TransactionAnnouncement
 	ReceiveTransactionHashesRequest() <-chan TransactionAnnouncement
 
 	SendBlockToBDN(*types.BxBlock, types.NodeEndpoint) error
 	SendBlockToNode(*types.BxBlock) error
 	SendConfirmedBlockToGateway(block *types.BxBlock, peerEndpoint types.NodeEndpoint) error
+	SendBlockHeaderToGateway(header *ethtypes.Header, peerEndpoint types.NodeEndpoint) error
 
 	ReceiveEthBlockFromBDN() <-chan *types.BxBlock
 	ReceiveBeaconBlockFromBDN() <-chan *types.BxBlock
 	ReceiveBlockFromNode() <-chan BlockFromNode
 	ReceiveConfirmedBlockFromNode() <-chan BlockFromNode
+	ReceiveBlockHeaderFromNode() <-chan BlockHeaderFromNode
 
 	ReceiveNoActiveBlockchainPeersAlert() <-chan NoActiveBlockchainPeersAlert
 	SendNoActiveBlockchainPeersAlert() error
@@ -98,6 +123,8 @@ type Bridge interface {
 	ReceiveBlockchainStatusResponse() <-chan []*types.NodeEndpoint
 	SendValidatorListInfo(info *ValidatorListInfo) error
 	ReceiveValidatorListInfo() <-chan *ValidatorListInfo
+	SendProposerDuties(duties []ProposerDuty) error
+	ReceiveProposerDuties() <-chan []ProposerDuty
 	SendBlockchainConnectionStatus(ConnectionStatus) error
 	ReceiveBlockchainConnectionStatus() <-chan ConnectionStatus
 
@@ -108,6 +135,19 @@ type Bridge interface {
 
 	SendDisconnectEvent(endpoint types.NodeEndpoint) error
 	ReceiveDisconnectEvent() <-chan types.NodeEndpoint
+
+	SendHeartbeat(Heartbeat) error
+	ReceiveHeartbeat() <-chan Heartbeat
+	SendHeartbeatAck(Heartbeat) error
+	ReceiveHeartbeatAck() <-chan Heartbeat
+
+	SendBlockBackfillRequest(BlockBackfillRequest) error
+	ReceiveBlockBackfillRequest() <-chan BlockBackfillRequest
+	SendBackfilledBlock(BlockFromNode) error
+	ReceiveBackfilledBlock() <-chan BlockFromNode
+
+	ChannelSaturation() map[string]float64
+	ChannelFullCounts() map[string]uint64
 }
 
 // Errors
@@ -121,12 +161,21 @@ type ValidatorListInfo struct {
 	BlockHeight   uint64
 }
 
+// ProposerDuty is a struct for the proposer of an upcoming beacon slot and the relays it is registered with
+type ProposerDuty struct {
+	Slot           uint64
+	ValidatorIndex uint64
+	Pubkey         string
+	Relays         []string
+}
+
 // BxBridge is a channel based implementation of the Bridge interface
 type BxBridge struct {
 	Converter
 	config                    chan network.EthConfig
 	transactionsFromNode      chan Transactions
 	transactionsFromBDN       chan Transactions
+	paidTransactionsFromBDN   chan Transactions
 	transactionHashesFromNode chan TransactionAnnouncement
 	transactionHashesRequests chan TransactionAnnouncement
 
@@ -137,6 +186,7 @@ type BxBridge struct {
 	beaconBlocksFromBDN chan *types.BxBlock
 
 	confirmedBlockFromNode chan BlockFromNode
+	blockHeadersFromNode   chan BlockHeaderFromNode
 
 	noActiveBlockchainPeers chan NoActiveBlockchainPeersAlert
 
@@ -147,6 +197,20 @@ type BxBridge struct {
 	blockchainConnectionStatus  chan ConnectionStatus
 	disconnectEvent             chan types.NodeEndpoint
 	validatorInfo               chan *ValidatorListInfo
+	proposerDuties              chan []ProposerDuty
+	heartbeats                  chan Heartbeat
+	heartbeatAcks               chan Heartbeat
+	blockBackfillRequests       chan BlockBackfillRequest
+	backfilledBlocks            chan BlockFromNode
+
+	channelFull channelFullCounters
+
+	// overflow buffers of the transaction channels, nil unless the bridge is adaptive
+	transactionsFromNodeOverflow      *overflowBuffer[Transactions]
+	transactionsFromBDNOverflow       *overflowBuffer[Transactions]
+	paidTransactionsFromBDNOverflow   *overflowBuffer[Transactions]
+	transactionHashesFromNodeOverflow *overflowBuffer[TransactionAnnouncement]
+	transactionHashesRequestsOverflow *overflowBuffer[TransactionAnnouncement]
 }
 
 // NewBxBridge returns a BxBridge instance
@@ -155,6 +219,7 @@ func NewBxBridge(converter Converter, beaconBlock bool) Bridge {
 		config:                      make(chan network.EthConfig, 1),
 		transactionsFromNode:        make(chan Transactions, transactionBacklog),
 		transactionsFromBDN:         make(chan Transactions, transactionBacklog),
+		paidTransactionsFromBDN:     make(chan Transactions, transactionBacklog),
 		transactionHashesFromNode:   make(chan TransactionAnnouncement, transactionHashesBacklog),
 		transactionHashesRequests:   make(chan TransactionAnnouncement, transactionHashesBacklog),
 		beaconBlock:                 beaconBlock,
@@ -162,6 +227,7 @@ func NewBxBridge(converter Converter, beaconBlock bool) Bridge {
 		ethBlocksFromBDN:            make(chan *types.BxBlock, blockBacklog),
 		beaconBlocksFromBDN:         make(chan *types.BxBlock, blockBacklog),
 		confirmedBlockFromNode:      make(chan BlockFromNode, blockBacklog),
+		blockHeadersFromNode:        make(chan BlockHeaderFromNode, blockBacklog),
 		noActiveBlockchainPeers:     make(chan NoActiveBlockchainPeersAlert),
 		blockchainStatusRequest:     make(chan struct{}, statusBacklog),
 		blockchainStatusResponse:    make(chan []*types.NodeEndpoint, statusBacklog),
@@ -171,9 +237,27 @@ func NewBxBridge(converter Converter, beaconBlock bool) Bridge {
 		disconnectEvent:             make(chan types.NodeEndpoint, statusBacklog),
 		Converter:                   converter,
 		validatorInfo:               make(chan *ValidatorListInfo, 1),
+		proposerDuties:              make(chan []ProposerDuty, statusBacklog),
+		heartbeats:                  make(chan Heartbeat, statusBacklog),
+		heartbeatAcks:               make(chan Heartbeat, statusBacklog),
+		blockBackfillRequests:       make(chan BlockBackfillRequest, statusBacklog),
+		backfilledBlocks:            make(chan BlockFromNode, blockBacklog),
+		channelFull:                 newChannelFullCounters(),
 	}
 }
 
+// NewAdaptiveBxBridge returns a BxBridge instance which grows the buffers of the transaction channels by up to
+// overflowCap messages each under sustained pressure, instead of dropping the transactions sent while they are full
+func NewAdaptiveBxBridge(converter Converter, beaconBlock bool, overflowCap int) Bridge {
+	b := NewBxBridge(converter, beaconBlock).(*BxBridge)
+	b.transactionsFromNodeOverflow = newOverflowBuffer(b.transactionsFromNode, overflowCap)
+	b.transactionsFromBDNOverflow = newOverflowBuffer(b.transactionsFromBDN, overflowCap)
+	b.paidTransactionsFromBDNOverflow = newOverflowBuffer(b.paidTransactionsFromBDN, overflowCap)
+	b.transactionHashesFromNodeOverflow = newOverflowBuffer(b.transactionHashesFromNode, overflowCap)
+	b.transactionHashesRequestsOverflow = newOverflowBuffer(b.transactionHashesRequests, overflowCap)
+	return b
+}
+
 // ReceiveNetworkConfigUpdates provides a channel with network config updates
 func (b *BxBridge) ReceiveNetworkConfigUpdates() <-chan network.EthConfig {
 	return b.config
@@ -187,42 +271,55 @@ func (b *BxBridge) UpdateNetworkConfig(config network.EthConfig) error {
 
 // AnnounceTransactionHashes pushes a series of transaction announcements onto the announcements channel
 func (b BxBridge) AnnounceTransactionHashes(peerID string, hashes types.SHA256HashList, endpoint types.NodeEndpoint) error {
-	select {
-	case b.transactionHashesFromNode <- TransactionAnnouncement{Hashes: hashes, PeerID: peerID, PeerEndpoint: endpoint}:
+	if sendOrOverflow(b.transactionHashesFromNode, b.transactionHashesFromNodeOverflow, TransactionAnnouncement{Hashes: hashes, PeerID: peerID, PeerEndpoint: endpoint}) {
 		return nil
-	default:
-		return ErrChannelFull
 	}
+	return b.channelFull.full(channelTransactionHashesFromNode)
 }
 
 // RequestTransactionsFromNode requests a series of transactions that a peer node has announced
 func (b BxBridge) RequestTransactionsFromNode(peerID string, hashes types.SHA256HashList) error {
-	select {
-	case b.transactionHashesRequests <- TransactionAnnouncement{Hashes: hashes, PeerID: peerID}:
+	if sendOrOverflow(b.transactionHashesRequests, b.transactionHashesRequestsOverflow, TransactionAnnouncement{Hashes: hashes, PeerID: peerID}) {
 		return nil
-	default:
-		return ErrChannelFull
 	}
+	return b.channelFull.full(channelTransactionHashesRequests)
 }
 
-// SendTransactionsFromBDN sends a set of transactions from the BDN for distribution to nodes
+// SendTransactionsFromBDN sends a set of transactions from the BDN for distribution to nodes. The paid and next
+// validator transactions are sent on the priority lane, which the nodes are delivered ahead of the bulk traffic.
 func (b BxBridge) SendTransactionsFromBDN(transactions Transactions) error {
-	select {
-	case b.transactionsFromBDN <- transactions:
-		return nil
-	default:
-		return ErrChannelFull
+	paid, bulk := splitPaidTransactions(transactions)
+
+	var err error
+	if len(paid.Transactions) > 0 && !sendOrOverflow(b.paidTransactionsFromBDN, b.paidTransactionsFromBDNOverflow, paid) {
+		err = b.channelFull.full(channelPaidTransactionsFromBDN)
+	}
+	if len(bulk.Transactions) > 0 && !sendOrOverflow(b.transactionsFromBDN, b.transactionsFromBDNOverflow, bulk) {
+		err = b.channelFull.full(channelTransactionsFromBDN)
 	}
+	return err
+}
+
+// splitPaidTransactions separates the paid and next validator transactions from the bulk ones
+func splitPaidTransactions(transactions Transactions) (paid Transactions, bulk Transactions) {
+	paid = Transactions{PeerEndpoint: transactions.PeerEndpoint, ConnectionType: transactions.ConnectionType}
+	bulk = paid
+	for _, tx := range transactions.Transactions {
+		if tx.Flags().IsPaidTx() || tx.Flags().IsNextValidator() {
+			paid.Transactions = append(paid.Transactions, tx)
+		} else {
+			bulk.Transactions = append(bulk.Transactions, tx)
+		}
+	}
+	return paid, bulk
 }
 
 // SendTransactionsToBDN sends a set of transactions from a node to the BDN for propagation
 func (b BxBridge) SendTransactionsToBDN(txs []*types.BxTransaction, peerEndpoint types.NodeEndpoint) error {
-	select {
-	case b.transactionsFromNode <- Transactions{Transactions: txs, PeerEndpoint: peerEndpoint}:
+	if sendOrOverflow(b.transactionsFromNode, b.transactionsFromNodeOverflow, Transactions{Transactions: txs, PeerEndpoint: peerEndpoint}) {
 		return nil
-	default:
-		return ErrChannelFull
 	}
+	return b.channelFull.full(channelTransactionsFromNode)
 }
 
 // SendConfirmedBlockToGateway sends a SHA256 of the block to be included in blockConfirm message
@@ -231,7 +328,17 @@ func (b BxBridge) SendConfirmedBlockToGateway(block *types.BxBlock, peerEndpoint
 	case b.confirmedBlockFromNode <- BlockFromNode{Block: block, PeerEndpoint: peerEndpoint}:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelConfirmedBlocksFromNode)
+	}
+}
+
+// SendBlockHeaderToGateway sends the header of a block from a node as soon as it is known, before its bodies
+func (b BxBridge) SendBlockHeaderToGateway(header *ethtypes.Header, peerEndpoint types.NodeEndpoint) error {
+	select {
+	case b.blockHeadersFromNode <- BlockHeaderFromNode{Header: header, PeerEndpoint: peerEndpoint}:
+		return nil
+	default:
+		return b.channelFull.full(channelBlockHeadersFromNode)
 	}
 }
 
@@ -245,6 +352,12 @@ func (b BxBridge) ReceiveBDNTransactions() <-chan Transactions {
 	return b.transactionsFromBDN
 }
 
+// ReceivePaidBDNTransactions provides a channel that pushes the paid and next validator transactions as they arrive
+// from the BDN, to be delivered to the nodes ahead of the ones of ReceiveBDNTransactions
+func (b BxBridge) ReceivePaidBDNTransactions() <-chan Transactions {
+	return b.paidTransactionsFromBDN
+}
+
 // ReceiveTransactionHashesAnnouncement provides a channel that pushes announcements as nodes announce them
 func (b BxBridge) ReceiveTransactionHashesAnnouncement() <-chan TransactionAnnouncement {
 	return b.transactionHashesFromNode
@@ -261,7 +374,7 @@ func (b BxBridge) SendBlockToBDN(block *types.BxBlock, peerEndpoint types.NodeEn
 	case b.blocksFromNode <- BlockFromNode{Block: block, PeerEndpoint: peerEndpoint}:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelBlocksFromNode)
 	}
 }
 
@@ -272,7 +385,7 @@ func (b BxBridge) SendBlockToNode(block *types.BxBlock) error {
 		select {
 		case b.ethBlocksFromBDN <- block:
 		default:
-			return ErrChannelFull
+			return b.channelFull.full(channelEthBlocksFromBDN)
 		}
 	case types.BxBlockTypeBeaconPhase0, types.BxBlockTypeBeaconAltair, types.BxBlockTypeBeaconBellatrix, types.BxBlockTypeBeaconCapella:
 		// No listener, `b.beaconBlock` is true if the gateway started with a beacon P2P node or Beacon API
@@ -283,7 +396,7 @@ func (b BxBridge) SendBlockToNode(block *types.BxBlock) error {
 		select {
 		case b.beaconBlocksFromBDN <- block:
 		default:
-			return ErrChannelFull
+			return b.channelFull.full(channelBeaconBlocksFromBDN)
 		}
 	default:
 		return fmt.Errorf("could not send block %v with type %v", block.Hash(), block.Type)
@@ -312,13 +425,18 @@ func (b BxBridge) ReceiveConfirmedBlockFromNode() <-chan BlockFromNode {
 	return b.confirmedBlockFromNode
 }
 
+// ReceiveBlockHeaderFromNode provides a channel that pushes the headers of the blocks from nodes before their bodies
+func (b BxBridge) ReceiveBlockHeaderFromNode() <-chan BlockHeaderFromNode {
+	return b.blockHeadersFromNode
+}
+
 // SendNoActiveBlockchainPeersAlert sends alerts to the BDN when there is no active blockchain peer
 func (b BxBridge) SendNoActiveBlockchainPeersAlert() error {
 	select {
 	case b.noActiveBlockchainPeers <- NoActiveBlockchainPeersAlert{}:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelNoActiveBlockchainPeers)
 	}
 }
 
@@ -333,7 +451,7 @@ func (b BxBridge) SendBlockchainStatusRequest() error {
 	case b.blockchainStatusRequest <- struct{}{}:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelBlockchainStatusRequests)
 	}
 }
 
@@ -348,7 +466,7 @@ func (b BxBridge) SendBlockchainStatusResponse(endpoints []*types.NodeEndpoint)
 	case b.blockchainStatusResponse <- endpoints:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelBlockchainStatusResponses)
 	}
 }
 
@@ -363,7 +481,7 @@ func (b BxBridge) SendNodeConnectionCheckRequest() error {
 	case b.nodeConnectionCheckRequest <- struct{}{}:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelNodeConnectionCheckRequests)
 	}
 }
 
@@ -378,7 +496,7 @@ func (b BxBridge) SendNodeConnectionCheckResponse(endpoints types.NodeEndpoint)
 	case b.nodeConnectionCheckResponse <- endpoints:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelNodeConnectionCheckResponses)
 	}
 }
 
@@ -393,7 +511,7 @@ func (b *BxBridge) SendValidatorListInfo(info *ValidatorListInfo) error {
 	case b.validatorInfo <- info:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelValidatorInfo)
 	}
 }
 
@@ -402,13 +520,28 @@ func (b *BxBridge) ReceiveValidatorListInfo() <-chan *ValidatorListInfo {
 	return b.validatorInfo
 }
 
+// SendProposerDuties sends the proposers of upcoming beacon slots to gateway
+func (b *BxBridge) SendProposerDuties(duties []ProposerDuty) error {
+	select {
+	case b.proposerDuties <- duties:
+		return nil
+	default:
+		return b.channelFull.full(channelProposerDuties)
+	}
+}
+
+// ReceiveProposerDuties called by gateway to receive the proposers of upcoming beacon slots
+func (b *BxBridge) ReceiveProposerDuties() <-chan []ProposerDuty {
+	return b.proposerDuties
+}
+
 // SendBlockchainConnectionStatus sends blockchain connection status
 func (b BxBridge) SendBlockchainConnectionStatus(connStatus ConnectionStatus) error {
 	select {
 	case b.blockchainConnectionStatus <- connStatus:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelBlockchainConnectionStatus)
 	}
 }
 
@@ -423,7 +556,7 @@ func (b BxBridge) SendDisconnectEvent(endpoint types.NodeEndpoint) error {
 	case b.disconnectEvent <- endpoint:
 		return nil
 	default:
-		return ErrChannelFull
+		return b.channelFull.full(channelDisconnectEvents)
 	}
 
 }
@@ -432,3 +565,92 @@ func (b BxBridge) SendDisconnectEvent(endpoint types.NodeEndpoint) error {
 func (b BxBridge) ReceiveDisconnectEvent() <-chan types.NodeEndpoint {
 	return b.disconnectEvent
 }
+
+// SendHeartbeat sends a heartbeat to the blockchain backend
+func (b BxBridge) SendHeartbeat(heartbeat Heartbeat) error {
+	select {
+	case b.heartbeats <- heartbeat:
+		return nil
+	default:
+		return b.channelFull.full(channelHeartbeats)
+	}
+}
+
+// ReceiveHeartbeat provides a channel that pushes the heartbeats to the blockchain backend
+func (b BxBridge) ReceiveHeartbeat() <-chan Heartbeat {
+	return b.heartbeats
+}
+
+// SendHeartbeatAck acknowledges a heartbeat from the blockchain backend
+func (b BxBridge) SendHeartbeatAck(heartbeat Heartbeat) error {
+	select {
+	case b.heartbeatAcks <- heartbeat:
+		return nil
+	default:
+		return b.channelFull.full(channelHeartbeatAcks)
+	}
+}
+
+// ReceiveHeartbeatAck provides a channel that pushes the acknowledgements of the heartbeats by the blockchain backend
+func (b BxBridge) ReceiveHeartbeatAck() <-chan Heartbeat {
+	return b.heartbeatAcks
+}
+
+// SendBlockBackfillRequest asks the blockchain backend for the blocks the gateway missed
+func (b BxBridge) SendBlockBackfillRequest(request BlockBackfillRequest) error {
+	select {
+	case b.blockBackfillRequests <- request:
+		return nil
+	default:
+		return b.channelFull.full(channelBlockBackfillRequests)
+	}
+}
+
+// ReceiveBlockBackfillRequest provides a channel that pushes the requests of the blocks the gateway missed
+func (b BxBridge) ReceiveBlockBackfillRequest() <-chan BlockBackfillRequest {
+	return b.blockBackfillRequests
+}
+
+// SendBackfilledBlock sends a block the gateway missed to the gateway
+func (b BxBridge) SendBackfilledBlock(block BlockFromNode) error {
+	select {
+	case b.backfilledBlocks <- block:
+		return nil
+	default:
+		return b.channelFull.full(channelBackfilledBlocks)
+	}
+}
+
+// ReceiveBackfilledBlock provides a channel that pushes the blocks the gateway missed, in the order of their heights
+func (b BxBridge) ReceiveBackfilledBlock() <-chan BlockFromNode {
+	return b.backfilledBlocks
+}
+
+// ChannelSaturation returns the share of the buffer of the data channels which is in use, by the name of the channel.
+// The share of a transaction channel of an adaptive bridge exceeds 1 while its overflow buffer is in use.
+func (b BxBridge) ChannelSaturation() map[string]float64 {
+	saturation := func(length, capacity int) float64 {
+		if capacity == 0 {
+			return 0
+		}
+		return float64(length) / float64(capacity)
+	}
+	return map[string]float64{
+		channelTransactionsFromNode:      saturation(len(b.transactionsFromNode)+b.transactionsFromNodeOverflow.len(), cap(b.transactionsFromNode)),
+		channelTransactionsFromBDN:       saturation(len(b.transactionsFromBDN)+b.transactionsFromBDNOverflow.len(), cap(b.transactionsFromBDN)),
+		channelPaidTransactionsFromBDN:   saturation(len(b.paidTransactionsFromBDN)+b.paidTransactionsFromBDNOverflow.len(), cap(b.paidTransactionsFromBDN)),
+		channelTransactionHashesFromNode: saturation(len(b.transactionHashesFromNode)+b.transactionHashesFromNodeOverflow.len(), cap(b.transactionHashesFromNode)),
+		channelTransactionHashesRequests: saturation(len(b.transactionHashesRequests)+b.transactionHashesRequestsOverflow.len(), cap(b.transactionHashesRequests)),
+		channelBlocksFromNode:            saturation(len(b.blocksFromNode), cap(b.blocksFromNode)),
+		channelEthBlocksFromBDN:          saturation(len(b.ethBlocksFromBDN), cap(b.ethBlocksFromBDN)),
+		channelBeaconBlocksFromBDN:       saturation(len(b.beaconBlocksFromBDN), cap(b.beaconBlocksFromBDN)),
+		channelConfirmedBlocksFromNode:   saturation(len(b.confirmedBlockFromNode), cap(b.confirmedBlockFromNode)),
+		channelBlockHeadersFromNode:      saturation(len(b.blockHeadersFromNode), cap(b.blockHeadersFromNode)),
+		channelBackfilledBlocks:          saturation(len(b.backfilledBlocks), cap(b.backfilledBlocks)),
+	}
+}
+
+// ChannelFullCounts returns the number of messages dropped because their channel was full, by the name of the channel
+func (b BxBridge) ChannelFullCounts() map[string]uint64 {
+	return b.channelFull.counts()
+}
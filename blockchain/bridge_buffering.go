@@ -0,0 +1,154 @@
+package blockchain
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// names of the BxBridge channels in the saturation and channel full metrics
+const (
+	channelTransactionsFromNode         = "transactions_from_node"
+	channelTransactionsFromBDN          = "transactions_from_bdn"
+	channelPaidTransactionsFromBDN      = "paid_transactions_from_bdn"
+	channelTransactionHashesFromNode    = "transaction_hashes_from_node"
+	channelTransactionHashesRequests    = "transaction_hashes_requests"
+	channelBlocksFromNode               = "blocks_from_node"
+	channelEthBlocksFromBDN             = "eth_blocks_from_bdn"
+	channelBeaconBlocksFromBDN          = "beacon_blocks_from_bdn"
+	channelConfirmedBlocksFromNode      = "confirmed_blocks_from_node"
+	channelBlockHeadersFromNode         = "block_headers_from_node"
+	channelNoActiveBlockchainPeers      = "no_active_blockchain_peers"
+	channelBlockchainStatusRequests     = "blockchain_status_requests"
+	channelBlockchainStatusResponses    = "blockchain_status_responses"
+	channelNodeConnectionCheckRequests  = "node_connection_check_requests"
+	channelNodeConnectionCheckResponses = "node_connection_check_responses"
+	channelBlockchainConnectionStatus   = "blockchain_connection_status"
+	channelDisconnectEvents             = "disconnect_events"
+	channelValidatorInfo                = "validator_info"
+	channelProposerDuties               = "proposer_duties"
+	channelHeartbeats                   = "heartbeats"
+	channelHeartbeatAcks                = "heartbeat_acks"
+	channelBlockBackfillRequests        = "block_backfill_requests"
+	channelBackfilledBlocks             = "backfilled_blocks"
+)
+
+var bridgeChannels = []string{
+	channelTransactionsFromNode, channelTransactionsFromBDN, channelPaidTransactionsFromBDN, channelTransactionHashesFromNode,
+	channelTransactionHashesRequests,
+	channelBlocksFromNode, channelEthBlocksFromBDN, channelBeaconBlocksFromBDN, channelConfirmedBlocksFromNode,
+	channelBlockHeadersFromNode, channelNoActiveBlockchainPeers, channelBlockchainStatusRequests, channelBlockchainStatusResponses,
+	channelNodeConnectionCheckRequests, channelNodeConnectionCheckResponses, channelBlockchainConnectionStatus,
+	channelDisconnectEvents, channelValidatorInfo, channelProposerDuties, channelHeartbeats, channelHeartbeatAcks,
+	channelBlockBackfillRequests, channelBackfilledBlocks,
+}
+
+// channelFullCounters counts the messages dropped by each channel of the bridge because it was full
+type channelFullCounters map[string]*atomic.Uint64
+
+func newChannelFullCounters() channelFullCounters {
+	counters := make(channelFullCounters, len(bridgeChannels))
+	for _, channel := range bridgeChannels {
+		counters[channel] = &atomic.Uint64{}
+	}
+	return counters
+}
+
+// full counts a message dropped by the channel and returns ErrChannelFull
+func (c channelFullCounters) full(channel string) error {
+	c[channel].Add(1)
+	return ErrChannelFull
+}
+
+func (c channelFullCounters) counts() map[string]uint64 {
+	counts := make(map[string]uint64, len(c))
+	for channel, counter := range c {
+		counts[channel] = counter.Load()
+	}
+	return counts
+}
+
+// overflowBuffer grows the buffer of a channel up to its cap: the messages sent while the channel is full are queued
+// and fed to the channel in order as it drains, instead of being dropped. The goroutine feeding the channel only runs
+// while messages are queued.
+type overflowBuffer[T any] struct {
+	ch        chan T
+	maxQueued int
+	lock      sync.Mutex
+	queue     []T
+	draining  bool
+}
+
+func newOverflowBuffer[T any](ch chan T, maxQueued int) *overflowBuffer[T] {
+	return &overflowBuffer[T]{ch: ch, maxQueued: maxQueued}
+}
+
+// send sends the message to the channel, or queues it behind the messages already waiting for the channel. It
+// returns false if the queue reached its cap.
+func (o *overflowBuffer[T]) send(message T) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !o.draining {
+		select {
+		case o.ch <- message:
+			return true
+		default:
+		}
+	}
+	if len(o.queue) >= o.maxQueued {
+		return false
+	}
+	o.queue = append(o.queue, message)
+	if !o.draining {
+		o.draining = true
+		go o.drain()
+	}
+	return true
+}
+
+// len returns the number of queued messages
+func (o *overflowBuffer[T]) len() int {
+	if o == nil {
+		return 0
+	}
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return len(o.queue)
+}
+
+// drain feeds the queued messages to the channel, the message at the head of the queue stays queued until the
+// channel takes it so the messages sent meanwhile are queued behind it
+func (o *overflowBuffer[T]) drain() {
+	for {
+		o.lock.Lock()
+		if len(o.queue) == 0 {
+			o.draining = false
+			o.lock.Unlock()
+			return
+		}
+		message := o.queue[0]
+		o.lock.Unlock()
+
+		o.ch <- message
+
+		o.lock.Lock()
+		var zero T
+		o.queue[0] = zero
+		o.queue = o.queue[1:]
+		o.lock.Unlock()
+	}
+}
+
+// sendOrOverflow sends the message to the channel, or to its overflow buffer if the bridge is adaptive. It returns
+// false if the message was dropped.
+func sendOrOverflow[T any](ch chan T, overflow *overflowBuffer[T], message T) bool {
+	if overflow != nil {
+		return overflow.send(message)
+	}
+	select {
+	case ch <- message:
+		return true
+	default:
+		return false
+	}
+}
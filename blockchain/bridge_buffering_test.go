@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBxBridge_ChannelFullCounts(t *testing.T) {
+	bridge := NewBxBridge(nil, false)
+	for i := 0; i < blockBacklog; i++ {
+		require.NoError(t, bridge.SendBlockToBDN(nil, types.NodeEndpoint{}))
+	}
+	assert.ErrorIs(t, bridge.SendBlockToBDN(nil, types.NodeEndpoint{}), ErrChannelFull)
+	assert.ErrorIs(t, bridge.SendBlockToBDN(nil, types.NodeEndpoint{}), ErrChannelFull)
+
+	counts := bridge.ChannelFullCounts()
+	assert.Len(t, counts, len(bridgeChannels))
+	assert.Equal(t, uint64(2), counts[channelBlocksFromNode])
+	assert.Equal(t, uint64(0), counts[channelTransactionsFromNode])
+	assert.Equal(t, float64(1), bridge.ChannelSaturation()[channelBlocksFromNode])
+}
+
+func TestAdaptiveBxBridge(t *testing.T) {
+	bridge := NewAdaptiveBxBridge(nil, false, 2)
+	txs := []*types.BxTransaction{types.NewBxTransaction(types.SHA256Hash{1}, 5, types.TFDeliverToNode, time.Now())}
+	for i := 0; i < transactionBacklog+2; i++ {
+		require.NoError(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: txs, PeerEndpoint: types.NodeEndpoint{Port: i}}))
+	}
+	// the overflow buffer reached its cap
+	assert.ErrorIs(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: txs}), ErrChannelFull)
+	assert.Equal(t, uint64(1), bridge.ChannelFullCounts()[channelTransactionsFromBDN])
+	assert.Greater(t, bridge.ChannelSaturation()[channelTransactionsFromBDN], float64(1))
+
+	// the overflowing transactions follow the buffered ones in order
+	for i := 0; i < transactionBacklog+2; i++ {
+		select {
+		case txs := <-bridge.ReceiveBDNTransactions():
+			assert.Equal(t, i, txs.PeerEndpoint.Port)
+		case <-time.After(time.Second):
+			t.Fatalf("transactions %v were not received", i)
+		}
+	}
+	assert.Eventually(t, func() bool {
+		return bridge.ChannelSaturation()[channelTransactionsFromBDN] == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestBxBridge_PaidTransactionsLane(t *testing.T) {
+	bridge := NewBxBridge(nil, false)
+	free := types.NewBxTransaction(types.SHA256Hash{1}, 5, types.TFDeliverToNode, time.Now())
+	paid := types.NewBxTransaction(types.SHA256Hash{2}, 5, types.TFPaidTx|types.TFDeliverToNode, time.Now())
+	nextValidator := types.NewBxTransaction(types.SHA256Hash{3}, 5, types.TFNextValidator, time.Now())
+	endpoint := types.NodeEndpoint{IP: "1.1.1.1", Port: 1}
+
+	require.NoError(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: []*types.BxTransaction{free, paid, nextValidator}, PeerEndpoint: endpoint}))
+	paidTxs := <-bridge.ReceivePaidBDNTransactions()
+	assert.Equal(t, []*types.BxTransaction{paid, nextValidator}, paidTxs.Transactions)
+	assert.Equal(t, endpoint, paidTxs.PeerEndpoint)
+	bulkTxs := <-bridge.ReceiveBDNTransactions()
+	assert.Equal(t, []*types.BxTransaction{free}, bulkTxs.Transactions)
+	assert.Equal(t, endpoint, bulkTxs.PeerEndpoint)
+
+	// the bulk lane being full does not hold back the paid transactions
+	for i := 0; i < transactionBacklog; i++ {
+		require.NoError(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: []*types.BxTransaction{free}}))
+	}
+	assert.ErrorIs(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: []*types.BxTransaction{free}}), ErrChannelFull)
+	require.NoError(t, bridge.SendTransactionsFromBDN(Transactions{Transactions: []*types.BxTransaction{paid}}))
+	assert.Equal(t, []*types.BxTransaction{paid}, (<-bridge.ReceivePaidBDNTransactions()).Transactions)
+}
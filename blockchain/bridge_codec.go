@@ -0,0 +1,404 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/network"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// BridgeMessageType identifies the Bridge method a message is sent with
+type BridgeMessageType uint8
+
+// BridgeMessageType types, the payload of each type is the argument of the Bridge method
+const (
+	BridgeNetworkConfig               BridgeMessageType = iota + 1 // network.EthConfig
+	BridgeTransactionAnnouncement                                  // TransactionAnnouncement
+	BridgeTransactionsRequest                                      // TransactionAnnouncement
+	BridgeTransactionsFromBDN                                      // Transactions
+	BridgeTransactionsToBDN                                        // Transactions
+	BridgeBlockToBDN                                               // BlockFromNode
+	BridgeBlockToNode                                              // *types.BxBlock
+	BridgeConfirmedBlock                                           // BlockFromNode
+	BridgeBlockHeader                                              // BlockHeaderFromNode
+	BridgeNoActivePeersAlert                                       // no payload
+	BridgeStatusRequest                                            // no payload
+	BridgeStatusResponse                                           // []*types.NodeEndpoint
+	BridgeNodeConnectionCheckRequest                               // no payload
+	BridgeNodeConnectionCheckResponse                              // types.NodeEndpoint
+	BridgeValidatorListInfo                                        // *ValidatorListInfo
+	BridgeProposerDuties                                           // []ProposerDuty
+	BridgeConnectionStatus                                         // ConnectionStatus
+	BridgeDisconnectEvent                                          // types.NodeEndpoint
+	BridgeHeartbeat                                                // Heartbeat
+	BridgeHeartbeatAck                                             // Heartbeat
+	BridgeBlockBackfillRequest                                     // BlockBackfillRequest
+	BridgeBackfilledBlock                                          // BlockFromNode
+)
+
+// BridgeMessage is a call of a Bridge method passed to the other end of a RemoteBridge
+type BridgeMessage struct {
+	Type    BridgeMessageType
+	Payload interface{}
+}
+
+// BridgeCodec serializes the messages of a RemoteBridge for its transport
+type BridgeCodec interface {
+	Encode(BridgeMessage) ([]byte, error)
+	Decode([]byte) (BridgeMessage, error)
+}
+
+// GobBridgeCodec is a BridgeCodec encoding the messages with encoding/gob
+type GobBridgeCodec struct{}
+
+// gobBridgeMessage holds the payload of a message in the field of its type, the fields of the other types are empty
+type gobBridgeMessage struct {
+	Type BridgeMessageType
+
+	Config           *gobEthConfig
+	Announcement     *TransactionAnnouncement
+	Transactions     []gobTransaction
+	ConnectionType   utils.NodeType
+	Block            *gobBlock
+	Header           []byte
+	PeerEndpoint     types.NodeEndpoint
+	Endpoints        []*types.NodeEndpoint
+	ValidatorList    *ValidatorListInfo
+	ProposerDuties   []ProposerDuty
+	ConnectionStatus ConnectionStatus
+	Heartbeat        Heartbeat
+	BackfillRequest  BlockBackfillRequest
+}
+
+type gobTransaction struct {
+	Hash       types.SHA256Hash
+	Content    types.TxContent
+	ShortIDs   types.ShortIDList
+	Flags      types.TxFlags
+	NetworkNum types.NetworkNum
+	Sender     types.Sender
+	AddTime    time.Time
+}
+
+type gobBlockTransaction struct {
+	Hash    types.SHA256Hash
+	Content []byte
+}
+
+type gobBlock struct {
+	Hash            types.SHA256Hash
+	BeaconHash      types.SHA256Hash
+	Type            types.BxBlockType
+	Header          []byte
+	Txs             []gobBlockTransaction
+	Trailer         []byte
+	TotalDifficulty *big.Int
+	Number          *big.Int
+	Size            int
+}
+
+type gobPeerInfo struct {
+	Enode        string
+	Multiaddr    string
+	EthWSURI     string
+	PrysmAddr    string
+	BeaconAPIURI string
+}
+
+// gobEthConfig replaces the nodes and private key of network.EthConfig, which have no exported fields, with their
+// text and bytes
+type gobEthConfig struct {
+	StaticPeers    []gobPeerInfo
+	BootstrapNodes []string
+	PrivateKey     []byte
+	Port           int
+
+	ProgramName             string
+	Network                 uint64
+	TotalDifficulty         *big.Int
+	TerminalTotalDifficulty *big.Int
+	GenesisTime             uint64
+	TTDOverrides            bool
+	Head                    common.Hash
+	Genesis                 common.Hash
+	ExecutionLayerForks     []string
+	BlockConfirmationsCount int
+	SendBlockConfirmation   bool
+
+	IgnoreBlockTimeout time.Duration
+	IgnoreSlotCount    int
+}
+
+// Encode serializes the message
+func (GobBridgeCodec) Encode(message BridgeMessage) ([]byte, error) {
+	m := gobBridgeMessage{Type: message.Type}
+	var err error
+	switch payload := message.Payload.(type) {
+	case network.EthConfig:
+		m.Config = newGobEthConfig(payload)
+	case TransactionAnnouncement:
+		m.Announcement = &payload
+	case Transactions:
+		m.Transactions = newGobTransactions(payload.Transactions)
+		m.PeerEndpoint = payload.PeerEndpoint
+		m.ConnectionType = payload.ConnectionType
+	case BlockFromNode:
+		m.Block = newGobBlock(payload.Block)
+		m.PeerEndpoint = payload.PeerEndpoint
+	case *types.BxBlock:
+		m.Block = newGobBlock(payload)
+	case BlockHeaderFromNode:
+		m.Header, err = rlp.EncodeToBytes(payload.Header)
+		m.PeerEndpoint = payload.PeerEndpoint
+	case []*types.NodeEndpoint:
+		m.Endpoints = payload
+	case types.NodeEndpoint:
+		m.PeerEndpoint = payload
+	case *ValidatorListInfo:
+		m.ValidatorList = payload
+	case []ProposerDuty:
+		m.ProposerDuties = payload
+	case ConnectionStatus:
+		m.ConnectionStatus = payload
+	case Heartbeat:
+		m.Heartbeat = payload
+	case BlockBackfillRequest:
+		m.BackfillRequest = payload
+	case nil:
+	default:
+		return nil, fmt.Errorf("unsupported payload %T of bridge message %v", message.Payload, message.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bridge message %v: %v", message.Type, err)
+	}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(&m); err != nil {
+		return nil, fmt.Errorf("failed to encode bridge message %v: %v", message.Type, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode deserializes a message
+func (GobBridgeCodec) Decode(data []byte) (BridgeMessage, error) {
+	var m gobBridgeMessage
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&m); err != nil {
+		return BridgeMessage{}, fmt.Errorf("failed to decode bridge message: %v", err)
+	}
+
+	message := BridgeMessage{Type: m.Type}
+	switch m.Type {
+	case BridgeNetworkConfig:
+		if m.Config == nil {
+			return BridgeMessage{}, fmt.Errorf("bridge message %v has no config", m.Type)
+		}
+		config, err := m.Config.ethConfig()
+		if err != nil {
+			return BridgeMessage{}, fmt.Errorf("failed to decode bridge message %v: %v", m.Type, err)
+		}
+		message.Payload = config
+	case BridgeTransactionAnnouncement, BridgeTransactionsRequest:
+		if m.Announcement == nil {
+			return BridgeMessage{}, fmt.Errorf("bridge message %v has no announcement", m.Type)
+		}
+		message.Payload = *m.Announcement
+	case BridgeTransactionsFromBDN, BridgeTransactionsToBDN:
+		message.Payload = Transactions{
+			Transactions:   bxTransactions(m.Transactions),
+			PeerEndpoint:   m.PeerEndpoint,
+			ConnectionType: m.ConnectionType,
+		}
+	case BridgeBlockToBDN, BridgeConfirmedBlock, BridgeBlockToNode, BridgeBackfilledBlock:
+		if m.Block == nil {
+			return BridgeMessage{}, fmt.Errorf("bridge message %v has no block", m.Type)
+		}
+		if m.Type == BridgeBlockToNode {
+			message.Payload = m.Block.bxBlock()
+		} else {
+			message.Payload = BlockFromNode{Block: m.Block.bxBlock(), PeerEndpoint: m.PeerEndpoint}
+		}
+	case BridgeBlockHeader:
+		header := new(ethtypes.Header)
+		if err := rlp.DecodeBytes(m.Header, header); err != nil {
+			return BridgeMessage{}, fmt.Errorf("failed to decode header of bridge message %v: %v", m.Type, err)
+		}
+		message.Payload = BlockHeaderFromNode{Header: header, PeerEndpoint: m.PeerEndpoint}
+	case BridgeNoActivePeersAlert, BridgeStatusRequest, BridgeNodeConnectionCheckRequest:
+	case BridgeStatusResponse:
+		message.Payload = m.Endpoints
+	case BridgeNodeConnectionCheckResponse, BridgeDisconnectEvent:
+		message.Payload = m.PeerEndpoint
+	case BridgeValidatorListInfo:
+		message.Payload = m.ValidatorList
+	case BridgeProposerDuties:
+		message.Payload = m.ProposerDuties
+	case BridgeConnectionStatus:
+		message.Payload = m.ConnectionStatus
+	case BridgeHeartbeat, BridgeHeartbeatAck:
+		message.Payload = m.Heartbeat
+	case BridgeBlockBackfillRequest:
+		message.Payload = m.BackfillRequest
+	default:
+		return BridgeMessage{}, fmt.Errorf("unknown bridge message type %v", m.Type)
+	}
+	return message, nil
+}
+
+func newGobTransactions(txs []*types.BxTransaction) []gobTransaction {
+	gobTxs := make([]gobTransaction, 0, len(txs))
+	for _, tx := range txs {
+		gobTxs = append(gobTxs, gobTransaction{
+			Hash:       tx.Hash(),
+			Content:    tx.Content(),
+			ShortIDs:   tx.ShortIDs(),
+			Flags:      tx.Flags(),
+			NetworkNum: tx.NetworkNum(),
+			Sender:     tx.Sender(),
+			AddTime:    tx.AddTime(),
+		})
+	}
+	return gobTxs
+}
+
+func bxTransactions(gobTxs []gobTransaction) []*types.BxTransaction {
+	txs := make([]*types.BxTransaction, 0, len(gobTxs))
+	for _, gobTx := range gobTxs {
+		tx := types.NewBxTransaction(gobTx.Hash, gobTx.NetworkNum, gobTx.Flags, gobTx.AddTime)
+		tx.SetContent(gobTx.Content)
+		tx.SetSender(gobTx.Sender)
+		for _, shortID := range gobTx.ShortIDs {
+			tx.AddShortID(shortID)
+		}
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func newGobBlock(block *types.BxBlock) *gobBlock {
+	if block == nil {
+		return nil
+	}
+	txs := make([]gobBlockTransaction, 0, len(block.Txs))
+	for _, tx := range block.Txs {
+		txs = append(txs, gobBlockTransaction{Hash: tx.Hash(), Content: tx.Content()})
+	}
+	return &gobBlock{
+		Hash:            block.Hash(),
+		BeaconHash:      block.BeaconHash(),
+		Type:            block.Type,
+		Header:          block.Header,
+		Txs:             txs,
+		Trailer:         block.Trailer,
+		TotalDifficulty: block.TotalDifficulty,
+		Number:          block.Number,
+		Size:            block.Size(),
+	}
+}
+
+// bxBlock returns the block, its timestamp is the time it was decoded at
+func (b *gobBlock) bxBlock() *types.BxBlock {
+	txs := make([]*types.BxBlockTransaction, 0, len(b.Txs))
+	for _, tx := range b.Txs {
+		txs = append(txs, types.NewBxBlockTransaction(tx.Hash, tx.Content))
+	}
+	return types.NewRawBxBlock(b.Hash, b.BeaconHash, b.Type, b.Header, txs, b.Trailer, b.TotalDifficulty, b.Number, b.Size)
+}
+
+func newGobEthConfig(config network.EthConfig) *gobEthConfig {
+	c := &gobEthConfig{
+		Port:                    config.Port,
+		ProgramName:             config.ProgramName,
+		Network:                 config.Network,
+		TotalDifficulty:         config.TotalDifficulty,
+		TerminalTotalDifficulty: config.TerminalTotalDifficulty,
+		GenesisTime:             config.GenesisTime,
+		TTDOverrides:            config.TTDOverrides,
+		Head:                    config.Head,
+		Genesis:                 config.Genesis,
+		ExecutionLayerForks:     config.ExecutionLayerForks,
+		BlockConfirmationsCount: config.BlockConfirmationsCount,
+		SendBlockConfirmation:   config.SendBlockConfirmation,
+		IgnoreBlockTimeout:      config.IgnoreBlockTimeout,
+		IgnoreSlotCount:         config.IgnoreSlotCount,
+	}
+	if config.PrivateKey != nil {
+		c.PrivateKey = crypto.FromECDSA(config.PrivateKey)
+	}
+	for _, node := range config.BootstrapNodes {
+		c.BootstrapNodes = append(c.BootstrapNodes, node.String())
+	}
+	for _, peer := range config.StaticPeers {
+		p := gobPeerInfo{EthWSURI: peer.EthWSURI, PrysmAddr: peer.PrysmAddr, BeaconAPIURI: peer.BeaconAPIURI}
+		if peer.Enode != nil {
+			p.Enode = peer.Enode.String()
+		}
+		if peer.Multiaddr != nil && *peer.Multiaddr != nil {
+			p.Multiaddr = (*peer.Multiaddr).String()
+		}
+		c.StaticPeers = append(c.StaticPeers, p)
+	}
+	return c
+}
+
+func (c *gobEthConfig) ethConfig() (network.EthConfig, error) {
+	config := network.EthConfig{
+		Port:                    c.Port,
+		ProgramName:             c.ProgramName,
+		Network:                 c.Network,
+		TotalDifficulty:         c.TotalDifficulty,
+		TerminalTotalDifficulty: c.TerminalTotalDifficulty,
+		GenesisTime:             c.GenesisTime,
+		TTDOverrides:            c.TTDOverrides,
+		Head:                    c.Head,
+		Genesis:                 c.Genesis,
+		ExecutionLayerForks:     c.ExecutionLayerForks,
+		BlockConfirmationsCount: c.BlockConfirmationsCount,
+		SendBlockConfirmation:   c.SendBlockConfirmation,
+		IgnoreBlockTimeout:      c.IgnoreBlockTimeout,
+		IgnoreSlotCount:         c.IgnoreSlotCount,
+	}
+	if c.PrivateKey != nil {
+		privateKey, err := crypto.ToECDSA(c.PrivateKey)
+		if err != nil {
+			return network.EthConfig{}, fmt.Errorf("invalid private key: %v", err)
+		}
+		config.PrivateKey = privateKey
+	}
+	for _, bootstrapNode := range c.BootstrapNodes {
+		node, err := enode.Parse(enode.ValidSchemes, bootstrapNode)
+		if err != nil {
+			return network.EthConfig{}, fmt.Errorf("invalid bootstrap node %v: %v", bootstrapNode, err)
+		}
+		config.BootstrapNodes = append(config.BootstrapNodes, node)
+	}
+	for _, p := range c.StaticPeers {
+		peer := network.PeerInfo{EthWSURI: p.EthWSURI, PrysmAddr: p.PrysmAddr, BeaconAPIURI: p.BeaconAPIURI}
+		if p.Enode != "" {
+			node, err := enode.Parse(enode.ValidSchemes, p.Enode)
+			if err != nil {
+				return network.EthConfig{}, fmt.Errorf("invalid peer enode %v: %v", p.Enode, err)
+			}
+			peer.Enode = node
+		}
+		if p.Multiaddr != "" {
+			addr, err := multiaddr.NewMultiaddr(p.Multiaddr)
+			if err != nil {
+				return network.EthConfig{}, fmt.Errorf("invalid peer multiaddr %v: %v", p.Multiaddr, err)
+			}
+			peer.Multiaddr = &addr
+		}
+		config.StaticPeers = append(config.StaticPeers, peer)
+	}
+	return config, nil
+}
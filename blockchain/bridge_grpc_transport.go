@@ -0,0 +1,207 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// bridgeExchangeMethod is the bidirectional stream the encoded messages of a RemoteBridge are exchanged over, each
+// message is wrapped in a BytesValue so the service needs no generated code
+const bridgeExchangeMethod = "/blockchain.BridgeTransport/Exchange"
+
+// errNoBridgeBackend is returned when a message is sent to the backend while none is connected
+var errNoBridgeBackend = errors.New("no blockchain backend is connected to the bridge")
+
+type bridgeTransportServer interface {
+	exchange(stream grpc.ServerStream) error
+}
+
+var bridgeTransportServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blockchain.BridgeTransport",
+	HandlerType: (*bridgeTransportServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Exchange",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(bridgeTransportServer).exchange(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// GRPCBridgeServerTransport is the BridgeTransport of the gateway end of a RemoteBridge, serving a blockchain backend
+// which connects with a GRPCBridgeClientTransport from another process or host. One backend is connected at a time,
+// a restarted backend connects again once the stream of the previous one ended. The channel of the received messages
+// stays open across the backends.
+type GRPCBridgeServerTransport struct {
+	server    *grpc.Server
+	listener  net.Listener
+	lock      sync.Mutex
+	stream    grpc.ServerStream
+	messages  chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewGRPCBridgeServerTransport listens for the blockchain backend on the address
+func NewGRPCBridgeServerTransport(address string) (*GRPCBridgeServerTransport, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the blockchain backend on %v: %v", address, err)
+	}
+
+	t := &GRPCBridgeServerTransport{
+		server:   grpc.NewServer(grpc.MaxRecvMsgSize(maxBridgeFrameSize), grpc.MaxSendMsgSize(maxBridgeFrameSize)),
+		listener: listener,
+		messages: make(chan []byte, blockBacklog),
+		closed:   make(chan struct{}),
+	}
+	t.server.RegisterService(&bridgeTransportServiceDesc, t)
+	go func() {
+		if err := t.server.Serve(listener); err != nil {
+			log.Errorf("bridge server for the blockchain backend stopped: %v", err)
+		}
+	}()
+	return t, nil
+}
+
+// Addr returns the address the transport listens on
+func (t *GRPCBridgeServerTransport) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// Send delivers the message to the connected backend
+func (t *GRPCBridgeServerTransport) Send(message []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.stream == nil {
+		return errNoBridgeBackend
+	}
+	return t.stream.SendMsg(&wrapperspb.BytesValue{Value: message})
+}
+
+// Receive provides the messages of the connected backends
+func (t *GRPCBridgeServerTransport) Receive() <-chan []byte {
+	return t.messages
+}
+
+// Close stops serving the backend
+func (t *GRPCBridgeServerTransport) Close() error {
+	t.closeOnce.Do(func() {
+		close(t.closed)
+		t.server.Stop()
+	})
+	return nil
+}
+
+func (t *GRPCBridgeServerTransport) exchange(stream grpc.ServerStream) error {
+	t.lock.Lock()
+	if t.stream != nil {
+		t.lock.Unlock()
+		return status.Error(codes.AlreadyExists, "another blockchain backend is connected to the bridge")
+	}
+	t.stream = stream
+	t.lock.Unlock()
+	log.Infof("blockchain backend connected to the bridge")
+
+	defer func() {
+		t.lock.Lock()
+		t.stream = nil
+		t.lock.Unlock()
+	}()
+
+	for {
+		message := &wrapperspb.BytesValue{}
+		if err := stream.RecvMsg(message); err != nil {
+			log.Warnf("blockchain backend disconnected from the bridge: %v", err)
+			return nil
+		}
+		select {
+		case t.messages <- message.Value:
+		case <-t.closed:
+			return nil
+		}
+	}
+}
+
+// GRPCBridgeClientTransport is the BridgeTransport of the blockchain backend end of a RemoteBridge, connected to the
+// GRPCBridgeServerTransport of the gateway
+type GRPCBridgeClientTransport struct {
+	conn     *grpc.ClientConn
+	stream   grpc.ClientStream
+	cancel   context.CancelFunc
+	lock     sync.Mutex
+	messages chan []byte
+}
+
+// DialGRPCBridgeTransport connects to the gateway listening for the blockchain backend on the address
+func DialGRPCBridgeTransport(ctx context.Context, address string) (*GRPCBridgeClientTransport, error) {
+	conn, err := grpc.DialContext(ctx, address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxBridgeFrameSize), grpc.MaxCallSendMsgSize(maxBridgeFrameSize)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial the bridge of the gateway at %v: %v", address, err)
+	}
+
+	// the stream lives until the transport is closed rather than until the dial context is done
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := conn.NewStream(streamCtx, &bridgeTransportServiceDesc.Streams[0], bridgeExchangeMethod)
+	if err != nil {
+		cancel()
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to open the bridge of the gateway at %v: %v", address, err)
+	}
+
+	t := &GRPCBridgeClientTransport{
+		conn:     conn,
+		stream:   stream,
+		cancel:   cancel,
+		messages: make(chan []byte, blockBacklog),
+	}
+	go t.read()
+	return t, nil
+}
+
+// Send delivers the message to the gateway
+func (t *GRPCBridgeClientTransport) Send(message []byte) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.stream.SendMsg(&wrapperspb.BytesValue{Value: message})
+}
+
+// Receive provides the messages of the gateway, the channel is closed when the stream ends
+func (t *GRPCBridgeClientTransport) Receive() <-chan []byte {
+	return t.messages
+}
+
+// Close ends the stream and the connection to the gateway
+func (t *GRPCBridgeClientTransport) Close() error {
+	t.cancel()
+	return t.conn.Close()
+}
+
+func (t *GRPCBridgeClientTransport) read() {
+	defer close(t.messages)
+
+	for {
+		message := &wrapperspb.BytesValue{}
+		if err := t.stream.RecvMsg(message); err != nil {
+			return
+		}
+		t.messages <- message.Value
+	}
+}
@@ -0,0 +1,65 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGRPCBridgeTransport(t *testing.T) {
+	serverTransport, err := NewGRPCBridgeServerTransport("127.0.0.1:0")
+	require.NoError(t, err)
+	gateway := NewRemoteBridge(nil, false, serverTransport, GobBridgeCodec{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = gateway.Run(ctx) }()
+
+	// nothing is sent before the backend connects
+	assert.ErrorIs(t, gateway.SendBlockchainStatusRequest(), errNoBridgeBackend)
+
+	clientTransport, err := DialGRPCBridgeTransport(ctx, serverTransport.Addr().String())
+	require.NoError(t, err)
+	backend := NewRemoteBridge(nil, false, clientTransport, GobBridgeCodec{})
+	backendErr := make(chan error, 1)
+	go func() { backendErr <- backend.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return gateway.SendBlockchainStatusRequest() == nil
+	}, time.Second, 10*time.Millisecond)
+	select {
+	case <-backend.ReceiveBlockchainStatusRequest():
+	case <-time.After(time.Second):
+		t.Fatal("status request was not received")
+	}
+
+	endpoint := types.NodeEndpoint{IP: "127.0.0.1", Port: 30303, PublicKey: "node"}
+	require.NoError(t, backend.SendBlockchainStatusResponse([]*types.NodeEndpoint{&endpoint}))
+	select {
+	case endpoints := <-gateway.ReceiveBlockchainStatusResponse():
+		require.Len(t, endpoints, 1)
+		assert.Equal(t, endpoint, *endpoints[0])
+	case <-time.After(time.Second):
+		t.Fatal("status response was not received")
+	}
+
+	// a second backend is rejected while the first one is connected
+	otherTransport, err := DialGRPCBridgeTransport(ctx, serverTransport.Addr().String())
+	require.NoError(t, err)
+	other := NewRemoteBridge(nil, false, otherTransport, GobBridgeCodec{})
+	assert.Error(t, other.Run(ctx))
+	_ = otherTransport.Close()
+
+	// the backend stops when the gateway closes the bridge
+	require.NoError(t, serverTransport.Close())
+	select {
+	case err = <-backendErr:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("backend did not stop")
+	}
+}
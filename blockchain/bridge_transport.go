@@ -0,0 +1,84 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+)
+
+// maxBridgeFrameSize bounds the encoded messages read by a stream transport, the largest are blocks with their
+// transactions
+const maxBridgeFrameSize = 128 * 1024 * 1024
+
+// BridgeTransport carries the encoded messages of a RemoteBridge to the process at its other end
+type BridgeTransport interface {
+	// Send delivers the message to the other end
+	Send([]byte) error
+	// Receive provides a channel of the messages from the other end, it is closed when the transport fails or closes
+	Receive() <-chan []byte
+	Close() error
+}
+
+// StreamBridgeTransport is a BridgeTransport framing the messages with their length over a stream, such as a unix
+// socket or the pipes of a child process
+type StreamBridgeTransport struct {
+	conn     io.ReadWriteCloser
+	lock     sync.Mutex
+	messages chan []byte
+}
+
+// NewStreamBridgeTransport creates the transport and starts reading the messages of the stream
+func NewStreamBridgeTransport(conn io.ReadWriteCloser) *StreamBridgeTransport {
+	t := &StreamBridgeTransport{
+		conn:     conn,
+		messages: make(chan []byte, blockBacklog),
+	}
+	go t.read()
+	return t
+}
+
+// Send writes the length of the message followed by the message
+func (t *StreamBridgeTransport) Send(message []byte) error {
+	frame := make([]byte, 4+len(message))
+	binary.BigEndian.PutUint32(frame, uint32(len(message)))
+	copy(frame[4:], message)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	_, err := t.conn.Write(frame)
+	return err
+}
+
+// Receive provides the messages read from the stream
+func (t *StreamBridgeTransport) Receive() <-chan []byte {
+	return t.messages
+}
+
+// Close closes the stream, which stops the reading of the messages
+func (t *StreamBridgeTransport) Close() error {
+	return t.conn.Close()
+}
+
+func (t *StreamBridgeTransport) read() {
+	defer close(t.messages)
+
+	length := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(t.conn, length); err != nil {
+			return
+		}
+		size := binary.BigEndian.Uint32(length)
+		if size > maxBridgeFrameSize {
+			log.Errorf("closing the bridge transport, the size %v of the message exceeds %v", size, maxBridgeFrameSize)
+			_ = t.conn.Close()
+			return
+		}
+		message := make([]byte, size)
+		if _, err := io.ReadFull(t.conn, message); err != nil {
+			return
+		}
+		t.messages <- message
+	}
+}
@@ -0,0 +1,288 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const canaryGasLimit = 21000
+
+// CanaryConfig defines the synthetic transactions periodically sent to check the end-to-end health of the gateway
+type CanaryConfig struct {
+	// PrivateKey is the hex encoded key signing the canary transactions, an empty key disables the canary
+	PrivateKey string
+	Interval   time.Duration
+	// Timeout is how long a canary transaction can take to be included in a block before the canary is unhealthy
+	Timeout time.Duration
+}
+
+// CanaryState is the health of the gateway according to the canary transactions
+type CanaryState string
+
+// CanaryState types enumeration
+const (
+	// CanaryUnknown is the state until the first canary transaction is included or fails
+	CanaryUnknown   CanaryState = "unknown"
+	CanaryHealthy   CanaryState = "healthy"
+	CanaryUnhealthy CanaryState = "unhealthy"
+)
+
+// CanaryStatus is the outcome of the canary transactions
+type CanaryStatus struct {
+	State     CanaryState `json:"state"`
+	Healthy   bool        `json:"healthy"`
+	Address   string      `json:"address"`
+	Pending   int         `json:"pending"`
+	Completed uint64      `json:"completed"`
+	Failed    uint64      `json:"failed"`
+
+	LastTxHash string `json:"last_tx_hash,omitempty"`
+	// time from the submission of the last completed canary until the node announced it and until it was included
+	LastNodeLatencyMs      int64     `json:"last_node_latency_ms,omitempty"`
+	LastInclusionLatencyMs int64     `json:"last_inclusion_latency_ms,omitempty"`
+	LastCompleted          time.Time `json:"last_completed,omitempty"`
+	LastError              string    `json:"last_error,omitempty"`
+}
+
+type canaryTx struct {
+	submitted time.Time
+	// zero until the node announced the transaction
+	seenByNode time.Time
+}
+
+// Canary periodically submits a self-addressed zero-value transaction through the gateway, and tracks it until it is
+// announced by the node and included in a block. It is meant for testnets, as each canary costs the gas of a transfer.
+type Canary struct {
+	cfg       CanaryConfig
+	wsManager WSManager
+	clock     utils.Clock
+	submit    func(tx *ethtypes.Transaction) error
+	key       *ecdsa.PrivateKey
+	address   common.Address
+	log       *log.Entry
+
+	lock    sync.Mutex
+	pending map[string]*canaryTx
+	status  CanaryStatus
+}
+
+// NewCanary creates the canary submitting its transactions with submit, it returns nil if no private key is configured
+func NewCanary(cfg CanaryConfig, wsManager WSManager, clock utils.Clock, submit func(tx *ethtypes.Transaction) error) (*Canary, error) {
+	if cfg.PrivateKey == "" {
+		return nil, nil
+	}
+	if wsManager == nil {
+		return nil, errors.New("canary requires a websocket connection to the blockchain node")
+	}
+
+	key, err := crypto.HexToECDSA(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canary private key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	return &Canary{
+		cfg:       cfg,
+		wsManager: wsManager,
+		clock:     clock,
+		submit:    submit,
+		key:       key,
+		address:   address,
+		log:       log.WithField("component", "canary"),
+		pending:   make(map[string]*canaryTx),
+		status:    CanaryStatus{State: CanaryUnknown, Address: address.Hex()},
+	}, nil
+}
+
+// Run sends a canary transaction every interval until the context is done
+func (c *Canary) Run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	c.log.Infof("sending canary transactions from %v every %v", c.address.Hex(), c.cfg.Interval)
+	ticker := c.clock.Ticker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			c.expire()
+			if err := c.send(); err != nil {
+				c.log.Warnf("failed to send canary transaction: %v", err)
+				c.lock.Lock()
+				c.status.LastError = err.Error()
+				c.lock.Unlock()
+			}
+		}
+	}
+}
+
+func (c *Canary) send() error {
+	provider, ok := c.wsManager.SyncedProvider()
+	if !ok {
+		return errors.New("no synced blockchain node")
+	}
+
+	chainID, err := callRPCUint64(provider, "eth_chainId")
+	if err != nil {
+		return err
+	}
+	nonce, err := callRPCUint64(provider, "eth_getTransactionCount", c.address.Hex(), "pending")
+	if err != nil {
+		return err
+	}
+	gasPrice, err := callRPCUint64(provider, "eth_gasPrice")
+	if err != nil {
+		return err
+	}
+
+	tx, err := ethtypes.SignTx(ethtypes.NewTx(&ethtypes.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: new(big.Int).SetUint64(gasPrice),
+		Gas:      canaryGasLimit,
+		To:       &c.address,
+		Value:    big.NewInt(0),
+	}), ethtypes.LatestSignerForChainID(new(big.Int).SetUint64(chainID)), c.key)
+	if err != nil {
+		return fmt.Errorf("failed to sign canary transaction: %v", err)
+	}
+
+	c.track(tx.Hash().String())
+	if err = c.submit(tx); err != nil {
+		c.lock.Lock()
+		delete(c.pending, tx.Hash().String())
+		c.lock.Unlock()
+		return fmt.Errorf("failed to submit canary transaction %v: %v", tx.Hash(), err)
+	}
+	c.log.Debugf("sent canary transaction %v with nonce %v", tx.Hash(), nonce)
+	return nil
+}
+
+func (c *Canary) track(hash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.pending[hash] = &canaryTx{submitted: c.clock.Now()}
+	c.status.LastTxHash = hash
+}
+
+// expire fails the canary transactions which were not included within the timeout
+func (c *Canary) expire() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	for hash, tx := range c.pending {
+		if now.Sub(tx.submitted) <= c.cfg.Timeout {
+			continue
+		}
+		delete(c.pending, hash)
+		c.status.Failed++
+		c.status.State = CanaryUnhealthy
+		c.status.Healthy = false
+		c.status.LastError = fmt.Sprintf("canary transaction %v was not included within %v", hash, c.cfg.Timeout)
+		c.log.Warnf("%v", c.status.LastError)
+	}
+}
+
+// ObserveTx records that the node announced the transaction
+func (c *Canary) ObserveTx(hash string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if tx, ok := c.pending[hash]; ok && tx.seenByNode.IsZero() {
+		tx.seenByNode = c.clock.Now()
+	}
+}
+
+// ObserveBlock completes the canary transactions included in the block
+func (c *Canary) ObserveBlock(block *types.BxBlock) {
+	if c == nil || block == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.pending) == 0 {
+		return
+	}
+
+	now := c.clock.Now()
+	for _, blockTx := range block.Txs {
+		hash := blockTx.Hash().Format(true)
+		tx, ok := c.pending[hash]
+		if !ok {
+			continue
+		}
+		delete(c.pending, hash)
+
+		c.status.Completed++
+		c.status.State = CanaryHealthy
+		c.status.Healthy = true
+		c.status.LastTxHash = hash
+		c.status.LastCompleted = now
+		c.status.LastInclusionLatencyMs = now.Sub(tx.submitted).Milliseconds()
+		c.status.LastNodeLatencyMs = 0
+		if !tx.seenByNode.IsZero() {
+			c.status.LastNodeLatencyMs = tx.seenByNode.Sub(tx.submitted).Milliseconds()
+		}
+		c.status.LastError = ""
+	}
+}
+
+// Ready returns false if the last canary transaction failed, the gateway is ready until the first canary resolves
+func (c *Canary) Ready() bool {
+	if c == nil {
+		return true
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.status.State != CanaryUnhealthy
+}
+
+// Status returns the outcome of the canary transactions, nil if the canary is disabled
+func (c *Canary) Status() *CanaryStatus {
+	if c == nil {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	status := c.status
+	status.Pending = len(c.pending)
+	return &status
+}
+
+func callRPCUint64(provider WSProvider, method string, params ...interface{}) (uint64, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	response, err := provider.CallRPC(method, params, RPCOptions{RetryAttempts: 1})
+	if err != nil {
+		return 0, fmt.Errorf("%v failed: %v", method, err)
+	}
+	result, ok := response.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected %v response %v", method, response)
+	}
+	return hexutil.DecodeUint64(result)
+}
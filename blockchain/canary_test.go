@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const canaryTestKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+// canaryWSManager is only passed to the canary, which does not send in the tests
+type canaryWSManager struct {
+	WSManager
+}
+
+func TestCanary(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+
+	canary, err := NewCanary(CanaryConfig{PrivateKey: canaryTestKey, Interval: time.Minute, Timeout: 5 * time.Minute},
+		canaryWSManager{}, &clock, func(*ethtypes.Transaction) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, CanaryUnknown, canary.Status().State)
+	assert.True(t, canary.Ready())
+	assert.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", canary.Status().Address)
+
+	var hash types.SHA256Hash
+	hash[0] = 1
+	canary.track(hash.Format(true))
+
+	clock.IncTime(100 * time.Millisecond)
+	canary.ObserveTx(hash.Format(true))
+	clock.IncTime(12 * time.Second)
+	assert.Equal(t, 1, canary.Status().Pending)
+
+	block, err := types.NewBxBlock(types.SHA256Hash{2}, types.SHA256Hash{}, types.BxBlockTypeEth, nil,
+		[]*types.BxBlockTransaction{types.NewBxBlockTransaction(hash, nil)}, nil, big.NewInt(1), big.NewInt(1), 0)
+	require.NoError(t, err)
+	canary.ObserveBlock(block)
+
+	status := canary.Status()
+	assert.Equal(t, CanaryHealthy, status.State)
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 0, status.Pending)
+	assert.Equal(t, uint64(1), status.Completed)
+	assert.Equal(t, int64(100), status.LastNodeLatencyMs)
+	assert.Equal(t, int64(12100), status.LastInclusionLatencyMs)
+
+	// a canary which is not included within the timeout fails
+	hash[0] = 2
+	canary.track(hash.Format(true))
+	clock.IncTime(6 * time.Minute)
+	canary.expire()
+
+	status = canary.Status()
+	assert.Equal(t, CanaryUnhealthy, status.State)
+	assert.False(t, status.Healthy)
+	assert.False(t, canary.Ready())
+	assert.Equal(t, uint64(1), status.Failed)
+	assert.NotEmpty(t, status.LastError)
+}
+
+func TestNewCanary(t *testing.T) {
+	canary, err := NewCanary(CanaryConfig{}, nil, &utils.MockClock{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, canary)
+	assert.Nil(t, canary.Status())
+	assert.True(t, canary.Ready())
+	canary.ObserveTx("0x01")
+	canary.ObserveBlock(nil)
+
+	_, err = NewCanary(CanaryConfig{PrivateKey: canaryTestKey}, nil, &utils.MockClock{}, nil)
+	assert.Error(t, err)
+
+	_, err = NewCanary(CanaryConfig{PrivateKey: "zz"}, canaryWSManager{}, &utils.MockClock{}, nil)
+	assert.Error(t, err)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
@@ -63,6 +65,7 @@ func NewHandler(parent context.Context, config *network.EthConfig, chain *Chain,
 	}
 	go h.checkInitialBlockchainLiveliness(100 * time.Second)
 	go h.handleBDNBridge(ctx)
+	go h.handleBlockBackfillRequests(ctx)
 	return h
 }
 
@@ -84,6 +87,9 @@ func (h *Handler) handleFeeds(wsCtx context.Context, nodeWS blockchain.WSProvide
 			return false
 		case newHeader := <-newHeadsRespCh:
 			nodeWS.Log().Tracef("received header for block %v (height %v)", newHeader.Hash(), newHeader.Number)
+			if err := h.bridge.SendBlockHeaderToGateway(newHeader, nodeWS.BlockchainPeerEndpoint()); err != nil {
+				nodeWS.Log().Debugf("failed to send header of block %v to the gateway: %v", newHeader.Hash(), err)
+			}
 			h.confirmBlockFromWS(newHeader.Hash(), newHeader.Number, nodeWS.BlockchainPeer().(*Peer))
 		case err := <-newPendingTxsErrCh:
 			nodeWS.Log().Errorf("failed to get notification from newPendingTransactions: %v  process %v", err, utils.GetGID())
@@ -218,46 +224,71 @@ func (h *Handler) RunPeer(ep *Peer, handler func(*Peer) error) error {
 }
 
 func (h *Handler) handleBDNBridge(ctx context.Context) {
+	// the bridge stops being handled on a panic, the supervisor restarts the backend when its heartbeats are not
+	// acknowledged
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("stopped handling the bridge after a panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
 	for {
+		// the paid transactions are delivered to the nodes ahead of the bulk traffic from the BDN
 		select {
-		case bdnTxs := <-h.bridge.ReceiveBDNTransactions():
-			readMore := true
-			endpointToTxs := make(map[types.NodeEndpoint]*blockchain.Transactions)
-			endpointToTxs[bdnTxs.PeerEndpoint] = &bdnTxs
-			for readMore {
-				select {
-				case moreBdnTxs := <-h.bridge.ReceiveBDNTransactions():
-					if tx, ok := endpointToTxs[moreBdnTxs.PeerEndpoint]; !ok {
-						endpointToTxs[moreBdnTxs.PeerEndpoint] = &moreBdnTxs
-					} else {
-						tx.Transactions = append(tx.Transactions, moreBdnTxs.Transactions...)
-					}
-				default:
-					readMore = false
-				}
-			}
+		case paidTxs := <-h.bridge.ReceivePaidBDNTransactions():
+			h.processBDNTransactionBatch(paidTxs, h.bridge.ReceivePaidBDNTransactions())
+			continue
+		default:
+		}
 
-			for _, sendingTxs := range endpointToTxs {
-				h.processBDNTransactions(*sendingTxs)
-			}
+		select {
+		case paidTxs := <-h.bridge.ReceivePaidBDNTransactions():
+			h.processBDNTransactionBatch(paidTxs, h.bridge.ReceivePaidBDNTransactions())
+		case bdnTxs := <-h.bridge.ReceiveBDNTransactions():
+			h.processBDNTransactionBatch(bdnTxs, h.bridge.ReceiveBDNTransactions())
 		case request := <-h.bridge.ReceiveTransactionHashesRequest():
 			h.processBDNTransactionRequests(request)
 		case bdnBlock := <-h.bridge.ReceiveEthBlockFromBDN():
 			h.processBDNBlock(bdnBlock)
 		case config := <-h.bridge.ReceiveNetworkConfigUpdates():
-			h.config.Update(config)
+			h.updateNetworkConfig(config)
 		case <-h.bridge.ReceiveBlockchainStatusRequest():
 			h.processBlockchainStatusRequest()
 		case <-h.bridge.ReceiveNodeConnectionCheckRequest():
 			h.processNodeConnectionCheckRequest()
 		case endpoint := <-h.bridge.ReceiveDisconnectEvent():
 			h.processDisconnectEvent(endpoint)
+		case heartbeat := <-h.bridge.ReceiveHeartbeat():
+			h.processHeartbeat(heartbeat)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// processBDNTransactionBatch processes the transactions along with the ones already waiting on the channel, by peer
+func (h *Handler) processBDNTransactionBatch(bdnTxs blockchain.Transactions, more <-chan blockchain.Transactions) {
+	readMore := true
+	endpointToTxs := make(map[types.NodeEndpoint]*blockchain.Transactions)
+	endpointToTxs[bdnTxs.PeerEndpoint] = &bdnTxs
+	for readMore {
+		select {
+		case moreBdnTxs := <-more:
+			if tx, ok := endpointToTxs[moreBdnTxs.PeerEndpoint]; !ok {
+				endpointToTxs[moreBdnTxs.PeerEndpoint] = &moreBdnTxs
+			} else {
+				tx.Transactions = append(tx.Transactions, moreBdnTxs.Transactions...)
+			}
+		default:
+			readMore = false
+		}
+	}
+
+	for _, sendingTxs := range endpointToTxs {
+		h.processBDNTransactions(*sendingTxs)
+	}
+}
+
 func (h *Handler) processBDNTransactions(bdnTxs blockchain.Transactions) {
 	p := datatype.NewProcessingETHTransaction(len(bdnTxs.Transactions))
 	for _, bdnTx := range bdnTxs.Transactions {
@@ -349,6 +380,18 @@ func (h *Handler) processBlockchainStatusRequest() {
 	}
 }
 
+func (h *Handler) processHeartbeat(heartbeat blockchain.Heartbeat) {
+	peers := h.peers.getAll()
+	heartbeat.Peers = make([]types.NodeEndpoint, 0, len(peers))
+	for _, peer := range peers {
+		heartbeat.Peers = append(heartbeat.Peers, peer.IPEndpoint())
+	}
+
+	if err := h.bridge.SendHeartbeatAck(heartbeat); err != nil {
+		log.Errorf("send heartbeat ack: %v", err)
+	}
+}
+
 func (h *Handler) processNodeConnectionCheckRequest() {
 	var endpoint types.NodeEndpoint
 	for _, peer := range h.peers.getAll() {
@@ -369,6 +412,27 @@ func (h *Handler) processNodeConnectionCheckRequest() {
 	}
 }
 
+// updateNetworkConfig applies the network config pushed from the SDN, and disconnects the peers if the
+// fork config changed so they handshake again with the new fork ID without a gateway restart
+func (h *Handler) updateNetworkConfig(config network.EthConfig) {
+	changes, handshake := h.config.Changes(config)
+	h.config.Update(config)
+	if len(changes) == 0 {
+		return
+	}
+
+	log.Infof("applied network config update: %v", strings.Join(changes, ", "))
+	if !handshake {
+		return
+	}
+
+	peers := h.peers.getAll()
+	log.Infof("disconnecting %v blockchain peers to handshake with the updated fork config", len(peers))
+	for _, peer := range peers {
+		peer.Disconnect(p2p.DiscRequested)
+	}
+}
+
 func (h *Handler) processDisconnectEvent(endpoint types.NodeEndpoint) {
 	// check if the peer is in the connections
 	for _, peer := range h.peers.getAll() {
@@ -427,6 +491,7 @@ func (h *Handler) fetchBlockResponse66(peer *Peer, blockHash ethcommon.Hash, hea
 				log.Errorf("could not convert headers for block %v to the expected packet type, got %T", blockHash.String(), rawHeaders)
 				errCh <- ErrInvalidPacketType
 			} else {
+				h.sendBlockHeader(headers, peer.IPEndpoint())
 				errCh <- nil
 			}
 		case <-time.After(responseTimeout):
@@ -485,6 +550,7 @@ func (h *Handler) fetchBlockResponse(peer *Peer, blockHash ethcommon.Hash, heade
 	}
 
 	peer.Log().Debugf("received header for block %v", blockHash.String())
+	h.sendBlockHeader(headers, peer.IPEndpoint())
 
 	select {
 	case rawBodies := <-bodiesCh:
@@ -502,6 +568,17 @@ func (h *Handler) fetchBlockResponse(peer *Peer, blockHash ethcommon.Hash, heade
 	return headers, bodies, nil
 }
 
+// sendBlockHeader passes the header of a requested block to the gateway, so it is notified before the bodies arrive
+func (h *Handler) sendBlockHeader(headers *eth.BlockHeadersPacket, peerEndpoint types.NodeEndpoint) {
+	if len(*headers) != 1 {
+		return
+	}
+	header := (*headers)[0]
+	if err := h.bridge.SendBlockHeaderToGateway(header, peerEndpoint); err != nil {
+		log.Debugf("failed to send header of block %v to the gateway: %v", header.Hash(), err)
+	}
+}
+
 func (h *Handler) processBlockComponents(peer *Peer, headers *eth.BlockHeadersPacket, bodies *eth.BlockBodiesPacket) error {
 	if len(*headers) != 1 || len(*bodies) != 1 {
 		return fmt.Errorf("received %v headers and %v bodies, instead of 1 of each", len(*headers), len(*bodies))
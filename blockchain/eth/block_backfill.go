@@ -0,0 +1,147 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+)
+
+// backfillResponseTimeout bounds the wait for a peer to respond with a block the gateway missed, a block older than a
+// few slots is not worth waiting for longer
+const backfillResponseTimeout = 5 * time.Second
+
+// errNoBackfillPeer is returned when a missed block is not stored in the chain and there is no static peer to fetch it from
+var errNoBackfillPeer = errors.New("no static peer to fetch the block from")
+
+// handleBlockBackfillRequests serves the backfill requests one after another, so the blocks of each request reach the
+// gateway in order, without holding up the other messages of the bridge
+func (h *Handler) handleBlockBackfillRequests(ctx context.Context) {
+	for {
+		select {
+		case request := <-h.bridge.ReceiveBlockBackfillRequest():
+			h.processBlockBackfillRequest(request)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processBlockBackfillRequest sends the blocks of the requested heights to the gateway in order, from the chain if it
+// stored them or else from a static peer. The backfill stops at the first block which cannot be found, the gateway
+// would publish the later ones out of order otherwise.
+func (h *Handler) processBlockBackfillRequest(request blockchain.BlockBackfillRequest) {
+	log.Infof("backfilling blocks %v to %v missed by the gateway", request.From, request.To)
+	for height := request.From; height <= request.To; height++ {
+		block, peer, err := h.backfillBlock(height)
+		if err != nil {
+			log.Warnf("stopping the backfill of blocks %v to %v at block %v: %v", request.From, request.To, height, err)
+			return
+		}
+
+		blockInfo := NewBlockInfo(block, nil)
+		_ = h.chain.SetTotalDifficulty(blockInfo)
+		bxBlock, err := h.bridge.BlockBlockchainToBDN(blockInfo)
+		if err != nil {
+			log.Errorf("cannot convert backfilled block %v at height %v to BDN block: %v", block.Hash(), height, err)
+			return
+		}
+		if err = h.bridge.SendBackfilledBlock(blockchain.BlockFromNode{Block: bxBlock, PeerEndpoint: peer.IPEndpoint()}); err != nil {
+			log.Errorf("failed to send backfilled block %v at height %v to the gateway: %v", block.Hash(), height, err)
+			return
+		}
+	}
+}
+
+// backfillBlock returns the canonical block of the height with the static peer it is attributed to
+func (h *Handler) backfillBlock(height uint64) (*ethtypes.Block, *Peer, error) {
+	peer, ok := h.backfillPeer()
+	if !ok {
+		return nil, nil, errNoBackfillPeer
+	}
+
+	headers, err := h.chain.GetHeaders(eth.HashOrNumber{Number: height}, 1, 0, false)
+	if err == nil && len(headers) == 1 {
+		if bodies, err := h.chain.GetBodies([]ethcommon.Hash{headers[0].Hash()}); err == nil {
+			return ethtypes.NewBlockWithHeader(headers[0]).WithBody(bodies[0].Transactions, bodies[0].Uncles), peer, nil
+		}
+	}
+
+	// the backend did not store the block, e.g. it missed it while restarting
+	block, err := h.fetchBlockAtHeight(peer, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, peer, nil
+}
+
+// backfillPeer returns a static peer, the blockchain node of the gateway
+func (h *Handler) backfillPeer() (*Peer, bool) {
+	for _, peer := range h.peers.getAll() {
+		if !peer.Dynamic() {
+			return peer, true
+		}
+	}
+	return nil, false
+}
+
+// fetchBlockAtHeight requests the header of the height from the peer, then the block of the header
+func (h *Handler) fetchBlockAtHeight(peer *Peer, height uint64) (*ethtypes.Block, error) {
+	// the channels are buffered so a late response does not block the peer
+	headerCh := make(chan eth.Packet, 1)
+	if err := peer.RequestBlockHeaderRaw(eth.HashOrNumber{Number: height}, 1, 0, false, headerCh); err != nil {
+		return nil, err
+	}
+	headers, err := awaitBackfillPacket[*eth.BlockHeadersPacket](headerCh)
+	if err != nil {
+		return nil, fmt.Errorf("header at height %v: %v", height, err)
+	}
+	if len(*headers) != 1 {
+		return nil, fmt.Errorf("received %v headers at height %v instead of 1", len(*headers), height)
+	}
+	hash := (*headers)[0].Hash()
+
+	headersCh := make(chan eth.Packet, 1)
+	bodiesCh := make(chan eth.Packet, 1)
+	if peer.isVersion66() {
+		err = peer.RequestBlock66(hash, headersCh, bodiesCh)
+	} else {
+		err = peer.RequestBlock(hash, headersCh, bodiesCh)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if headers, err = awaitBackfillPacket[*eth.BlockHeadersPacket](headersCh); err != nil {
+		return nil, fmt.Errorf("header of block %v: %v", hash, err)
+	}
+	bodies, err := awaitBackfillPacket[*eth.BlockBodiesPacket](bodiesCh)
+	if err != nil {
+		return nil, fmt.Errorf("body of block %v: %v", hash, err)
+	}
+	if len(*headers) != 1 || len(*bodies) != 1 {
+		return nil, fmt.Errorf("received %v headers and %v bodies for block %v, instead of 1 of each", len(*headers), len(*bodies), hash)
+	}
+
+	body := (*bodies)[0]
+	return ethtypes.NewBlockWithHeader((*headers)[0]).WithBody(body.Transactions, body.Uncles), nil
+}
+
+func awaitBackfillPacket[T eth.Packet](responseCh chan eth.Packet) (T, error) {
+	var packet T
+	select {
+	case rawPacket := <-responseCh:
+		var ok bool
+		if packet, ok = rawPacket.(T); !ok {
+			return packet, ErrInvalidPacketType
+		}
+		return packet, nil
+	case <-time.After(backfillResponseTimeout):
+		return packet, ErrResponseTimeout
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/prysmaticlabs/prysm/v4/runtime/version"
 )
 
+// bxBlockRLP is the RLP of an Ethereum block, whose trailer holds the RLP of the uncles followed by the RLP of the
+// withdrawals of a post-Shanghai block
 type bxBlockRLP struct {
 	Header  rlp.RawValue
 	Txs     []rlp.RawValue
@@ -73,6 +75,14 @@ func (c Converter) ethBlockBlockchainToBDN(blockInfo *BlockInfo) (*types.BxBlock
 	if err != nil {
 		return nil, fmt.Errorf("could not encode block trailer: %v: %v", block.Uncles(), err)
 	}
+	// the withdrawals are the optional last field of the block, following the uncles
+	if withdrawals := block.Withdrawals(); withdrawals != nil {
+		encodedWithdrawals, err := rlp.EncodeToBytes(withdrawals)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode block withdrawals: %v: %v", withdrawals, err)
+		}
+		encodedTrailer = append(encodedTrailer, encodedWithdrawals...)
+	}
 
 	var txs []*types.BxBlockTransaction
 	for _, tx := range block.Transactions() {
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/prysmaticlabs/prysm/v4/consensus-types/interfaces"
 	"github.com/stretchr/testify/assert"
 )
@@ -69,6 +70,32 @@ func TestConverter_Block(t *testing.T) {
 	}
 }
 
+func TestConverter_BlockWithWithdrawals(t *testing.T) {
+	c := Converter{}
+	withdrawals := ethtypes.Withdrawals{
+		{Index: 1, Validator: 2, Address: common.HexToAddress("0x1"), Amount: 3},
+		{Index: 2, Validator: 5, Address: common.HexToAddress("0x2"), Amount: 8},
+	}
+	header := bxmock.NewEthBlockHeader(10, common.Hash{})
+	withdrawalsHash := ethtypes.DeriveSha(withdrawals, trie.NewStackTrie(nil))
+	header.WithdrawalsHash = &withdrawalsHash
+	block := bxmock.NewEthBlockWithHeader(header).WithWithdrawals(withdrawals)
+
+	bxBlock, err := c.BlockBlockchainToBDN(NewBlockInfo(block, big.NewInt(100)))
+	assert.Nil(t, err)
+	blockchainBlock, err := c.BlockBDNtoBlockchain(bxBlock)
+	assert.Nil(t, err)
+
+	ethBlock := blockchainBlock.(*BlockInfo).Block
+	assert.Equal(t, block.Hash(), ethBlock.Hash())
+	assert.Equal(t, withdrawals, ethBlock.Withdrawals())
+
+	notification, err := types.NewEthBlockNotification(ethBlock.Hash(), ethBlock, nil, false)
+	assert.Nil(t, err)
+	assert.Equal(t, withdrawals, notification.Withdrawals)
+	assert.Equal(t, &withdrawalsHash, notification.WithdrawalsRoot)
+}
+
 func TestConverter_BellatrixBeaconBlock(t *testing.T) {
 	c := Converter{}
 	block := bxmock.NewEthBlock(10, common.Hash{})
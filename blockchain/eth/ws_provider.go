@@ -152,9 +152,11 @@ func (ws *WSProvider) CallRPC(method string, payload []interface{}, options bloc
 		err      error
 	)
 	for retries := 0; retries < options.RetryAttempts; retries++ {
-		err = ws.client.Call(&response, method, payload...)
+		err = ws.client.CallContext(options.Ctx(), &response, method, payload...)
 		if (err != nil && strings.Contains(err.Error(), "header not found")) || response == nil {
-			time.Sleep(options.RetryInterval)
+			if sleepErr := options.Sleep(); sleepErr != nil {
+				return response, sleepErr
+			}
 			continue
 		}
 		break
@@ -172,6 +174,45 @@ func (ws *WSProvider) FetchTransactionReceipt(payload []interface{}, options blo
 	return ws.CallRPC("eth_getTransactionReceipt", payload, options)
 }
 
+// FetchTransactionReceipts fetches the receipts of the transaction hashes in a single batch call, retrying the
+// receipts the node failed to return
+func (ws *WSProvider) FetchTransactionReceipts(hashes []interface{}, options blockchain.RPCOptions) ([]interface{}, error) {
+	receipts := make([]interface{}, len(hashes))
+	missing := make([]int, len(hashes))
+	for i := range hashes {
+		missing[i] = i
+	}
+
+	var err error
+	for retries := 0; retries < options.RetryAttempts && len(missing) > 0; retries++ {
+		if retries > 0 {
+			if sleepErr := options.Sleep(); sleepErr != nil {
+				return receipts, sleepErr
+			}
+		}
+		batch := make([]rpc.BatchElem, len(missing))
+		for i, index := range missing {
+			batch[i] = rpc.BatchElem{Method: "eth_getTransactionReceipt", Args: []interface{}{hashes[index]}, Result: &receipts[index]}
+		}
+		if err = ws.client.BatchCallContext(options.Ctx(), batch); err != nil {
+			if options.Ctx().Err() != nil {
+				return receipts, err
+			}
+			continue
+		}
+
+		var failed []int
+		for i, elem := range batch {
+			if elem.Error != nil || receipts[missing[i]] == nil {
+				err = elem.Error
+				failed = append(failed, missing[i])
+			}
+		}
+		missing = failed
+	}
+	return receipts, err
+}
+
 // FetchTransaction check status of a transaction via CallRPC
 func (ws *WSProvider) FetchTransaction(payload []interface{}, options blockchain.RPCOptions) (interface{}, error) {
 	return ws.CallRPC("eth_getTransactionByHash", payload, options)
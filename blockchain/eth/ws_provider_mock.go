@@ -114,6 +114,16 @@ func (m *MockWSProvider) FetchTransactionReceipt(payload []interface{}, options
 	return testTxReceiptMap, nil
 }
 
+// FetchTransactionReceipts returns a fake receipt of each hash with no error
+func (m *MockWSProvider) FetchTransactionReceipts(hashes []interface{}, options blockchain.RPCOptions) ([]interface{}, error) {
+	m.NumReceiptsFetched += len(hashes)
+	receipts := make([]interface{}, len(hashes))
+	for i := range receipts {
+		receipts[i] = testTxReceiptMap
+	}
+	return receipts, nil
+}
+
 // FetchTransaction returns a fake response with no error
 func (m *MockWSProvider) FetchTransaction(payload []interface{}, options blockchain.RPCOptions) (interface{}, error) {
 	return nil, nil
@@ -0,0 +1,210 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxFeeHistoryPercentiles is the maximum number of reward percentiles of a fee history request
+const maxFeeHistoryPercentiles = 100
+
+// londonChainConfig estimates the base fee of the next block with the EIP-1559 parameters of Ethereum
+var londonChainConfig = &params.ChainConfig{LondonBlock: big.NewInt(0)}
+
+// FeeHistory is the fee history of a range of blocks, in the format of the eth_feeHistory response
+type FeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+type txTip struct {
+	tip *big.Int
+	gas uint64
+}
+
+type blockFees struct {
+	hash         common.Hash
+	baseFee      *big.Int
+	nextBaseFee  *big.Int
+	gasUsedRatio float64
+	// effective priority fees of the transactions sorted in ascending order
+	tips []txTip
+}
+
+// FeeTracker keeps rolling fee statistics of the most recent blocks, so the fee history is served without a round
+// trip to the blockchain node. The receipts are not available, so the reward percentiles are weighted by the gas
+// limits of the transactions instead of the gas they used.
+type FeeTracker struct {
+	lock   sync.RWMutex
+	size   uint64
+	newest uint64
+	blocks map[uint64]*blockFees
+}
+
+// NewFeeTracker creates a tracker that keeps the fees of at most size blocks
+func NewFeeTracker(size int) *FeeTracker {
+	return &FeeTracker{
+		size:   uint64(size),
+		blocks: make(map[uint64]*blockFees),
+	}
+}
+
+// OnBlock records the fees of the block, replacing the fees of a previous block at the same height
+func (t *FeeTracker) OnBlock(block *ethtypes.Block) {
+	if t == nil || block == nil {
+		return
+	}
+
+	number := block.NumberU64()
+	t.lock.RLock()
+	existing, ok := t.blocks[number]
+	t.lock.RUnlock()
+	if ok && existing.hash == block.Hash() {
+		// blocks are received both from the BDN and the blockchain node
+		return
+	}
+
+	fees := newBlockFees(block)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.blocks[number] = fees
+	if number <= t.newest {
+		return
+	}
+	t.newest = number
+	for height := range t.blocks {
+		if height+t.size <= t.newest {
+			delete(t.blocks, height)
+		}
+	}
+}
+
+func newBlockFees(block *ethtypes.Block) *blockFees {
+	header := block.Header()
+	fees := &blockFees{
+		hash:        block.Hash(),
+		baseFee:     new(big.Int),
+		nextBaseFee: new(big.Int),
+		tips:        make([]txTip, 0, len(block.Transactions())),
+	}
+	if header.BaseFee != nil && header.GasLimit >= londonChainConfig.ElasticityMultiplier() {
+		fees.baseFee = header.BaseFee
+		// the actual base fee of the next block replaces the estimate once the block is received
+		fees.nextBaseFee = misc.CalcBaseFee(londonChainConfig, header)
+	}
+	if header.GasLimit > 0 {
+		fees.gasUsedRatio = float64(header.GasUsed) / float64(header.GasLimit)
+	}
+
+	for _, tx := range block.Transactions() {
+		tip, err := tx.EffectiveGasTip(header.BaseFee)
+		if err != nil {
+			// the fee cap is below the base fee, which only happens for invalid blocks
+			tip = new(big.Int)
+		}
+		fees.tips = append(fees.tips, txTip{tip: tip, gas: tx.Gas()})
+	}
+	sort.Slice(fees.tips, func(i, j int) bool { return fees.tips[i].tip.Cmp(fees.tips[j].tip) < 0 })
+	return fees
+}
+
+// History returns the fee history of the blockCount blocks up to newestBlock, following the semantics of
+// eth_feeHistory. A zero newestBlock selects the latest block. The range is truncated to the most recent
+// consecutive blocks that are tracked.
+func (t *FeeTracker) History(blockCount, newestBlock uint64, rewardPercentiles []float64) (*FeeHistory, error) {
+	if t == nil {
+		return nil, errors.New("fee history is not available")
+	}
+	if blockCount == 0 {
+		return nil, errors.New("block count must be positive")
+	}
+	if len(rewardPercentiles) > maxFeeHistoryPercentiles {
+		return nil, fmt.Errorf("at most %v reward percentiles can be requested", maxFeeHistoryPercentiles)
+	}
+	for i, p := range rewardPercentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("invalid reward percentile %v, expected between 0 and 100", p)
+		}
+		if i > 0 && p < rewardPercentiles[i-1] {
+			return nil, fmt.Errorf("invalid reward percentile %v, the percentiles must be increasing", p)
+		}
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if newestBlock == 0 {
+		newestBlock = t.newest
+	}
+	if _, ok := t.blocks[newestBlock]; !ok {
+		return nil, fmt.Errorf("fees of block %v are not available", newestBlock)
+	}
+	if blockCount > t.size {
+		blockCount = t.size
+	}
+
+	oldest := newestBlock
+	for oldest > 0 && newestBlock-oldest+1 < blockCount {
+		if _, ok := t.blocks[oldest-1]; !ok {
+			break
+		}
+		oldest--
+	}
+
+	history := &FeeHistory{OldestBlock: (*hexutil.Big)(new(big.Int).SetUint64(oldest))}
+	for number := oldest; number <= newestBlock; number++ {
+		fees := t.blocks[number]
+		history.BaseFee = append(history.BaseFee, (*hexutil.Big)(fees.baseFee))
+		history.GasUsedRatio = append(history.GasUsedRatio, fees.gasUsedRatio)
+		if len(rewardPercentiles) > 0 {
+			history.Reward = append(history.Reward, fees.rewards(rewardPercentiles))
+		}
+	}
+
+	nextBaseFee := t.blocks[newestBlock].nextBaseFee
+	if next, ok := t.blocks[newestBlock+1]; ok {
+		nextBaseFee = next.baseFee
+	}
+	history.BaseFee = append(history.BaseFee, (*hexutil.Big)(nextBaseFee))
+	return history, nil
+}
+
+// rewards returns the priority fees at the percentiles of the gas of the block, like eth_feeHistory
+func (b *blockFees) rewards(percentiles []float64) []*hexutil.Big {
+	rewards := make([]*hexutil.Big, len(percentiles))
+	if len(b.tips) == 0 {
+		for i := range rewards {
+			rewards[i] = (*hexutil.Big)(new(big.Int))
+		}
+		return rewards
+	}
+
+	var totalGas uint64
+	for _, tip := range b.tips {
+		totalGas += tip.gas
+	}
+
+	index := 0
+	sumGas := b.tips[0].gas
+	for i, p := range percentiles {
+		threshold := uint64(float64(totalGas) * p / 100)
+		for sumGas < threshold && index < len(b.tips)-1 {
+			index++
+			sumGas += b.tips[index].gas
+		}
+		rewards[i] = (*hexutil.Big)(b.tips[index].tip)
+	}
+	return rewards
+}
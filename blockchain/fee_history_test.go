@@ -0,0 +1,81 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feeTestBlock(number, baseFee, gasUsed uint64, tips ...int64) *ethtypes.Block {
+	txs := make([]*ethtypes.Transaction, 0, len(tips))
+	for i, tip := range tips {
+		txs = append(txs, ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+			Nonce:     uint64(i),
+			Gas:       21000,
+			GasTipCap: big.NewInt(tip),
+			GasFeeCap: big.NewInt(int64(baseFee) + tip),
+		}))
+	}
+	header := &ethtypes.Header{
+		Number:   new(big.Int).SetUint64(number),
+		GasLimit: 30000000,
+		GasUsed:  gasUsed,
+		BaseFee:  new(big.Int).SetUint64(baseFee),
+	}
+	return ethtypes.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+func bigs(values ...int64) []*hexutil.Big {
+	result := make([]*hexutil.Big, 0, len(values))
+	for _, v := range values {
+		result = append(result, (*hexutil.Big)(big.NewInt(v)))
+	}
+	return result
+}
+
+func TestFeeTracker(t *testing.T) {
+	tracker := NewFeeTracker(3)
+
+	_, err := tracker.History(1, 0, nil)
+	assert.Error(t, err)
+
+	tracker.OnBlock(feeTestBlock(10, 1000, 15000000, 3, 1, 2, 4))
+	tracker.OnBlock(feeTestBlock(11, 1100, 30000000))
+	tracker.OnBlock(feeTestBlock(12, 1200, 0, 5))
+
+	history, err := tracker.History(2, 0, []float64{0, 50, 100})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), history.OldestBlock.ToInt().Uint64())
+	// the base fee of the block after the newest is estimated from its gas usage
+	assert.Equal(t, bigs(1100, 1200, 1050), history.BaseFee)
+	assert.Equal(t, []float64{1, 0}, history.GasUsedRatio)
+	assert.Equal(t, [][]*hexutil.Big{bigs(0, 0, 0), bigs(5, 5, 5)}, history.Reward)
+
+	history, err = tracker.History(5, 10, []float64{25, 75})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), history.OldestBlock.ToInt().Uint64())
+	// the actual base fee of the next block is used once it was received
+	assert.Equal(t, bigs(1000, 1100), history.BaseFee)
+	assert.Equal(t, [][]*hexutil.Big{bigs(1, 3)}, history.Reward)
+
+	// the oldest block is evicted
+	tracker.OnBlock(feeTestBlock(13, 1200, 0))
+	_, err = tracker.History(1, 10, nil)
+	assert.Error(t, err)
+	history, err = tracker.History(10, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), history.OldestBlock.ToInt().Uint64())
+	assert.Len(t, history.GasUsedRatio, 3)
+	assert.Nil(t, history.Reward)
+
+	for _, percentiles := range [][]float64{{-1}, {101}, {50, 10}} {
+		_, err = tracker.History(1, 0, percentiles)
+		assert.Error(t, err)
+	}
+	_, err = tracker.History(0, 0, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,390 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// FeedSource types enumeration
+const (
+	FeedSourceNode      = "node"
+	FeedSourcePublicRPC = "public_rpc"
+)
+
+// FeedDivergence reasons enumeration
+const (
+	FeedDivergenceHeight = "height"
+	FeedDivergenceHash   = "hash"
+)
+
+const (
+	feedVerificationWebhookTimeout = 5 * time.Second
+	feedVerificationRPCTimeout     = 5 * time.Second
+	// feedVerificationHistory is the number of heights whose BDN block hashes are kept to be compared
+	feedVerificationHistory = 256
+)
+
+var errNoSyncedNode = errors.New("no synced blockchain node")
+
+// FeedVerificationConfig defines the cross-check of the blocks from the BDN against the node and a public RPC
+type FeedVerificationConfig struct {
+	Enabled bool
+	// PublicRPC is the URL of an HTTP JSON-RPC endpoint the blocks are also compared with, it is optional
+	PublicRPC string
+	// Threshold is the number of blocks the BDN can be ahead or behind a source, the hashes are compared at the
+	// height the sources had time to agree on, threshold blocks below the lowest of their heads
+	Threshold uint64
+	Interval  time.Duration
+	// Webhook is the URL the alerts are posted to, the alerts are only logged if it is empty
+	Webhook string
+}
+
+// FeedDivergenceAlert is sent when a source starts or stops diverging from the BDN
+type FeedDivergenceAlert struct {
+	Source     string                `json:"source"`
+	Diverged   bool                  `json:"diverged"`
+	Divergence *types.FeedDivergence `json:"divergence,omitempty"`
+	Time       time.Time             `json:"time"`
+}
+
+// FeedVerificationStatus is the outcome of the feed verification
+type FeedVerificationStatus struct {
+	BDNHeight   uint64                  `json:"bdn_height"`
+	Checks      uint64                  `json:"checks"`
+	Divergences []*types.FeedDivergence `json:"divergences,omitempty"`
+	LastError   string                  `json:"last_error,omitempty"`
+}
+
+// feedSource is a secondary source of the blocks, call returns the decoded result of a JSON-RPC call
+type feedSource struct {
+	name string
+	call func(method string, params ...interface{}) (interface{}, error)
+}
+
+func (s feedSource) blockNumber() (uint64, error) {
+	response, err := s.call("eth_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+	result, ok := response.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected eth_blockNumber response %v", response)
+	}
+	return hexutil.DecodeUint64(result)
+}
+
+// blockHash returns the hash of the block at the height, empty if the source does not have it
+func (s feedSource) blockHash(number uint64) (string, error) {
+	response, err := s.call("eth_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err != nil || response == nil {
+		return "", err
+	}
+	block, ok := response.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("unexpected eth_getBlockByNumber response %v", response)
+	}
+	hash, _ := block["hash"].(string)
+	return hash, nil
+}
+
+// FeedVerifier periodically compares the blocks from the BDN with the blocks of the node, and optionally of a
+// public RPC, guarding the feeds against upstream data issues. A source diverges while its head is further than the
+// threshold from the BDN, or while it disagrees with the BDN on the hash of a block.
+type FeedVerifier struct {
+	cfg     FeedVerificationConfig
+	sources []feedSource
+	clock   utils.Clock
+	client  *http.Client
+	log     *log.Entry
+
+	lock        sync.Mutex
+	bdnHeight   uint64
+	bdnHashes   map[uint64]string
+	divergences map[string]*types.FeedDivergence
+	checks      uint64
+	lastError   string
+}
+
+// NewFeedVerifier creates the feed verifier, it returns nil if the verification is disabled
+func NewFeedVerifier(cfg FeedVerificationConfig, wsManager WSManager, clock utils.Clock) (*FeedVerifier, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var sources []feedSource
+	if wsManager != nil {
+		sources = append(sources, feedSource{name: FeedSourceNode, call: func(method string, params ...interface{}) (interface{}, error) {
+			provider, ok := wsManager.SyncedProvider()
+			if !ok {
+				return nil, errNoSyncedNode
+			}
+			if params == nil {
+				params = []interface{}{}
+			}
+			response, err := provider.CallRPC(method, params, RPCOptions{RetryAttempts: 1})
+			if err != nil {
+				return nil, fmt.Errorf("%v failed: %v", method, err)
+			}
+			return response, nil
+		}})
+	}
+	if cfg.PublicRPC != "" {
+		client, err := ethrpc.DialHTTP(cfg.PublicRPC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feed verification RPC %v: %v", cfg.PublicRPC, err)
+		}
+		sources = append(sources, feedSource{name: FeedSourcePublicRPC, call: func(method string, params ...interface{}) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), feedVerificationRPCTimeout)
+			defer cancel()
+			var response interface{}
+			if err := client.CallContext(ctx, &response, method, params...); err != nil {
+				return nil, fmt.Errorf("%v failed: %v", method, err)
+			}
+			return response, nil
+		}})
+	}
+	if len(sources) == 0 {
+		return nil, errors.New("feed verification requires a websocket connection to the blockchain node or a public RPC")
+	}
+
+	return newFeedVerifier(cfg, sources, clock), nil
+}
+
+func newFeedVerifier(cfg FeedVerificationConfig, sources []feedSource, clock utils.Clock) *FeedVerifier {
+	return &FeedVerifier{
+		cfg:         cfg,
+		sources:     sources,
+		clock:       clock,
+		client:      &http.Client{Timeout: feedVerificationWebhookTimeout},
+		log:         log.WithField("component", "feedVerifier"),
+		bdnHashes:   make(map[uint64]string),
+		divergences: make(map[string]*types.FeedDivergence),
+	}
+}
+
+// ObserveBlock records the block received from the BDN
+func (v *FeedVerifier) ObserveBlock(block *types.BxBlock) {
+	if v == nil || block == nil || block.Number == nil || !block.Number.IsUint64() {
+		return
+	}
+
+	number := block.Number.Uint64()
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.bdnHashes[number] = block.Hash().Format(true)
+	if number > v.bdnHeight {
+		v.bdnHeight = number
+	}
+	if len(v.bdnHashes) > feedVerificationHistory {
+		for height := range v.bdnHashes {
+			if height+feedVerificationHistory <= v.bdnHeight {
+				delete(v.bdnHashes, height)
+			}
+		}
+	}
+}
+
+// Run compares the BDN with the sources every interval until the context is done
+func (v *FeedVerifier) Run(ctx context.Context) {
+	if v == nil {
+		return
+	}
+
+	names := make([]string, 0, len(v.sources))
+	for _, source := range v.sources {
+		names = append(names, source.name)
+	}
+	v.log.Infof("verifying the blocks from the BDN against %v every %v with a threshold of %v blocks", strings.Join(names, ", "), v.cfg.Interval, v.cfg.Threshold)
+
+	ticker := v.clock.Ticker(v.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			for _, alert := range v.check() {
+				v.send(alert)
+			}
+		}
+	}
+}
+
+// check compares the BDN with each source, and returns the alerts of the sources whose divergence started or ended
+func (v *FeedVerifier) check() []FeedDivergenceAlert {
+	v.lock.Lock()
+	bdnHeight := v.bdnHeight
+	v.checks++
+	v.lock.Unlock()
+	if bdnHeight == 0 {
+		// nothing to compare until the first block from the BDN
+		return nil
+	}
+
+	var alerts []FeedDivergenceAlert
+	for _, source := range v.sources {
+		divergence, err := v.compare(source, bdnHeight)
+		if err != nil {
+			// the state of the source is kept until it can be compared again, the node is expected to be unavailable
+			// while it is syncing
+			if !errors.Is(err, errNoSyncedNode) {
+				v.log.Warnf("failed to compare the blocks from the BDN with %v: %v", source.name, err)
+			}
+			v.lock.Lock()
+			v.lastError = fmt.Sprintf("%v: %v", source.name, err)
+			v.lock.Unlock()
+			continue
+		}
+		if alert := v.update(source.name, divergence); alert != nil {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts
+}
+
+// compare returns the divergence of the source from the BDN, nil if they agree
+func (v *FeedVerifier) compare(source feedSource, bdnHeight uint64) (*types.FeedDivergence, error) {
+	sourceHeight, err := source.blockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	gap := bdnHeight - sourceHeight
+	if sourceHeight > bdnHeight {
+		gap = sourceHeight - bdnHeight
+	}
+	if gap > v.cfg.Threshold {
+		return &types.FeedDivergence{Source: source.name, Reason: FeedDivergenceHeight, BDNHeight: bdnHeight, SourceHeight: sourceHeight}, nil
+	}
+
+	number := bdnHeight
+	if sourceHeight < number {
+		number = sourceHeight
+	}
+	if number <= v.cfg.Threshold {
+		return nil, nil
+	}
+	number -= v.cfg.Threshold
+
+	v.lock.Lock()
+	bdnHash, ok := v.bdnHashes[number]
+	v.lock.Unlock()
+	if !ok {
+		// the BDN did not deliver a block at the height, it has nothing to compare
+		return nil, nil
+	}
+	sourceHash, err := source.blockHash(number)
+	if err != nil {
+		return nil, err
+	}
+	if sourceHash == "" || strings.EqualFold(sourceHash, bdnHash) {
+		return nil, nil
+	}
+	return &types.FeedDivergence{
+		Source:       source.name,
+		Reason:       FeedDivergenceHash,
+		BDNHeight:    bdnHeight,
+		SourceHeight: sourceHeight,
+		BlockNumber:  number,
+		BDNHash:      bdnHash,
+		SourceHash:   sourceHash,
+	}, nil
+}
+
+// update records the divergence of the source, and returns an alert if the source started or stopped diverging
+func (v *FeedVerifier) update(source string, divergence *types.FeedDivergence) *FeedDivergenceAlert {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	_, diverged := v.divergences[source]
+	if divergence == nil {
+		delete(v.divergences, source)
+	} else {
+		v.divergences[source] = divergence
+	}
+	if diverged == (divergence != nil) {
+		return nil
+	}
+	return &FeedDivergenceAlert{Source: source, Diverged: divergence != nil, Divergence: divergence, Time: v.clock.Now()}
+}
+
+func (v *FeedVerifier) send(alert FeedDivergenceAlert) {
+	if !alert.Diverged {
+		v.log.Infof("blocks from the BDN agree with %v again", alert.Source)
+	} else {
+		d := alert.Divergence
+		if d.Reason == FeedDivergenceHash {
+			v.log.Warnf("blocks from the BDN diverge from %v: block %v is %v from the BDN and %v from %v", alert.Source, d.BlockNumber, d.BDNHash, d.SourceHash, alert.Source)
+		} else {
+			v.log.Warnf("blocks from the BDN diverge from %v: the BDN is at height %v and %v at %v, more than %v blocks apart", alert.Source, d.BDNHeight, alert.Source, d.SourceHeight, v.cfg.Threshold)
+		}
+	}
+
+	if v.cfg.Webhook == "" {
+		return
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		v.log.Errorf("failed to marshal %v feed divergence alert: %v", alert.Source, err)
+		return
+	}
+	resp, err := v.client.Post(v.cfg.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		v.log.Errorf("failed to post %v feed divergence alert to the webhook: %v", alert.Source, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		v.log.Errorf("failed to post %v feed divergence alert to the webhook: status %v", alert.Source, resp.Status)
+	}
+}
+
+// Divergences returns the current divergences of the sources, ordered by source, nil if there are none
+func (v *FeedVerifier) Divergences() []*types.FeedDivergence {
+	if v == nil {
+		return nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if len(v.divergences) == 0 {
+		return nil
+	}
+	divergences := make([]*types.FeedDivergence, 0, len(v.divergences))
+	for _, divergence := range v.divergences {
+		divergences = append(divergences, divergence)
+	}
+	sort.Slice(divergences, func(i, j int) bool { return divergences[i].Source < divergences[j].Source })
+	return divergences
+}
+
+// Status returns the outcome of the feed verification, nil if it is disabled
+func (v *FeedVerifier) Status() *FeedVerificationStatus {
+	if v == nil {
+		return nil
+	}
+
+	divergences := v.Divergences()
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return &FeedVerificationStatus{
+		BDNHeight:   v.bdnHeight,
+		Checks:      v.checks,
+		Divergences: divergences,
+		LastError:   v.lastError,
+	}
+}
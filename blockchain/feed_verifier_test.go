@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFeedSource serves the head and the block hashes of a fake source
+type testFeedSource struct {
+	head   uint64
+	hashes map[uint64]string
+}
+
+func (s *testFeedSource) source(name string) feedSource {
+	return feedSource{name: name, call: func(method string, params ...interface{}) (interface{}, error) {
+		if method == "eth_blockNumber" {
+			return hexutil.EncodeUint64(s.head), nil
+		}
+		number, err := hexutil.DecodeUint64(params[0].(string))
+		if err != nil {
+			return nil, err
+		}
+		hash, ok := s.hashes[number]
+		if !ok {
+			return nil, nil
+		}
+		return map[string]interface{}{"hash": hash}, nil
+	}}
+}
+
+func feedVerifierTestBlock(t *testing.T, number int64, hash types.SHA256Hash) *types.BxBlock {
+	block, err := types.NewBxBlock(hash, types.SHA256Hash{}, types.BxBlockTypeEth, nil, nil, nil, big.NewInt(1), big.NewInt(number), 0)
+	require.NoError(t, err)
+	return block
+}
+
+func TestFeedVerifier(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+
+	node := &testFeedSource{hashes: make(map[uint64]string)}
+	verifier := newFeedVerifier(FeedVerificationConfig{Enabled: true, Threshold: 2, Interval: time.Minute}, []feedSource{node.source(FeedSourceNode)}, &clock)
+	assert.Nil(t, verifier.check())
+
+	for number := int64(1); number <= 10; number++ {
+		hash := types.SHA256Hash{byte(number)}
+		verifier.ObserveBlock(feedVerifierTestBlock(t, number, hash))
+		node.hashes[uint64(number)] = hash.Format(true)
+	}
+
+	// the node is within the threshold and agrees on the hashes
+	node.head = 9
+	assert.Nil(t, verifier.check())
+	assert.Nil(t, verifier.Divergences())
+
+	// the node falls behind
+	node.head = 7
+	alerts := verifier.check()
+	require.Len(t, alerts, 1)
+	assert.True(t, alerts[0].Diverged)
+	assert.Equal(t, FeedDivergenceHeight, alerts[0].Divergence.Reason)
+	assert.Equal(t, uint64(10), alerts[0].Divergence.BDNHeight)
+	assert.Equal(t, uint64(7), alerts[0].Divergence.SourceHeight)
+	require.Len(t, verifier.Divergences(), 1)
+
+	// the divergence lasts without alerting again
+	assert.Nil(t, verifier.check())
+
+	// the node catches up, but disagrees on the hash of a block the BDN delivered
+	node.head = 10
+	node.hashes[8] = types.SHA256Hash{0xff}.Format(true)
+	alerts = verifier.check()
+	assert.Nil(t, alerts)
+	divergences := verifier.Divergences()
+	require.Len(t, divergences, 1)
+	assert.Equal(t, FeedDivergenceHash, divergences[0].Reason)
+	assert.Equal(t, uint64(8), divergences[0].BlockNumber)
+
+	// the node agrees again
+	node.hashes[8] = types.SHA256Hash{8}.Format(true)
+	alerts = verifier.check()
+	require.Len(t, alerts, 1)
+	assert.False(t, alerts[0].Diverged)
+	assert.Nil(t, verifier.Divergences())
+
+	status := verifier.Status()
+	assert.Equal(t, uint64(10), status.BDNHeight)
+	assert.Equal(t, uint64(6), status.Checks)
+}
+
+func TestNewFeedVerifier(t *testing.T) {
+	verifier, err := NewFeedVerifier(FeedVerificationConfig{}, nil, &utils.MockClock{})
+	require.NoError(t, err)
+	assert.Nil(t, verifier)
+	assert.Nil(t, verifier.Status())
+	assert.Nil(t, verifier.Divergences())
+	verifier.ObserveBlock(nil)
+
+	_, err = NewFeedVerifier(FeedVerificationConfig{Enabled: true}, nil, &utils.MockClock{})
+	assert.Error(t, err)
+}
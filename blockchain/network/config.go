@@ -431,6 +431,42 @@ func (ec *EthConfig) Update(otherConfig EthConfig) {
 	ec.BlockConfirmationsCount = otherConfig.BlockConfirmationsCount
 }
 
+// Changes describes the properties that Update would change with the other config, and whether the changes
+// affect the fork ID and chain exchanged in the status handshake with the blockchain peers
+func (ec *EthConfig) Changes(otherConfig EthConfig) (changes []string, handshake bool) {
+	if ec.Network != otherConfig.Network {
+		changes = append(changes, fmt.Sprintf("network %v -> %v", ec.Network, otherConfig.Network))
+		handshake = true
+	}
+	if ec.Genesis != otherConfig.Genesis {
+		changes = append(changes, fmt.Sprintf("genesis %v -> %v", ec.Genesis, otherConfig.Genesis))
+		handshake = true
+	}
+	if strings.Join(ec.ExecutionLayerForks, ",") != strings.Join(otherConfig.ExecutionLayerForks, ",") {
+		changes = append(changes, fmt.Sprintf("execution layer forks %v -> %v", ec.ExecutionLayerForks, otherConfig.ExecutionLayerForks))
+		handshake = true
+	}
+	if !ec.TTDOverrides && bigIntString(ec.TerminalTotalDifficulty) != bigIntString(otherConfig.TerminalTotalDifficulty) {
+		changes = append(changes, fmt.Sprintf("terminal total difficulty %v -> %v",
+			bigIntString(ec.TerminalTotalDifficulty), bigIntString(otherConfig.TerminalTotalDifficulty)))
+	}
+	if bigIntString(ec.TotalDifficulty) != bigIntString(otherConfig.TotalDifficulty) {
+		changes = append(changes, fmt.Sprintf("total difficulty %v -> %v",
+			bigIntString(ec.TotalDifficulty), bigIntString(otherConfig.TotalDifficulty)))
+	}
+	if ec.BlockConfirmationsCount != otherConfig.BlockConfirmationsCount {
+		changes = append(changes, fmt.Sprintf("block confirmations count %v -> %v", ec.BlockConfirmationsCount, otherConfig.BlockConfirmationsCount))
+	}
+	return changes, handshake
+}
+
+func bigIntString(i *big.Int) string {
+	if i == nil {
+		return "nil"
+	}
+	return i.String()
+}
+
 // StaticEnodes makes a list of enodes only from StaticPeers
 func (ec *EthConfig) StaticEnodes() []*enode.Node {
 	var enodesList []*enode.Node
@@ -2,6 +2,7 @@ package network
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/bloXroute-Labs/gateway/v2/utils"
@@ -116,3 +117,31 @@ func generateMultiEthWSURIInput(numPeers int) (string, []*enode.Node) {
 	}
 	return input, enodes
 }
+
+func TestEthConfigChanges(t *testing.T) {
+	config := EthConfig{
+		Network:                 1,
+		TotalDifficulty:         big.NewInt(1),
+		TerminalTotalDifficulty: big.NewInt(100),
+		ExecutionLayerForks:     []string{"shanghai"},
+	}
+
+	other := config
+	changes, handshake := config.Changes(other)
+	assert.Empty(t, changes)
+	assert.False(t, handshake)
+
+	other.TerminalTotalDifficulty = big.NewInt(200)
+	changes, handshake = config.Changes(other)
+	assert.Len(t, changes, 1)
+	assert.False(t, handshake)
+
+	config.TTDOverrides = true
+	changes, _ = config.Changes(other)
+	assert.Empty(t, changes)
+
+	other.ExecutionLayerForks = []string{"shanghai", "cancun"}
+	changes, handshake = config.Changes(other)
+	assert.Len(t, changes, 1)
+	assert.True(t, handshake)
+}
@@ -3,6 +3,7 @@ package blockchain
 import (
 	"github.com/bloXroute-Labs/gateway/v2/blockchain/network"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 // NoOpBxBridge is a placeholder bridge that still operates as a Converter
@@ -57,6 +58,11 @@ func (n NoOpBxBridge) ReceiveBDNTransactions() <-chan Transactions {
 	return make(chan Transactions)
 }
 
+// ReceivePaidBDNTransactions is a no-op
+func (n NoOpBxBridge) ReceivePaidBDNTransactions() <-chan Transactions {
+	return make(chan Transactions)
+}
+
 // ReceiveTransactionHashesAnnouncement is a no-op
 func (n NoOpBxBridge) ReceiveTransactionHashesAnnouncement() <-chan TransactionAnnouncement {
 	return make(chan TransactionAnnouncement)
@@ -97,6 +103,16 @@ func (n NoOpBxBridge) ReceiveConfirmedBlockFromNode() <-chan BlockFromNode {
 	return nil
 }
 
+// SendBlockHeaderToGateway is a no-op
+func (n NoOpBxBridge) SendBlockHeaderToGateway(header *ethtypes.Header, peerEndpoint types.NodeEndpoint) error {
+	return nil
+}
+
+// ReceiveBlockHeaderFromNode is a no-op
+func (n NoOpBxBridge) ReceiveBlockHeaderFromNode() <-chan BlockHeaderFromNode {
+	return make(chan BlockHeaderFromNode)
+}
+
 // ReceiveNoActiveBlockchainPeersAlert is a no-op
 func (n NoOpBxBridge) ReceiveNoActiveBlockchainPeersAlert() <-chan NoActiveBlockchainPeersAlert {
 	return make(chan NoActiveBlockchainPeersAlert)
@@ -152,6 +168,16 @@ func (n *NoOpBxBridge) ReceiveValidatorListInfo() <-chan *ValidatorListInfo {
 	return make(chan *ValidatorListInfo)
 }
 
+// SendProposerDuties is a no-op
+func (n *NoOpBxBridge) SendProposerDuties(duties []ProposerDuty) error {
+	return nil
+}
+
+// ReceiveProposerDuties is a no-op
+func (n *NoOpBxBridge) ReceiveProposerDuties() <-chan []ProposerDuty {
+	return make(chan []ProposerDuty)
+}
+
 // SendBlockchainConnectionStatus is a no-op
 func (n NoOpBxBridge) SendBlockchainConnectionStatus(ConnectionStatus) error { return nil }
 
@@ -167,3 +193,45 @@ func (n NoOpBxBridge) SendDisconnectEvent(endpoint types.NodeEndpoint) error { r
 func (n NoOpBxBridge) ReceiveDisconnectEvent() <-chan types.NodeEndpoint {
 	return make(chan types.NodeEndpoint)
 }
+
+// SendHeartbeat is a no-op
+func (n NoOpBxBridge) SendHeartbeat(Heartbeat) error { return nil }
+
+// ReceiveHeartbeat is a no-op
+func (n NoOpBxBridge) ReceiveHeartbeat() <-chan Heartbeat {
+	return make(chan Heartbeat)
+}
+
+// SendHeartbeatAck is a no-op
+func (n NoOpBxBridge) SendHeartbeatAck(Heartbeat) error { return nil }
+
+// ReceiveHeartbeatAck is a no-op
+func (n NoOpBxBridge) ReceiveHeartbeatAck() <-chan Heartbeat {
+	return make(chan Heartbeat)
+}
+
+// SendBlockBackfillRequest is a no-op
+func (n NoOpBxBridge) SendBlockBackfillRequest(BlockBackfillRequest) error { return nil }
+
+// ReceiveBlockBackfillRequest is a no-op
+func (n NoOpBxBridge) ReceiveBlockBackfillRequest() <-chan BlockBackfillRequest {
+	return make(chan BlockBackfillRequest)
+}
+
+// SendBackfilledBlock is a no-op
+func (n NoOpBxBridge) SendBackfilledBlock(BlockFromNode) error { return nil }
+
+// ReceiveBackfilledBlock is a no-op
+func (n NoOpBxBridge) ReceiveBackfilledBlock() <-chan BlockFromNode {
+	return make(chan BlockFromNode)
+}
+
+// ChannelSaturation is a no-op
+func (n NoOpBxBridge) ChannelSaturation() map[string]float64 {
+	return nil
+}
+
+// ChannelFullCounts is a no-op
+func (n NoOpBxBridge) ChannelFullCounts() map[string]uint64 {
+	return nil
+}
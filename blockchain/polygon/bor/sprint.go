@@ -267,6 +267,32 @@ func (m *SprintManager) FutureValidators(header *ethtypes.Header) [2]*types.Futu
 	return validatorInfo
 }
 
+// SprintProducers returns the producer set selected by Heimdall for the span of the block height.
+func (m *SprintManager) SprintProducers(height uint64) ([]string, error) {
+	spanInfo, err := m.spanner.GetSpanForHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return SpanProducers(spanInfo), nil
+}
+
+// SpanProducers returns the signer addresses of the producers selected for the span, in selection order.
+func SpanProducers(spanInfo *SpanInfo) []string {
+	producers := make([]string, 0, len(spanInfo.SelectedProducers))
+	seen := make(map[string]struct{}, len(spanInfo.SelectedProducers))
+	for _, producer := range spanInfo.SelectedProducers {
+		address := strings.ToLower(producer.Address.String())
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		producers = append(producers, address)
+	}
+
+	return producers
+}
+
 // StaticFutureValidatorInfo that can be recovered from block header.
 func StaticFutureValidatorInfo(height uint64, producer string) [2]*types.FutureValidatorInfo {
 	if IsSprintStart(height + 1) {
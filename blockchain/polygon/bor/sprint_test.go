@@ -1,10 +1,12 @@
 package bor
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +70,40 @@ func BenchmarkSnapshot_IncrementSprint(b *testing.B) {
 		)
 	}
 }
+
+type spanByHeight struct {
+	Spanner
+	spans []*SpanInfo
+}
+
+func (s spanByHeight) GetSpanForHeight(height uint64) (*SpanInfo, error) {
+	for _, span := range s.spans {
+		if span.StartBlock <= height && height <= span.EndBlock {
+			return span, nil
+		}
+	}
+	return nil, errBadSpanResp
+}
+
+func TestSprintManager_SprintProducers(t *testing.T) {
+	sprinterPayload := newSprinterPayloadDTO(
+		t,
+		"./testdata/span_6145.json",
+		"./testdata/span_6146.json",
+		"./testdata/snap_39321856.json",
+	)
+	spanner := spanByHeight{spans: []*SpanInfo{sprinterPayload.Current.Result, sprinterPayload.Next.Result}}
+	manager := NewSprintManager(context.Background(), nil, spanner)
+
+	producers, err := manager.SprintProducers(39321856)
+	require.NoError(t, err)
+	assert.Len(t, producers, 22)
+	assert.Equal(t, "0x02f70172f7f490653665c9bfac0666147c8af1f5", producers[0])
+
+	nextProducers, err := manager.SprintProducers(sprinterPayload.Next.Result.StartBlock)
+	require.NoError(t, err)
+	assert.Equal(t, SpanProducers(sprinterPayload.Next.Result), nextProducers)
+
+	_, err = manager.SprintProducers(1)
+	assert.Error(t, err)
+}
@@ -11,4 +11,6 @@ type ValidatorInfoManager interface {
 	Run() error
 	IsRunning() bool
 	FutureValidators(header *ethtypes.Header) [2]*types.FutureValidatorInfo
+	// SprintProducers returns the producer set selected by Heimdall for the span of the block height
+	SprintProducers(height uint64) ([]string, error)
 }
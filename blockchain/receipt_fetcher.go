@@ -0,0 +1,250 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"golang.org/x/sync/errgroup"
+)
+
+// latencyWeight is the weight of the latest call in the average latency of the node
+const latencyWeight = 0.2
+
+// ReceiptFetchConfig tunes the fetching of the receipts of the blocks from a node
+type ReceiptFetchConfig struct {
+	// Concurrency is the max number of receipt calls in flight to the node
+	Concurrency int
+	// BatchSize is the number of receipts requested by a JSON-RPC batch call, 1 requests them one by one
+	BatchSize int
+	// Retries is the number of attempts of a failed receipt call, the attempts after the first one are run by the
+	// ReceiptRetries shared by the nodes
+	Retries int
+	// LatencyTarget is the average latency of the node above which the concurrency is halved until the node recovers,
+	// 0 disables the throttling
+	LatencyTarget time.Duration
+}
+
+// DefaultReceiptFetchConfig is used for the nodes without their own config
+var DefaultReceiptFetchConfig = ReceiptFetchConfig{
+	Concurrency:   32,
+	BatchSize:     10,
+	Retries:       bxgateway.MaxEthTxReceiptCallRetries,
+	LatencyTarget: time.Second,
+}
+
+// ReceiptFetchConfigs are the receipt fetch configs of the nodes, by the address of their websocket endpoint
+type ReceiptFetchConfigs struct {
+	Default   ReceiptFetchConfig
+	Providers map[string]ReceiptFetchConfig
+	// Retry limits the retries of the failed receipt calls of all the nodes
+	Retry ReceiptRetryConfig
+}
+
+// ParseReceiptFetchConfigs parses [ws-uri=]concurrency/batch-size/retries/latency-target configs, the config
+// without ws-uri replaces the default of the nodes
+func ParseReceiptFetchConfigs(values []string) (ReceiptFetchConfigs, error) {
+	configs := ReceiptFetchConfigs{Default: DefaultReceiptFetchConfig, Providers: make(map[string]ReceiptFetchConfig), Retry: DefaultReceiptRetryConfig}
+	for _, value := range values {
+		var addr string
+		settings := value
+		// the URI can hold = in its query, the settings cannot
+		if i := strings.LastIndex(value, "="); i >= 0 {
+			addr, settings = value[:i], value[i+1:]
+			if addr == "" {
+				return configs, fmt.Errorf("invalid receipt fetch config %v, the ws-uri is empty", value)
+			}
+		}
+
+		fields := strings.Split(settings, "/")
+		if len(fields) != 4 {
+			return configs, fmt.Errorf("invalid receipt fetch config %v, expected [ws-uri=]concurrency/batch-size/retries/latency-target", value)
+		}
+		var cfg ReceiptFetchConfig
+		var err error
+		if cfg.Concurrency, err = strconv.Atoi(fields[0]); err != nil || cfg.Concurrency <= 0 {
+			return configs, fmt.Errorf("invalid concurrency in receipt fetch config %v", value)
+		}
+		if cfg.BatchSize, err = strconv.Atoi(fields[1]); err != nil || cfg.BatchSize <= 0 {
+			return configs, fmt.Errorf("invalid batch size in receipt fetch config %v", value)
+		}
+		if cfg.Retries, err = strconv.Atoi(fields[2]); err != nil || cfg.Retries <= 0 {
+			return configs, fmt.Errorf("invalid retries in receipt fetch config %v", value)
+		}
+		if cfg.LatencyTarget, err = time.ParseDuration(fields[3]); err != nil || cfg.LatencyTarget < 0 {
+			return configs, fmt.Errorf("invalid latency target in receipt fetch config %v", value)
+		}
+
+		if addr == "" {
+			configs.Default = cfg
+		} else {
+			configs.Providers[addr] = cfg
+		}
+	}
+	return configs, nil
+}
+
+// ReceiptFetcher fetches receipts from a node within the concurrency of its config. The concurrency adapts to the
+// latency of the node: it is halved while the node is slower than the latency target, and grows back by one call
+// at a time once it recovers, so a struggling node degrades gracefully instead of timing out every call.
+type ReceiptFetcher struct {
+	cfg     ReceiptFetchConfig
+	retries *ReceiptRetries
+
+	lock     sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	inFlight int
+	latency  time.Duration
+}
+
+// NewReceiptFetcher creates a ReceiptFetcher with the config, its unset limits are taken from the default config. Its
+// retries are limited by the default retry config.
+func NewReceiptFetcher(cfg ReceiptFetchConfig) *ReceiptFetcher {
+	return newReceiptFetcher(cfg, NewReceiptRetries(DefaultReceiptRetryConfig, utils.RealClock{}))
+}
+
+func newReceiptFetcher(cfg ReceiptFetchConfig, retries *ReceiptRetries) *ReceiptFetcher {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultReceiptFetchConfig.Concurrency
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultReceiptFetchConfig.BatchSize
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = DefaultReceiptFetchConfig.Retries
+	}
+	f := &ReceiptFetcher{cfg: cfg, retries: retries, limit: cfg.Concurrency}
+	f.cond = sync.NewCond(&f.lock)
+	return f
+}
+
+// Limit returns the current concurrency and average latency of the node
+func (f *ReceiptFetcher) Limit() (int, time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.limit, f.latency
+}
+
+func (f *ReceiptFetcher) acquire() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for f.inFlight >= f.limit {
+		f.cond.Wait()
+	}
+	f.inFlight++
+}
+
+// abandon ends a canceled call, whose latency does not reflect the node
+func (f *ReceiptFetcher) abandon() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.inFlight--
+	f.cond.Broadcast()
+}
+
+// release ends a call, adapting the concurrency to its latency
+func (f *ReceiptFetcher) release(latency time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.inFlight--
+
+	if f.latency == 0 {
+		f.latency = latency
+	} else {
+		f.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(f.latency))
+	}
+	if f.cfg.LatencyTarget > 0 {
+		switch {
+		case f.latency > f.cfg.LatencyTarget && latency > f.cfg.LatencyTarget:
+			if f.limit > 1 {
+				f.limit /= 2
+			}
+		case f.latency <= f.cfg.LatencyTarget && f.limit < f.cfg.Concurrency:
+			f.limit++
+		}
+	}
+	f.cond.Broadcast()
+}
+
+// Fetch fetches the receipts of the transaction hashes, in their order. A receipt is nil if the node does not have it.
+// The batches not yet fetched are skipped once ctx is canceled, and the error of ctx is returned. The missing receipts
+// of a batch are retried every retryInterval by the ReceiptRetries, without holding a call of the node.
+func (f *ReceiptFetcher) Fetch(ctx context.Context, provider WSProvider, hashes []interface{}, retryInterval time.Duration) ([]interface{}, error) {
+	options := RPCOptions{RetryAttempts: 1, Context: ctx}
+	receipts := make([]interface{}, len(hashes))
+
+	g := new(errgroup.Group)
+	for start := 0; start < len(hashes); start += f.cfg.BatchSize {
+		end := start + f.cfg.BatchSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		start := start
+		f.acquire()
+		if ctx.Err() != nil {
+			f.abandon()
+			break
+		}
+		g.Go(func() error {
+			indices := make([]int, end-start)
+			for i := range indices {
+				indices[i] = i
+			}
+			callStart := time.Now()
+			err := fetchReceipts(provider, hashes[start:end], receipts[start:end], indices, options)
+			if ctx.Err() != nil {
+				f.abandon()
+				return err
+			}
+			f.release(time.Since(callStart))
+
+			if f.cfg.Retries > 1 {
+				err = f.retries.retry(ctx, provider, hashes[start:end], receipts[start:end], f.cfg.Retries-1, retryInterval)
+			}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return receipts, err
+	}
+	return receipts, ctx.Err()
+}
+
+// ReceiptFetchers holds the ReceiptFetcher of each node, so the nodes are throttled independently, and the
+// ReceiptRetries they share
+type ReceiptFetchers struct {
+	configs ReceiptFetchConfigs
+	retries *ReceiptRetries
+
+	lock     sync.Mutex
+	fetchers map[string]*ReceiptFetcher
+}
+
+// NewReceiptFetchers creates the ReceiptFetchers of the nodes with the configs
+func NewReceiptFetchers(configs ReceiptFetchConfigs) *ReceiptFetchers {
+	return &ReceiptFetchers{configs: configs, retries: NewReceiptRetries(configs.Retry, utils.RealClock{}), fetchers: make(map[string]*ReceiptFetcher)}
+}
+
+// Get returns the ReceiptFetcher of the node
+func (r *ReceiptFetchers) Get(provider WSProvider) *ReceiptFetcher {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	addr := provider.Addr()
+	if fetcher, ok := r.fetchers[addr]; ok {
+		return fetcher
+	}
+	cfg, ok := r.configs.Providers[addr]
+	if !ok {
+		cfg = r.configs.Default
+	}
+	fetcher := newReceiptFetcher(cfg, r.retries)
+	r.fetchers[addr] = fetcher
+	return fetcher
+}
@@ -0,0 +1,123 @@
+package blockchain
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReceiptFetchConfigs(t *testing.T) {
+	configs, err := ParseReceiptFetchConfigs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultReceiptFetchConfig, configs.Default)
+
+	configs, err = ParseReceiptFetchConfigs([]string{"16/20/3/500ms", "ws://127.0.0.1:8546=4/1/5/0s"})
+	require.NoError(t, err)
+	assert.Equal(t, ReceiptFetchConfig{Concurrency: 16, BatchSize: 20, Retries: 3, LatencyTarget: 500 * time.Millisecond}, configs.Default)
+	assert.Equal(t, ReceiptFetchConfig{Concurrency: 4, BatchSize: 1, Retries: 5}, configs.Providers["ws://127.0.0.1:8546"])
+
+	for _, value := range []string{"16/20/3", "0/20/3/1s", "16/0/3/1s", "16/20/x/1s", "16/20/3/fast", "=16/20/3/1s"} {
+		_, err = ParseReceiptFetchConfigs([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestReceiptFetcher_Throttling(t *testing.T) {
+	fetcher := NewReceiptFetcher(ReceiptFetchConfig{Concurrency: 8, LatencyTarget: 100 * time.Millisecond})
+
+	// a slow node halves the concurrency on each slow call
+	for i := 0; i < 2; i++ {
+		fetcher.acquire()
+		fetcher.release(time.Second)
+	}
+	limit, _ := fetcher.Limit()
+	assert.Equal(t, 2, limit)
+
+	// and gets it back one call at a time once the average latency recovers
+	for i := 0; i < 20; i++ {
+		fetcher.acquire()
+		fetcher.release(time.Millisecond)
+	}
+	limit, latency := fetcher.Limit()
+	assert.Equal(t, 8, limit)
+	assert.Less(t, latency, 100*time.Millisecond)
+}
+
+// receiptProvider returns the hash of each transaction as its receipt
+type receiptProvider struct {
+	WSProvider
+
+	lock         sync.Mutex
+	singleCalls  int
+	batchCalls   int
+	inFlight     int
+	maxInFlight  int
+	callDuration time.Duration
+}
+
+func (p *receiptProvider) call() {
+	p.lock.Lock()
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	p.lock.Unlock()
+
+	time.Sleep(p.callDuration)
+
+	p.lock.Lock()
+	p.inFlight--
+	p.lock.Unlock()
+}
+
+func (p *receiptProvider) FetchTransactionReceipt(payload []interface{}, _ RPCOptions) (interface{}, error) {
+	p.call()
+	p.lock.Lock()
+	p.singleCalls++
+	p.lock.Unlock()
+	return payload[0], nil
+}
+
+func (p *receiptProvider) FetchTransactionReceipts(hashes []interface{}, _ RPCOptions) ([]interface{}, error) {
+	p.call()
+	p.lock.Lock()
+	p.batchCalls++
+	p.lock.Unlock()
+	return hashes, nil
+}
+
+func TestReceiptFetcher_Fetch(t *testing.T) {
+	provider := &receiptProvider{callDuration: time.Millisecond}
+	fetcher := NewReceiptFetcher(ReceiptFetchConfig{Concurrency: 2, BatchSize: 3})
+
+	hashes := []interface{}{"0x1", "0x2", "0x3", "0x4", "0x5", "0x6", "0x7"}
+	receipts, err := fetcher.Fetch(context.Background(), provider, hashes, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, hashes, receipts)
+	assert.Equal(t, 2, provider.batchCalls)
+	assert.Equal(t, 1, provider.singleCalls)
+	assert.LessOrEqual(t, provider.maxInFlight, 2)
+}
+
+func TestReceiptFetcher_FetchCanceled(t *testing.T) {
+	provider := &receiptProvider{callDuration: time.Millisecond}
+	fetcher := NewReceiptFetcher(ReceiptFetchConfig{Concurrency: 1, BatchSize: 2, LatencyTarget: time.Nanosecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	receipts, err := fetcher.Fetch(ctx, provider, []interface{}{"0x1", "0x2", "0x3"}, time.Millisecond)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []interface{}{nil, nil, nil}, receipts)
+	assert.Equal(t, 0, provider.batchCalls+provider.singleCalls)
+
+	// the canceled fetch neither holds a slot nor throttles the node
+	limit, latency := fetcher.Limit()
+	assert.Equal(t, 1, limit)
+	assert.Zero(t, latency)
+	_, err = fetcher.Fetch(context.Background(), provider, []interface{}{"0x1"}, time.Millisecond)
+	require.NoError(t, err)
+}
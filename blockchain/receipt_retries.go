@@ -0,0 +1,143 @@
+package blockchain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// ReceiptRetryConfig limits the retries of the receipt calls of all the nodes
+type ReceiptRetryConfig struct {
+	// Concurrency is the max number of retries in flight to all the nodes
+	Concurrency int
+	// RatePerNode is the max number of retries per second to a node
+	RatePerNode uint64
+}
+
+// DefaultReceiptRetryConfig is used for the unset limits of the retries
+var DefaultReceiptRetryConfig = ReceiptRetryConfig{
+	Concurrency: 16,
+	RatePerNode: 50,
+}
+
+// ReceiptRetries runs the retries of the receipt calls, apart from the first calls which the ReceiptFetcher of each
+// node limits. The retries of all the nodes share a concurrency limit and each node has a rate limit, so that the
+// receipts a restarting node misses are not retried by every fetch at once.
+type ReceiptRetries struct {
+	cfg   ReceiptRetryConfig
+	clock utils.Clock
+	slots chan struct{}
+
+	lock     sync.Mutex
+	limiters map[string]utils.RateLimiter
+}
+
+// NewReceiptRetries creates a ReceiptRetries with the config, its unset limits are taken from the default config
+func NewReceiptRetries(cfg ReceiptRetryConfig, clock utils.Clock) *ReceiptRetries {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultReceiptRetryConfig.Concurrency
+	}
+	if cfg.RatePerNode == 0 {
+		cfg.RatePerNode = DefaultReceiptRetryConfig.RatePerNode
+	}
+	return &ReceiptRetries{
+		cfg:      cfg,
+		clock:    clock,
+		slots:    make(chan struct{}, cfg.Concurrency),
+		limiters: make(map[string]utils.RateLimiter),
+	}
+}
+
+// retry fetches the missing receipts until they are all fetched or the attempts are exhausted, waiting for the
+// interval before each attempt. It returns the error of the last attempt.
+func (r *ReceiptRetries) retry(ctx context.Context, provider WSProvider, hashes []interface{}, receipts []interface{}, attempts int, interval time.Duration) error {
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var missing []int
+		for i, receipt := range receipts {
+			if receipt == nil {
+				missing = append(missing, i)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+
+		if waitErr := r.wait(ctx, interval); waitErr != nil {
+			return waitErr
+		}
+		if waitErr := r.acquire(ctx, provider.Addr()); waitErr != nil {
+			return waitErr
+		}
+		err = fetchReceipts(provider, hashes, receipts, missing, RPCOptions{RetryAttempts: 1, Context: ctx})
+		<-r.slots
+	}
+	return err
+}
+
+// acquire waits for the rate limit of the node, then for a slot of the retries
+func (r *ReceiptRetries) acquire(ctx context.Context, addr string) error {
+	limiter := r.limiter(addr)
+	for {
+		if allowed, _ := limiter.Take(); allowed {
+			break
+		}
+		if err := r.wait(ctx, time.Second/time.Duration(r.cfg.RatePerNode)); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *ReceiptRetries) limiter(addr string) utils.RateLimiter {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	limiter, ok := r.limiters[addr]
+	if !ok {
+		limiter = utils.NewLeakyBucketRateLimiter(r.clock, r.cfg.RatePerNode, time.Second)
+		r.limiters[addr] = limiter
+	}
+	return limiter
+}
+
+// wait returns after the duration, or with the error of the context if it is canceled before
+func (r *ReceiptRetries) wait(ctx context.Context, d time.Duration) error {
+	timer := r.clock.Timer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.Alert():
+		return nil
+	}
+}
+
+// fetchReceipts fetches the receipts of the hashes at the indices, a single one with a receipt call and several with a
+// batch call
+func fetchReceipts(provider WSProvider, hashes []interface{}, receipts []interface{}, indices []int, options RPCOptions) error {
+	if len(indices) == 1 {
+		receipt, err := provider.FetchTransactionReceipt(hashes[indices[0]:indices[0]+1], options)
+		receipts[indices[0]] = receipt
+		return err
+	}
+
+	batchHashes := make([]interface{}, len(indices))
+	for i, index := range indices {
+		batchHashes[i] = hashes[index]
+	}
+	batch, err := provider.FetchTransactionReceipts(batchHashes, options)
+	for i, index := range indices {
+		if i < len(batch) {
+			receipts[index] = batch[i]
+		}
+	}
+	return err
+}
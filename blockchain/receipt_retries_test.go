@@ -0,0 +1,98 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restartingProvider misses the receipts of its first calls, as a node which is restarting
+type restartingProvider struct {
+	WSProvider
+
+	lock        sync.Mutex
+	missedCalls int
+	calls       int
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *restartingProvider) Addr() string {
+	return "ws://127.0.0.1:8546"
+}
+
+func (p *restartingProvider) call(hashes []interface{}) ([]interface{}, bool) {
+	p.lock.Lock()
+	p.calls++
+	missed := p.calls <= p.missedCalls
+	p.inFlight++
+	if p.inFlight > p.maxInFlight {
+		p.maxInFlight = p.inFlight
+	}
+	p.lock.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	p.lock.Lock()
+	p.inFlight--
+	p.lock.Unlock()
+	if missed {
+		return make([]interface{}, len(hashes)), true
+	}
+	return hashes, false
+}
+
+func (p *restartingProvider) FetchTransactionReceipt(payload []interface{}, _ RPCOptions) (interface{}, error) {
+	receipts, _ := p.call(payload)
+	return receipts[0], nil
+}
+
+func (p *restartingProvider) FetchTransactionReceipts(hashes []interface{}, _ RPCOptions) ([]interface{}, error) {
+	receipts, missed := p.call(hashes)
+	if missed {
+		return receipts, fmt.Errorf("node is restarting")
+	}
+	return receipts, nil
+}
+
+func TestReceiptFetcher_Retries(t *testing.T) {
+	// the first calls of the 4 batches miss their receipts, their retries run one at a time
+	provider := &restartingProvider{missedCalls: 4}
+	retries := NewReceiptRetries(ReceiptRetryConfig{Concurrency: 1, RatePerNode: 1000}, utils.RealClock{})
+	fetcher := newReceiptFetcher(ReceiptFetchConfig{Concurrency: 4, BatchSize: 2, Retries: 3}, retries)
+
+	hashes := []interface{}{"0x1", "0x2", "0x3", "0x4", "0x5", "0x6", "0x7", "0x8"}
+	receipts, err := fetcher.Fetch(context.Background(), provider, hashes, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, hashes, receipts)
+	assert.Equal(t, 8, provider.calls)
+
+	provider = &restartingProvider{missedCalls: 100}
+	receipts, err = fetcher.Fetch(context.Background(), provider, hashes[:1], time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{nil}, receipts)
+	assert.Equal(t, 3, provider.calls)
+}
+
+func TestReceiptRetries_Concurrency(t *testing.T) {
+	provider := &restartingProvider{missedCalls: 8}
+	retries := NewReceiptRetries(ReceiptRetryConfig{Concurrency: 2, RatePerNode: 1000}, utils.RealClock{})
+
+	g := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		g.Add(1)
+		go func(i int) {
+			defer g.Done()
+			receipts := make([]interface{}, 1)
+			assert.NoError(t, retries.retry(context.Background(), provider, []interface{}{fmt.Sprintf("0x%v", i)}, receipts, 3, time.Millisecond))
+		}(i)
+	}
+	g.Wait()
+	assert.LessOrEqual(t, provider.maxInFlight, 2)
+}
@@ -0,0 +1,233 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/network"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// RemoteBridge is a Bridge whose other end runs in another process, such as the blockchain backend isolated from the
+// gateway. Each Send method passes its call to the other end through the transport, where it is delivered to the
+// Receive channels of the bridge of that end. The Converter methods run in-process.
+type RemoteBridge struct {
+	// Bridge holds the channels the calls of the other end are delivered to
+	Bridge
+	codec     BridgeCodec
+	transport BridgeTransport
+}
+
+// NewRemoteBridge returns a RemoteBridge exchanging the calls with the other end through the transport, Run must be
+// called for the calls of the other end to be received
+func NewRemoteBridge(converter Converter, beaconBlock bool, transport BridgeTransport, codec BridgeCodec) *RemoteBridge {
+	return &RemoteBridge{
+		Bridge:    NewBxBridge(converter, beaconBlock),
+		codec:     codec,
+		transport: transport,
+	}
+}
+
+// Run delivers the calls of the other end until the transport closes or the context is done
+func (b *RemoteBridge) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return b.transport.Close()
+		case data, ok := <-b.transport.Receive():
+			if !ok {
+				return errors.New("bridge transport was closed")
+			}
+			message, err := b.codec.Decode(data)
+			if err != nil {
+				log.Errorf("dropping bridge message: %v", err)
+				continue
+			}
+			if err = b.deliver(message); err != nil {
+				log.Warnf("dropping bridge message %v: %v", message.Type, err)
+			}
+		}
+	}
+}
+
+func (b *RemoteBridge) send(messageType BridgeMessageType, payload interface{}) error {
+	data, err := b.codec.Encode(BridgeMessage{Type: messageType, Payload: payload})
+	if err != nil {
+		return err
+	}
+	return b.transport.Send(data)
+}
+
+// deliver calls the Send method of the message on the local bridge
+func (b *RemoteBridge) deliver(message BridgeMessage) error {
+	switch payload := message.Payload.(type) {
+	case network.EthConfig:
+		return b.Bridge.UpdateNetworkConfig(payload)
+	case TransactionAnnouncement:
+		if message.Type == BridgeTransactionsRequest {
+			return b.Bridge.RequestTransactionsFromNode(payload.PeerID, payload.Hashes)
+		}
+		return b.Bridge.AnnounceTransactionHashes(payload.PeerID, payload.Hashes, payload.PeerEndpoint)
+	case Transactions:
+		if message.Type == BridgeTransactionsFromBDN {
+			return b.Bridge.SendTransactionsFromBDN(payload)
+		}
+		return b.Bridge.SendTransactionsToBDN(payload.Transactions, payload.PeerEndpoint)
+	case BlockFromNode:
+		switch message.Type {
+		case BridgeConfirmedBlock:
+			return b.Bridge.SendConfirmedBlockToGateway(payload.Block, payload.PeerEndpoint)
+		case BridgeBackfilledBlock:
+			return b.Bridge.SendBackfilledBlock(payload)
+		}
+		return b.Bridge.SendBlockToBDN(payload.Block, payload.PeerEndpoint)
+	case *types.BxBlock:
+		return b.Bridge.SendBlockToNode(payload)
+	case BlockHeaderFromNode:
+		return b.Bridge.SendBlockHeaderToGateway(payload.Header, payload.PeerEndpoint)
+	case []*types.NodeEndpoint:
+		return b.Bridge.SendBlockchainStatusResponse(payload)
+	case types.NodeEndpoint:
+		if message.Type == BridgeDisconnectEvent {
+			return b.Bridge.SendDisconnectEvent(payload)
+		}
+		return b.Bridge.SendNodeConnectionCheckResponse(payload)
+	case *ValidatorListInfo:
+		return b.Bridge.SendValidatorListInfo(payload)
+	case []ProposerDuty:
+		return b.Bridge.SendProposerDuties(payload)
+	case ConnectionStatus:
+		return b.Bridge.SendBlockchainConnectionStatus(payload)
+	case Heartbeat:
+		if message.Type == BridgeHeartbeatAck {
+			return b.Bridge.SendHeartbeatAck(payload)
+		}
+		return b.Bridge.SendHeartbeat(payload)
+	case BlockBackfillRequest:
+		return b.Bridge.SendBlockBackfillRequest(payload)
+	}
+
+	switch message.Type {
+	case BridgeNoActivePeersAlert:
+		return b.Bridge.SendNoActiveBlockchainPeersAlert()
+	case BridgeStatusRequest:
+		return b.Bridge.SendBlockchainStatusRequest()
+	case BridgeNodeConnectionCheckRequest:
+		return b.Bridge.SendNodeConnectionCheckRequest()
+	default:
+		return fmt.Errorf("unexpected payload %T", message.Payload)
+	}
+}
+
+// UpdateNetworkConfig passes the Ethereum configuration update to the other end
+func (b *RemoteBridge) UpdateNetworkConfig(config network.EthConfig) error {
+	return b.send(BridgeNetworkConfig, config)
+}
+
+// AnnounceTransactionHashes passes the transaction announcements to the other end
+func (b *RemoteBridge) AnnounceTransactionHashes(peerID string, hashes types.SHA256HashList, endpoint types.NodeEndpoint) error {
+	return b.send(BridgeTransactionAnnouncement, TransactionAnnouncement{Hashes: hashes, PeerID: peerID, PeerEndpoint: endpoint})
+}
+
+// RequestTransactionsFromNode passes the request of the transactions a peer node has announced to the other end
+func (b *RemoteBridge) RequestTransactionsFromNode(peerID string, hashes types.SHA256HashList) error {
+	return b.send(BridgeTransactionsRequest, TransactionAnnouncement{Hashes: hashes, PeerID: peerID})
+}
+
+// SendTransactionsFromBDN passes the transactions from the BDN to the other end
+func (b *RemoteBridge) SendTransactionsFromBDN(transactions Transactions) error {
+	return b.send(BridgeTransactionsFromBDN, transactions)
+}
+
+// SendTransactionsToBDN passes the transactions from a node to the other end
+func (b *RemoteBridge) SendTransactionsToBDN(txs []*types.BxTransaction, peerEndpoint types.NodeEndpoint) error {
+	return b.send(BridgeTransactionsToBDN, Transactions{Transactions: txs, PeerEndpoint: peerEndpoint})
+}
+
+// SendConfirmedBlockToGateway passes the confirmed block to the other end
+func (b *RemoteBridge) SendConfirmedBlockToGateway(block *types.BxBlock, peerEndpoint types.NodeEndpoint) error {
+	return b.send(BridgeConfirmedBlock, BlockFromNode{Block: block, PeerEndpoint: peerEndpoint})
+}
+
+// SendBlockHeaderToGateway passes the header of a block from a node to the other end
+func (b *RemoteBridge) SendBlockHeaderToGateway(header *ethtypes.Header, peerEndpoint types.NodeEndpoint) error {
+	return b.send(BridgeBlockHeader, BlockHeaderFromNode{Header: header, PeerEndpoint: peerEndpoint})
+}
+
+// SendBlockToBDN passes the block from a node to the other end
+func (b *RemoteBridge) SendBlockToBDN(block *types.BxBlock, peerEndpoint types.NodeEndpoint) error {
+	return b.send(BridgeBlockToBDN, BlockFromNode{Block: block, PeerEndpoint: peerEndpoint})
+}
+
+// SendBlockToNode passes the block from the BDN to the other end
+func (b *RemoteBridge) SendBlockToNode(block *types.BxBlock) error {
+	return b.send(BridgeBlockToNode, block)
+}
+
+// SendNoActiveBlockchainPeersAlert passes the alert to the other end
+func (b *RemoteBridge) SendNoActiveBlockchainPeersAlert() error {
+	return b.send(BridgeNoActivePeersAlert, nil)
+}
+
+// SendBlockchainStatusRequest passes the blockchain connection status request to the other end
+func (b *RemoteBridge) SendBlockchainStatusRequest() error {
+	return b.send(BridgeStatusRequest, nil)
+}
+
+// SendBlockchainStatusResponse passes the blockchain connection status response to the other end
+func (b *RemoteBridge) SendBlockchainStatusResponse(endpoints []*types.NodeEndpoint) error {
+	return b.send(BridgeStatusResponse, endpoints)
+}
+
+// SendNodeConnectionCheckRequest passes the node connection check request to the other end
+func (b *RemoteBridge) SendNodeConnectionCheckRequest() error {
+	return b.send(BridgeNodeConnectionCheckRequest, nil)
+}
+
+// SendNodeConnectionCheckResponse passes the node connection check response to the other end
+func (b *RemoteBridge) SendNodeConnectionCheckResponse(endpoint types.NodeEndpoint) error {
+	return b.send(BridgeNodeConnectionCheckResponse, endpoint)
+}
+
+// SendValidatorListInfo passes the validator info to the other end
+func (b *RemoteBridge) SendValidatorListInfo(info *ValidatorListInfo) error {
+	return b.send(BridgeValidatorListInfo, info)
+}
+
+// SendProposerDuties passes the proposers of upcoming beacon slots to the other end
+func (b *RemoteBridge) SendProposerDuties(duties []ProposerDuty) error {
+	return b.send(BridgeProposerDuties, duties)
+}
+
+// SendBlockchainConnectionStatus passes the blockchain connection status to the other end
+func (b *RemoteBridge) SendBlockchainConnectionStatus(connStatus ConnectionStatus) error {
+	return b.send(BridgeConnectionStatus, connStatus)
+}
+
+// SendDisconnectEvent passes the disconnect event to the other end
+func (b *RemoteBridge) SendDisconnectEvent(endpoint types.NodeEndpoint) error {
+	return b.send(BridgeDisconnectEvent, endpoint)
+}
+
+// SendHeartbeat passes the heartbeat to the other end
+func (b *RemoteBridge) SendHeartbeat(heartbeat Heartbeat) error {
+	return b.send(BridgeHeartbeat, heartbeat)
+}
+
+// SendHeartbeatAck passes the acknowledgement of the heartbeat to the other end
+func (b *RemoteBridge) SendHeartbeatAck(heartbeat Heartbeat) error {
+	return b.send(BridgeHeartbeatAck, heartbeat)
+}
+
+// SendBlockBackfillRequest passes the request of the blocks the gateway missed to the other end
+func (b *RemoteBridge) SendBlockBackfillRequest(request BlockBackfillRequest) error {
+	return b.send(BridgeBlockBackfillRequest, request)
+}
+
+// SendBackfilledBlock passes a block the gateway missed to the other end
+func (b *RemoteBridge) SendBackfilledBlock(block BlockFromNode) error {
+	return b.send(BridgeBackfilledBlock, block)
+}
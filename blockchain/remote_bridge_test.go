@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/network"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteBridge(t *testing.T) {
+	gatewayConn, backendConn := net.Pipe()
+	gateway := NewRemoteBridge(nil, false, NewStreamBridgeTransport(gatewayConn), GobBridgeCodec{})
+	backend := NewRemoteBridge(nil, false, NewStreamBridgeTransport(backendConn), GobBridgeCodec{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = gateway.Run(ctx) }()
+	go func() { _ = backend.Run(ctx) }()
+
+	endpoint := types.NodeEndpoint{IP: "127.0.0.1", Port: 30303, PublicKey: "node"}
+
+	// transactions from the BDN are received by the backend, on the priority lane for the paid ones
+	tx := types.NewBxTransaction(types.SHA256Hash{1}, 5, types.TFPaidTx, time.Unix(1000, 0))
+	tx.SetContent([]byte{1, 2, 3})
+	tx.AddShortID(7)
+	require.NoError(t, gateway.SendTransactionsFromBDN(Transactions{Transactions: []*types.BxTransaction{tx}}))
+	select {
+	case txs := <-backend.ReceivePaidBDNTransactions():
+		require.Len(t, txs.Transactions, 1)
+		assert.Equal(t, tx.Hash(), txs.Transactions[0].Hash())
+		assert.Equal(t, tx.Content(), txs.Transactions[0].Content())
+		assert.Equal(t, tx.ShortIDs(), txs.Transactions[0].ShortIDs())
+		assert.Equal(t, tx.Flags(), txs.Transactions[0].Flags())
+		assert.True(t, tx.AddTime().Equal(txs.Transactions[0].AddTime()))
+	case <-time.After(time.Second):
+		t.Fatal("transactions were not received")
+	}
+
+	// blocks and headers from the nodes are received by the gateway
+	block := types.NewRawBxBlock(types.SHA256Hash{2}, types.SHA256Hash{}, types.BxBlockTypeEth, []byte{4},
+		[]*types.BxBlockTransaction{types.NewBxBlockTransaction(types.SHA256Hash{1}, []byte{1, 2, 3})}, []byte{5},
+		big.NewInt(10), big.NewInt(100), 200)
+	require.NoError(t, backend.SendBlockToBDN(block, endpoint))
+	header := &ethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1)}
+	require.NoError(t, backend.SendBlockHeaderToGateway(header, endpoint))
+	select {
+	case blockFromNode := <-gateway.ReceiveBlockFromNode():
+		assert.True(t, block.Equals(blockFromNode.Block))
+		assert.Equal(t, block.Size(), blockFromNode.Block.Size())
+		assert.Equal(t, endpoint, blockFromNode.PeerEndpoint)
+	case <-time.After(time.Second):
+		t.Fatal("block was not received")
+	}
+	select {
+	case headerFromNode := <-gateway.ReceiveBlockHeaderFromNode():
+		assert.Equal(t, header.Hash(), headerFromNode.Header.Hash())
+	case <-time.After(time.Second):
+		t.Fatal("header was not received")
+	}
+
+	// the signals without payload are received as well
+	require.NoError(t, gateway.SendBlockchainStatusRequest())
+	select {
+	case <-backend.ReceiveBlockchainStatusRequest():
+	case <-time.After(time.Second):
+		t.Fatal("status request was not received")
+	}
+
+	// the network config keeps its private key
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, gateway.UpdateNetworkConfig(network.EthConfig{PrivateKey: privateKey, Network: 1, TotalDifficulty: big.NewInt(3)}))
+	select {
+	case config := <-backend.ReceiveNetworkConfigUpdates():
+		assert.Equal(t, privateKey.D, config.PrivateKey.D)
+		assert.Equal(t, uint64(1), config.Network)
+		assert.Equal(t, big.NewInt(3), config.TotalDifficulty)
+	case <-time.After(time.Second):
+		t.Fatal("network config was not received")
+	}
+
+	// the bridge of the closed end stops
+	_ = backendConn.Close()
+	assert.Error(t, backend.Run(context.Background()))
+}
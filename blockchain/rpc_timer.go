@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// receiptsBatchMethod labels the batch calls of FetchTransactionReceipts, to keep them apart from the single receipts
+const receiptsBatchMethod = "eth_getTransactionReceipt_batch"
+
+// rpcLatencyBuckets are the buckets of the node RPC call latency in seconds, from 1ms to 16s
+var rpcLatencyBuckets = prometheus.ExponentialBuckets(0.001, 2, 15)
+
+// RPCTimer measures the duration of the RPC calls of the node websocket providers. It logs the calls slower than the
+// threshold with their method, payload digest and provider, and keeps a latency histogram per method, to tell whether
+// the lag of the feeds comes from the gateway or from the node. All methods do nothing on a nil receiver.
+type RPCTimer struct {
+	slowThreshold time.Duration
+	clock         utils.Clock
+	latency       *prometheus.HistogramVec
+	log           *log.Entry
+}
+
+// NewRPCTimer creates an RPCTimer logging the calls slower than slowThreshold, 0 disables the logs
+func NewRPCTimer(slowThreshold time.Duration, clock utils.Clock) *RPCTimer {
+	return &RPCTimer{
+		slowThreshold: slowThreshold,
+		clock:         clock,
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gateway",
+			Name:      "node_rpc_call_seconds",
+			Help:      "Duration of the RPC calls to the blockchain node by method, including the retries.",
+			Buckets:   rpcLatencyBuckets,
+		}, []string{"method"}),
+		log: log.WithField("component", "nodeRPCTimer"),
+	}
+}
+
+// Wrap returns a constructor of the providers whose calls are measured by the timer
+func (t *RPCTimer) Wrap(newWS func(string, types.NodeEndpoint, time.Duration) WSProvider) func(string, types.NodeEndpoint, time.Duration) WSProvider {
+	if t == nil {
+		return newWS
+	}
+	return func(uri string, peerEndpoint types.NodeEndpoint, timeout time.Duration) WSProvider {
+		return &timedWSProvider{WSProvider: newWS(uri, peerEndpoint, timeout), timer: t}
+	}
+}
+
+// Describe implements prometheus.Collector
+func (t *RPCTimer) Describe(ch chan<- *prometheus.Desc) {
+	if t == nil {
+		return
+	}
+	t.latency.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (t *RPCTimer) Collect(ch chan<- prometheus.Metric) {
+	if t == nil {
+		return
+	}
+	t.latency.Collect(ch)
+}
+
+// observe records a call started at the time
+func (t *RPCTimer) observe(provider WSProvider, method string, payload interface{}, start time.Time, err error) {
+	duration := t.clock.Now().Sub(start)
+	t.latency.WithLabelValues(method).Observe(duration.Seconds())
+	if t.slowThreshold == 0 || duration < t.slowThreshold {
+		return
+	}
+	t.log.WithFields(log.Fields{
+		"method":        method,
+		"payloadDigest": payloadDigest(payload),
+		"provider":      provider.Addr(),
+		"duration":      duration,
+		"failed":        err != nil,
+	}).Warnf("slow node RPC call, took %v over the %v threshold", duration, t.slowThreshold)
+}
+
+// payloadDigest identifies a payload in the logs without its content, which may be a whole signed tx
+func payloadDigest(payload interface{}) string {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		encoded = []byte(fmt.Sprint(payload))
+	}
+	digest := sha256.Sum256(encoded)
+	return hex.EncodeToString(digest[:8])
+}
+
+// timedWSProvider measures the RPC calls of the provider it wraps
+type timedWSProvider struct {
+	WSProvider
+	timer *RPCTimer
+}
+
+func (p *timedWSProvider) CallRPC(method string, payload []interface{}, options RPCOptions) (interface{}, error) {
+	start := p.timer.clock.Now()
+	response, err := p.WSProvider.CallRPC(method, payload, options)
+	p.timer.observe(p.WSProvider, method, payload, start, err)
+	return response, err
+}
+
+func (p *timedWSProvider) FetchTransaction(payload []interface{}, options RPCOptions) (interface{}, error) {
+	start := p.timer.clock.Now()
+	response, err := p.WSProvider.FetchTransaction(payload, options)
+	p.timer.observe(p.WSProvider, "eth_getTransactionByHash", payload, start, err)
+	return response, err
+}
+
+func (p *timedWSProvider) FetchBlock(payload []interface{}, options RPCOptions) (interface{}, error) {
+	start := p.timer.clock.Now()
+	response, err := p.WSProvider.FetchBlock(payload, options)
+	p.timer.observe(p.WSProvider, "eth_getBlockByNumber", payload, start, err)
+	return response, err
+}
+
+func (p *timedWSProvider) FetchTransactionReceipt(payload []interface{}, options RPCOptions) (interface{}, error) {
+	start := p.timer.clock.Now()
+	response, err := p.WSProvider.FetchTransactionReceipt(payload, options)
+	p.timer.observe(p.WSProvider, "eth_getTransactionReceipt", payload, start, err)
+	return response, err
+}
+
+func (p *timedWSProvider) FetchTransactionReceipts(hashes []interface{}, options RPCOptions) ([]interface{}, error) {
+	start := p.timer.clock.Now()
+	receipts, err := p.WSProvider.FetchTransactionReceipts(hashes, options)
+	p.timer.observe(p.WSProvider, receiptsBatchMethod, hashes, start, err)
+	return receipts, err
+}
+
+func (p *timedWSProvider) SendTransaction(rawTx string, options RPCOptions) (interface{}, error) {
+	start := p.timer.clock.Now()
+	response, err := p.WSProvider.SendTransaction(rawTx, options)
+	p.timer.observe(p.WSProvider, "eth_sendRawTransaction", []interface{}{rawTx}, start, err)
+	return response, err
+}
@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowWSProvider takes the delay of the mock clock to answer the calls
+type slowWSProvider struct {
+	WSProvider
+	clock *utils.MockClock
+	delay time.Duration
+	calls []string
+}
+
+func (p *slowWSProvider) Addr() string {
+	return "ws://node:8546"
+}
+
+func (p *slowWSProvider) CallRPC(method string, _ []interface{}, _ RPCOptions) (interface{}, error) {
+	p.clock.IncTime(p.delay)
+	p.calls = append(p.calls, method)
+	return "0x1", nil
+}
+
+func (p *slowWSProvider) SendTransaction(string, RPCOptions) (interface{}, error) {
+	p.clock.IncTime(p.delay)
+	p.calls = append(p.calls, "eth_sendRawTransaction")
+	return nil, errors.New("nonce too low")
+}
+
+func TestRPCTimer(t *testing.T) {
+	clock := &utils.MockClock{}
+	provider := &slowWSProvider{clock: clock, delay: 20 * time.Millisecond}
+	timer := NewRPCTimer(time.Second, clock)
+	newWS := timer.Wrap(func(string, types.NodeEndpoint, time.Duration) WSProvider { return provider })
+	ws := newWS("ws://node:8546", types.NodeEndpoint{}, time.Second)
+
+	response, err := ws.CallRPC("eth_call", []interface{}{"0x"}, RPCOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "0x1", response)
+	provider.delay = 2 * time.Second
+	_, err = ws.SendTransaction("0xf8", RPCOptions{})
+	assert.EqualError(t, err, "nonce too low")
+	_, err = ws.CallRPC("eth_call", []interface{}{"0x"}, RPCOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"eth_call", "eth_sendRawTransaction", "eth_call"}, provider.calls)
+	assert.Equal(t, 2, testutil.CollectAndCount(timer, "gateway_node_rpc_call_seconds"))
+	assert.Equal(t, "ws://node:8546", ws.Addr())
+}
+
+func TestRPCTimer_Nil(t *testing.T) {
+	var timer *RPCTimer
+	provider := &slowWSProvider{}
+	newWS := timer.Wrap(func(string, types.NodeEndpoint, time.Duration) WSProvider { return provider })
+	assert.Equal(t, provider, newWS("", types.NodeEndpoint{}, time.Second))
+	assert.Equal(t, 0, testutil.CollectAndCount(timer))
+}
+
+func TestPayloadDigest(t *testing.T) {
+	assert.Len(t, payloadDigest([]interface{}{"0xf8"}), 16)
+	assert.Equal(t, payloadDigest([]interface{}{"0xf8"}), payloadDigest([]interface{}{"0xf8"}))
+	assert.NotEqual(t, payloadDigest([]interface{}{"0xf8"}), payloadDigest([]interface{}{"0xf9"}))
+}
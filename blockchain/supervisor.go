@@ -0,0 +1,166 @@
+package blockchain
+
+import (
+	"context"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	// heartbeatsPerTimeout is the number of heartbeats sent to the backend within the timeout of the supervisor
+	heartbeatsPerTimeout = 3
+	// backendStopTimeout bounds the wait for a backend to stop before it is replaced, a deadlocked backend never stops
+	backendStopTimeout = 10 * time.Second
+)
+
+// SupervisedBackend is a blockchain backend handling the bridge which the Supervisor restarts, such as the Ethereum
+// p2p server
+type SupervisedBackend interface {
+	Start() error
+	Stop()
+}
+
+// BackendFactory creates an instance of the backend, which stops handling the bridge once the context is done
+type BackendFactory func(ctx context.Context) (SupervisedBackend, error)
+
+// Supervisor health-checks a blockchain backend with heartbeats over the bridge and restarts it if they are not
+// acknowledged within the timeout, which happens if it deadlocks or stops handling the bridge after a panic. The
+// bridge outlives the backend so the requests pending in it are handled by the new instance. The peers of the replaced
+// instance are reported disconnected, the new instance reports them connected again as it reconnects to them.
+type Supervisor struct {
+	bridge  Bridge
+	factory BackendFactory
+	timeout time.Duration
+	clock   utils.Clock
+
+	backend         SupervisedBackend
+	cancel          context.CancelFunc
+	sequence        uint64
+	startedSequence uint64
+	lastAck         time.Time
+	peers           []types.NodeEndpoint
+	restarts        int
+}
+
+// NewSupervisor creates a supervisor restarting the backends of the factory when they do not acknowledge the
+// heartbeats for the timeout
+func NewSupervisor(bridge Bridge, factory BackendFactory, timeout time.Duration, clock utils.Clock) *Supervisor {
+	return &Supervisor{
+		bridge:  bridge,
+		factory: factory,
+		timeout: timeout,
+		clock:   clock,
+	}
+}
+
+// Run starts the backend and supervises it until the context is done, it returns an error if the first instance of
+// the backend cannot be started
+func (s *Supervisor) Run(ctx context.Context) error {
+	if err := s.start(ctx); err != nil {
+		return err
+	}
+	defer s.stop()
+
+	ticker := s.clock.Ticker(s.timeout / heartbeatsPerTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ack := <-s.bridge.ReceiveHeartbeatAck():
+			s.acknowledge(ack)
+		case <-ticker.Alert():
+			s.check(ctx)
+		}
+	}
+}
+
+// acknowledge records the peers of the backend, the acknowledgements of the heartbeats sent to a replaced instance
+// are ignored
+func (s *Supervisor) acknowledge(ack Heartbeat) {
+	if ack.Sequence <= s.startedSequence {
+		return
+	}
+	s.lastAck = s.clock.Now()
+	s.peers = ack.Peers
+}
+
+// check restarts the backend if it did not acknowledge the heartbeats for the timeout, or sends it the next heartbeat
+func (s *Supervisor) check(ctx context.Context) {
+	if silence := s.clock.Now().Sub(s.lastAck); silence > s.timeout {
+		log.Errorf("blockchain backend did not acknowledge the heartbeats for %v, restarting it", silence)
+		s.restart(ctx)
+		return
+	}
+
+	s.sequence++
+	if err := s.bridge.SendHeartbeat(Heartbeat{Sequence: s.sequence}); err != nil {
+		log.Warnf("failed to send heartbeat %v to the blockchain backend: %v", s.sequence, err)
+	}
+}
+
+func (s *Supervisor) restart(ctx context.Context) {
+	s.stop()
+
+	for _, peer := range s.peers {
+		status := ConnectionStatus{PeerEndpoint: peer, IsConnected: false, IsDynamic: peer.Dynamic}
+		if err := s.bridge.SendBlockchainConnectionStatus(status); err != nil {
+			log.Errorf("failed to report peer %v of the replaced blockchain backend disconnected: %v", peer.IPPort(), err)
+		}
+	}
+	s.peers = nil
+
+	s.restarts++
+	if err := s.start(ctx); err != nil {
+		// the restart is retried after the timeout
+		log.Errorf("failed to restart the blockchain backend, attempt %v: %v", s.restarts, err)
+		return
+	}
+	log.Infof("restarted the blockchain backend, %v restarts so far", s.restarts)
+}
+
+func (s *Supervisor) start(ctx context.Context) error {
+	// the attempt counts as an acknowledgement so the backend is given the timeout to start
+	s.lastAck = s.clock.Now()
+	s.startedSequence = s.sequence
+
+	backendCtx, cancel := context.WithCancel(ctx)
+	backend, err := s.factory(backendCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	if err = backend.Start(); err != nil {
+		cancel()
+		return err
+	}
+	s.backend = backend
+	s.cancel = cancel
+	return nil
+}
+
+// stop stops the backend, giving up on a deadlocked backend after backendStopTimeout
+func (s *Supervisor) stop() {
+	if s.backend == nil {
+		return
+	}
+	s.cancel()
+
+	stopped := make(chan struct{})
+	go func(backend SupervisedBackend) {
+		backend.Stop()
+		close(stopped)
+	}(s.backend)
+	s.backend = nil
+
+	timer := s.clock.Timer(backendStopTimeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+	case <-timer.Alert():
+		log.Errorf("blockchain backend did not stop within %v, abandoning it", backendStopTimeout)
+	}
+}
@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBackend struct {
+	started bool
+	stopped bool
+}
+
+func (b *testBackend) Start() error {
+	b.started = true
+	return nil
+}
+
+func (b *testBackend) Stop() {
+	b.stopped = true
+}
+
+func TestSupervisor(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(0, 0))
+	bridge := NewBxBridge(nil, false)
+
+	var backends []*testBackend
+	factory := func(ctx context.Context) (SupervisedBackend, error) {
+		backend := &testBackend{}
+		backends = append(backends, backend)
+		return backend, nil
+	}
+	supervisor := NewSupervisor(bridge, factory, 3*time.Second, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, supervisor.start(ctx))
+	require.Len(t, backends, 1)
+	assert.True(t, backends[0].started)
+
+	// the acknowledged heartbeats keep the backend running
+	supervisor.check(ctx)
+	heartbeat := <-bridge.ReceiveHeartbeat()
+	assert.Equal(t, uint64(1), heartbeat.Sequence)
+	peer := types.NodeEndpoint{IP: "127.0.0.1", Port: 30303, Dynamic: true}
+	supervisor.acknowledge(Heartbeat{Sequence: heartbeat.Sequence, Peers: []types.NodeEndpoint{peer}})
+
+	clock.IncTime(2 * time.Second)
+	supervisor.check(ctx)
+	heartbeat = <-bridge.ReceiveHeartbeat()
+	assert.Equal(t, uint64(2), heartbeat.Sequence)
+	require.Len(t, backends, 1)
+
+	// the backend silent for the timeout is replaced and its peers are reported disconnected
+	clock.IncTime(2 * time.Second)
+	supervisor.check(ctx)
+	require.Len(t, backends, 2)
+	assert.True(t, backends[0].stopped)
+	assert.True(t, backends[1].started)
+	select {
+	case status := <-bridge.ReceiveBlockchainConnectionStatus():
+		assert.Equal(t, peer, status.PeerEndpoint)
+		assert.False(t, status.IsConnected)
+		assert.True(t, status.IsDynamic)
+	default:
+		t.Fatal("peer of the replaced backend was not reported disconnected")
+	}
+
+	// the late acknowledgement of the replaced backend does not count for the new one
+	supervisor.acknowledge(Heartbeat{Sequence: heartbeat.Sequence})
+	clock.IncTime(4 * time.Second)
+	supervisor.check(ctx)
+	require.Len(t, backends, 3)
+	assert.True(t, backends[1].stopped)
+
+	// the heartbeats after the restart are acknowledged by the new backend
+	supervisor.check(ctx)
+	heartbeat = <-bridge.ReceiveHeartbeat()
+	assert.Equal(t, uint64(3), heartbeat.Sequence)
+	supervisor.acknowledge(heartbeat)
+	clock.IncTime(2 * time.Second)
+	supervisor.check(ctx)
+	require.Len(t, backends, 3)
+
+	supervisor.stop()
+	assert.True(t, backends[2].stopped)
+}
@@ -0,0 +1,192 @@
+package blockchain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// TxFlowShapingConfig limits the rate of transactions delivered from the BDN to the blockchain node, zero rate means no limit
+type TxFlowShapingConfig struct {
+	TxsPerSecond   float64
+	BytesPerSecond float64
+	// Burst is the amount of time worth of traffic that can be sent at once after an idle period
+	Burst time.Duration
+}
+
+// Enabled returns true if any limit is configured
+func (c TxFlowShapingConfig) Enabled() bool {
+	return c.TxsPerSecond > 0 || c.BytesPerSecond > 0
+}
+
+// tokenBucket allows a rate of units per second with a burst allowance
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst time.Duration, now time.Time) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	capacity := rate * burst.Seconds()
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &tokenBucket{rate: rate, burst: capacity, tokens: capacity, last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// wait returns how long to wait until the amount can be taken
+func (b *tokenBucket) wait(amount float64, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.refill(now)
+	// an item larger than the burst is sent once the bucket is full
+	if amount > b.burst {
+		amount = b.burst
+	}
+	if b.tokens >= amount {
+		return 0
+	}
+	return time.Duration((amount - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) take(amount float64) {
+	if b == nil {
+		return
+	}
+	if amount > b.burst {
+		amount = b.burst
+	}
+	b.tokens -= amount
+}
+
+// TxFlowShapedBridge delivers the transactions from the BDN to the node at a limited rate. Blocks and
+// all the other bridge messages are not affected and keep their priority over the shaped transactions.
+type TxFlowShapedBridge struct {
+	Bridge
+	cfg   TxFlowShapingConfig
+	clock utils.Clock
+	queue chan Transactions
+
+	lock    sync.Mutex
+	txs     *tokenBucket
+	bytes   *tokenBucket
+	delayed uint64
+	dropped uint64
+}
+
+// NewTxFlowShapedBridge wraps the bridge with rate limiting of the transactions sent from the BDN to the node
+func NewTxFlowShapedBridge(bridge Bridge, cfg TxFlowShapingConfig, clock utils.Clock) *TxFlowShapedBridge {
+	now := clock.Now()
+	return &TxFlowShapedBridge{
+		Bridge: bridge,
+		cfg:    cfg,
+		clock:  clock,
+		queue:  make(chan Transactions, transactionBacklog),
+		txs:    newTokenBucket(cfg.TxsPerSecond, cfg.Burst, now),
+		bytes:  newTokenBucket(cfg.BytesPerSecond, cfg.Burst, now),
+	}
+}
+
+// SendTransactionsFromBDN queues the transactions to be delivered to the nodes at the configured rate
+func (b *TxFlowShapedBridge) SendTransactionsFromBDN(transactions Transactions) error {
+	select {
+	case b.queue <- transactions:
+		return nil
+	default:
+		b.lock.Lock()
+		b.dropped += uint64(len(transactions.Transactions))
+		b.lock.Unlock()
+		return ErrChannelFull
+	}
+}
+
+// Stats returns the number of transactions delayed and dropped by the shaper
+func (b *TxFlowShapedBridge) Stats() (delayed uint64, dropped uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.delayed, b.dropped
+}
+
+// Run delivers the queued transactions until the context is done
+func (b *TxFlowShapedBridge) Run(ctx context.Context) {
+	log.Infof("shaping transactions from BDN to node: %v txs/sec, %v bytes/sec, burst %v", b.cfg.TxsPerSecond, b.cfg.BytesPerSecond, b.cfg.Burst)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case transactions := <-b.queue:
+			b.deliver(transactions)
+		}
+	}
+}
+
+func (b *TxFlowShapedBridge) deliver(transactions Transactions) {
+	batch := transactions
+	batch.Transactions = make([]*types.BxTransaction, 0, len(transactions.Transactions))
+
+	for _, tx := range transactions.Transactions {
+		size := float64(len(tx.Content()))
+
+		b.lock.Lock()
+		now := b.clock.Now()
+		waitTime := b.txs.wait(1, now)
+		if bytesWait := b.bytes.wait(size, now); bytesWait > waitTime {
+			waitTime = bytesWait
+		}
+		if waitTime > 0 {
+			b.delayed++
+		}
+		b.lock.Unlock()
+
+		if waitTime > 0 {
+			// flush what is allowed so far before waiting for the next tokens
+			b.forward(batch)
+			batch.Transactions = make([]*types.BxTransaction, 0, len(transactions.Transactions))
+			b.clock.Sleep(waitTime)
+
+			b.lock.Lock()
+			now = b.clock.Now()
+			b.txs.refill(now)
+			b.bytes.refill(now)
+			b.lock.Unlock()
+		}
+
+		b.lock.Lock()
+		b.txs.take(1)
+		b.bytes.take(size)
+		b.lock.Unlock()
+
+		batch.Transactions = append(batch.Transactions, tx)
+	}
+
+	b.forward(batch)
+}
+
+func (b *TxFlowShapedBridge) forward(transactions Transactions) {
+	if len(transactions.Transactions) == 0 {
+		return
+	}
+	if err := b.Bridge.SendTransactionsFromBDN(transactions); err != nil {
+		log.Errorf("failed to send %v shaped transactions from BDN to bridge: %v", len(transactions.Transactions), err)
+	}
+}
@@ -0,0 +1,36 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(10, time.Second, now)
+
+	// burst allowance is available right away
+	for i := 0; i < 10; i++ {
+		assert.Zero(t, bucket.wait(1, now))
+		bucket.take(1)
+	}
+	assert.Equal(t, 100*time.Millisecond, bucket.wait(1, now))
+
+	// tokens are refilled at the configured rate
+	now = now.Add(100 * time.Millisecond)
+	assert.Zero(t, bucket.wait(1, now))
+	bucket.take(1)
+
+	// refill does not exceed the burst allowance
+	now = now.Add(time.Hour)
+	assert.Zero(t, bucket.wait(10, now))
+	bucket.take(10)
+	assert.NotZero(t, bucket.wait(1, now))
+
+	// no limit configured
+	assert.Nil(t, newTokenBucket(0, time.Second, now))
+	var unlimited *tokenBucket
+	assert.Zero(t, unlimited.wait(1000, now))
+}
@@ -0,0 +1,232 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxPoolState describes how transactions are forwarded to the node based on the usage of its tx pool
+type TxPoolState string
+
+// TxPoolState types enumeration
+const (
+	TxPoolNormal    TxPoolState = "NORMAL"
+	TxPoolThrottled TxPoolState = "THROTTLED"
+	TxPoolPaused    TxPoolState = "PAUSED"
+)
+
+// TxPoolMonitorConfig defines when transactions forwarding to the nodes is throttled or paused
+type TxPoolMonitorConfig struct {
+	// Capacity is the number of pending and queued transactions considered as a full pool, zero disables the monitor
+	Capacity uint64
+	// ThrottleThreshold and PauseThreshold are the fractions of the capacity at which forwarding is throttled or paused
+	ThrottleThreshold float64
+	PauseThreshold    float64
+	// ThrottledTxsPerSecond is the rate of transactions forwarded while throttled
+	ThrottledTxsPerSecond int
+	Interval              time.Duration
+}
+
+// NodeTxPoolStatus is the last known tx pool usage of a node
+type NodeTxPoolStatus struct {
+	Endpoint  string      `json:"endpoint"`
+	Pending   uint64      `json:"pending"`
+	Queued    uint64      `json:"queued"`
+	State     TxPoolState `json:"state"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// TxPoolMonitor periodically queries txpool_status of the connected nodes and decides if transactions
+// from the BDN can be forwarded, the most loaded node determines the state
+type TxPoolMonitor struct {
+	cfg       TxPoolMonitorConfig
+	wsManager WSManager
+	clock     utils.Clock
+	limiter   utils.RateLimiter
+	log       *log.Entry
+
+	lock       sync.RWMutex
+	state      TxPoolState
+	nodes      map[string]NodeTxPoolStatus
+	droppedTxs uint64
+	forwarded  uint64
+}
+
+// NewTxPoolMonitor creates a monitor of the tx pools of the nodes managed by the ws manager
+func NewTxPoolMonitor(cfg TxPoolMonitorConfig, wsManager WSManager, clock utils.Clock) *TxPoolMonitor {
+	var limiter utils.RateLimiter
+	if cfg.ThrottledTxsPerSecond > 0 {
+		limiter = utils.NewLeakyBucketRateLimiter(clock, uint64(cfg.ThrottledTxsPerSecond), time.Second)
+	}
+	return &TxPoolMonitor{
+		cfg:       cfg,
+		wsManager: wsManager,
+		clock:     clock,
+		limiter:   limiter,
+		log:       log.WithField("component", "txPoolMonitor"),
+		state:     TxPoolNormal,
+		nodes:     make(map[string]NodeTxPoolStatus),
+	}
+}
+
+// Enabled returns true if the capacity of the node tx pool is configured
+func (m *TxPoolMonitor) Enabled() bool {
+	return m.cfg.Capacity > 0 && m.wsManager != nil
+}
+
+// Run queries the tx pools of the nodes until the context is done
+func (m *TxPoolMonitor) Run(ctx context.Context) {
+	if !m.Enabled() {
+		return
+	}
+
+	m.poll()
+	ticker := m.clock.Ticker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			m.poll()
+		}
+	}
+}
+
+// AllowTx returns true if a transaction from the BDN can be forwarded to the nodes. Transactions which are not
+// allowed are dropped, they are not queued until the tx pool drains.
+func (m *TxPoolMonitor) AllowTx() bool {
+	state := m.State()
+
+	allowed := state == TxPoolNormal
+	if state == TxPoolThrottled && m.limiter != nil {
+		allowed, _ = m.limiter.Take()
+	}
+
+	m.lock.Lock()
+	if allowed {
+		m.forwarded++
+	} else {
+		m.droppedTxs++
+	}
+	m.lock.Unlock()
+
+	return allowed
+}
+
+// State returns the current forwarding state
+func (m *TxPoolMonitor) State() TxPoolState {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.state
+}
+
+// Stats returns the number of transactions forwarded and dropped since start
+func (m *TxPoolMonitor) Stats() (forwarded uint64, dropped uint64) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.forwarded, m.droppedTxs
+}
+
+// Status returns the last known tx pool usage of every node
+func (m *TxPoolMonitor) Status() []NodeTxPoolStatus {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	status := make([]NodeTxPoolStatus, 0, len(m.nodes))
+	for _, nodeStatus := range m.nodes {
+		status = append(status, nodeStatus)
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Endpoint < status[j].Endpoint })
+	return status
+}
+
+func (m *TxPoolMonitor) poll() {
+	nodes := make(map[string]NodeTxPoolStatus)
+	for endpoint, provider := range m.wsManager.Providers() {
+		if !provider.IsOpen() {
+			continue
+		}
+
+		nodeStatus := NodeTxPoolStatus{Endpoint: endpoint, State: TxPoolNormal, UpdatedAt: m.clock.Now()}
+		pending, queued, err := fetchTxPoolStatus(provider)
+		if err != nil {
+			m.log.Debugf("failed to fetch txpool_status from %v: %v", endpoint, err)
+			nodeStatus.Error = err.Error()
+		} else {
+			nodeStatus.Pending = pending
+			nodeStatus.Queued = queued
+			nodeStatus.State = m.stateForUsage(pending + queued)
+		}
+		nodes[endpoint] = nodeStatus
+	}
+
+	m.update(nodes)
+}
+
+func (m *TxPoolMonitor) update(nodes map[string]NodeTxPoolStatus) {
+	state := TxPoolNormal
+	for _, nodeStatus := range nodes {
+		if nodeStatus.State == TxPoolPaused || (nodeStatus.State == TxPoolThrottled && state == TxPoolNormal) {
+			state = nodeStatus.State
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if state != m.state {
+		m.log.Infof("tx forwarding to nodes changed from %v to %v, forwarded %v txs, dropped %v txs", m.state, state, m.forwarded, m.droppedTxs)
+	}
+	m.state = state
+	m.nodes = nodes
+}
+
+func (m *TxPoolMonitor) stateForUsage(size uint64) TxPoolState {
+	usage := float64(size) / float64(m.cfg.Capacity)
+	switch {
+	case usage >= m.cfg.PauseThreshold:
+		return TxPoolPaused
+	case usage >= m.cfg.ThrottleThreshold:
+		return TxPoolThrottled
+	default:
+		return TxPoolNormal
+	}
+}
+
+func fetchTxPoolStatus(provider WSProvider) (pending uint64, queued uint64, err error) {
+	response, err := provider.CallRPC("txpool_status", []interface{}{}, RPCOptions{RetryAttempts: 1})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	result, ok := response.(map[string]interface{})
+	if !ok {
+		return 0, 0, fmt.Errorf("unexpected txpool_status response %v", response)
+	}
+
+	if pending, err = decodeTxPoolCount(result, "pending"); err != nil {
+		return 0, 0, err
+	}
+	if queued, err = decodeTxPoolCount(result, "queued"); err != nil {
+		return 0, 0, err
+	}
+	return pending, queued, nil
+}
+
+func decodeTxPoolCount(result map[string]interface{}, field string) (uint64, error) {
+	value, ok := result[field].(string)
+	if !ok {
+		return 0, fmt.Errorf("txpool_status response is missing %v", field)
+	}
+	return hexutil.DecodeUint64(value)
+}
@@ -0,0 +1,52 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxPoolMonitor(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	monitor := NewTxPoolMonitor(TxPoolMonitorConfig{
+		Capacity:              1000,
+		ThrottleThreshold:     0.8,
+		PauseThreshold:        0.95,
+		ThrottledTxsPerSecond: 2,
+		Interval:              time.Second,
+	}, nil, &clock)
+
+	assert.Equal(t, TxPoolNormal, monitor.stateForUsage(100))
+	assert.Equal(t, TxPoolThrottled, monitor.stateForUsage(800))
+	assert.Equal(t, TxPoolPaused, monitor.stateForUsage(990))
+
+	// the most loaded node determines the state
+	monitor.update(map[string]NodeTxPoolStatus{
+		"node1": {Endpoint: "node1", Pending: 100, State: TxPoolNormal},
+		"node2": {Endpoint: "node2", Pending: 850, State: TxPoolThrottled},
+	})
+	assert.Equal(t, TxPoolThrottled, monitor.State())
+	assert.True(t, monitor.AllowTx())
+	assert.True(t, monitor.AllowTx())
+	assert.False(t, monitor.AllowTx())
+
+	monitor.update(map[string]NodeTxPoolStatus{
+		"node1": {Endpoint: "node1", Pending: 990, State: TxPoolPaused},
+		"node2": {Endpoint: "node2", Pending: 850, State: TxPoolThrottled},
+	})
+	assert.False(t, monitor.AllowTx())
+
+	// forwarding resumes once the pool drains
+	monitor.update(map[string]NodeTxPoolStatus{
+		"node1": {Endpoint: "node1", Pending: 10, State: TxPoolNormal},
+	})
+	assert.True(t, monitor.AllowTx())
+
+	forwarded, dropped := monitor.Stats()
+	assert.Equal(t, uint64(3), forwarded)
+	assert.Equal(t, uint64(2), dropped)
+	assert.Len(t, monitor.Status(), 1)
+}
@@ -0,0 +1,63 @@
+package blockchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// TxPoolReconciliation summarizes the divergence between the node tx pool and the recent transactions seen by the gateway
+type TxPoolReconciliation struct {
+	Endpoint         string    `json:"endpoint"`
+	Time             time.Time `json:"time"`
+	NodeTxs          int       `json:"node_txs"`
+	GatewayTxs       int       `json:"gateway_txs"`
+	MissingInNode    int       `json:"missing_in_node"`
+	MissingInGateway int       `json:"missing_in_gateway"`
+	Forwarded        int       `json:"forwarded"`
+}
+
+// FetchTxPoolContentHashes returns the hashes of the pending and queued transactions in the node tx pool
+func FetchTxPoolContentHashes(provider WSProvider) (map[types.SHA256Hash]struct{}, error) {
+	response, err := provider.CallRPC("txpool_content", []interface{}{}, RPCOptions{RetryAttempts: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected txpool_content response type %T", response)
+	}
+
+	hashes := make(map[types.SHA256Hash]struct{})
+	for _, pool := range []string{"pending", "queued"} {
+		// txpool_content returns the transactions by sender and nonce
+		senders, ok := result[pool].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, nonces := range senders {
+			txsByNonce, ok := nonces.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, rawTx := range txsByNonce {
+				tx, ok := rawTx.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				hashStr, ok := tx["hash"].(string)
+				if !ok {
+					continue
+				}
+				hash, err := types.NewSHA256HashFromString(hashStr)
+				if err != nil {
+					return nil, fmt.Errorf("invalid tx hash %v in txpool_content: %v", hashStr, err)
+				}
+				hashes[hash] = struct{}{}
+			}
+		}
+	}
+	return hashes, nil
+}
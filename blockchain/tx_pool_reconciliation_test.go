@@ -0,0 +1,45 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type txPoolContentProvider struct {
+	WSProvider
+	response interface{}
+}
+
+func (p txPoolContentProvider) CallRPC(string, []interface{}, RPCOptions) (interface{}, error) {
+	return p.response, nil
+}
+
+func TestFetchTxPoolContentHashes(t *testing.T) {
+	pendingHash := types.GenerateSHA256Hash()
+	queuedHash := types.GenerateSHA256Hash()
+
+	provider := txPoolContentProvider{response: map[string]interface{}{
+		"pending": map[string]interface{}{
+			"0x1": map[string]interface{}{
+				"1": map[string]interface{}{"hash": "0x" + pendingHash.String()},
+			},
+		},
+		"queued": map[string]interface{}{
+			"0x2": map[string]interface{}{
+				"7": map[string]interface{}{"hash": "0x" + queuedHash.String()},
+			},
+		},
+	}}
+
+	hashes, err := FetchTxPoolContentHashes(provider)
+	require.NoError(t, err)
+	assert.Len(t, hashes, 2)
+	assert.Contains(t, hashes, pendingHash)
+	assert.Contains(t, hashes, queuedHash)
+
+	_, err = FetchTxPoolContentHashes(txPoolContentProvider{response: "unexpected"})
+	assert.Error(t, err)
+}
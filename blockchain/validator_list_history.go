@@ -0,0 +1,74 @@
+package blockchain
+
+import (
+	"sort"
+	"sync"
+)
+
+// ValidatorListHistory keeps the most recent validator lists received over the bridge, indexed by block height
+type ValidatorListHistory struct {
+	lock    sync.RWMutex
+	size    int
+	heights []uint64
+	lists   map[uint64][]string
+}
+
+// NewValidatorListHistory creates a history that keeps at most size validator lists
+func NewValidatorListHistory(size int) *ValidatorListHistory {
+	return &ValidatorListHistory{
+		size:  size,
+		lists: make(map[uint64][]string),
+	}
+}
+
+// Store adds the validator list of the block height, evicting the oldest list if the history is full
+func (h *ValidatorListHistory) Store(blockHeight uint64, validatorList []string) {
+	if h == nil {
+		return
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.lists[blockHeight]; !ok {
+		i := sort.Search(len(h.heights), func(i int) bool { return h.heights[i] > blockHeight })
+		h.heights = append(h.heights, 0)
+		copy(h.heights[i+1:], h.heights[i:])
+		h.heights[i] = blockHeight
+	}
+	h.lists[blockHeight] = validatorList
+
+	for len(h.heights) > h.size {
+		delete(h.lists, h.heights[0])
+		h.heights = h.heights[1:]
+	}
+}
+
+// Load returns the validator list received for exactly the block height
+func (h *ValidatorListHistory) Load(blockHeight uint64) ([]string, bool) {
+	if h == nil {
+		return nil, false
+	}
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	validatorList, ok := h.lists[blockHeight]
+	return validatorList, ok
+}
+
+// Lookup returns the validator list that applied to the block height, which is the most recent list received
+// at or before it
+func (h *ValidatorListHistory) Lookup(blockHeight uint64) (*ValidatorListInfo, bool) {
+	if h == nil {
+		return nil, false
+	}
+
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	i := sort.Search(len(h.heights), func(i int) bool { return h.heights[i] > blockHeight })
+	if i == 0 {
+		return nil, false
+	}
+	height := h.heights[i-1]
+	return &ValidatorListInfo{BlockHeight: height, ValidatorList: h.lists[height]}, true
+}
@@ -0,0 +1,41 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorListHistory(t *testing.T) {
+	history := NewValidatorListHistory(2)
+
+	_, ok := history.Lookup(100)
+	assert.False(t, ok)
+
+	history.Store(400, []string{"c"})
+	history.Store(200, []string{"a", "b"})
+
+	info, ok := history.Lookup(399)
+	require.True(t, ok)
+	assert.Equal(t, uint64(200), info.BlockHeight)
+	assert.Equal(t, []string{"a", "b"}, info.ValidatorList)
+
+	info, ok = history.Lookup(450)
+	require.True(t, ok)
+	assert.Equal(t, uint64(400), info.BlockHeight)
+
+	_, ok = history.Lookup(199)
+	assert.False(t, ok)
+
+	// the oldest list is evicted when the history is full
+	history.Store(600, []string{"d"})
+	_, ok = history.Load(200)
+	assert.False(t, ok)
+	_, ok = history.Lookup(399)
+	assert.False(t, ok)
+
+	validatorList, ok := history.Load(600)
+	require.True(t, ok)
+	assert.Equal(t, []string{"d"}, validatorList)
+}
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"context"
 	"time"
 
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
@@ -20,6 +21,29 @@ const (
 type RPCOptions struct {
 	RetryAttempts int
 	RetryInterval time.Duration
+	// Context cancels the call and its retries, the call is never canceled if nil
+	Context context.Context
+}
+
+// Ctx returns the context of the call
+func (o RPCOptions) Ctx() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
+}
+
+// Sleep waits for the retry interval, it returns the error of the context if it is canceled before
+func (o RPCOptions) Sleep() error {
+	ctx := o.Ctx()
+	timer := time.NewTimer(o.RetryInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // DefaultRPCOptions - provides default options for CallRPC
@@ -47,6 +71,7 @@ type WSProvider interface {
 	FetchTransaction(payload []interface{}, options RPCOptions) (interface{}, error)
 	FetchBlock(payload []interface{}, options RPCOptions) (interface{}, error)
 	FetchTransactionReceipt(payload []interface{}, options RPCOptions) (interface{}, error)
+	FetchTransactionReceipts(hashes []interface{}, options RPCOptions) ([]interface{}, error)
 	SendTransaction(rawTx string, options RPCOptions) (interface{}, error)
 	Log() *log.Entry
 }
@@ -0,0 +1,184 @@
+package bxclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	bxrpc "github.com/bloXroute-Labs/gateway/v2/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCOptions are the options of a GRPCClient
+type GRPCOptions struct {
+	// AuthHeader is the Authorization header of the account, sent with each call
+	AuthHeader string
+	// TLS connects over TLS, the auth header is otherwise sent in clear text
+	TLS bool
+	// ReconnectInterval is the interval between the attempts to resubscribe to a stream, DefaultReconnectInterval if 0
+	ReconnectInterval time.Duration
+	// ChannelSize is the buffer of the channels of the streams, DefaultChannelSize if 0
+	ChannelSize int
+}
+
+// GRPCClient is a client of the gRPC API of a gateway, the streams are resubscribed when they end
+type GRPCClient struct {
+	conn    *grpc.ClientConn
+	client  pb.GatewayClient
+	options GRPCOptions
+	log     *log.Entry
+}
+
+// DialGRPC connects to the gRPC API of the gateway at address, e.g. 127.0.0.1:5001
+func DialGRPC(ctx context.Context, address string, options GRPCOptions) (*GRPCClient, error) {
+	if options.ReconnectInterval <= 0 {
+		options.ReconnectInterval = DefaultReconnectInterval
+	}
+	if options.ChannelSize <= 0 {
+		options.ChannelSize = DefaultChannelSize
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithInsecure()}
+	if options.TLS {
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}))}
+	}
+	if options.AuthHeader != "" {
+		dialOptions = append(dialOptions, bxrpc.NewBLXRCredentials(options.AuthHeader))
+	}
+	conn, err := grpc.DialContext(ctx, address, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to gateway gRPC %v: %v", address, err)
+	}
+	return &GRPCClient{
+		conn:    conn,
+		client:  pb.NewGatewayClient(conn),
+		options: options,
+		log:     log.WithField("component", "bxclient"),
+	}, nil
+}
+
+// Close closes the connection, ending the streams
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// Gateway returns the generated client of the gRPC API, for the methods without a helper
+func (c *GRPCClient) Gateway() pb.GatewayClient {
+	return c.client
+}
+
+// SendTx sends the tx of the request with BlxrTx, returning its hash
+func (c *GRPCClient) SendTx(ctx context.Context, request *pb.BlxrTxRequest) (string, error) {
+	reply, err := c.client.BlxrTx(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return reply.GetTxHash(), nil
+}
+
+// SendRawTx sends the hex encoded signed tx with BlxrTx, returning its hash
+func (c *GRPCClient) SendRawTx(ctx context.Context, rawTx string) (string, error) {
+	return c.SendTx(ctx, &pb.BlxrTxRequest{Transaction: rawTx})
+}
+
+// NewTxs streams the txs of the newTxs feed to the returned channel until the context is done, resubscribing when
+// the stream ends. The txs the program does not keep up with are dropped.
+func (c *GRPCClient) NewTxs(ctx context.Context, request *pb.TxsRequest) <-chan *pb.Tx {
+	return c.streamTxs(ctx, "newTxs", func(ctx context.Context) (txsStream, error) {
+		return c.client.NewTxs(ctx, request)
+	})
+}
+
+// PendingTxs streams the txs of the pendingTxs feed to the returned channel until the context is done, resubscribing
+// when the stream ends. The txs the program does not keep up with are dropped.
+func (c *GRPCClient) PendingTxs(ctx context.Context, request *pb.TxsRequest) <-chan *pb.Tx {
+	return c.streamTxs(ctx, "pendingTxs", func(ctx context.Context) (txsStream, error) {
+		return c.client.PendingTxs(ctx, request)
+	})
+}
+
+// NewBlocks streams the blocks of the newBlocks feed to the returned channel until the context is done, resubscribing
+// when the stream ends. The blocks the program does not keep up with are dropped.
+func (c *GRPCClient) NewBlocks(ctx context.Context, request *pb.BlocksRequest) <-chan *pb.BlocksReply {
+	blocks := make(chan *pb.BlocksReply, c.options.ChannelSize)
+	go func() {
+		defer close(blocks)
+		c.resubscribe(ctx, "newBlocks", func() error {
+			stream, err := c.client.NewBlocks(ctx, request)
+			if err != nil {
+				return err
+			}
+			for {
+				reply, err := stream.Recv()
+				if err != nil {
+					return err
+				}
+				select {
+				case blocks <- reply:
+				default:
+				}
+			}
+		})
+	}()
+	return blocks
+}
+
+// txsStream is a stream of the tx feeds
+type txsStream interface {
+	Recv() (*pb.TxsReply, error)
+}
+
+func (c *GRPCClient) streamTxs(ctx context.Context, feed string, subscribe func(ctx context.Context) (txsStream, error)) <-chan *pb.Tx {
+	txs := make(chan *pb.Tx, c.options.ChannelSize)
+	go func() {
+		defer close(txs)
+		c.resubscribe(ctx, feed, func() error {
+			stream, err := subscribe(ctx)
+			if err != nil {
+				return err
+			}
+			for {
+				reply, err := stream.Recv()
+				if err != nil {
+					return err
+				}
+				for _, tx := range reply.GetTx() {
+					select {
+					case txs <- tx:
+					default:
+					}
+				}
+			}
+		})
+	}()
+	return txs
+}
+
+// resubscribe serves the stream until the context is done, subscribing again after the reconnect interval when it
+// ends. The streams the gateway rejects are not resubscribed.
+func (c *GRPCClient) resubscribe(ctx context.Context, feed string, serve func() error) {
+	for {
+		err := serve()
+		if ctx.Err() != nil {
+			return
+		}
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated, codes.Unimplemented:
+			c.log.Errorf("%v stream of gateway was rejected: %v", feed, err)
+			return
+		}
+		c.log.Warnf("%v stream of gateway ended, resubscribing in %v: %v", feed, c.options.ReconnectInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.options.ReconnectInterval):
+		}
+	}
+}
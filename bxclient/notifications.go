@@ -0,0 +1,45 @@
+package bxclient
+
+import (
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxNotification is a notification of the newTxs and pendingTxs feeds, the fields are set if the subscription
+// includes them
+type TxNotification struct {
+	TxHash      string                 `json:"txHash,omitempty"`
+	TxContents  map[string]interface{} `json:"txContents,omitempty"`
+	LocalRegion *bool                  `json:"localRegion,omitempty"`
+	Time        string                 `json:"time,omitempty"`
+	RawTx       string                 `json:"rawTx,omitempty"`
+	TimeSkewMs  *float64               `json:"time_skew_ms,omitempty"`
+	SeenOn      []types.FeedType       `json:"seen_on,omitempty"`
+	Origin      string                 `json:"origin,omitempty"`
+	Enrichment  map[string]interface{} `json:"enrichment,omitempty"`
+}
+
+// Transaction decodes the raw tx of the notification, the subscription must include raw_tx
+func (n TxNotification) Transaction() (*ethtypes.Transaction, error) {
+	if n.RawTx == "" {
+		return nil, fmt.Errorf("notification of tx %v has no raw_tx", n.TxHash)
+	}
+	b, err := hexutil.Decode(n.RawTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw_tx of tx %v: %v", n.TxHash, err)
+	}
+	var tx ethtypes.Transaction
+	if err = tx.UnmarshalBinary(b); err != nil {
+		return nil, fmt.Errorf("invalid raw_tx of tx %v: %v", n.TxHash, err)
+	}
+	return &tx, nil
+}
+
+// BlockNotification is a notification of the newBlocks and bdnBlocks feeds
+type BlockNotification = types.EthBlockNotification
+
+// TxReceiptNotification is a notification of the txReceipts feed
+type TxReceiptNotification = types.TxReceipt
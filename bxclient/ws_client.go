@@ -0,0 +1,526 @@
+package bxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	websocketjsonrpc2 "github.com/sourcegraph/jsonrpc2/websocket"
+)
+
+// DefaultReconnectInterval is the interval between the attempts to reconnect to the gateway
+const DefaultReconnectInterval = 5 * time.Second
+
+// DefaultChannelSize is the buffer of the channels of the subscriptions
+const DefaultChannelSize = 1000
+
+// ErrClosed is returned by the calls of a closed client
+var ErrClosed = errors.New("client is closed")
+
+// WSOptions are the options of a WSClient
+type WSOptions struct {
+	// AuthHeader is the Authorization header of the account, e.g. the base64 encoding of account_id:secret_hash
+	AuthHeader string
+	// ReconnectInterval is the interval between the attempts to reconnect, DefaultReconnectInterval if 0
+	ReconnectInterval time.Duration
+	// ChannelSize is the buffer of the channels of the subscriptions, DefaultChannelSize if 0
+	ChannelSize int
+}
+
+// WSClient is a client of the websocket API of a gateway. The connection is re-established when it is lost, the
+// subscriptions are then transferred to the new connection or subscribed again, so their channels keep delivering the
+// notifications. A reconnect notification of a draining gateway moves the client to the address it names. All methods
+// are safe for concurrent use.
+type WSClient struct {
+	url     string
+	options WSOptions
+	log     *log.Entry
+
+	lock          sync.Mutex
+	conn          *jsonrpc2.Conn
+	connected     chan struct{}
+	subscriptions map[string]*subscription
+	// early holds the notifications received before the reply of their subscribe request was handled
+	early    map[string][]json.RawMessage
+	inflight int
+	// transfers are the transfer tokens of the subscriptions sent by a draining gateway, which asks the client to
+	// reconnect right away
+	transfers map[string]string
+	draining  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// DialWS connects to the websocket API of the gateway at url, e.g. ws://127.0.0.1:28333/ws, and keeps the connection
+// up until Close is called or the context is done
+func DialWS(ctx context.Context, url string, options WSOptions) (*WSClient, error) {
+	if options.ReconnectInterval <= 0 {
+		options.ReconnectInterval = DefaultReconnectInterval
+	}
+	if options.ChannelSize <= 0 {
+		options.ChannelSize = DefaultChannelSize
+	}
+
+	clientCtx, cancel := context.WithCancel(ctx)
+	c := &WSClient{
+		url:           url,
+		options:       options,
+		log:           log.WithField("component", "bxclient"),
+		connected:     make(chan struct{}),
+		subscriptions: make(map[string]*subscription),
+		early:         make(map[string][]json.RawMessage),
+		transfers:     make(map[string]string),
+		ctx:           clientCtx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+	conn, err := c.dial(url)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	c.setConn(conn)
+	go c.run(conn)
+	return c, nil
+}
+
+// Close unsubscribes the subscriptions and closes the connection
+func (c *WSClient) Close() error {
+	c.lock.Lock()
+	subscriptions := make([]*subscription, 0, len(c.subscriptions))
+	for _, sub := range c.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	c.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(c.ctx, time.Second)
+	defer cancel()
+	for _, sub := range subscriptions {
+		_ = c.unsubscribe(ctx, sub)
+	}
+
+	c.cancel()
+	<-c.done
+	return nil
+}
+
+// Call sends a request of the method to the gateway and decodes its result into result, waiting for the connection
+// to be re-established if it is lost
+func (c *WSClient) Call(ctx context.Context, method jsonrpc.RPCRequestType, params, result interface{}) error {
+	conn, err := c.waitConn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Call(ctx, string(method), params, result)
+}
+
+// Ping returns the time of the gateway
+func (c *WSClient) Ping(ctx context.Context) (string, error) {
+	var result struct {
+		Pong string `json:"pong"`
+	}
+	if err := c.Call(ctx, jsonrpc.RPCPing, nil, &result); err != nil {
+		return "", err
+	}
+	return result.Pong, nil
+}
+
+// SendTx sends the tx of the payload with blxr_tx, returning its hash
+func (c *WSClient) SendTx(ctx context.Context, payload jsonrpc.RPCTxPayload) (string, error) {
+	var result struct {
+		TxHash string `json:"txHash"`
+	}
+	if err := c.Call(ctx, jsonrpc.RPCTx, payload, &result); err != nil {
+		return "", err
+	}
+	return result.TxHash, nil
+}
+
+// SendRawTx sends the hex encoded signed tx with blxr_tx, returning its hash
+func (c *WSClient) SendRawTx(ctx context.Context, rawTx string) (string, error) {
+	return c.SendTx(ctx, jsonrpc.RPCTxPayload{Transaction: rawTx})
+}
+
+// SendBatchTx sends the hex encoded signed txs of the payload with blxr_batch_tx, returning their hashes
+func (c *WSClient) SendBatchTx(ctx context.Context, payload jsonrpc.RPCBatchTxPayload) ([]string, error) {
+	var result struct {
+		TxHashes []string `json:"txHashes"`
+	}
+	if err := c.Call(ctx, jsonrpc.RPCBatchTx, payload, &result); err != nil {
+		return nil, err
+	}
+	return result.TxHashes, nil
+}
+
+func (c *WSClient) dial(url string) (*jsonrpc2.Conn, error) {
+	headers := make(http.Header)
+	if c.options.AuthHeader != "" {
+		headers.Set("Authorization", c.options.AuthHeader)
+	}
+	ws, _, err := websocket.DefaultDialer.DialContext(c.ctx, url, headers)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to gateway websocket %v: %v", url, err)
+	}
+	// the notifications are handled in order, a subscription which is not read drops its notifications
+	return jsonrpc2.NewConn(c.ctx, websocketjsonrpc2.NewObjectStream(ws), jsonrpc2.HandlerWithError(c.handle)), nil
+}
+
+// run re-establishes the connection each time it is lost, until the client is closed
+func (c *WSClient) run(conn *jsonrpc2.Conn) {
+	defer close(c.done)
+	for {
+		select {
+		case <-c.ctx.Done():
+			_ = conn.Close()
+			c.closeSubscriptions()
+			return
+		case <-conn.DisconnectNotify():
+		}
+
+		c.lock.Lock()
+		c.connected = make(chan struct{})
+		url := c.url
+		delay := c.options.ReconnectInterval
+		if c.draining {
+			delay = 0
+		}
+		c.draining = false
+		c.lock.Unlock()
+		c.log.Warnf("connection to gateway websocket %v was lost, reconnecting", url)
+
+		for {
+			select {
+			case <-c.ctx.Done():
+				c.closeSubscriptions()
+				return
+			case <-time.After(delay):
+			}
+			var err error
+			if conn, err = c.dial(url); err == nil {
+				break
+			}
+			c.log.Warnf("%v, retrying in %v", err, c.options.ReconnectInterval)
+			delay = c.options.ReconnectInterval
+		}
+		c.resubscribe(conn)
+		c.setConn(conn)
+		c.log.Infof("reconnected to gateway websocket %v", url)
+	}
+}
+
+func (c *WSClient) setConn(conn *jsonrpc2.Conn) {
+	c.lock.Lock()
+	c.conn = conn
+	close(c.connected)
+	c.lock.Unlock()
+}
+
+func (c *WSClient) waitConn(ctx context.Context) (*jsonrpc2.Conn, error) {
+	if c.ctx.Err() != nil {
+		return nil, ErrClosed
+	}
+	c.lock.Lock()
+	connected := c.connected
+	c.lock.Unlock()
+
+	select {
+	case <-c.ctx.Done():
+		return nil, ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-connected:
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.conn, nil
+}
+
+// closeSubscriptions closes the channels of the subscriptions once the client is closed
+func (c *WSClient) closeSubscriptions() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, sub := range c.subscriptions {
+		sub.close()
+		delete(c.subscriptions, id)
+	}
+}
+
+// resubscribe moves the subscriptions to the new connection, adopting them with the transfer tokens of a reconnect
+// notification and subscribing again otherwise
+func (c *WSClient) resubscribe(conn *jsonrpc2.Conn) {
+	c.lock.Lock()
+	subscriptions := c.subscriptions
+	transfers := c.transfers
+	c.subscriptions = make(map[string]*subscription)
+	c.transfers = make(map[string]string)
+	c.inflight++
+	c.lock.Unlock()
+
+	defer c.endSubscribe()
+	for oldID, sub := range subscriptions {
+		var id string
+		err := errors.New("no transfer token")
+		if token, ok := transfers[oldID]; ok {
+			err = conn.Call(c.ctx, string(jsonrpc.RPCSubscriptionTransfer), []string{token}, &id)
+		}
+		if err != nil {
+			err = conn.Call(c.ctx, string(jsonrpc.RPCSubscribe), sub.params(), &id)
+		}
+		if err != nil {
+			c.log.Errorf("failed to resubscribe to %v: %v", sub.feed, err)
+			c.lock.Lock()
+			sub.close()
+			c.lock.Unlock()
+			continue
+		}
+		if !c.register(id, sub) {
+			var result interface{}
+			_ = conn.Call(c.ctx, string(jsonrpc.RPCUnsubscribe), []string{id}, &result)
+		}
+	}
+}
+
+// subscribe sends the subscribe request of the subscription and registers it under the returned ID
+func (c *WSClient) subscribe(ctx context.Context, sub *subscription) error {
+	conn, err := c.waitConn(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.inflight++
+	c.lock.Unlock()
+	defer c.endSubscribe()
+
+	var id string
+	if err = conn.Call(ctx, string(jsonrpc.RPCSubscribe), sub.params(), &id); err != nil {
+		return err
+	}
+	c.register(id, sub)
+	return nil
+}
+
+// register delivers the notifications of the subscription ID to the subscription, returning false if the
+// subscription was ended while it was moved to a new connection
+func (c *WSClient) register(id string, sub *subscription) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if sub.ended {
+		return false
+	}
+	sub.id = id
+	c.subscriptions[id] = sub
+	for _, result := range c.early[id] {
+		sub.deliver(result)
+	}
+	delete(c.early, id)
+	return true
+}
+
+func (c *WSClient) endSubscribe() {
+	c.lock.Lock()
+	c.inflight--
+	if c.inflight == 0 {
+		c.early = make(map[string][]json.RawMessage)
+	}
+	c.lock.Unlock()
+}
+
+func (c *WSClient) unsubscribe(ctx context.Context, sub *subscription) error {
+	c.lock.Lock()
+	id := sub.id
+	_, ok := c.subscriptions[id]
+	delete(c.subscriptions, id)
+	sub.ended = true
+	sub.close()
+	conn := c.conn
+	c.lock.Unlock()
+	if !ok {
+		return nil
+	}
+
+	var result interface{}
+	return conn.Call(ctx, string(jsonrpc.RPCUnsubscribe), []string{id}, &result)
+}
+
+// subscriptionNotification is the notification of a subscription
+type subscriptionNotification struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// reconnectNotification is the reconnect notification of a draining gateway
+type reconnectNotification struct {
+	ReconnectTo   string            `json:"reconnect_to"`
+	Subscriptions map[string]string `json:"subscriptions,omitempty"`
+}
+
+func (c *WSClient) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	if !req.Notif || req.Params == nil {
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: fmt.Sprintf("method %v is not supported", req.Method)}
+	}
+
+	switch req.Method {
+	case string(jsonrpc.RPCSubscribe):
+		var notification subscriptionNotification
+		if err := json.Unmarshal(*req.Params, &notification); err != nil {
+			c.log.Debugf("failed to decode notification: %v", err)
+			return nil, nil
+		}
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if sub, ok := c.subscriptions[notification.Subscription]; ok {
+			sub.deliver(notification.Result)
+		} else if c.inflight > 0 && len(c.early[notification.Subscription]) < c.options.ChannelSize {
+			c.early[notification.Subscription] = append(c.early[notification.Subscription], notification.Result)
+		}
+	case "reconnect":
+		var notification reconnectNotification
+		if err := json.Unmarshal(*req.Params, &notification); err != nil {
+			c.log.Debugf("failed to decode reconnect notification: %v", err)
+			return nil, nil
+		}
+		c.log.Infof("gateway is draining the connection, reconnecting to %v", notification.ReconnectTo)
+		c.lock.Lock()
+		if notification.ReconnectTo != "" {
+			c.url = notification.ReconnectTo
+		}
+		c.transfers = notification.Subscriptions
+		if c.transfers == nil {
+			c.transfers = make(map[string]string)
+		}
+		c.draining = true
+		c.lock.Unlock()
+		go func() { _ = conn.Close() }()
+	}
+	return nil, nil
+}
+
+// subscription is a subscription of the client, kept across the connections. Its fields are guarded by the lock of
+// the client, which deliver and close are called with.
+type subscription struct {
+	feed    types.FeedType
+	options SubscribeOptions
+	id      string
+	ended   bool
+	deliver func(result json.RawMessage)
+	close   func()
+}
+
+func (sub *subscription) params() []interface{} {
+	return []interface{}{sub.feed, sub.options}
+}
+
+// SubscribeOptions are the options of a subscription, as documented for the subscribe method of the gateway
+type SubscribeOptions struct {
+	Include         []string          `json:"Include"`
+	Filters         string            `json:"Filters,omitempty"`
+	DedupWindowMs   int64             `json:"DedupWindowMs,omitempty"`
+	WasmFilter      string            `json:"WasmFilter,omitempty"`
+	Transform       map[string]string `json:"Transform,omitempty"`
+	Backpressure    string            `json:"Backpressure,omitempty"`
+	ABI             json.RawMessage   `json:"ABI,omitempty"`
+	HeaderFirst     bool              `json:"HeaderFirst,omitempty"`
+	TxHashes        []string          `json:"TxHashes,omitempty"`
+	AutoTrack       bool              `json:"AutoTrack,omitempty"`
+	StrictOrdering  bool              `json:"StrictOrdering,omitempty"`
+	OnBlockPageSize int               `json:"OnBlockPageSize,omitempty"`
+}
+
+// Subscription delivers the notifications of a feed decoded as T
+type Subscription[T any] struct {
+	client        *WSClient
+	sub           *subscription
+	notifications chan T
+	dropped       uint64
+	closed        bool
+}
+
+// Subscribe subscribes to the feed, decoding its notifications as T. The notifications the program does not keep up
+// with are dropped, the channel is closed once Unsubscribe is called, the client is closed or the subscription cannot
+// be restored after a reconnection.
+func Subscribe[T any](ctx context.Context, c *WSClient, feed types.FeedType, options SubscribeOptions) (*Subscription[T], error) {
+	if options.Include == nil {
+		options.Include = []string{}
+	}
+	s := &Subscription[T]{client: c, notifications: make(chan T, c.options.ChannelSize)}
+	s.sub = &subscription{
+		feed:    feed,
+		options: options,
+		deliver: func(result json.RawMessage) {
+			var notification T
+			if err := json.Unmarshal(result, &notification); err != nil {
+				c.log.Debugf("failed to decode %v notification: %v", feed, err)
+				return
+			}
+			select {
+			case s.notifications <- notification:
+			default:
+				s.dropped++
+			}
+		},
+		close: func() {
+			if !s.closed {
+				s.closed = true
+				close(s.notifications)
+			}
+		},
+	}
+	if err := c.subscribe(ctx, s.sub); err != nil {
+		return nil, fmt.Errorf("could not subscribe to %v: %w", feed, err)
+	}
+	return s, nil
+}
+
+// Notifications returns the channel of the notifications
+func (s *Subscription[T]) Notifications() <-chan T {
+	return s.notifications
+}
+
+// Dropped returns the number of notifications dropped as the channel was full
+func (s *Subscription[T]) Dropped() uint64 {
+	s.client.lock.Lock()
+	defer s.client.lock.Unlock()
+	return s.dropped
+}
+
+// Unsubscribe ends the subscription and closes its channel
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	return s.client.unsubscribe(ctx, s.sub)
+}
+
+// SubscribeNewTxs subscribes to the newTxs feed
+func (c *WSClient) SubscribeNewTxs(ctx context.Context, options SubscribeOptions) (*Subscription[TxNotification], error) {
+	return Subscribe[TxNotification](ctx, c, types.NewTxsFeed, options)
+}
+
+// SubscribePendingTxs subscribes to the pendingTxs feed
+func (c *WSClient) SubscribePendingTxs(ctx context.Context, options SubscribeOptions) (*Subscription[TxNotification], error) {
+	return Subscribe[TxNotification](ctx, c, types.PendingTxsFeed, options)
+}
+
+// SubscribeNewBlocks subscribes to the newBlocks feed
+func (c *WSClient) SubscribeNewBlocks(ctx context.Context, options SubscribeOptions) (*Subscription[BlockNotification], error) {
+	return Subscribe[BlockNotification](ctx, c, types.NewBlocksFeed, options)
+}
+
+// SubscribeBDNBlocks subscribes to the bdnBlocks feed
+func (c *WSClient) SubscribeBDNBlocks(ctx context.Context, options SubscribeOptions) (*Subscription[BlockNotification], error) {
+	return Subscribe[BlockNotification](ctx, c, types.BDNBlocksFeed, options)
+}
+
+// SubscribeTxReceipts subscribes to the txReceipts feed
+func (c *WSClient) SubscribeTxReceipts(ctx context.Context, options SubscribeOptions) (*Subscription[TxReceiptNotification], error) {
+	return Subscribe[TxReceiptNotification](ctx, c, types.TxReceiptsFeed, options)
+}
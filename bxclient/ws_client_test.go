@@ -0,0 +1,145 @@
+package bxclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	websocketjsonrpc2 "github.com/sourcegraph/jsonrpc2/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGateway answers the subscribe, subscription_transfer, unsubscribe and blxr_tx requests of the clients
+type mockGateway struct {
+	lock    sync.Mutex
+	conns   []*jsonrpc2.Conn
+	methods []string
+	ids     []string
+}
+
+func (g *mockGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := jsonrpc2.NewConn(r.Context(), websocketjsonrpc2.NewObjectStream(ws), jsonrpc2.HandlerWithError(g.handle))
+	g.lock.Lock()
+	g.conns = append(g.conns, conn)
+	g.lock.Unlock()
+	<-conn.DisconnectNotify()
+}
+
+func (g *mockGateway) handle(_ context.Context, _ *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.methods = append(g.methods, req.Method)
+	switch req.Method {
+	case "subscribe":
+		id := fmt.Sprintf("sub-%v", len(g.ids))
+		g.ids = append(g.ids, id)
+		return id, nil
+	case "subscription_transfer":
+		var params []string
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		id := "adopted-" + params[0]
+		g.ids = append(g.ids, id)
+		return id, nil
+	case "unsubscribe":
+		return true, nil
+	case "blxr_tx":
+		return map[string]string{"txHash": "abcd"}, nil
+	}
+	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: req.Method}
+}
+
+// conn returns the i-th connection and the last subscription ID once they exist
+func (g *mockGateway) conn(t *testing.T, i int, subscriptions int) (*jsonrpc2.Conn, string) {
+	var conn *jsonrpc2.Conn
+	var id string
+	require.Eventually(t, func() bool {
+		g.lock.Lock()
+		defer g.lock.Unlock()
+		if len(g.conns) <= i || len(g.ids) < subscriptions {
+			return false
+		}
+		conn, id = g.conns[i], g.ids[len(g.ids)-1]
+		return true
+	}, time.Second, time.Millisecond)
+	return conn, id
+}
+
+func (g *mockGateway) notify(t *testing.T, conn *jsonrpc2.Conn, id string, txHash string) {
+	require.NoError(t, conn.Notify(context.Background(), "subscribe", subscriptionNotification{
+		Subscription: id,
+		Result:       json.RawMessage(`{"txHash": "` + txHash + `"}`),
+	}))
+}
+
+func receiveTx(t *testing.T, sub *Subscription[TxNotification]) TxNotification {
+	select {
+	case notification := <-sub.Notifications():
+		return notification
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered")
+	}
+	return TxNotification{}
+}
+
+func TestWSClient(t *testing.T) {
+	gateway := &mockGateway{}
+	server := httptest.NewServer(gateway)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	ctx := context.Background()
+	client, err := DialWS(ctx, url, WSOptions{ReconnectInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	sub, err := client.SubscribeNewTxs(ctx, SubscribeOptions{Include: []string{"tx_hash"}})
+	require.NoError(t, err)
+	conn, id := gateway.conn(t, 0, 1)
+	gateway.notify(t, conn, id, "0x01")
+	assert.Equal(t, "0x01", receiveTx(t, sub).TxHash)
+
+	txHash, err := client.SendRawTx(ctx, "f86b")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", txHash)
+
+	// the subscription is subscribed again once the connection is re-established
+	require.NoError(t, conn.Close())
+	conn, id = gateway.conn(t, 1, 2)
+	assert.Equal(t, "sub-1", id)
+	gateway.notify(t, conn, id, "0x02")
+	assert.Equal(t, "0x02", receiveTx(t, sub).TxHash)
+
+	// a draining gateway hands off the subscription with a transfer token
+	require.NoError(t, conn.Notify(ctx, "reconnect", reconnectNotification{ReconnectTo: url, Subscriptions: map[string]string{id: "token"}}))
+	conn, id = gateway.conn(t, 2, 3)
+	assert.Equal(t, "adopted-token", id)
+	gateway.notify(t, conn, id, "0x03")
+	assert.Equal(t, "0x03", receiveTx(t, sub).TxHash)
+
+	require.NoError(t, sub.Unsubscribe(ctx))
+	_, ok := <-sub.Notifications()
+	assert.False(t, ok)
+	assert.Zero(t, sub.Dropped())
+
+	require.NoError(t, client.Close())
+	_, err = client.Ping(ctx)
+	assert.ErrorIs(t, err, ErrClosed)
+
+	gateway.lock.Lock()
+	defer gateway.lock.Unlock()
+	assert.Equal(t, []string{"subscribe", "blxr_tx", "subscribe", "subscription_transfer", "unsubscribe"}, gateway.methods)
+}
@@ -17,14 +17,22 @@ const (
 	broadcastTypeBeaconCapella   broadcastType = "bcnc"
 )
 
+// broadcast flags, packed in the byte of the encryption which older peers set to 0 or 1
+const (
+	broadcastFlagEncrypted uint8 = 1 << iota
+	broadcastFlagCompressed
+)
+
 // Broadcast - represent the "broadcast" message
 type Broadcast struct {
 	BroadcastHeader
 	broadcastType [BroadcastTypeLen]byte
 	encrypted     bool
 	block         []byte
-	sids          types.ShortIDList
-	beaconHash    types.SHA256Hash
+	// compressedBlock is the zstd compression of the block sent instead of it, nil if the block is sent uncompressed
+	compressedBlock []byte
+	sids            types.ShortIDList
+	beaconHash      types.SHA256Hash
 }
 
 func blockToBroadcastType(blockType types.BxBlockType) broadcastType {
@@ -121,9 +129,10 @@ func (b *Broadcast) SetEncrypted(encrypted bool) {
 	b.encrypted = encrypted
 }
 
-// SetBlock sets the block
+// SetBlock sets the block, which is sent uncompressed
 func (b *Broadcast) SetBlock(block []byte) {
 	b.block = block
+	b.compressedBlock = nil
 }
 
 // SetBeaconHash sets the beacon block hash
@@ -152,16 +161,20 @@ func (b *Broadcast) Pack(protocol Protocol) ([]byte, error) {
 	}
 	copy(buf[offset:], b.broadcastType[:])
 	offset += BroadcastTypeLen
+	var flags uint8
 	if b.encrypted {
-		copy(buf[offset:], []uint8{1})
-	} else {
-		copy(buf[offset:], []uint8{0})
+		flags |= broadcastFlagEncrypted
 	}
+	if b.Compressed() {
+		flags |= broadcastFlagCompressed
+	}
+	buf[offset] = flags
 	offset += EncryptedTypeLen
-	binary.LittleEndian.PutUint64(buf[offset:], uint64(len(b.block)+types.UInt64Len))
+	block := b.packedBlock()
+	binary.LittleEndian.PutUint64(buf[offset:], uint64(len(block)+types.UInt64Len))
 	offset += types.UInt64Len
-	copy(buf[offset:], b.block)
-	offset += len(b.block)
+	copy(buf[offset:], block)
+	offset += len(block)
 	binary.LittleEndian.PutUint32(buf[offset:], uint32(len(b.sids)))
 	offset += types.UInt32Len
 	for _, sid := range b.sids {
@@ -190,7 +203,8 @@ func (b *Broadcast) Unpack(buf []byte, protocol Protocol) error {
 	if b.IsBeaconBlock() && protocol < BeaconBlockProtocol {
 		return fmt.Errorf("should not unpack beacon block from lower protocol %v", protocol)
 	}
-	b.encrypted = int(buf[offset : offset+EncryptedTypeLen][0]) != 0
+	flags := buf[offset]
+	b.encrypted = flags&broadcastFlagEncrypted != 0
 	offset += EncryptedTypeLen
 
 	if err := checkBufSize(&buf, offset, types.UInt64Len); err != nil {
@@ -204,6 +218,11 @@ func (b *Broadcast) Unpack(buf []byte, protocol Protocol) error {
 	}
 	b.block = buf[offset+types.UInt64Len : offset+sidsOffset]
 	offset += sidsOffset
+	if flags&broadcastFlagCompressed != 0 {
+		if err := b.decompress(); err != nil {
+			return err
+		}
+	}
 
 	if err := checkBufSize(&buf, offset, types.UInt32Len); err != nil {
 		return err
@@ -233,7 +252,7 @@ func (b *Broadcast) Unpack(buf []byte, protocol Protocol) error {
 func (b *Broadcast) Size(protocol Protocol) uint32 {
 	size := b.fixedSize() +
 		types.UInt64Len + // sids offset
-		uint32(len(b.block)) +
+		uint32(len(b.packedBlock())) +
 		types.UInt32Len + // sids len
 		(uint32(len(b.sids)) * types.UInt32Len)
 
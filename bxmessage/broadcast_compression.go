@@ -0,0 +1,69 @@
+package bxmessage
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// minCompressedBlockSize is the size of the smallest block compressed in broadcasts, the compression of smaller blocks
+// saves too little bandwidth for its latency
+const minCompressedBlockSize = 4 * 1024
+
+// maxDecompressedBlockSize bounds the memory used to decompress the block of a broadcast
+const maxDecompressedBlockSize = 256 * 1024 * 1024
+
+// the encoder and decoder are safe for concurrent use with EncodeAll and DecodeAll, their options are valid so they
+// are created without errors
+var (
+	blockEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	blockDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBlockSize))
+)
+
+// Compress compresses the block with zstd for the peers supporting FeatureCompressedBroadcast, the block stays
+// uncompressed if it is small or does not compress
+func (b *Broadcast) Compress() {
+	if len(b.block) < minCompressedBlockSize {
+		return
+	}
+	compressed := blockEncoder.EncodeAll(b.block, make([]byte, 0, len(b.block)/2))
+	if len(compressed) >= len(b.block) {
+		return
+	}
+	b.compressedBlock = compressed
+}
+
+// Compressed returns true if the block is sent compressed
+func (b *Broadcast) Compressed() bool {
+	return b.compressedBlock != nil
+}
+
+// Uncompressed returns the broadcast sending its block uncompressed, for the peers not supporting
+// FeatureCompressedBroadcast
+func (b *Broadcast) Uncompressed() *Broadcast {
+	if !b.Compressed() {
+		return b
+	}
+	uncompressed := *b
+	uncompressed.compressedBlock = nil
+	return &uncompressed
+}
+
+// packedBlock returns the bytes of the block sent in the message
+func (b *Broadcast) packedBlock() []byte {
+	if b.Compressed() {
+		return b.compressedBlock
+	}
+	return b.block
+}
+
+// decompress replaces the compressed block of an unpacked message with its decompression
+func (b *Broadcast) decompress() error {
+	block, err := blockDecoder.DecodeAll(b.block, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decompress the block of broadcast %v: %v", b.hash, err)
+	}
+	b.compressedBlock = b.block
+	b.block = block
+	return nil
+}
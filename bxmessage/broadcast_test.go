@@ -1,6 +1,7 @@
 package bxmessage
 
 import (
+	"bytes"
 	"encoding/hex"
 	"testing"
 
@@ -63,3 +64,32 @@ func TestBroadcastUnpackFixtureEmptyBlock(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, b, encodedBroadcast)
 }
+
+func TestBroadcastCompression(t *testing.T) {
+	blockBody := bytes.Repeat(test.GenerateBytes(100), 100)
+	broadcast := NewBlockBroadcast(types.GenerateSHA256Hash(), types.EmptyHash, types.BxBlockTypeEth, blockBody, types.ShortIDList{1, 2}, networkNum)
+	broadcast.Compress()
+	assert.True(t, broadcast.Compressed())
+	assert.Equal(t, blockBody, broadcast.Block())
+
+	compressed, err := broadcast.Pack(CurrentProtocol)
+	assert.Nil(t, err)
+	uncompressed, err := broadcast.Uncompressed().Pack(CurrentProtocol)
+	assert.Nil(t, err)
+	assert.Less(t, len(compressed), len(uncompressed))
+	assert.True(t, broadcast.Compressed())
+
+	for _, b := range [][]byte{compressed, uncompressed} {
+		var decodedBroadcast Broadcast
+		assert.Nil(t, decodedBroadcast.Unpack(b, CurrentProtocol))
+		assert.Equal(t, broadcast.Hash(), decodedBroadcast.Hash())
+		assert.Equal(t, blockBody, decodedBroadcast.Block())
+		assert.Equal(t, types.ShortIDList{1, 2}, decodedBroadcast.ShortIDs())
+		assert.False(t, decodedBroadcast.Encrypted())
+	}
+
+	// small blocks are not compressed
+	small := NewBlockBroadcast(types.GenerateSHA256Hash(), types.EmptyHash, types.BxBlockTypeEth, blockBody[:100], nil, networkNum)
+	small.Compress()
+	assert.False(t, small.Compressed())
+}
@@ -0,0 +1,127 @@
+package bxmessage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ConformanceVector is a sample message with its canonical encoding at a protocol version, used to catch
+// accidental changes of the wire format
+type ConformanceVector struct {
+	Name     string
+	Protocol Protocol
+	Message  Message
+	// New returns an empty message to unpack the encoding into
+	New func() Message
+	// Lossy is set for messages that are not completely restored on unpack, so they are not packed again
+	Lossy bool
+}
+
+// Key returns the identifier of the vector in the golden file
+func (v ConformanceVector) Key() string {
+	return fmt.Sprintf("%v@%v", v.Name, v.Protocol)
+}
+
+// Check packs the message and compares it to the golden encoding, then unpacks the golden encoding and packs it
+// again to make sure the round trip is stable
+func (v ConformanceVector) Check(golden []byte) error {
+	buf, err := v.Message.Pack(v.Protocol)
+	if err != nil {
+		return fmt.Errorf("%v: failed to pack: %v", v.Key(), err)
+	}
+	if !bytes.Equal(buf, golden) {
+		return fmt.Errorf("%v: encoding changed\n\tgolden: %x\n\tpacked: %x", v.Key(), golden, buf)
+	}
+
+	msg := v.New()
+	if err = msg.Unpack(golden, v.Protocol); err != nil {
+		return fmt.Errorf("%v: failed to unpack: %v", v.Key(), err)
+	}
+	if v.Lossy {
+		return nil
+	}
+	repacked, err := msg.Pack(v.Protocol)
+	if err != nil {
+		return fmt.Errorf("%v: failed to pack unpacked message: %v", v.Key(), err)
+	}
+	if !bytes.Equal(repacked, golden) {
+		return fmt.Errorf("%v: round trip changed encoding\n\tgolden:   %x\n\trepacked: %x", v.Key(), golden, repacked)
+	}
+	return nil
+}
+
+// DumpConformanceVectors writes the encodings of the vectors in the golden file format, one vector per line
+func DumpConformanceVectors(w io.Writer, vectors []ConformanceVector) error {
+	for _, v := range vectors {
+		buf, err := v.Message.Pack(v.Protocol)
+		if err != nil {
+			return fmt.Errorf("%v: failed to pack: %v", v.Key(), err)
+		}
+		if _, err = fmt.Fprintf(w, "%v %v %x\n", v.Name, v.Protocol, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyConformanceVectors checks the vectors against the golden file, reporting every changed encoding as well as
+// vectors missing from the golden file and golden encodings without a vector
+func VerifyConformanceVectors(r io.Reader, vectors []ConformanceVector) error {
+	golden, err := readConformanceVectors(r)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, v := range vectors {
+		buf, ok := golden[v.Key()]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%v: no golden encoding", v.Key()))
+			continue
+		}
+		delete(golden, v.Key())
+		if err = v.Check(buf); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	for key := range golden {
+		failures = append(failures, fmt.Sprintf("%v: golden encoding without vector", key))
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%v conformance failures:\n%v", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+func readConformanceVectors(r io.Reader) (map[string][]byte, error) {
+	golden := make(map[string][]byte)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %v: expected name, protocol and encoding, got %v fields", line, len(fields))
+		}
+		protocol, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %v: invalid protocol %v: %v", line, fields[1], err)
+		}
+		buf, err := hex.DecodeString(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %v: invalid encoding: %v", line, err)
+		}
+		golden[fmt.Sprintf("%v@%v", fields[0], protocol)] = buf
+	}
+	return golden, scanner.Err()
+}
@@ -0,0 +1,229 @@
+package bxmessage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const conformanceVectorsFile = "testdata/conformance_vectors.txt"
+
+// run with -update after an intended wire format change to regenerate the golden encodings
+var updateConformanceVectors = flag.Bool("update", false, "update the golden conformance vectors")
+
+func TestConformanceVectors(t *testing.T) {
+	if *updateConformanceVectors {
+		var buf bytes.Buffer
+		require.NoError(t, DumpConformanceVectors(&buf, conformanceVectors()))
+		require.NoError(t, os.WriteFile(conformanceVectorsFile, buf.Bytes(), 0644))
+	}
+
+	f, err := os.Open(conformanceVectorsFile)
+	require.NoError(t, err)
+	defer f.Close()
+
+	assert.NoError(t, VerifyConformanceVectors(f, conformanceVectors()))
+}
+
+func TestConformanceVectorsDetectChanges(t *testing.T) {
+	vectors := conformanceVectors()
+	var buf bytes.Buffer
+	require.NoError(t, DumpConformanceVectors(&buf, vectors))
+	require.NoError(t, VerifyConformanceVectors(bytes.NewReader(buf.Bytes()), vectors))
+
+	// a changed encoding
+	ping := vectors[3]
+	require.Equal(t, PingType, ping.Name)
+	golden, err := ping.Message.Pack(ping.Protocol)
+	require.NoError(t, err)
+	golden[HeaderLen] ^= 0xff
+	assert.Error(t, ping.Check(golden))
+
+	// a vector without golden encoding, and a golden encoding without vector
+	assert.Error(t, VerifyConformanceVectors(bytes.NewReader(buf.Bytes()), vectors[1:]))
+	assert.Error(t, VerifyConformanceVectors(bytes.NewReader(buf.Bytes()), append(vectors, ConformanceVector{
+		Name: PingType, Protocol: CurrentProtocol, Message: &Ping{Nonce: 1}, New: func() Message { return &Ping{} },
+	})))
+}
+
+// conformanceBytes returns n deterministic bytes starting from seed
+func conformanceBytes(seed byte, n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = seed + byte(i)
+	}
+	return b
+}
+
+func conformanceHash(seed byte) (hash types.SHA256Hash) {
+	copy(hash[:], conformanceBytes(seed, types.SHA256HashLen))
+	return hash
+}
+
+func conformanceBroadcastHeader(seed byte) BroadcastHeader {
+	header := BroadcastHeader{
+		hash:          conformanceHash(seed),
+		networkNumber: bxgateway.MainnetNum,
+	}
+	copy(header.sourceID[:], conformanceBytes(seed+0x80, SourceIDLen))
+	return header
+}
+
+func conformanceCleanup(seed byte) abstractCleanup {
+	return abstractCleanup{
+		BroadcastHeader: conformanceBroadcastHeader(seed),
+		Hashes:          types.SHA256HashList{conformanceHash(0x40)},
+		ShortIDs:        types.ShortIDList{1, 2},
+	}
+}
+
+func conformanceTx(protocol Protocol, flags types.TxFlags) *Tx {
+	tx := &Tx{
+		BroadcastHeader: conformanceBroadcastHeader(0x10),
+		shortID:         9,
+		flags:           flags,
+		timestamp:       time.Unix(1700000000, 123456789),
+		content:         conformanceBytes(0xa0, 16),
+	}
+	copy(tx.sender[:], conformanceBytes(0x60, SenderLen))
+	if flags.IsPaid() {
+		tx.SetAccountID("c2f7a8b2-6f4e-4b4f-9a4b-6d0a3f1b2c3d")
+	}
+	if flags.IsNextValidator() {
+		tx.SetFallback(100)
+		tx.SetWalletID(0, "0x0bac492386862ad3df4b666bc096b0505bb694da")
+		if protocol >= NextValidatorMultipleProtocol {
+			tx.SetWalletID(1, "0x2465176c461afb316ebc773c61faee85a6515daa")
+		}
+	}
+	return tx
+}
+
+func conformanceSyncTxs() *SyncTxsMessage {
+	msg := &SyncTxsMessage{networkNumber: bxgateway.MainnetNum}
+	tx := types.NewBxTransaction(conformanceHash(0x20), bxgateway.MainnetNum, types.TFPaidTx, time.Unix(1700000000, 0))
+	tx.SetContent(conformanceBytes(0xb0, 8))
+	tx.AddShortID(3)
+	tx.AddShortID(4)
+	msg.Add(tx)
+	return msg
+}
+
+func conformanceBDNStats() *BdnPerformanceStats {
+	return &BdnPerformanceStats{
+		intervalStartTime:   time.Unix(1700000000, 0),
+		intervalEndTime:     time.Unix(1700000060, 0),
+		memoryUtilizationMb: 512,
+		nodeStats: map[string]*BdnPerformanceStatsData{
+			types.NodeEndpoint{IP: "127.0.0.1", Port: 8545}.IPPort(): {
+				NewBlocksReceivedFromBlockchainNode:     1,
+				NewBlocksReceivedFromBdn:                2,
+				NewBlocksSeen:                           3,
+				NewBlockMessagesFromBlockchainNode:      4,
+				NewBlockAnnouncementsFromBlockchainNode: 5,
+				NewTxReceivedFromBlockchainNode:         6,
+				NewTxReceivedFromBdn:                    7,
+				TxSentToNode:                            8,
+				DuplicateTxFromNode:                     9,
+				IsConnected:                             true,
+				IsBeacon:                                true,
+			},
+		},
+		burstLimitedTransactionsPaid:   10,
+		burstLimitedTransactionsUnpaid: 11,
+	}
+}
+
+func conformanceMEVBundle() *MEVBundle {
+	return &MEVBundle{
+		BroadcastHeader:      conformanceBroadcastHeader(0x30),
+		Method:               "eth_sendBundle",
+		UUID:                 "4e5f6a7b-8c9d-4eaf-b0c1-d2e3f4a5b6c7",
+		Transactions:         []string{"0x" + hex.EncodeToString(conformanceBytes(0xc0, 8))},
+		BlockNumber:          "0x10",
+		MinTimestamp:         1700000000,
+		MaxTimestamp:         1700000012,
+		RevertingHashes:      []string{conformanceHash(0x50).Format(true)},
+		Frontrunning:         true,
+		PerformanceTimestamp: time.Unix(1700000000, 0),
+		BundleHash:           conformanceHash(0x70).Format(true),
+		MEVBuilders:          MEVBundleBuilders{"bloxroute": "auth"},
+		BundlePrice:          1000,
+		EnforcePayout:        true,
+	}
+}
+
+func conformanceMEVSearcher() *MEVSearcher {
+	return &MEVSearcher{
+		BroadcastHeader:      conformanceBroadcastHeader(0x38),
+		Method:               "eth_sendBundle",
+		auth:                 MEVSearcherAuth{"bloxroute": "auth"},
+		UUID:                 "4e5f6a7b-8c9d-4eaf-b0c1-d2e3f4a5b6c7",
+		Frontrunning:         true,
+		effectiveGasPriceLen: 2,
+		EffectiveGasPrice:    *big.NewInt(1000),
+		coinbaseProfitLen:    1,
+		CoinbaseProfit:       *big.NewInt(200),
+		Params:               MEVSearcherParams(`[{"blockNumber":"0x10"}]`),
+	}
+}
+
+// conformanceVectors returns sample messages of every message type, at each protocol version that changed their
+// encoding. Vectors must be deterministic, and new protocol versions that change an encoding should add vectors
+// instead of changing the existing ones.
+func conformanceVectors() []ConformanceVector {
+	nodeID := types.NodeID("a0b1c2d3-e4f5-4a6b-8c7d-9e0f1a2b3c4d")
+	helloMEV := &Hello{Protocol: MEVProtocol, networkNumber: bxgateway.MainnetNum, NodeID: nodeID,
+		Capabilities: types.CapabilityBDN, ClientVersion: "v2.0.0"}
+	helloNegotiation := &Hello{Protocol: CapabilityNegotiationProtocol, networkNumber: bxgateway.MainnetNum, NodeID: nodeID,
+		Capabilities: types.CapabilityBDN, ClientVersion: "v2.0.0", Features: FeatureTxBatching,
+		MsgTypes: []string{HelloType, TxType, BroadcastType}}
+
+	syncReq := &SyncReq{}
+	syncReq.SetNetworkNum(bxgateway.MainnetNum)
+	syncDone := &SyncDone{}
+	syncDone.SetNetworkNum(bxgateway.MainnetNum)
+
+	ethBroadcast := NewBlockBroadcast(conformanceHash(0x01), types.SHA256Hash{}, types.BxBlockTypeEth,
+		conformanceBytes(0xd0, 24), types.ShortIDList{5, 6}, bxgateway.MainnetNum)
+	beaconBroadcast := NewBlockBroadcast(conformanceHash(0x02), conformanceHash(0x03), types.BxBlockTypeBeaconCapella,
+		conformanceBytes(0xe0, 24), types.ShortIDList{7}, bxgateway.MainnetNum)
+
+	return []ConformanceVector{
+		{Name: HelloType, Protocol: MEVProtocol, Message: helloMEV, New: func() Message { return &Hello{} }, Lossy: true},
+		{Name: HelloType, Protocol: CapabilityNegotiationProtocol, Message: helloNegotiation, New: func() Message { return &Hello{} }, Lossy: true},
+		{Name: AckType, Protocol: MinProtocol, Message: &Ack{}, New: func() Message { return &Ack{} }},
+		{Name: PingType, Protocol: MinProtocol, Message: &Ping{Nonce: 0x0102030405060708}, New: func() Message { return &Ping{} }},
+		{Name: PongType, Protocol: MinProtocol, Message: &Pong{Nonce: 0x0102030405060708, TimeStamp: 1700000000000000}, New: func() Message { return &Pong{} }},
+		{Name: SyncReqType, Protocol: MinProtocol, Message: syncReq, New: func() Message { return &SyncReq{} }},
+		{Name: SyncDoneType, Protocol: MinProtocol, Message: syncDone, New: func() Message { return &SyncDone{} }},
+		{Name: SyncTxsType, Protocol: MinProtocol, Message: conformanceSyncTxs(), New: func() Message { return &SyncTxsMessage{} }, Lossy: true},
+		{Name: DropRelayType, Protocol: MinProtocol, Message: &DropRelay{}, New: func() Message { return &DropRelay{} }},
+		{Name: RefreshBlockchainNetworkType, Protocol: MinProtocol, Message: &RefreshBlockchainNetwork{}, New: func() Message { return &RefreshBlockchainNetwork{} }},
+		{Name: BlockConfirmationType, Protocol: MinProtocol, Message: &BlockConfirmation{conformanceCleanup(0x04)}, New: func() Message { return &BlockConfirmation{} }},
+		{Name: TxCleanupType, Protocol: MinProtocol, Message: &TxCleanup{conformanceCleanup(0x05)}, New: func() Message { return &TxCleanup{} }},
+		{Name: GetTransactionsType, Protocol: MinProtocol, Message: &GetTxs{ShortIDs: types.ShortIDList{1, 0x01020304}}, New: func() Message { return &GetTxs{} }},
+		{Name: TransactionsType, Protocol: MinProtocol, Message: NewTxs([]TxsItem{{Hash: conformanceHash(0x06), Content: conformanceBytes(0xf0, 4), ShortID: 7}}), New: func() Message { return &Txs{} }},
+		{Name: ErrorNotificationType, Protocol: MinProtocol, Message: &ErrorNotification{Code: 1, Reason: "blocked"}, New: func() Message { return &ErrorNotification{} }},
+		{Name: ValidatorUpdatesType, Protocol: MinProtocol, Message: &ValidatorUpdates{networkNum: uint32(bxgateway.BSCMainnetNum), onlineListLength: 2,
+			onlineList: []string{"0x0bac492386862ad3df4b666bc096b0505bb694da", "0x2465176c461afb316ebc773c61faee85a6515daa"}}, New: func() Message { return &ValidatorUpdates{} }},
+		{Name: TxType, Protocol: SenderProtocol, Message: conformanceTx(SenderProtocol, types.TFPaidTx), New: func() Message { return &Tx{} }},
+		{Name: TxType, Protocol: NextValidatorProtocol, Message: conformanceTx(NextValidatorProtocol, types.TFNextValidator), New: func() Message { return &Tx{} }},
+		{Name: TxType, Protocol: NextValidatorMultipleProtocol, Message: conformanceTx(NextValidatorMultipleProtocol, types.TFNextValidator), New: func() Message { return &Tx{} }},
+		{Name: BroadcastType, Protocol: MinProtocol, Message: ethBroadcast, New: func() Message { return &Broadcast{} }},
+		{Name: BroadcastType, Protocol: ShanghaiProtocol, Message: beaconBroadcast, New: func() Message { return &Broadcast{} }},
+		{Name: BDNPerformanceStatsType, Protocol: GatewayInboundConnections, Message: conformanceBDNStats(), New: func() Message { return &BdnPerformanceStats{} }},
+		{Name: MEVBundleType, Protocol: BundlesOverBDNProtocol, Message: conformanceMEVBundle(), New: func() Message { return &MEVBundle{} }},
+		{Name: MEVBundleType, Protocol: BundlesOverBDNPayoutProtocol, Message: conformanceMEVBundle(), New: func() Message { return &MEVBundle{} }},
+		{Name: MEVSearcherType, Protocol: MevMaxProfitBuilder, Message: conformanceMEVSearcher(), New: func() Message { return &MEVSearcher{} }},
+	}
+}
@@ -61,6 +61,7 @@ const (
 	ValidatorUpdatesType         = "validator"
 	MEVBundleType                = "mevbundle"
 	MEVSearcherType              = "mevsearcher"
+	MEVShareHintType             = "mevsharehint"
 	ErrorNotificationType        = "notify"
 )
 
@@ -86,7 +87,11 @@ const EmptyProtocol = 0
 const MinProtocol = 19
 
 // CurrentProtocol tracks the most recent version of the bloxroute wire protocol
-const CurrentProtocol = BundlesOverBDNPayoutProtocol
+const CurrentProtocol = CapabilityNegotiationProtocol
+
+// CapabilityNegotiationProtocol is the minimum protocol version that exchanges the supported message types and
+// optional features in the hello message
+const CapabilityNegotiationProtocol = 39
 
 // BundlesOverBDNPayoutProtocol is the minimum protocol version that supports bundles over BDN with payout
 const BundlesOverBDNPayoutProtocol = 38
@@ -158,6 +163,12 @@ const CapabilitiesLen = 2
 // ClientVersionLen is the byte length of the client version
 const ClientVersionLen = 100
 
+// FeaturesLen is the byte length of the optional protocol features
+const FeaturesLen = 4
+
+// MsgTypesCountLen is the byte length of the number of supported message types
+const MsgTypesCountLen = 2
+
 // NullByteAccountID is a null byte packed series, which represents an empty accountID
 var NullByteAccountID = bytes.Repeat([]byte("\x00"), AccountIDLen)
 
@@ -0,0 +1,76 @@
+package bxmessage
+
+import "github.com/bloXroute-Labs/gateway/v2/version"
+
+// Features are the optional protocol features a node supports, negotiated in the hello handshake so they can be
+// enabled per connection without a protocol version lockstep
+type Features uint32
+
+// Features enumeration
+const (
+	FeatureCompressedBroadcast Features = 1 << iota
+	FeatureTxBatching
+)
+
+// SupportedFeatures are the optional protocol features implemented by this node
+const SupportedFeatures = FeatureCompressedBroadcast
+
+// SupportedMsgTypes are the message types this node can handle
+var SupportedMsgTypes = []string{
+	HelloType, AckType, TxType, PingType, PongType, BroadcastType, BlockTxsType, TxCleanupType, SyncTxsType,
+	SyncReqType, SyncDoneType, DropRelayType, RefreshBlockchainNetworkType, BlockConfirmationType,
+	GetTransactionsType, TransactionsType, BDNPerformanceStatsType, ValidatorUpdatesType, MEVBundleType,
+	MEVSearcherType, MEVShareHintType, ErrorNotificationType,
+}
+
+// NegotiatedMsgType returns the type of the message if it is only sent to the peers listing it in their hello
+// message, an empty string for the message types every peer handles
+func NegotiatedMsgType(msg Message) string {
+	switch msg.(type) {
+	case *TxCleanup:
+		return TxCleanupType
+	case *BlockConfirmation:
+		return BlockConfirmationType
+	case *BdnPerformanceStats:
+		return BDNPerformanceStatsType
+	case *ValidatorUpdates:
+		return ValidatorUpdatesType
+	case *MEVBundle:
+		return MEVBundleType
+	case *MEVSearcher:
+		return MEVSearcherType
+	case *MEVShareHint:
+		return MEVShareHintType
+	case *ErrorNotification:
+		return ErrorNotificationType
+	default:
+		return ""
+	}
+}
+
+// Has returns true if all the features are set
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// featureNames are the names of the optional protocol features, reported in the build information
+var featureNames = map[Features]string{
+	FeatureCompressedBroadcast: "compressed_broadcast",
+	FeatureTxBatching:          "tx_batching",
+}
+
+// Names returns the names of the features which are set
+func (f Features) Names() []string {
+	names := make([]string, 0)
+	for feature := FeatureCompressedBroadcast; feature <= FeatureTxBatching; feature <<= 1 {
+		if f.Has(feature) {
+			names = append(names, featureNames[feature])
+		}
+	}
+	return names
+}
+
+// BuildInfo returns the build information of this node, with the wire protocol versions and features it supports
+func BuildInfo() version.Info {
+	return version.NewInfo(MinProtocol, CurrentProtocol, SupportedFeatures.Names())
+}
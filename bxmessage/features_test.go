@@ -0,0 +1,20 @@
+package bxmessage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeaturesNames(t *testing.T) {
+	assert.Empty(t, Features(0).Names())
+	assert.Equal(t, []string{"tx_batching"}, FeatureTxBatching.Names())
+	assert.Equal(t, []string{"compressed_broadcast", "tx_batching"}, (FeatureCompressedBroadcast | FeatureTxBatching).Names())
+}
+
+func TestBuildInfo(t *testing.T) {
+	info := BuildInfo()
+	assert.Equal(t, int64(MinProtocol), info.MinProtocolVersion)
+	assert.Equal(t, int64(CurrentProtocol), info.ProtocolVersion)
+	assert.Equal(t, SupportedFeatures.Names(), info.ProtocolFeatures)
+}
@@ -1,8 +1,10 @@
 package bxmessage
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"fmt"
 	"strings"
 
 	"github.com/bloXroute-Labs/gateway/v2/types"
@@ -16,6 +18,8 @@ type Hello struct {
 	NodeID        types.NodeID
 	Capabilities  types.CapabilityFlags
 	ClientVersion string
+	Features      Features
+	MsgTypes      []string
 }
 
 // GetNetworkNum gets the message network number
@@ -33,6 +37,9 @@ func (m *Hello) size() uint32 {
 	if m.Protocol >= MEVProtocol {
 		size += ClientVersionLen + CapabilitiesLen
 	}
+	if m.Protocol >= CapabilityNegotiationProtocol {
+		size += FeaturesLen + MsgTypesCountLen + uint32(len(m.MsgTypes))*TypeLength
+	}
 
 	return size
 }
@@ -58,6 +65,16 @@ func (m *Hello) Pack(protocol Protocol) ([]byte, error) {
 		copy(buf[offset:], m.ClientVersion)
 		offset += ClientVersionLen
 	}
+	if m.Protocol >= CapabilityNegotiationProtocol {
+		binary.LittleEndian.PutUint32(buf[offset:], uint32(m.Features))
+		offset += FeaturesLen
+		binary.LittleEndian.PutUint16(buf[offset:], uint16(len(m.MsgTypes)))
+		offset += MsgTypesCountLen
+		for _, msgType := range m.MsgTypes {
+			copy(buf[offset:offset+TypeLength], msgType)
+			offset += TypeLength
+		}
+	}
 	m.Header.Pack(&buf, "hello")
 	return buf, nil
 
@@ -72,7 +89,11 @@ func (m *Hello) Unpack(buf []byte, protocol Protocol) error {
 	offset += types.NetworkNumLen
 	m.NodeID = types.NodeID(buf[offset:])
 	offset += types.NodeIDLen
-	if m.Protocol >= MEVProtocol {
+	if m.Protocol >= CapabilityNegotiationProtocol {
+		if err := m.unpackNegotiation(buf, offset); err != nil {
+			return err
+		}
+	} else if m.Protocol >= MEVProtocol {
 		m.Capabilities = types.CapabilityFlags(binary.LittleEndian.Uint16(buf[offset:]))
 		offset += CapabilitiesLen
 		m.ClientVersion = string(buf[offset:])
@@ -85,3 +106,26 @@ func (m *Hello) Unpack(buf []byte, protocol Protocol) error {
 
 	return m.Header.Unpack(buf, protocol)
 }
+
+func (m *Hello) unpackNegotiation(buf []byte, offset int) error {
+	if len(buf) < offset+CapabilitiesLen+ClientVersionLen+FeaturesLen+MsgTypesCountLen {
+		return fmt.Errorf("hello message is too short: %v bytes", len(buf))
+	}
+	m.Capabilities = types.CapabilityFlags(binary.LittleEndian.Uint16(buf[offset:]))
+	offset += CapabilitiesLen
+	m.ClientVersion = string(bytes.Trim(buf[offset:offset+ClientVersionLen], NullByte))
+	offset += ClientVersionLen
+	m.Features = Features(binary.LittleEndian.Uint32(buf[offset:]))
+	offset += FeaturesLen
+	count := int(binary.LittleEndian.Uint16(buf[offset:]))
+	offset += MsgTypesCountLen
+	if len(buf) < offset+count*TypeLength {
+		return fmt.Errorf("hello message is too short for %v message types: %v bytes", count, len(buf))
+	}
+	m.MsgTypes = make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		m.MsgTypes = append(m.MsgTypes, string(bytes.Trim(buf[offset:offset+TypeLength], NullByte)))
+		offset += TypeLength
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package bxmessage
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelloPackUnpackNegotiation(t *testing.T) {
+	hello := Hello{
+		Protocol:      CapabilityNegotiationProtocol,
+		NodeID:        "8f1b5e2c-0c3b-4f3a-9e7e-2a1c8d9b7f60",
+		Capabilities:  types.CapabilityBDN,
+		ClientVersion: "2.0.0",
+		Features:      FeatureTxBatching,
+		MsgTypes:      SupportedMsgTypes,
+	}
+	hello.SetNetworkNum(5)
+
+	b, err := hello.Pack(CapabilityNegotiationProtocol)
+	require.NoError(t, err)
+
+	var unpacked Hello
+	require.NoError(t, unpacked.Unpack(b, 0))
+	assert.Equal(t, types.NetworkNum(5), unpacked.GetNetworkNum())
+	assert.Equal(t, "2.0.0", unpacked.ClientVersion)
+	assert.Equal(t, types.CapabilityBDN, unpacked.Capabilities)
+	assert.True(t, unpacked.Features.Has(FeatureTxBatching))
+	assert.False(t, unpacked.Features.Has(FeatureCompressedBroadcast))
+	assert.Equal(t, SupportedMsgTypes, unpacked.MsgTypes)
+
+	assert.Error(t, unpacked.Unpack(b[:len(b)-TypeLength-ControlByteLen], 0))
+}
+
+func TestHelloPackUnpackBeforeNegotiation(t *testing.T) {
+	hello := Hello{
+		Protocol: BundlesOverBDNPayoutProtocol,
+		NodeID:   "8f1b5e2c-0c3b-4f3a-9e7e-2a1c8d9b7f60",
+		Features: FeatureTxBatching,
+		MsgTypes: SupportedMsgTypes,
+	}
+
+	b, err := hello.Pack(BundlesOverBDNPayoutProtocol)
+	require.NoError(t, err)
+
+	var unpacked Hello
+	require.NoError(t, unpacked.Unpack(b, 0))
+	assert.Equal(t, Features(0), unpacked.Features)
+	assert.Empty(t, unpacked.MsgTypes)
+}
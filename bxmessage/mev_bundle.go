@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage/utils"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	uuid "github.com/satori/go.uuid"
@@ -92,6 +93,33 @@ func NewMEVBundle(
 	}, nil
 }
 
+// NewMEVBundleCancellation creates a MEVBundle retracting the bundles submitted with the UUID from the builders. The
+// time of the cancellation is its MinTimestamp, so the repeated cancellations of a UUID are not dropped as seen.
+func NewMEVBundleCancellation(uuid string, blockNumber string, mevBuilders MEVBundleBuilders) (MEVBundle, error) {
+	if err := checkBuilderSize(len(mevBuilders)); err != nil {
+		return MEVBundle{}, err
+	}
+	if len(uuid) != 36 {
+		return MEVBundle{}, errors.New("invalid uuid len")
+	}
+	if blockNumber == "" {
+		blockNumber = "0x0"
+	}
+	return MEVBundle{
+		Method:       string(jsonrpc.RPCEthCancelBundle),
+		UUID:         uuid,
+		BlockNumber:  blockNumber,
+		MinTimestamp: int(time.Now().Unix()),
+		MEVBuilders:  mevBuilders,
+	}, nil
+}
+
+// IsCancellation returns true if the bundle retracts the bundles of its UUID, a bundle with a UUID and without txs
+// is a cancellation as well
+func (m MEVBundle) IsCancellation() bool {
+	return m.UUID != "" && (m.Method == string(jsonrpc.RPCEthCancelBundle) || len(m.Transactions) == 0)
+}
+
 // String returns a string representation of the MEVBundle
 func (m MEVBundle) String() string {
 	return fmt.Sprintf("mev bundle(hash: %s, blockNumber: %s, builders: %v, frontrunning: %t, txs: %d)", m.BundleHash, m.BlockNumber, m.MEVBuilders, m.Frontrunning, len(m.Transactions))
@@ -0,0 +1,55 @@
+package bxmessage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// MEVShareHint represents the hint of a private tx propagated via BDN to the searchers of the mevShare feed, its
+// hash is the hash of the tx
+type MEVShareHint struct {
+	BroadcastHeader
+	Hint types.MEVShareNotification
+}
+
+// NewMEVShareHint creates a new MEVShareHint of the network
+func NewMEVShareHint(hint types.MEVShareNotification, networkNum types.NetworkNum) (*MEVShareHint, error) {
+	hash, err := types.NewSHA256HashFromString(hint.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hash of mev share hint: %v", err)
+	}
+	msg := &MEVShareHint{Hint: hint}
+	msg.SetHash(hash)
+	msg.SetNetworkNum(networkNum)
+	return msg, nil
+}
+
+// String returns a string representation of the MEVShareHint
+func (m MEVShareHint) String() string {
+	return fmt.Sprintf("mev share hint(hash: %s, logs: %d)", m.Hint.Hash, len(m.Hint.Logs))
+}
+
+// Pack serializes a MEVShareHint into a buffer for sending
+func (m MEVShareHint) Pack(protocol Protocol) ([]byte, error) {
+	hint, err := json.Marshal(m.Hint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mev share hint: %v", err)
+	}
+	buf := make([]byte, m.BroadcastHeader.Size()+uint32(len(hint)))
+	m.BroadcastHeader.Pack(&buf, MEVShareHintType, protocol)
+	copy(buf[BroadcastHeaderLen:], hint)
+	return buf, nil
+}
+
+// Unpack deserializes a MEVShareHint from a buffer
+func (m *MEVShareHint) Unpack(buf []byte, protocol Protocol) error {
+	if err := m.BroadcastHeader.Unpack(buf, protocol); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(buf[BroadcastHeaderLen:len(buf)-ControlByteLen], &m.Hint); err != nil {
+		return fmt.Errorf("failed to unmarshal mev share hint: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package bxmessage
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMEVShareHintPackUnpack(t *testing.T) {
+	hint := types.MEVShareNotification{
+		Hash:             "0x1f1b8a6b2a8e1f8d8b1a5a2a1c2b3d4e5f60718293a4b5c6d7e8f90112233445",
+		To:               "0xdac17f958d2ee523a2206206994597c13d831ec7",
+		FunctionSelector: "0xa9059cbb",
+		Logs: []types.MEVShareLog{{
+			Address: "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			Topics:  []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		}},
+	}
+	msg, err := NewMEVShareHint(hint, 5)
+	require.NoError(t, err)
+
+	buf, err := msg.Pack(CurrentProtocol)
+	require.NoError(t, err)
+
+	var unpacked MEVShareHint
+	require.NoError(t, unpacked.Unpack(buf, CurrentProtocol))
+	assert.Equal(t, hint, unpacked.Hint)
+	assert.Equal(t, msg.Hash(), unpacked.Hash())
+	assert.Equal(t, types.NetworkNum(5), unpacked.GetNetworkNum())
+}
+
+func TestNewMEVShareHintInvalidHash(t *testing.T) {
+	_, err := NewMEVShareHint(types.MEVShareNotification{Hash: "0x1234"}, 5)
+	assert.Error(t, err)
+}
@@ -25,7 +25,9 @@ func (pm Pong) Pack(protocol Protocol) ([]byte, error) {
 	offset := HeaderLen
 	binary.LittleEndian.PutUint64(buf[offset:], pm.Nonce)
 	offset += types.UInt64Len
-	pm.TimeStamp = uint64(time.Now().UnixNano() / 1000)
+	if pm.TimeStamp == 0 {
+		pm.TimeStamp = uint64(time.Now().UnixNano() / 1000)
+	}
 	binary.LittleEndian.PutUint64(buf[offset:], pm.TimeStamp)
 	pm.Header.Pack(&buf, "pong")
 	return buf, nil
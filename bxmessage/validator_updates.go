@@ -87,7 +87,7 @@ func (vu *ValidatorUpdates) Unpack(buf []byte, protocol Protocol) error {
 	if err := checkBufSize(&buf, offset, int(vu.onlineListLength*common.AddressLength)); err != nil {
 		return err
 	}
-	validatorList := make([]string, vu.onlineListLength)
+	validatorList := make([]string, 0, vu.onlineListLength)
 	for index := 0; index < int(vu.onlineListLength); index++ {
 		addrBytes := buf[offset+index*common.AddressLength : offset+(index+1)*common.AddressLength]
 		validatorList = append(validatorList, common.BytesToAddress(addrBytes).String())
@@ -14,12 +14,14 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/config"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
 	"github.com/bloXroute-Labs/gateway/v2/rpc"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services/capture"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -312,6 +314,35 @@ func main() {
 				Before: beforeBxCli,
 				Action: cmdShortIDs,
 			},
+			{
+				Name:  "replay",
+				Usage: "re-inject the messages of a gateway capture file through a sandboxed tx store and block processor",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "capture-file",
+						Required: true,
+					},
+					&cli.TimestampFlag{
+						Name:   "from",
+						Usage:  "replay messages captured from this time (e.g. 2023-06-01T12:00:00)",
+						Layout: "2006-01-02T15:04:05",
+					},
+					&cli.TimestampFlag{
+						Name:   "to",
+						Usage:  "replay messages captured until this time (e.g. 2023-06-01T12:05:00)",
+						Layout: "2006-01-02T15:04:05",
+					},
+					&cli.IntFlag{
+						Name:  "network-num",
+						Value: int(bxgateway.MainnetNum),
+					},
+					&cli.BoolFlag{
+						Name:  "errors-only",
+						Usage: "print only the messages that failed to be processed",
+					},
+				},
+				Action: cmdReplay,
+			},
 		},
 		Flags: []cli.Flag{
 			utils.GRPCHostFlag,
@@ -909,6 +940,34 @@ func cmdStatus(ctx *cli.Context) error {
 	return nil
 }
 
+func cmdReplay(ctx *cli.Context) error {
+	var from, to time.Time
+	if ctx.Timestamp("from") != nil {
+		from = *ctx.Timestamp("from")
+	}
+	if ctx.Timestamp("to") != nil {
+		to = *ctx.Timestamp("to")
+	}
+
+	records, err := capture.ReadWindow(ctx.String("capture-file"), from, to)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	sandbox := capture.NewSandbox(types.NetworkNum(ctx.Int("network-num")))
+	for _, result := range sandbox.Replay(records) {
+		if result.Err != nil {
+			failed++
+		} else if ctx.Bool("errors-only") {
+			continue
+		}
+		fmt.Println(result)
+	}
+	fmt.Printf("replayed %v messages, %v failed\n", len(records), failed)
+	return nil
+}
+
 func cmdShortIDs(ctx *cli.Context) error {
 	transactions := ctx.StringSlice("transaction-hashes")
 	txHashes := make([][]byte, len(transactions))
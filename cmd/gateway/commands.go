@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxclient"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/servers"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// the subcommands operate a running gateway through its websocket and gRPC APIs, for smoke tests without scripts
+var (
+	wsURLFlag = &cli.StringFlag{
+		Name:  "ws-url",
+		Usage: "websocket API of the gateway",
+		Value: "ws://127.0.0.1:28333/ws",
+	}
+	grpcAddressFlag = &cli.StringFlag{
+		Name:  "grpc-address",
+		Usage: "gRPC API of the gateway",
+		Value: "127.0.0.1:5001",
+	}
+	authHeaderFlag = &cli.StringFlag{
+		Name:  "auth-header",
+		Usage: "Authorization header of the account, the base64 encoding of account_id:secret_hash",
+	}
+	commandTimeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "time to wait for the reply of the gateway",
+		Value: 10 * time.Second,
+	}
+)
+
+var commands = []*cli.Command{
+	{
+		Name:  "sub",
+		Usage: "stream the notifications of a feed of a running gateway to stdout, one JSON object per line",
+		Flags: []cli.Flag{
+			wsURLFlag,
+			authHeaderFlag,
+			&cli.StringFlag{
+				Name:  "feed",
+				Usage: "feed to subscribe to, e.g. newTxs, pendingTxs, newBlocks, bdnBlocks or txReceipts",
+				Value: string(types.NewTxsFeed),
+			},
+			&cli.StringFlag{
+				Name:  "filters",
+				Usage: "filters of the tx feeds, e.g. \"to = 0xa0b8... and value > 1000\"",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "fields of the notifications, all the fields if not set",
+			},
+		},
+		Action: cmdSub,
+	},
+	{
+		Name:  "tx",
+		Usage: "submit a raw tx to a running gateway with blxr_tx",
+		Flags: []cli.Flag{
+			wsURLFlag,
+			authHeaderFlag,
+			commandTimeoutFlag,
+			&cli.StringFlag{
+				Name:     "transaction",
+				Usage:    "hex encoded signed tx",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name: "validators-only",
+			},
+			&cli.BoolFlag{
+				Name: "next-validator",
+			},
+			&cli.IntFlag{
+				Name: "fallback",
+			},
+			&cli.BoolFlag{
+				Name: "node-validation",
+			},
+			&cli.BoolFlag{
+				Name: "front-running-protection",
+			},
+		},
+		Action: cmdTx,
+	},
+	{
+		Name:  "bundle",
+		Usage: "submit the bundle of a JSON file, holding the params of blxr_submit_bundle, to a running gateway",
+		Flags: []cli.Flag{
+			wsURLFlag,
+			authHeaderFlag,
+			commandTimeoutFlag,
+			&cli.StringFlag{
+				Name:     "file",
+				Usage:    "JSON file of the bundle, e.g. {\"transaction\": [\"f86b...\"], \"block_number\": \"0x10d4f1a\"}",
+				Required: true,
+			},
+		},
+		Action: cmdBundle,
+	},
+	{
+		Name:  "status",
+		Usage: "query the status of a running gateway",
+		Flags: []cli.Flag{
+			grpcAddressFlag,
+			authHeaderFlag,
+			commandTimeoutFlag,
+		},
+		Action: cmdStatus,
+	},
+	{
+		Name:  "replay",
+		Usage: "serve the feeds captured by the JSON file sinks over the websocket subscribe API, for client tests",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "file",
+				Usage:    "capture to replay, gzipped if it ends with .gz, the files are replayed in the order of the flags",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "host",
+				Usage: "host of the websocket API of the replay",
+				Value: "127.0.0.1",
+			},
+			&cli.IntFlag{
+				Name:  "port",
+				Usage: "port of the websocket API of the replay, served at /ws",
+				Value: 28333,
+			},
+			&cli.Float64Flag{
+				Name:  "speed",
+				Usage: "pace of the replay, 1 replays the original timing, 10 ten times faster and 0 without waiting",
+				Value: 1,
+			},
+		},
+		Action: cmdReplay,
+	},
+}
+
+func dialWS(ctx context.Context, c *cli.Context) (*bxclient.WSClient, error) {
+	return bxclient.DialWS(ctx, c.String(wsURLFlag.Name), bxclient.WSOptions{AuthHeader: c.String(authHeaderFlag.Name)})
+}
+
+// printJSON writes v to the output of the app, stdout unless the app is run with another writer
+func printJSON(c *cli.Context, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON: %v", err)
+	}
+	_, err = fmt.Fprintln(c.App.Writer, string(b))
+	return err
+}
+
+func cmdSub(c *cli.Context) error {
+	ctx := utils.ContextWithSignal(c.Context)
+	client, err := dialWS(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	options := bxclient.SubscribeOptions{Include: c.StringSlice("include"), Filters: c.String("filters")}
+	sub, err := bxclient.Subscribe[json.RawMessage](ctx, client, types.FeedType(c.String("feed")), options)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification, ok := <-sub.Notifications():
+			if !ok {
+				return fmt.Errorf("subscription to %v ended", c.String("feed"))
+			}
+			if _, err = fmt.Fprintln(c.App.Writer, string(notification)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func cmdTx(c *cli.Context) error {
+	ctx, cancel := context.WithTimeout(c.Context, c.Duration(commandTimeoutFlag.Name))
+	defer cancel()
+	client, err := dialWS(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	txHash, err := client.SendTx(ctx, jsonrpc.RPCTxPayload{
+		Transaction:            c.String("transaction"),
+		ValidatorsOnly:         c.Bool("validators-only"),
+		NextValidator:          c.Bool("next-validator"),
+		Fallback:               uint16(c.Int("fallback")),
+		NodeValidation:         c.Bool("node-validation"),
+		FrontRunningProtection: c.Bool("front-running-protection"),
+	})
+	if err != nil {
+		return fmt.Errorf("could not submit tx: %v", err)
+	}
+	return printJSON(c, map[string]string{"txHash": txHash})
+}
+
+func cmdBundle(c *cli.Context) error {
+	content, err := os.ReadFile(c.String("file"))
+	if err != nil {
+		return fmt.Errorf("could not read bundle file: %v", err)
+	}
+	var bundle jsonrpc.RPCBundleSubmissionPayload
+	if err = json.Unmarshal(content, &bundle); err != nil {
+		return fmt.Errorf("could not decode bundle file: %v", err)
+	}
+	if err = bundle.Validate(); err != nil {
+		return fmt.Errorf("invalid bundle: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context, c.Duration(commandTimeoutFlag.Name))
+	defer cancel()
+	client, err := dialWS(ctx, c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var result json.RawMessage
+	if err = client.Call(ctx, jsonrpc.RPCBundleSubmission, bundle, &result); err != nil {
+		return fmt.Errorf("could not submit bundle: %v", err)
+	}
+	_, err = fmt.Fprintln(c.App.Writer, string(result))
+	return err
+}
+
+func cmdStatus(c *cli.Context) error {
+	ctx, cancel := context.WithTimeout(c.Context, c.Duration(commandTimeoutFlag.Name))
+	defer cancel()
+	client, err := bxclient.DialGRPC(ctx, c.String(grpcAddressFlag.Name), bxclient.GRPCOptions{AuthHeader: c.String(authHeaderFlag.Name)})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	status, err := client.Gateway().Status(ctx, &pb.StatusRequest{})
+	if err != nil {
+		return fmt.Errorf("could not get status: %v", err)
+	}
+	return printJSON(c, status)
+}
+
+func cmdReplay(c *cli.Context) error {
+	handler, err := servers.NewReplayHandler(servers.ReplayOptions{
+		Files: c.StringSlice("file"),
+		Speed: c.Float64("speed"),
+	}, utils.RealClock{})
+	if err != nil {
+		return err
+	}
+	server := servers.NewWebsocketRPCServer(c.String("host"), c.Int("port"), handler)
+	return server.Run()
+}
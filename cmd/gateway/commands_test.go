@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+	websocketjsonrpc2 "github.com/sourcegraph/jsonrpc2/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+)
+
+const testAuthHeader = "dXNlcjpwYXNzd29yZA=="
+
+// output is the output of an app, written by the command while the test reads it
+type output struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.buf.Write(p)
+}
+
+func (o *output) String() string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return o.buf.String()
+}
+
+// mockGateway records the websocket requests of the commands and answers them like a gateway
+type mockGateway struct {
+	lock        sync.Mutex
+	authHeaders []string
+	requests    []*jsonrpc2.Request
+}
+
+func (g *mockGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	g.lock.Lock()
+	g.authHeaders = append(g.authHeaders, r.Header.Get("Authorization"))
+	g.lock.Unlock()
+	conn := jsonrpc2.NewConn(r.Context(), websocketjsonrpc2.NewObjectStream(ws), jsonrpc2.HandlerWithError(g.handle))
+	<-conn.DisconnectNotify()
+}
+
+func (g *mockGateway) handle(_ context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	g.lock.Lock()
+	g.requests = append(g.requests, req)
+	g.lock.Unlock()
+
+	switch jsonrpc.RPCRequestType(req.Method) {
+	case jsonrpc.RPCSubscribe:
+		go func() {
+			_ = conn.Notify(context.Background(), req.Method, map[string]interface{}{
+				"subscription": "sub-0",
+				"result":       json.RawMessage(`{"txHash":"0x01"}`),
+			})
+		}()
+		return "sub-0", nil
+	case jsonrpc.RPCUnsubscribe:
+		return true, nil
+	case jsonrpc.RPCTx:
+		return map[string]string{"txHash": "0xabcd"}, nil
+	case jsonrpc.RPCBundleSubmission:
+		return map[string]string{"bundleHash": "0x1234"}, nil
+	}
+	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: req.Method}
+}
+
+// request returns the params of the first request of the method and the auth header of its connection
+func (g *mockGateway) request(t *testing.T, method jsonrpc.RPCRequestType) (json.RawMessage, string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, req := range g.requests {
+		if req.Method == string(method) {
+			require.NotNil(t, req.Params)
+			return *req.Params, g.authHeaders[0]
+		}
+	}
+	t.Fatalf("%v was not requested", method)
+	return nil, ""
+}
+
+func startMockGateway(t *testing.T) (*mockGateway, string) {
+	gateway := &mockGateway{}
+	server := httptest.NewServer(gateway)
+	t.Cleanup(server.Close)
+	return gateway, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func runCommand(ctx context.Context, out *output, args ...string) error {
+	app := &cli.App{Name: "gateway", Commands: commands, Writer: out}
+	return app.RunContext(ctx, append([]string{"gateway"}, args...))
+}
+
+func TestCmdSub(t *testing.T) {
+	gateway, url := startMockGateway(t)
+	out := &output{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- runCommand(ctx, out, "sub", "--ws-url", url, "--auth-header", testAuthHeader,
+			"--feed", "pendingTxs", "--filters", "value > 1000", "--include", "tx_hash", "--include", "raw_tx")
+	}()
+
+	require.Eventually(t, func() bool { return out.String() != "" }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "{\"txHash\":\"0x01\"}\n", out.String())
+
+	params, authHeader := gateway.request(t, jsonrpc.RPCSubscribe)
+	assert.Equal(t, testAuthHeader, authHeader)
+	assert.JSONEq(t, `["pendingTxs", {"Include": ["tx_hash", "raw_tx"], "Filters": "value > 1000"}]`, string(params))
+
+	// the command streams until it is interrupted
+	cancel()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("sub did not return once interrupted")
+	}
+}
+
+func TestCmdTx(t *testing.T) {
+	gateway, url := startMockGateway(t)
+	out := &output{}
+
+	err := runCommand(context.Background(), out, "tx", "--ws-url", url, "--auth-header", testAuthHeader,
+		"--transaction", "f86b", "--validators-only", "--fallback", "5", "--front-running-protection")
+	require.NoError(t, err)
+	assert.Equal(t, "{\"txHash\":\"0xabcd\"}\n", out.String())
+
+	params, authHeader := gateway.request(t, jsonrpc.RPCTx)
+	assert.Equal(t, testAuthHeader, authHeader)
+	assert.JSONEq(t, `{
+		"transaction": "f86b",
+		"mev_bundle_tx": false,
+		"validators_only": true,
+		"next_validator": false,
+		"fall_back": 5,
+		"blockchain_network": "",
+		"original_sender_account_id": "",
+		"original_rpc_method": "",
+		"node_validation": false,
+		"front_running_protection": true
+	}`, string(params))
+}
+
+func TestCmdBundle(t *testing.T) {
+	gateway, url := startMockGateway(t)
+	file := filepath.Join(t.TempDir(), "bundle.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"transaction": ["f86b"], "block_number": "0x10d4f1a", "mev_builders": {"all": ""}}`), 0o600))
+	out := &output{}
+
+	err := runCommand(context.Background(), out, "bundle", "--ws-url", url, "--file", file)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"bundleHash\":\"0x1234\"}\n", out.String())
+
+	params, _ := gateway.request(t, jsonrpc.RPCBundleSubmission)
+	var bundle jsonrpc.RPCBundleSubmissionPayload
+	require.NoError(t, json.Unmarshal(params, &bundle))
+	assert.Equal(t, []string{"f86b"}, bundle.Transaction)
+	assert.Equal(t, "0x10d4f1a", bundle.BlockNumber)
+	assert.Equal(t, map[string]string{"all": ""}, bundle.MEVBuilders)
+
+	// an invalid bundle is rejected before it is submitted
+	require.NoError(t, os.WriteFile(file, []byte(`{"transaction": ["f86b"]}`), 0o600))
+	err = runCommand(context.Background(), &output{}, "bundle", "--ws-url", url, "--file", file)
+	require.ErrorContains(t, err, "bundle missing blockNumber")
+}
+
+// statusServer answers the Status calls of the status command, recording their auth header
+type statusServer struct {
+	pb.UnimplementedGatewayServer
+	authHeader chan string
+}
+
+func (s *statusServer) Status(ctx context.Context, _ *pb.StatusRequest) (*pb.StatusResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authHeader <- strings.Join(md.Get("authorization"), ",")
+	return &pb.StatusResponse{GatewayInfo: &pb.GatewayInfo{Version: "2.0.0", NodeId: "node"}}, nil
+}
+
+func TestCmdStatus(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	gateway := &statusServer{authHeader: make(chan string, 1)}
+	server := grpc.NewServer()
+	pb.RegisterGatewayServer(server, gateway)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+	out := &output{}
+
+	err = runCommand(context.Background(), out, "status", "--grpc-address", listener.Addr().String(), "--auth-header", testAuthHeader)
+	require.NoError(t, err)
+	assert.Equal(t, testAuthHeader, <-gateway.authHeader)
+
+	var status pb.StatusResponse
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &status))
+	require.NotNil(t, status.GatewayInfo)
+	assert.Equal(t, "2.0.0", status.GatewayInfo.Version)
+	assert.Equal(t, "node", status.GatewayInfo.NodeId)
+}
@@ -42,6 +42,7 @@ func main() {
 			utils.WSFlag,
 			utils.WSPortFlag,
 			utils.WSHostFlag,
+			utils.HTTPHostFlag,
 			utils.HTTPPortFlag,
 			utils.EnvFlag,
 			utils.LogLevelFlag,
@@ -102,8 +103,91 @@ func main() {
 			utils.BlocksToCacheWhileProposing,
 			utils.ProposingInterval,
 			utils.TxIncludeSenderInFeed,
+			utils.ClusterListenFlag,
+			utils.ClusterPeersFlag,
+			utils.ClusterPeerTimeoutFlag,
+			utils.ClusterSecretFlag,
+			utils.FeedLeaderElectionFlag,
+			utils.FeedLeaderLockDirFlag,
+			utils.FeedLeaderLeaseFlag,
+			utils.NodeTxRateLimitFlag,
+			utils.NodeTxBytesRateLimitFlag,
+			utils.NodeTxBurstFlag,
+			utils.NodeTxPoolCapacityFlag,
+			utils.NodeTxPoolThrottleThresholdFlag,
+			utils.NodeTxPoolPauseThresholdFlag,
+			utils.NodeTxPoolThrottledRateFlag,
+			utils.TxPoolReconciliationIntervalFlag,
+			utils.NodeRPCSlowThresholdFlag,
+			utils.AccountCacheTTLFlag,
+			utils.AccountCacheNegativeTTLFlag,
+			utils.AccountCacheMaxStaleFlag,
+			utils.DenylistFileFlag,
+			utils.FilterFilesDirFlag,
+			utils.AddressLabelsFileFlag,
+			utils.StandingSubscriptionsFileFlag,
+			utils.AccountAllowedContractsFileFlag,
+			utils.AccountTxDefaultsFileFlag,
+			utils.NetworkTxPolicyFileFlag,
+			utils.ProposerDutiesFlag,
+			utils.ProposerDutiesRelaysFlag,
+			utils.NTPServerFlag,
+			utils.CaptureFileFlag,
+			utils.CaptureFileSizeFlag,
+			utils.ValidateBDNTxsFlag,
+			utils.BDNTxValidationWorkersFlag,
+			utils.FeedPeersFlag,
+			utils.FeedPeerAuthHeaderFlag,
+			utils.FeedPeerTLSFlag,
+			utils.WSDrainWindowFlag,
+			utils.WSShutdownDeadlineFlag,
+			utils.LocalModeFlag,
+			utils.FeatureFlagsFlag,
+			utils.FeedExportDirFlag,
+			utils.FeedExportKeysFileFlag,
+			utils.FeedExportPublishedFlag,
+			utils.TxReceiptsFetchFlag,
+			utils.TxReceiptsRetryConcurrencyFlag,
+			utils.TxReceiptsRetryRateFlag,
+			utils.TrafficMirrorAccountIDFlag,
+			utils.TrafficMirrorFileFlag,
+			utils.TrafficMirrorRedactFlag,
+			utils.TrafficMirrorHashRedactedFlag,
+			utils.FeedAlertsFlag,
+			utils.FeedAlertIntervalFlag,
+			utils.FeedAlertWebhookFlag,
+			utils.CanaryPrivateKeyFlag,
+			utils.CanaryIntervalFlag,
+			utils.CanaryTimeoutFlag,
+			utils.FeedVerificationFlag,
+			utils.FeedVerificationRPCFlag,
+			utils.FeedVerificationThresholdFlag,
+			utils.FeedVerificationIntervalFlag,
+			utils.ClockSkewThresholdFlag,
+			utils.TopOfBlockTxsFlag,
+			utils.SecurityWebhookFlag,
+			utils.SecurityAuthFailuresFlag,
+			utils.SecurityEventWindowFlag,
+			utils.JWTJWKSURLFlag,
+			utils.JWTIssuerFlag,
+			utils.JWTAudienceFlag,
+			utils.JWTAccountClaimFlag,
+			utils.JWTRefreshIntervalFlag,
+			utils.TrustedProxiesFlag,
+			utils.ProxyProtocolFlag,
+			utils.RPCRateLimitFlag,
+			utils.OutboundProxyFlag,
+			utils.RelayDNSFlag,
+			utils.RelayDNSIntervalFlag,
+			utils.BlockchainHeartbeatTimeoutFlag,
+			utils.BlockBackfillMaxBlocksFlag,
+			utils.TxStorePersistenceFlag,
+			utils.BlockchainBridgeGRPCListenFlag,
+			utils.BlockchainBridgeGRPCConnectFlag,
+			utils.BridgeAdaptiveBufferCapFlag,
 		},
-		Action: runGateway,
+		Commands: commands,
+		Action:   runGateway,
 	}
 
 	err := app.Run(os.Args)
@@ -147,6 +231,22 @@ func runGateway(c *cli.Context) error {
 		return err
 	}
 
+	if address := c.String(utils.BlockchainBridgeGRPCConnectFlag.Name); address != "" {
+		group.Go(func() error {
+			return runBlockchainBackend(ctx, c, ethConfig, dataDir, address)
+		})
+		<-ctx.Done()
+
+		if pprofServer != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err = pprofServer.Shutdown(shutdownCtx); err != nil {
+				log.Errorf("error shutting down pprof server: %v", err)
+			}
+		}
+		return group.Wait()
+	}
+
 	var blockchainPeers []types.NodeEndpoint
 	var prysmEndpoint types.NodeEndpoint
 	var prysmAddr string
@@ -189,8 +289,56 @@ func runGateway(c *cli.Context) error {
 	startupBlockchainClient := startupBeaconAPIClients || startupBeaconNode || len(ethConfig.StaticEnodes()) > 0 || bxConfig.EnableDynamicPeers // if beacon node running we need to receive txs also
 	startupPrysmClient := bxConfig.GatewayMode.IsBDN() && prysmAddr != ""
 
+	bridgeListenAddress := c.String(utils.BlockchainBridgeGRPCListenFlag.Name)
+	if bridgeListenAddress != "" {
+		// the beacon clients would send their blocks to the other process rather than to the gateway
+		if startupBeaconNode || startupBeaconAPIClients || startupPrysmClient {
+			return fmt.Errorf("--%v supports the Ethereum p2p client only, the beacon clients cannot be used with it", utils.BlockchainBridgeGRPCListenFlag.Name)
+		}
+		startupBlockchainClient = false
+	}
+
+	if c.Bool(utils.ProposerDutiesFlag.Name) && !startupBeaconAPIClients {
+		return fmt.Errorf("--%v requires a beacon API endpoint, set with --beacon-api-uri or --multi-node", utils.ProposerDutiesFlag.Name)
+	}
+
 	// initialize bridge even if startupPrysmClient and startupBlockchainClient are false
-	bridge := blockchain.NewBxBridge(eth.Converter{}, startupBeaconNode || startupBeaconAPIClients)
+	var bridge blockchain.Bridge
+	if overflowCap := c.Int(utils.BridgeAdaptiveBufferCapFlag.Name); overflowCap > 0 {
+		bridge = blockchain.NewAdaptiveBxBridge(eth.Converter{}, startupBeaconNode || startupBeaconAPIClients, overflowCap)
+	} else {
+		bridge = blockchain.NewBxBridge(eth.Converter{}, startupBeaconNode || startupBeaconAPIClients)
+	}
+	if bridgeListenAddress != "" {
+		transport, err := blockchain.NewGRPCBridgeServerTransport(bridgeListenAddress)
+		if err != nil {
+			return err
+		}
+		remoteBridge := blockchain.NewRemoteBridge(eth.Converter{}, false, transport, blockchain.GobBridgeCodec{})
+		group.Go(func() error {
+			return remoteBridge.Run(ctx)
+		})
+		bridge = remoteBridge
+	}
+
+	// transactions from the BDN are shaped on the gateway side only, the blockchain clients read from the bridge as is
+	var gatewayBridge blockchain.Bridge = bridge
+	if bxConfig.NodeTxFlowShaping.Enabled() {
+		shapedBridge := blockchain.NewTxFlowShapedBridge(bridge, bxConfig.NodeTxFlowShaping, utils.RealClock{})
+		go shapedBridge.Run(ctx)
+		gatewayBridge = shapedBridge
+	}
+
+	// slots are counted from the beacon chain genesis, networks without a beacon chain only use the slot clock for its
+	// corrected time
+	var genesis time.Time
+	var slotDuration time.Duration
+	if ethConfig.GenesisTime != 0 {
+		genesis = time.Unix(int64(ethConfig.GenesisTime), 0)
+		slotDuration = bxgateway.NetworkToBlockDuration[blockchainNetwork]
+	}
+	slotClock := utils.NewSlotClock(utils.RealClock{}, genesis, slotDuration, c.String(utils.NTPServerFlag.Name), c.Duration(utils.ClockSkewThresholdFlag.Name))
+	go slotClock.Run(ctx, bxgateway.SlotClockSyncInterval)
 
 	if bxConfig.ManageWSServer && !bxConfig.WebsocketEnabled && !bxConfig.WebsocketTLSEnabled {
 		return fmt.Errorf("websocket server must be enabled using --ws or --ws-tls if --manage-ws-server is enabled")
@@ -198,7 +346,8 @@ func runGateway(c *cli.Context) error {
 	if bxConfig.EnableBlockchainRPC && !bxConfig.WebsocketEnabled && !bxConfig.WebsocketTLSEnabled {
 		return fmt.Errorf("websocket server must be enabled using --ws or --ws-tls if --enable-blockchain-rpc is used")
 	}
-	wsManager := eth.NewEthWSManager(ethConfig.StaticPeers, eth.NewWSProvider, bxgateway.WSProviderTimeout, bxConfig.EnableBlockchainRPC)
+	nodeRPCTimer := blockchain.NewRPCTimer(bxConfig.NodeRPCSlowThreshold, utils.RealClock{})
+	wsManager := eth.NewEthWSManager(ethConfig.StaticPeers, nodeRPCTimer.Wrap(eth.NewWSProvider), bxgateway.WSProviderTimeout, bxConfig.EnableBlockchainRPC)
 	if (bxConfig.WebsocketEnabled || bxConfig.WebsocketTLSEnabled) && !ethConfig.ValidWSAddr() {
 		log.Warn("websocket server enabled but no valid websockets endpoint specified via --eth-ws-uri nor --multi-node: only newTxs and bdnBlocks feeds are available")
 	}
@@ -212,7 +361,7 @@ func runGateway(c *cli.Context) error {
 	gateway, err := nodes.NewGateway(
 		ctx,
 		bxConfig,
-		bridge,
+		gatewayBridge,
 		wsManager,
 		blockchainPeers,
 		ethConfig.StaticPeers,
@@ -228,6 +377,8 @@ func runGateway(c *cli.Context) error {
 		c.Int(utils.BlocksToCacheWhileProposing.Name),
 		c.Duration(utils.ProposingInterval.Name),
 		c.Bool(utils.TxIncludeSenderInFeed.Name),
+		slotClock,
+		nodeRPCTimer,
 	)
 	if err != nil {
 		return err
@@ -254,18 +405,38 @@ func runGateway(c *cli.Context) error {
 
 		dialRatio := c.Int(utils.DialRatio.Name)
 
-		blockchainServer, err = eth.NewServerWithEthLogger(ctx, port, externalIP, ethConfig, ethChain, bridge, dataDir, wsManager, dynamicPeers, dialRatio, recommendedPeers)
-		if err != nil {
-			return err
-		}
+		newBlockchainServer := func(ctx context.Context) (*eth.Server, error) {
+			server, err := eth.NewServerWithEthLogger(ctx, port, externalIP, ethConfig, ethChain, bridge, dataDir, wsManager, dynamicPeers, dialRatio, recommendedPeers)
+			if err != nil {
+				return nil, err
+			}
 
-		if err = blockchainServer.AddEthLoggerFileHandler(bxConfig.Config.FileName); err != nil {
-			log.Warnf("skipping reconfiguration of eth p2p server logger due to error: %v", err)
+			if err = server.AddEthLoggerFileHandler(bxConfig.Config.FileName); err != nil {
+				log.Warnf("skipping reconfiguration of eth p2p server logger due to error: %v", err)
+			}
+			return server, nil
 		}
 
-		if err = blockchainServer.Start(); err != nil {
-			return err
+		if heartbeatTimeout := c.Duration(utils.BlockchainHeartbeatTimeoutFlag.Name); heartbeatTimeout > 0 {
+			// the supervisor stops its blockchain server when the context is done
+			supervisor := blockchain.NewSupervisor(bridge, func(ctx context.Context) (blockchain.SupervisedBackend, error) {
+				return newBlockchainServer(ctx)
+			}, heartbeatTimeout, utils.RealClock{})
+			group.Go(func() error {
+				return supervisor.Run(ctx)
+			})
+		} else {
+			blockchainServer, err = newBlockchainServer(ctx)
+			if err != nil {
+				return err
+			}
+
+			if err = blockchainServer.Start(); err != nil {
+				return err
+			}
 		}
+	} else if bridgeListenAddress != "" {
+		log.Infof("blockchain client runs in another process, listening for it on %v", bridgeListenAddress)
 	} else {
 		log.Infof("skipping starting blockchain client as no enodes have been provided")
 	}
@@ -308,6 +479,12 @@ func runGateway(c *cli.Context) error {
 		}
 	}
 
+	if startupBeaconAPIClients && c.Bool(utils.ProposerDutiesFlag.Name) {
+		proposerDutiesClient := beacon.NewProposerDutiesClient(ctx, httpclient.Client(nil), bridge, ethConfig.BeaconAPIEndpoints()[0],
+			c.StringSlice(utils.ProposerDutiesRelaysFlag.Name), slotClock)
+		proposerDutiesClient.Start()
+	}
+
 	if startupBeaconNode || startupBeaconAPIClients {
 		go beacon.HandleBDNBlocksBridge(ctx, bridge, beaconNode, beaconAPIClients)
 	}
@@ -349,6 +526,33 @@ func runGateway(c *cli.Context) error {
 	return group.Wait()
 }
 
+// runBlockchainBackend runs the Ethereum p2p client only, connected over gRPC to the bridge of a gateway running in
+// another process or host. It stops when the gateway closes the bridge, to be restarted with the gateway.
+func runBlockchainBackend(ctx context.Context, c *cli.Context, ethConfig *network.EthConfig, dataDir string, address string) error {
+	transport, err := blockchain.DialGRPCBridgeTransport(ctx, address)
+	if err != nil {
+		return err
+	}
+	bridge := blockchain.NewRemoteBridge(eth.Converter{}, false, transport, blockchain.GobBridgeCodec{})
+
+	// the dynamic peers are limited by the account of the gateway, which the backend does not know
+	wsManager := eth.NewEthWSManager(ethConfig.StaticPeers, eth.NewWSProvider, bxgateway.WSProviderTimeout, false)
+	server, err := eth.NewServerWithEthLogger(ctx, c.Int(utils.PortFlag.Name), net.ParseIP(c.String(utils.ExternalIPFlag.Name)), ethConfig,
+		eth.NewChain(ctx, ethConfig.IgnoreBlockTimeout), bridge, dataDir, wsManager, 0, c.Int(utils.DialRatio.Name), make(map[string]struct{}))
+	if err != nil {
+		_ = transport.Close()
+		return err
+	}
+	if err = server.Start(); err != nil {
+		_ = transport.Close()
+		return err
+	}
+	defer server.Stop()
+
+	log.Infof("blockchain client connected to the bridge of the gateway at %v", address)
+	return bridge.Run(ctx)
+}
+
 func downloadGenesisFile(network, genesisFilePath string) (string, error) {
 	var genesisFileURL string
 	switch network {
@@ -4,10 +4,21 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
+	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/bundle"
 	"github.com/urfave/cli/v2"
@@ -36,13 +47,21 @@ type Bx struct {
 	FluentDHost        string
 
 	Relays string
+	// RelayDNS is the name whose SRV or TXT records hold the relays, which replace --relays if it is set
+	RelayDNS         string
+	RelayDNSInterval time.Duration
 
 	WebsocketEnabled    bool
 	WebsocketTLSEnabled bool
 	WebsocketHost       string
 	WebsocketPort       int
 	ManageWSServer      bool
+	HTTPHost            string
 	HTTPPort            int
+	// TrustedProxies are the load balancers whose X-Forwarded-For and PROXY protocol headers hold the client address
+	TrustedProxies utils.TrustedProxies
+	// ProxyProtocol reads the PROXY protocol header of the connections from the trusted proxies
+	ProxyProtocol bool
 
 	BlocksOnly          bool
 	AllTransactions     bool
@@ -61,6 +80,84 @@ type Bx struct {
 	NoBlocks                     bool
 	NoStats                      bool
 
+	ClusterListenAddress string
+	ClusterPeers         []string
+	ClusterPeerTimeout   time.Duration
+	ClusterSecret        string
+
+	FeedLeaderElection string
+	FeedLeaderLockDir  string
+	FeedLeaderLease    time.Duration
+
+	NodeTxFlowShaping blockchain.TxFlowShapingConfig
+	NodeTxPoolMonitor blockchain.TxPoolMonitorConfig
+
+	TxPoolReconciliationInterval time.Duration
+
+	// NodeRPCSlowThreshold is the duration above which the RPC calls to the node are logged, 0 disables the logs
+	NodeRPCSlowThreshold time.Duration
+
+	DenylistFile string
+
+	FilterFilesDir string
+
+	AddressLabelsFile string
+
+	StandingSubscriptionsFile string
+
+	CaptureFile     string
+	CaptureFileSize int64
+
+	ValidateBDNTxs         bool
+	BDNTxValidationWorkers int
+
+	FeedPeers          []string
+	FeedPeerAuthHeader string
+	FeedPeerTLS        bool
+
+	WSDrainWindow      time.Duration
+	WSShutdownDeadline time.Duration
+
+	LocalMode bool
+
+	FeatureFlags map[string]bool
+
+	FeedExport export.Config
+	// TrafficMirror duplicates the traffic of a single account, it is disabled if no account is set
+	TrafficMirror export.MirrorConfig
+
+	FeedAlerts services.FeedAlertsConfig
+	Canary     blockchain.CanaryConfig
+	// FeedVerification compares the blocks from the BDN with secondary sources, it is disabled unless enabled
+	FeedVerification blockchain.FeedVerificationConfig
+	// SecurityEvents are posted to the webhooks of the accounts, they are disabled if no webhook is set
+	SecurityEvents services.SecurityEventsConfig
+	// JWT authenticates the clients with the bearer tokens of an identity provider, it is disabled if no JWKS URL is set
+	JWT services.JWTConfig
+	// OutboundProxy routes the SDN and relay connections through proxies by destination, they are direct if it is empty
+	OutboundProxy []connections.ProxyRule
+	// RPCRateLimits override the default rate limits of the calls of the accounts by tier
+	RPCRateLimits RPCRateLimits
+	// AccountCache caches the account models of the clients connecting with a foreign account
+	AccountCache services.AccountCacheConfig
+
+	// TxReceiptsFetch tunes the receipt fetching of the txReceipts and contractCreations feeds from each node
+	TxReceiptsFetch blockchain.ReceiptFetchConfigs
+
+	// TopOfBlockTxs is the number of transactions at the top of each block published to the topOfBlock feed
+	TopOfBlockTxs int
+	// BlockBackfillMaxBlocks is the largest gap of block heights from the node which is backfilled, 0 disables the backfill
+	BlockBackfillMaxBlocks int
+	// TxStorePersistence persists the transactions of the TxStore so their short IDs are recovered after a restart
+	TxStorePersistence bool
+
+	Settings Settings
+
+	AccountAllowedContracts map[types.AccountID][]string
+	AccountTxDefaults       map[types.AccountID]sdnmessage.TxDefaults
+	// TxPolicy is the policy of the flags of the txs submitted on the network of the gateway
+	TxPolicy TxPolicy
+
 	*GRPC
 	*Env
 	*logger.Config
@@ -106,6 +203,87 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		}
 	}
 
+	var accountAllowedContracts map[types.AccountID][]string
+	if ctx.IsSet(utils.AccountAllowedContractsFileFlag.Name) {
+		contents, err := os.ReadFile(ctx.String(utils.AccountAllowedContractsFileFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open account allowed contracts file: %s", err)
+		}
+
+		if err := json.Unmarshal(contents, &accountAllowedContracts); err != nil {
+			return nil, fmt.Errorf("failed to decode account allowed contracts file: %s", err)
+		}
+	}
+
+	var accountTxDefaults map[types.AccountID]sdnmessage.TxDefaults
+	if ctx.IsSet(utils.AccountTxDefaultsFileFlag.Name) {
+		contents, err := os.ReadFile(ctx.String(utils.AccountTxDefaultsFileFlag.Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open account tx defaults file: %s", err)
+		}
+
+		if err := json.Unmarshal(contents, &accountTxDefaults); err != nil {
+			return nil, fmt.Errorf("failed to decode account tx defaults file: %s", err)
+		}
+	}
+
+	var txPolicy TxPolicy
+	if ctx.IsSet(utils.NetworkTxPolicyFileFlag.Name) {
+		txPolicy, err = LoadTxPolicy(ctx.String(utils.NetworkTxPolicyFileFlag.Name), ctx.String(utils.BlockchainNetworkFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	featureFlags := make(map[string]bool)
+	for _, featureFlag := range ctx.StringSlice(utils.FeatureFlagsFlag.Name) {
+		name, value, found := strings.Cut(featureFlag, "=")
+		enabled := true
+		if found {
+			if enabled, err = strconv.ParseBool(value); err != nil {
+				return nil, fmt.Errorf("invalid --feature-flags value %v, expected name=true or name=false", featureFlag)
+			}
+		}
+		featureFlags[name] = enabled
+	}
+
+	feedRateBounds, err := services.ParseFeedRateBounds(ctx.StringSlice(utils.FeedAlertsFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --feed-alerts: %v", err)
+	}
+
+	txReceiptsFetch, err := blockchain.ParseReceiptFetchConfigs(ctx.StringSlice(utils.TxReceiptsFetchFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tx-receipts-fetch: %v", err)
+	}
+	txReceiptsFetch.Retry = blockchain.ReceiptRetryConfig{
+		Concurrency: ctx.Int(utils.TxReceiptsRetryConcurrencyFlag.Name),
+		RatePerNode: ctx.Uint64(utils.TxReceiptsRetryRateFlag.Name),
+	}
+	if txReceiptsFetch.Retry.Concurrency <= 0 || txReceiptsFetch.Retry.RatePerNode == 0 {
+		return nil, errors.New("--tx-receipts-retry-concurrency and --tx-receipts-retry-rate must be greater than 0")
+	}
+
+	trustedProxies, err := utils.ParseTrustedProxies(ctx.StringSlice(utils.TrustedProxiesFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --trusted-proxies: %v", err)
+	}
+
+	outboundProxy, err := connections.ParseProxyRules(ctx.StringSlice(utils.OutboundProxyFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --outbound-proxy: %v", err)
+	}
+
+	rpcRateLimits, err := ParseRPCRateLimits(ctx.StringSlice(utils.RPCRateLimitFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --rpc-rate-limit: %v", err)
+	}
+
+	securityWebhooks, defaultSecurityWebhook, err := services.ParseSecurityWebhooks(ctx.StringSlice(utils.SecurityWebhookFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --security-webhook: %v", err)
+	}
+
 	bxConfig := &Bx{
 		Host:               ctx.String(utils.HostFlag.Name),
 		OverrideExternalIP: ctx.IsSet(utils.ExternalIPFlag.Name),
@@ -114,6 +292,8 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		BlockchainNetwork:  ctx.String(utils.BlockchainNetworkFlag.Name),
 		PrioritySending:    !ctx.Bool(utils.AvoidPrioritySendingFlag.Name),
 		Relays:             ctx.String(utils.RelayHostsFlag.Name),
+		RelayDNS:           ctx.String(utils.RelayDNSFlag.Name),
+		RelayDNSInterval:   ctx.Duration(utils.RelayDNSIntervalFlag.Name),
 		NodeType:           nodeType,
 		GatewayMode:        gatewayMode,
 		LogNetworkContent:  ctx.Bool(utils.LogNetworkContentFlag.Name),
@@ -126,8 +306,12 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		WebsocketPort:       ctx.Int(utils.WSPortFlag.Name),
 		ManageWSServer:      ctx.Bool(utils.ManageWSServer.Name),
 
+		HTTPHost: ctx.String(utils.HTTPHostFlag.Name),
 		HTTPPort: ctx.Int(utils.HTTPPortFlag.Name),
 
+		TrustedProxies: trustedProxies,
+		ProxyProtocol:  ctx.Bool(utils.ProxyProtocolFlag.Name),
+
 		BlocksOnly:       ctx.Bool(utils.BlocksOnlyFlag.Name),
 		SendConfirmation: ctx.Bool(utils.SendBlockConfirmation.Name),
 		AllTransactions:  ctx.Bool(utils.AllTransactionsFlag.Name),
@@ -145,19 +329,301 @@ func NewBxFromCLI(ctx *cli.Context) (*Bx, error) {
 		NoBlocks:                   ctx.Bool(utils.NoBlocks.Name),
 		NoStats:                    ctx.Bool(utils.NoStats.Name),
 
+		ClusterListenAddress: ctx.String(utils.ClusterListenFlag.Name),
+		ClusterPeers:         ctx.StringSlice(utils.ClusterPeersFlag.Name),
+		ClusterPeerTimeout:   ctx.Duration(utils.ClusterPeerTimeoutFlag.Name),
+		ClusterSecret:        ctx.String(utils.ClusterSecretFlag.Name),
+
+		FeedLeaderElection: ctx.String(utils.FeedLeaderElectionFlag.Name),
+		FeedLeaderLockDir:  ctx.String(utils.FeedLeaderLockDirFlag.Name),
+		FeedLeaderLease:    ctx.Duration(utils.FeedLeaderLeaseFlag.Name),
+
+		NodeTxFlowShaping: blockchain.TxFlowShapingConfig{
+			TxsPerSecond:   ctx.Float64(utils.NodeTxRateLimitFlag.Name),
+			BytesPerSecond: ctx.Float64(utils.NodeTxBytesRateLimitFlag.Name),
+			Burst:          ctx.Duration(utils.NodeTxBurstFlag.Name),
+		},
+		NodeTxPoolMonitor: blockchain.TxPoolMonitorConfig{
+			Capacity:              ctx.Uint64(utils.NodeTxPoolCapacityFlag.Name),
+			ThrottleThreshold:     ctx.Float64(utils.NodeTxPoolThrottleThresholdFlag.Name),
+			PauseThreshold:        ctx.Float64(utils.NodeTxPoolPauseThresholdFlag.Name),
+			ThrottledTxsPerSecond: ctx.Int(utils.NodeTxPoolThrottledRateFlag.Name),
+			Interval:              bxgateway.NodeTxPoolStatusInterval,
+		},
+
+		TxPoolReconciliationInterval: ctx.Duration(utils.TxPoolReconciliationIntervalFlag.Name),
+
+		NodeRPCSlowThreshold: ctx.Duration(utils.NodeRPCSlowThresholdFlag.Name),
+
+		DenylistFile: ctx.String(utils.DenylistFileFlag.Name),
+
+		FilterFilesDir: ctx.String(utils.FilterFilesDirFlag.Name),
+
+		AddressLabelsFile: ctx.String(utils.AddressLabelsFileFlag.Name),
+
+		StandingSubscriptionsFile: ctx.String(utils.StandingSubscriptionsFileFlag.Name),
+
+		CaptureFile:     ctx.String(utils.CaptureFileFlag.Name),
+		CaptureFileSize: int64(ctx.Int(utils.CaptureFileSizeFlag.Name)) * 1024 * 1024,
+
+		ValidateBDNTxs:         ctx.Bool(utils.ValidateBDNTxsFlag.Name),
+		BDNTxValidationWorkers: ctx.Int(utils.BDNTxValidationWorkersFlag.Name),
+
+		FeedPeers:          ctx.StringSlice(utils.FeedPeersFlag.Name),
+		FeedPeerAuthHeader: ctx.String(utils.FeedPeerAuthHeaderFlag.Name),
+		FeedPeerTLS:        ctx.Bool(utils.FeedPeerTLSFlag.Name),
+
+		WSDrainWindow:      ctx.Duration(utils.WSDrainWindowFlag.Name),
+		WSShutdownDeadline: ctx.Duration(utils.WSShutdownDeadlineFlag.Name),
+
+		LocalMode: ctx.Bool(utils.LocalModeFlag.Name),
+
+		FeatureFlags: featureFlags,
+
+		FeedExport: export.Config{
+			Dir:       ctx.String(utils.FeedExportDirFlag.Name),
+			Published: ctx.Bool(utils.FeedExportPublishedFlag.Name),
+		},
+		TrafficMirror: export.MirrorConfig{
+			AccountID:    types.AccountID(ctx.String(utils.TrafficMirrorAccountIDFlag.Name)),
+			File:         ctx.String(utils.TrafficMirrorFileFlag.Name),
+			Redact:       ctx.StringSlice(utils.TrafficMirrorRedactFlag.Name),
+			HashRedacted: ctx.Bool(utils.TrafficMirrorHashRedactedFlag.Name),
+		},
+
+		TxReceiptsFetch: txReceiptsFetch,
+
+		AccountCache: services.AccountCacheConfig{
+			TTL:         ctx.Duration(utils.AccountCacheTTLFlag.Name),
+			NegativeTTL: ctx.Duration(utils.AccountCacheNegativeTTLFlag.Name),
+			MaxStale:    ctx.Duration(utils.AccountCacheMaxStaleFlag.Name),
+		},
+
+		FeedAlerts: services.FeedAlertsConfig{
+			Bounds:   feedRateBounds,
+			Interval: ctx.Duration(utils.FeedAlertIntervalFlag.Name),
+			Webhook:  ctx.String(utils.FeedAlertWebhookFlag.Name),
+		},
+		Canary: blockchain.CanaryConfig{
+			PrivateKey: strings.TrimPrefix(ctx.String(utils.CanaryPrivateKeyFlag.Name), "0x"),
+			Interval:   ctx.Duration(utils.CanaryIntervalFlag.Name),
+			Timeout:    ctx.Duration(utils.CanaryTimeoutFlag.Name),
+		},
+		FeedVerification: blockchain.FeedVerificationConfig{
+			Enabled:   ctx.Bool(utils.FeedVerificationFlag.Name),
+			PublicRPC: ctx.String(utils.FeedVerificationRPCFlag.Name),
+			Threshold: ctx.Uint64(utils.FeedVerificationThresholdFlag.Name),
+			Interval:  ctx.Duration(utils.FeedVerificationIntervalFlag.Name),
+			Webhook:   ctx.String(utils.FeedAlertWebhookFlag.Name),
+		},
+		JWT: services.JWTConfig{
+			JWKSURL:         ctx.String(utils.JWTJWKSURLFlag.Name),
+			Issuer:          ctx.String(utils.JWTIssuerFlag.Name),
+			Audience:        ctx.String(utils.JWTAudienceFlag.Name),
+			AccountClaim:    ctx.String(utils.JWTAccountClaimFlag.Name),
+			RefreshInterval: ctx.Duration(utils.JWTRefreshIntervalFlag.Name),
+		},
+		OutboundProxy: outboundProxy,
+		RPCRateLimits: rpcRateLimits,
+		SecurityEvents: services.SecurityEventsConfig{
+			Webhooks:       securityWebhooks,
+			DefaultWebhook: defaultSecurityWebhook,
+			AuthFailures:   ctx.Int(utils.SecurityAuthFailuresFlag.Name),
+			Window:         ctx.Duration(utils.SecurityEventWindowFlag.Name),
+		},
+
+		TopOfBlockTxs:          ctx.Int(utils.TopOfBlockTxsFlag.Name),
+		BlockBackfillMaxBlocks: ctx.Int(utils.BlockBackfillMaxBlocksFlag.Name),
+		TxStorePersistence:     ctx.Bool(utils.TxStorePersistenceFlag.Name),
+
+		Settings: NewSettingsFromCLI(ctx),
+
+		AccountAllowedContracts: accountAllowedContracts,
+		AccountTxDefaults:       accountTxDefaults,
+		TxPolicy:                txPolicy,
+
 		GRPC:       grpcConfig,
 		Env:        env,
 		Config:     log,
 		TxTraceLog: txTraceLog,
 	}
 
+	// settings loaded from files, only the builder names are reported as their endpoints may embed credentials
+	if mevBuilders != nil {
+		builders := make([]string, 0, len(mevBuilders))
+		for name := range mevBuilders {
+			builders = append(builders, name)
+		}
+		sort.Strings(builders)
+		bxConfig.Settings.Set("mev-builders", Setting{Value: builders, Source: SourceFile})
+	}
+	if accountAllowedContracts != nil {
+		bxConfig.Settings.Set("account-allowed-contracts", Setting{Value: accountAllowedContracts, Source: SourceFile})
+	}
+	if accountTxDefaults != nil {
+		bxConfig.Settings.Set("account-tx-defaults", Setting{Value: accountTxDefaults, Source: SourceFile})
+	}
+	if ctx.IsSet(utils.NetworkTxPolicyFileFlag.Name) {
+		bxConfig.Settings.Set("network-tx-policy", Setting{Value: txPolicy, Source: SourceFile})
+	}
+
 	if bxConfig.BlocksOnly && bxConfig.AllTransactions {
 		return bxConfig, errors.New("cannot set both --blocks-only and --all-txs")
 	}
 
+	if len(bxConfig.ClusterPeers) > 0 && bxConfig.ClusterListenAddress == "" {
+		return bxConfig, errors.New("--cluster-listen must be set if --cluster-peers is provided")
+	}
+	if len(bxConfig.ClusterPeers) > 0 && bxConfig.ClusterSecret == "" {
+		return bxConfig, errors.New("--cluster-secret must be set if --cluster-peers is provided")
+	}
+	// the cluster and the leader election only deduplicate the published export, subscriptions are always served
+	if (len(bxConfig.ClusterPeers) > 0 || bxConfig.FeedLeaderElection != "") && !bxConfig.FeedExport.Published {
+		return bxConfig, errors.New("--feed-export-published must be set if --cluster-peers or --feed-leader-election is provided")
+	}
+	if bxConfig.FeedExport.Published && bxConfig.FeedExport.Dir == "" {
+		return bxConfig, errors.New("--feed-export-dir must be set if --feed-export-published is provided")
+	}
+
+	switch bxConfig.FeedLeaderElection {
+	case "":
+	case "cluster":
+		if len(bxConfig.ClusterPeers) == 0 {
+			return bxConfig, errors.New("--cluster-peers must be set if --feed-leader-election is cluster")
+		}
+	case "lockfile":
+		if bxConfig.FeedLeaderLockDir == "" {
+			return bxConfig, errors.New("--feed-leader-lock-dir must be set if --feed-leader-election is lockfile")
+		}
+	default:
+		return bxConfig, fmt.Errorf("unsupported --feed-leader-election %v, possible values are: cluster, lockfile", bxConfig.FeedLeaderElection)
+	}
+
+	if bxConfig.NodeTxFlowShaping.TxsPerSecond < 0 || bxConfig.NodeTxFlowShaping.BytesPerSecond < 0 {
+		return bxConfig, errors.New("--node-tx-rate-limit and --node-tx-bytes-rate-limit cannot be negative")
+	}
+
+	txPoolMonitor := bxConfig.NodeTxPoolMonitor
+	if txPoolMonitor.ThrottleThreshold <= 0 || txPoolMonitor.ThrottleThreshold > txPoolMonitor.PauseThreshold || txPoolMonitor.PauseThreshold > 1 {
+		return bxConfig, errors.New("--node-txpool-throttle-threshold and --node-txpool-pause-threshold must satisfy 0 < throttle <= pause <= 1")
+	}
+
+	if bxConfig.ValidateBDNTxs && bxConfig.BDNTxValidationWorkers <= 0 {
+		return bxConfig, errors.New("--bdn-tx-validation-workers must be positive if --validate-bdn-txs is set")
+	}
+
+	if bxConfig.WSDrainWindow < 0 {
+		return bxConfig, errors.New("--ws-drain-window cannot be negative")
+	}
+
+	if bxConfig.WSShutdownDeadline < 0 {
+		return bxConfig, errors.New("--ws-shutdown-deadline cannot be negative")
+	}
+
+	if ctx.IsSet(utils.FeedExportKeysFileFlag.Name) {
+		if bxConfig.FeedExport.Dir == "" {
+			return bxConfig, errors.New("--feed-export-dir must be set if --feed-export-keys-file is provided")
+		}
+		keyring, err := export.LoadKeyring(ctx.String(utils.FeedExportKeysFileFlag.Name))
+		if err != nil {
+			return bxConfig, err
+		}
+		bxConfig.FeedExport.Keys = keyring
+	}
+
+	if bxConfig.TrafficMirror.AccountID != "" && bxConfig.TrafficMirror.File == "" {
+		return bxConfig, errors.New("--traffic-mirror-file must be set if --traffic-mirror-account-id is provided")
+	}
+
+	if len(bxConfig.FeedAlerts.Bounds) > 0 && bxConfig.FeedAlerts.Interval <= 0 {
+		return bxConfig, errors.New("--feed-alert-interval must be positive if --feed-alerts is set")
+	}
+
+	if bxConfig.Canary.PrivateKey != "" {
+		if _, ok := canaryNetworks[bxConfig.BlockchainNetwork]; !ok {
+			return bxConfig, fmt.Errorf("--canary-private-key cannot be used on %v, canary transactions are only sent on testnets", bxConfig.BlockchainNetwork)
+		}
+		if bxConfig.Canary.Interval <= 0 || bxConfig.Canary.Timeout <= 0 {
+			return bxConfig, errors.New("--canary-interval and --canary-timeout must be positive if --canary-private-key is set")
+		}
+	}
+
+	if bxConfig.FeedVerification.PublicRPC != "" && !bxConfig.FeedVerification.Enabled {
+		return bxConfig, errors.New("--feed-verification-rpc requires --feed-verification")
+	}
+	if bxConfig.FeedVerification.Enabled && bxConfig.FeedVerification.Interval <= 0 {
+		return bxConfig, errors.New("--feed-verification-interval must be positive if --feed-verification is set")
+	}
+
+	if (len(bxConfig.SecurityEvents.Webhooks) > 0 || bxConfig.SecurityEvents.DefaultWebhook != "") &&
+		(bxConfig.SecurityEvents.AuthFailures <= 0 || bxConfig.SecurityEvents.Window <= 0) {
+		return bxConfig, errors.New("--security-auth-failures and --security-event-window must be positive if --security-webhook is set")
+	}
+
+	if bxConfig.JWT.JWKSURL != "" {
+		if !strings.HasPrefix(bxConfig.JWT.JWKSURL, "https://") && !strings.HasPrefix(bxConfig.JWT.JWKSURL, "http://") {
+			return bxConfig, fmt.Errorf("--jwt-jwks-url must be an http or https URL, got %v", bxConfig.JWT.JWKSURL)
+		}
+		if bxConfig.JWT.AccountClaim == "" || bxConfig.JWT.RefreshInterval <= 0 {
+			return bxConfig, errors.New("--jwt-account-claim must be set and --jwt-refresh-interval must be positive if --jwt-jwks-url is set")
+		}
+	}
+
+	if bxConfig.RelayDNS != "" {
+		if ctx.IsSet(utils.RelayHostsFlag.Name) {
+			return bxConfig, errors.New("--relay-dns cannot be used with --relays")
+		}
+		if bxConfig.RelayDNSInterval <= 0 {
+			return bxConfig, errors.New("--relay-dns-interval must be positive if --relay-dns is set")
+		}
+	}
+
+	if bxConfig.ProxyProtocol && len(bxConfig.TrustedProxies) == 0 {
+		return bxConfig, errors.New("--proxy-protocol requires --trusted-proxies")
+	}
+
+	// the auth header would be sent in plaintext to the peers
+	if bxConfig.FeedPeerAuthHeader != "" && !bxConfig.FeedPeerTLS {
+		for _, peer := range bxConfig.FeedPeers {
+			host, _, err := net.SplitHostPort(peer)
+			if err != nil || !isLoopback(host) {
+				return bxConfig, fmt.Errorf("--feed-peer-auth-header requires --feed-peer-tls to subscribe to %v, which is not a loopback address", peer)
+			}
+		}
+	}
+
+	if bxConfig.LocalMode && bxConfig.WebsocketTLSEnabled {
+		return bxConfig, errors.New("--local-mode cannot be used with --ws-tls, there are no certificates without the SDN")
+	}
+	// clients are not authorized in local mode, the servers must not be reachable from other hosts
+	if bxConfig.LocalMode && bxConfig.WebsocketEnabled && !isLoopback(bxConfig.WebsocketHost) {
+		return bxConfig, fmt.Errorf("--local-mode requires --ws-host to be a loopback address, got %v", bxConfig.WebsocketHost)
+	}
+	if bxConfig.LocalMode && bxConfig.GRPC.Enabled && !isLoopback(bxConfig.GRPC.Host) {
+		return bxConfig, fmt.Errorf("--local-mode requires --grpc-host to be a loopback address, got %v", bxConfig.GRPC.Host)
+	}
+
 	return bxConfig, nil
 }
 
+// canaryNetworks are the testnets canary transactions can be sent on, each canary costs the gas of a transfer
+var canaryNetworks = map[string]struct{}{
+	bxgateway.Goerli:        {},
+	bxgateway.Zhejiang:      {},
+	bxgateway.Ropsten:       {},
+	bxgateway.BSCTestnet:    {},
+	bxgateway.PolygonMumbai: {},
+}
+
+// isLoopback returns true if the host is localhost or a loopback IP address
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // GRPC represents Go RPC configuration details
 type GRPC struct {
 	Enabled     bool
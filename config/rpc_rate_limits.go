@@ -0,0 +1,60 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+)
+
+// RateLimitedMethod is a kind of client call rate limited per account
+type RateLimitedMethod string
+
+// RateLimitedMethod types enumeration
+const (
+	RateLimitTx        RateLimitedMethod = "blxr_tx"
+	RateLimitBatchTx   RateLimitedMethod = "blxr_batch_tx"
+	RateLimitSubscribe RateLimitedMethod = "subscribe"
+	// RateLimitOnBlockCall counts each call registered by an onBlock subscription
+	RateLimitOnBlockCall RateLimitedMethod = "onblock_call"
+)
+
+var rateLimitedMethods = map[RateLimitedMethod]struct{}{
+	RateLimitTx:          {},
+	RateLimitBatchTx:     {},
+	RateLimitSubscribe:   {},
+	RateLimitOnBlockCall: {},
+}
+
+// RPCRateLimits are the calls per second allowed to each account by account tier and method, 0 means no limit
+type RPCRateLimits map[sdnmessage.AccountTier]map[RateLimitedMethod]int
+
+// ParseRPCRateLimits parses tier:method=calls-per-second rate limits
+func ParseRPCRateLimits(values []string) (RPCRateLimits, error) {
+	limits := make(RPCRateLimits)
+	for _, value := range values {
+		tierMethod, rate, found := strings.Cut(value, "=")
+		tier, method, foundMethod := strings.Cut(tierMethod, ":")
+		if !found || !foundMethod {
+			return nil, fmt.Errorf("invalid rate limit %v, expected tier:method=calls-per-second", value)
+		}
+		if err := sdnmessage.AccountTier(tier).IsValid(); err != nil {
+			return nil, fmt.Errorf("invalid rate limit %v: %v", value, err)
+		}
+		if _, ok := rateLimitedMethods[RateLimitedMethod(method)]; !ok {
+			return nil, fmt.Errorf("invalid rate limit %v, the method must be one of %v, %v, %v or %v", value,
+				RateLimitTx, RateLimitBatchTx, RateLimitSubscribe, RateLimitOnBlockCall)
+		}
+		callsPerSecond, err := strconv.Atoi(rate)
+		if err != nil || callsPerSecond < 0 {
+			return nil, fmt.Errorf("invalid rate limit %v, the calls per second must be a non-negative integer", value)
+		}
+
+		if _, ok := limits[sdnmessage.AccountTier(tier)]; !ok {
+			limits[sdnmessage.AccountTier(tier)] = make(map[RateLimitedMethod]int)
+		}
+		limits[sdnmessage.AccountTier(tier)][RateLimitedMethod(method)] = callsPerSecond
+	}
+	return limits, nil
+}
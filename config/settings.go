@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// SettingSource represents where the effective value of a setting comes from
+type SettingSource string
+
+// SettingSource types enumeration
+const (
+	SourceDefault SettingSource = "default"
+	SourceFlag    SettingSource = "flag"
+	SourceEnv     SettingSource = "env"
+	SourceFile    SettingSource = "file"
+	SourceSDN     SettingSource = "sdn"
+)
+
+// redactedValue replaces the value of secret settings
+const redactedValue = "<redacted>"
+
+// secretSettings are the parts of setting names holding credentials, their values are redacted. The endpoints of
+// third party services are redacted too, their URLs commonly embed API keys.
+var secretSettings = []string{
+	"password", "auth-header", "secret", "dbdsn", "private-key",
+	"webhook", "endpoint", "mev-relay-url", "eth-ws-uri", "beacon-api-uri", "prysm-grpc-uri", "multi-node",
+}
+
+// Setting is the effective value of a setting, with its default and where it comes from
+type Setting struct {
+	Value   interface{}   `json:"value"`
+	Default string        `json:"default,omitempty"`
+	Source  SettingSource `json:"source"`
+}
+
+// Settings are the effective runtime settings by name, secrets redacted
+type Settings map[string]Setting
+
+// NewSettingsFromCLI returns the effective value and the source of each flag of the app
+func NewSettingsFromCLI(ctx *cli.Context) Settings {
+	settings := make(Settings)
+	if ctx.App == nil {
+		return settings
+	}
+
+	for _, flag := range ctx.App.Flags {
+		name := flag.Names()[0]
+
+		setting := Setting{Value: flagValue(ctx, flag), Source: SourceDefault}
+		if docFlag, ok := flag.(cli.DocGenerationFlag); ok {
+			setting.Default = unquote(docFlag.GetDefaultText())
+		}
+
+		switch {
+		case flag.IsSet():
+			// the flag itself is only set when its value was read from the environment or a file
+			setting.Source = SourceFile
+			if docFlag, ok := flag.(cli.DocGenerationFlag); ok && envSet(docFlag.GetEnvVars()) {
+				setting.Source = SourceEnv
+			}
+		case ctx.IsSet(name):
+			setting.Source = SourceFlag
+		}
+
+		settings.Set(name, setting)
+	}
+	return settings
+}
+
+// Set adds the setting, redacting its value if it is a secret
+func (s Settings) Set(name string, setting Setting) {
+	if isSecretSetting(name) {
+		if !isEmptyValue(setting.Value) {
+			setting.Value = redactedValue
+		}
+		if setting.Default != "" {
+			setting.Default = redactedValue
+		}
+	}
+	s[name] = setting
+}
+
+// Diff returns the settings which do not have their default value
+func (s Settings) Diff() Settings {
+	diff := make(Settings)
+	for name, setting := range s {
+		if setting.Source != SourceDefault {
+			diff[name] = setting
+		}
+	}
+	return diff
+}
+
+// Clone returns a copy of the settings, so settings can be added without changing the configuration
+func (s Settings) Clone() Settings {
+	clone := make(Settings, len(s))
+	for name, setting := range s {
+		clone[name] = setting
+	}
+	return clone
+}
+
+func flagValue(ctx *cli.Context, flag cli.Flag) interface{} {
+	name := flag.Names()[0]
+	switch flag.(type) {
+	case *cli.StringSliceFlag:
+		return ctx.StringSlice(name)
+	case *cli.IntSliceFlag:
+		return ctx.IntSlice(name)
+	case *cli.Int64SliceFlag:
+		return ctx.Int64Slice(name)
+	case *cli.Float64SliceFlag:
+		return ctx.Float64Slice(name)
+	}
+
+	value := ctx.Value(name)
+	if duration, ok := value.(time.Duration); ok {
+		return duration.String()
+	}
+	return value
+}
+
+func isSecretSetting(name string) bool {
+	for _, secret := range secretSettings {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+func envSet(envVars []string) bool {
+	for _, envVar := range envVars {
+		if _, ok := os.LookupEnv(envVar); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func unquote(s string) string {
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+	return s
+}
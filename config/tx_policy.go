@@ -0,0 +1,104 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TxFlag names a flag of the submitted txs, as in the blxr_tx requests
+type TxFlag string
+
+// TxFlag types enumeration
+const (
+	TxFlagValidatorsOnly         TxFlag = "validators_only"
+	TxFlagNextValidator          TxFlag = "next_validator"
+	TxFlagFrontRunningProtection TxFlag = "front_running_protection"
+	TxFlagNodeValidation         TxFlag = "node_validation"
+)
+
+var txFlags = map[TxFlag]struct{}{
+	TxFlagValidatorsOnly:         {},
+	TxFlagNextValidator:          {},
+	TxFlagFrontRunningProtection: {},
+	TxFlagNodeValidation:         {},
+}
+
+// TxPolicy is how the gateway handles the flags of the txs submitted on its network, the zero value keeps the flags
+// of the requests
+type TxPolicy struct {
+	// ValidatorsOnly is applied to the txs whose request omits validators_only and whose account has no default
+	ValidatorsOnly bool `json:"validators_only"`
+	// DeliverToNode sends the submitted txs without validators_only or next_validator to the node, true if not set
+	DeliverToNode *bool `json:"deliver_to_node"`
+	// ForbiddenFlags are the flags the txs are rejected with, e.g. next_validator on a deployment without validators
+	ForbiddenFlags []TxFlag `json:"forbidden_flags"`
+}
+
+// LoadTxPolicy reads the policy of the network from a JSON file mapping blockchain networks to their TxPolicy, a
+// network missing from the file has the zero policy
+func LoadTxPolicy(path string, network string) (TxPolicy, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return TxPolicy{}, fmt.Errorf("failed to open network tx policy file: %v", err)
+	}
+	var policies map[string]TxPolicy
+	if err = json.Unmarshal(contents, &policies); err != nil {
+		return TxPolicy{}, fmt.Errorf("failed to decode network tx policy file: %v", err)
+	}
+	for policyNetwork, policy := range policies {
+		for _, flag := range policy.ForbiddenFlags {
+			if _, ok := txFlags[flag]; !ok {
+				return TxPolicy{}, fmt.Errorf("invalid forbidden flag %v of network %v, must be one of %v, %v, %v or %v",
+					flag, policyNetwork, TxFlagValidatorsOnly, TxFlagNextValidator, TxFlagFrontRunningProtection, TxFlagNodeValidation)
+			}
+		}
+		if policy.ValidatorsOnly && policy.Forbids(TxFlagValidatorsOnly) {
+			return TxPolicy{}, fmt.Errorf("validators_only of network %v cannot be both the default and forbidden", policyNetwork)
+		}
+	}
+	return policies[network], nil
+}
+
+// Forbids returns true if the txs with the flag are rejected
+func (p TxPolicy) Forbids(flag TxFlag) bool {
+	for _, forbidden := range p.ForbiddenFlags {
+		if forbidden == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// SendsToNode returns true if the submitted txs are delivered to the node
+func (p TxPolicy) SendsToNode() bool {
+	return p.DeliverToNode == nil || *p.DeliverToNode
+}
+
+// Validate rejects the flags of a tx forbidden by the policy
+func (p TxPolicy) Validate(validatorsOnly, nextValidator, frontRunningProtection, nodeValidation bool) error {
+	requested := []struct {
+		flag TxFlag
+		set  bool
+	}{
+		{TxFlagValidatorsOnly, validatorsOnly},
+		{TxFlagNextValidator, nextValidator},
+		{TxFlagFrontRunningProtection, frontRunningProtection},
+		{TxFlagNodeValidation, nodeValidation},
+	}
+	var forbidden []string
+	for _, r := range requested {
+		if r.set && p.Forbids(r.flag) {
+			forbidden = append(forbidden, string(r.flag))
+		}
+	}
+	switch len(forbidden) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%v is not allowed by the tx policy of the gateway, submit the transaction without it", forbidden[0])
+	default:
+		return fmt.Errorf("%v are not allowed by the tx policy of the gateway, submit the transaction without them", strings.Join(forbidden, ", "))
+	}
+}
@@ -0,0 +1,37 @@
+package connections
+
+import (
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// BDNStatus summarizes the connectivity of the node with the BDN
+type BDNStatus struct {
+	// Protocol is the newest protocol version the node speaks, each relay connection reports the version in use
+	Protocol        bxmessage.Protocol
+	SyncedWithRelay bool
+	Relays          []BDNConnStatus
+}
+
+// BDNConnStatus is the status of a connection to a relay, the rates are the messages per second of the last minute
+type BDNConnStatus struct {
+	PeerID           types.NodeID         `json:"peer_id,omitempty"`
+	Address          string               `json:"address"`
+	ConnectionType   string               `json:"connection_type"`
+	Connected        bool                 `json:"connected"`
+	ConnectedAt      time.Time            `json:"connected_at,omitempty"`
+	Protocol         bxmessage.Protocol   `json:"protocol"`
+	Features         []string             `json:"features"`
+	LastMessages     map[string]time.Time `json:"last_messages"`
+	MessagesReceived uint64               `json:"messages_received"`
+	MessagesSent     uint64               `json:"messages_sent"`
+	InboundRate      float64              `json:"inbound_rate"`
+	OutboundRate     float64              `json:"outbound_rate"`
+}
+
+// BDNStatusConn describes the connections reporting their BDN status
+type BDNStatusConn interface {
+	BDNStatus() BDNConnStatus
+}
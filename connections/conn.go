@@ -3,9 +3,11 @@ package connections
 import (
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 )
 
 // ConnHandler defines the methods needed to handle bloxroute connections
@@ -45,6 +47,24 @@ type NodeStatus struct {
 	TransactionServiceSynced bool
 	Capabilities             types.CapabilityFlags
 	Version                  string
+	// tx pool usage of the blockchain nodes and the resulting tx forwarding state
+	TxPoolState  blockchain.TxPoolState
+	TxPools      []blockchain.NodeTxPoolStatus
+	TxsForwarded uint64
+	TxsDropped   uint64
+	// last comparison of the node tx pool with the recent gateway transactions
+	TxPoolReconciliation *blockchain.TxPoolReconciliation
+	// number of network config updates from the SDN that changed the config applied to the blockchain peers
+	NetworkConfigUpdates uint64
+	// transactions from the BDN that failed content validation, by relay, and dropped because the validation queue was full
+	InvalidBDNTxs        map[string]uint64
+	DroppedBDNValidation uint64
+	// outcome of the canary transactions, nil if the canary is disabled
+	Canary *blockchain.CanaryStatus
+	// outcome of the comparison of the blocks from the BDN with secondary sources, nil if it is disabled
+	FeedVerification *blockchain.FeedVerificationStatus
+	// connections opened through each outbound proxy, empty if the connections are direct
+	OutboundProxies []ProxyHealth
 }
 
 // MsgHandlingOptions represents background/foreground options for message handling
@@ -59,6 +79,12 @@ const (
 // BxListener defines a struct that is capable of processing bloxroute messages
 type BxListener interface {
 	NodeStatus() NodeStatus
+	CanaryStatus() *blockchain.CanaryStatus
+	BDNStatus() BDNStatus
+	ValidatorList(blockHeight uint64) (*blockchain.ValidatorListInfo, bool)
+	SlotTime() utils.SlotTime
+	ClockSkew() time.Duration
+	FeeHistory(blockCount, newestBlock uint64, rewardPercentiles []float64) (*blockchain.FeeHistory, error)
 	HandleMsg(msg bxmessage.Message, conn Conn, background MsgHandlingOptions) error
 	ValidateConnection(conn Conn) error
 
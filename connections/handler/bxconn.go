@@ -48,10 +48,13 @@ type BxConn struct {
 	log                   *log.Entry
 	clock                 utils.Clock
 	capabilities          types.CapabilityFlags
+	features              bxmessage.Features
+	msgTypes              map[string]struct{}
 	clientVersion         string
 	sameRegion            bool
 	connectedAt           time.Time
 	receiveChan           chan bxmessage.MessageBytes
+	msgStats              *msgStats
 }
 
 // NewBxConn constructs a connection to a bloxroute node.
@@ -80,6 +83,7 @@ func NewBxConn(node connections.BxListener, connect func() (connections.Socket,
 		clock:       clock,
 		sameRegion:  sameRegion,
 		receiveChan: make(chan bxmessage.MessageBytes, receiveChannelSize),
+		msgStats:    newMsgStats(clock),
 	}
 	bc.stringRepresentation = fmt.Sprintf("%v/%v@<connecting...>", connectionType, bc.Conn)
 	go bc.readFromChannel()
@@ -101,8 +105,17 @@ func (b *BxConn) Start() error {
 	return nil
 }
 
-// Send sends a message to the peer.
+// Send sends a message to the peer. Negotiated message types the peer did not list in its hello are not sent, and
+// compressed broadcasts are sent uncompressed to the peers not supporting FeatureCompressedBroadcast.
 func (b *BxConn) Send(msg bxmessage.Message) error {
+	if msgType := bxmessage.NegotiatedMsgType(msg); msgType != "" && !b.SupportsMsgType(msgType) {
+		b.Log().Tracef("not sending %v message, the peer does not support it", msgType)
+		return nil
+	}
+	if broadcast, ok := msg.(*bxmessage.Broadcast); ok && broadcast.Compressed() && !b.SupportsFeatures(bxmessage.FeatureCompressedBroadcast) {
+		msg = broadcast.Uncompressed()
+	}
+	b.msgStats.send()
 	if msg.GetPriority() != bxmessage.OnPongPriority {
 		return b.Conn.Send(msg)
 	}
@@ -130,6 +143,53 @@ func (b *BxConn) GetVersion() string { return b.clientVersion }
 // GetCapabilities return capabilities
 func (b *BxConn) GetCapabilities() types.CapabilityFlags { return b.capabilities }
 
+// SupportsMsgType returns true if the peer can handle the message type. Peers older than
+// CapabilityNegotiationProtocol handle all the message types of their protocol version, as do peers which did not
+// complete the handshake yet.
+func (b *BxConn) SupportsMsgType(msgType string) bool {
+	if b.Protocol() < bxmessage.CapabilityNegotiationProtocol {
+		return true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.msgTypes == nil {
+		return true
+	}
+	_, ok := b.msgTypes[msgType]
+	return ok
+}
+
+// SupportsFeatures returns true if the peer listed all the optional protocol features in its hello, which peers older
+// than CapabilityNegotiationProtocol and peers which did not complete the handshake yet do not
+func (b *BxConn) SupportsFeatures(features bxmessage.Features) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.features.Has(features)
+}
+
+// BDNStatus returns the status of the connection with its message counts and rates
+func (b *BxConn) BDNStatus() connections.BDNConnStatus {
+	lastMessages, received, sent, inboundRate, outboundRate := b.msgStats.snapshot()
+	b.lock.Lock()
+	features := b.features.Names()
+	b.lock.Unlock()
+	return connections.BDNConnStatus{
+		PeerID:           b.peerID,
+		Address:          fmt.Sprintf("%v:%v", b.GetPeerIP(), b.GetPeerPort()),
+		ConnectionType:   b.connectionType.String(),
+		Connected:        b.IsOpen(),
+		ConnectedAt:      b.connectedAt,
+		Protocol:         b.Protocol(),
+		Features:         features,
+		LastMessages:     lastMessages,
+		MessagesReceived: received,
+		MessagesSent:     sent,
+		InboundRate:      inboundRate,
+		OutboundRate:     outboundRate,
+	}
+}
+
 // GetConnectionType returns type of the connection
 func (b *BxConn) GetConnectionType() utils.NodeType { return b.connectionType }
 
@@ -221,8 +281,18 @@ func (b *BxConn) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 		b.networkNum = helloMsg.GetNetworkNum()
 		b.capabilities = helloMsg.Capabilities
 		b.clientVersion = helloMsg.ClientVersion
+		if helloMsg.Protocol >= bxmessage.CapabilityNegotiationProtocol {
+			msgTypes := make(map[string]struct{}, len(helloMsg.MsgTypes))
+			for _, msgType := range helloMsg.MsgTypes {
+				msgTypes[msgType] = struct{}{}
+			}
+			b.lock.Lock()
+			b.features = helloMsg.Features
+			b.msgTypes = msgTypes
+			b.lock.Unlock()
+		}
 
-		b.Log().Debugf("completed handshake: network %v, protocol %v, peer id %v ", b.networkNum, b.Protocol(), b.peerID)
+		b.Log().Debugf("completed handshake: network %v, protocol %v, peer id %v, features %v, message types %v", b.networkNum, b.Protocol(), b.peerID, b.features, helloMsg.MsgTypes)
 
 		err = b.Node.ValidateConnection(b)
 		if err != nil {
@@ -234,7 +304,7 @@ func (b *BxConn) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 		ack := bxmessage.Ack{}
 		_ = b.Send(&ack)
 		if !b.IsInitiator() {
-			hello := bxmessage.Hello{NodeID: b.nodeID, Protocol: b.Protocol()}
+			hello := bxmessage.Hello{NodeID: b.nodeID, Protocol: b.Protocol(), Features: bxmessage.SupportedFeatures, MsgTypes: bxmessage.SupportedMsgTypes}
 			hello.SetNetworkNum(b.networkNum)
 			_ = b.Send(&hello)
 		} else {
@@ -323,6 +393,13 @@ func (b *BxConn) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 			return
 		}
 		_ = b.Node.HandleMsg(mevBundle, b, connections.RunForeground)
+	case bxmessage.MEVShareHintType:
+		mevShareHint := &bxmessage.MEVShareHint{}
+		if err := mevShareHint.Unpack(msg, b.Protocol()); err != nil {
+			b.log.Warnf("Failed to unpack mevShareHint bxmessage: %v", err)
+			return
+		}
+		_ = b.Node.HandleMsg(mevShareHint, b, connections.RunForeground)
 	default:
 		b.Log().Debugf("read %v (%d bytes)", msgType, len(msg))
 	}
@@ -386,6 +463,7 @@ func (b *BxConn) handleNonces(nodeNonce, peerNonce uint64) {
 }
 
 func (b *BxConn) processMessage(msgBytes bxmessage.MessageBytes) {
+	b.msgStats.receive(msgBytes.BxType())
 	msgBytes.SetNetworkChannelPositionAndInsertTime(len(b.receiveChan), b.clock.Now())
 	select {
 	case b.receiveChan <- msgBytes:
@@ -418,7 +496,7 @@ func (b *BxConn) readLoop() {
 		}
 
 		if isInitiator {
-			hello := bxmessage.Hello{NodeID: b.nodeID, Protocol: bxmessage.CurrentProtocol}
+			hello := bxmessage.Hello{NodeID: b.nodeID, Protocol: bxmessage.CurrentProtocol, Features: bxmessage.SupportedFeatures, MsgTypes: bxmessage.SupportedMsgTypes}
 			hello.SetNetworkNum(b.networkNum)
 			nodeStatus := b.Node.NodeStatus()
 			hello.ClientVersion = nodeStatus.Version
@@ -39,6 +39,36 @@ func TestBxConn_BDNIsDefaultForOldProtocol(t *testing.T) {
 	assert.True(t, bx.capabilities&types.CapabilityBDN != 0)
 }
 
+func TestBxConn_NegotiatedMsgTypes(t *testing.T) {
+	th := testHandler{}
+	_, bx := bxConn(&th)
+	th.setConn(bx)
+
+	// a peer which did not send its hello yet is assumed to support every message type
+	assert.True(t, bx.SupportsMsgType(bxmessage.MEVBundleType))
+
+	// a protocol 38 peer does not list its message types, it handles every message type of its protocol
+	oldHello := bxmessage.Hello{Protocol: bxmessage.BundlesOverBDNPayoutProtocol}
+	b, err := oldHello.Pack(bxmessage.BundlesOverBDNPayoutProtocol)
+	assert.NoError(t, err)
+	bx.ProcessMessage(bxmessage.NewMessageBytes(b, time.Now()))
+	assert.Equal(t, bxmessage.Protocol(bxmessage.BundlesOverBDNPayoutProtocol), bx.Protocol())
+	assert.True(t, bx.SupportsMsgType(bxmessage.MEVBundleType))
+
+	_, bx = bxConn(&th)
+	th.setConn(bx)
+	hello := bxmessage.Hello{Protocol: bxmessage.CapabilityNegotiationProtocol, MsgTypes: []string{bxmessage.TxType, bxmessage.BlockConfirmationType}}
+	b, err = hello.Pack(bxmessage.CapabilityNegotiationProtocol)
+	assert.NoError(t, err)
+	bx.ProcessMessage(bxmessage.NewMessageBytes(b, time.Now()))
+	assert.True(t, bx.SupportsMsgType(bxmessage.BlockConfirmationType))
+	assert.False(t, bx.SupportsMsgType(bxmessage.MEVBundleType))
+
+	// the supported message reaches the socket, which is not connected in this test, the unsupported one is skipped
+	assert.Error(t, bx.Send(&bxmessage.BlockConfirmation{}))
+	assert.NoError(t, bx.Send(&bxmessage.MEVBundle{}))
+}
+
 // semi integration test: in general, sleep should be avoided, but these closing tests cases are checking that we are closing goroutines correctly
 func TestBxConn_ClosingFromHandler(t *testing.T) {
 	startCount := runtime.NumGoroutine()
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// msgRateWindow is the window of the message rates, in one second buckets
+const msgRateWindow = 60
+
+// msgStats counts the messages of a connection, with the last time each message type was received and the message
+// rates of the last minute
+type msgStats struct {
+	lock         sync.Mutex
+	clock        utils.Clock
+	lastReceived map[string]time.Time
+	received     uint64
+	sent         uint64
+	inbound      msgRate
+	outbound     msgRate
+}
+
+// msgRate counts the messages of each second of the window
+type msgRate struct {
+	buckets [msgRateWindow]uint64
+	seconds [msgRateWindow]int64
+}
+
+func newMsgStats(clock utils.Clock) *msgStats {
+	return &msgStats{
+		clock:        clock,
+		lastReceived: make(map[string]time.Time),
+	}
+}
+
+// receive records a message of the type received from the peer
+func (s *msgStats) receive(msgType string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.clock.Now()
+	s.lastReceived[msgType] = now
+	s.received++
+	s.inbound.add(now.Unix())
+}
+
+// send records a message sent to the peer
+func (s *msgStats) send() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sent++
+	s.outbound.add(s.clock.Now().Unix())
+}
+
+// snapshot returns the last receive time of each message type, the message counts and the inbound and outbound
+// rates in messages per second
+func (s *msgStats) snapshot() (map[string]time.Time, uint64, uint64, float64, float64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	lastReceived := make(map[string]time.Time, len(s.lastReceived))
+	for msgType, t := range s.lastReceived {
+		lastReceived[msgType] = t
+	}
+	now := s.clock.Now().Unix()
+	return lastReceived, s.received, s.sent, s.inbound.rate(now), s.outbound.rate(now)
+}
+
+func (r *msgRate) add(second int64) {
+	i := second % msgRateWindow
+	if r.seconds[i] != second {
+		r.seconds[i] = second
+		r.buckets[i] = 0
+	}
+	r.buckets[i]++
+}
+
+// rate returns the messages per second of the window ending at the second
+func (r *msgRate) rate(second int64) float64 {
+	var count uint64
+	for i := range r.buckets {
+		if second-r.seconds[i] < msgRateWindow {
+			count += r.buckets[i]
+		}
+	}
+	return float64(count) / msgRateWindow
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgStats(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	stats := newMsgStats(clock)
+
+	for i := 0; i < 30; i++ {
+		stats.receive(bxmessage.TxType)
+	}
+	stats.send()
+	clock.IncTime(10 * time.Second)
+	stats.receive(bxmessage.BroadcastType)
+	for i := 0; i < 59; i++ {
+		stats.send()
+	}
+
+	lastMessages, received, sent, inboundRate, outboundRate := stats.snapshot()
+	assert.Equal(t, map[string]time.Time{
+		bxmessage.TxType:        time.Unix(1700000000, 0),
+		bxmessage.BroadcastType: time.Unix(1700000010, 0),
+	}, lastMessages)
+	assert.Equal(t, uint64(31), received)
+	assert.Equal(t, uint64(60), sent)
+	assert.InDelta(t, 31.0/60, inboundRate, 1e-9)
+	assert.InDelta(t, 1.0, outboundRate, 1e-9)
+
+	// the messages older than a minute are out of the rates
+	clock.IncTime(55 * time.Second)
+	_, _, _, inboundRate, outboundRate = stats.snapshot()
+	assert.InDelta(t, 1.0/60, inboundRate, 1e-9)
+	assert.InDelta(t, 59.0/60, outboundRate, 1e-9)
+}
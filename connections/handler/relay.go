@@ -7,6 +7,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services/capture"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 )
@@ -18,6 +19,7 @@ type Relay struct {
 	sendSyncReq   bool
 	syncDoneCount uint32
 	endpoint      types.NodeEndpoint
+	recorder      capture.Recorder
 }
 
 // NewOutboundRelay builds a new connection to a relay Node
@@ -74,12 +76,20 @@ func (r *Relay) NodeEndpoint() types.NodeEndpoint {
 	return r.endpoint
 }
 
+// SetRecorder sets the recorder capturing the messages received on the relay connection
+func (r *Relay) SetRecorder(recorder capture.Recorder) {
+	r.recorder = recorder
+}
+
 // ProcessMessage handles messages received on the relay connection, delegating to the BxListener when appropriate
 func (r *Relay) ProcessMessage(msgBytes bxmessage.MessageBytes) {
 	var err error
 
 	msgType := msgBytes.BxType()
 	msg := msgBytes.Raw()
+	if r.recorder != nil {
+		r.recorder.Record(capture.SourceRelay, r.Protocol(), msg)
+	}
 	if msgType != bxmessage.TxType {
 		r.Log().Tracef("processing message %v, msg len %v", msgType, len(msg))
 	}
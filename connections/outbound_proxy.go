@@ -0,0 +1,259 @@
+package connections
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// directProxy is the proxy URL of a rule connecting directly to its destinations
+const directProxy = "direct"
+
+// outboundProxy routes the SDN and relay connections, they connect directly unless SetOutboundProxy is called
+var outboundProxy *OutboundProxy
+
+// SetOutboundProxy routes the SDN and relay connections through the proxy, it must be called before they are opened
+func SetOutboundProxy(p *OutboundProxy) {
+	outboundProxy = p
+}
+
+// OutboundProxyHealth returns the health of the proxies the SDN and relay connections were opened through
+func OutboundProxyHealth() []ProxyHealth {
+	return outboundProxy.Health()
+}
+
+// ProxyRule routes the outbound connections to a destination through a proxy
+type ProxyRule struct {
+	// Destination is a host, a *.domain wildcard, an IP or a CIDR, the rule without destination applies to the
+	// destinations not matching any other rule
+	Destination string
+	// Proxy is the socks5 or http URL of the proxy, nil connects directly
+	Proxy *url.URL
+}
+
+// ParseProxyRules parses [destination=]url rules, the url is a socks5:// or http:// proxy URL or direct
+func ParseProxyRules(values []string) ([]ProxyRule, error) {
+	rules := make([]ProxyRule, 0, len(values))
+	hasDefault := false
+	for _, value := range values {
+		destination, proxyURL, found := strings.Cut(value, "=")
+		// the user info of a URL without destination can hold =
+		if !found || strings.Contains(destination, "://") {
+			destination, proxyURL = "", value
+		}
+
+		rule := ProxyRule{Destination: destination}
+		if proxyURL != directProxy {
+			u, err := url.Parse(proxyURL)
+			if err != nil || (u.Scheme != "socks5" && u.Scheme != "http") || u.Host == "" {
+				return nil, fmt.Errorf("invalid outbound proxy %v, expected [destination=]url with a socks5 or http url, or direct", value)
+			}
+			rule.Proxy = u
+		}
+		if destination == "" {
+			if hasDefault {
+				return nil, fmt.Errorf("invalid outbound proxy %v, the default proxy is already set", value)
+			}
+			hasDefault = true
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// matches returns whether the rule applies to the host
+func (r ProxyRule) matches(host string) bool {
+	switch {
+	case r.Destination == "":
+		return true
+	case strings.HasPrefix(r.Destination, "*."):
+		return strings.HasSuffix(host, r.Destination[1:])
+	case strings.Contains(r.Destination, "/"):
+		_, ipNet, err := net.ParseCIDR(r.Destination)
+		ip := net.ParseIP(host)
+		return err == nil && ip != nil && ipNet.Contains(ip)
+	default:
+		return strings.EqualFold(r.Destination, host)
+	}
+}
+
+// ProxyHealth reports the connections opened through a proxy
+type ProxyHealth struct {
+	Proxy         string    `json:"proxy"`
+	Dials         uint64    `json:"dials"`
+	Failures      uint64    `json:"failures"`
+	LastDialMs    float64   `json:"last_dial_ms"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorTime time.Time `json:"last_error_time,omitempty"`
+}
+
+// OutboundProxy opens the outbound connections through the proxy of the first rule matching their destination, the
+// default rule applies to the other destinations and the destinations without a rule are connected directly. All
+// methods connect directly on a nil receiver.
+type OutboundProxy struct {
+	rules []ProxyRule
+
+	lock   sync.Mutex
+	health map[string]*ProxyHealth
+}
+
+// NewOutboundProxy creates the proxy, it returns nil if there are no rules
+func NewOutboundProxy(rules []ProxyRule) *OutboundProxy {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	// the default rule is matched last
+	sorted := append([]ProxyRule{}, rules...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Destination != "" && sorted[j].Destination == ""
+	})
+	return &OutboundProxy{
+		rules:  sorted,
+		health: make(map[string]*ProxyHealth),
+	}
+}
+
+// DialContext connects to the address through the proxy of its destination
+func (p *OutboundProxy) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	direct := &net.Dialer{Timeout: dialTimeout}
+	proxyURL := p.route(addr)
+	if proxyURL == nil {
+		return direct.DialContext(ctx, network, addr)
+	}
+
+	start := time.Now()
+	var conn net.Conn
+	var err error
+	switch proxyURL.Scheme {
+	case "socks5":
+		var dialer proxy.Dialer
+		dialer, err = proxy.FromURL(proxyURL, direct)
+		if err != nil {
+			break
+		}
+		if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+			conn, err = contextDialer.DialContext(ctx, network, addr)
+		} else {
+			conn, err = dialer.Dial(network, addr)
+		}
+	default:
+		conn, err = dialHTTPConnect(ctx, direct, proxyURL, addr)
+	}
+	p.track(proxyURL, time.Since(start), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v through proxy %v: %v", addr, proxyURL.Redacted(), err)
+	}
+	return conn, nil
+}
+
+// Health returns the health of each proxy a connection was opened through
+func (p *OutboundProxy) Health() []ProxyHealth {
+	if p == nil {
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	health := make([]ProxyHealth, 0, len(p.health))
+	for _, h := range p.health {
+		health = append(health, *h)
+	}
+	sort.Slice(health, func(i, j int) bool { return health[i].Proxy < health[j].Proxy })
+	return health
+}
+
+// route returns the proxy of the address, nil if it is connected directly
+func (p *OutboundProxy) route(addr string) *url.URL {
+	if p == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	for _, rule := range p.rules {
+		if rule.matches(host) {
+			return rule.Proxy
+		}
+	}
+	return nil
+}
+
+func (p *OutboundProxy) track(proxyURL *url.URL, latency time.Duration, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	name := proxyURL.Redacted()
+	h, ok := p.health[name]
+	if !ok {
+		h = &ProxyHealth{Proxy: name}
+		p.health[name] = h
+	}
+	h.Dials++
+	h.LastDialMs = float64(latency) / float64(time.Millisecond)
+	if err != nil {
+		h.Failures++
+		h.LastError = err.Error()
+		h.LastErrorTime = time.Now()
+	}
+}
+
+// dialHTTPConnect opens a tunnel to the address through the HTTP proxy with the CONNECT method
+func dialHTTPConnect(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, addr string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(dialTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	// the body of the response is the tunnel, it must not be drained
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("proxy refused the tunnel: %v", resp.Status)
+	}
+	if reader.Buffered() > 0 {
+		_ = conn.Close()
+		return nil, errors.New("proxy sent data before the tunnel was used")
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
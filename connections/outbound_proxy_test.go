@@ -0,0 +1,102 @@
+package connections
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveHTTPConnect accepts a CONNECT request and echoes the tunnel
+func serveHTTPConnect(listener net.Listener, requests chan<- *http.Request) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer func() { _ = conn.Close() }()
+			req, err := http.ReadRequest(bufio.NewReader(conn))
+			if err != nil {
+				return
+			}
+			requests <- req
+			if req.Header.Get("Proxy-Authorization") == "" {
+				_, _ = conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n"))
+				return
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+			_, _ = io.Copy(conn, conn)
+		}()
+	}
+}
+
+func TestOutboundProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	requests := make(chan *http.Request, 2)
+	go serveHTTPConnect(listener, requests)
+
+	rules, err := ParseProxyRules([]string{
+		"http://user:secret@" + listener.Addr().String(),
+		"*.sdn.example=http://" + listener.Addr().String(),
+		"10.0.0.0/8=direct",
+	})
+	require.NoError(t, err)
+	p := NewOutboundProxy(rules)
+
+	assert.Nil(t, p.route("10.1.2.3:1809"))
+	// the default proxy applies to the destinations without a rule, whatever the order of the rules
+	assert.NotNil(t, p.route("relay.example:1809").User)
+	assert.Nil(t, p.route("api.sdn.example:443").User)
+
+	conn, err := p.DialContext(context.Background(), "tcp", "relay.example:1809")
+	require.NoError(t, err)
+	req := <-requests
+	assert.Equal(t, http.MethodConnect, req.Method)
+	assert.Equal(t, "relay.example:1809", req.Host)
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	echo := make([]byte, 4)
+	_, err = io.ReadFull(conn, echo)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(echo))
+	_ = conn.Close()
+
+	// the proxy refuses the tunnel without credentials
+	_, err = p.DialContext(context.Background(), "tcp", "api.sdn.example:443")
+	assert.Error(t, err)
+	<-requests
+
+	health := p.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, "http://"+listener.Addr().String(), health[0].Proxy)
+	assert.Equal(t, uint64(1), health[0].Failures)
+	assert.Equal(t, "http://user:xxxxx@"+listener.Addr().String(), health[1].Proxy)
+	assert.Equal(t, uint64(1), health[1].Dials)
+	assert.Equal(t, uint64(0), health[1].Failures)
+
+	var direct *OutboundProxy
+	assert.Nil(t, direct.Health())
+	conn, err = direct.DialContext(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+}
+
+func TestParseProxyRules(t *testing.T) {
+	for _, values := range [][]string{
+		{"ftp://proxy:21"},
+		{"relay.example=proxy:1080"},
+		{"socks5://proxy:1080", "http://proxy:3128"},
+	} {
+		_, err := ParseProxyRules(values)
+		assert.Error(t, err, values)
+	}
+	assert.Nil(t, NewOutboundProxy(nil))
+}
@@ -0,0 +1,138 @@
+package connections
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// relayDNSTimeout bounds each resolution of the relay records
+const relayDNSTimeout = 10 * time.Second
+
+// dnsResolver looks up the relay records, it is implemented by net.Resolver
+type dnsResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// RelayDiscovery resolves the relays from the SRV records of a DNS name, or from its TXT records holding ip:port
+// relays if it has no SRV record, and directs the gateway to migrate its relay connections when the records change.
+// The SRV records of the lowest priorities are used, their weights are ignored so the relays do not change between
+// resolutions of the same records.
+type RelayDiscovery struct {
+	name         string
+	interval     time.Duration
+	limit        int
+	instructions chan<- RelayInstruction
+	resolver     dnsResolver
+	clock        utils.Clock
+
+	current relayMap
+}
+
+// NewRelayDiscovery creates the discovery of up to limit relays, which re-resolves the name every interval
+func NewRelayDiscovery(name string, interval time.Duration, limit int, instructions chan<- RelayInstruction, clock utils.Clock) *RelayDiscovery {
+	return newRelayDiscovery(name, interval, limit, instructions, net.DefaultResolver, clock)
+}
+
+func newRelayDiscovery(name string, interval time.Duration, limit int, instructions chan<- RelayInstruction, resolver dnsResolver, clock utils.Clock) *RelayDiscovery {
+	return &RelayDiscovery{
+		name:         name,
+		interval:     interval,
+		limit:        limit,
+		instructions: instructions,
+		resolver:     resolver,
+		clock:        clock,
+		current:      make(relayMap),
+	}
+}
+
+// Run resolves the relays and re-resolves them every interval until the context is done
+func (d *RelayDiscovery) Run(ctx context.Context) {
+	d.refresh(ctx)
+
+	ticker := d.clock.Ticker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			d.refresh(ctx)
+		}
+	}
+}
+
+// refresh connects to the relays added to the records before disconnecting from the removed ones, the relays are
+// kept if the records cannot be resolved
+func (d *RelayDiscovery) refresh(ctx context.Context) {
+	relays, err := d.resolve(ctx)
+	if err != nil {
+		log.Errorf("failed to resolve the relays of %v, keeping the %v current relays: %v", d.name, len(d.current), err)
+		return
+	}
+	if len(relays) == 0 {
+		log.Errorf("no relay found in the records of %v, keeping the %v current relays", d.name, len(d.current))
+		return
+	}
+
+	for ip, port := range relays {
+		if currentPort, ok := d.current[ip]; !ok || currentPort != port {
+			log.Infof("relay %v:%v was added to the records of %v", ip, port, d.name)
+			d.instructions <- RelayInstruction{IP: ip, Port: port, Type: Connect}
+		}
+	}
+	for ip, port := range d.current {
+		if newPort, ok := relays[ip]; !ok || newPort != port {
+			log.Infof("relay %v:%v was removed from the records of %v", ip, port, d.name)
+			d.instructions <- RelayInstruction{IP: ip, Port: port, Type: Disconnect}
+		}
+	}
+	d.current = relays
+}
+
+// resolve returns the relays of the SRV records of the name, or of its TXT records if it has no SRV record
+func (d *RelayDiscovery) resolve(ctx context.Context) (relayMap, error) {
+	ctx, cancel := context.WithTimeout(ctx, relayDNSTimeout)
+	defer cancel()
+
+	_, records, srvErr := d.resolver.LookupSRV(ctx, "", "", d.name)
+	if srvErr == nil && len(records) > 0 {
+		sort.SliceStable(records, func(i, j int) bool {
+			if records[i].Priority != records[j].Priority {
+				return records[i].Priority < records[j].Priority
+			}
+			return records[i].Target < records[j].Target
+		})
+		relays := make(relayMap)
+		for _, record := range records {
+			if len(relays) == d.limit {
+				break
+			}
+			ip, err := utils.GetIP(strings.TrimSuffix(record.Target, "."))
+			if err != nil {
+				log.Warnf("skipping relay %v of the records of %v: %v", record.Target, d.name, err)
+				continue
+			}
+			relays[ip] = int64(record.Port)
+		}
+		return relays, nil
+	}
+
+	txts, err := d.resolver.LookupTXT(ctx, d.name)
+	if err != nil {
+		return nil, fmt.Errorf("no SRV record (%v) nor TXT record (%v)", srvErr, err)
+	}
+	// the TXT records hold comma separated relays in the format of --relays
+	relays, _, err := parsedCmdlineRelays(strings.Join(txts, ","), uint64(d.limit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TXT record: %v", err)
+	}
+	return relays, nil
+}
@@ -0,0 +1,85 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDNSResolver struct {
+	srv []*net.SRV
+	txt []string
+}
+
+func (r *testDNSResolver) LookupSRV(context.Context, string, string, string) (string, []*net.SRV, error) {
+	if len(r.srv) == 0 {
+		return "", nil, errors.New("no such host")
+	}
+	// the records are copied since they are sorted
+	return "", append([]*net.SRV{}, r.srv...), nil
+}
+
+func (r *testDNSResolver) LookupTXT(context.Context, string) ([]string, error) {
+	if len(r.txt) == 0 {
+		return nil, errors.New("no such host")
+	}
+	return r.txt, nil
+}
+
+func drainRelayInstructions(instructions chan RelayInstruction) []RelayInstruction {
+	var drained []RelayInstruction
+	for {
+		select {
+		case instruction := <-instructions:
+			drained = append(drained, instruction)
+		default:
+			return drained
+		}
+	}
+}
+
+func TestRelayDiscovery(t *testing.T) {
+	resolver := &testDNSResolver{srv: []*net.SRV{
+		{Target: "10.0.0.3.", Port: 1809, Priority: 20},
+		{Target: "10.0.0.2.", Port: 1809, Priority: 10},
+		{Target: "10.0.0.1.", Port: 1809, Priority: 10},
+	}}
+	instructions := make(chan RelayInstruction, 10)
+	d := newRelayDiscovery("_bxrelay._tcp.example", 0, 2, instructions, resolver, &utils.MockClock{})
+
+	// the relays of the lowest priority are used
+	d.refresh(context.Background())
+	assert.ElementsMatch(t, []RelayInstruction{
+		{IP: "10.0.0.1", Port: 1809, Type: Connect},
+		{IP: "10.0.0.2", Port: 1809, Type: Connect},
+	}, drainRelayInstructions(instructions))
+
+	// the same records do not change the relays
+	d.refresh(context.Background())
+	assert.Empty(t, drainRelayInstructions(instructions))
+
+	// the connection to a replaced relay is opened before the one to the removed relay is closed
+	resolver.srv = resolver.srv[:2]
+	d.refresh(context.Background())
+	assert.Equal(t, []RelayInstruction{
+		{IP: "10.0.0.3", Port: 1809, Type: Connect},
+		{IP: "10.0.0.1", Port: 1809, Type: Disconnect},
+	}, drainRelayInstructions(instructions))
+
+	// the relays are kept if the records cannot be resolved
+	resolver.srv = nil
+	d.refresh(context.Background())
+	assert.Empty(t, drainRelayInstructions(instructions))
+
+	// the TXT records are used without SRV records
+	resolver.txt = []string{"10.0.0.2:1809,10.0.0.4"}
+	d.refresh(context.Background())
+	assert.Equal(t, []RelayInstruction{
+		{IP: "10.0.0.4", Port: 1809, Type: Connect},
+		{IP: "10.0.0.3", Port: 1809, Type: Disconnect},
+	}, drainRelayInstructions(instructions))
+}
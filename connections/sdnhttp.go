@@ -62,6 +62,7 @@ type SDNHTTP interface {
 	Register() error
 	NeedsRegistration() bool
 	FetchCustomerAccountModel(accountID types.AccountID) (sdnmessage.Account, error)
+	FetchFeatureFlags() (map[string]bool, error)
 	DirectRelayConnections(ctx context.Context, relayHosts string, relayLimit uint64, relayInstructions chan<- RelayInstruction, autoRelayTimeout time.Duration) error
 	FindNetwork(networkNum types.NetworkNum) (*sdnmessage.BlockchainNetwork, error)
 	MinTxAge() time.Duration
@@ -380,6 +381,7 @@ func (s realSDNHTTP) httpClient() (*http.Client, error) {
 	client := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: tlsConfig,
+			DialContext:     outboundProxy.DialContext,
 		},
 	}
 	return client, nil
@@ -524,6 +526,21 @@ func (s *realSDNHTTP) FetchCustomerAccountModel(accountID types.AccountID) (sdnm
 	return s.getAccountModelWithEndpoint(accountID, "accounts")
 }
 
+// FetchFeatureFlags gets the current feature flags of the node model registered with SDN. The registered node model
+// is left unchanged since it is shared with the rest of the gateway.
+func (s *realSDNHTTP) FetchFeatureFlags() (map[string]bool, error) {
+	url := fmt.Sprintf("%v/nodes/%v", s.sdnURL, s.nodeID)
+	resp, err := s.http(url, bxgateway.GetMethod, nil)
+	if err != nil {
+		return nil, err
+	}
+	var nodeModel sdnmessage.NodeModel
+	if err = json.Unmarshal(resp, &nodeModel); err != nil {
+		return nil, fmt.Errorf("could not deserialize node model: %v", err)
+	}
+	return nodeModel.FeatureFlags, nil
+}
+
 // getRelays gets the potential relays for a gateway
 func (s *realSDNHTTP) getRelays(nodeID types.NodeID, networkNum types.NetworkNum) (sdnmessage.Peers, error) {
 	url := fmt.Sprintf("%v/nodes/%v/%v/potential-relays", s.sdnURL, nodeID, networkNum)
@@ -0,0 +1,148 @@
+package connections
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// LocalAccountID - account of a gateway running in local mode, used for all its clients
+const LocalAccountID types.AccountID = "local"
+
+// localNodeID - node ID of a gateway running in local mode
+const localNodeID types.NodeID = "local-gateway"
+
+// errLocalMode - returned by SDN requests which cannot be served in local mode
+var errLocalMode = errors.New("SDN is not available in local mode")
+
+// localSDNHTTP serves the SDN data of a gateway running without SDN and relay connectivity from static values
+type localSDNHTTP struct {
+	nodeModel    sdnmessage.NodeModel
+	networks     sdnmessage.BlockchainNetworks
+	accountModel sdnmessage.Account
+}
+
+// NewLocalSDNHTTP returns an SDNHTTP for a gateway running in local mode on the provided blockchain network. It never
+// connects to the SDN: the node is registered to an elite account and no relays are provided.
+func NewLocalSDNHTTP(nodeModel sdnmessage.NodeModel, network string) (SDNHTTP, error) {
+	networkNum, ok := bxgateway.BlockchainNetworkToNetworkNum[network]
+	if !ok {
+		return nil, fmt.Errorf("blockchain network %v is not supported in local mode", network)
+	}
+
+	nodeModel.NodeID = localNodeID
+	nodeModel.Network = network
+	nodeModel.Protocol = bxgateway.Ethereum
+	nodeModel.BlockchainNetworkNum = networkNum
+	nodeModel.AccountID = LocalAccountID
+
+	blockchainNetwork := &sdnmessage.BlockchainNetwork{
+		Network:    network,
+		NetworkNum: networkNum,
+		Protocol:   bxgateway.Ethereum,
+		DefaultAttributes: sdnmessage.BlockchainAttributes{
+			NetworkID: bxgateway.NetworkNumToChainID[networkNum],
+		},
+	}
+
+	return &localSDNHTTP{
+		nodeModel:    nodeModel,
+		networks:     sdnmessage.BlockchainNetworks{networkNum: blockchainNetwork},
+		accountModel: localAccount(LocalAccountID, network),
+	}, nil
+}
+
+func localAccount(accountID types.AccountID, network string) sdnmessage.Account {
+	account := sdnmessage.GetDefaultEliteAccount(time.Now().UTC())
+	account.AccountID = accountID
+	account.BlockchainNetwork = network
+	return account
+}
+
+func (s *localSDNHTTP) SDNURL() string {
+	return ""
+}
+
+func (s *localSDNHTTP) NodeID() types.NodeID {
+	return s.nodeModel.NodeID
+}
+
+func (s *localSDNHTTP) Networks() *sdnmessage.BlockchainNetworks {
+	return &s.networks
+}
+
+func (s *localSDNHTTP) SetNetworks(networks sdnmessage.BlockchainNetworks) {
+	s.networks = networks
+}
+
+func (s *localSDNHTTP) FetchAllBlockchainNetworks() error {
+	return nil
+}
+
+func (s *localSDNHTTP) FetchBlockchainNetwork() error {
+	return nil
+}
+
+func (s *localSDNHTTP) InitGateway(protocol string, network string) error {
+	if network != s.nodeModel.Network {
+		return fmt.Errorf("local mode gateway was created for blockchain network %v, not %v", s.nodeModel.Network, network)
+	}
+	return nil
+}
+
+func (s *localSDNHTTP) NodeModel() *sdnmessage.NodeModel {
+	return &s.nodeModel
+}
+
+func (s *localSDNHTTP) AccountTier() sdnmessage.AccountTier {
+	return s.accountModel.TierName
+}
+
+func (s *localSDNHTTP) AccountModel() sdnmessage.Account {
+	return s.accountModel
+}
+
+func (s *localSDNHTTP) NetworkNum() types.NetworkNum {
+	return s.nodeModel.BlockchainNetworkNum
+}
+
+func (s *localSDNHTTP) Register() error {
+	return nil
+}
+
+func (s *localSDNHTTP) NeedsRegistration() bool {
+	return false
+}
+
+// FetchCustomerAccountModel returns an elite account for any account ID, there is no SDN to verify it with
+func (s *localSDNHTTP) FetchCustomerAccountModel(accountID types.AccountID) (sdnmessage.Account, error) {
+	return localAccount(accountID, s.nodeModel.Network), nil
+}
+
+// DirectRelayConnections does not provide any relay, the gateway only serves the blockchain node in local mode
+func (s *localSDNHTTP) DirectRelayConnections(context.Context, string, uint64, chan<- RelayInstruction, time.Duration) error {
+	return nil
+}
+
+func (s *localSDNHTTP) FindNetwork(networkNum types.NetworkNum) (*sdnmessage.BlockchainNetwork, error) {
+	return s.networks.FindNetwork(networkNum)
+}
+
+func (s *localSDNHTTP) MinTxAge() time.Duration {
+	return 0
+}
+
+func (s *localSDNHTTP) SendNodeEvent(sdnmessage.NodeEvent, types.NodeID) {}
+
+func (s *localSDNHTTP) Get(string, []byte) ([]byte, error) {
+	return nil, errLocalMode
+}
+
+func (s *localSDNHTTP) GetQuotaUsage(string) (*QuotaResponseBody, error) {
+	return nil, errLocalMode
+}
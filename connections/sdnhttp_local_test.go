@@ -0,0 +1,42 @@
+package connections
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSDNHTTP(t *testing.T) {
+	_, err := NewLocalSDNHTTP(sdnmessage.NodeModel{}, "unknown")
+	require.Error(t, err)
+
+	sdn, err := NewLocalSDNHTTP(sdnmessage.NodeModel{ExternalIP: "127.0.0.1"}, bxgateway.Mainnet)
+	require.NoError(t, err)
+
+	assert.Equal(t, bxgateway.MainnetNum, sdn.NetworkNum())
+	assert.Equal(t, "127.0.0.1", sdn.NodeModel().ExternalIP)
+	assert.False(t, sdn.NeedsRegistration())
+	assert.NoError(t, sdn.InitGateway(bxgateway.Ethereum, bxgateway.Mainnet))
+	assert.Error(t, sdn.InitGateway(bxgateway.Ethereum, bxgateway.BSCMainnet))
+
+	network, err := sdn.FindNetwork(bxgateway.MainnetNum)
+	require.NoError(t, err)
+	assert.Equal(t, bxgateway.EthChainID, network.DefaultAttributes.NetworkID)
+
+	assert.Equal(t, LocalAccountID, sdn.AccountModel().AccountID)
+	assert.True(t, sdn.AccountTier().IsEnterprise())
+
+	account, err := sdn.FetchCustomerAccountModel("customer")
+	require.NoError(t, err)
+	assert.Equal(t, types.AccountID("customer"), account.AccountID)
+	assert.True(t, account.TierName.IsEnterprise())
+
+	relayInstructions := make(chan RelayInstruction)
+	assert.NoError(t, sdn.DirectRelayConnections(context.Background(), "auto", 1, relayInstructions, AutoRelayTimeout))
+	assert.Len(t, relayInstructions, 0)
+}
@@ -1,6 +1,7 @@
 package connections
 
 import (
+	"context"
 	"crypto/tls"
 	"net"
 	"strconv"
@@ -38,7 +39,9 @@ func NewTLS(ip string, port int, certs *utils.SSLCerts) (*TLS, error) {
 		return nil, err
 	}
 	ipAddress := ip + ":" + strconv.Itoa(port)
-	ipConn, err := net.DialTimeout("tcp", ipAddress, dialTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+	ipConn, err := outboundProxy.DialContext(ctx, "tcp", ipAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +52,57 @@ const AsyncMsgChannelSize = 500
 // BxNotificationChannelSize - is the size of feed channels
 const BxNotificationChannelSize = 1000
 
+// SubscriptionTransferGracePeriod - how long a subscription with a transfer token is kept alive after its connection is gone
+const SubscriptionTransferGracePeriod = 30 * time.Second
+
+// ClusterGossipInterval - interval between gossip messages sent to the other gateways of the cluster
+const ClusterGossipInterval = 100 * time.Millisecond
+
+// NodeTxPoolStatusInterval - interval between txpool_status queries sent to the blockchain nodes
+const NodeTxPoolStatusInterval = 5 * time.Second
+
+// TxPoolReconciliationMinAge - minimum age of a gateway tx before it is expected to be in the node txpool
+const TxPoolReconciliationMinAge = 5 * time.Second
+
+// TxPoolReconciliationMaxAge - maximum age of a gateway tx compared with the node txpool
+const TxPoolReconciliationMaxAge = time.Minute
+
+// DenylistReloadInterval - interval of checking the denylist file for modifications
+const DenylistReloadInterval = 10 * time.Second
+
+// FilterFilesReloadInterval - interval of checking the address files of the filters for modifications
+const FilterFilesReloadInterval = 10 * time.Second
+
+// FeedPeerReconnectInterval - interval between attempts to subscribe to the feeds of a peer gateway
+const FeedPeerReconnectInterval = 5 * time.Second
+
+// WSCertificateCheckInterval - interval of checking the websocket TLS certificate files for modifications
+const WSCertificateCheckInterval = 10 * time.Second
+
+// BDNTxValidationQueueSize - number of transactions from the BDN waiting for content validation before new ones are dropped
+const BDNTxValidationQueueSize = 10000
+
+// ValidatorListHistorySize - number of validator lists received over the bridge kept for blxr_validator_list
+const ValidatorListHistorySize = 64
+
+// BSCEpochLength - number of blocks between two updates of the BSC validator set
+const BSCEpochLength = 200
+
+// ValidatorSnapshotInterval - interval between the snapshots of the next validators and validator status in the data dir
+const ValidatorSnapshotInterval = 5 * time.Second
+
+// ValidatorSnapshotMaxAge - maximum age of the validator snapshot loaded on startup, older next validators are outdated
+const ValidatorSnapshotMaxAge = time.Minute
+
+// FeeHistorySize - number of recent blocks whose fees are kept for blxr_fee_history
+const FeeHistorySize = 1024
+
+// FeatureFlagsRefreshInterval - interval between fetches of the feature flags of the node model from the SDN
+const FeatureFlagsRefreshInterval = 5 * time.Minute
+
+// SlotClockSyncInterval - interval between NTP queries correcting the drift of the slot clock
+const SlotClockSyncInterval = 5 * time.Minute
+
 // MaxEthOnBlockCallRetries - max number of retries for eth RPC calls executed for onBlock feed
 const MaxEthOnBlockCallRetries = 2
 
@@ -76,6 +127,12 @@ const TaskCompletedEvent = "TaskCompletedEvent"
 // TaskDisabledEvent - sent as notification on onBlock feed when a RPC call is disabled due to failure
 const TaskDisabledEvent = "TaskDisabledEvent"
 
+// BlockCompleteEvent - sent on a combined subscription with a block barrier after all the notifications of a block were sent
+const BlockCompleteEvent = "blockComplete"
+
+// BlockReorgEvent - sent on a subscription with strict ordering before a block replacing the blocks sent from its height
+const BlockReorgEvent = "blockReorg"
+
 // BDNBlocksMaxBlocksAway - gateway should not publish blocks to BDNBlocks feed that are older than the best height from node minus BDNBlocksMaxBlocksAway
 const BDNBlocksMaxBlocksAway = 50
 
@@ -0,0 +1,197 @@
+// Package hooks lets advanced users run custom logic on the transactions and blocks processed by the gateway, e.g.
+// proprietary filtering, without forking the internal packages. Hooks are registered at compile time from the init
+// function of a package imported by a custom main package, and are run by the gateway from startup:
+//
+//	func init() {
+//		hooks.Register("my-filter", myFilter{})
+//	}
+package hooks
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// Source is the connection a transaction or block was received from
+type Source struct {
+	ConnectionType string
+	PeerIP         string
+	PeerPort       int64
+	AccountID      types.AccountID
+}
+
+// TxReceivedHook is implemented by hooks processing the transactions received by the gateway
+type TxReceivedHook interface {
+	// OnTxReceived is called for each new transaction before it is published to the feeds and propagated, the
+	// transaction is dropped if it returns false
+	OnTxReceived(tx *types.BxTransaction, source Source) bool
+}
+
+// BlockReceivedHook is implemented by hooks processing the blocks received by the gateway
+type BlockReceivedHook interface {
+	// OnBlockReceived is called for each block received from the blockchain node or the BDN
+	OnBlockReceived(block *types.BxBlock, source Source)
+}
+
+// BeforePropagateHook is implemented by hooks processing the messages propagated to the BDN
+type BeforePropagateHook interface {
+	// OnBeforePropagate is called before a message is sent to the BDN, the message is not sent if it returns false
+	OnBeforePropagate(msg bxmessage.Message) bool
+}
+
+// TxEnrichHook is implemented by hooks annotating the tx notifications of the subscriptions which include enrich
+type TxEnrichHook interface {
+	// OnTxEnrich returns the annotations of the transaction, published under the name of the hook. The transaction is
+	// not annotated by the hook if it returns nil.
+	OnTxEnrich(tx *types.NewTransactionNotification) interface{}
+}
+
+var (
+	registeredLock sync.Mutex
+	registered     = make(map[string]interface{})
+)
+
+// Register makes a hook available to the gateway. The hook must implement at least one of the hook interfaces.
+// Like database/sql drivers, Register panics if it is called twice with the same name or with an invalid hook.
+func Register(name string, hook interface{}) {
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+
+	if _, ok := registered[name]; ok {
+		panic(fmt.Sprintf("hooks: Register called twice for hook %v", name))
+	}
+	_, txReceived := hook.(TxReceivedHook)
+	_, blockReceived := hook.(BlockReceivedHook)
+	_, beforePropagate := hook.(BeforePropagateHook)
+	_, txEnrich := hook.(TxEnrichHook)
+	if !txReceived && !blockReceived && !beforePropagate && !txEnrich {
+		panic(fmt.Sprintf("hooks: hook %v does not implement any hook interface", name))
+	}
+	registered[name] = hook
+}
+
+// Hooks are the hooks run by the gateway, a hook which panics is skipped
+type Hooks struct {
+	names           []string
+	txReceived      []namedHook[TxReceivedHook]
+	blockReceived   []namedHook[BlockReceivedHook]
+	beforePropagate []namedHook[BeforePropagateHook]
+	txEnrich        []namedHook[TxEnrichHook]
+}
+
+type namedHook[T any] struct {
+	name string
+	hook T
+}
+
+// New returns the registered hooks, ordered by name
+func New() *Hooks {
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+
+	h := &Hooks{names: make([]string, 0, len(registered))}
+	for name := range registered {
+		h.names = append(h.names, name)
+	}
+	sort.Strings(h.names)
+
+	for _, name := range h.names {
+		hook := registered[name]
+		if txReceived, ok := hook.(TxReceivedHook); ok {
+			h.txReceived = append(h.txReceived, namedHook[TxReceivedHook]{name: name, hook: txReceived})
+		}
+		if blockReceived, ok := hook.(BlockReceivedHook); ok {
+			h.blockReceived = append(h.blockReceived, namedHook[BlockReceivedHook]{name: name, hook: blockReceived})
+		}
+		if beforePropagate, ok := hook.(BeforePropagateHook); ok {
+			h.beforePropagate = append(h.beforePropagate, namedHook[BeforePropagateHook]{name: name, hook: beforePropagate})
+		}
+		if txEnrich, ok := hook.(TxEnrichHook); ok {
+			h.txEnrich = append(h.txEnrich, namedHook[TxEnrichHook]{name: name, hook: txEnrich})
+		}
+	}
+	return h
+}
+
+// Names returns the names of the hooks
+func (h *Hooks) Names() []string {
+	if h == nil {
+		return nil
+	}
+	return h.names
+}
+
+// TxReceived runs the OnTxReceived hooks, it returns false if a hook dropped the transaction
+func (h *Hooks) TxReceived(tx *types.BxTransaction, source Source) bool {
+	if h == nil {
+		return true
+	}
+	for _, txReceived := range h.txReceived {
+		if !run(txReceived.name, func() bool { return txReceived.hook.OnTxReceived(tx, source) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// BlockReceived runs the OnBlockReceived hooks
+func (h *Hooks) BlockReceived(block *types.BxBlock, source Source) {
+	if h == nil {
+		return
+	}
+	for _, blockReceived := range h.blockReceived {
+		run(blockReceived.name, func() bool {
+			blockReceived.hook.OnBlockReceived(block, source)
+			return true
+		})
+	}
+}
+
+// BeforePropagate runs the OnBeforePropagate hooks, it returns false if a hook dropped the message
+func (h *Hooks) BeforePropagate(msg bxmessage.Message) bool {
+	if h == nil {
+		return true
+	}
+	for _, beforePropagate := range h.beforePropagate {
+		if !run(beforePropagate.name, func() bool { return beforePropagate.hook.OnBeforePropagate(msg) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// EnrichTx runs the OnTxEnrich hooks, it returns the annotations of the transaction by hook name
+func (h *Hooks) EnrichTx(tx *types.NewTransactionNotification) map[string]interface{} {
+	if h == nil {
+		return nil
+	}
+	var enrichment map[string]interface{}
+	for _, txEnrich := range h.txEnrich {
+		run(txEnrich.name, func() bool {
+			if annotations := txEnrich.hook.OnTxEnrich(tx); annotations != nil {
+				if enrichment == nil {
+					enrichment = make(map[string]interface{})
+				}
+				enrichment[txEnrich.name] = annotations
+			}
+			return true
+		})
+	}
+	return enrichment
+}
+
+// run calls the hook, recovering from its panics so a faulty hook does not stop the gateway nor drop messages
+func run(name string, hook func() bool) (result bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("hook %v panicked: %v", name, r)
+			result = true
+		}
+	}()
+	return hook()
+}
@@ -0,0 +1,64 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type dropTxHook struct {
+	dropped types.SHA256Hash
+}
+
+func (h dropTxHook) OnTxReceived(tx *types.BxTransaction, _ Source) bool {
+	return tx.Hash() != h.dropped
+}
+
+type blockHook struct {
+	blocks *int
+}
+
+func (h blockHook) OnBlockReceived(*types.BxBlock, Source) {
+	*h.blocks++
+}
+
+func (h blockHook) OnBeforePropagate(bxmessage.Message) bool {
+	panic("faulty hook")
+}
+
+type enrichHook struct{}
+
+func (enrichHook) OnTxEnrich(*types.NewTransactionNotification) interface{} {
+	return "labeled"
+}
+
+func TestHooks(t *testing.T) {
+	var empty *Hooks
+	assert.True(t, empty.TxReceived(nil, Source{}))
+	assert.True(t, empty.BeforePropagate(nil))
+	assert.Nil(t, empty.EnrichTx(nil))
+
+	dropped := types.GenerateSHA256Hash()
+	blocks := 0
+	Register("test-drop-tx", dropTxHook{dropped: dropped})
+	Register("test-block", blockHook{blocks: &blocks})
+	Register("test-enrich", enrichHook{})
+	assert.Panics(t, func() { Register("test-drop-tx", dropTxHook{}) })
+	assert.Panics(t, func() { Register("test-invalid", struct{}{}) })
+
+	h := New()
+	assert.Equal(t, []string{"test-block", "test-drop-tx", "test-enrich"}, h.Names())
+
+	assert.False(t, h.TxReceived(types.NewRawBxTransaction(dropped, nil), Source{}))
+	assert.True(t, h.TxReceived(types.NewRawBxTransaction(types.GenerateSHA256Hash(), nil), Source{}))
+
+	h.BlockReceived(&types.BxBlock{}, Source{})
+	assert.Equal(t, 1, blocks)
+
+	// a hook which panics does not drop the message
+	assert.True(t, h.BeforePropagate(&bxmessage.Ping{}))
+
+	assert.Equal(t, map[string]interface{}{"test-enrich": "labeled"}, h.EnrichTx(nil))
+}
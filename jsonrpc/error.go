@@ -25,13 +25,21 @@ const (
 
 	// Blocked - blocked
 	Blocked RPCErrorCode = -32001
+
+	// RateLimited - the account exceeded the rate limit of the method
+	RateLimited RPCErrorCode = -32005
+
+	// SubscriptionLimit - the subscription exceeds the subscription limits of the account
+	SubscriptionLimit RPCErrorCode = -32006
 )
 
 // ErrorMsg is a mapping of codes to error messages
 var ErrorMsg = map[RPCErrorCode]string{
-	MethodNotFound: "Invalid method",
-	InvalidParams:  "Invalid params",
-	AccountIDError: "Invalid account ID",
-	InternalError:  "Internal error",
-	Blocked:        "Insufficient quota",
+	MethodNotFound:    "Invalid method",
+	InvalidParams:     "Invalid params",
+	AccountIDError:    "Invalid account ID",
+	InternalError:     "Internal error",
+	Blocked:           "Insufficient quota",
+	RateLimited:       "Rate limit exceeded",
+	SubscriptionLimit: "Subscription limit exceeded",
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	uuid "github.com/satori/go.uuid"
 )
@@ -18,6 +19,7 @@ const (
 	RPCUnsubscribe                RPCRequestType = "unsubscribe"
 	RPCPrivateTxBalance           RPCRequestType = "private_tx_balance"
 	RPCPrivateTx                  RPCRequestType = "blxr_private_tx"
+	RPCMEVShareTx                 RPCRequestType = "blxr_mev_share_tx"
 	RPCTx                         RPCRequestType = "blxr_tx"
 	RPCPing                       RPCRequestType = "ping"
 	RPCMEVSearcher                RPCRequestType = "blxr_mev_searcher" // Deprecated: use blxr_submit_bundle instead. Will be removed in the future.
@@ -25,6 +27,7 @@ const (
 	RPCQuotaUsage                 RPCRequestType = "quota_usage"
 	RPCBundleSubmission           RPCRequestType = "blxr_submit_bundle"
 	RPCBundleSimulation           RPCRequestType = "blxr_simulate_bundle"
+	RPCBundleCancellation         RPCRequestType = "blxr_cancel_bundle"
 	RPCMegaBundleSubmission       RPCRequestType = "blxr_submit_mega_bundle"
 	RPCStartMonitoringTx          RPCRequestType = "start_monitor_transaction"
 	RPCStopMonitoringTx           RPCRequestType = "stop_monitor_transaction"
@@ -33,6 +36,18 @@ const (
 	RPCEthSubscribe               RPCRequestType = "eth_subscribe"
 	RPCEthSendRawTransaction      RPCRequestType = "eth_sendRawTransaction"
 	RPCEthUnsubscribe             RPCRequestType = "eth_unsubscribe"
+	RPCSubscriptionTransferToken  RPCRequestType = "subscription_transfer_token"
+	RPCSubscriptionTransfer       RPCRequestType = "subscription_transfer"
+	RPCNodeStatus                 RPCRequestType = "blxr_node_status"
+	RPCBDNStatus                  RPCRequestType = "blxr_bdn_status"
+	RPCDenylist                   RPCRequestType = "blxr_denylist"
+	RPCValidatorList              RPCRequestType = "blxr_validator_list"
+	RPCTime                       RPCRequestType = "blxr_time"
+	RPCConfig                     RPCRequestType = "blxr_config"
+	RPCVersion                    RPCRequestType = "blxr_version"
+	RPCWasmFilter                 RPCRequestType = "blxr_wasm_filter"
+	RPCFeeHistory                 RPCRequestType = "blxr_fee_history"
+	RPCAccountConnections         RPCRequestType = "blxr_account_connections"
 )
 
 // External RPCRequestType enumeration
@@ -60,6 +75,65 @@ type RPCTxPayload struct {
 	OriginalRPCMethod       RPCRequestType `json:"original_rpc_method"`
 	NodeValidation          bool           `json:"node_validation"`
 	FrontRunningProtection  bool           `json:"front_running_protection"`
+
+	// ValidatorsOnlySet and FrontRunningProtectionSet are false if the request omitted the flags
+	ValidatorsOnlySet         bool `json:"-"`
+	FrontRunningProtectionSet bool `json:"-"`
+}
+
+// RPCPrivateTxPayload is the payload of blxr_private_tx request, the tx is delivered to the nodes like any other tx
+// if it is not included in a block within the timeout in seconds, 0 keeps it private
+type RPCPrivateTxPayload struct {
+	Transaction string `json:"transaction"`
+	Timeout     uint64 `json:"timeout"`
+}
+
+// RPCMEVShareTxPayload is the payload of blxr_mev_share_tx request, the tx is kept private as a blxr_private_tx
+// while the hints are published to the searchers, the default hints are shared if none is listed
+type RPCMEVShareTxPayload struct {
+	Transaction string   `json:"transaction"`
+	Hints       []string `json:"hints"`
+	Timeout     uint64   `json:"timeout"`
+}
+
+// Validate doing validation for blxr_mev_share_tx payload
+func (p RPCMEVShareTxPayload) Validate() error {
+	for _, hint := range p.Hints {
+		switch hint {
+		case types.MEVShareHintCalldata, types.MEVShareHintContractAddress, types.MEVShareHintFunctionSelector, types.MEVShareHintLogs:
+		default:
+			return fmt.Errorf("invalid hint %v, must be one of %v, %v, %v or %v", hint, types.MEVShareHintCalldata,
+				types.MEVShareHintContractAddress, types.MEVShareHintFunctionSelector, types.MEVShareHintLogs)
+		}
+	}
+	return nil
+}
+
+// RPCDenylistPayload is the payload of blxr_denylist request, action is one of add, remove, reload or list
+type RPCDenylistPayload struct {
+	Action         string   `json:"action"`
+	Addresses      []string `json:"addresses"`
+	TxHashPatterns []string `json:"tx_hash_patterns"`
+}
+
+// RPCWasmFilterPayload is the payload of blxr_wasm_filter request, action is one of upload, remove or list. The module
+// is the base64 encoded WASM binary of an upload.
+type RPCWasmFilterPayload struct {
+	Action string `json:"action"`
+	Name   string `json:"name"`
+	Module string `json:"module"`
+}
+
+// RPCValidatorListPayload is the payload of blxr_validator_list request
+type RPCValidatorListPayload struct {
+	BlockHeight uint64 `json:"block_height"`
+}
+
+// RPCFeeHistoryPayload is the payload of blxr_fee_history request, a zero newest block selects the latest block
+type RPCFeeHistoryPayload struct {
+	BlockCount        uint64    `json:"block_count"`
+	NewestBlock       uint64    `json:"newest_block"`
+	RewardPercentiles []float64 `json:"reward_percentiles"`
 }
 
 // RPCBatchTxPayload is the payload of blxr_batch_tx request
@@ -73,14 +147,14 @@ type RPCBatchTxPayload struct {
 type rpcTxJSON struct {
 	Transaction             string         `json:"transaction"`
 	MevBundleTx             bool           `json:"mev_bundle_tx"`
-	ValidatorsOnly          bool           `json:"validators_only"`
+	ValidatorsOnly          *bool          `json:"validators_only"`
 	NextValidator           bool           `json:"next_validator"`
 	Fallback                uint16         `json:"fall_back"`
 	BlockchainNetwork       string         `json:"blockchain_network"`
 	OriginalSenderAccountID string         `json:"original_sender_account_id"`
 	OriginalRPCMethod       RPCRequestType `json:"original_rpc_method"`
 	NodeValidation          bool           `json:"node_validation"`
-	FrontRunningProtection  bool           `json:"front_running_protection"`
+	FrontRunningProtection  *bool          `json:"front_running_protection"`
 }
 
 // UnmarshalJSON provides a compatibility layer for go-ethereum style RPC calls, which are [object], instead of just object.
@@ -103,14 +177,20 @@ func (p *RPCTxPayload) UnmarshalJSON(b []byte) error {
 	}
 
 	p.Transaction = payload.Transaction
-	p.ValidatorsOnly = payload.ValidatorsOnly
+	if payload.ValidatorsOnly != nil {
+		p.ValidatorsOnly = *payload.ValidatorsOnly
+		p.ValidatorsOnlySet = true
+	}
 	p.BlockchainNetwork = payload.BlockchainNetwork
 	p.OriginalSenderAccountID = payload.OriginalSenderAccountID
 	p.NextValidator = payload.NextValidator
 	p.Fallback = payload.Fallback
 	p.OriginalRPCMethod = payload.OriginalRPCMethod
 	p.NodeValidation = payload.NodeValidation
-	p.FrontRunningProtection = payload.FrontRunningProtection
+	if payload.FrontRunningProtection != nil {
+		p.FrontRunningProtection = *payload.FrontRunningProtection
+		p.FrontRunningProtectionSet = true
+	}
 	p.MevBundleTx = payload.MevBundleTx
 
 	return nil
@@ -165,6 +245,53 @@ func (p RPCBundleSubmissionPayload) Validate() error {
 
 }
 
+// RPCBundleCancellationPayload is the payload of blxr_cancel_bundle request, retracting the bundles submitted with
+// the UUID from the builders
+type RPCBundleCancellationPayload struct {
+	UUID                    string            `json:"uuid"`
+	BlockNumber             string            `json:"block_number"`
+	MEVBuilders             map[string]string `json:"mev_builders"`
+	OriginalSenderAccountID string            `json:"original_sender_account_id"`
+}
+
+// Validate doing validation for blxr_cancel_bundle payload
+func (p RPCBundleCancellationPayload) Validate() error {
+	if p.UUID == "" {
+		return errors.New("bundle missing uuid")
+	}
+	if _, err := uuid.FromString(p.UUID); err != nil {
+		return fmt.Errorf("invalid UUID, %v", err)
+	}
+	if p.BlockNumber != "" {
+		if _, err := hexutil.DecodeUint64(p.BlockNumber); err != nil {
+			return fmt.Errorf("blockNumber must be hex, %v", err)
+		}
+	}
+	return nil
+}
+
+// RPCBundleSimulationPayload is the payload of blxr_simulate_bundle request
+type RPCBundleSimulationPayload struct {
+	Transaction      []string `json:"transaction"`
+	BlockNumber      string   `json:"block_number"`
+	StateBlockNumber string   `json:"state_block_number"` // block tag or hex number of the state, latest if empty
+	Timestamp        int64    `json:"timestamp,omitempty"`
+}
+
+// Validate doing validation for blxr_simulate_bundle payload
+func (p RPCBundleSimulationPayload) Validate() error {
+	if len(p.Transaction) == 0 {
+		return errors.New("bundle missing txs")
+	}
+	if p.Timestamp < 0 {
+		return errors.New("timestamp must be greater than or equal to 0")
+	}
+	if _, err := hexutil.DecodeUint64(p.BlockNumber); err != nil {
+		return fmt.Errorf("blockNumber must be hex, %v", err)
+	}
+	return nil
+}
+
 // RPCMEVSearcherPayload is the payload of blxr_searcher request
 // Depreceted: use RPCBundleSubmissionPayload instead. Will be removed in the future.
 type RPCMEVSearcherPayload struct {
@@ -186,6 +313,8 @@ type RPCSendBundle struct {
 	Frontrunning      bool     `json:"frontrunning,omitempty"`
 	BundlePrice       int64    `json:"bundlePrice,omitempty"` // in wei
 	EnforcePayout     bool     `json:"enforcePayout,omitempty"`
+	// ReplacementUUID is the UUID of the bundle for the builders, a bundle supersedes the previous one with its UUID
+	ReplacementUUID string `json:"replacementUuid,omitempty"`
 }
 
 // RPCCancelBundlePayload custom json-rpc required to cancel flashbots bundle
@@ -11,6 +11,9 @@ func TestRPCTxPayload_UnmarshalJSON(t *testing.T) {
 	original := RPCTxPayload{
 		Transaction:    "12312312312abacasdf",
 		ValidatorsOnly: false,
+		// flags are serialized, so they are set when deserialized
+		ValidatorsOnlySet:         true,
+		FrontRunningProtectionSet: true,
 	}
 
 	singleSerialized, err := json.Marshal(original)
@@ -30,6 +33,16 @@ func TestRPCTxPayload_UnmarshalJSON(t *testing.T) {
 	assert.Equal(t, original, gethResult)
 }
 
+func TestRPCTxPayload_UnmarshalJSONOmittedFlags(t *testing.T) {
+	var result RPCTxPayload
+	err := json.Unmarshal([]byte(`{"transaction": "abcd", "front_running_protection": true}`), &result)
+	assert.Nil(t, err)
+	assert.True(t, result.FrontRunningProtection)
+	assert.True(t, result.FrontRunningProtectionSet)
+	assert.False(t, result.ValidatorsOnly)
+	assert.False(t, result.ValidatorsOnlySet)
+}
+
 func TestRPCBatchTxPayload_UnmarshalJSON(t *testing.T) {
 	original := RPCBatchTxPayload{
 		Transactions:   []string{"12312312312abacasdf", "12312312312abacasdd"},
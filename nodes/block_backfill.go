@@ -0,0 +1,43 @@
+package nodes
+
+import (
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+)
+
+// requestBlockBackfill asks the blockchain backend for the blocks between the best block and the new block of the
+// height from the node, which the gateway missed while the backend restarted or the bridge overflowed
+func (g *gateway) requestBlockBackfill(height int) {
+	if g.BxConfig.BlockBackfillMaxBlocks <= 0 || g.bestBlockHeight == 0 || height <= g.bestBlockHeight+1 {
+		return
+	}
+
+	// a larger gap is a node catching up after a resync rather than a disruption, its blocks are not worth publishing
+	missed := height - g.bestBlockHeight - 1
+	if missed > g.BxConfig.BlockBackfillMaxBlocks {
+		g.log.Warnf("missed %v blocks before block %v from the node, more than the %v blocks which are backfilled", missed, height, g.BxConfig.BlockBackfillMaxBlocks)
+		return
+	}
+
+	request := blockchain.BlockBackfillRequest{From: uint64(g.bestBlockHeight + 1), To: uint64(height - 1)}
+	g.log.Infof("missed blocks %v to %v from the node, requesting their backfill", request.From, request.To)
+	if err := g.bridge.SendBlockBackfillRequest(request); err != nil {
+		g.log.Errorf("failed to request the backfill of blocks %v to %v: %v", request.From, request.To, err)
+	}
+}
+
+// publishBackfilledBlock publishes a block the gateway missed to the block feeds, marked as backfilled. The backend
+// sends the blocks of a backfill in order, the feeds skip the ones published meanwhile as duplicates.
+func (g *gateway) publishBackfilledBlock(backfilledBlock blockchain.BlockFromNode) {
+	bxBlock := backfilledBlock.Block
+	g.recordBlockTxs(bxBlock)
+
+	if !g.feedManager.NeedBlocks() {
+		return
+	}
+
+	source := connections.NewBlockchainConn(backfilledBlock.PeerEndpoint)
+	if err := g.notifyBlockFeeds(bxBlock, &source, nil, true, true); err != nil {
+		source.Log().Errorf("failed to publish backfilled block %v: %v", bxBlock, err)
+	}
+}
@@ -0,0 +1,94 @@
+package nodes
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	bxrpc "github.com/bloXroute-Labs/gateway/v2/rpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// runFeedPeer merges the newTxs feed of the peer gateway into the local feeds, resubscribing until the context is done
+func (g *gateway) runFeedPeer(ctx context.Context, peer string) {
+	l := g.log.WithField("feedPeer", peer)
+	for {
+		err := g.subscribeFeedPeer(ctx, peer)
+		if ctx.Err() != nil {
+			return
+		}
+		l.Warnf("newTxs feed of peer gateway was closed, resubscribing in %v: %v", bxgateway.FeedPeerReconnectInterval, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bxgateway.FeedPeerReconnectInterval):
+		}
+	}
+}
+
+func (g *gateway) subscribeFeedPeer(ctx context.Context, peer string) error {
+	options := []grpc.DialOption{grpc.WithInsecure()}
+	if g.BxConfig.FeedPeerTLS {
+		options = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}))}
+	}
+	// the auth header is only sent over TLS or to loopback peers, as validated by the config
+	if g.BxConfig.FeedPeerAuthHeader != "" {
+		options = append(options, bxrpc.NewBLXRCredentials(g.BxConfig.FeedPeerAuthHeader))
+	}
+	conn, err := grpc.DialContext(ctx, peer, options...)
+	if err != nil {
+		return fmt.Errorf("could not connect: %v", err)
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewGatewayClient(conn).NewTxs(ctx, &pb.TxsRequest{})
+	if err != nil {
+		return fmt.Errorf("could not subscribe: %v", err)
+	}
+	g.log.Infof("subscribed to newTxs feed of peer gateway %v", peer)
+
+	for {
+		reply, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		for _, tx := range reply.GetTx() {
+			g.publishFeedPeerTx(tx.GetRawTx(), peer)
+		}
+	}
+}
+
+// publishFeedPeerTx publishes the transaction of the peer gateway to the newTxs feed unless it was already received
+// locally or from another peer
+func (g *gateway) publishFeedPeerTx(rawTx []byte, peer string) {
+	var ethTx ethtypes.Transaction
+	if err := ethTx.UnmarshalBinary(rawTx); err != nil {
+		log.Debugf("failed to decode tx from peer gateway %v: %v", peer, err)
+		return
+	}
+	content, err := rlp.EncodeToBytes(&ethTx)
+	if err != nil {
+		log.Debugf("failed to encode tx %v from peer gateway %v: %v", ethTx.Hash(), peer, err)
+		return
+	}
+
+	hash := types.SHA256Hash(ethTx.Hash())
+	if g.TxStore.HasContent(hash) || !g.feedPeerTxs.SetIfAbsent(hash.String(), 15*time.Minute) {
+		return
+	}
+
+	bxTx := types.NewBxTransaction(hash, g.sdn.NetworkNum(), 0, g.clock.Now())
+	bxTx.SetContent(content)
+	notification := types.CreateNewTransactionNotification(bxTx)
+	notification.SetOrigin(peer)
+	g.notify(notification)
+}
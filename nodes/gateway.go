@@ -10,6 +10,7 @@ import (
 	"io"
 	"math"
 	"math/big"
+	"net"
 	"net/http"
 	"os"
 	"path"
@@ -20,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2/hooks"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
@@ -54,6 +56,8 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/servers"
 	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/capture"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
 	"github.com/bloXroute-Labs/gateway/v2/services/loggers"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
 	"github.com/bloXroute-Labs/gateway/v2/types"
@@ -76,6 +80,10 @@ const (
 
 var (
 	errUnsupportedBlockType = errors.New("block type is not supported")
+
+	publishedFeeds = []types.FeedType{types.NewTxsFeed, types.PendingTxsFeed, types.BDNBlocksFeed, types.NewBlocksFeed,
+		types.OnBlockFeed, types.TxReceiptsFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.NextSprintValidatorsFeed,
+		types.ProposerDutiesFeed, types.TopOfBlockFeed, types.ContractCreationsFeed, types.LogsFeed, types.MEVShareFeed}
 )
 
 type gateway struct {
@@ -85,6 +93,7 @@ type gateway struct {
 
 	sslCerts           *utils.SSLCerts
 	sdn                connections.SDNHTTP
+	accountCache       *services.AccountCache
 	accountID          types.AccountID
 	bridge             blockchain.Bridge
 	feedManager        *servers.FeedManager
@@ -96,25 +105,59 @@ type gateway struct {
 	pendingTxs         services.HashHistory
 	possiblePendingTxs services.HashHistory
 	txTrace            loggers.TxTrace
+	capture            capture.Recorder
 	blockchainPeers    []types.NodeEndpoint
 	stats              statistics.Stats
 	bdnBlocks          services.HashHistory
+	feedPeerTxs        services.HashHistory
 	newBlocks          services.HashHistory
+	blockHeaders       services.HashHistory
+	topOfBlocks        services.HashHistory
+	recentBlockTxs     services.HashHistory
+	blockSources       *services.BlockSourceTracker
 	wsManager          blockchain.WSManager
 	syncedWithRelay    atomic.Bool
 	clock              utils.Clock
 	timeStarted        time.Time
 	burstLimiter       services.AccountBurstLimiter
+	cluster            services.Cluster
+	feedLeader         services.FeedLeaderElector
+	txPoolMonitor      *blockchain.TxPoolMonitor
+	canary             *blockchain.Canary
+	feedVerifier       *blockchain.FeedVerifier
+	txStatusTracker    *services.TxStatusTracker
+	securityEvents     *services.SecurityEventNotifier
+	jwtVerifier        *services.JWTVerifier
+	feeTracker         *blockchain.FeeTracker
+	denylist           *services.Denylist
+	filterFiles        *services.AddressSets
+	addressLabels      *services.AddressLabels
+	bdnTxValidator     *services.BDNTxValidator
+	featureFlags       *services.FeatureFlags
+	hooks              *hooks.Hooks
+	contractAllowlist  *services.ContractAllowlist
+
+	// standingSubscriptions are served by the feed manager without a connected client
+	standingSubscriptions []servers.StandingSubscription
+
+	txPoolReconciliationLock sync.Mutex
+	txPoolReconciliation     *blockchain.TxPoolReconciliation
+
+	networkConfigLock    sync.Mutex
+	networkConfig        *network.EthConfig
+	networkConfigUpdates atomic.Uint64
 
 	bestBlockHeight       int
 	bdnBlocksSkipCount    int
 	seenMEVBundles        services.HashHistory
 	seenMEVMinerBundles   services.HashHistory
 	seenMEVSearchers      services.HashHistory
+	seenMEVShareHints     services.HashHistory
 	seenBlockConfirmation services.HashHistory
 
 	mevClient           *http.Client
 	mevBundleDispatcher *bundle.Dispatcher
+	bundleStatusTracker *services.BundleStatusTracker
 
 	blockProposer services.BlockProposer
 
@@ -124,13 +167,16 @@ type gateway struct {
 
 	staticEnodesCount            int
 	startupArgs                  string
-	validatorStatusMap           *syncmap.SyncMap[string, bool]     // validator addr -> online/offline
-	validatorListMap             *syncmap.SyncMap[uint64, []string] // block height -> list of validators
-	nextValidatorMap             *orderedmap.OrderedMap             // next accessible validator
+	validatorStatusMap           *syncmap.SyncMap[string, bool]   // validator addr -> online/offline
+	validatorListMap             *blockchain.ValidatorListHistory // block height -> list of validators
+	nextValidatorMap             *orderedmap.OrderedMap           // next accessible validator
+	nextValidatorStrategy        servers.NextValidatorStrategy    // nil if the network has no next_validator support
+	validatorSnapshot            *services.ValidatorSnapshot      // persists the validator maps across restarts
 	validatorListReady           bool
 	validatorInfoUpdateLock      sync.Mutex
 	latestValidatorInfo          []*types.FutureValidatorInfo
 	latestValidatorInfoHeight    int64
+	nextSprintValidatorsHeight   atomic.Uint64
 	transactionSlotStartDuration int
 	transactionSlotEndDuration   int
 	nextBlockTime                time.Time
@@ -139,8 +185,11 @@ type gateway struct {
 
 	polygonValidatorInfoManager polygon.ValidatorInfoManager
 	blockTime                   time.Duration
+	slotClock                   *utils.SlotClock
+	nodeRPCTimer                *blockchain.RPCTimer
 
 	grpcHandler   *servers.GrpcHandler
+	feedsServer   *servers.FeedsServer
 	txsQueue      services.MessageQueue
 	txsOrderQueue services.MessageQueue
 
@@ -186,6 +235,8 @@ func NewGateway(parent context.Context,
 	blocksToCacheWhileProposing int,
 	proposingInterval time.Duration,
 	txIncludeSenderInFeed bool,
+	slotClock *utils.SlotClock,
+	nodeRPCTimer *blockchain.RPCTimer,
 ) (Node, error) {
 
 	clock := utils.RealClock{}
@@ -201,10 +252,16 @@ func NewGateway(parent context.Context,
 		pendingTxs:                   services.NewHashHistory("pendingTxs", 15*time.Minute),
 		possiblePendingTxs:           services.NewHashHistory("possiblePendingTxs", 15*time.Minute),
 		bdnBlocks:                    services.NewHashHistory("bdnBlocks", 15*time.Minute),
+		feedPeerTxs:                  services.NewHashHistory("feedPeerTxs", 15*time.Minute),
 		newBlocks:                    services.NewHashHistory("newBlocks", 15*time.Minute),
+		blockHeaders:                 services.NewHashHistory("blockHeaders", 15*time.Minute),
+		topOfBlocks:                  services.NewHashHistory("topOfBlocks", 15*time.Minute),
+		recentBlockTxs:               services.NewHashHistory("recentBlockTxs", 15*time.Minute),
+		blockSources:                 services.NewBlockSourceTracker(clock),
 		seenMEVBundles:               services.NewHashHistory("mevBundle", 30*time.Minute),
 		seenMEVMinerBundles:          services.NewHashHistory("mevMinerBundle", 30*time.Minute),
 		seenMEVSearchers:             services.NewHashHistory("mevSearcher", 30*time.Minute),
+		seenMEVShareHints:            services.NewHashHistory("mevShareHint", 30*time.Minute),
 		seenBlockConfirmation:        services.NewHashHistory("blockConfirmation", 30*time.Minute),
 		clock:                        clock,
 		timeStarted:                  clock.Now(),
@@ -217,11 +274,18 @@ func NewGateway(parent context.Context,
 		sslCerts:                     sslCerts,
 		blockTime:                    blockTime,
 		txIncludeSenderInFeed:        txIncludeSenderInFeed,
+		slotClock:                    slotClock,
+		nodeRPCTimer:                 nodeRPCTimer,
 		log: log.WithFields(log.Fields{
 			"component": "gateway",
 		}),
 	}
 
+	// the SDN is read on each fetch, g.sdn may be replaced once the gateway is created
+	g.accountCache = services.NewAccountCache(bxConfig.AccountCache, clock, func(accountID types.AccountID) (sdnmessage.Account, error) {
+		return g.sdn.FetchCustomerAccountModel(accountID)
+	})
+
 	g.blockProposer = services.NewNoopBlockProposer(&g.TxStore, log.WithField("service", "noop-block-proposer"))
 
 	if polygonHeimdallEndpoints != "" {
@@ -230,13 +294,19 @@ func NewGateway(parent context.Context,
 		g.polygonValidatorInfoManager = nil
 	}
 
-	if bxConfig.BlockchainNetwork == bxgateway.BSCMainnet || bxConfig.BlockchainNetwork == bxgateway.PolygonMainnet || bxConfig.BlockchainNetwork == bxgateway.PolygonMumbai {
+	g.nextValidatorStrategy, _ = servers.NextValidatorStrategyOf(bxgateway.BlockchainNetworkToNetworkNum[bxConfig.BlockchainNetwork])
+	// the future validators of Mumbai are tracked as the ones of Polygon, without next_validator support
+	if g.nextValidatorStrategy != nil || bxConfig.BlockchainNetwork == bxgateway.PolygonMumbai {
 		g.validatorStatusMap = syncmap.NewStringMapOf[bool]()
 		g.nextValidatorMap = orderedmap.New()
+		g.validatorSnapshot = services.NewValidatorSnapshot(bxConfig.DataDir, bxgateway.ValidatorSnapshotMaxAge, utils.RealClock{}, g.nextValidatorMap, g.validatorStatusMap)
+		if _, err := g.validatorSnapshot.Load(); err != nil {
+			g.log.Warnf("next_validator txs are rejected until the validators are received: %v", err)
+		}
 	}
 
-	if bxConfig.BlockchainNetwork == bxgateway.BSCMainnet || bxConfig.BlockchainNetwork == bxgateway.BSCTestnet {
-		g.validatorListMap = syncmap.NewIntegerMapOf[uint64, []string]()
+	if bxConfig.BlockchainNetwork == bxgateway.BSCMainnet || bxConfig.BlockchainNetwork == bxgateway.BSCTestnet || g.epochValidators() {
+		g.validatorListMap = blockchain.NewValidatorListHistory(bxgateway.ValidatorListHistorySize)
 		g.validatorListReady = false
 		g.bscTxClient = &http.Client{
 			Transport: &http.Transport{
@@ -257,6 +327,10 @@ func NewGateway(parent context.Context,
 	// create tx store service pass to eth client
 	g.bdnStats = bxmessage.NewBDNStats(blockchainPeers, recommendedPeers)
 	g.burstLimiter = services.NewAccountBurstLimiter(g.clock)
+	g.cluster = services.NoOpCluster{}
+	g.feedLeader = services.AlwaysLeader{}
+	g.txPoolMonitor = blockchain.NewTxPoolMonitor(bxConfig.NodeTxPoolMonitor, wsManager, g.clock)
+	g.feeTracker = blockchain.NewFeeTracker(bxgateway.FeeHistorySize)
 
 	// set empty default stats, Run function will override it
 	g.stats = statistics.NewStats(false, "127.0.0.1", "", nil, false)
@@ -286,6 +360,60 @@ func NewGateway(parent context.Context,
 		g.bloomFilter = services.NoOpBloomFilter{}
 	}
 
+	g.contractAllowlist = services.NewContractAllowlist(bxConfig.AccountAllowedContracts)
+
+	var err error
+	g.denylist, err = services.NewDenylist(bxConfig.DenylistFile)
+	if err != nil {
+		return nil, err
+	}
+	g.filterFiles = services.NewAddressSets(bxConfig.FilterFilesDir)
+
+	if bxConfig.AddressLabelsFile != "" {
+		g.addressLabels, err = services.LoadAddressLabels(bxConfig.AddressLabelsFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("loaded the labels of %v addresses", g.addressLabels.Len())
+	}
+
+	if bxConfig.StandingSubscriptionsFile != "" {
+		g.standingSubscriptions, err = servers.LoadStandingSubscriptions(bxConfig.StandingSubscriptionsFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, subscription := range g.standingSubscriptions {
+			if subscription.Sink.Type == servers.StandingSinkExport && bxConfig.FeedExport.Dir == "" {
+				return nil, fmt.Errorf("standing subscription %q exports its notifications, which requires --%v", subscription.Name, utils.FeedExportDirFlag.Name)
+			}
+		}
+		log.Infof("loaded %v standing subscriptions", len(g.standingSubscriptions))
+	}
+
+	g.featureFlags, err = services.NewFeatureFlags(bxConfig.FeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	g.hooks = hooks.New()
+
+	g.canary, err = blockchain.NewCanary(bxConfig.Canary, wsManager, g.clock, g.submitCanaryTx)
+	if err != nil {
+		return nil, err
+	}
+
+	g.feedVerifier, err = blockchain.NewFeedVerifier(bxConfig.FeedVerification, wsManager, g.clock)
+	if err != nil {
+		return nil, err
+	}
+
+	g.securityEvents = services.NewSecurityEventNotifier(bxConfig.SecurityEvents, g.clock)
+
+	g.jwtVerifier, err = services.NewJWTVerifier(bxConfig.JWT, g.clock)
+	if err != nil {
+		return nil, err
+	}
+
 	return g, nil
 }
 
@@ -304,11 +432,24 @@ func (g *gateway) setSyncWithRelay() {
 	g.syncedWithRelay.Store(true)
 }
 
-func (g *gateway) setupTxStore() {
+func (g *gateway) setupTxStore() error {
 	assigner := services.NewEmptyShortIDAssigner()
-	g.TxStore = services.NewEthTxStore(g.clock, 30*time.Minute, 3*24*time.Hour, 10*time.Minute,
+	txStore := services.NewEthTxStore(g.clock, 30*time.Minute, 3*24*time.Hour, 10*time.Minute,
 		assigner, services.NewHashHistory("seenTxs", 30*time.Minute), nil, *g.sdn.Networks(), g.bloomFilter)
+	if g.BxConfig.TxStorePersistence {
+		// the recovered short IDs let the blocks from the BDN be decompressed while the TxStore re-syncs with the relay
+		persistence, err := services.NewPebbleTxStorePersistence(g.BxConfig.DataDir)
+		if err != nil {
+			return err
+		}
+		if err = txStore.Recover(persistence); err != nil {
+			_ = persistence.Close()
+			return err
+		}
+	}
+	g.TxStore = txStore
 	g.blockProcessor = services.NewBlockProcessor(g.TxStore)
+	return nil
 }
 
 // InitSDN initialize SDN, get account model
@@ -317,11 +458,8 @@ func InitSDN(bxConfig *config.Bx, blockchainPeers []types.NodeEndpoint, gatewayP
 	isDocker := !os.IsNotExist(err)
 	hostname, _ := os.Hostname()
 
-	privateCertDir := path.Join(bxConfig.DataDir, "ssl")
 	gatewayType := bxConfig.NodeType
 	gatewayMode := bxConfig.GatewayMode
-	privateCertFile, privateKeyFile, registrationOnlyCertFile, registrationOnlyKeyFile := utils.GetCertDir(bxConfig.RegistrationCertDir, privateCertDir, strings.ToLower(gatewayType.String()))
-	sslCerts := utils.NewSSLCertsFromFiles(privateCertFile, privateKeyFile, registrationOnlyCertFile, registrationOnlyKeyFile)
 	blockchainPeerEndpoint := types.NodeEndpoint{IP: "", Port: 0, PublicKey: ""}
 	if len(blockchainPeers) > 0 {
 		blockchainPeerEndpoint.IP = blockchainPeers[0].IP
@@ -348,8 +486,24 @@ func InitSDN(bxConfig *config.Bx, blockchainPeers []types.NodeEndpoint, gatewayP
 		NodeStartTime:        time.Now().Format(bxgateway.TimeLayoutISO),
 		StartupArgs:          strings.Join(os.Args[1:], " "),
 		BlockchainRPCEnabled: bxConfig.EnableBlockchainRPC,
+		BuildInfo:            bxmessage.BuildInfo(),
 	}
 
+	if bxConfig.LocalMode {
+		// there is no SDN to register with, the gateway does not need certificates nor an account
+		log.Warnf("running in local mode: the gateway is not connected to the SDN and relays, transactions are sent to the blockchain node only")
+		sdn, err := connections.NewLocalSDNHTTP(nodeModel, bxConfig.BlockchainNetwork)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &utils.SSLCerts{}, sdn, nil
+	}
+
+	privateCertDir := path.Join(bxConfig.DataDir, "ssl")
+	privateCertFile, privateKeyFile, registrationOnlyCertFile, registrationOnlyKeyFile := utils.GetCertDir(bxConfig.RegistrationCertDir, privateCertDir, strings.ToLower(gatewayType.String()))
+	sslCerts := utils.NewSSLCertsFromFiles(privateCertFile, privateKeyFile, registrationOnlyCertFile, registrationOnlyKeyFile)
+	// the SDN and relay connections are opened through the outbound proxy of their destination
+	connections.SetOutboundProxy(connections.NewOutboundProxy(bxConfig.OutboundProxy))
 	sdn := connections.NewSDNHTTP(&sslCerts, bxConfig.SDNURL, nodeModel, bxConfig.DataDir)
 
 	err = sdn.InitGateway(bxgateway.Ethereum, bxConfig.BlockchainNetwork)
@@ -418,7 +572,9 @@ func (g *gateway) Run() error {
 	accountModel := g.sdn.AccountModel()
 
 	// once we called InitGateway() we have the Networks so we can setup TxStore
-	g.setupTxStore()
+	if err := g.setupTxStore(); err != nil {
+		return err
+	}
 
 	g.burstLimiter.Register(&accountModel)
 	var err error
@@ -439,8 +595,27 @@ func (g *gateway) Run() error {
 	}
 	g.txTrace = loggers.NewTxTrace(txTraceLogger)
 
+	g.capture, err = capture.NewRecorder(g.BxConfig.CaptureFile, g.BxConfig.CaptureFileSize, g.clock)
+	if err != nil {
+		return fmt.Errorf("failed to create message capture: %v", err)
+	}
+	if g.BxConfig.CaptureFile != "" {
+		g.log.Infof("capturing received messages to %v", g.BxConfig.CaptureFile)
+	}
+
 	networkNum := g.sdn.NetworkNum()
 
+	g.featureFlags.UpdateRemote(g.sdn.NodeModel().FeatureFlags)
+	go g.refreshFeatureFlagsOnInterval(ctx, bxgateway.FeatureFlagsRefreshInterval)
+
+	if names := g.hooks.Names(); len(names) > 0 {
+		g.log.Infof("running hooks %v", strings.Join(names, ", "))
+	}
+
+	if g.BxConfig.ValidateBDNTxs {
+		g.bdnTxValidator = services.NewBDNTxValidator(bxgateway.NetworkNumToChainID[networkNum], g.BxConfig.BDNTxValidationWorkers, bxgateway.BDNTxValidationQueueSize)
+	}
+
 	err = g.pushBlockchainConfig()
 	if err != nil {
 		return fmt.Errorf("could process initial blockchain configuration: %v", err)
@@ -457,12 +632,67 @@ func (g *gateway) Run() error {
 
 	go g.TxStore.Start()
 	go g.updateValidatorStateMap()
+	go g.handleProposerDuties()
+	go g.handleBlockHeaders()
+
+	if len(g.BxConfig.ClusterPeers) > 0 {
+		g.cluster = services.NewCluster(services.ClusterConfig{
+			NodeID:         nodeID,
+			ListenAddress:  g.BxConfig.ClusterListenAddress,
+			Peers:          g.BxConfig.ClusterPeers,
+			GossipInterval: bxgateway.ClusterGossipInterval,
+			PeerTimeout:    g.BxConfig.ClusterPeerTimeout,
+			Secret:         g.BxConfig.ClusterSecret,
+		}, g.clock)
+		group.Go(func() error {
+			return g.cluster.Start(ctx)
+		})
+	}
+
+	switch services.LeaderElectionMode(g.BxConfig.FeedLeaderElection) {
+	case services.LeaderElectionCluster:
+		g.feedLeader = services.NewClusterLeaderElector(g.cluster)
+	case services.LeaderElectionLockFile:
+		g.feedLeader = services.NewLockFileLeaderElector(g.BxConfig.FeedLeaderLockDir, nodeID, g.BxConfig.FeedLeaderLease, publishedFeeds, g.clock)
+	}
+	group.Go(func() error {
+		return g.feedLeader.Start(ctx)
+	})
+
+	go g.txPoolMonitor.Run(ctx)
+
+	go g.canary.Run(ctx)
+
+	go g.feedVerifier.Run(ctx)
+
+	go g.securityEvents.Run(ctx)
+
+	go g.jwtVerifier.Run(ctx)
+
+	go g.denylist.Watch(ctx, bxgateway.DenylistReloadInterval)
+
+	go g.filterFiles.Watch(ctx, bxgateway.FilterFilesReloadInterval)
+
+	go g.bdnTxValidator.Run(ctx)
+
+	for _, peer := range g.BxConfig.FeedPeers {
+		go g.runFeedPeer(ctx, peer)
+	}
+
+	if g.BxConfig.TxPoolReconciliationInterval > 0 {
+		go g.reconcileTxPoolOnInterval(ctx, g.BxConfig.TxPoolReconciliationInterval)
+	}
+
+	if g.validatorSnapshot != nil {
+		go g.validatorSnapshot.Run(ctx, bxgateway.ValidatorSnapshotInterval)
+	}
 
 	if g.BxConfig.NoStats {
 		g.stats = statistics.NoStats{}
 	} else {
 		g.stats = statistics.NewStats(g.BxConfig.FluentDEnabled, g.BxConfig.FluentDHost, g.sdn.NodeID(), g.sdn.Networks(), g.BxConfig.LogNetworkContent)
 	}
+	go g.reportClockSkewOnInterval(ctx, bxgateway.SlotClockSyncInterval)
 
 	sslCert := g.sslCerts
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
@@ -472,18 +702,35 @@ func (g *gateway) Run() error {
 		return fmt.Errorf("failed to find the blockchainNetwork with networkNum %v, %v", networkNum, err)
 	}
 
+	g.txStatusTracker = services.NewTxStatusTracker(g.TxStore, g.clock, g.notify)
+	g.bundleStatusTracker = services.NewBundleStatusTracker(g.clock, g.notify)
+	g.mevBundleDispatcher.OnResponse(g.bundleStatusTracker.BuilderResponse)
+	feedManagerOptions := servers.FeedManagerOptions{Denylist: g.denylist, ContractAllowlist: g.contractAllowlist, FeatureFlags: g.featureFlags, Enrichers: g.txEnrichers(),
+		StandingSubscriptions: g.standingSubscriptions, TxStatusTracker: g.txStatusTracker, BundleStatusTracker: g.bundleStatusTracker, TxStore: g.TxStore, BridgeSaturation: g.bridge.ChannelSaturation,
+		BridgeChannelFull: g.bridge.ChannelFullCounts, BlockSources: g.blockSources.Stats, SecurityEvents: g.securityEvents, JWTVerifier: g.jwtVerifier,
+		FilterFiles: g.filterFiles, NodeRPCTimer: g.nodeRPCTimer}
+	if g.BxConfig.TrafficMirror.AccountID != "" {
+		feedManagerOptions.Mirror, err = export.NewMirror(g.context, g.BxConfig.TrafficMirror, utils.RealClock{})
+		if err != nil {
+			return fmt.Errorf("failed to open traffic mirror file: %v", err)
+		}
+		log.Infof("mirroring the traffic of account %v to %v", g.BxConfig.TrafficMirror.AccountID, g.BxConfig.TrafficMirror.File)
+	}
+
 	g.feedManager = servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(), networkNum,
 		blockchainNetwork.DefaultAttributes.NetworkID, g.sdn.NodeModel().NodeID,
-		g.wsManager, accountModel, g.sdn.FetchCustomerAccountModel,
+		g.wsManager, accountModel, g.accountCache.Get,
 		sslCert.PrivateCertFile(), sslCert.PrivateKeyFile(), *g.BxConfig, g.stats, g.nextValidatorMap, g.validatorStatusMap,
+		feedManagerOptions,
 	)
 
 	txFromFieldIncludable := blockchainNetwork.EnableCheckSenderNonce || g.txIncludeSenderInFeed
 
 	g.grpcHandler = servers.NewGrpcHandler(g.feedManager, txFromFieldIncludable)
+	g.feedsServer = servers.NewFeedsServer(g.grpcHandler, g.authorizeFeeds)
 
-	// start feed manager if websocket or gRPC is enabled
-	if g.BxConfig.WebsocketEnabled || g.BxConfig.WebsocketTLSEnabled || g.BxConfig.GRPC.Enabled {
+	// start feed manager if websocket or gRPC is enabled, or if it serves standing subscriptions
+	if g.BxConfig.WebsocketEnabled || g.BxConfig.WebsocketTLSEnabled || g.BxConfig.GRPC.Enabled || len(g.standingSubscriptions) > 0 {
 		group.Go(func() error {
 			return g.feedManager.Start(ctx)
 		})
@@ -509,14 +756,24 @@ func (g *gateway) Run() error {
 
 	go g.PingLoop()
 
-	relayInstructions := make(chan connections.RelayInstruction)
-	go g.updateRelayConnections(relayInstructions, *sslCert, networkNum)
-	err = g.sdn.DirectRelayConnections(context.Background(), g.BxConfig.Relays, uint64(accountModel.RelayLimit.MsgQuota.Limit), relayInstructions, connections.AutoRelayTimeout)
-	if err != nil {
-		return err
-	}
+	if g.BxConfig.LocalMode {
+		// no relay will ever sync the gateway, transactions and blocks of the node are processed right away
+		g.setSyncWithRelay()
+	} else {
+		relayInstructions := make(chan connections.RelayInstruction)
+		go g.updateRelayConnections(relayInstructions, *sslCert, networkNum)
+		if g.BxConfig.RelayDNS != "" {
+			discovery := connections.NewRelayDiscovery(g.BxConfig.RelayDNS, g.BxConfig.RelayDNSInterval, int(accountModel.RelayLimit.MsgQuota.Limit), relayInstructions, g.clock)
+			go discovery.Run(ctx)
+		} else {
+			err = g.sdn.DirectRelayConnections(context.Background(), g.BxConfig.Relays, uint64(accountModel.RelayLimit.MsgQuota.Limit), relayInstructions, connections.AutoRelayTimeout)
+			if err != nil {
+				return err
+			}
+		}
 
-	go g.sendStatsOnInterval(15 * time.Minute)
+		go g.sendStatsOnInterval(15 * time.Minute)
+	}
 
 	if g.BxConfig.GRPC.Enabled {
 		g.grpcServer = newGatewayGRPCServer(g, g.BxConfig.Host, g.BxConfig.Port, g.BxConfig.User, g.BxConfig.Password)
@@ -555,6 +812,12 @@ func (g *gateway) Close() error {
 		g.grpcServer.Stop()
 	}
 
+	if g.capture != nil {
+		if err := g.capture.Close(); err != nil {
+			g.log.Errorf("failed to close capture file: %v", err)
+		}
+	}
+
 	if g.clientHandler != nil {
 		return g.clientHandler.Stop()
 	}
@@ -563,22 +826,36 @@ func (g *gateway) Close() error {
 }
 
 func (g *gateway) updateRelayConnections(relayInstructions chan connections.RelayInstruction, sslCerts utils.SSLCerts, networkNum types.NetworkNum) {
+	// the outbound relays by address, only accessed by this goroutine
+	relays := make(map[string]*handler.Relay)
 	for {
 		instruction := <-relayInstructions
+		addr := net.JoinHostPort(instruction.IP, strconv.FormatInt(instruction.Port, 10))
 
 		switch instruction.Type {
 		case connections.Connect:
-			g.connectRelay(instruction, sslCerts, networkNum)
+			if _, ok := relays[addr]; ok {
+				continue
+			}
+			relays[addr] = g.connectRelay(instruction, sslCerts, networkNum)
 		case connections.Disconnect:
-			// disconnectRelay
+			relay, ok := relays[addr]
+			if !ok {
+				continue
+			}
+			delete(relays, addr)
+			g.log.Infof("disconnecting from relay %v", addr)
+			// the relay is not reconnected once closed
+			_ = relay.Close("relay is no longer directed")
 		}
 	}
 }
 
-func (g *gateway) connectRelay(instruction connections.RelayInstruction, sslCerts utils.SSLCerts, networkNum types.NetworkNum) {
+func (g *gateway) connectRelay(instruction connections.RelayInstruction, sslCerts utils.SSLCerts, networkNum types.NetworkNum) *handler.Relay {
 	relay := handler.NewOutboundRelay(g, &sslCerts, instruction.IP, instruction.Port, g.sdn.NodeID(), utils.Relay,
 		g.BxConfig.PrioritySending, g.sdn.Networks(), true, false, utils.RealClock{}, false, g.isBDN)
 	relay.SetNetworkNum(networkNum)
+	relay.SetRecorder(g.capture)
 
 	relay.Start()
 
@@ -588,10 +865,14 @@ func (g *gateway) connectRelay(instruction connections.RelayInstruction, sslCert
 		"relayPort": instruction.Port,
 	}).Info("connecting to relay")
 
+	return relay
 }
 
 func (g *gateway) broadcast(msg bxmessage.Message, source connections.Conn, to utils.NodeType) types.BroadcastResults {
 	results := types.BroadcastResults{}
+	if !g.hooks.BeforePropagate(msg) {
+		return results
+	}
 
 	g.ConnectionsLock.RLock()
 	for _, conn := range g.Connections {
@@ -668,6 +949,15 @@ func (g *gateway) pushBlockchainConfig() error {
 		BlockConfirmationsCount: blockConfirmationsCount,
 	}
 
+	g.networkConfigLock.Lock()
+	if g.networkConfig != nil {
+		if changes, _ := g.networkConfig.Changes(ethConfig); len(changes) > 0 {
+			g.networkConfigUpdates.Inc()
+		}
+	}
+	g.networkConfig = &ethConfig
+	g.networkConfigLock.Unlock()
+
 	return g.bridge.UpdateNetworkConfig(ethConfig)
 }
 
@@ -731,16 +1021,22 @@ func (g *gateway) cleanUpNextValidatorMap(currentHeight uint64) {
 	}
 }
 
-func (g *gateway) generateBSCValidator(blockHeight uint64) []*types.FutureValidatorInfo {
+// epochValidators returns true if the next validators of the network are computed from its epoch blocks
+func (g *gateway) epochValidators() bool {
+	return g.nextValidatorStrategy != nil && g.nextValidatorStrategy.EpochLength() > 0
+}
+
+func (g *gateway) generateEpochValidator(blockHeight uint64) []*types.FutureValidatorInfo {
 	vi := blockchain.DefaultValidatorInfo(blockHeight)
 
-	if g.validatorListMap == nil || g.validatorStatusMap == nil || g.nextValidatorMap == nil {
+	if g.validatorListMap == nil || g.validatorStatusMap == nil || g.nextValidatorMap == nil || !g.epochValidators() {
 		return vi
 	}
 
-	// currentEpochBlockHeight will be the most recent block height that can be module by 200
-	currentEpochBlockHeight := blockHeight / 200 * 200
-	previousEpochBlockHeight := currentEpochBlockHeight - 200
+	// currentEpochBlockHeight will be the most recent block height that can be module by the epoch length
+	epochLength := g.nextValidatorStrategy.EpochLength()
+	currentEpochBlockHeight := blockHeight / epochLength * epochLength
+	previousEpochBlockHeight := currentEpochBlockHeight - epochLength
 	prevEpochValidatorList, exist := g.validatorListMap.Load(previousEpochBlockHeight)
 	if !exist { // we need previous epoch validator list to calculate
 		err := g.queryEpochBlock(previousEpochBlockHeight)
@@ -794,7 +1090,8 @@ func (g *gateway) generateBSCValidator(blockHeight uint64) []*types.FutureValida
 }
 
 func (g *gateway) reevaluatePendingBSCNextValidatorTx() {
-	now := time.Now()
+	// the fallback deadlines are measured with the slot clock, as when the transactions were received
+	now := g.slotClock.Now()
 	g.feedManager.LockPendingNextValidatorTxs()
 	defer g.feedManager.UnlockPendingNextValidatorTxs()
 
@@ -806,7 +1103,7 @@ func (g *gateway) reevaluatePendingBSCNextValidatorTx() {
 			timeSinceRequest := now.Sub(txInfo.TimeOfRequest)
 			adjustedFallback := fallback - timeSinceRequest
 
-			firstValidatorInaccessible, err := servers.ProcessNextValidatorTx(txInfo.Tx, uint16(adjustedFallback.Milliseconds()), g.nextValidatorMap, g.validatorStatusMap, txInfo.Tx.GetNetworkNum(), txInfo.Source, pendingNextValidatorTxsMap)
+			firstValidatorInaccessible, err := servers.ProcessNextValidatorTx(txInfo.Tx, uint16(adjustedFallback.Milliseconds()), g.nextValidatorMap, g.validatorStatusMap, txInfo.Tx.GetNetworkNum(), txInfo.Source, pendingNextValidatorTxsMap, now)
 			delete(pendingNextValidatorTxsMap, txHash)
 			l := g.log.WithFields(log.Fields{"txHash": txHash})
 
@@ -841,6 +1138,11 @@ func (g *gateway) generatePolygonValidator(bxBlock *types.BxBlock, blockInfo *et
 	validatorInfo := g.polygonValidatorInfoManager.FutureValidators(blockInfo.Block.Header())
 
 	for _, info := range validatorInfo {
+		if bor.IsSprintStart(info.BlockHeight) && g.feedManager.SubscriptionTypeExists(types.NextSprintValidatorsFeed) {
+			// the span may have to be fetched from Heimdall, block processing does not wait for it
+			go g.notifySprintProducers(info.BlockHeight)
+		}
+
 		if info.WalletID == "nil" {
 			break
 		}
@@ -873,15 +1175,17 @@ func (g *gateway) generateFutureValidatorInfo(block *types.BxBlock, blockInfo *e
 	case bxgateway.PolygonMainnetNum, bxgateway.PolygonMumbaiNum:
 		g.latestValidatorInfo = g.generatePolygonValidator(block, blockInfo)
 		return g.latestValidatorInfo
-	case bxgateway.BSCMainnetNum:
-		g.latestValidatorInfo = g.generateBSCValidator(block.Number.Uint64())
-		return g.latestValidatorInfo
 	default:
-		return nil
+		if !g.epochValidators() {
+			return nil
+		}
+		g.latestValidatorInfo = g.generateEpochValidator(block.Number.Uint64())
+		return g.latestValidatorInfo
 	}
 }
 
 func (g *gateway) publishBlock(bxBlock *types.BxBlock, nodeSource *connections.Blockchain, info []*types.FutureValidatorInfo, isBlockchainBlock bool) error {
+	g.recordBlockTxs(bxBlock)
 
 	// publishing a block means extracting the sender for all the block transactions which is heavy.
 	// if there are no active block related feed subscribers we can skip this.
@@ -918,14 +1222,14 @@ func (g *gateway) publishBlock(bxBlock *types.BxBlock, nodeSource *connections.B
 		}
 	}
 
-	if err := g.notifyBlockFeeds(bxBlock, nodeSource, info, isBlockchainBlock); err != nil {
+	if err := g.notifyBlockFeeds(bxBlock, nodeSource, info, isBlockchainBlock, false); err != nil {
 		return fmt.Errorf("cannot notify block feeds: %v", err)
 	}
 
 	return nil
 }
 
-func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connections.Blockchain, info []*types.FutureValidatorInfo, isBlockchainBlock bool) error {
+func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connections.Blockchain, info []*types.FutureValidatorInfo, isBlockchainBlock, backfilled bool) error {
 	// Not optimal. Block -> BxBlock -> Block
 	block, err := g.bridge.BlockBDNtoBlockchain(bxBlock)
 	if err != nil {
@@ -941,6 +1245,20 @@ func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connectio
 		if err != nil {
 			return err
 		}
+		ethNotification.Divergences = g.feedVerifier.Divergences()
+		ethNotification.Backfilled = backfilled
+		if arrival, ok := g.blockSources.Arrival(bxBlock.Hash().String()); ok {
+			ethNotification.FirstSource = arrival.Source
+			if arrival.BothSources {
+				leadTimeMs := arrival.LeadTime.Milliseconds()
+				ethNotification.LeadTimeMs = &leadTimeMs
+			}
+		}
+
+		// the top of the block is published once, as soon as the block is received from either the BDN or the node
+		if !backfilled && g.feedManager.SubscriptionTypeExists(types.TopOfBlockFeed) && g.topOfBlocks.SetIfAbsent(bxBlock.Hash().String(), 15*time.Minute) {
+			g.notify(types.NewTopOfBlockNotification(common.Hash(bxBlock.Hash()), block, g.BxConfig.TopOfBlockTxs))
+		}
 
 		if g.bdnBlocks.SetIfAbsent(bxBlock.Hash().String(), 15*time.Minute) {
 			// Send ETH notifications to BDN feed even if source is blockchain
@@ -957,8 +1275,13 @@ func (g *gateway) notifyBlockFeeds(bxBlock *types.BxBlock, nodeSource *connectio
 
 		if isBlockchainBlock {
 			if g.newBlocks.SetIfAbsent(bxBlock.Hash().String(), 15*time.Minute) {
-				g.bestBlockHeight = int(block.Number().Int64())
-				g.bdnBlocksSkipCount = 0
+				if !backfilled {
+					g.requestBlockBackfill(int(block.Number().Int64()))
+					g.bestBlockHeight = int(block.Number().Int64())
+					g.bdnBlocksSkipCount = 0
+				}
+				g.txStatusTracker.ObserveBlock(bxBlock)
+				g.bundleStatusTracker.ObserveBlock(bxBlock)
 
 				notification := ethNotification.Clone()
 				notification.SetNotificationType(types.NewBlocksFeed)
@@ -1029,18 +1352,38 @@ func (g *gateway) notifyTxReceiptsAndOnBlockFeeds(nodeSource *connections.Blockc
 	notification.SetSource(&sourceEndpoint)
 	g.notify(notification)
 
+	// the logs of each subscription are fetched with its own filters, like the onBlock calls
+	if g.feedManager.SubscriptionTypeExists(types.LogsFeed) {
+		notification = ethNotification.Clone()
+		notification.SetNotificationType(types.LogsFeed)
+		notification.SetSource(&sourceEndpoint)
+		g.notify(notification)
+	}
+
 	notification = ethNotification.Clone()
 	notification.SetSource(&sourceEndpoint)
 
+	if g.feedManager.SubscriptionTypeExists(types.ContractCreationsFeed) {
+		receipts, err := servers.HandleContractCreations(g.context, g.feedManager, notification.(*types.EthBlockNotification))
+		if err != nil {
+			log.Debugf("failed to handle contract creations: %v", err)
+		}
+		for _, receipt := range receipts {
+			g.notify(types.NewContractCreationNotification(receipt))
+		}
+	}
+
 	if g.feedManager.SubscriptionTypeExists(types.TxReceiptsFeed) {
-		receipts, err := servers.HandleTxReceipts(g.feedManager, notification.(*types.EthBlockNotification))
+		receipts, err := servers.HandleTxReceipts(g.context, g.feedManager, notification.(*types.EthBlockNotification))
 		if err != nil {
 			log.Printf("failed to handle tx receipts: %v", err)
 			return
 		}
-		if len(receipts) > 0 {
-			g.notify(types.NewTxReceiptsNotification(receipts))
-		}
+		// notify also blocks without receipts, so combined subscriptions waiting on the block barrier can complete them
+		txReceiptsNotification := types.NewTxReceiptsNotification(receipts)
+		txReceiptsNotification.BlockHash = ethNotification.GetHash()
+		txReceiptsNotification.BlockNumber = ethNotification.Header.Number
+		g.notify(txReceiptsNotification)
 	}
 }
 
@@ -1087,6 +1430,7 @@ func (g *gateway) handleBridgeMessages(ctx context.Context) error {
 					tx := bxmessage.NewTx(blockchainTx.Hash(), blockchainTx.Content(), g.sdn.NetworkNum(), types.TFLocalRegion, types.EmptyAccountID)
 					tx.SetReceiveTime(receiveTime.Add(-time.Microsecond))
 					tx.SetTimestamp(receiveTime)
+					g.capture.RecordMessage(capture.SourceBlockchain, tx)
 					g.processTransaction(tx, blockchainConnection)
 				}
 			}, "ReceiveNodeTransactions", txsFromNode.PeerEndpoint.String(), int64(len(txsFromNode.Transactions)))
@@ -1173,10 +1517,52 @@ func (g *gateway) handleBridgeMessages(ctx context.Context) error {
 				g.traceIfSlow(func() { g.handleBlockFromBlockchain(blockchainBlock) },
 					fmt.Sprintf("handleBlockFromBlockchain hash=[%s]", blockchainBlock.Block.Hash()), blockchainBlock.PeerEndpoint.String(), 1)
 			}
+		case backfilledBlock := <-g.bridge.ReceiveBackfilledBlock():
+			if !g.BxConfig.NoBlocks {
+				g.traceIfSlow(func() { g.publishBackfilledBlock(backfilledBlock) },
+					fmt.Sprintf("publishBackfilledBlock hash=[%s]", backfilledBlock.Block.Hash()), backfilledBlock.PeerEndpoint.String(), 1)
+			}
 		}
 	}
 }
 
+// SlotTime returns the current time of the slot clock
+func (g *gateway) SlotTime() utils.SlotTime {
+	return g.slotClock.SlotTime()
+}
+
+// ClockSkew returns the offset of the local clock if it is beyond the skew threshold, zero otherwise
+func (g *gateway) ClockSkew() time.Duration {
+	return g.slotClock.Skew()
+}
+
+// reportClockSkewOnInterval publishes the offset of the local clock to the stats while it is beyond the skew threshold
+func (g *gateway) reportClockSkewOnInterval(ctx context.Context, interval time.Duration) {
+	ticker := g.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			if skew := g.slotClock.Skew(); skew != 0 {
+				g.stats.LogClockSkew(skew)
+			}
+		}
+	}
+}
+
+// ValidatorList returns the validator list that applied to the block height
+func (g *gateway) ValidatorList(blockHeight uint64) (*blockchain.ValidatorListInfo, bool) {
+	return g.validatorListMap.Lookup(blockHeight)
+}
+
+// FeeHistory returns the fee history of the recent blocks decoded by the gateway
+func (g *gateway) FeeHistory(blockCount, newestBlock uint64, rewardPercentiles []float64) (*blockchain.FeeHistory, error) {
+	return g.feeTracker.History(blockCount, newestBlock, rewardPercentiles)
+}
+
 func (g *gateway) NodeStatus() connections.NodeStatus {
 	var capabilities types.CapabilityFlags
 
@@ -1192,10 +1578,65 @@ func (g *gateway) NodeStatus() connections.NodeStatus {
 		capabilities |= types.CapabilityBlockchainRPCEnabled
 	}
 
+	txsForwarded, txsDropped := g.txPoolMonitor.Stats()
+
 	return connections.NodeStatus{
 		Capabilities: capabilities,
 		Version:      version.BuildVersion,
+		TxPoolState:  g.txPoolMonitor.State(),
+		TxPools:      g.txPoolMonitor.Status(),
+		TxsForwarded: txsForwarded,
+		TxsDropped:   txsDropped,
+
+		TxPoolReconciliation: g.lastTxPoolReconciliation(),
+		NetworkConfigUpdates: g.networkConfigUpdates.Load(),
+
+		InvalidBDNTxs:        g.bdnTxValidator.InvalidTxs(),
+		DroppedBDNValidation: g.bdnTxValidator.Dropped(),
+
+		Canary:           g.canary.Status(),
+		FeedVerification: g.feedVerifier.Status(),
+
+		OutboundProxies: connections.OutboundProxyHealth(),
+	}
+}
+
+// BDNStatus returns the status of the relay connections
+func (g *gateway) BDNStatus() connections.BDNStatus {
+	status := connections.BDNStatus{
+		Protocol:        bxmessage.CurrentProtocol,
+		SyncedWithRelay: g.isSyncWithRelay(),
+	}
+	g.ConnectionsLock.RLock()
+	for _, conn := range g.Connections {
+		if !connections.IsRelay(conn.GetConnectionType()) {
+			continue
+		}
+		if statusConn, ok := conn.(connections.BDNStatusConn); ok {
+			status.Relays = append(status.Relays, statusConn.BDNStatus())
+		}
 	}
+	g.ConnectionsLock.RUnlock()
+	return status
+}
+
+// CanaryStatus returns the outcome of the canary transactions, nil if the canary is disabled
+func (g *gateway) CanaryStatus() *blockchain.CanaryStatus {
+	return g.canary.Status()
+}
+
+// submitCanaryTx handles a canary transaction like a paid transaction sent through the websocket API by the gateway account
+func (g *gateway) submitCanaryTx(ethTx *ethtypes.Transaction) error {
+	content, err := rlp.EncodeToBytes(ethTx)
+	if err != nil {
+		return err
+	}
+
+	var hash types.SHA256Hash
+	copy(hash[:], ethTx.Hash().Bytes())
+	tx := bxmessage.NewTx(hash, content, g.sdn.NetworkNum(), types.TFPaidTx|types.TFLocalRegion|types.TFDeliverToNode, g.accountID)
+	source := connections.NewRPCConn(g.accountID, "canary", g.sdn.NetworkNum(), utils.Websocket)
+	return g.HandleMsg(tx, source, connections.RunForeground)
 }
 
 func (g *gateway) HandleMsg(msg bxmessage.Message, source connections.Conn, background connections.MsgHandlingOptions) error {
@@ -1228,13 +1669,17 @@ func (g *gateway) HandleMsg(msg bxmessage.Message, source connections.Conn, back
 	case *bxmessage.Txs:
 		// TODO: check if this is the message type we want to use?
 		for _, txsItem := range typedMsg.Items() {
-			g.TxStore.Add(txsItem.Hash, txsItem.Content, txsItem.ShortID, g.sdn.NetworkNum(), false, 0, time.Now(), 0, types.EmptySender)
+			if g.TxStore.Add(txsItem.Hash, txsItem.Content, txsItem.ShortID, g.sdn.NetworkNum(), false, 0, time.Now(), 0, types.EmptySender).NewSID {
+				g.publishShortID(txsItem.Hash, txsItem.ShortID, g.sdn.NetworkNum())
+			}
 		}
 	case *bxmessage.SyncDone:
 		g.setSyncWithRelay()
 		err = g.Bx.HandleMsg(msg, source)
 	case *bxmessage.MEVBundle:
 		go g.handleMEVBundleMessage(*typedMsg, source)
+	case *bxmessage.MEVShareHint:
+		g.handleMEVShareHint(typedMsg, source)
 	case *bxmessage.ErrorNotification:
 		source.Log().Errorf("received an error notification %v. terminating the gateway", typedMsg.Reason)
 		// TODO should also close the gateway while notify the bridge and other go routine (web socket server, ...)
@@ -1313,6 +1758,7 @@ func (g *gateway) processBroadcast(broadcastMsg *bxmessage.Broadcast, source con
 			source.Log().Debugf("received duplicate %v skipping", broadcastMsg)
 		case services.ErrMissingShortIDs:
 			source.Log().Debugf("%v from BDN is missing %v short IDs", broadcastMsg, len(missingShortIDs))
+			g.notifyBlockHeaderFromBroadcast(broadcastMsg)
 
 			if !g.isSyncWithRelay() {
 				source.Log().Debugf("TxStore sync is in progress - Ignoring %v from bdn with unknown %v shortIDs", broadcastMsg, len(missingShortIDs))
@@ -1344,6 +1790,7 @@ func (g *gateway) processBroadcast(broadcastMsg *bxmessage.Broadcast, source con
 	g.nextBlockTime = startTime.Add(g.blockTime).Round(time.Second)
 
 	source.Log().Infof("processing %v from BDN, block number: %v", broadcastMsg, bxBlock.Number)
+	g.notifyBlockHeaderFromBroadcast(broadcastMsg)
 	g.processBlockFromBDN(bxBlock)
 
 	var eventName string
@@ -1356,6 +1803,25 @@ func (g *gateway) processBroadcast(broadcastMsg *bxmessage.Broadcast, source con
 	g.stats.AddGatewayBlockEvent(eventName, source, broadcastMsg.Hash(), broadcastMsg.BeaconHash(), broadcastMsg.GetNetworkNum(), 1, startTime, 0, bxBlock.Size(), len(broadcastMsg.Block()), len(broadcastMsg.ShortIDs()), len(bxBlock.Txs), 0, bxBlock)
 }
 
+// txEnrichers returns the enrichment stages of the tx notifications: the address labels, then the enrich hooks
+func (g *gateway) txEnrichers() []servers.TxEnricher {
+	var enrichers []servers.TxEnricher
+	if g.addressLabels != nil {
+		enrichers = append(enrichers, g.addressLabels)
+	}
+	return append(enrichers, g.hooks)
+}
+
+// hookSource describes the connection a message was received from to the hooks
+func hookSource(source connections.Conn) hooks.Source {
+	return hooks.Source{
+		ConnectionType: source.GetConnectionType().String(),
+		PeerIP:         source.GetPeerIP(),
+		PeerPort:       source.GetPeerPort(),
+		AccountID:      source.GetAccountID(),
+	}
+}
+
 func (g *gateway) processBlockchainNetworkUpdate(source connections.Conn) {
 	if err := g.sdn.FetchBlockchainNetwork(); err != nil {
 		source.Log().Errorf("could not fetch blockchain network config: %v", err)
@@ -1366,6 +1832,32 @@ func (g *gateway) processBlockchainNetworkUpdate(source connections.Conn) {
 		source.Log().Errorf("could not push blockchain network config: %v", err)
 		return
 	}
+
+	g.refreshFeatureFlags()
+}
+
+func (g *gateway) refreshFeatureFlagsOnInterval(ctx context.Context, interval time.Duration) {
+	ticker := g.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			g.refreshFeatureFlags()
+		}
+	}
+}
+
+// refreshFeatureFlags fetches the feature flags from the SDN, the previous values are kept if the SDN is unavailable
+func (g *gateway) refreshFeatureFlags() {
+	flags, err := g.sdn.FetchFeatureFlags()
+	if err != nil {
+		g.log.Warnf("could not refresh feature flags from the SDN: %v", err)
+		return
+	}
+	g.featureFlags.UpdateRemote(flags)
 }
 
 func (g *gateway) processValidatorUpdate(msg *bxmessage.ValidatorUpdates, source connections.Conn) {
@@ -1418,6 +1910,9 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 	sender := tx.Sender()
 	// we add the transaction to TxStore with current time, so we can measure time difference to node announcement/confirmation
 	txResult := g.TxStore.Add(tx.Hash(), tx.Content(), tx.ShortID(), tx.GetNetworkNum(), !(isRelay || (connections.IsGrpc(connectionType) && sender != types.EmptySender)), tx.Flags(), g.clock.Now(), 0, sender)
+	if txResult.NewSID {
+		g.publishShortID(tx.Hash(), tx.ShortID(), tx.GetNetworkNum())
+	}
 
 	nodeID := source.GetNodeID()
 	l := source.Log().WithFields(log.Fields{
@@ -1431,14 +1926,25 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 	case txResult.NewContent && txResult.Transaction.Flags().IsReuseSenderNonce() && tx.ShortID() == types.ShortIDEmpty:
 		eventName = "TxReuseSenderNonce"
 		l.Trace(txResult.DebugData)
+	case txResult.NewContent && !g.hooks.TxReceived(txResult.Transaction, hookSource(source)):
+		eventName = "TxDroppedByHook"
 	case txResult.AlreadySeen:
 		l.Tracef("received already Seen transaction from %v:%v and account id %v, reason: %s", peerIP, peerPort, source.GetAccountID(), txResult.DebugData)
 	case txResult.NewContent || txResult.NewSID || txResult.Reprocess:
 		eventName = "TxProcessedByGatewayFromPeer"
 		if txResult.NewContent || txResult.Reprocess {
-			if txResult.NewContent && !tx.Flags().IsValidatorsOnly() && !tx.Flags().IsNextValidator() {
-				newTxsNotification := types.CreateNewTransactionNotification(txResult.Transaction)
-				g.notify(newTxsNotification)
+			publish := txResult.NewContent && !tx.Flags().IsValidatorsOnly() && !tx.Flags().IsNextValidator() && !tx.Flags().IsPrivateTx()
+			// a private tx is published once, when it falls back to the public mempool
+			if txResult.Reprocess && txResult.Transaction.Flags().IsPrivateTx() && !tx.Flags().IsPrivateTx() {
+				txResult.Transaction.RemoveFlags(types.TFPrivateTx)
+				publish = true
+			}
+			if publish {
+				// already published from a peer gateway
+				if !g.feedPeerTxs.Exists(txResult.Transaction.Hash().String()) {
+					newTxsNotification := types.CreateNewTransactionNotification(txResult.Transaction)
+					g.notify(newTxsNotification)
+				}
 				if !sourceEndpoint.IsDynamic() {
 					g.publishPendingTx(txResult.Transaction.Hash(), txResult.Transaction, connectionType == utils.Blockchain)
 				}
@@ -1447,23 +1953,23 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 			if !isRelay {
 				if connectionType == utils.Blockchain {
 					g.bdnStats.LogNewTxFromNode(sourceEndpoint)
+					g.canary.ObserveTx(tx.Hash().Format(true))
+					g.txStatusTracker.ObserveTx(tx.Hash(), types.Propagated)
 				}
 
 				paidTx := tx.Flags().IsPaid()
-				var (
-					allowed  bool
-					behavior sdnmessage.BDNServiceBehaviorType
-				)
+				burstAccountID := g.accountID
 				if connectionType == utils.CloudAPI {
-					allowed, behavior = g.burstLimiter.AllowTransaction(source.GetAccountID(), paidTx)
-				} else {
-					allowed, behavior = g.burstLimiter.AllowTransaction(g.accountID, paidTx)
+					burstAccountID = source.GetAccountID()
 				}
+				allowed, behavior := g.burstLimiter.AllowTransaction(burstAccountID, paidTx)
 				if !allowed {
 					if paidTx {
 						g.bdnStats.LogBurstLimitedTransactionsPaid()
+						g.securityEvents.QuotaExhausted(burstAccountID, "paid transaction burst limit")
 					} else {
 						g.bdnStats.LogBurstLimitedTransactionsUnpaid()
+						g.securityEvents.QuotaExhausted(burstAccountID, "unpaid transaction burst limit")
 					}
 				}
 
@@ -1489,6 +1995,7 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 					tx.SetTimestamp(g.clock.Now())
 					broadcastRes = g.broadcast(tx, source, utils.RelayTransaction)
 					sentToBDN = true
+					g.txStatusTracker.ObserveTx(tx.Hash(), types.ReceivedByBDN)
 				}
 			}
 
@@ -1548,9 +2055,11 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 							return
 						}
 
-						err := g.bridge.SendTransactionsFromBDN(txsToDeliverToNodes)
+						err := g.sendTransactionsFromBDN(txsToDeliverToNodes, peerIP)
 						if err != nil {
 							l.Errorf("failed to send transaction from BDN to bridge: %v", err)
+						} else {
+							g.txStatusTracker.ObserveTx(tx.Hash(), types.Propagated)
 						}
 
 						if shouldSendTxFromNodeToOtherNodes {
@@ -1563,9 +2072,11 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 						}).Debug("tx sent to blockchain with front run protection delay")
 					})
 				} else {
-					err := g.bridge.SendTransactionsFromBDN(txsToDeliverToNodes)
+					err := g.sendTransactionsFromBDN(txsToDeliverToNodes, peerIP)
 					if err != nil {
 						l.Errorf("failed to send transaction from BDN to bridge: %v", err)
+					} else {
+						g.txStatusTracker.ObserveTx(tx.Hash(), types.Propagated)
 					}
 
 					sentToBlockchainNode = true
@@ -1581,6 +2092,7 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 
 			if isRelay && !txResult.Reprocess {
 				g.bdnStats.LogNewTxFromBDN()
+				g.txStatusTracker.ObserveTx(tx.Hash(), types.ReceivedByBDN)
 			}
 
 			if txResult.NewContent {
@@ -1630,6 +2142,31 @@ func (g *gateway) processTransaction(tx *bxmessage.Tx, source connections.Conn)
 	l.Trace("msgTx")
 }
 
+// sendTransactionsFromBDN sends the transactions to the blockchain nodes, transactions received from a relay are
+// validated first if --validate-bdn-txs is set and the bdn_tx_validation feature is enabled
+func (g *gateway) sendTransactionsFromBDN(txs blockchain.Transactions, relay string) error {
+	if g.bdnTxValidator == nil || !connections.IsRelay(txs.ConnectionType) || !g.featureFlags.Enabled(services.FeatureBDNTxValidation) {
+		return g.bridge.SendTransactionsFromBDN(txs)
+	}
+
+	for _, tx := range txs.Transactions {
+		validatedTxs := blockchain.Transactions{
+			Transactions:   []*types.BxTransaction{tx},
+			PeerEndpoint:   txs.PeerEndpoint,
+			ConnectionType: txs.ConnectionType,
+		}
+		submitted := g.bdnTxValidator.Submit(tx, relay, func() {
+			if err := g.bridge.SendTransactionsFromBDN(validatedTxs); err != nil {
+				log.Errorf("failed to send validated transaction %v from BDN to bridge: %v", tx.Hash(), err)
+			}
+		})
+		if !submitted {
+			return fmt.Errorf("validation queue is full, dropped transaction %v", tx.Hash())
+		}
+	}
+	return nil
+}
+
 // shouldSendTxFromBDNToNodes send to node if all are true
 func (g *gateway) shouldSendTxFromBDNToNodes(connectionType utils.NodeType, tx *bxmessage.Tx, validatorsOnly bool, nextValidatorTx bool) (send bool) {
 	if connectionType == utils.Blockchain {
@@ -1640,14 +2177,38 @@ func (g *gateway) shouldSendTxFromBDNToNodes(connectionType utils.NodeType, tx *
 		return // Gateway is not connected to nodes
 	}
 
+	if tx.Flags().IsPrivateTx() {
+		return // Private transaction is not propagated to the public mempool
+	}
+
 	if (!g.BxConfig.BlocksOnly && tx.Flags().ShouldDeliverToNode()) || // (Gateway didn't start with blocks only mode and DeliverToNode flag is on) OR
 		(g.BxConfig.AllTransactions && !validatorsOnly && !nextValidatorTx) { // (Gateway started with a flag to send all transactions, and this is not validator only nor next validator tx
 		send = true
 	}
 
+	if send && !g.txAllowedToNodes(tx.Content()) {
+		send = false
+	}
+
 	return
 }
 
+// txAllowedToNodes applies the denylist and the node tx pool monitor to a transaction about to be sent to the nodes
+func (g *gateway) txAllowedToNodes(content types.TxContent) bool {
+	if !g.denylist.Empty() {
+		var ethTx ethtypes.Transaction
+		if err := rlp.DecodeBytes(content, &ethTx); err == nil {
+			if err = g.denylist.Validate(&ethTx); err != nil {
+				log.Debugf("not sending tx from BDN to nodes: %v", err)
+				return false
+			}
+		}
+	}
+
+	// node tx pool is near capacity
+	return g.txPoolMonitor.AllowTx()
+}
+
 func (g *gateway) updateValidatorStateMap() {
 	for newList := range g.bridge.ReceiveValidatorListInfo() {
 		if newList == nil {
@@ -1655,11 +2216,89 @@ func (g *gateway) updateValidatorStateMap() {
 		}
 
 		blockHeight := newList.BlockHeight
-		g.validatorListMap.Delete(blockHeight - 400) // remove the validator list that doesn't need anymore
 		g.validatorListMap.Store(blockHeight, newList.ValidatorList)
 	}
 }
 
+func (g *gateway) handleProposerDuties() {
+	for duties := range g.bridge.ReceiveProposerDuties() {
+		for _, duty := range duties {
+			g.notify(types.NewProposerDutyNotification(duty.Slot, duty.ValidatorIndex, duty.Pubkey, duty.Relays))
+		}
+	}
+}
+
+func (g *gateway) handleBlockHeaders() {
+	for header := range g.bridge.ReceiveBlockHeaderFromNode() {
+		// like the full blocks, the blocks of the dynamic peers are not published to newBlocks
+		if g.BxConfig.NoBlocks || header.PeerEndpoint.IsDynamic() {
+			continue
+		}
+		g.notifyBlockHeader(header.Header)
+	}
+}
+
+// notifyBlockHeaderFromBroadcast publishes the header of the ETH block of the broadcast, so the headerFirst
+// subscribers are notified while its missing transactions are recovered
+func (g *gateway) notifyBlockHeaderFromBroadcast(broadcastMsg *bxmessage.Broadcast) {
+	if broadcastMsg.IsBeaconBlock() || !g.feedManager.SubscriptionTypeExists(types.NewBlocksFeed) {
+		return
+	}
+	header, err := services.EthBlockHeaderFromBroadcast(broadcastMsg)
+	if err != nil {
+		g.log.Debugf("could not decode the header of %v: %v", broadcastMsg, err)
+		return
+	}
+	g.notifyBlockHeader(header)
+}
+
+// notifyBlockHeader publishes the header only notification of a block to newBlocks, once, and only if the full
+// notification of the block has not been published yet
+func (g *gateway) notifyBlockHeader(header *ethtypes.Header) {
+	if !g.feedManager.SubscriptionTypeExists(types.NewBlocksFeed) {
+		return
+	}
+	hash := header.Hash()
+	key := types.SHA256Hash(hash).String()
+	if g.newBlocks.Exists(key) || !g.blockHeaders.SetIfAbsent(key, 15*time.Minute) {
+		return
+	}
+
+	notification, err := types.NewEthBlockHeaderNotification(hash, header)
+	if err != nil {
+		g.log.Errorf("failed to create header notification of block %v: %v", hash, err)
+		return
+	}
+	notification.Divergences = g.feedVerifier.Divergences()
+	notification.SetNotificationType(types.NewBlocksFeed)
+	g.notify(notification)
+}
+
+// notifySprintProducers publishes the producer set of the Heimdall span of the sprint starting at blockHeight
+func (g *gateway) notifySprintProducers(blockHeight uint64) {
+	producers, err := g.polygonValidatorInfoManager.SprintProducers(blockHeight)
+	if err != nil {
+		g.log.Warnf("failed to get the producers of sprint starting at %v: %v", blockHeight, err)
+		return
+	}
+	g.notifyNextSprintValidators(blockHeight, producers)
+}
+
+// notifyNextSprintValidators publishes the producer set of the sprint starting at blockHeight, once per sprint
+func (g *gateway) notifyNextSprintValidators(blockHeight uint64, validatorList []string) {
+	for {
+		lastHeight := g.nextSprintValidatorsHeight.Load()
+		if blockHeight <= lastHeight {
+			return
+		}
+		if g.nextSprintValidatorsHeight.CompareAndSwap(lastHeight, blockHeight) {
+			break
+		}
+	}
+
+	g.notify(types.NewNextSprintValidatorsNotification(blockHeight, validatorList))
+}
+
 func getRawBytesStringFromTXMsg(tx *bxmessage.Tx) (string, error) {
 	var ethTransaction ethtypes.Transaction
 	err := rlp.DecodeBytes(tx.Content(), &ethTransaction)
@@ -1686,7 +2325,12 @@ func (g *gateway) handleBlockFromBlockchain(blockchainBlock blockchain.BlockFrom
 	}
 
 	g.onBlock(blockInfo)
+	if !blockchainBlock.PeerEndpoint.IsDynamic() {
+		g.blockSources.Observe(bxBlock.Hash().String(), services.BlockSourceNode)
+	}
 	source := connections.NewBlockchainConn(blockchainBlock.PeerEndpoint)
+	g.hooks.BlockReceived(bxBlock, hookSource(source))
+	g.canary.ObserveBlock(bxBlock)
 
 	g.bdnStats.LogNewBlockMessageFromNode(source.NodeEndpoint())
 
@@ -1702,6 +2346,7 @@ func (g *gateway) handleBlockFromBlockchain(blockchainBlock blockchain.BlockFrom
 		if !g.isSyncWithRelay() {
 			source.Log().Debugf("TxSync not completed. Not sending block %v to the bdn", bxBlock.Hash())
 		} else {
+			g.capture.RecordMessage(capture.SourceBlockchain, broadcastMessage)
 			source.Log().Debugf("compressed %v from blockchain node: compressed %v short IDs", bxBlock, len(usedShortIDs))
 			source.Log().Infof("propagating %v from blockchain node to BDN", bxBlock)
 
@@ -1731,6 +2376,10 @@ func (g *gateway) processBlockFromBDN(bxBlock *types.BxBlock) {
 	}
 
 	g.onBlock(blockInfo)
+	g.blockSources.Observe(bxBlock.Hash().String(), services.BlockSourceBDN)
+	g.hooks.BlockReceived(bxBlock, hooks.Source{ConnectionType: utils.Relay.String()})
+	g.canary.ObserveBlock(bxBlock)
+	g.feedVerifier.ObserveBlock(bxBlock)
 
 	if err = g.bridge.SendBlockToNode(bxBlock); err != nil {
 		g.log.Errorf("unable to send block %v from BDN to node: %v", bxBlock, err)
@@ -1745,7 +2394,12 @@ func (g *gateway) processBlockFromBDN(bxBlock *types.BxBlock) {
 }
 
 func (g *gateway) notify(notification types.Notification) {
-	if g.BxConfig.WebsocketEnabled || g.BxConfig.WebsocketTLSEnabled || g.BxConfig.GRPC.Enabled {
+	// only one gateway of the cluster publishes each notification to the shared sink, the subscriptions are always served
+	if g.BxConfig.FeedExport.Published && g.feedLeader.IsLeader(notification.NotificationType()) && g.cluster.ShouldDeliver(notification) {
+		g.feedManager.ExportPublished(notification)
+	}
+
+	if g.BxConfig.WebsocketEnabled || g.BxConfig.WebsocketTLSEnabled || g.BxConfig.GRPC.Enabled || len(g.standingSubscriptions) > 0 {
 		select {
 		case g.feedManagerChan <- notification:
 		default:
@@ -1754,6 +2408,14 @@ func (g *gateway) notify(notification types.Notification) {
 	}
 }
 
+// publishShortID publishes the short ID the relay assigned to the tx to the shortIDs subscriptions
+func (g *gateway) publishShortID(hash types.SHA256Hash, shortID types.ShortID, networkNum types.NetworkNum) {
+	if shortID == types.ShortIDEmpty || !g.feedManager.SubscriptionTypeExists(types.ShortIDsFeed) {
+		return
+	}
+	g.notify(types.NewShortIDNotification(hash, shortID, networkNum))
+}
+
 func (g *gateway) handleMEVBundleMessage(mevBundle bxmessage.MEVBundle, source connections.Conn) {
 	start := time.Now()
 	blockNumber, err := strconv.ParseInt(strings.TrimPrefix(mevBundle.BlockNumber, "0x"), 16, 64)
@@ -1796,6 +2458,23 @@ func (g *gateway) handleMEVBundleMessage(mevBundle bxmessage.MEVBundle, source c
 	g.stats.AddGatewayBundleEvent(event, source, start, mevBundle.BundleHash, mevBundle.GetNetworkNum(), mevBundle.Names(), mevBundle.Frontrunning, mevBundle.UUID, uint64(blockNumber), mevBundle.MinTimestamp, mevBundle.MaxTimestamp, mevBundle.BundlePrice, mevBundle.EnforcePayout)
 }
 
+// handleMEVShareHint publishes the hint of a private tx to the mevShare feed, the hints of the local submissions are
+// broadcast to the relays as well
+func (g *gateway) handleMEVShareHint(hint *bxmessage.MEVShareHint, source connections.Conn) {
+	if !g.seenMEVShareHints.SetIfAbsent(hint.Hash().String(), 30*time.Minute) {
+		source.Log().Tracef("ignoring seen %v", hint)
+		return
+	}
+
+	if !connections.IsRelay(source.GetConnectionType()) {
+		broadcastRes := g.broadcast(hint, source, utils.RelayTransaction)
+		source.Log().Tracef("broadcasting %v %v", hint, broadcastRes)
+	}
+
+	notification := hint.Hint
+	g.notify(&notification)
+}
+
 func retrieveAuthHeader(ctx context.Context, authFromRequestBody string) string {
 	authHeader, err := bxrpc.ReadAuthMetadata(ctx)
 	if err == nil {
@@ -1819,6 +2498,18 @@ func retrieveOriginalSenderAccountID(ctx context.Context, accountModel *sdnmessa
 	return &accountID, nil
 }
 
+// grpcTxFlags returns the MEV flags of a gRPC tx request. Proto3 flags cannot tell an omitted flag from false, only
+// enabled flags override the defaults of the account.
+func grpcTxFlags(validatorsOnly, nextValidator, frontRunningProtection bool) servers.TxFlags {
+	return servers.TxFlags{
+		ValidatorsOnly:            validatorsOnly,
+		ValidatorsOnlySet:         validatorsOnly,
+		NextValidator:             nextValidator,
+		FrontRunningProtection:    frontRunningProtection,
+		FrontRunningProtectionSet: frontRunningProtection,
+	}
+}
+
 func (g *gateway) Peers(ctx context.Context, req *pb.PeersRequest) (*pb.PeersReply, error) {
 	authHeader := retrieveAuthHeader(ctx, req.AuthHeader)
 
@@ -1854,7 +2545,7 @@ const (
 func (g *gateway) validateAuthHeader(authHeader string, required bool, allowAccessToInternalGateway bool) (*sdnmessage.Account, error) {
 	var err error
 	if authHeader == "" {
-		if required {
+		if required && !g.BxConfig.LocalMode {
 			return nil, fmt.Errorf("auth header is missing")
 		}
 		if g.sdn.AccountModel().AccountID == types.BloxrouteAccountID {
@@ -1869,6 +2560,7 @@ func (g *gateway) validateAuthHeader(authHeader string, required bool, allowAcce
 
 	accountModel, err := g.authorize(accountID, secretHash, allowAccessToInternalGateway)
 	if err != nil {
+		g.securityEvents.AuthFailed(accountID, "")
 		return nil, err
 	}
 	return &accountModel, nil
@@ -1901,6 +2593,11 @@ func (g *gateway) authorize(accountID types.AccountID, secretHash string, allowA
 	// SDN will return StatusUnauthorized and fail this connection. if SDN return any other error -
 	// assuming the issue is with the SDN and set default enterprise account for the customer. in order to send request to the gateway,
 	// customer must be enterprise / elite account
+	if g.BxConfig.LocalMode {
+		// there is no SDN to verify accounts with, every client is served with the local account
+		return g.sdn.AccountModel(), nil
+	}
+
 	var err error
 	connectionAccountModel := g.sdn.AccountModel()
 	l := g.log.WithFields(log.Fields{
@@ -1913,15 +2610,16 @@ func (g *gateway) authorize(accountID types.AccountID, secretHash string, allowA
 			l.Errorf("account %v is not authorized to call this method directly", g.sdn.AccountModel().AccountID)
 			return connectionAccountModel, fmt.Errorf("not authorized to call this method")
 		}
-		connectionAccountModel, err = g.sdn.FetchCustomerAccountModel(accountID)
+		connectionAccountModel, err = g.accountCache.Get(accountID)
 		if err != nil {
 			var invalidUserError error
 
-			if strings.Contains(err.Error(), strconv.FormatInt(http.StatusUnauthorized, 10)) {
+			switch services.AccountErrorStatus(err) {
+			case http.StatusUnauthorized:
 				invalidUserError = fmt.Errorf("account %v is not authorized to get other account %v information", g.sdn.AccountModel().AccountID, accountID)
-			} else if strings.Contains(err.Error(), strconv.FormatInt(http.StatusNotFound, 10)) {
+			case http.StatusNotFound:
 				invalidUserError = fmt.Errorf("account %v is not found", accountID)
-			} else if strings.Contains(err.Error(), strconv.FormatInt(http.StatusBadRequest, 10)) {
+			case http.StatusBadRequest:
 				invalidUserError = fmt.Errorf("bad request for %v", accountID)
 			}
 
@@ -1946,6 +2644,7 @@ func (g *gateway) authorize(accountID types.AccountID, secretHash string, allowA
 		return connectionAccountModel, fmt.Errorf("wrong value in the authorization header")
 	}
 
+	g.contractAllowlist.UpdateAccount(connectionAccountModel)
 	return connectionAccountModel, nil
 }
 
@@ -2205,6 +2904,9 @@ func (g *gateway) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTx
 	if err != nil {
 		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
+	if err = g.feedManager.AllowCalls(*accountModel, config.RateLimitTx, 1); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
 
 	accountID, err := retrieveOriginalSenderAccountID(ctx, accountModel)
 	if err != nil {
@@ -2212,8 +2914,9 @@ func (g *gateway) BlxrTx(ctx context.Context, req *pb.BlxrTxRequest) (*pb.BlxrTx
 	}
 
 	grpc := connections.NewRPCConn(*accountID, servers.GetPeerAddr(ctx), g.sdn.NetworkNum(), utils.GRPC)
+	flags := g.feedManager.ApplyTxDefaults(grpc, *accountModel, grpcTxFlags(req.ValidatorsOnly, req.NextValidator, req.FrontrunningProtection))
 	txHash, ok, err := servers.HandleSingleTransaction(g.feedManager, req.Transaction, nil, grpc,
-		req.ValidatorsOnly, req.NextValidator, req.NodeValidation, req.FrontrunningProtection, uint16(req.Fallback),
+		flags.ValidatorsOnly, req.NextValidator, req.NodeValidation, flags.FrontRunningProtection, uint16(req.Fallback),
 		g.feedManager.GetNextValidatorMap(), g.feedManager.GetValidatorStatusMap())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
@@ -2234,6 +2937,13 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 		return nil, err
 	}
 
+	if !g.featureFlags.Enabled(services.FeatureTxBatching) {
+		return nil, status.Error(codes.Unavailable, "blxr_batch_tx is disabled on this gateway")
+	}
+	if err = g.feedManager.AllowCalls(*accountModel, config.RateLimitBatchTx, 1); err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	startTime := time.Now()
 	var txHashes []*pb.TxIndex
 	var txErrors []*pb.ErrorIndex
@@ -2252,11 +2962,12 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 	}
 
 	grpc := connections.NewRPCConn(*accountID, servers.GetPeerAddr(ctx), g.sdn.NetworkNum(), utils.GRPC)
+	flags := g.feedManager.ApplyTxDefaults(grpc, *accountModel, grpcTxFlags(req.ValidatorsOnly, req.NextValidator, req.FrontrunningProtection))
 
 	for idx, transactionsAndSender := range transactionsAndSenders {
 		tx := transactionsAndSender.GetTransaction()
 		txHash, ok, err := servers.HandleSingleTransaction(g.feedManager, tx, transactionsAndSender.GetSender(), grpc,
-			req.ValidatorsOnly, req.NextValidator, req.NodeValidation, req.FrontrunningProtection,
+			flags.ValidatorsOnly, req.NextValidator, req.NodeValidation, flags.FrontRunningProtection,
 			uint16(req.Fallback), g.feedManager.GetNextValidatorMap(), g.feedManager.GetValidatorStatusMap())
 		if err != nil {
 			txErrors = append(txErrors, &pb.ErrorIndex{Idx: int32(idx), Error: err.Error()})
@@ -2282,6 +2993,16 @@ func (g *gateway) BlxrBatchTX(ctx context.Context, req *pb.BlxrBatchTXRequest) (
 	return &pb.BlxrBatchTXReply{TxHashes: txHashes, TxErrors: txErrors}, nil
 }
 
+// authorizeFeeds returns the account of the auth header of a call of the versioned feeds service, which is only read
+// from the metadata
+func (g *gateway) authorizeFeeds(ctx context.Context) (sdnmessage.Account, error) {
+	accountModel, err := g.validateAuthHeader(retrieveAuthHeader(ctx, ""), true, true)
+	if err != nil {
+		return sdnmessage.Account{}, err
+	}
+	return *accountModel, nil
+}
+
 func (g *gateway) NewTxs(req *pb.TxsRequest, stream pb.Gateway_NewTxsServer) error {
 	authHeader := retrieveAuthHeader(stream.Context(), req.AuthHeader)
 
@@ -2398,6 +3119,8 @@ func (g *gateway) onBlock(blockInfo *eth.BlockInfo) {
 		return
 	}
 
+	g.feeTracker.OnBlock(blockInfo.Block)
+
 	if err := g.blockProposer.OnBlock(g.context, blockInfo.Block); err != nil {
 		g.log.Debugf("failed to process block: %v", err)
 		return
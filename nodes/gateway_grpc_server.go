@@ -5,15 +5,21 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	feedsv1 "github.com/bloXroute-Labs/gateway/v2/protobuf/feeds/v1"
 	"github.com/bloXroute-Labs/gateway/v2/rpc"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -32,7 +38,7 @@ type gatewayGRPCServer struct {
 }
 
 func newGatewayGRPCServer(gateway *gateway, host string, port int, user string, secret string) *gatewayGRPCServer {
-	grpcHostPort := fmt.Sprintf("%v:%v", host, port)
+	grpcHostPort := net.JoinHostPort(host, strconv.Itoa(port))
 
 	var encodedAuth string
 	if user != "" && secret != "" {
@@ -63,16 +69,21 @@ func (ggs *gatewayGRPCServer) run() error {
 	if err != nil {
 		return fmt.Errorf("failed to listen: %v", err)
 	}
+	if ggs.gateway.BxConfig.ProxyProtocol {
+		listener = utils.NewProxyProtocolListener(listener, ggs.gateway.BxConfig.TrustedProxies)
+	}
 
 	serverOptions := []grpc.ServerOption{
 		grpc.WriteBufferSize(bufferSize),
 		grpc.InitialConnWindowSize(windowSize),
 		grpc.UnaryInterceptor(ggs.authenticate),
-		grpc.ChainUnaryInterceptor(ggs.authenticate, ggs.reqSDKStats),
+		grpc.ChainUnaryInterceptor(ggs.gateway.feedManager.RecoverUnary, ggs.authenticate, ggs.reqSDKStats),
+		grpc.ChainStreamInterceptor(ggs.gateway.feedManager.RecoverStream, ggs.limitStreamConnections),
 	}
 
 	ggs.server = grpc.NewServer(serverOptions...)
 	pb.RegisterGatewayServer(ggs.server, ggs.gateway)
+	feedsv1.RegisterFeedsServer(ggs.server, ggs.gateway.feedsServer)
 
 	log.Infof("GRPC server is starting on %v", ggs.listenAddr)
 
@@ -98,6 +109,69 @@ func (ggs *gatewayGRPCServer) authenticate(ctx context.Context, req interface{},
 	return handler(ctx, req)
 }
 
+// limitStreamConnections counts every gRPC stream, whichever service and method opened it, against the concurrent
+// connections limit of its account. The account is only known once the request carrying the auth header is
+// received, so the stream is counted on its first message and released when the handler returns.
+func (ggs *gatewayGRPCServer) limitStreamConnections(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	limited := &connectionLimitedStream{ServerStream: stream, gateway: ggs.gateway}
+	defer limited.release()
+	return handler(srv, limited)
+}
+
+// connectionLimitedStream acquires a connection of the account of the stream when its request is received
+type connectionLimitedStream struct {
+	grpc.ServerStream
+	gateway           *gateway
+	received          bool
+	releaseConnection func()
+}
+
+func (s *connectionLimitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil || s.received {
+		return err
+	}
+	s.received = true
+
+	var authFromRequestBody string
+	if req, ok := m.(interface{ GetAuthHeader() string }); ok {
+		authFromRequestBody = req.GetAuthHeader()
+	}
+	account, err := s.account(retrieveAuthHeader(s.Context(), authFromRequestBody))
+	if err != nil {
+		// the handler rejects the stream when it authorizes the request
+		return nil
+	}
+
+	release, err := s.gateway.feedManager.AcquireConnection(account, types.GRPCFeed)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	s.releaseConnection = release
+	return nil
+}
+
+// account returns the account of the auth header the same way validateAuthHeader does, without reporting failures
+// the handler reports when it authorizes the request
+func (s *connectionLimitedStream) account(authHeader string) (sdnmessage.Account, error) {
+	if authHeader == "" {
+		if !s.gateway.BxConfig.LocalMode {
+			return sdnmessage.Account{}, errors.New("auth header is missing")
+		}
+		return s.gateway.sdn.AccountModel(), nil
+	}
+	accountID, secretHash, err := utils.GetAccountIDSecretHashFromHeader(authHeader)
+	if err != nil {
+		return sdnmessage.Account{}, err
+	}
+	return s.gateway.authorize(accountID, secretHash, true)
+}
+
+func (s *connectionLimitedStream) release() {
+	if s.releaseConnection != nil {
+		s.releaseConnection()
+	}
+}
+
 func (ggs *gatewayGRPCServer) reqSDKStats(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	start := time.Now()
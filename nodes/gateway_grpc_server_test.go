@@ -36,6 +36,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -313,6 +315,63 @@ func TestGatewayGRPCNewTxs(t *testing.T) {
 	})
 }
 
+// TestGatewayGRPCStreamConnectionLimit tests that every gRPC stream is counted against the connections limit of its
+// account, including streams sharing a transport connection
+func TestGatewayGRPCStreamConnectionLimit(t *testing.T) {
+	port := test.NextTestPort()
+
+	g, _, s := spawnGRPCServer(t, port, "", "")
+	defer s.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go g.feedManager.Start(ctx)
+
+	ctl := gomock.NewController(t)
+	mockedSdn := mock_connections.NewMockSDNHTTP(ctl)
+	customerAccountModel := sdnmessage.Account{
+		AccountInfo: sdnmessage.AccountInfo{
+			AccountID: types.AccountID(testDifferentAccountID),
+			TierName:  sdnmessage.ATierUltra,
+		},
+		SecretHash: testDifferentSecretHash,
+	}
+	customerAccountModel.MaxConnections.MsgQuota.Limit = 1
+	mockedSdn.EXPECT().FetchCustomerAccountModel(gomock.Any()).Return(customerAccountModel, nil).AnyTimes()
+	mockedSdn.EXPECT().AccountModel().Return(testAccountModel).AnyTimes()
+	g.sdn = mockedSdn
+
+	client, err := rpc.GatewayClient(&config.GRPC{Host: "127.0.0.1", Port: port, AuthEnabled: true, EncodedAuthSet: true, EncodedAuth: testDifferentAuthHeader})
+	require.NoError(t, err)
+
+	streamCtx, cancelStream := context.WithCancel(ctx)
+	_, err = client.NewTxs(streamCtx, &pb.TxsRequest{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return g.feedManager.AccountConnections()[testDifferentAccountID].GRPCStreams == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// a stream of another method over the same transport connection exceeds the limit
+	blocksStream, err := client.NewBlocks(ctx, &pb.BlocksRequest{})
+	require.NoError(t, err)
+	_, err = blocksStream.Recv()
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, servers.AccountConnections{GRPCStreams: 1, Limit: 1}, g.feedManager.AccountConnections()[testDifferentAccountID])
+
+	// the stream is released once the client cancels it
+	cancelStream()
+	require.Eventually(t, func() bool {
+		_, ok := g.feedManager.AccountConnections()[testDifferentAccountID]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = client.NewBlocks(ctx, &pb.BlocksRequest{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return g.feedManager.AccountConnections()[testDifferentAccountID].GRPCStreams == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
 // TestGatewayGRPCNewTxs_withNonTxIncludes tests that the gateway will not crash if the client includes non-tx fields
 func TestGatewayGRPCNewTxs_withNonTxIncludes(t *testing.T) {
 	port := test.NextTestPort()
@@ -509,7 +568,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 		return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 			networkNum, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 			g.wsManager, g.sdn.AccountModel(), nil,
-			"", "", *g.BxConfig, g.stats, nil, nil)
+			"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 	}
 
 	testCases := []struct {
@@ -527,7 +586,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					networkNum, types.NetworkID(chainID), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nil, nil)
+					"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 			},
 			request:           &pb.BlxrTxRequest{},
 			generateTxAndHash: generateLegacyTxAndHash,
@@ -610,7 +669,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					bxgateway.BSCMainnetNum, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nextValidatorMap, validatorStatusMap)
+					"", "", *g.BxConfig, g.stats, nextValidatorMap, validatorStatusMap, servers.FeedManagerOptions{})
 			},
 			request: &pb.BlxrTxRequest{
 				NextValidator: true,
@@ -624,7 +683,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					1, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nil, nil)
+					"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 			}, request: &pb.BlxrTxRequest{
 				NextValidator: true,
 			},
@@ -644,7 +703,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					bxgateway.BSCMainnetNum, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nil, nil)
+					"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 			},
 			request: &pb.BlxrTxRequest{
 				NextValidator: true,
@@ -668,7 +727,7 @@ func TestGatewayGRPCBlxrTx(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					bxgateway.BSCMainnetNum, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nextValidatorMap, validatorStatusMap)
+					"", "", *g.BxConfig, g.stats, nextValidatorMap, validatorStatusMap, servers.FeedManagerOptions{})
 			},
 			request: &pb.BlxrTxRequest{
 				NextValidator: true,
@@ -740,7 +799,7 @@ func TestGatewayGRPCBlxrBatchTx(t *testing.T) {
 		return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 			networkNum, types.NetworkID(10), g.sdn.NodeModel().NodeID,
 			g.wsManager, g.sdn.AccountModel(), nil,
-			"", "", *g.BxConfig, g.stats, nil, nil)
+			"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 	}
 
 	testCases := []struct {
@@ -882,7 +941,7 @@ func TestGatewaySubmitBundle(t *testing.T) {
 		return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 			networkNum, types.NetworkID(1), g.sdn.NodeModel().NodeID,
 			g.wsManager, g.sdn.AccountModel(), nil,
-			"", "", *g.BxConfig, g.stats, nil, nil)
+			"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 	}
 
 	testCases := []struct {
@@ -1044,7 +1103,7 @@ func TestGatewaySubmitBundle(t *testing.T) {
 				return servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 					36, types.NetworkID(137), g.sdn.NodeModel().NodeID,
 					g.wsManager, g.sdn.AccountModel(), nil,
-					"", "", *g.BxConfig, g.stats, nil, nil)
+					"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 			},
 			request: &pb.BlxrSubmitBundleRequest{
 				BlockNumber: "0x1f71710",
@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"math/big"
@@ -25,6 +26,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/servers"
 	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/capture"
 	"github.com/bloXroute-Labs/gateway/v2/services/loggers"
 	"github.com/bloXroute-Labs/gateway/v2/test"
 	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
@@ -34,6 +36,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/utils/utilmock"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -113,6 +116,8 @@ func setup(t *testing.T, numPeers int) (blockchain.Bridge, *gateway) {
 		0,
 		0,
 		false,
+		utils.NewSlotClock(utils.RealClock{}, time.Unix(0, 0), bxgateway.NetworkToBlockDuration[bxgateway.Mainnet], "", 0),
+		nil,
 	)
 
 	g := node.(*gateway)
@@ -120,15 +125,16 @@ func setup(t *testing.T, numPeers int) (blockchain.Bridge, *gateway) {
 	// Required for TxReceipts feed
 	g.wsManager.UpdateNodeSyncStatus(blockchainPeers[0], blockchain.Synced)
 
-	g.setupTxStore()
+	_ = g.setupTxStore()
 	g.txTrace = loggers.NewTxTrace(nil)
+	g.capture, _ = capture.NewRecorder("", 0, g.clock)
 	g.setSyncWithRelay()
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 
 	g.feedManager = servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
 		networkNum, types.NetworkID(chainID), g.sdn.NodeModel().NodeID,
 		g.wsManager, g.sdn.AccountModel(), nil,
-		"", "", *g.BxConfig, g.stats, nil, nil)
+		"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
 	return bridge, g
 }
 
@@ -668,6 +674,79 @@ func TestGateway_HandleTransactionFromRPC(t *testing.T) {
 	assert.Equal(t, txSentToBlockchain.Hash(), txSentToBDN.Hash())
 }
 
+// pendingReceiptProvider is a node which did not include any tx
+type pendingReceiptProvider struct {
+	blockchain.WSProvider
+}
+
+func (pendingReceiptProvider) FetchTransactionReceipt([]interface{}, blockchain.RPCOptions) (interface{}, error) {
+	return nil, nil
+}
+
+func TestGateway_HandlePrivateTransactionFallback(t *testing.T) {
+	bridge, g := setup(t, 1)
+	mockTLS, relayConn := addRelayConn(g)
+	g.BxConfig.WebsocketEnabled = true
+
+	blockchainPeers, blockchainPeersInfo := ethtest.GenerateBlockchainPeersInfo(1)
+	wsManager := eth.NewEthWSManager(blockchainPeersInfo, func(uri string, endpoint types.NodeEndpoint, timeout time.Duration) blockchain.WSProvider {
+		return pendingReceiptProvider{WSProvider: eth.NewMockWSProvider(uri, endpoint, timeout)}
+	}, bxgateway.WSProviderTimeout, false)
+	wsManager.UpdateNodeSyncStatus(blockchainPeers[0], blockchain.Synced)
+	g.feedManager = servers.NewFeedManager(g.context, g, g.feedManagerChan, services.NewNoOpSubscriptionServices(),
+		networkNum, types.NetworkID(chainID), g.sdn.NodeModel().NodeID,
+		wsManager, g.sdn.AccountModel(), nil,
+		"", "", *g.BxConfig, g.stats, nil, nil, servers.FeedManagerOptions{})
+
+	ethTx, ethTxBytes := bxmock.NewSignedEthTxBytes(ethtypes.DynamicFeeTxType, 1, nil, big.NewInt(chainID))
+	timeout := 200 * time.Millisecond
+	txHash, err := servers.HandlePrivateTransaction(g.feedManager, hex.EncodeToString(ethTxBytes),
+		connections.NewRPCConn("", "", networkNum, utils.Websocket), timeout)
+	require.NoError(t, err)
+	assert.Equal(t, ethTx.Hash().Hex()[2:], txHash)
+
+	// the private tx is sent to the BDN only
+	msgBytes, err := mockTLS.MockAdvanceSent()
+	require.NoError(t, err)
+	var txSentToBDN bxmessage.Tx
+	require.NoError(t, txSentToBDN.Unpack(msgBytes, relayConn.Protocol()))
+	assert.True(t, txSentToBDN.Flags().IsPrivateTx())
+	assert.False(t, txSentToBDN.Flags().ShouldDeliverToNode())
+
+	select {
+	case <-bridge.ReceiveBDNTransactions():
+		assert.Fail(t, "private tx was sent to the node before its timeout")
+	case notification := <-g.feedManagerChan:
+		assert.Fail(t, "private tx was published before its timeout", "%v", notification)
+	case <-time.After(timeout / 2):
+	}
+
+	// it falls back to the public mempool once the timeout expires without a receipt
+	select {
+	case bdnTxs := <-bridge.ReceiveBDNTransactions():
+		require.Len(t, bdnTxs.Transactions, 1)
+		assert.Equal(t, ethTx.Hash().Bytes(), bdnTxs.Transactions[0].Hash().Bytes())
+	case <-time.After(time.Second):
+		require.Fail(t, "private tx was not sent to the node after its timeout")
+	}
+
+	newTxs := 0
+	for len(g.feedManagerChan) > 0 {
+		if notification := <-g.feedManagerChan; notification.NotificationType() == types.NewTxsFeed {
+			assert.Equal(t, ethTx.Hash().Hex(), notification.GetHash())
+			newTxs++
+		}
+	}
+	assert.Equal(t, 1, newTxs)
+
+	// the fallback is broadcast once
+	select {
+	case <-bridge.ReceiveBDNTransactions():
+		assert.Fail(t, "private tx was sent to the node more than once")
+	case <-time.After(timeout):
+	}
+}
+
 func TestGateway_HandleTransactionFromRelay(t *testing.T) {
 	bridge, g := setup(t, 1)
 	_, relayConn1 := addRelayConn(g)
@@ -754,6 +833,8 @@ func TestGateway_HandleTransactionFromRelayBlocksOnly(t *testing.T) {
 	select {
 	case <-bridge.ReceiveBDNTransactions():
 		assert.Fail(t, "unexpectedly received txs when --blocks-only set")
+	case <-bridge.ReceivePaidBDNTransactions():
+		assert.Fail(t, "unexpectedly received paid txs when --blocks-only set")
 	default:
 	}
 }
@@ -768,8 +849,8 @@ func TestGateway_HandleBlockFromBlockchain(t *testing.T) {
 	}()
 
 	g.BxConfig.WebsocketEnabled = true
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 	var count int32
 	var wg sync.WaitGroup
@@ -825,8 +906,8 @@ func TestGateway_HandleBlockFromInboundBlockchain(t *testing.T) {
 	}()
 
 	g.BxConfig.WebsocketEnabled = true
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 	var count int32
 	var wg sync.WaitGroup
@@ -926,7 +1007,7 @@ func TestGateway_HandleBlockFromBlockchain_TwoRelays(t *testing.T) {
 
 func TestGateway_HandleBlockFromRelay(t *testing.T) {
 	bridge, g := setup(t, 1)
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	_, relayConn1 := addRelayConn(g)
 	mockTLS2, _ := addRelayConn(g)
 
@@ -972,8 +1053,8 @@ func TestGateway_HandleBlockFromRelay(t *testing.T) {
 
 func TestGateway_HandleBeaconBlockFromRelay(t *testing.T) {
 	bridge, g := setup(t, 1)
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	_, relayConn1 := addRelayConn(g)
 	mockTLS2, _ := addRelayConn(g)
 
@@ -1074,8 +1155,8 @@ func TestGateway_HandleBeaconBlockFromRelay(t *testing.T) {
 
 func TestGateway_ValidateHeightBDNBlocksWithNode(t *testing.T) {
 	bridge, g := setup(t, 1)
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 	g.BxConfig.WebsocketEnabled = true
 
@@ -1145,16 +1226,16 @@ func TestGateway_BlockFeedIfSubscribeOnly(t *testing.T) {
 	heightFromNode := 0
 	expectNoFeedNotification(t, bridge, g, true, heightFromNode, heightFromNode, 0)
 
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	heightFromNode = 10
 	expectFeedNotification(t, bridge, g, true, heightFromNode, heightFromNode, 0)
 }
 
 func TestGateway_ValidateHeightBDNBlocksWithoutNode(t *testing.T) {
 	bridge, g := setup(t, 1)
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
-	g.feedManager.Subscribe(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 	g.BxConfig.WebsocketEnabled = true
 	g.blockchainPeers = []types.NodeEndpoint{}
@@ -1180,7 +1261,7 @@ func TestGateway_ValidateHeightBDNBlocksWithoutNode(t *testing.T) {
 func TestGateway_TestNoTxReceiptsWithoutSubscription(t *testing.T) {
 	// The test checks that there is no TxReceipts feed notification when there is no corresponding subscription
 	bridge, g := setup(t, 1)
-	g.feedManager.Subscribe(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	g.feedManager.SubscribeClient(types.BDNBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
 	g.feedManagerChan = make(chan types.Notification, bxgateway.BxNotificationChannelSize)
 	g.BxConfig.WebsocketEnabled = true
 	g.blockchainPeers = []types.NodeEndpoint{}
@@ -1213,6 +1294,115 @@ func TestGateway_TestNoTxReceiptsWithoutSubscription(t *testing.T) {
 	}
 }
 
+func TestGateway_TopOfBlockFromNode(t *testing.T) {
+	bridge, g := setup(t, 1)
+	g.BxConfig.WebsocketEnabled = true
+	g.BxConfig.TopOfBlockTxs = 2
+	_, err := g.feedManager.SubscribeClient(types.TopOfBlockFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	require.NoError(t, err)
+
+	ethBlock := bxmock.NewEthBlock(uint64(10), common.Hash{})
+	bxBlock, _ := bridge.BlockBlockchainToBDN(eth.NewBlockInfo(ethBlock, nil))
+
+	// the block is first received from the node
+	require.NoError(t, g.publishBlock(bxBlock, nil, nil, true))
+
+	var topOfBlock *types.TopOfBlockNotification
+	timeout := time.After(time.Second)
+	for topOfBlock == nil {
+		select {
+		case notification := <-g.feedManagerChan:
+			if notification.NotificationType() == types.TopOfBlockFeed {
+				topOfBlock = notification.(*types.TopOfBlockNotification)
+			}
+		case <-timeout:
+			require.FailNow(t, "did not receive the topOfBlock notification")
+		}
+	}
+	assert.Equal(t, uint64(10), topOfBlock.BlockHeight)
+	assert.Len(t, topOfBlock.Txs, 2)
+
+	// the same block received from the BDN is not published again
+	require.NoError(t, g.publishBlock(bxBlock, nil, nil, false))
+	for len(g.feedManagerChan) > 0 {
+		assert.NotEqual(t, types.TopOfBlockFeed, (<-g.feedManagerChan).NotificationType())
+	}
+}
+
+func TestGateway_BlockBackfill(t *testing.T) {
+	bridge, g := setup(t, 1)
+	g.BxConfig.WebsocketEnabled = true
+	g.BxConfig.BlockBackfillMaxBlocks = 5
+	_, err := g.feedManager.SubscribeClient(types.NewBlocksFeed, types.WebSocketFeed, nil, types.ClientInfo{Tier: string(sdnmessage.ATierEnterprise)}, types.ReqOptions{}, false)
+	require.NoError(t, err)
+
+	newBxBlock := func(height int) *types.BxBlock {
+		bxBlock, err := bridge.BlockBlockchainToBDN(eth.NewBlockInfo(bxmock.NewEthBlock(uint64(height), common.Hash{}), nil))
+		require.NoError(t, err)
+		return bxBlock
+	}
+
+	// the blocks missed from the node are requested
+	require.NoError(t, g.publishBlock(newBxBlock(10), nil, nil, true))
+	require.NoError(t, g.publishBlock(newBxBlock(13), nil, nil, true))
+	select {
+	case request := <-bridge.ReceiveBlockBackfillRequest():
+		assert.Equal(t, blockchain.BlockBackfillRequest{From: 11, To: 12}, request)
+	default:
+		require.FailNow(t, "the missed blocks were not requested")
+	}
+
+	// a gap larger than the backfill is not requested
+	require.NoError(t, g.publishBlock(newBxBlock(30), nil, nil, true))
+	assert.Len(t, bridge.ReceiveBlockBackfillRequest(), 0)
+
+	// the backfilled block is published marked, without moving the best block back
+	backfilled := newBxBlock(11)
+	g.publishBackfilledBlock(blockchain.BlockFromNode{Block: backfilled})
+	assert.Equal(t, 30, g.bestBlockHeight)
+	assert.Len(t, bridge.ReceiveBlockBackfillRequest(), 0)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case notification := <-g.feedManagerChan:
+			if notification.NotificationType() != types.NewBlocksFeed {
+				continue
+			}
+			ethNotification := notification.(*types.EthBlockNotification)
+			if ethNotification.BlockHash.String() != common.Hash(backfilled.Hash()).String() {
+				assert.False(t, ethNotification.Backfilled)
+				continue
+			}
+			assert.True(t, ethNotification.Backfilled)
+			return
+		case <-timeout:
+			require.FailNow(t, "did not receive the backfilled block notification")
+		}
+	}
+}
+
+type notLeader struct {
+	services.AlwaysLeader
+}
+
+func (notLeader) IsLeader(types.FeedType) bool { return false }
+
+func TestGateway_NotifyNotLeaderServesSubscriptions(t *testing.T) {
+	_, g := setup(t, 1)
+	g.BxConfig.WebsocketEnabled = true
+	g.feedLeader = notLeader{}
+
+	// the leader election only applies to the published export, local subscriptions are still served
+	g.notify(types.NewNextSprintValidatorsNotification(10, []string{"0x1"}))
+	select {
+	case notification := <-g.feedManagerChan:
+		assert.Equal(t, types.NextSprintValidatorsFeed, notification.NotificationType())
+	default:
+		assert.Fail(t, "notification was not delivered to the subscriptions")
+	}
+}
+
 func expectNoFeedNotification(t *testing.T, bridge blockchain.Bridge, g *gateway, isBDNBlock bool, blockHeight int, expectedBestBlockHeight int, expectedSkipBlockCount int) {
 	ethBlock := bxmock.NewEthBlock(uint64(blockHeight), common.Hash{})
 	bxBlock, _ := bridge.BlockBlockchainToBDN(eth.NewBlockInfo(ethBlock, nil))
@@ -1476,3 +1666,70 @@ func TestretrieveOriginalSenderAccountID_NoMetadata(t *testing.T) {
 
 	require.NotNil(t, err)
 }
+
+func TestGateway_FeedPeerTxs(t *testing.T) {
+	_, g := setup(t, 1)
+	g.BxConfig.WebsocketEnabled = true
+	g.feedManagerChan = make(chan types.Notification, 10)
+
+	ethTx := bxmock.NewSignedEthTx(ethtypes.LegacyTxType, 1, nil, nil)
+	rawTx, err := ethTx.MarshalBinary()
+	require.NoError(t, err)
+
+	g.publishFeedPeerTx(rawTx, "10.0.0.1:5001")
+	require.Len(t, g.feedManagerChan, 1)
+	notification := (<-g.feedManagerChan).(*types.NewTransactionNotification)
+	assert.Equal(t, "10.0.0.1:5001", notification.Origin())
+	assert.Equal(t, types.SHA256Hash(ethTx.Hash()).Format(true), notification.GetHash())
+
+	// same tx from another peer
+	g.publishFeedPeerTx(rawTx, "10.0.0.2:5001")
+	assert.Len(t, g.feedManagerChan, 0)
+
+	// same tx from the node is processed, but not published again to newTxs
+	content, err := rlp.EncodeToBytes(ethTx)
+	require.NoError(t, err)
+	tx := bxmessage.NewTx(types.SHA256Hash(ethTx.Hash()), content, networkNum, types.TFLocalRegion, types.EmptyAccountID)
+	g.processTransaction(tx, connections.NewBlockchainConn(blockchainIPEndpoint))
+	assert.True(t, g.TxStore.HasContent(tx.Hash()))
+	for len(g.feedManagerChan) > 0 {
+		assert.NotEqual(t, types.NewTxsFeed, (<-g.feedManagerChan).NotificationType())
+	}
+
+	// tx received locally first is not published from peers
+	ethTx = bxmock.NewSignedEthTx(ethtypes.LegacyTxType, 2, nil, nil)
+	content, err = rlp.EncodeToBytes(ethTx)
+	require.NoError(t, err)
+	g.processTransaction(bxmessage.NewTx(types.SHA256Hash(ethTx.Hash()), content, networkNum, types.TFLocalRegion, types.EmptyAccountID), connections.NewBlockchainConn(blockchainIPEndpoint))
+	for len(g.feedManagerChan) > 0 {
+		<-g.feedManagerChan
+	}
+	rawTx, err = ethTx.MarshalBinary()
+	require.NoError(t, err)
+	g.publishFeedPeerTx(rawTx, "10.0.0.1:5001")
+	assert.Len(t, g.feedManagerChan, 0)
+}
+
+func TestGateway_RefreshFeatureFlags(t *testing.T) {
+	_, g := setup(t, 1)
+	sdn := g.sdn.(*mock_connections.MockSDNHTTP)
+
+	gomock.InOrder(
+		sdn.EXPECT().FetchFeatureFlags().Return(map[string]bool{string(services.FeatureTxBatching): false}, nil),
+		sdn.EXPECT().FetchFeatureFlags().Return(nil, fmt.Errorf("sdn unavailable")),
+		sdn.EXPECT().FetchFeatureFlags().Return(map[string]bool{string(services.FeatureTxBatching): true}, nil),
+	)
+
+	assert.True(t, g.featureFlags.Enabled(services.FeatureTxBatching))
+
+	// the SDN turns the feature off at runtime
+	g.refreshFeatureFlags()
+	assert.False(t, g.featureFlags.Enabled(services.FeatureTxBatching))
+
+	// the last values are kept while the SDN is unavailable
+	g.refreshFeatureFlags()
+	assert.False(t, g.featureFlags.Enabled(services.FeatureTxBatching))
+
+	g.refreshFeatureFlags()
+	assert.True(t, g.featureFlags.Enabled(services.FeatureTxBatching))
+}
@@ -0,0 +1,135 @@
+package nodes
+
+import (
+	"context"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// txPoolReconciliationSource is reported as the relay of the forwarded transactions by the BDN tx validation
+const txPoolReconciliationSource = "txpool-reconciliation"
+
+// recordBlockTxs remembers the transactions of the block, so the reconciliation does not forward them again
+func (g *gateway) recordBlockTxs(bxBlock *types.BxBlock) {
+	if g.BxConfig.TxPoolReconciliationInterval <= 0 {
+		return
+	}
+	for _, tx := range bxBlock.Txs {
+		if hash := tx.Hash(); hash != (types.SHA256Hash{}) {
+			g.recentBlockTxs.Add(hash.String(), bxgateway.TxPoolReconciliationMaxAge)
+		}
+	}
+}
+
+// reconcileTxPoolOnInterval periodically compares the node tx pool with the recent transactions of the gateway
+func (g *gateway) reconcileTxPoolOnInterval(ctx context.Context, interval time.Duration) {
+	ticker := g.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			g.reconcileTxPool()
+		}
+	}
+}
+
+// reconcileTxPool forwards the recent transactions the node is missing and records the ones the gateway missed
+func (g *gateway) reconcileTxPool() {
+	provider, ok := g.wsManager.SyncedProvider()
+	if !ok {
+		return
+	}
+
+	nodeHashes, err := blockchain.FetchTxPoolContentHashes(provider)
+	if err != nil {
+		g.log.Debugf("failed to fetch txpool_content from %v: %v", provider.Addr(), err)
+		return
+	}
+
+	now := g.clock.Now()
+	result := blockchain.TxPoolReconciliation{
+		Endpoint: provider.Addr(),
+		Time:     now,
+		NodeTxs:  len(nodeHashes),
+	}
+
+	// give the node time to receive the transactions before treating them as missing
+	var missingInNode []*types.BxTransaction
+	for tx := range g.TxStore.Iter() {
+		age := now.Sub(tx.AddTime())
+		if age < bxgateway.TxPoolReconciliationMinAge || age > bxgateway.TxPoolReconciliationMaxAge {
+			continue
+		}
+		if !tx.HasContent() || !((!g.BxConfig.BlocksOnly && tx.Flags().ShouldDeliverToNode()) || (g.BxConfig.AllTransactions && !tx.Flags().IsPrivateTx())) {
+			continue
+		}
+
+		result.GatewayTxs++
+		if _, ok := nodeHashes[tx.Hash()]; ok {
+			continue
+		}
+		// the node dropped the transactions of the recent blocks from its tx pool, they are not missing
+		if g.recentBlockTxs.Exists(tx.Hash().String()) {
+			continue
+		}
+		missingInNode = append(missingInNode, tx)
+	}
+	result.MissingInNode = len(missingInNode)
+
+	for hash := range nodeHashes {
+		if !g.TxStore.Known(hash) {
+			result.MissingInGateway++
+			g.log.Tracef("tx %v found in txpool of %v is not known by the gateway", hash, provider.Addr())
+		}
+	}
+
+	// the node tx pool monitor has priority over the reconciliation
+	if len(missingInNode) > 0 && g.txPoolMonitor.State() == blockchain.TxPoolNormal && !g.BxConfig.NoTxsToBlockchain {
+		// the missing transactions go through the same checks as the transactions received from the BDN
+		forwarded := make([]*types.BxTransaction, 0, len(missingInNode))
+		for _, tx := range missingInNode {
+			if g.txAllowedToNodes(tx.Content()) {
+				forwarded = append(forwarded, tx)
+			}
+		}
+
+		if len(forwarded) > 0 {
+			err = g.sendTransactionsFromBDN(blockchain.Transactions{
+				Transactions:   forwarded,
+				ConnectionType: utils.Relay,
+			}, txPoolReconciliationSource)
+			if err != nil {
+				g.log.Errorf("failed to send %v transactions missing in txpool of %v to bridge: %v", len(forwarded), provider.Addr(), err)
+			} else {
+				result.Forwarded = len(forwarded)
+			}
+		}
+	}
+
+	g.txPoolReconciliationLock.Lock()
+	g.txPoolReconciliation = &result
+	g.txPoolReconciliationLock.Unlock()
+
+	g.log.WithFields(log.Fields{
+		"endpoint":         result.Endpoint,
+		"nodeTxs":          result.NodeTxs,
+		"gatewayTxs":       result.GatewayTxs,
+		"missingInNode":    result.MissingInNode,
+		"missingInGateway": result.MissingInGateway,
+		"forwarded":        result.Forwarded,
+	}).Debug("txpool reconciliation")
+}
+
+func (g *gateway) lastTxPoolReconciliation() *blockchain.TxPoolReconciliation {
+	g.txPoolReconciliationLock.Lock()
+	defer g.txPoolReconciliationLock.Unlock()
+	return g.txPoolReconciliation
+}
@@ -0,0 +1,5 @@
+package gateway
+
+// generates the go code of gateway.proto and of the versioned feeds contract feeds/v1/feeds.proto, protoc runs in
+// docker so the generated code does not depend on the local toolchain
+//go:generate make genproto
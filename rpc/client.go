@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/bloXroute-Labs/gateway/v2/config"
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
@@ -25,7 +27,7 @@ func AuthOption(grpcConfig *config.GRPC) (authOption grpc.DialOption, included b
 }
 
 func connectInsecure(grpcConfig *config.GRPC) (*grpc.ClientConn, error) {
-	address := fmt.Sprintf("%v:%v", grpcConfig.Host, grpcConfig.Port)
+	address := net.JoinHostPort(grpcConfig.Host, strconv.Itoa(grpcConfig.Port))
 	authOption, required := AuthOption(grpcConfig)
 
 	if required {
@@ -266,10 +266,27 @@ type Account struct {
 	SolanaDexAPIRateLimit   BDNQuotaService `json:"solana_dex_api_rate_limit"`
 	SolanaDexAPIStreamLimit BDNQuotaService `json:"solana_dex_api_stream_limit"`
 
+	// concurrent websocket connections and gRPC streams, subscriptions of a websocket connection and conditions of the
+	// filters of a subscription, 0 means no limit
+	MaxConnections                BDNQuotaService `json:"max_connections"`
+	MaxSubscriptionsPerConnection BDNQuotaService `json:"max_subscriptions_per_connection"`
+	MaxFilterComplexity           BDNQuotaService `json:"max_filter_complexity"`
+
 	TwammStreaming         BDNFeedService `json:"twamm_streaming"`
 	PrivateOrdersStreaming BDNFeedService `json:"private_orders_streaming"`
 
 	Bundles BDNBundlesService `json:"bundles"`
+
+	// AllowedContracts restricts the destination of the submitted transactions, empty means no restriction
+	AllowedContracts []string `json:"allowed_contracts,omitempty"`
+
+	TxDefaults TxDefaults `json:"tx_defaults"`
+}
+
+// TxDefaults are the flags applied to the transactions submitted by an account when the request omits them
+type TxDefaults struct {
+	FrontRunningProtection bool `json:"front_running_protection"`
+	ValidatorsOnly         bool `json:"validators_only"`
 }
 
 // Validate verifies the response that the response from bxapi is well understood
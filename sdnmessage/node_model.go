@@ -7,6 +7,7 @@ import (
 
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/version"
 )
 
 // NodeModel represents metadata on a given node in the bloxroute network
@@ -66,6 +67,8 @@ type NodeModel struct {
 	RelayType                 types.RelayType  `json:"relay_type"`
 	StartupArgs               string           `json:"startup_args"`
 	BlockchainRPCEnabled      bool             `json:"blockchain_rpc_enabled"`
+	BuildInfo                 version.Info     `json:"build_info"`
+	FeatureFlags              map[string]bool  `json:"feature_flags"`
 }
 
 // Pack serializes a NodeModel into a buffer for sending
@@ -0,0 +1,63 @@
+package servers
+
+import (
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// backpressurePolicy decides what happens to a notification published while the channel of the subscription is
+// full, which trades the completeness of the feed against its latency for clients reading slower than it is published
+type backpressurePolicy string
+
+const (
+	// backpressureDisconnect unsubscribes the client and closes its connection, it is the default policy
+	backpressureDisconnect backpressurePolicy = "disconnect"
+	// backpressureDropOldest drops the oldest notification waiting in the channel to make room for the new one
+	backpressureDropOldest backpressurePolicy = "drop_oldest"
+	// backpressureDropNewest drops the new notification
+	backpressureDropNewest backpressurePolicy = "drop_newest"
+)
+
+func parseBackpressurePolicy(policy string) (backpressurePolicy, error) {
+	switch backpressurePolicy(policy) {
+	case "", backpressureDisconnect:
+		return backpressureDisconnect, nil
+	case backpressureDropOldest, backpressureDropNewest:
+		return backpressurePolicy(policy), nil
+	default:
+		return "", fmt.Errorf("invalid Backpressure %v, must be one of %v, %v or %v", policy,
+			backpressureDisconnect, backpressureDropOldest, backpressureDropNewest)
+	}
+}
+
+// offer sends the notification on the subscription channel without blocking, applying the backpressure policy of
+// the subscription if the channel is full. It returns false if the subscription must be disconnected.
+func (c *ClientSubscription) offer(notification types.Notification) bool {
+	select {
+	case c.feed <- notification:
+		return true
+	default:
+	}
+
+	switch c.backpressure {
+	case backpressureDropNewest:
+		c.delivery.drop()
+		return true
+	case backpressureDropOldest:
+		select {
+		case <-c.feed:
+			c.delivery.drop()
+		default:
+		}
+		select {
+		case c.feed <- notification:
+		default:
+			c.delivery.drop()
+		}
+		return true
+	default:
+		c.delivery.drop()
+		return false
+	}
+}
@@ -0,0 +1,55 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackpressurePolicy(t *testing.T) {
+	policy, err := parseBackpressurePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, backpressureDisconnect, policy)
+
+	policy, err = parseBackpressurePolicy("drop_oldest")
+	require.NoError(t, err)
+	assert.Equal(t, backpressureDropOldest, policy)
+
+	_, err = parseBackpressurePolicy("block")
+	assert.Error(t, err)
+}
+
+func TestClientSubscriptionOffer(t *testing.T) {
+	notification := func(hash string) types.Notification {
+		return &types.ContractCreationNotification{TxHash: hash}
+	}
+	fullSubscription := func(policy backpressurePolicy) *ClientSubscription {
+		sub := &ClientSubscription{feed: make(chan types.Notification, 2), delivery: newDeliveryStats(nil), backpressure: policy}
+		require.True(t, sub.offer(notification("0x1")))
+		require.True(t, sub.offer(notification("0x2")))
+		return sub
+	}
+	hashes := func(sub *ClientSubscription) []string {
+		var result []string
+		for len(sub.feed) > 0 {
+			result = append(result, (<-sub.feed).GetHash())
+		}
+		return result
+	}
+
+	sub := fullSubscription(backpressureDisconnect)
+	assert.False(t, sub.offer(notification("0x3")))
+	assert.Equal(t, uint64(1), sub.delivery.summary().Dropped)
+
+	sub = fullSubscription(backpressureDropNewest)
+	assert.True(t, sub.offer(notification("0x3")))
+	assert.Equal(t, []string{"0x1", "0x2"}, hashes(sub))
+	assert.Equal(t, uint64(1), sub.delivery.summary().Dropped)
+
+	sub = fullSubscription(backpressureDropOldest)
+	assert.True(t, sub.offer(notification("0x3")))
+	assert.Equal(t, []string{"0x2", "0x3"}, hashes(sub))
+	assert.Equal(t, uint64(1), sub.delivery.summary().Dropped)
+}
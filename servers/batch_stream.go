@@ -0,0 +1,170 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// maxBatchSize is the number of requests a JSON-RPC batch can hold
+const maxBatchSize = 100
+
+// batchError is the response to an invalid request of a batch, or to an invalid batch, whose ID is unknown
+type batchError struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *jsonrpc2.ID    `json:"id"`
+	Error   *jsonrpc2.Error `json:"error"`
+}
+
+func newBatchError(message string) json.RawMessage {
+	response, _ := json.Marshal(batchError{
+		JSONRPC: "2.0",
+		Error:   &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidRequest, Message: message},
+	})
+	return response
+}
+
+// batchMessage holds the fields identifying a request or a response of a batch
+type batchMessage struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+}
+
+// batch collects the responses of the requests of a batch, which are written at once in the order of the requests
+type batch struct {
+	responses []json.RawMessage
+	// pending are the indexes of the responses still expected, by request ID
+	pending map[jsonrpc2.ID]int
+}
+
+// batchObjectStream is an ObjectStream accepting JSON-RPC batches. The requests of a batch are read one by one
+// by the connection, and their responses are held until the response to the last of them is written.
+type batchObjectStream struct {
+	jsonrpc2.ObjectStream
+
+	// queue holds the requests of the last batch not read yet, it is only accessed by the reading goroutine
+	queue []json.RawMessage
+
+	// lock serializes the writes of the connection with the responses written while reading a batch
+	lock    sync.Mutex
+	batches map[jsonrpc2.ID]*batch
+}
+
+func newBatchObjectStream(stream jsonrpc2.ObjectStream) *batchObjectStream {
+	return &batchObjectStream{
+		ObjectStream: stream,
+		batches:      make(map[jsonrpc2.ID]*batch),
+	}
+}
+
+// ReadObject reads the next request, either a single request or the next request of a batch
+func (s *batchObjectStream) ReadObject(v interface{}) error {
+	for len(s.queue) == 0 {
+		var message json.RawMessage
+		if err := s.ObjectStream.ReadObject(&message); err != nil {
+			return err
+		}
+		if trimmed := bytes.TrimLeft(message, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '[' {
+			return json.Unmarshal(message, v)
+		}
+		if err := s.readBatch(message); err != nil {
+			return err
+		}
+	}
+
+	request := s.queue[0]
+	s.queue = s.queue[1:]
+	return json.Unmarshal(request, v)
+}
+
+// readBatch queues the valid requests of the batch and registers the responses it expects
+func (s *batchObjectStream) readBatch(message json.RawMessage) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var requests []json.RawMessage
+	if err := json.Unmarshal(message, &requests); err != nil {
+		return s.ObjectStream.WriteObject(newBatchError(fmt.Sprintf("invalid batch: %v", err)))
+	}
+	if len(requests) == 0 {
+		return s.ObjectStream.WriteObject(newBatchError("empty batch"))
+	}
+	if len(requests) > maxBatchSize {
+		return s.ObjectStream.WriteObject(newBatchError(fmt.Sprintf("batch of %v requests, expected at most %v", len(requests), maxBatchSize)))
+	}
+
+	b := &batch{pending: make(map[jsonrpc2.ID]int)}
+	for _, request := range requests {
+		var m batchMessage
+		if err := json.Unmarshal(request, &m); err != nil || m.Method == "" {
+			b.responses = append(b.responses, newBatchError("invalid request"))
+			continue
+		}
+		if m.ID == nil {
+			// notifications are handled without response
+			s.queue = append(s.queue, request)
+			continue
+		}
+		var id jsonrpc2.ID
+		if err := json.Unmarshal(*m.ID, &id); err != nil {
+			b.responses = append(b.responses, newBatchError("invalid request ID"))
+			continue
+		}
+		_, inBatch := b.pending[id]
+		if _, ok := s.batches[id]; ok || inBatch {
+			b.responses = append(b.responses, newBatchError(fmt.Sprintf("request ID %v is already pending", id)))
+			continue
+		}
+		b.pending[id] = len(b.responses)
+		b.responses = append(b.responses, nil)
+		s.queue = append(s.queue, request)
+	}
+
+	if len(b.pending) == 0 {
+		if len(b.responses) == 0 {
+			return nil
+		}
+		return s.ObjectStream.WriteObject(b.responses)
+	}
+	for id := range b.pending {
+		s.batches[id] = b
+	}
+	return nil
+}
+
+// WriteObject writes the object, unless it is the response to a request of a batch which still expects responses
+func (s *batchObjectStream) WriteObject(obj interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.batches) == 0 {
+		return s.ObjectStream.WriteObject(obj)
+	}
+
+	response, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var m batchMessage
+	if err = json.Unmarshal(response, &m); err != nil || m.Method != "" || m.ID == nil {
+		return s.ObjectStream.WriteObject(json.RawMessage(response))
+	}
+	var id jsonrpc2.ID
+	if err = json.Unmarshal(*m.ID, &id); err != nil {
+		return s.ObjectStream.WriteObject(json.RawMessage(response))
+	}
+	b, ok := s.batches[id]
+	if !ok {
+		return s.ObjectStream.WriteObject(json.RawMessage(response))
+	}
+
+	b.responses[b.pending[id]] = response
+	delete(b.pending, id)
+	delete(s.batches, id)
+	if len(b.pending) > 0 {
+		return nil
+	}
+	return s.ObjectStream.WriteObject(b.responses)
+}
@@ -0,0 +1,90 @@
+package servers
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockObjectStream reads the messages it holds and records the objects written as JSON
+type mockObjectStream struct {
+	messages []string
+	written  []string
+}
+
+func (m *mockObjectStream) ReadObject(v interface{}) error {
+	if len(m.messages) == 0 {
+		return io.EOF
+	}
+	message := m.messages[0]
+	m.messages = m.messages[1:]
+	return json.Unmarshal([]byte(message), v)
+}
+
+func (m *mockObjectStream) WriteObject(obj interface{}) error {
+	encoded, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	m.written = append(m.written, string(encoded))
+	return nil
+}
+
+func (m *mockObjectStream) Close() error {
+	return nil
+}
+
+func reply(t *testing.T, stream jsonrpc2.ObjectStream, id jsonrpc2.ID, result string) {
+	raw := json.RawMessage(result)
+	require.NoError(t, stream.WriteObject(&jsonrpc2.Response{ID: id, Result: &raw}))
+}
+
+func TestBatchObjectStream(t *testing.T) {
+	inner := &mockObjectStream{messages: []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`,
+		`[{"jsonrpc": "2.0", "id": 2, "method": "ping"}, {"jsonrpc": "2.0", "method": "notify"}, 5, {"jsonrpc": "2.0", "id": "a", "method": "ping"}]`,
+		`[]`,
+		`[5]`,
+	}}
+	stream := newBatchObjectStream(inner)
+
+	var request jsonrpc2.Request
+	// a single request is answered right away
+	require.NoError(t, stream.ReadObject(&request))
+	assert.Equal(t, jsonrpc2.ID{Num: 1}, request.ID)
+	reply(t, stream, request.ID, `1`)
+	require.Len(t, inner.written, 1)
+
+	var requests []jsonrpc2.Request
+	for i := 0; i < 3; i++ {
+		var r jsonrpc2.Request
+		require.NoError(t, stream.ReadObject(&r))
+		requests = append(requests, r)
+	}
+	assert.Equal(t, "notify", requests[1].Method)
+
+	// the responses are written in the order of the requests once all of them are answered
+	reply(t, stream, requests[2].ID, `"a"`)
+	require.NoError(t, stream.WriteObject(&jsonrpc2.Request{Method: "subscribe", Notif: true}))
+	require.Len(t, inner.written, 2)
+	reply(t, stream, requests[0].ID, `2`)
+	require.Len(t, inner.written, 3)
+
+	var responses []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(inner.written[2]), &responses))
+	require.Len(t, responses, 3)
+	assert.Equal(t, float64(2), responses[0]["result"])
+	assert.Contains(t, responses[1], "error")
+	assert.Nil(t, responses[1]["id"])
+	assert.Equal(t, "a", responses[2]["result"])
+
+	// the invalid batches are answered with an error, and the stream keeps reading
+	assert.Equal(t, io.EOF, stream.ReadObject(&request))
+	require.Len(t, inner.written, 5)
+	assert.Contains(t, inner.written[3], "empty batch")
+	assert.Contains(t, inner.written[4], "invalid request")
+}
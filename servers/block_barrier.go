@@ -0,0 +1,109 @@
+package servers
+
+import (
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// maxPendingBlockBarriers - number of incomplete blocks tracked by a block barrier, the oldest block is dropped
+// without a blockComplete event when a feed never notifies it (e.g. receipts could not be fetched)
+const maxPendingBlockBarriers = 64
+
+// BlockComplete - event sent on a combined subscription with BlockBarrier after the block and all the
+// notifications derived from it were sent
+type BlockComplete struct {
+	BlockHash   string `json:"block_hash"`
+	BlockNumber string `json:"block_number,omitempty"`
+}
+
+type pendingBlockBarrier struct {
+	number string
+	feeds  map[types.FeedType]struct{}
+}
+
+// blockBarrier tracks which feeds of a combined subscription were sent for each block
+type blockBarrier struct {
+	feeds   map[types.FeedType]struct{}
+	pending map[string]*pendingBlockBarrier
+	order   []string
+}
+
+// isBlockBarrierFeed returns true if the feed notifies a block, or notifications derived from a block
+func isBlockBarrierFeed(feed types.FeedType) bool {
+	switch feed {
+	case types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed, types.OnBlockFeed:
+		return true
+	}
+	return false
+}
+
+func newBlockBarrier(feeds []types.FeedType) *blockBarrier {
+	b := &blockBarrier{
+		feeds:   make(map[types.FeedType]struct{}),
+		pending: make(map[string]*pendingBlockBarrier),
+	}
+	for _, feed := range feeds {
+		if isBlockBarrierFeed(feed) {
+			b.feeds[feed] = struct{}{}
+		}
+	}
+	return b
+}
+
+// notificationBlock returns the hash and number of the block of the notification
+func notificationBlock(notification types.Notification) (string, string, bool) {
+	switch n := notification.(type) {
+	case *types.EthBlockNotification:
+		// the block is not complete until its full notification is sent
+		if n.BlockHash == nil || n.Header == nil || n.HeaderOnly {
+			return "", "", false
+		}
+		return n.GetHash(), n.Header.Number, true
+	case *types.TxReceiptsNotification:
+		hash := n.GetHash()
+		return hash, n.BlockNumber, hash != ""
+	}
+	return "", "", false
+}
+
+// complete records that all the events of the feed for the block were sent, and returns the blockComplete event
+// once every feed of the barrier was sent for the block
+func (b *blockBarrier) complete(feed types.FeedType, notification types.Notification) (*BlockComplete, bool) {
+	if _, ok := b.feeds[feed]; !ok {
+		return nil, false
+	}
+	hash, number, ok := notificationBlock(notification)
+	if !ok {
+		return nil, false
+	}
+	hash = strings.ToLower(hash)
+
+	block, ok := b.pending[hash]
+	if !ok {
+		if len(b.order) >= maxPendingBlockBarriers {
+			delete(b.pending, b.order[0])
+			b.order = b.order[1:]
+		}
+		block = &pendingBlockBarrier{feeds: make(map[types.FeedType]struct{})}
+		b.pending[hash] = block
+		b.order = append(b.order, hash)
+	}
+	if block.number == "" {
+		block.number = number
+	}
+	block.feeds[feed] = struct{}{}
+
+	if len(block.feeds) < len(b.feeds) {
+		return nil, false
+	}
+
+	delete(b.pending, hash)
+	for i, pendingHash := range b.order {
+		if pendingHash == hash {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			break
+		}
+	}
+	return &BlockComplete{BlockHash: hash, BlockNumber: block.number}, true
+}
@@ -0,0 +1,52 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockBarrier(t *testing.T) {
+	barrier := newBlockBarrier([]types.FeedType{types.NewTxsFeed, types.NewBlocksFeed, types.TxReceiptsFeed})
+
+	ethBlock := bxmock.NewEthBlock(10, common.Hash{})
+	block, err := types.NewEthBlockNotification(ethBlock.Hash(), ethBlock, nil, false)
+	require.NoError(t, err)
+	receipts := types.NewTxReceiptsNotification(nil)
+	receipts.BlockHash = block.GetHash()
+	receipts.BlockNumber = block.Header.Number
+
+	// feeds not derived from blocks are ignored
+	_, ok := barrier.complete(types.NewTxsFeed, block)
+	assert.False(t, ok)
+
+	// the header only notification does not complete the block
+	header, err := types.NewEthBlockHeaderNotification(ethBlock.Hash(), ethBlock.Header())
+	require.NoError(t, err)
+	_, ok = barrier.complete(types.NewBlocksFeed, header)
+	assert.False(t, ok)
+
+	// receipts can be sent before the block
+	_, ok = barrier.complete(types.TxReceiptsFeed, receipts)
+	assert.False(t, ok)
+	blockComplete, ok := barrier.complete(types.NewBlocksFeed, block)
+	require.True(t, ok)
+	assert.Equal(t, block.GetHash(), blockComplete.BlockHash)
+	assert.Equal(t, block.Header.Number, blockComplete.BlockNumber)
+	assert.Empty(t, barrier.pending)
+
+	// blocks that are never completed are dropped
+	for i := 0; i < maxPendingBlockBarriers+1; i++ {
+		ethBlock = bxmock.NewEthBlock(uint64(i), common.Hash{})
+		block, err = types.NewEthBlockNotification(ethBlock.Hash(), ethBlock, nil, false)
+		require.NoError(t, err)
+		_, ok = barrier.complete(types.NewBlocksFeed, block)
+		assert.False(t, ok)
+	}
+	assert.Len(t, barrier.pending, maxPendingBlockBarriers)
+	assert.Len(t, barrier.order, maxPendingBlockBarriers)
+}
@@ -0,0 +1,173 @@
+package servers
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// strictOrderingDelay - longest time a block of a StrictOrdering subscription is held back waiting for the blocks
+	// of the heights below it, the held back blocks are sent in order without the missing ones afterwards
+	strictOrderingDelay = time.Second
+	// maxStrictOrderingPending - number of heights held back by a StrictOrdering subscription, the held back blocks
+	// are sent without waiting for the delay once exceeded
+	maxStrictOrderingPending = 64
+	// maxStrictOrderingSent - number of sent block hashes remembered by a StrictOrdering subscription, so a block
+	// sent again (e.g. its full notification after the header only one) is not taken for a replacement
+	maxStrictOrderingSent = 128
+)
+
+// BlockReorg - event sent on a subscription with StrictOrdering before a block at or below the height of the last
+// block sent, the block replaces the blocks sent from its height up to ReplacedBlockNumber
+type BlockReorg struct {
+	Event               string `json:"event"`
+	BlockHash           string `json:"block_hash"`
+	BlockNumber         string `json:"block_number"`
+	ReplacedBlockNumber string `json:"replaced_block_number"`
+}
+
+// orderedBlock is either a block notification or the reorg event preceding its replacement block
+type orderedBlock struct {
+	notification types.Notification
+	reorg        *BlockReorg
+}
+
+// blockOrderer delivers the block notifications of a subscription in ascending height order, holding back the blocks
+// which arrive before the blocks of the heights below them
+type blockOrderer struct {
+	clock      utils.Clock
+	lastHeight uint64
+	pending    map[uint64][]*types.EthBlockNotification
+	timer      utils.Timer
+	sent       map[string]struct{}
+	sentOrder  []string
+}
+
+func newBlockOrderer(clock utils.Clock) *blockOrderer {
+	return &blockOrderer{
+		clock:   clock,
+		pending: make(map[uint64][]*types.EthBlockNotification),
+		sent:    make(map[string]struct{}),
+	}
+}
+
+// add returns the notifications to send in order following the block, which are none if it is held back
+func (o *blockOrderer) add(block *types.EthBlockNotification) []orderedBlock {
+	if block.Header == nil {
+		return []orderedBlock{{notification: block}}
+	}
+
+	height := block.Header.GetNumber()
+	if o.lastHeight != 0 && height > o.lastHeight+1 {
+		o.pending[height] = append(o.pending[height], block)
+		if len(o.pending) > maxStrictOrderingPending {
+			return o.flush()
+		}
+		if o.timer == nil {
+			o.timer = o.clock.Timer(strictOrderingDelay)
+		}
+		return nil
+	}
+
+	return o.drain(o.emit(nil, block))
+}
+
+// alert fires once the held back blocks waited for strictOrderingDelay, it is nil while none is held back
+func (o *blockOrderer) alert() <-chan time.Time {
+	if o == nil || o.timer == nil {
+		return nil
+	}
+	return o.timer.Alert()
+}
+
+// flush returns the held back blocks in order, giving up on the missing heights below them
+func (o *blockOrderer) flush() []orderedBlock {
+	heights := make([]uint64, 0, len(o.pending))
+	for height := range o.pending {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+
+	var blocks []orderedBlock
+	for _, height := range heights {
+		// a replacement block clears the blocks held back above it
+		pending, ok := o.pending[height]
+		if !ok {
+			continue
+		}
+		delete(o.pending, height)
+		for _, block := range pending {
+			blocks = o.emit(blocks, block)
+		}
+	}
+	o.stopTimer()
+	return blocks
+}
+
+// drain appends the held back blocks following the last block sent
+func (o *blockOrderer) drain(blocks []orderedBlock) []orderedBlock {
+	for {
+		pending, ok := o.pending[o.lastHeight+1]
+		if !ok {
+			break
+		}
+		delete(o.pending, o.lastHeight+1)
+		for _, block := range pending {
+			blocks = o.emit(blocks, block)
+		}
+	}
+	if len(o.pending) == 0 {
+		o.stopTimer()
+	}
+	return blocks
+}
+
+// emit appends the block, preceded by a reorg event if it replaces a block sent before
+func (o *blockOrderer) emit(blocks []orderedBlock, block *types.EthBlockNotification) []orderedBlock {
+	height := block.Header.GetNumber()
+	hash := strings.ToLower(block.GetHash())
+	if _, ok := o.sent[hash]; ok {
+		if height > o.lastHeight {
+			o.lastHeight = height
+		}
+		return append(blocks, orderedBlock{notification: block})
+	}
+
+	if o.lastHeight != 0 && height <= o.lastHeight {
+		// a backfilled block missed before the last block sent does not replace it
+		if block.Backfilled {
+			return blocks
+		}
+		blocks = append(blocks, orderedBlock{reorg: &BlockReorg{
+			Event:               bxgateway.BlockReorgEvent,
+			BlockHash:           hash,
+			BlockNumber:         hexutil.EncodeUint64(height),
+			ReplacedBlockNumber: hexutil.EncodeUint64(o.lastHeight),
+		}})
+		// the blocks held back above the replacement most likely belong to the replaced chain
+		o.pending = make(map[uint64][]*types.EthBlockNotification)
+		o.stopTimer()
+	}
+
+	o.lastHeight = height
+	if len(o.sentOrder) >= maxStrictOrderingSent {
+		delete(o.sent, o.sentOrder[0])
+		o.sentOrder = o.sentOrder[1:]
+	}
+	o.sent[hash] = struct{}{}
+	o.sentOrder = append(o.sentOrder, hash)
+	return append(blocks, orderedBlock{notification: block})
+}
+
+func (o *blockOrderer) stopTimer() {
+	if o.timer != nil {
+		o.timer.Stop()
+		o.timer = nil
+	}
+}
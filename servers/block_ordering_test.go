@@ -0,0 +1,96 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrderingTestBlock(t *testing.T, height uint64, parent common.Hash) *types.EthBlockNotification {
+	ethBlock := bxmock.NewEthBlock(height, parent)
+	block, err := types.NewEthBlockNotification(ethBlock.Hash(), ethBlock, nil, false)
+	require.NoError(t, err)
+	return block
+}
+
+func orderedHeights(blocks []orderedBlock) []uint64 {
+	heights := make([]uint64, 0, len(blocks))
+	for _, block := range blocks {
+		if block.reorg == nil {
+			heights = append(heights, block.notification.(*types.EthBlockNotification).Header.GetNumber())
+		}
+	}
+	return heights
+}
+
+func TestBlockOrderer(t *testing.T) {
+	clock := &utils.MockClock{}
+	orderer := newBlockOrderer(clock)
+	assert.Nil(t, orderer.alert())
+
+	block10 := newOrderingTestBlock(t, 10, common.Hash{})
+	block11 := newOrderingTestBlock(t, 11, common.Hash{})
+	block12 := newOrderingTestBlock(t, 12, common.Hash{})
+	block13 := newOrderingTestBlock(t, 13, common.Hash{})
+	assert.Equal(t, []uint64{10}, orderedHeights(orderer.add(block10)))
+
+	// the blocks arriving early are held back until the blocks below them arrive
+	assert.Empty(t, orderer.add(block12))
+	assert.NotNil(t, orderer.alert())
+	assert.Equal(t, []uint64{11, 12}, orderedHeights(orderer.add(block11)))
+	assert.Nil(t, orderer.alert())
+
+	// the same block sent again is not a replacement
+	assert.Equal(t, []uint64{12}, orderedHeights(orderer.add(block12)))
+
+	// the held back blocks are sent once the delay passed, without the missing heights
+	block15 := newOrderingTestBlock(t, 15, common.Hash{})
+	assert.Empty(t, orderer.add(block15))
+	clock.IncTime(strictOrderingDelay)
+	select {
+	case <-orderer.alert():
+	default:
+		t.Fatal("held back block was not flushed")
+	}
+	assert.Equal(t, []uint64{15}, orderedHeights(orderer.flush()))
+
+	// a backfilled block below the last block sent is dropped
+	block13.Backfilled = true
+	assert.Empty(t, orderer.add(block13))
+
+	// a replacement below the last block sent is preceded by a reorg event and drops the held back blocks
+	assert.Empty(t, orderer.add(newOrderingTestBlock(t, 17, common.Hash{})))
+	replacement := newOrderingTestBlock(t, 14, common.Hash{1})
+	blocks := orderer.add(replacement)
+	require.Len(t, blocks, 2)
+	require.NotNil(t, blocks[0].reorg)
+	assert.Equal(t, BlockReorg{
+		Event:               bxgateway.BlockReorgEvent,
+		BlockHash:           replacement.GetHash(),
+		BlockNumber:         "0xe",
+		ReplacedBlockNumber: "0xf",
+	}, *blocks[0].reorg)
+	assert.Equal(t, replacement, blocks[1].notification)
+	assert.Empty(t, orderer.pending)
+	assert.Nil(t, orderer.alert())
+	assert.Equal(t, []uint64{15}, orderedHeights(orderer.add(newOrderingTestBlock(t, 15, replacement.Header.ParentHash))))
+}
+
+func TestBlockOrderer_maxPending(t *testing.T) {
+	orderer := newBlockOrderer(&utils.MockClock{})
+	orderer.add(newOrderingTestBlock(t, 1, common.Hash{}))
+
+	for height := uint64(3); height < maxStrictOrderingPending+3; height++ {
+		assert.Empty(t, orderer.add(newOrderingTestBlock(t, height, common.Hash{})))
+	}
+	blocks := orderer.add(newOrderingTestBlock(t, maxStrictOrderingPending+3, common.Hash{}))
+	assert.Len(t, blocks, maxStrictOrderingPending+1)
+	assert.Equal(t, uint64(3), orderedHeights(blocks)[0])
+	assert.Empty(t, orderer.pending)
+}
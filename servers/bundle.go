@@ -149,6 +149,11 @@ type GatewayBundleResponse struct {
 	BundleHash string `json:"bundleHash"`
 }
 
+// GatewayBundleCancelResponse Response struct including the uuid of the cancelled bundles
+type GatewayBundleCancelResponse struct {
+	UUID string `json:"uuid"`
+}
+
 func trimZeroFromHEX(hex string) (string, error) {
 	trimmedHash := strings.TrimPrefix(hex, "0x")
 	value, err := strconv.ParseUint(trimmedHash, 16, 64)
@@ -0,0 +1,56 @@
+package servers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+)
+
+// bundleSubmitted tracks the status of the bundle sent to the BDN, simulating it on a synced node in the background
+func (f *FeedManager) bundleSubmitted(bundle *bxmessage.MEVBundle) {
+	if f.bundleStatusTracker == nil {
+		return
+	}
+	f.bundleStatusTracker.Submitted(bundle)
+
+	if f.nodeWSManager == nil {
+		return
+	}
+	ws, synced := f.nodeWSManager.SyncedProvider()
+	if !synced {
+		return
+	}
+	go func() {
+		result, err := simulateBundle(ws, jsonrpc.RPCBundleSimulationPayload{
+			Transaction: bundle.Transactions,
+			BlockNumber: bundle.BlockNumber,
+		})
+		f.bundleStatusTracker.Simulated(bundle, bundleSimulationError(bundle, result, err))
+	}()
+}
+
+// bundleSimulationError returns the error of the simulation, or the error of its first reverted tx which the bundle
+// does not allow to revert
+func bundleSimulationError(bundle *bxmessage.MEVBundle, result *rpcBundleSimulationResponse, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, tx := range result.Results {
+		if tx.Error != "" && !allowedToRevert(bundle, tx.TxHash) {
+			return fmt.Errorf("tx %v reverted: %v", tx.TxHash, tx.Error)
+		}
+	}
+	return nil
+}
+
+// allowedToRevert returns true if the bundle lists the tx in its reverting hashes
+func allowedToRevert(bundle *bxmessage.MEVBundle, txHash string) bool {
+	for _, hash := range bundle.RevertingHashes {
+		if strings.EqualFold(hash, txHash) {
+			return true
+		}
+	}
+	return false
+}
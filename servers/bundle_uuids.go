@@ -0,0 +1,137 @@
+package servers
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// bundleUUIDExpiration is how long the last bundle of a UUID is kept, the bundles target the next few blocks
+const bundleUUIDExpiration = 30 * time.Minute
+
+var errBundleUUIDInUse = errors.New("bundle uuid is used by another account")
+
+// bundleUUIDs keeps the last bundle submitted with each UUID through the gateway. A bundle with the UUID of a previous
+// one supersedes it, which the builders replace by its replacementUuid, and a cancellation retracts it. The bundles of
+// a UUID are sent under the lock, so they reach the BDN in the order they are recorded.
+type bundleUUIDs struct {
+	lock        sync.Mutex
+	clock       utils.Clock
+	bundles     map[string]uuidBundle
+	nextCleanup time.Time
+}
+
+type uuidBundle struct {
+	accountID  types.AccountID
+	bundleHash string
+	expiration time.Time
+}
+
+func newBundleUUIDs(clock utils.Clock) *bundleUUIDs {
+	return &bundleUUIDs{
+		clock:   clock,
+		bundles: make(map[string]uuidBundle),
+	}
+}
+
+// submit sends the bundle of the account, recording it as the last one of its UUID. It returns the hash of the bundle
+// it supersedes, empty if there is none.
+func (b *bundleUUIDs) submit(accountID types.AccountID, bundle *bxmessage.MEVBundle, send func() error) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	previous, err := b.owned(accountID, bundle.UUID)
+	if err != nil {
+		return "", err
+	}
+	if err = send(); err != nil {
+		return "", err
+	}
+	b.bundles[bundle.UUID] = uuidBundle{
+		accountID:  accountID,
+		bundleHash: bundle.BundleHash,
+		expiration: b.clock.Now().Add(bundleUUIDExpiration),
+	}
+	return previous.bundleHash, nil
+}
+
+// cancel sends the cancellation of the account, forgetting the last bundle of its UUID. It returns the hash of the
+// bundle it retracts, empty if the UUID was not submitted through the gateway.
+func (b *bundleUUIDs) cancel(accountID types.AccountID, uuid string, send func() error) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	previous, err := b.owned(accountID, uuid)
+	if err != nil {
+		return "", err
+	}
+	if err = send(); err != nil {
+		return "", err
+	}
+	delete(b.bundles, uuid)
+	return previous.bundleHash, nil
+}
+
+// owned returns the last bundle of the UUID, failing if another account submitted it
+func (b *bundleUUIDs) owned(accountID types.AccountID, uuid string) (uuidBundle, error) {
+	now := b.clock.Now()
+	if now.After(b.nextCleanup) {
+		for id, bundle := range b.bundles {
+			if now.After(bundle.expiration) {
+				delete(b.bundles, id)
+			}
+		}
+		b.nextCleanup = now.Add(time.Minute)
+	}
+
+	previous, ok := b.bundles[uuid]
+	if !ok || now.After(previous.expiration) {
+		return uuidBundle{}, nil
+	}
+	if previous.accountID != accountID {
+		return uuidBundle{}, fmt.Errorf("%w: %v", errBundleUUIDInUse, uuid)
+	}
+	return previous, nil
+}
+
+// sendMEVBundle sends the bundle to the BDN, the bundles with a UUID supersede the previous bundle of their UUID and
+// the cancellations retract it
+func (f *FeedManager) sendMEVBundle(bundle *bxmessage.MEVBundle, conn connections.Conn) error {
+	send := func() error {
+		return f.node.HandleMsg(bundle, conn, connections.RunForeground)
+	}
+	if bundle.UUID == "" {
+		if err := send(); err != nil {
+			return err
+		}
+		f.bundleSubmitted(bundle)
+		return nil
+	}
+
+	if bundle.IsCancellation() {
+		cancelled, err := f.bundleUUIDs.cancel(conn.GetAccountID(), bundle.UUID, send)
+		if err != nil {
+			return err
+		}
+		if cancelled != "" {
+			f.log.Debugf("bundle %v of uuid %v is cancelled by account %v", cancelled, bundle.UUID, conn.GetAccountID())
+		}
+		f.bundleStatusTracker.Cancelled(bundle.UUID)
+		return nil
+	}
+	replaced, err := f.bundleUUIDs.submit(conn.GetAccountID(), bundle, send)
+	if err != nil {
+		return err
+	}
+	if replaced != "" {
+		f.log.Debugf("bundle %v of uuid %v is replaced by %v", replaced, bundle.UUID, bundle.BundleHash)
+	}
+	f.bundleSubmitted(bundle)
+	return nil
+}
@@ -0,0 +1,73 @@
+package servers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBundleUUID = "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8"
+
+func TestBundleUUIDs(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	registry := newBundleUUIDs(clock)
+	sent := 0
+	send := func() error {
+		sent++
+		return nil
+	}
+
+	first := &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x01"}
+	replaced, err := registry.submit("account", first, send)
+	require.NoError(t, err)
+	assert.Empty(t, replaced)
+
+	// a bundle with the same uuid supersedes the previous one
+	second := &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x02"}
+	replaced, err = registry.submit("account", second, send)
+	require.NoError(t, err)
+	assert.Equal(t, "0x01", replaced)
+
+	// the uuid cannot be used by another account until it expires
+	_, err = registry.submit("other-account", &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x03"}, send)
+	assert.True(t, errors.Is(err, errBundleUUIDInUse))
+	_, err = registry.cancel(types.AccountID("other-account"), testBundleUUID, send)
+	assert.True(t, errors.Is(err, errBundleUUIDInUse))
+	assert.Equal(t, 2, sent)
+
+	cancelled, err := registry.cancel("account", testBundleUUID, send)
+	require.NoError(t, err)
+	assert.Equal(t, "0x02", cancelled)
+	assert.Equal(t, 3, sent)
+
+	// the cancelled uuid is free
+	replaced, err = registry.submit("other-account", &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x03"}, send)
+	require.NoError(t, err)
+	assert.Empty(t, replaced)
+
+	clock.IncTime(bundleUUIDExpiration + time.Second)
+	replaced, err = registry.submit("account", first, send)
+	require.NoError(t, err)
+	assert.Empty(t, replaced)
+}
+
+func TestBundleUUIDs_SendFailed(t *testing.T) {
+	registry := newBundleUUIDs(utils.RealClock{})
+
+	_, err := registry.submit("account", &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x01"}, func() error {
+		return errors.New("failed")
+	})
+	assert.Error(t, err)
+
+	// the failed bundle is not recorded
+	replaced, err := registry.submit("other-account", &bxmessage.MEVBundle{UUID: testBundleUUID, BundleHash: "0x02"}, func() error { return nil })
+	require.NoError(t, err)
+	assert.Empty(t, replaced)
+}
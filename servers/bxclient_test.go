@@ -0,0 +1,84 @@
+package servers
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth/test"
+	"github.com/bloXroute-Labs/gateway/v2/bxclient"
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/test/fixtures"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBxClient runs the client SDK against the websocket server, so the SDK follows the changes of the API
+func TestBxClient(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feedChan := make(chan types.Notification, 10)
+	gwAccount, _ := getMockCustomerAccountModel("gw")
+	cfg := config.Bx{WebsocketPort: 28339, ManageWSServer: true}
+	_, blockchainPeersInfo := test.GenerateBlockchainPeersInfo(1)
+	fm := NewFeedManager(ctx, bxmock.MockBxListener{}, feedChan, services.NewNoOpSubscriptionServices(),
+		types.NetworkNum(1), 1, types.NodeID("nodeID"),
+		eth.NewEthWSManager(blockchainPeersInfo, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false),
+		gwAccount, getMockCustomerAccountModel, "", "", cfg, statistics.NoStats{}, nil, nil, FeedManagerOptions{})
+	sourceFromNode := false
+	clientHandler := NewClientHandler(fm, nil, NewHTTPServer(fm, cfg.HTTPPort), true, nil, log.WithFields(log.Fields{
+		"component": "gatewayClientHandler",
+	}), &sourceFromNode, mockAuthorize, true)
+	go clientHandler.ManageWSServer(ctx, cfg.ManageWSServer)
+	go func() { _ = fm.Start(ctx) }()
+
+	var client *bxclient.WSClient
+	require.Eventually(t, func() bool {
+		var err error
+		client, err = bxclient.DialWS(ctx, "ws://127.0.0.1:28339/ws", bxclient.WSOptions{AuthHeader: "Z3c6c2VjcmV0"})
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	defer client.Close()
+
+	_, err := client.Ping(ctx)
+	require.NoError(t, err)
+	txHash, err := client.SendRawTx(ctx, fixtures.LegacyTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.LegacyTransactionHash[2:], txHash)
+
+	sub, err := client.SubscribeNewTxs(ctx, bxclient.SubscribeOptions{Include: []string{"tx_hash", "raw_tx", "tx_contents.to"}})
+	require.NoError(t, err)
+
+	content, err := hex.DecodeString(fixtures.LegacyTransaction)
+	require.NoError(t, err)
+	hash, err := types.NewSHA256HashFromString(fixtures.LegacyTransactionHash)
+	require.NoError(t, err)
+	bxTx := types.NewBxTransaction(hash, types.NetworkNum(1), types.TFLocalRegion, time.Now())
+	bxTx.SetContent(content)
+	feedChan <- types.CreateNewTransactionNotification(bxTx)
+
+	select {
+	case notification := <-sub.Notifications():
+		assert.Equal(t, fixtures.LegacyTransactionHash, notification.TxHash)
+		assert.NotEmpty(t, notification.TxContents["to"])
+		tx, err := notification.Transaction()
+		require.NoError(t, err)
+		assert.Equal(t, fixtures.LegacyTransactionHash, tx.Hash().Hex())
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered")
+	}
+
+	require.NoError(t, sub.Unsubscribe(ctx))
+	assert.Eventually(t, func() bool {
+		return !fm.SubscriptionTypeExists(types.NewTxsFeed)
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,114 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// calldataArgPrefix prefixes the decoded calldata arguments in the filters, e.g. {args.amount} > 1000
+const calldataArgPrefix = "args."
+
+// calldataDecoder decodes the calldata arguments of the txs calling the methods of an ABI fragment, so filters can
+// reference them. The names of the arguments are lower case, like the rest of the filters.
+type calldataDecoder struct {
+	abi abi.ABI
+}
+
+func newCalldataDecoder(fragment json.RawMessage) (*calldataDecoder, error) {
+	parsed, err := abi.JSON(bytes.NewReader(fragment))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ABI: %v", err)
+	}
+	if len(parsed.Methods) == 0 {
+		return nil, errors.New("invalid ABI: no methods")
+	}
+	return &calldataDecoder{abi: parsed}, nil
+}
+
+// emptyArgs returns a zero value of each argument of the methods, used to check the filters
+func (d *calldataDecoder) emptyArgs() map[string]interface{} {
+	args := make(map[string]interface{})
+	for _, method := range d.abi.Methods {
+		for _, input := range method.Inputs {
+			args[calldataArgPrefix+strings.ToLower(input.Name)] = filterValue(reflect.Zero(input.Type.GetType()).Interface())
+		}
+	}
+	return args
+}
+
+// args decodes the calldata arguments, returning false if the calldata does not call a method of the ABI
+func (d *calldataDecoder) args(calldata []byte) (map[string]interface{}, bool) {
+	if len(calldata) < 4 {
+		return nil, false
+	}
+	method, err := d.abi.MethodById(calldata[:4])
+	if err != nil {
+		return nil, false
+	}
+	values := make(map[string]interface{})
+	if err = method.Inputs.UnpackIntoMap(values, calldata[4:]); err != nil {
+		return nil, false
+	}
+
+	args := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		args[calldataArgPrefix+strings.ToLower(name)] = filterValue(value)
+	}
+	return args, true
+}
+
+// filterValue converts a decoded argument to the types the filters compare, numbers to float64 like value and
+// gas_price, addresses and bytes to lower case hex strings like to and from
+func filterValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return float64(0)
+		}
+		return types.BigIntAsFloat64(v)
+	case common.Address:
+		return strings.ToLower(v.Hex())
+	case []byte:
+		return hexutil.Encode(v)
+	case string:
+		return strings.ToLower(v)
+	case bool:
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Array:
+		// fixed size bytes, e.g. bytes32
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return hexutil.Encode(b)
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// hasCalldataArgs returns true if the filters reference calldata arguments
+func hasCalldataArgs(filters []string) bool {
+	for _, filter := range filters {
+		if strings.HasPrefix(filter, calldataArgPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package servers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhouzhuojie/conditions"
+)
+
+const erc20TransferABI = `[{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}]}]`
+
+func TestCalldataFilters(t *testing.T) {
+	decoder, err := newCalldataDecoder(json.RawMessage(erc20TransferABI))
+	require.NoError(t, err)
+
+	to := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	calldata, err := decoder.abi.Pack("transfer", to, big.NewInt(5000))
+	require.NoError(t, err)
+
+	args, ok := decoder.args(calldata)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"args.to":     "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		"args.amount": float64(5000),
+	}, args)
+
+	_, ok = decoder.args([]byte{0xa9, 0x05})
+	assert.False(t, ok)
+	_, ok = decoder.args([]byte{0x01, 0x02, 0x03, 0x04})
+	assert.False(t, ok)
+
+	expr, err := validateCalldataFilters("args.to = 0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2 and args.amount > 1000", true, decoder)
+	require.NoError(t, err)
+	assert.True(t, hasCalldataArgs(expr.Args()))
+	match, err := conditions.Evaluate(expr, args)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	// the arguments cannot be referenced without the ABI
+	_, err = validateFilters("args.amount > 1000", true)
+	assert.Error(t, err)
+
+	_, err = newCalldataDecoder(json.RawMessage(`[{"type": "event", "name": "Transfer", "inputs": []}]`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,33 @@
+package servers
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// listen listens on the address, the connections from the trusted proxies report the client address of their PROXY
+// protocol header as remote address if the PROXY protocol is enabled
+func (f *FeedManager) listen(addr string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if f.cfg.ProxyProtocol {
+		listener = utils.NewProxyProtocolListener(listener, f.cfg.TrustedProxies)
+	}
+	return listener, nil
+}
+
+// forwardedFor replaces the remote address of the requests from the trusted proxies by the client address of their
+// X-Forwarded-For header, so the per-IP controls apply to the clients rather than to the proxies
+func (f *FeedManager) forwardedFor(next http.Handler) http.Handler {
+	if len(f.cfg.TrustedProxies) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = f.cfg.TrustedProxies.ClientAddress(r)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -7,15 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/blockchain"
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/config"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
@@ -38,8 +41,14 @@ var upgrader = websocket.Upgrader{}
 
 // ClientHandler is a struct for gateway client handler object
 type ClientHandler struct {
-	feedManager              *FeedManager
+	feedManager *FeedManager
+	// wsLock guards the websocket server, which is replaced when it is re-bound
+	wsLock                   sync.Mutex
 	websocketServer          *http.Server
+	wsConnections            *wsConnections
+	wsListener               wsListener
+	pendingWSListener        *wsListener
+	wsListenerUpdated        chan struct{}
 	httpServer               *HTTPServer
 	getQuotaUsage            func(accountID string) (*connections.QuotaResponseBody, error)
 	enableBlockchainRPC      bool
@@ -54,6 +63,8 @@ func NewClientHandler(feedManager *FeedManager, websocketServer *http.Server, ht
 	return &ClientHandler{
 		feedManager:              feedManager,
 		websocketServer:          websocketServer,
+		wsListener:               wsListener{host: feedManager.cfg.WebsocketHost, port: feedManager.cfg.WebsocketPort},
+		wsListenerUpdated:        make(chan struct{}, 1),
 		httpServer:               httpServer,
 		getQuotaUsage:            getQuotaUsage,
 		enableBlockchainRPC:      enableBlockchainRPC,
@@ -66,15 +77,41 @@ func NewClientHandler(feedManager *FeedManager, websocketServer *http.Server, ht
 
 // ManageWSServer manage the ws connection of the blockchain node
 func (ch *ClientHandler) ManageWSServer(ctx context.Context, activeManagement bool) error {
+	running := false
 	if !activeManagement {
-		go ch.runWSServer()
+		ch.startWSServer()
+		running = true
+	}
+
+	// the server is re-bound when the TLS certificate is replaced
+	var certificateCheck <-chan time.Time
+	certificateModTime := filesModTime(ch.feedManager.certFile, ch.feedManager.keyFile)
+	if ch.feedManager.cfg.WebsocketTLSEnabled {
+		ticker := time.NewTicker(bxgateway.WSCertificateCheckInterval)
+		defer ticker.Stop()
+		certificateCheck = ticker.C
 	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			ch.shutdownWSServer()
+			ch.shutdownWSServer(wsShutdownReasonStopped)
 			return nil
+		case <-certificateCheck:
+			modTime := filesModTime(ch.feedManager.certFile, ch.feedManager.keyFile)
+			if !modTime.After(certificateModTime) {
+				continue
+			}
+			certificateModTime = modTime
+			if running {
+				ch.log.Infof("websocket TLS certificate was modified")
+				ch.rebindWSServer()
+			}
+		case <-ch.wsListenerUpdated:
+			if !ch.applyWSListener() || !running {
+				continue
+			}
+			ch.rebindWSServer()
 		case syncStatus := <-ch.feedManager.nodeWSManager.ReceiveNodeSyncStatusUpdate():
 			if !activeManagement {
 				// consume update
@@ -83,9 +120,11 @@ func (ch *ClientHandler) ManageWSServer(ctx context.Context, activeManagement bo
 
 			switch syncStatus {
 			case blockchain.Synced:
-				go ch.runWSServer()
+				ch.startWSServer()
+				running = true
 			case blockchain.Unsynced:
-				ch.shutdownWSServer()
+				ch.shutdownWSServer(wsShutdownReasonUnsynced)
+				running = false
 				ch.feedManager.subscriptionServices.SendSubscriptionResetNotification(make([]sdnmessage.SubscriptionModel, 0))
 			}
 		}
@@ -99,21 +138,39 @@ func (ch *ClientHandler) ManageHTTPServer() error {
 
 // Stop stops the servers
 func (ch *ClientHandler) Stop() error {
-	ch.shutdownWSServer()
+	ch.shutdownWSServer(wsShutdownReasonStopped)
 	return ch.httpServer.Stop()
 }
 
-func (ch *ClientHandler) runWSServer() error {
-	ch.websocketServer = newWSServer(ch.feedManager, ch.getQuotaUsage, ch.enableBlockchainRPC, ch.pendingTxsSourceFromNode, ch.authorize, ch.txFromFieldIncludable)
-	ch.log.Infof("starting websockets RPC server at: %v", ch.websocketServer.Addr)
-	var err error
+// startWSServer creates the websocket server with the current listener and serves it in the background
+func (ch *ClientHandler) startWSServer() {
+	ch.feedManager.stopWSSubscriptions(false)
+	ch.wsLock.Lock()
+	ch.wsConnections = newWSConnections()
+	ch.websocketServer = newWSServer(ch.feedManager, ch.wsConnections, ch.wsListener.addr(), ch.getQuotaUsage, ch.enableBlockchainRPC, ch.pendingTxsSourceFromNode, ch.authorize, ch.txFromFieldIncludable)
+	server := ch.websocketServer
+	ch.wsLock.Unlock()
+
+	go func() {
+		if err := ch.serveWSServer(server); err != nil {
+			ch.log.Error(err)
+		}
+	}()
+}
+
+func (ch *ClientHandler) serveWSServer(server *http.Server) error {
+	ch.log.Infof("starting websockets RPC server at: %v", server.Addr)
+	listener, err := ch.feedManager.listen(server.Addr)
+	if err != nil {
+		return fmt.Errorf("websockets RPC server failed to start: %v", err)
+	}
 	if ch.feedManager.cfg.WebsocketTLSEnabled {
-		ch.websocketServer.TLSConfig = &tls.Config{
+		server.TLSConfig = &tls.Config{
 			ClientAuth: tls.RequestClientCert,
 		}
-		err = ch.websocketServer.ListenAndServeTLS(ch.feedManager.certFile, ch.feedManager.keyFile)
+		err = server.ServeTLS(listener, ch.feedManager.certFile, ch.feedManager.keyFile)
 	} else {
-		err = ch.websocketServer.ListenAndServe()
+		err = server.Serve(listener)
 	}
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("websockets RPC server failed to start: %v", err)
@@ -124,17 +181,36 @@ func (ch *ClientHandler) runWSServer() error {
 	return nil
 }
 
-func (ch *ClientHandler) shutdownWSServer() {
-	ch.log.Infof("shutting down websocket server")
+// shutdownWSServer notifies the websocket clients of the shutdown, flushes the notifications queued for their
+// subscriptions and closes the connections within the shutdown deadline
+func (ch *ClientHandler) shutdownWSServer(reason string) {
+	ch.wsLock.Lock()
+	server, connections := ch.websocketServer, ch.wsConnections
+	ch.wsLock.Unlock()
+	if server == nil {
+		return
+	}
+
+	ch.log.Infof("shutting down websocket server: %v", reason)
+	ch.feedManager.stopWSSubscriptions(true)
+	var conns []*jsonrpc2.Conn
+	if connections != nil {
+		conns = connections.list()
+	}
+	ch.notifyWSShutdown(conns, reason, ch.feedManager.cfg.WSShutdownDeadline)
 	ch.feedManager.CloseAllClientConnections()
-	err := ch.websocketServer.Shutdown(ch.feedManager.context)
+	for _, conn := range conns {
+		// connections without subscriptions are not closed by the feed manager
+		_ = conn.Close()
+	}
+	err := server.Shutdown(ch.feedManager.context)
 	if err != nil {
-		ch.log.Errorf("encountered error shutting down websocket server %v: %v", ch.feedManager.cfg.WebsocketPort, err)
+		ch.log.Errorf("encountered error shutting down websocket server %v: %v", server.Addr, err)
 	}
 }
 
 // newWSServer creates and returns a new websocket server managed by FeedManager
-func newWSServer(feedManager *FeedManager, getQuotaUsage func(accountID string) (*connections.QuotaResponseBody, error), enableBlockchainRPC bool, pendingTxsSourceFromNode *bool, authorize func(accountID types.AccountID, secretHash string, allowAccessToInternalGateway bool) (sdnmessage.Account, error), txFromFieldIncludable bool) *http.Server {
+func newWSServer(feedManager *FeedManager, wsConnections *wsConnections, addr string, getQuotaUsage func(accountID string) (*connections.QuotaResponseBody, error), enableBlockchainRPC bool, pendingTxsSourceFromNode *bool, authorize func(accountID types.AccountID, secretHash string, allowAccessToInternalGateway bool) (sdnmessage.Account, error), txFromFieldIncludable bool) *http.Server {
 	handler := http.NewServeMux()
 	wsHandler := func(responseWriter http.ResponseWriter, request *http.Request) {
 		// if enable client handler - skip authorization
@@ -147,7 +223,7 @@ func newWSServer(feedManager *FeedManager, getQuotaUsage func(accountID string)
 			authHeader := request.Header.Get("Authorization")
 			switch {
 			case authHeader != "":
-				accountID, secretHash, err = utils.GetAccountIDSecretHashFromHeader(authHeader)
+				accountID, secretHash, err = feedManager.accountFromAuthHeader(authHeader)
 				if err != nil {
 					log.Errorf("remoteAddr: %v requestURI: %v - %v.", request.RemoteAddr, request.RequestURI, err.Error())
 					errorWithDelay(responseWriter, request, "failed parsing the authorization header")
@@ -161,15 +237,20 @@ func newWSServer(feedManager *FeedManager, getQuotaUsage func(accountID string)
 						return
 					}
 				}
+			case feedManager.cfg.LocalMode:
+				// clients of a local mode gateway do not need credentials
+				accountID = serverAccountID
 			default:
 				errorWithDelay(responseWriter, request, fmt.Errorf("missing authorization from method: %v", request.Method).Error())
 				return
 			}
 			connectionAccountModel, err = authorize(accountID, secretHash, true)
 			if err != nil {
+				feedManager.securityEvents.AuthFailed(accountID, request.RemoteAddr)
 				errorWithDelay(responseWriter, request, err.Error())
 				return
 			}
+			feedManager.securityEvents.Connected(accountID, request.RemoteAddr)
 		} else {
 			connectionAccountModel, err = feedManager.getCustomerAccountModel(serverAccountID)
 			if err != nil {
@@ -177,28 +258,45 @@ func newWSServer(feedManager *FeedManager, getQuotaUsage func(accountID string)
 					serverAccountID, request.RemoteAddr, err)
 			}
 		}
-		handleWSClientConnection(feedManager, responseWriter, request, connectionAccountModel, getQuotaUsage, enableBlockchainRPC, pendingTxsSourceFromNode, txFromFieldIncludable)
+		handleWSClientConnection(feedManager, wsConnections, responseWriter, request, connectionAccountModel, getQuotaUsage, enableBlockchainRPC, pendingTxsSourceFromNode, txFromFieldIncludable)
 	}
 
 	handler.HandleFunc("/ws", wsHandler)
 	handler.HandleFunc("/", wsHandler)
 
 	server := http.Server{
-		Handler: handler,
-	}
-	if feedManager.cfg.WebsocketHost == localhost {
-		server.Addr = fmt.Sprintf(":%v", feedManager.cfg.WebsocketPort)
-	} else {
-		server.Addr = fmt.Sprintf("%v:%v", feedManager.cfg.WebsocketHost, feedManager.cfg.WebsocketPort)
+		Addr:    addr,
+		Handler: feedManager.forwardedFor(handler),
 	}
 	return &server
 }
 
+// accountFromAuthHeader returns the account of the Authorization header, which holds either a JWT bearer token or the
+// base64 encoded accountID:secretHash. The JWT clients have no secret hash.
+func (f *FeedManager) accountFromAuthHeader(authHeader string) (types.AccountID, string, error) {
+	if f.jwtVerifier.Enabled() && strings.HasPrefix(authHeader, services.BearerPrefix) {
+		accountID, err := f.jwtVerifier.Verify(strings.TrimPrefix(authHeader, services.BearerPrefix))
+		return accountID, "", err
+	}
+	return utils.GetAccountIDSecretHashFromHeader(authHeader)
+}
+
 // handleWsClientConnection - when new http connection is made we get here upgrade to ws, and start handling
-func handleWSClientConnection(feedManager *FeedManager, w http.ResponseWriter, r *http.Request, accountModel sdnmessage.Account, getQuotaUsage func(accountID string) (*connections.QuotaResponseBody, error), enableBlockchainRPC bool, pendingTxsSourceFromNode *bool, txFromFieldIncludable bool) {
+func handleWSClientConnection(feedManager *FeedManager, wsConnections *wsConnections, w http.ResponseWriter, r *http.Request, accountModel sdnmessage.Account, getQuotaUsage func(accountID string) (*connections.QuotaResponseBody, error), enableBlockchainRPC bool, pendingTxsSourceFromNode *bool, txFromFieldIncludable bool) {
 	log.Debugf("new web-socket connection from %v", r.RemoteAddr)
+	release, err := feedManager.AcquireConnection(accountModel, types.WebSocketFeed)
+	if err != nil {
+		var limitErr *ConnectionLimitError
+		if errors.As(err, &limitErr) {
+			rejectWithDelay(w, r, websocket.CloseTryAgainLater, limitErr.closeReason())
+			return
+		}
+		errorWithDelay(w, r, err.Error())
+		return
+	}
 	connection, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
+		release()
 		log.Errorf("error upgrading HTTP server connection to the WebSocket protocol - %v", err.Error())
 		http.Error(w, "error upgrading HTTP server connection to the WebSocket protocol", http.StatusUpgradeRequired)
 		time.Sleep(ErrWSConnDelay)
@@ -222,13 +320,23 @@ func handleWSClientConnection(feedManager *FeedManager, w http.ResponseWriter, r
 		headers:                  types.SDKMetaFromHeaders(r.Header),
 		stats:                    feedManager.stats,
 		txFromFieldIncludable:    txFromFieldIncludable,
+		feedDedup:                newFeedDedup(utils.RealClock{}),
 	}
 
 	asyncHandler := jsonrpc2.AsyncHandler(handler)
-	_ = jsonrpc2.NewConn(r.Context(), websocketjsonrpc2.NewObjectStream(connection), asyncHandler)
+	conn := jsonrpc2.NewConn(r.Context(), newBatchObjectStream(websocketjsonrpc2.NewObjectStream(connection)), asyncHandler)
+	wsConnections.add(conn)
+	go func() {
+		<-conn.DisconnectNotify()
+		release()
+	}()
 }
 
 func errorWithDelay(w http.ResponseWriter, r *http.Request, msg string) {
+	rejectWithDelay(w, r, websocket.ClosePolicyViolation, msg)
+}
+
+func rejectWithDelay(w http.ResponseWriter, r *http.Request, closeCode int, msg string) {
 	// sleep for 10 seconds to prevent the client (bot) to reissue the same requests in a loop
 	c, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -236,7 +344,7 @@ func errorWithDelay(w http.ResponseWriter, r *http.Request, msg string) {
 		time.Sleep(ErrWSConnDelay)
 		return
 	}
-	_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, msg))
+	_ = c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(closeCode, msg))
 	time.Sleep(ErrWSConnDelay)
 	c.Close()
 }
@@ -255,28 +363,12 @@ func SendErrorMsg(ctx context.Context, code jsonrpc.RPCErrorCode, data string, c
 	}
 }
 
-func filterAndInclude(clientReq *clientReq, tx *types.NewTransactionNotification, remoteAddress string, accountID types.AccountID) *TxResult {
-	if clientReq.expr != nil {
-		filters := clientReq.expr.Args()
-		txFilters := tx.Filters(filters)
-
-		// should be doone after tx.Filters() to avoid nil pointer dereference
-		txType := tx.BlockchainTransaction.(*types.EthTransaction).Type()
-
-		if !isFiltersSupportedByTxType(txType, filters) {
-			log.Tracef("skipping [%s] transaction evaluation for feed, configured unsupported filter %s for tx type: %d. feed: %v remote address: %v. account id: %v",
-				tx.GetHash(), clientReq.expr, txType, clientReq.feed, remoteAddress, accountID)
-			return nil
-		}
-
-		// Evaluate if we should send the tx
-		shouldSend, err := conditions.Evaluate(clientReq.expr, txFilters)
-		if err != nil {
-			log.Errorf("error evaluate Filters. feed: %v. filters: %s. remote address: %v. account id: %v error - %v tx: %v",
-				clientReq.feed, clientReq.expr, remoteAddress, accountID, err.Error(), txFilters)
-			return nil
-		}
-		if !shouldSend {
+func filterAndInclude(clientReq *clientReq, tx *types.NewTransactionNotification, remoteAddress string, accountID types.AccountID, clockSkew time.Duration) *TxResult {
+	if clientReq.expr != nil || clientReq.wasmFilter != nil {
+		start := time.Now()
+		match := matchFilters(clientReq, tx, remoteAddress, accountID)
+		clientReq.delivery.filtered(time.Since(start))
+		if !match {
 			return nil
 		}
 	}
@@ -291,12 +383,18 @@ func filterAndInclude(clientReq *clientReq, tx *types.NewTransactionNotification
 		case "time":
 			timeNow := time.Now().Format(bxgateway.MicroSecTimeFormat)
 			response.Time = &timeNow
+			if clockSkew != 0 {
+				skewMs := float64(clockSkew) / float64(time.Millisecond)
+				response.TimeSkewMs = &skewMs
+			}
 		case "local_region":
 			localRegion := tx.LocalRegion()
 			response.LocalRegion = &localRegion
 		case "raw_tx":
 			rawTx := hexutil.Encode(tx.RawTx())
 			response.RawTx = &rawTx
+		case enrichInclude:
+			response.Enrichment = enrichTx(clientReq.enrichers, tx)
 		default:
 			if strings.HasPrefix(param, "tx_contents.") {
 				hasTxContent = true
@@ -312,11 +410,84 @@ func filterAndInclude(clientReq *clientReq, tx *types.NewTransactionNotification
 		}
 		response.TxContents = fields
 	}
+	response.Origin = tx.Origin()
 	return &response
 }
 
+// matchFilters evaluates the filters and the wasm filter of the subscription on the tx
+func matchFilters(clientReq *clientReq, tx *types.NewTransactionNotification, remoteAddress string, accountID types.AccountID) bool {
+	if clientReq.expr != nil {
+		// the operations of the filters are computed from the fields they apply to
+		filters := clientReq.operations.fields(clientReq.expr.Args())
+		txFilters := tx.Filters(filters)
+
+		// should be doone after tx.Filters() to avoid nil pointer dereference
+		txType := tx.BlockchainTransaction.(*types.EthTransaction).Type()
+
+		if clientReq.calldata != nil && hasCalldataArgs(filters) {
+			// txs which do not call a method of the ABI with the arguments of the filters cannot match them
+			args, ok := clientReq.calldata.args(tx.BlockchainTransaction.(*types.EthTransaction).Data())
+			if !ok {
+				return false
+			}
+			for _, filter := range filters {
+				if !strings.HasPrefix(filter, calldataArgPrefix) {
+					continue
+				}
+				value, ok := args[filter]
+				if !ok {
+					return false
+				}
+				txFilters[filter] = value
+			}
+		}
+
+		if !isFiltersSupportedByTxType(txType, filters) {
+			log.Tracef("skipping [%s] transaction evaluation for feed, configured unsupported filter %s for tx type: %d. feed: %v remote address: %v. account id: %v",
+				tx.GetHash(), clientReq.expr, txType, clientReq.feed, remoteAddress, accountID)
+			return false
+		}
+
+		clientReq.operations.apply(txFilters)
+
+		// Evaluate if we should send the tx
+		shouldSend, err := conditions.Evaluate(clientReq.expr, txFilters)
+		if err != nil {
+			log.Errorf("error evaluate Filters. feed: %v. filters: %s. remote address: %v. account id: %v error - %v tx: %v",
+				clientReq.feed, clientReq.expr, remoteAddress, accountID, err.Error(), txFilters)
+			return false
+		}
+		if !shouldSend {
+			return false
+		}
+	}
+
+	if clientReq.wasmFilter != nil {
+		// a filter which cannot keep up drops transactions rather than filling the subscription channel
+		if clientReq.backlog != nil && clientReq.wasmFilter.Shed(clientReq.backlog()) {
+			clientReq.delivery.drop()
+			return false
+		}
+		match, err := clientReq.wasmFilter.Match(tx.Filters(availableFilters))
+		if err != nil {
+			log.Debugf("error evaluating wasm filter. feed: %v. remote address: %v. account id: %v error - %v tx: %v",
+				clientReq.feed, remoteAddress, accountID, err, tx.GetHash())
+			return false
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
 // validateTxFromExternalSource validate transaction from external source (ws / grpc), return bool indicates if tx is pending reevaluation
-func validateTxFromExternalSource(transaction string, txBytes []byte, validatorsOnly bool, gatewayChainID types.NetworkID, nextValidator bool, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, accountID types.AccountID, nodeValidationRequested bool, wsManager blockchain.WSManager, source connections.Conn, pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo, frontRunningProtection bool) (*bxmessage.Tx, bool, error) {
+func validateTxFromExternalSource(transaction string, txBytes []byte, validatorsOnly bool, gatewayChainID types.NetworkID, nextValidator bool, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, accountID types.AccountID, nodeValidationRequested bool, wsManager blockchain.WSManager, source connections.Conn, pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo, frontRunningProtection bool, denylist *services.Denylist, contractAllowlist *services.ContractAllowlist, txPolicy config.TxPolicy, now time.Time) (*bxmessage.Tx, bool, error) {
+	if err := txPolicy.Validate(validatorsOnly, nextValidator, frontRunningProtection, nodeValidationRequested); err != nil {
+		return nil, false, err
+	}
+
 	// Ethereum's transactions encoding for RPC interfaces is slightly different from the RLP encoded format, so decode + re-encode the transaction for consistency.
 	// Specifically, note `UnmarshalBinary` should be used for RPC interfaces, and rlp.DecodeBytes should be used for the wire protocol.
 	var ethTx ethtypes.Transaction
@@ -336,6 +507,14 @@ func validateTxFromExternalSource(transaction string, txBytes []byte, validators
 		return nil, false, fmt.Errorf("chainID mismatch for hash %v, expect %v got %v, make sure the tx is sent with the right blockchain network", ethTx.Hash().String(), gatewayChainID, ethTx.ChainId().Int64())
 	}
 
+	if err = denylist.Validate(&ethTx); err != nil {
+		return nil, false, err
+	}
+
+	if err = contractAllowlist.Validate(accountID, &ethTx); err != nil {
+		return nil, false, err
+	}
+
 	txContent, err := rlp.EncodeToBytes(&ethTx)
 
 	if err != nil {
@@ -347,7 +526,7 @@ func validateTxFromExternalSource(transaction string, txBytes []byte, validators
 		txFlags |= types.TFValidatorsOnly
 	} else if nextValidator {
 		txFlags |= types.TFNextValidator
-	} else {
+	} else if txPolicy.SendsToNode() {
 		txFlags |= types.TFDeliverToNode
 	}
 
@@ -361,7 +540,7 @@ func validateTxFromExternalSource(transaction string, txBytes []byte, validators
 	// should set the account of the sender, not the account of the gateway itself
 	tx := bxmessage.NewTx(hash, txContent, networkNum, txFlags, accountID)
 	if nextValidator {
-		txPendingReevaluation, err := ProcessNextValidatorTx(tx, fallback, nextValidatorMap, validatorStatusMap, networkNum, source, pendingBSCNextValidatorTxHashToInfo)
+		txPendingReevaluation, err := ProcessNextValidatorTx(tx, fallback, nextValidatorMap, validatorStatusMap, networkNum, source, pendingBSCNextValidatorTxHashToInfo, now)
 		if err != nil {
 			return nil, false, err
 		}
@@ -393,69 +572,10 @@ func validateTxFromExternalSource(transaction string, txBytes []byte, validators
 	return tx, false, nil
 }
 
-// ProcessNextValidatorTx - sets next validator wallets if accessible and returns bool indicating if tx is pending reevaluation due to inaccessible first validator for BSC
-func ProcessNextValidatorTx(tx *bxmessage.Tx, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, source connections.Conn, pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo) (bool, error) {
-	if networkNum != bxgateway.BSCMainnetNum && networkNum != bxgateway.PolygonMainnetNum {
-		return false, errors.New("currently next_validator is only supported on BSC and Polygon networks, please contact bloXroute support")
-	}
-
-	if nextValidatorMap == nil {
-		log.Errorf("failed to process next validator tx, because next validator map is nil, tx %v", tx.Hash().String())
-		return false, errors.New("failed to send next validator tx, please contact bloXroute support")
-	}
-
-	tx.SetFallback(fallback)
-
-	// take the latest two blocks from the ordered map for updating txMsg walletID
-	n2Validator := nextValidatorMap.Newest()
-	if n2Validator == nil {
-		return false, errors.New("can't send tx with next_validator because the gateway encountered an issue fetching the epoch block, please try again later or contact bloXroute support")
-	}
-
-	if networkNum == bxgateway.BSCMainnetNum {
-		n1Validator := n2Validator.Prev()
-		n1ValidatorAccessible := false
-		n1Wallet := ""
-		if n1Validator != nil {
-			n1Wallet = n1Validator.Value.(string)
-			accessible, exist := validatorStatusMap.Load(n1Wallet)
-			if exist {
-				n1ValidatorAccessible = accessible
-			}
-		}
-
-		if n1ValidatorAccessible {
-			tx.SetWalletID(0, n1Wallet)
-		} else {
-			blockIntervalBSC := bxgateway.NetworkToBlockDuration[bxgateway.BSCMainnet]
-			if fallback != 0 && fallback < uint16(blockIntervalBSC.Milliseconds()) {
-				return false, nil
-			}
-			pendingBSCNextValidatorTxHashToInfo[tx.Hash().String()] = PendingNextValidatorTxInfo{
-				Tx:            tx,
-				Fallback:      fallback,
-				TimeOfRequest: time.Now(),
-				Source:        source,
-			}
-			return true, nil
-		}
-	}
-
-	if networkNum == bxgateway.PolygonMainnetNum || networkNum == bxgateway.PolygonMumbaiNum {
-		n1Validator := n2Validator.Prev()
-		if n1Validator != nil {
-			tx.SetWalletID(0, n1Validator.Value.(string))
-			tx.SetWalletID(1, n2Validator.Value.(string))
-		} else {
-			tx.SetWalletID(0, n2Validator.Value.(string))
-		}
-	}
-
-	return false, nil
-}
-
 // HandleMEVBundle handles the submission of a bundle and returns its hash, an error and the equivalent error code that we need to send in the response
 func HandleMEVBundle(feedManager *FeedManager, conn connections.Conn, connectionAccount sdnmessage.Account, params *jsonrpc.RPCBundleSubmissionPayload) (*GatewayBundleResponse, int, error) {
+	feedManager.mirrorSubmission(conn, string(jsonrpc.RPCBundleSubmission), params)
+
 	// If MEVBuilders request parameter is empty, only send to default builders.
 	if len(params.MEVBuilders) == 0 {
 		params.MEVBuilders = map[string]string{
@@ -488,11 +608,49 @@ func HandleMEVBundle(feedManager *FeedManager, conn connections.Conn, connection
 		return nil, jsonrpc2.CodeInvalidRequest, fmt.Errorf("txs limit exceeded, max txs allowed: %v", maxTxsLen)
 	}
 
-	if err := feedManager.node.HandleMsg(mevBundle, conn, connections.RunForeground); err != nil {
-		// err here is not possible right now, but anyway we don't want expose reason of internal error to the client
+	if err := feedManager.sendMEVBundle(mevBundle, conn); err != nil {
+		if errors.Is(err, errBundleUUIDInUse) {
+			return nil, jsonrpc2.CodeInvalidRequest, err
+		}
+		// we don't want expose reason of internal error to the client
 		log.Errorf("failed to process %s: %v", mevBundle, err)
 		return nil, jsonrpc2.CodeInternalError, err
 	}
 
 	return result, 0, nil
 }
+
+// HandleMEVBundleCancellation handles the cancellation of the bundles of a UUID, returns an error and the equivalent error code that we need to send in the response
+func HandleMEVBundleCancellation(feedManager *FeedManager, conn connections.Conn, connectionAccount sdnmessage.Account, params *jsonrpc.RPCBundleCancellationPayload) (*GatewayBundleCancelResponse, int, error) {
+	feedManager.mirrorSubmission(conn, string(jsonrpc.RPCBundleCancellation), params)
+
+	// If MEVBuilders request parameter is empty, only send to default builders.
+	if len(params.MEVBuilders) == 0 {
+		params.MEVBuilders = map[string]string{
+			bxgateway.BloxrouteBuilderName: "",
+			bxgateway.FlashbotsBuilderName: "",
+		}
+	}
+
+	mevBundle, err := bxmessage.NewMEVBundleCancellation(params.UUID, params.BlockNumber, params.MEVBuilders)
+	if err != nil {
+		return nil, jsonrpc2.CodeInvalidParams, err
+	}
+	mevBundle.SetHash()
+	mevBundle.SetNetworkNum(feedManager.networkNum)
+
+	if !connectionAccount.TierName.IsElite() {
+		log.Tracef("%s rejected for non EnterpriseElite account %v tier %v", mevBundle, connectionAccount.AccountID, connectionAccount.TierName)
+		return nil, jsonrpc2.CodeInvalidRequest, errors.New("enterprise account is required in order to cancel bundle")
+	}
+
+	if err = feedManager.sendMEVBundle(&mevBundle, conn); err != nil {
+		if errors.Is(err, errBundleUUIDInUse) {
+			return nil, jsonrpc2.CodeInvalidRequest, err
+		}
+		log.Errorf("failed to process cancellation of bundle uuid %v: %v", params.UUID, err)
+		return nil, jsonrpc2.CodeInternalError, err
+	}
+
+	return &GatewayBundleCancelResponse{UUID: params.UUID}, 0, nil
+}
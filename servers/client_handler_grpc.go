@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bloXroute-Labs/gateway/v2/config"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
@@ -200,7 +201,7 @@ func (g *GrpcHandler) PendingTxs(req *pb.TxsRequest, stream pb.Gateway_PendingTx
 	return g.handleTransactions(req, stream, types.PendingTxsFeed, account)
 }
 
-func processTx(clientReq *clientReq, notification types.Notification, multiTxsResponse *[]*pb.Tx, remoteAddress string, accountID types.AccountID, feedType types.FeedType, txFromFieldIncludable bool) {
+func processTx(clientReq *clientReq, notification types.Notification, multiTxsResponse *[]*pb.Tx, remoteAddress string, accountID types.AccountID, feedType types.FeedType, txFromFieldIncludable bool, clockSkew time.Duration) {
 	var transaction *types.NewTransactionNotification
 	switch feedType {
 	case types.NewTxsFeed:
@@ -210,13 +211,17 @@ func processTx(clientReq *clientReq, notification types.Notification, multiTxsRe
 		transaction = &tx.NewTransactionNotification
 	}
 
-	txResult := filterAndInclude(clientReq, transaction, remoteAddress, accountID)
+	txResult := filterAndInclude(clientReq, transaction, remoteAddress, accountID, clockSkew)
 	if txResult != nil {
 		*multiTxsResponse = append(*multiTxsResponse, makeTransaction(*transaction, txFromFieldIncludable))
 	}
 }
 
 func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_NewTxsServer, feedType types.FeedType, account sdnmessage.Account) error {
+	if err := g.feedManager.AllowCalls(account, config.RateLimitSubscribe, 1); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	var expr conditions.Expr
 	if req.GetFilters() != "" {
 		var err error
@@ -225,6 +230,10 @@ func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_N
 			return status.Error(codes.InvalidArgument, err.Error())
 		}
 	}
+	operations, err := newFilterOperations(expr, g.feedManager.filterFiles)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("error creating Filters: %v", err))
+	}
 
 	includes, err := validateIncludeParam(feedType, req.GetIncludes(), g.txFromFieldIncludable)
 	if err != nil {
@@ -242,28 +251,39 @@ func (g *GrpcHandler) handleTransactions(req *pb.TxsRequest, stream pb.Gateway_N
 		Filters: req.GetFilters(),
 	}
 
-	sub, err := g.feedManager.Subscribe(feedType, types.GRPCFeed, nil, ci, ro, false)
+	sub, err := g.feedManager.SubscribeClient(feedType, types.GRPCFeed, nil, ci, ro, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, fmt.Sprintf("failed to subscribe to gRPC %v feed", feedType))
 	}
 	defer g.feedManager.Unsubscribe(sub.SubscriptionID, false, "")
 
-	clReq := &clientReq{includes: includes, expr: expr, feed: feedType}
+	clReq := &clientReq{includes: includes, expr: expr, feed: feedType, operations: operations}
 
 	var txsResponse []*pb.Tx
-	for notification := range sub.FeedChan {
-		processTx(clReq, notification, &txsResponse, ci.RemoteAddress, account.AccountID, feedType, g.txFromFieldIncludable)
+	for {
+		var notification types.Notification
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case n, ok := <-sub.FeedChan:
+			if !ok {
+				return nil
+			}
+			notification = n
+		}
+		processTx(clReq, notification, &txsResponse, ci.RemoteAddress, account.AccountID, feedType, g.txFromFieldIncludable, g.feedManager.clockSkew())
 
 		if (len(sub.FeedChan) == 0 || len(txsResponse) == maxTxsInSingleResponse) && len(txsResponse) > 0 {
-			err = stream.Send(&pb.TxsReply{Tx: txsResponse})
+			reply := &pb.TxsReply{Tx: txsResponse}
+			err = stream.Send(reply)
 			if err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
+			g.feedManager.exporter.Export(account.AccountID, feedType, reply)
 
 			txsResponse = txsResponse[:0]
 		}
 	}
-	return nil
 }
 
 // NewBlocks handler for stream of new blocks
@@ -278,6 +298,10 @@ func (g *GrpcHandler) BdnBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnBloc
 
 // EthOnBlock handler for stream of changes in the EVM state when a new block is mined
 func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_EthOnBlockServer, account sdnmessage.Account) error {
+	if err := g.feedManager.AllowCalls(account, config.RateLimitSubscribe, 1); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -285,7 +309,7 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(types.OnBlockFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	sub, err := g.feedManager.SubscribeClient(types.OnBlockFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, "failed to subscribe to gRPC ethOnBlock")
 	}
@@ -309,21 +333,39 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 			return status.Error(codes.InvalidArgument, err.Error())
 		}
 	}
+	if err = g.feedManager.AllowCalls(account, config.RateLimitOnBlockCall, len(calls)); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
 
+	// the onBlock calls stop as soon as the client disconnects
+	ctx := stream.Context()
 	for {
-		notification, ok := <-sub.FeedChan
-		if !ok {
-			return status.Error(codes.Internal, "error when reading new block from gRPC ethOnBlock")
+		var notification types.Notification
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case n, ok := <-sub.FeedChan:
+			if !ok {
+				return status.Error(codes.Internal, "error when reading new block from gRPC ethOnBlock")
+			}
+			notification = n
 		}
 
 		block := notification.(*types.EthBlockNotification)
 		sendEthOnBlockGrpcNotification := func(notification *types.OnBlockNotification) error {
 			ethOnBlockNotificationReply := notification.WithFields(includes).(*types.OnBlockNotification)
 			grpcEthOnBlockNotificationReply := generateEthOnBlockReply(ethOnBlockNotificationReply)
-			return stream.Send(grpcEthOnBlockNotificationReply)
+			if err := stream.Send(grpcEthOnBlockNotificationReply); err != nil {
+				return err
+			}
+			g.feedManager.exporter.Export(account.AccountID, types.OnBlockFeed, grpcEthOnBlockNotificationReply)
+			return nil
 		}
 
-		err = handleEthOnBlock(g.feedManager, block, calls, sendEthOnBlockGrpcNotification)
+		err = handleEthOnBlock(ctx, g.feedManager, block, calls, sendEthOnBlockGrpcNotification)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
 		if err != nil {
 			return status.Error(codes.Internal, err.Error())
 		}
@@ -332,6 +374,10 @@ func (g *GrpcHandler) EthOnBlock(req *pb.EthOnBlockRequest, stream pb.Gateway_Et
 
 // TxReceipts handler for stream of all transaction receipts in each newly mined block
 func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_TxReceiptsServer, account sdnmessage.Account) error {
+	if err := g.feedManager.AllowCalls(account, config.RateLimitSubscribe, 1); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -339,7 +385,7 @@ func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_Tx
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(types.TxReceiptsFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	sub, err := g.feedManager.SubscribeClient(types.TxReceiptsFeed, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, "failed to subscribe to gRPC txReceipts")
 	}
@@ -352,20 +398,33 @@ func (g *GrpcHandler) TxReceipts(req *pb.TxReceiptsRequest, stream pb.Gateway_Tx
 		includes = req.GetIncludes()
 	}
 
-	for notification := range sub.FeedChan {
+	for {
+		var notification types.Notification
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case n, ok := <-sub.FeedChan:
+			if !ok {
+				return status.Error(codes.Internal, "error when reading new block from gRPC txReceipts")
+			}
+			notification = n
+		}
 		txReceiptsNotificationReply := notification.WithFields(includes).(*types.TxReceiptsNotification)
 		for _, receipt := range txReceiptsNotificationReply.Receipts {
 			grpcTxReceiptsNotificationReply := generateTxReceiptReply(receipt)
 			if err := stream.Send(grpcTxReceiptsNotificationReply); err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
+			g.feedManager.exporter.Export(account.AccountID, types.TxReceiptsFeed, grpcTxReceiptsNotificationReply)
 		}
 	}
-
-	return status.Error(codes.Internal, "error when reading new block from gRPC txReceipts")
 }
 
 func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnBlocksServer, feedType types.FeedType, account sdnmessage.Account) error {
+	if err := g.feedManager.AllowCalls(account, config.RateLimitSubscribe, 1); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	ci := types.ClientInfo{
 		AccountID:     account.AccountID,
 		Tier:          string(account.TierName),
@@ -373,7 +432,7 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 		RemoteAddress: GetPeerAddr(stream.Context()),
 	}
 
-	sub, err := g.feedManager.Subscribe(feedType, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
+	sub, err := g.feedManager.SubscribeClient(feedType, types.GRPCFeed, nil, ci, types.ReqOptions{}, false)
 	if err != nil {
 		return status.Error(codes.InvalidArgument, fmt.Sprintf("failed to subscribe to gRPC %v feed", feedType))
 	}
@@ -388,6 +447,8 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 
 	for {
 		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
 		case notification, ok := <-sub.FeedChan:
 			if !ok {
 				return status.Error(codes.Internal, "error when reading new notification for gRPC bdnBlocks")
@@ -401,6 +462,7 @@ func (g *GrpcHandler) handleBlocks(req *pb.BlocksRequest, stream pb.Gateway_BdnB
 			if err != nil {
 				return status.Error(codes.Internal, err.Error())
 			}
+			g.feedManager.exporter.Export(account.AccountID, feedType, blocksReply)
 		}
 	}
 }
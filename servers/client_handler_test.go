@@ -18,6 +18,7 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/blockchain"
 	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth"
 	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth/test"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/config"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
@@ -26,9 +27,11 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
 	"github.com/bloXroute-Labs/gateway/v2/test/fixtures"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/version"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -144,7 +147,7 @@ func TestClientHandler(t *testing.T) {
 	fm := NewFeedManager(context.Background(), g, feedChan, services.NewNoOpSubscriptionServices(),
 		types.NetworkNum(1), 1, types.NodeID("nodeID"),
 		eth.NewEthWSManager(blockchainPeersInfo, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false),
-		gwAccount, getMockCustomerAccountModel, "", "", cfg, stats, nil, nil)
+		gwAccount, getMockCustomerAccountModel, "", "", cfg, stats, nil, nil, FeedManagerOptions{})
 	providers := fm.nodeWSManager.Providers()
 	p1 := providers[blockchainPeers[0].IPPort()]
 	assert.NotNil(t, p1)
@@ -170,7 +173,7 @@ func TestClientHandler(t *testing.T) {
 	BscWsURLs := fmt.Sprintf("ws://%s/ws", urlBSC)
 	blockchainPeersBSC, blockchainPeersInfoBSC := test.GenerateBlockchainPeersInfo(1)
 
-	fmBSC := NewFeedManager(context.Background(), g, feedChan, services.NewNoOpSubscriptionServices(), types.NetworkNum(1), 56, types.NodeID("nodeID"), eth.NewEthWSManager(blockchainPeersInfoBSC, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false), gwAccount, getMockCustomerAccountModel, "", "", cfgBSC, stats, nil, nil)
+	fmBSC := NewFeedManager(context.Background(), g, feedChan, services.NewNoOpSubscriptionServices(), types.NetworkNum(1), 56, types.NodeID("nodeID"), eth.NewEthWSManager(blockchainPeersInfoBSC, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false), gwAccount, getMockCustomerAccountModel, "", "", cfgBSC, stats, nil, nil, FeedManagerOptions{})
 	p4 := providers[blockchainPeersBSC[0].IPPort()]
 	assert.NotNil(t, p4)
 	clientHandlerBSC := NewClientHandler(fmBSC, nil, NewHTTPServer(fmBSC, cfg.HTTPPort+1), false, getMockQuotaUsage, log.WithFields(log.Fields{
@@ -226,6 +229,8 @@ func TestClientHandler(t *testing.T) {
 
 		t.Run(fmt.Sprintf("wsClient-%s", wsURL), func(t *testing.T) {
 			handlePingRequest(t, ws)
+			handleConfigRequest(t, fm, ws)
+			handleVersionRequest(t, ws)
 			handleBlxrTxEnsureNodeValidation(t, fm, ws)
 			handleBlxrTxRequestLegacyTx(t, ws)
 			handleBlxrTxsRequestLegacyTx(t, ws)
@@ -234,17 +239,22 @@ func TestClientHandler(t *testing.T) {
 			handleBlxrTxRequestTxWithPrefix(t, ws)
 			handleBlxrTxRequestWithNextValidator(t, ws)
 			handleBlxrTxRequestRLPTx(t, ws)
+			handleBlxrPrivateTx(t, ws)
 			handleBlxrTxWithWrongChainID(t, ws)
 			handleNonBloxrouteRPCMethods(t, fm, ws, blockchainPeers)
 			handleNonBloxrouteSendTxMethod(t, fm, ws, blockchainPeers)
 			handleSubscribe(t, fm, ws)
+			handleCombinedSubscribe(t, fm, ws)
+			handleWasmFilterSubscribe(t, fm, ws)
+			handleTransformSubscribe(t, fm, ws)
+			handleMultiTxsBatchSubscribe(t, fm, ws)
 			handleEthSubscribe(t, fm, ws, blockchainPeers)
 			handleTxReceiptsSubscribe(t, fm, ws)
 			handleInvalidSubscribe(t, ws)
 			testWSShutdown(t, fm, ws, blockchainPeers)
 		})
 		// restart bc last test shut down ws server
-		fm = NewFeedManager(context.Background(), g, make(chan types.Notification), services.NewNoOpSubscriptionServices(), types.NetworkNum(1), 1, types.NodeID("nodeID"), eth.NewEthWSManager(blockchainPeersInfo, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false), gwAccount, getMockCustomerAccountModel, "", "", cfg, stats, nil, nil)
+		fm = NewFeedManager(context.Background(), g, make(chan types.Notification), services.NewNoOpSubscriptionServices(), types.NetworkNum(1), 1, types.NodeID("nodeID"), eth.NewEthWSManager(blockchainPeersInfo, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false), gwAccount, getMockCustomerAccountModel, "", "", cfg, stats, nil, nil, FeedManagerOptions{})
 		clientHandler = NewClientHandler(fm, nil, NewHTTPServer(fm, cfg.HTTPPort), true, getMockQuotaUsage, log.WithFields(log.Fields{
 			"component": "gatewayClientHandler",
 		}), &sourceFromNode, mockAuthorize, true)
@@ -405,6 +415,96 @@ func handleSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
 	handlePingRequest(t, ws)
 }
 
+func handleCombinedSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	subscriptions := fm.GetNumberOfSubscriptionsForAccount("gw")
+	unsubscribeFilter, subscriptionID := assertSubscribe(t, ws, fm, `{"id": "1", "method": "subscribe", "params": ["combined", {"Feeds": {"newTxs": {"include": ["tx_hash"]}, "txReceipts": {"include": []}}}]}`)
+	assert.Equal(t, subscriptions+2, fm.GetNumberOfSubscriptionsForAccount("gw"))
+
+	// unsubscribing the combined subscription removes the subscriptions of all its feeds
+	writeMsgToWsAndReadResponse(t, ws, []byte(unsubscribeFilter), nil)
+	time.Sleep(time.Millisecond)
+	assert.False(t, fm.SubscriptionExists(subscriptionID))
+	assert.Eventually(t, func() bool {
+		return fm.GetNumberOfSubscriptionsForAccount("gw") == subscriptions
+	}, time.Second, time.Millisecond)
+	handlePingRequest(t, ws)
+
+	subscribeMsg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["combined", {"Feeds": {"newTxs": {"include": ["tx_hash"]}}, "BlockBarrier": true}]}`), nil)
+	clientRes := getClientResponse(t, subscribeMsg)
+	assert.NotNil(t, clientRes.Error)
+}
+
+// wasmFilterModule matches the transactions with a field map longer than 20 bytes
+const wasmFilterModule = "AGFzbQEAAAABDAJgAX8Bf2ACf38BfwMDAgABBQMBAAEHGwMGbWVtb3J5AgAFYWxsb2MAAAZmaWx0ZXIAAQoPAgUAQYAICwcAIAFBFEsL"
+
+func handleWasmFilterSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	subscribeMsg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "WasmFilter": "length"}]}`), nil)
+	clientRes := getClientResponse(t, subscribeMsg)
+	assert.NotNil(t, clientRes.Error)
+
+	msg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "blxr_wasm_filter", "params": {"action": "upload", "name": "invalid", "module": "AGFzbQ=="}}`), nil)
+	clientRes = getClientResponse(t, msg)
+	assert.NotNil(t, clientRes.Error)
+
+	msg = writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "blxr_wasm_filter", "params": {"action": "upload", "name": "length", "module": "`+wasmFilterModule+`"}}`), nil)
+	clientRes = getClientResponse(t, msg)
+	require.Nil(t, clientRes.Error)
+	var res rpcWasmFilterResponse
+	b, err := json.Marshal(clientRes.Result)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &res))
+	assert.Equal(t, []string{"length"}, res.Modules)
+
+	// only tx feeds can be filtered
+	subscribeMsg = writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["txReceipts", {"include": [], "WasmFilter": "length"}]}`), nil)
+	clientRes = getClientResponse(t, subscribeMsg)
+	assert.NotNil(t, clientRes.Error)
+
+	unsubscribeFilter, subscriptionID := assertSubscribe(t, ws, fm, `{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "WasmFilter": "length"}]}`)
+	writeMsgToWsAndReadResponse(t, ws, []byte(unsubscribeFilter), nil)
+	time.Sleep(time.Millisecond)
+	assert.False(t, fm.SubscriptionExists(subscriptionID))
+
+	msg = writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "blxr_wasm_filter", "params": {"action": "remove", "name": "length"}}`), nil)
+	clientRes = getClientResponse(t, msg)
+	require.Nil(t, clientRes.Error)
+	handlePingRequest(t, ws)
+}
+
+func handleTransformSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	subscribeMsg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "Transform": {"hash": "txHash()"}}]}`), nil)
+	clientRes := getClientResponse(t, subscribeMsg)
+	assert.NotNil(t, clientRes.Error)
+
+	subscribeMsg = writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "MultiTxs": true, "Transform": {"hash": "txHash"}}]}`), nil)
+	clientRes = getClientResponse(t, subscribeMsg)
+	assert.NotNil(t, clientRes.Error)
+
+	unsubscribeFilter, subscriptionID := assertSubscribe(t, ws, fm, `{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "Transform": {"hash": "txHash"}}]}`)
+	writeMsgToWsAndReadResponse(t, ws, []byte(unsubscribeFilter), nil)
+	time.Sleep(time.Millisecond)
+	assert.False(t, fm.SubscriptionExists(subscriptionID))
+	handlePingRequest(t, ws)
+}
+
+func handleMultiTxsBatchSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	for _, params := range []string{
+		`{"include": ["tx_hash"], "MaxBatchSize": 100}`,
+		`{"include": ["tx_hash"], "MultiTxs": true, "MaxBatchSize": 100000}`,
+		`{"include": ["tx_hash"], "MultiTxs": true, "MaxBatchDelayMs": -1}`,
+	} {
+		subscribeMsg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "subscribe", "params": ["newTxs", `+params+`]}`), nil)
+		clientRes := getClientResponse(t, subscribeMsg)
+		assert.NotNil(t, clientRes.Error, params)
+	}
+
+	unsubscribeFilter, subscriptionID := assertSubscribe(t, ws, fm, `{"id": "1", "method": "subscribe", "params": ["newTxs", {"include": ["tx_hash"], "MultiTxs": true, "MaxBatchSize": 200, "MaxBatchDelayMs": 20}]}`)
+	writeMsgToWsAndReadResponse(t, ws, []byte(unsubscribeFilter), nil)
+	time.Sleep(time.Millisecond)
+	assert.False(t, fm.SubscriptionExists(subscriptionID))
+	handlePingRequest(t, ws)
+}
+
 func handleEthSubscribe(t *testing.T, fm *FeedManager, ws *websocket.Conn, blockchainPeers []types.NodeEndpoint) {
 	wsProvider, ok := fm.nodeWSManager.Provider(&blockchainPeers[0])
 	assert.True(t, ok)
@@ -849,6 +949,46 @@ func handlePingRequest(t *testing.T, ws *websocket.Conn) {
 	assert.True(t, timeServerReceivesRequest.After(timeClientSendsRequest))
 }
 
+func handleConfigRequest(t *testing.T, fm *FeedManager, ws *websocket.Conn) {
+	fm.cfg.Settings = config.Settings{}
+	fm.cfg.Settings.Set("ws-port", config.Setting{Value: 28332, Default: "28333", Source: config.SourceFlag})
+	fm.cfg.Settings.Set("log-level", config.Setting{Value: "info", Default: "info", Source: config.SourceDefault})
+	fm.cfg.Settings.Set("grpc-password", config.Setting{Value: "password", Source: config.SourceFlag})
+
+	msg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "blxr_config"}`), nil)
+	clientRes := getClientResponse(t, msg)
+	require.Nil(t, clientRes.Error)
+
+	var res rpcConfigResponse
+	b, err := json.Marshal(clientRes.Result)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &res))
+
+	assert.Equal(t, config.SourceFlag, res.Settings["ws-port"].Source)
+	assert.Equal(t, "<redacted>", res.Settings["grpc-password"].Value)
+	assert.Equal(t, "gw", res.Settings["sdn-account-id"].Value)
+	assert.Equal(t, config.SourceSDN, res.Settings["sdn-account-id"].Source)
+
+	assert.Contains(t, res.Diff, "ws-port")
+	assert.NotContains(t, res.Diff, "log-level")
+}
+
+func handleVersionRequest(t *testing.T, ws *websocket.Conn) {
+	msg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "blxr_version"}`), nil)
+	clientRes := getClientResponse(t, msg)
+	require.Nil(t, clientRes.Error)
+
+	var res version.Info
+	b, err := json.Marshal(clientRes.Result)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &res))
+
+	assert.Equal(t, version.BuildVersion, res.Version)
+	assert.Equal(t, int64(bxmessage.CurrentProtocol), res.ProtocolVersion)
+	assert.Equal(t, int64(bxmessage.MinProtocol), res.MinProtocolVersion)
+	assert.NotNil(t, res.FeatureFlags)
+}
+
 func handleQuotaUsageRequest(t *testing.T, ws *websocket.Conn) {
 	msg := writeMsgToWsAndReadResponse(t, ws, []byte(`{"id": "1", "method": "quota_usage"}`), nil)
 	clientRes := getClientResponse(t, msg)
@@ -899,6 +1039,20 @@ func handleBlxrTxRequestRLPTx(t *testing.T, ws *websocket.Conn) {
 	assert.Nil(t, clientRes.Error)
 }
 
+func handleBlxrPrivateTx(t *testing.T, ws *websocket.Conn) {
+	reqPayload := fmt.Sprintf(`{"id": "1", "method": "blxr_private_tx", "params": {"transaction": "%s", "timeout": 30}}`, fixtures.LegacyTransaction)
+	msg := writeMsgToWsAndReadResponse(t, ws, []byte(reqPayload), nil)
+	clientRes := getClientResponse(t, msg)
+	res := parseBlxrTxResult(t, clientRes.Result)
+	assert.Equal(t, fixtures.LegacyTransactionHash[2:], res.TxHash)
+	assert.Nil(t, clientRes.Error)
+
+	reqPayload = fmt.Sprintf(`{"id": "1", "method": "blxr_private_tx", "params": {"transaction": "%s", "timeout": 86400}}`, fixtures.LegacyTransaction)
+	msg = writeMsgToWsAndReadResponse(t, ws, []byte(reqPayload), nil)
+	clientRes = getClientResponse(t, msg)
+	assert.NotNil(t, clientRes.Error)
+}
+
 func handleBlxrTxWithWrongChainID(t *testing.T, ws *websocket.Conn) {
 	reqPayload := fmt.Sprintf(`{"id": "1", "method": "blxr_tx", "params": {"transaction": "%s"}}`, fixtures.LegacyTransactionBSC)
 	msg := writeMsgToWsAndReadResponse(t, ws, []byte(reqPayload), nil)
@@ -1101,3 +1255,30 @@ func TestisFiltersSupportedByTxType(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateTxFromExternalSource_TxPolicy(t *testing.T) {
+	txBytes, err := types.DecodeHex(fixtures.LegacyTransaction)
+	require.NoError(t, err)
+	validate := func(txPolicy config.TxPolicy, validatorsOnly, nextValidator bool) (*bxmessage.Tx, error) {
+		tx, _, err := validateTxFromExternalSource(fixtures.LegacyTransaction, txBytes, validatorsOnly, fixtures.LegacyChainID, nextValidator, 0,
+			nil, nil, types.NetworkNum(5), "account", false, nil, nil, nil, false, nil, nil, txPolicy, time.Now())
+		return tx, err
+	}
+
+	tx, err := validate(config.TxPolicy{}, false, false)
+	require.NoError(t, err)
+	assert.True(t, tx.Flags().ShouldDeliverToNode())
+
+	deliverToNode := false
+	tx, err = validate(config.TxPolicy{DeliverToNode: &deliverToNode}, false, false)
+	require.NoError(t, err)
+	assert.False(t, tx.Flags().ShouldDeliverToNode())
+
+	txPolicy := config.TxPolicy{ForbiddenFlags: []config.TxFlag{config.TxFlagNextValidator, config.TxFlagValidatorsOnly}}
+	_, err = validate(txPolicy, false, true)
+	assert.EqualError(t, err, "next_validator is not allowed by the tx policy of the gateway, submit the transaction without it")
+	_, err = validate(txPolicy, true, false)
+	assert.Error(t, err)
+	_, err = validate(txPolicy, false, false)
+	assert.NoError(t, err)
+}
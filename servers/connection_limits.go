@@ -0,0 +1,136 @@
+package servers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// ConnectionLimitError is returned when a connection would exceed the concurrent connections limit of the account
+type ConnectionLimitError struct {
+	AccountID   types.AccountID `json:"-"`
+	Reason      string          `json:"error"`
+	Connections int             `json:"connections"`
+	Limit       int             `json:"limit"`
+}
+
+func newConnectionLimitError(accountID types.AccountID, connections, limit int) *ConnectionLimitError {
+	return &ConnectionLimitError{AccountID: accountID, Reason: "connection_limit", Connections: connections, Limit: limit}
+}
+
+func (e *ConnectionLimitError) Error() string {
+	return fmt.Sprintf("account %v has %v open connections, limited to %v concurrent connections",
+		e.AccountID, e.Connections, e.Limit)
+}
+
+// closeReason is the structured reason sent in the close frame of a rejected websocket connection, it fits the
+// 123 bytes a close frame allows
+func (e *ConnectionLimitError) closeReason() string {
+	reason, err := json.Marshal(e)
+	if err != nil {
+		return e.Error()
+	}
+	return string(reason)
+}
+
+// AccountConnections is the number of open websocket connections and gRPC streams of an account. Every gRPC stream
+// counts as a connection, whichever streaming method opened it and however many streams share a transport connection.
+type AccountConnections struct {
+	WebSocket   int `json:"websocket"`
+	GRPCStreams int `json:"grpc_streams"`
+	Limit       int `json:"limit,omitempty"`
+}
+
+// total returns the number of connections counted against the limit of the account
+func (c AccountConnections) total() int {
+	return c.WebSocket + c.GRPCStreams
+}
+
+func (c *AccountConnections) add(connectionType types.FeedConnectionType, delta int) {
+	switch connectionType {
+	case types.WebSocketFeed:
+		c.WebSocket += delta
+	case types.GRPCFeed:
+		c.GRPCStreams += delta
+	}
+}
+
+// accountConnections counts the open connections of each account
+type accountConnections struct {
+	lock   sync.Mutex
+	counts map[types.AccountID]*AccountConnections
+}
+
+// acquire counts a new connection of the account, failing if the limit of the account is reached. The returned
+// function releases the connection and must be called once it is closed.
+func (c *accountConnections) acquire(accountID types.AccountID, limit int, connectionType types.FeedConnectionType) (func(), error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.counts == nil {
+		c.counts = make(map[types.AccountID]*AccountConnections)
+	}
+	count, ok := c.counts[accountID]
+	if !ok {
+		count = &AccountConnections{}
+		c.counts[accountID] = count
+	}
+	count.Limit = limit
+	if limit > 0 && count.total() >= limit {
+		return nil, newConnectionLimitError(accountID, count.total(), limit)
+	}
+	count.add(connectionType, 1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.release(accountID, connectionType) })
+	}, nil
+}
+
+func (c *accountConnections) release(accountID types.AccountID, connectionType types.FeedConnectionType) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	count, ok := c.counts[accountID]
+	if !ok {
+		return
+	}
+	count.add(connectionType, -1)
+	if count.total() <= 0 {
+		delete(c.counts, accountID)
+	}
+}
+
+// snapshot returns the connections of the accounts with open connections
+func (c *accountConnections) snapshot() map[types.AccountID]AccountConnections {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	snapshot := make(map[types.AccountID]AccountConnections, len(c.counts))
+	for accountID, count := range c.counts {
+		snapshot[accountID] = *count
+	}
+	return snapshot
+}
+
+// AcquireConnection counts a new websocket connection or gRPC stream of the account against its concurrent
+// connections limit, the customer running the gateway is not limited
+func (f *FeedManager) AcquireConnection(account sdnmessage.Account, connectionType types.FeedConnectionType) (func(), error) {
+	limit := int(account.MaxConnections.MsgQuota.Limit)
+	if account.AccountID == f.accountModel.AccountID {
+		limit = 0
+	}
+	release, err := f.accountConns.acquire(account.AccountID, limit, connectionType)
+	if err != nil {
+		f.log.Debugf("rejecting %v connection: %v", connectionType, err)
+	}
+	return release, err
+}
+
+// AccountConnections returns the open connections of each account
+func (f *FeedManager) AccountConnections() map[types.AccountID]AccountConnections {
+	return f.accountConns.snapshot()
+}
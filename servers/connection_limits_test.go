@@ -0,0 +1,56 @@
+package servers
+
+import (
+	"testing"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireConnection(t *testing.T) {
+	fm := &FeedManager{
+		accountModel: sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "gw"}},
+		log:          log.WithField("component", "test"),
+	}
+	account := sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "a"}}
+	account.MaxConnections.MsgQuota.Limit = 2
+
+	releaseWS, err := fm.AcquireConnection(account, types.WebSocketFeed)
+	require.NoError(t, err)
+	releaseGRPC, err := fm.AcquireConnection(account, types.GRPCFeed)
+	require.NoError(t, err)
+
+	// websocket connections and gRPC streams share the limit
+	_, err = fm.AcquireConnection(account, types.WebSocketFeed)
+	var limitErr *ConnectionLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.Equal(t, 2, limitErr.Connections)
+	assert.Equal(t, 2, limitErr.Limit)
+	assert.Equal(t, `{"error":"connection_limit","connections":2,"limit":2}`, limitErr.closeReason())
+	assert.Equal(t, map[types.AccountID]AccountConnections{"a": {WebSocket: 1, GRPCStreams: 1, Limit: 2}}, fm.AccountConnections())
+
+	// releasing twice does not free another connection
+	releaseWS()
+	releaseWS()
+	assert.Equal(t, map[types.AccountID]AccountConnections{"a": {GRPCStreams: 1, Limit: 2}}, fm.AccountConnections())
+	releaseWS, err = fm.AcquireConnection(account, types.WebSocketFeed)
+	require.NoError(t, err)
+
+	releaseWS()
+	releaseGRPC()
+	assert.Empty(t, fm.AccountConnections())
+
+	// 0 means no limit, and the customer running the gateway is not limited
+	for _, account := range []sdnmessage.Account{
+		{AccountInfo: sdnmessage.AccountInfo{AccountID: "b"}},
+		{AccountInfo: sdnmessage.AccountInfo{AccountID: "gw"}, MaxConnections: account.MaxConnections},
+	} {
+		for i := 0; i < 3; i++ {
+			_, err = fm.AcquireConnection(account, types.WebSocketFeed)
+			assert.NoError(t, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package servers
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+)
+
+// deliveryStats counts the notifications of a subscription. The counters are updated by the feed manager and by the
+// goroutine serving the subscription, all methods do nothing on a nil receiver.
+type deliveryStats struct {
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	bytesSent atomic.Uint64
+	messages  atomic.Uint64
+	// latency is the total time in nanoseconds between reading the notifications and writing their messages
+	latency atomic.Int64
+	// received is the time the notification being served was read, only accessed by the goroutine serving it
+	received time.Time
+	// metrics are the exported metrics of the feed of the subscription
+	metrics *feedCounters
+}
+
+func newDeliveryStats(metrics *feedCounters) *deliveryStats {
+	return &deliveryStats{metrics: metrics}
+}
+
+// receive marks the time the notification being served was read
+func (d *deliveryStats) receive() {
+	if d == nil {
+		return
+	}
+	d.received = time.Now()
+}
+
+// sent records a message of the given size holding the given number of notifications written to the connection
+func (d *deliveryStats) sent(notifications int, bytes int) {
+	if d == nil {
+		return
+	}
+	d.delivered.Add(uint64(notifications))
+	d.bytesSent.Add(uint64(bytes))
+	d.messages.Add(1)
+	if !d.received.IsZero() {
+		d.latency.Add(int64(time.Since(d.received)))
+	}
+	d.metrics.addSent(notifications)
+}
+
+// drop records a notification which was not delivered because the subscription could not keep up
+func (d *deliveryStats) drop() {
+	if d == nil {
+		return
+	}
+	d.dropped.Add(1)
+	d.metrics.addDropped()
+}
+
+// writeError records a message which failed to be written to the connection
+func (d *deliveryStats) writeError() {
+	if d == nil {
+		return
+	}
+	d.metrics.addWriteError()
+}
+
+// filtered records the time spent evaluating the filters of the subscription on a notification
+func (d *deliveryStats) filtered(latency time.Duration) {
+	if d == nil {
+		return
+	}
+	d.metrics.observeFilter(latency)
+}
+
+func (d *deliveryStats) summary() statistics.SubscriptionDeliveryStats {
+	if d == nil {
+		return statistics.SubscriptionDeliveryStats{}
+	}
+	summary := statistics.SubscriptionDeliveryStats{
+		Delivered: d.delivered.Load(),
+		Dropped:   d.dropped.Load(),
+		BytesSent: d.bytesSent.Load(),
+	}
+	if messages := d.messages.Load(); messages > 0 {
+		summary.AvgLatencyMs = float64(d.latency.Load()) / float64(messages) / float64(time.Millisecond)
+	}
+	return summary
+}
@@ -0,0 +1,32 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryStats(t *testing.T) {
+	delivery := newDeliveryStats(nil)
+	assert.Equal(t, statistics.SubscriptionDeliveryStats{}, delivery.summary())
+
+	delivery.receive()
+	delivery.sent(1, 100)
+	delivery.receive()
+	delivery.sent(3, 250)
+	delivery.drop()
+
+	summary := delivery.summary()
+	assert.Equal(t, uint64(4), summary.Delivered)
+	assert.Equal(t, uint64(1), summary.Dropped)
+	assert.Equal(t, uint64(350), summary.BytesSent)
+	assert.GreaterOrEqual(t, summary.AvgLatencyMs, float64(0))
+
+	// subscriptions without stats record nothing
+	var none *deliveryStats
+	none.receive()
+	none.sent(1, 100)
+	none.drop()
+	assert.Equal(t, statistics.SubscriptionDeliveryStats{}, none.summary())
+}
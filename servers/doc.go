@@ -0,0 +1,19 @@
+// Package servers serves the feeds of the gateway to the websocket and gRPC clients.
+//
+// The FeedManager, its subscriptions and the notification pipeline can also be embedded in another Go program without
+// the websocket and gRPC servers. NewEmbeddedFeedManager creates a FeedManager fed with the notifications sent to its
+// channel, and Subscribe delivers the notifications of a feed to an in-process consumer:
+//
+//	feed := make(chan types.Notification, bxgateway.BxNotificationChannelSize)
+//	feedManager := servers.NewEmbeddedFeedManager(ctx, feed, networkNum, config.Bx{}, servers.FeedManagerOptions{})
+//	go func() { _ = feedManager.Start(ctx) }()
+//
+//	notifications, cancel, err := feedManager.Subscribe(types.NewTxsFeed, servers.SubscribeOptions{Filters: "gas_price > 1000000000"})
+//	if err != nil {
+//		return err
+//	}
+//	defer cancel()
+//	for notification := range notifications {
+//		...
+//	}
+package servers
@@ -0,0 +1,142 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/zhouzhuojie/conditions"
+)
+
+// SubscribeOptions are the options of an in-process subscription
+type SubscribeOptions struct {
+	// Filters is the filter expression of the newTxs and pendingTxs feeds, in the syntax of the websocket subscriptions
+	Filters string
+	// ChannelSize is the buffer of the returned channel, bxgateway.BxNotificationChannelSize if 0
+	ChannelSize int
+}
+
+// NewEmbeddedFeedManager creates a FeedManager serving only the in-process subscriptions of Subscribe, without the
+// websocket and gRPC servers nor a connection to the BDN. The notifications sent to feed are delivered to the
+// subscriptions once Start runs.
+func NewEmbeddedFeedManager(ctx context.Context, feed chan types.Notification, networkNum types.NetworkNum, cfg config.Bx, opts FeedManagerOptions) *FeedManager {
+	getAccountModel := func(accountID types.AccountID) (sdnmessage.Account, error) {
+		return sdnmessage.Account{}, fmt.Errorf("account %v is not available to an embedded feed manager", accountID)
+	}
+	return NewFeedManager(ctx, nil, feed, services.NewNoOpSubscriptionServices(), networkNum, 0, "", nil,
+		sdnmessage.Account{}, getAccountModel, "", "", cfg, statistics.NoStats{}, nil, nil, opts)
+}
+
+// Subscribe subscribes the program embedding the feed manager to the feed. The notifications of the feed, which
+// match the filters of the options, are delivered to the returned channel until cancel is called. The notifications
+// the program does not keep up with are dropped, the channel is closed once the subscription ends.
+func (f *FeedManager) Subscribe(feed types.FeedType, opts SubscribeOptions) (<-chan types.Notification, func(), error) {
+	if _, ok := availableFeedsMap[feed]; !ok {
+		return nil, nil, fmt.Errorf("unsupported feed %v, possible feeds are: %v", feed, availableFeeds)
+	}
+	// the onBlock calls, the logs filters and the tracked txs are declared by the websocket subscriptions
+	if feed == types.OnBlockFeed || feed == types.LogsFeed || feed == types.TransactionStatusFeed {
+		return nil, nil, fmt.Errorf("%v feed is not supported in-process", feed)
+	}
+	var expr conditions.Expr
+	var err error
+	if opts.Filters != "" {
+		if feed != types.NewTxsFeed && feed != types.PendingTxsFeed {
+			return nil, nil, errors.New("filters are only supported by the newTxs and pendingTxs feeds")
+		}
+		expr, err = validateFilters(opts.Filters, true)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating Filters: %w", err)
+		}
+	}
+	operations, err := newFilterOperations(expr, f.filterFiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating Filters: %w", err)
+	}
+	channelSize := opts.ChannelSize
+	if channelSize <= 0 {
+		channelSize = bxgateway.BxNotificationChannelSize
+	}
+
+	id := f.subscriptionServices.GenerateSubscriptionID(false)
+	delivery := newDeliveryStats(f.metrics.forFeed(feed))
+	request := &clientReq{feed: feed, expr: expr, delivery: delivery, operations: operations}
+	clientSubscription := ClientSubscription{
+		feed:               make(chan types.Notification, channelSize),
+		feedType:           feed,
+		feedConnectionType: types.InProcessFeed,
+		network:            f.networkNum,
+		timeOpenedFeed:     time.Now(),
+		errMsgChan:         make(chan string, 1),
+		handOff:            make(chan struct{}),
+		request:            request,
+		delivery:           delivery,
+		backpressure:       backpressureDropNewest,
+		ClientInfo:         types.ClientInfo{AccountID: f.accountModel.AccountID, RemoteAddress: string(types.InProcessFeed)},
+	}
+
+	f.lock.Lock()
+	f.idToClientSubscription[id] = clientSubscription
+	f.lock.Unlock()
+	f.log.Infof("in-process subscription %v to %v with filter [%v]", id, feed, opts.Filters)
+
+	notifications := make(chan types.Notification, channelSize)
+	done := make(chan struct{})
+	var cancelOnce sync.Once
+	cancel := func() {
+		cancelOnce.Do(func() {
+			close(done)
+			_ = f.Unsubscribe(id, false, "")
+		})
+	}
+	go f.serveInProcess(request, clientSubscription.feed, notifications, done)
+	return notifications, cancel, nil
+}
+
+// serveInProcess forwards the notifications of the subscription which match its filters until it ends or is canceled
+func (f *FeedManager) serveInProcess(request *clientReq, feed <-chan types.Notification, notifications chan<- types.Notification, done <-chan struct{}) {
+	defer close(notifications)
+	defer f.recoverPanic(panicScopeSubscription, string(request.feed), nil)
+	for {
+		select {
+		case <-done:
+			return
+		case notification, ok := <-feed:
+			if !ok {
+				return
+			}
+			request.delivery.receive()
+			if !f.matchInProcess(request, notification) {
+				continue
+			}
+			select {
+			case notifications <- notification:
+				request.delivery.sent(1, 0)
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+func (f *FeedManager) matchInProcess(request *clientReq, notification types.Notification) bool {
+	if request.expr == nil {
+		return true
+	}
+	switch n := notification.(type) {
+	case *types.NewTransactionNotification:
+		return matchFilters(request, n, string(types.InProcessFeed), f.accountModel.AccountID)
+	case *types.PendingTransactionNotification:
+		return matchFilters(request, &n.NewTransactionNotification, string(types.InProcessFeed), f.accountModel.AccountID)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,53 @@
+package servers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedManager_SubscribeInProcess(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := make(chan types.Notification, 10)
+	fm := NewEmbeddedFeedManager(ctx, feed, 5, config.Bx{}, FeedManagerOptions{})
+	go func() { _ = fm.Start(ctx) }()
+
+	_, _, err := fm.Subscribe("unknown", SubscribeOptions{})
+	assert.Error(t, err)
+	_, _, err = fm.Subscribe(types.OnBlockFeed, SubscribeOptions{})
+	assert.Error(t, err)
+	_, _, err = fm.Subscribe(types.NewBlocksFeed, SubscribeOptions{Filters: "gas_price > 1"})
+	assert.Error(t, err)
+
+	notifications, unsubscribe, err := fm.Subscribe(types.NewBlocksFeed, SubscribeOptions{ChannelSize: 1})
+	require.NoError(t, err)
+	assert.True(t, fm.SubscriptionTypeExists(types.NewBlocksFeed))
+
+	block := newOrderingTestBlock(t, 10, common.Hash{})
+	block.SetNotificationType(types.NewBlocksFeed)
+	feed <- block
+	select {
+	case notification := <-notifications:
+		assert.Equal(t, block.GetHash(), notification.GetHash())
+	case <-time.After(time.Second):
+		t.Fatal("notification was not delivered")
+	}
+
+	// the channel is closed once the subscription is canceled
+	unsubscribe()
+	unsubscribe()
+	select {
+	case _, ok := <-notifications:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed")
+	}
+	assert.False(t, fm.SubscriptionTypeExists(types.NewBlocksFeed))
+}
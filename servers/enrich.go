@@ -0,0 +1,29 @@
+package servers
+
+import (
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// enrichInclude is the include param adding the annotations of the enrichers to the tx notifications
+const enrichInclude = "enrich"
+
+// TxEnricher is a stage of the fan-out pipeline annotating the tx notifications of the subscriptions which include
+// enrich, e.g. with the labels of their addresses. *hooks.Hooks runs the enrichers registered as hooks.
+type TxEnricher interface {
+	// EnrichTx returns the annotations of the tx by name, nil if there are none
+	EnrichTx(tx *types.NewTransactionNotification) map[string]interface{}
+}
+
+// enrichTx merges the annotations of the enrichers, nil is returned if none of them annotated the tx
+func enrichTx(enrichers []TxEnricher, tx *types.NewTransactionNotification) map[string]interface{} {
+	var enrichment map[string]interface{}
+	for _, enricher := range enrichers {
+		for name, annotations := range enricher.EnrichTx(tx) {
+			if enrichment == nil {
+				enrichment = make(map[string]interface{})
+			}
+			enrichment[name] = annotations
+		}
+	}
+	return enrichment
+}
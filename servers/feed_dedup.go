@@ -0,0 +1,65 @@
+package servers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+type feedDedupEntry struct {
+	expiry time.Time
+	feeds  []types.FeedType
+}
+
+// feedDedup suppresses a transaction on the tx feeds of a connection if it was already delivered on another feed
+// of the same connection within the dedup window requested by the subscription
+type feedDedup struct {
+	lock        sync.Mutex
+	clock       utils.Clock
+	seen        map[string]*feedDedupEntry
+	nextCleanup time.Time
+}
+
+func newFeedDedup(clock utils.Clock) *feedDedup {
+	return &feedDedup{
+		clock: clock,
+		seen:  make(map[string]*feedDedupEntry),
+	}
+}
+
+// check records that the hash matched the feed, and returns the feeds it matched within the window and whether it
+// should be delivered on the feed
+func (d *feedDedup) check(hash string, feed types.FeedType, window time.Duration) ([]types.FeedType, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.clock.Now()
+	if now.After(d.nextCleanup) {
+		for h, entry := range d.seen {
+			if now.After(entry.expiry) {
+				delete(d.seen, h)
+			}
+		}
+		d.nextCleanup = now.Add(window)
+	}
+
+	entry, ok := d.seen[hash]
+	if !ok || now.After(entry.expiry) {
+		d.seen[hash] = &feedDedupEntry{
+			expiry: now.Add(window),
+			feeds:  []types.FeedType{feed},
+		}
+		return []types.FeedType{feed}, true
+	}
+
+	for _, seenFeed := range entry.feeds {
+		if seenFeed == feed {
+			// another subscription on the same feed
+			return append([]types.FeedType(nil), entry.feeds...), true
+		}
+	}
+	entry.feeds = append(entry.feeds, feed)
+	return nil, false
+}
@@ -0,0 +1,46 @@
+package servers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedDedup(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	dedup := newFeedDedup(clock)
+	window := 2 * time.Second
+
+	seenOn, deliver := dedup.check("0x1", types.NewTxsFeed, window)
+	assert.True(t, deliver)
+	assert.Equal(t, []types.FeedType{types.NewTxsFeed}, seenOn)
+
+	// same hash on another feed within the window is suppressed
+	clock.IncTime(time.Second)
+	_, deliver = dedup.check("0x1", types.PendingTxsFeed, window)
+	assert.False(t, deliver)
+
+	// another subscription on the first feed still gets the tx, with all the feeds it matched
+	seenOn, deliver = dedup.check("0x1", types.NewTxsFeed, window)
+	assert.True(t, deliver)
+	assert.Equal(t, []types.FeedType{types.NewTxsFeed, types.PendingTxsFeed}, seenOn)
+
+	// the window has passed
+	clock.IncTime(2 * time.Second)
+	seenOn, deliver = dedup.check("0x1", types.PendingTxsFeed, window)
+	assert.True(t, deliver)
+	assert.Equal(t, []types.FeedType{types.PendingTxsFeed}, seenOn)
+
+	seenOn, deliver = dedup.check("0x2", types.PendingTxsFeed, window)
+	assert.True(t, deliver)
+	assert.Equal(t, []types.FeedType{types.PendingTxsFeed}, seenOn)
+
+	// expired hashes are cleaned up
+	clock.IncTime(time.Minute)
+	dedup.check("0x3", types.NewTxsFeed, window)
+	assert.Len(t, dedup.seen, 1)
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
@@ -17,15 +17,22 @@ import (
 	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
 	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
 	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/services/wasmfilter"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 const accountExpiredError = "Account expired, unsubscribe feed"
 
+// ErrGatewayShuttingDown is returned for the websocket subscriptions requested while the websocket server shuts down
+var ErrGatewayShuttingDown = errors.New("gateway is shutting down, new subscriptions are not accepted")
+
 // ClientSubscription contains client subscription feed and connection
 type ClientSubscription struct {
 	types.ClientInfo
@@ -38,6 +45,11 @@ type ClientSubscription struct {
 	timeOpenedFeed     time.Time
 	messagesSent       uint64
 	errMsgChan         chan string
+	handOff            chan struct{}
+	request            *clientReq
+	delivery           *deliveryStats
+	backpressure       backpressurePolicy
+	headerFirst        bool
 }
 
 // ClientSubscriptionHandlingInfo contains all info needed by subscription handler
@@ -46,6 +58,8 @@ type ClientSubscriptionHandlingInfo struct {
 	FeedChan           chan types.Notification
 	ErrMsgChan         chan string
 	PermissionRespChan chan *sdnmessage.SubscriptionPermissionMessage
+	HandOffChan        chan struct{}
+	delivery           *deliveryStats
 }
 
 // PendingNextValidatorTxInfo holds info needed to reevaluate next validator tx when next block published
@@ -77,12 +91,67 @@ type FeedManager struct {
 	validatorStatusMap                  *syncmap.SyncMap[string, bool]
 	pendingBSCNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo
 	pendingBSCNextValidatorTxsMapLock   sync.Mutex
+	subscriptionTransfers               map[string]*subscriptionTransfer
+	denylist                            *services.Denylist
+	contractAllowlist                   *services.ContractAllowlist
+	featureFlags                        *services.FeatureFlags
+	enrichers                           []TxEnricher
+	wasmFilters                         *wasmfilter.Store
+	filterFiles                         *services.AddressSets
+	exporter                            export.Exporter
+	feedRateAlerter                     *services.FeedRateAlerter
+	standing                            []*standingSubscription
+	mirror                              export.Mirror
+	bundleUUIDs                         *bundleUUIDs
+	receiptFetchers                     *blockchain.ReceiptFetchers
+	txStatusTracker                     *services.TxStatusTracker
+	bundleStatusTracker                 *services.BundleStatusTracker
+	txStore                             services.TxStore
+	metrics                             *feedMetrics
+	securityEvents                      *services.SecurityEventNotifier
+	jwtVerifier                         *services.JWTVerifier
+	rateLimiter                         *rpcRateLimiter
+	wsSubscriptionsStopped              atomic.Bool
+	accountConns                        accountConnections
 
 	context context.Context
 	cancel  context.CancelFunc
 	stats   statistics.Stats
 }
 
+// FeedManagerOptions are the optional services of a feedManager, a nil service is disabled
+type FeedManagerOptions struct {
+	Denylist          *services.Denylist
+	ContractAllowlist *services.ContractAllowlist
+	FeatureFlags      *services.FeatureFlags
+	// Enrichers annotate the tx notifications of the subscriptions which include enrich
+	Enrichers []TxEnricher
+	// StandingSubscriptions are served from startup without a connected client
+	StandingSubscriptions []StandingSubscription
+	// Mirror duplicates the notifications and the submissions of an account to a shadow sink
+	Mirror export.Mirror
+	// TxStatusTracker follows the txs of the transactionStatus subscriptions
+	TxStatusTracker *services.TxStatusTracker
+	// BundleStatusTracker follows the bundles of the bundleStatus subscriptions
+	BundleStatusTracker *services.BundleStatusTracker
+	// TxStore holds the time the gateway first saw the txs, which the receipts report their inclusion delay from
+	TxStore services.TxStore
+	// BridgeSaturation reports the share of the buffer of the bridge channels in use, exported on /metrics
+	BridgeSaturation func() map[string]float64
+	// BridgeChannelFull reports the number of messages dropped by the full bridge channels, exported on /metrics
+	BridgeChannelFull func() map[string]uint64
+	// BlockSources reports which of the BDN and the blockchain node delivered the blocks first, exported on /metrics
+	BlockSources func() map[string]services.BlockSourceStats
+	// SecurityEvents posts the auth failures, new IP ranges and privileged method calls of the accounts to their webhooks
+	SecurityEvents *services.SecurityEventNotifier
+	// JWTVerifier authenticates the clients sending a JWT bearer token
+	JWTVerifier *services.JWTVerifier
+	// FilterFiles are the address files the filters reference with in_file
+	FilterFiles *services.AddressSets
+	// NodeRPCTimer measures the RPC calls to the node, its latency histograms are exported on /metrics
+	NodeRPCTimer *blockchain.RPCTimer
+}
+
 // NewFeedManager - create a new feedManager
 func NewFeedManager(parent context.Context, node connections.BxListener, wsFeedChan chan types.Notification,
 	subscriptionServices services.SubscriptionServices,
@@ -90,7 +159,7 @@ func NewFeedManager(parent context.Context, node connections.BxListener, wsFeedC
 	wsManager blockchain.WSManager,
 	accountModel sdnmessage.Account, getCustomerAccountModel func(types.AccountID) (sdnmessage.Account, error),
 	certFile string, keyFile string, cfg config.Bx, stats statistics.Stats,
-	nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool]) *FeedManager {
+	nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], opts FeedManagerOptions) *FeedManager {
 	ctx, cancel := context.WithCancel(parent)
 	logger := log.WithFields(log.Fields{
 		"component": "feedManager",
@@ -117,7 +186,38 @@ func NewFeedManager(parent context.Context, node connections.BxListener, wsFeedC
 		stats:                               stats,
 		log:                                 logger,
 		pendingBSCNextValidatorTxHashToInfo: make(map[string]PendingNextValidatorTxInfo),
+		subscriptionTransfers:               make(map[string]*subscriptionTransfer),
+		denylist:                            opts.Denylist,
+		contractAllowlist:                   opts.ContractAllowlist,
+		featureFlags:                        opts.FeatureFlags,
+		enrichers:                           opts.Enrichers,
+		wasmFilters:                         wasmfilter.NewStore(),
+		filterFiles:                         opts.FilterFiles,
+		exporter:                            export.NewExporter(ctx, cfg.FeedExport, utils.RealClock{}),
+		mirror:                              opts.Mirror,
+		bundleUUIDs:                         newBundleUUIDs(utils.RealClock{}),
+		receiptFetchers:                     blockchain.NewReceiptFetchers(cfg.TxReceiptsFetch),
+		txStatusTracker:                     opts.TxStatusTracker,
+		bundleStatusTracker:                 opts.BundleStatusTracker,
+		txStore:                             opts.TxStore,
+		securityEvents:                      opts.SecurityEvents,
+		jwtVerifier:                         opts.JWTVerifier,
+		rateLimiter:                         newRPCRateLimiter(utils.RealClock{}, cfg.RPCRateLimits),
+	}
+	if opts.Mirror != nil {
+		// the notifications delivered to the mirrored account are passed to the mirror along with the archive
+		newServer.exporter = export.Tee(newServer.exporter, opts.Mirror)
 	}
+	collectors := []prometheus.Collector{
+		bridgeCollector{saturation: opts.BridgeSaturation, channelFull: opts.BridgeChannelFull},
+		blockSourceCollector{stats: opts.BlockSources},
+	}
+	if opts.NodeRPCTimer != nil {
+		collectors = append(collectors, opts.NodeRPCTimer)
+	}
+	newServer.metrics = newFeedMetrics(newServer, collectors...)
+	newServer.feedRateAlerter = services.NewFeedRateAlerter(cfg.FeedAlerts, utils.RealClock{}, newServer.nodeSynced)
+	newServer.standing = newServer.newStandingSubscriptions(opts.StandingSubscriptions)
 	return newServer
 }
 
@@ -133,7 +233,7 @@ func (f *FeedManager) checkForDuplicateFeed(clientSubscription *ClientSubscripti
 		return nil
 	}
 
-	remoteIP := strings.Split(remoteAddress, ":")[0] + ":"
+	remoteIP := utils.RemoteIP(remoteAddress)
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 	for k, v := range f.idToClientSubscription {
@@ -143,7 +243,7 @@ func (f *FeedManager) checkForDuplicateFeed(clientSubscription *ClientSubscripti
 				v.Includes == clientSubscription.Includes &&
 				v.Filters == clientSubscription.Filters &&
 				v.Project == clientSubscription.Project &&
-				strings.HasPrefix(v.RemoteAddress, remoteIP) {
+				utils.RemoteIP(v.RemoteAddress) == remoteIP {
 				return fmt.Errorf("duplicate feed request - account %v tier %v ip %v previous subscription ID %v", clientSubscription.AccountID, clientSubscription.Tier, remoteAddress, k)
 			}
 		}
@@ -151,10 +251,14 @@ func (f *FeedManager) checkForDuplicateFeed(clientSubscription *ClientSubscripti
 	return nil
 }
 
-// Subscribe - subscribe a client to a desired feed
-func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType types.FeedConnectionType,
+// SubscribeClient - subscribe a websocket or gRPC client to a desired feed
+func (f *FeedManager) SubscribeClient(feedName types.FeedType, feedConnectionType types.FeedConnectionType,
 	conn *jsonrpc2.Conn, ci types.ClientInfo, ro types.ReqOptions, ethSubscribe bool) (*ClientSubscriptionHandlingInfo, error) {
 
+	if feedConnectionType == types.WebSocketFeed && f.wsSubscriptionsStopped.Load() {
+		return nil, ErrGatewayShuttingDown
+	}
+
 	id := f.subscriptionServices.GenerateSubscriptionID(ethSubscribe)
 	clientSubscription := ClientSubscription{
 		feed:               make(chan types.Notification, bxgateway.BxNotificationChannelSize),
@@ -164,6 +268,8 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 		network:            f.networkNum,
 		timeOpenedFeed:     time.Now(),
 		errMsgChan:         make(chan string, 1),
+		handOff:            make(chan struct{}),
+		delivery:           newDeliveryStats(f.metrics.forFeed(feedName)),
 		ClientInfo:         ci,
 		ReqOptions:         ro,
 	}
@@ -175,7 +281,7 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 
 	subscriptionModel := sdnmessage.SubscriptionModel{
 		SubscriptionID: id,
-		SubscriberIP:   strings.Split(ci.RemoteAddress, ":")[0],
+		SubscriberIP:   utils.RemoteIP(ci.RemoteAddress),
 		NodeID:         string(f.nodeID),
 		AccountID:      ci.AccountID,
 		NetworkNum:     f.networkNum,
@@ -201,6 +307,8 @@ func (f *FeedManager) Subscribe(feedName types.FeedType, feedConnectionType type
 		FeedChan:           clientSubscription.feed,
 		ErrMsgChan:         clientSubscription.errMsgChan,
 		PermissionRespChan: permissionRespChannel,
+		HandOffChan:        clientSubscription.handOff,
+		delivery:           clientSubscription.delivery,
 	}
 	return &handlingInfo, nil
 }
@@ -224,7 +332,7 @@ func (f *FeedManager) Unsubscribe(subscriptionID string, closeClientConnection b
 
 	subscription := sdnmessage.SubscriptionModel{
 		SubscriptionID: subscriptionID,
-		SubscriberIP:   strings.Split(clientSub.RemoteAddress, ":")[0],
+		SubscriberIP:   utils.RemoteIP(clientSub.RemoteAddress),
 		NodeID:         string(f.nodeID),
 		AccountID:      clientSub.AccountID,
 		NetworkNum:     clientSub.network,
@@ -246,14 +354,28 @@ func (f *FeedManager) Unsubscribe(subscriptionID string, closeClientConnection b
 		)
 	}
 
+	delivery := clientSub.delivery.summary()
+	f.log.Infof("subscription %v to %v delivered %v notifications, dropped %v, average latency %.2fms, sent %v bytes",
+		subscriptionID, clientSub.feedType, delivery.Delivered, delivery.Dropped, delivery.AvgLatencyMs, delivery.BytesSent)
 	f.stats.LogUnsubscribeStats(
 		subscriptionID,
 		clientSub.feedType,
 		f.networkNum,
 		clientSub.AccountID,
-		sdnmessage.AccountTier(clientSub.Tier))
+		sdnmessage.AccountTier(clientSub.Tier),
+		delivery)
 	close(clientSub.feed)
 	delete(f.idToClientSubscription, subscriptionID)
+	if clientSub.feedType == types.TransactionStatusFeed {
+		f.txStatusTracker.Untrack(subscriptionID)
+	}
+	f.removeSubscriptionTransfer(subscriptionID)
+	if clientSub.request != nil {
+		if skipped := clientSub.request.wasmFilter.Skipped(); skipped > 0 {
+			f.log.Infof("wasm filter of subscription %v skipped %v transactions while the subscription was behind", subscriptionID, skipped)
+		}
+		clientSub.request.wasmFilter.Close()
+	}
 	if closeClientConnection && clientSub.connection != nil {
 		// TODO: need to unsubscribe all other subscriptions on this connection.
 		err := clientSub.connection.Close()
@@ -266,6 +388,18 @@ func (f *FeedManager) Unsubscribe(subscriptionID string, closeClientConnection b
 	return nil
 }
 
+// SubscriptionDeliveryStats returns the delivery statistics of the subscription so far
+func (f *FeedManager) SubscriptionDeliveryStats(subscriptionID string) (statistics.SubscriptionDeliveryStats, bool) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists {
+		return statistics.SubscriptionDeliveryStats{}, false
+	}
+	return clientSub.delivery.summary(), true
+}
+
 // CloseAllClientConnections - unsubscribes all client subscriptions and closes all client ws connections
 func (f *FeedManager) CloseAllClientConnections() {
 	// copy the map, since Unsubscribe has a lock inside
@@ -281,10 +415,34 @@ func (f *FeedManager) CloseAllClientConnections() {
 	}
 }
 
+// stopWSSubscriptions stops accepting websocket subscriptions and delivering new notifications to the existing ones,
+// until resumed
+func (f *FeedManager) stopWSSubscriptions(stop bool) {
+	f.wsSubscriptionsStopped.Store(stop)
+}
+
+// pendingWSNotifications returns the number of notifications queued for the websocket subscriptions
+func (f *FeedManager) pendingWSNotifications() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	pending := 0
+	for _, clientSub := range f.idToClientSubscription {
+		if clientSub.feedConnectionType == types.WebSocketFeed {
+			pending += len(clientSub.feed)
+		}
+	}
+	return pending
+}
+
 // run - getting feed notification and pass to client via common channel
 func (f *FeedManager) run(ctx context.Context) {
 	defer f.cancel()
 	f.log.Infof("feedManager is starting for network %v", f.networkNum)
+	go f.feedRateAlerter.Run(ctx)
+	for _, subscription := range f.standing {
+		go f.serveStanding(ctx, subscription)
+	}
 
 	// variables needed for daily account expiration check
 	firstDailyCheckTriggered := true
@@ -308,6 +466,10 @@ func (f *FeedManager) run(ctx context.Context) {
 
 			f.lock.Lock()
 			for subID, sub := range f.idToClientSubscription {
+				// the in-process subscriptions belong to the program embedding the feed manager
+				if sub.feedConnectionType == types.InProcessFeed {
+					continue
+				}
 				accountModel, err := f.getCustomerAccountModel(sub.AccountID)
 				if err != nil {
 					log.Debugf("can't get account model for %v, while account has active feed subscription (%v), feed type: %v with %v since %s", sub.AccountID, subID, sub.feedType, sub.feedConnectionType, sub.timeOpenedFeed)
@@ -339,18 +501,26 @@ func (f *FeedManager) run(ctx context.Context) {
 				f.log.Errorf("can't pull from ws feed channel. Terminating")
 				break
 			}
+			f.feedRateAlerter.Track(notification.NotificationType())
+			headerOnly := isHeaderOnly(notification)
+			if !headerOnly {
+				f.publishStanding(notification)
+			}
+			// the websocket subscriptions only flush their queued notifications while the server shuts down
+			wsStopped := f.wsSubscriptionsStopped.Load()
 			f.lock.RLock()
 			for uid, clientSub := range f.idToClientSubscription {
-				if (clientSub.feedConnectionType == types.WebSocketFeed || clientSub.feedConnectionType == types.GRPCFeed) && clientSub.feedType == notification.NotificationType() {
-					select {
-					case clientSub.feed <- notification:
-						// Offer: I took this out as we are locking the map in read and can't write.
-						// also, do we need to update the map after we update the counter?
-						// if entry, ok := f.idToClientSubscription[uid]; ok {
-						//	entry.messagesSent++
-						//	f.idToClientSubscription[uid] = entry
-						// }
-					default:
+				if (clientSub.feedConnectionType == types.WebSocketFeed || clientSub.feedConnectionType == types.GRPCFeed || clientSub.feedConnectionType == types.InProcessFeed) && clientSub.feedType == notification.NotificationType() {
+					if clientSub.feedConnectionType == types.WebSocketFeed && wsStopped {
+						continue
+					}
+					if headerOnly && !clientSub.headerFirst {
+						continue
+					}
+					if !addressedTo(notification, uid) {
+						continue
+					}
+					if !clientSub.offer(notification) {
 						f.log.Errorf("can't send %v to channel %v without blocking. Ignored hash %v and unsubscribing", clientSub.feedType, uid, notification.GetHash())
 						go func(subscriptionID string) {
 							// running as go-routine since we are holding the lock. Closing the connection since we can't write
@@ -367,6 +537,75 @@ func (f *FeedManager) run(ctx context.Context) {
 	}
 }
 
+// nodeSynced returns true if the blockchain nodes are synced, or if the gateway has no websocket connection to tell
+func (f *FeedManager) nodeSynced() bool {
+	return f.nodeWSManager == nil || len(f.nodeWSManager.Providers()) == 0 || f.nodeWSManager.Synced()
+}
+
+// clockSkew returns the offset of the gateway clock if it is beyond the skew threshold, zero otherwise
+func (f *FeedManager) clockSkew() time.Duration {
+	return f.node.ClockSkew()
+}
+
+// ExportPublished archives the notification with the default fields of its feed under the account of the gateway
+func (f *FeedManager) ExportPublished(notification types.Notification) {
+	// the header only notifications are only sent to the subscriptions requesting them, and the tx statuses to the
+	// subscriptions tracking the txs
+	if isHeaderOnly(notification) || notification.NotificationType() == types.TransactionStatusFeed {
+		return
+	}
+	feed := notification.NotificationType()
+	includes, err := validateIncludeParam(feed, nil, false)
+	if err != nil || len(includes) == 0 {
+		return
+	}
+
+	var content interface{}
+	if tx, ok := notification.(*types.NewTransactionNotification); ok {
+		result := filterAndInclude(&clientReq{feed: feed, includes: includes}, tx, "", f.accountModel.AccountID, f.clockSkew())
+		if result == nil {
+			return
+		}
+		content = result
+	} else {
+		content = notification.WithFields(includes)
+	}
+	f.exporter.Export(f.accountModel.AccountID, feed, content)
+}
+
+// mirrorSubmission mirrors the payload submitted on the connection if the traffic of its account is mirrored
+func (f *FeedManager) mirrorSubmission(conn connections.Conn, method string, payload interface{}) {
+	if f.mirror == nil {
+		return
+	}
+	remoteAddress := conn.GetPeerIP()
+	if rpcConn, ok := conn.(connections.RPCConn); ok {
+		remoteAddress = rpcConn.RemoteAddress
+	}
+	f.mirror.Submit(conn.GetAccountID(), method, remoteAddress, payload)
+}
+
+// txIncluded returns true if a synced node has the receipt of the tx, i.e. the tx was included in a block. It returns an
+// error if the inclusion of the tx is unknown, because no node is synced or the node failed to answer.
+func (f *FeedManager) txIncluded(ctx context.Context, hash types.SHA256Hash) (bool, error) {
+	if f.nodeWSManager == nil {
+		return false, errors.New("no node is connected over websockets")
+	}
+	nodeWS, ok := f.nodeWSManager.SyncedProvider()
+	if !ok {
+		return false, errors.New("no synced node")
+	}
+	receipt, err := nodeWS.FetchTransactionReceipt([]interface{}{"0x" + hash.String()}, blockchain.RPCOptions{
+		RetryAttempts: bxgateway.MaxEthOnBlockCallRetries,
+		RetryInterval: bxgateway.EthOnBlockCallRetrySleepInterval,
+		Context:       ctx,
+	})
+	if err != nil {
+		return false, err
+	}
+	return receipt != nil, nil
+}
+
 // SubscriptionExists - check if subscription exists
 func (f *FeedManager) SubscriptionExists(subscriptionID string) bool {
 	f.lock.RLock()
@@ -378,8 +617,74 @@ func (f *FeedManager) SubscriptionExists(subscriptionID string) bool {
 	return false
 }
 
+// setSubscriptionBackpressure sets the policy applied when the channel of the subscription is full
+func (f *FeedManager) setSubscriptionBackpressure(subscriptionID string, policy backpressurePolicy) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists {
+		return
+	}
+	clientSub.backpressure = policy
+	f.idToClientSubscription[subscriptionID] = clientSub
+}
+
+// setSubscriptionHeaderFirst sets whether the header only notifications of the blocks are sent to the subscription
+func (f *FeedManager) setSubscriptionHeaderFirst(subscriptionID string, headerFirst bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists {
+		return
+	}
+	clientSub.headerFirst = headerFirst
+	f.idToClientSubscription[subscriptionID] = clientSub
+}
+
+// txFirstSeen returns the time the gateway first saw the tx, zero if it did not see it
+func (f *FeedManager) txFirstSeen(txHash string) time.Time {
+	if f.txStore == nil {
+		return time.Time{}
+	}
+	hash, err := types.NewSHA256HashFromString(txHash)
+	if err != nil {
+		return time.Time{}
+	}
+	tx, ok := f.txStore.Get(hash)
+	if !ok {
+		return time.Time{}
+	}
+	return tx.AddTime()
+}
+
+// trackTxStatus tracks the txs of the request for the subscription if it is a transactionStatus subscription
+func (f *FeedManager) trackTxStatus(subscriptionID string, accountID types.AccountID, options *txStatusOptions) {
+	if options == nil {
+		return
+	}
+	f.txStatusTracker.Track(subscriptionID, accountID, options.hashes, options.autoTrack)
+}
+
+// addressedTo returns false if the notification is a tx status addressed to other subscriptions
+func addressedTo(notification types.Notification, subscriptionID string) bool {
+	status, ok := notification.(*types.TransactionStatusNotification)
+	return !ok || utils.Exists(subscriptionID, status.SubscriptionIDs)
+}
+
+// isHeaderOnly returns true if the notification is the early header of a block whose full notification follows
+func isHeaderOnly(notification types.Notification) bool {
+	block, ok := notification.(*types.EthBlockNotification)
+	return ok && block.HeaderOnly
+}
+
 // SubscriptionTypeExists - check if subscription with specific type exists
 func (f *FeedManager) SubscriptionTypeExists(feedType types.FeedType) bool {
+	if f.standingFeedExists(feedType) {
+		return true
+	}
+
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 	for _, clientSub := range f.idToClientSubscription {
@@ -392,6 +697,12 @@ func (f *FeedManager) SubscriptionTypeExists(feedType types.FeedType) bool {
 
 // NeedBlocks checks if feedManager should receive block notifications
 func (f *FeedManager) NeedBlocks() bool {
+	for _, subscription := range f.standing {
+		if subscription.Feed != types.NewTxsFeed && subscription.Feed != types.PendingTxsFeed {
+			return true
+		}
+	}
+
 	f.lock.RLock()
 	defer f.lock.RUnlock()
 	for _, clientSub := range f.idToClientSubscription {
@@ -459,7 +770,7 @@ func (f *FeedManager) GetAllSubscriptions() []sdnmessage.SubscriptionModel {
 	for id, sub := range f.idToClientSubscription {
 		subscriptionModel := sdnmessage.SubscriptionModel{
 			SubscriptionID: id,
-			SubscriberIP:   strings.Split(sub.RemoteAddress, ":")[0],
+			SubscriberIP:   utils.RemoteIP(sub.RemoteAddress),
 			NodeID:         string(f.nodeID),
 			AccountID:      sub.AccountID,
 			NetworkNum:     sub.network,
@@ -486,6 +797,39 @@ func (f *FeedManager) GetValidatorStatusMap() *syncmap.SyncMap[string, bool] {
 	return f.validatorStatusMap
 }
 
+// accountTxDefaults returns the flags applied to the txs of the account when omitted, local config has priority over the account model
+func (f *FeedManager) accountTxDefaults(accountID types.AccountID, account sdnmessage.Account) sdnmessage.TxDefaults {
+	if txDefaults, ok := f.cfg.AccountTxDefaults[accountID]; ok {
+		return txDefaults
+	}
+	if account.AccountID == accountID {
+		return account.TxDefaults
+	}
+	return sdnmessage.TxDefaults{}
+}
+
+// TxFlags are the MEV flags of a submitted tx, the Set fields are false if the request omitted the flag
+type TxFlags struct {
+	ValidatorsOnly            bool
+	ValidatorsOnlySet         bool
+	NextValidator             bool
+	FrontRunningProtection    bool
+	FrontRunningProtectionSet bool
+}
+
+// ApplyTxDefaults sets the flags omitted by the request to the defaults of the account sending the tx through the
+// connection, which is the original sender of txs relayed by cloud services, then to the tx policy of the network
+func (f *FeedManager) ApplyTxDefaults(conn connections.Conn, account sdnmessage.Account, flags TxFlags) TxFlags {
+	txDefaults := f.accountTxDefaults(conn.GetAccountID(), account)
+	if !flags.FrontRunningProtectionSet {
+		flags.FrontRunningProtection = txDefaults.FrontRunningProtection
+	}
+	if !flags.ValidatorsOnlySet && !flags.NextValidator {
+		flags.ValidatorsOnly = txDefaults.ValidatorsOnly || f.cfg.TxPolicy.ValidatorsOnly
+	}
+	return flags
+}
+
 // LockPendingNextValidatorTxs activates mutex lock for pendingBSCNextValidatorTxHashToInfo map
 func (f *FeedManager) LockPendingNextValidatorTxs() {
 	f.pendingBSCNextValidatorTxsMapLock.Lock()
@@ -0,0 +1,398 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	feedsv1 "github.com/bloXroute-Labs/gateway/v2/protobuf/feeds/v1"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/zhouzhuojie/conditions"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// feedsV1 are the feeds of the versioned feeds contract, in the order ListFeeds returns them
+var feedsV1 = []struct {
+	feed     feedsv1.Feed
+	feedType types.FeedType
+	includes []string
+}{
+	{feedsv1.Feed_FEED_NEW_TXS, types.NewTxsFeed, validTxParams},
+	{feedsv1.Feed_FEED_PENDING_TXS, types.PendingTxsFeed, validTxParams},
+	{feedsv1.Feed_FEED_NEW_BLOCKS, types.NewBlocksFeed, validBlockParams},
+	{feedsv1.Feed_FEED_BDN_BLOCKS, types.BDNBlocksFeed, validBlockParams},
+	{feedsv1.Feed_FEED_ETH_ON_BLOCK, types.OnBlockFeed, validOnBlockParams},
+	{feedsv1.Feed_FEED_TX_RECEIPTS, types.TxReceiptsFeed, validTxReceiptParams},
+	{feedsv1.Feed_FEED_CONTRACT_CREATIONS, types.ContractCreationsFeed, validContractCreationParams},
+	{feedsv1.Feed_FEED_LOGS, types.LogsFeed, validLogParams},
+}
+
+var feedsV1Backpressure = map[feedsv1.Backpressure]backpressurePolicy{
+	feedsv1.Backpressure_BACKPRESSURE_DISCONNECT:  backpressureDisconnect,
+	feedsv1.Backpressure_BACKPRESSURE_DROP_OLDEST: backpressureDropOldest,
+	feedsv1.Backpressure_BACKPRESSURE_DROP_NEWEST: backpressureDropNewest,
+}
+
+// FeedsServer serves the versioned feeds contract of protobuf/feeds/v1 from the feed manager of the gRPC handler.
+// The includes, filters and options of the subscriptions have the syntax and the validation of the websocket
+// subscriptions.
+type FeedsServer struct {
+	feedsv1.UnimplementedFeedsServer
+	handler   *GrpcHandler
+	authorize func(ctx context.Context) (sdnmessage.Account, error)
+}
+
+// NewFeedsServer creates a new FeedsServer, authorize returns the account of the auth header of the call
+func NewFeedsServer(handler *GrpcHandler, authorize func(ctx context.Context) (sdnmessage.Account, error)) *FeedsServer {
+	return &FeedsServer{
+		handler:   handler,
+		authorize: authorize,
+	}
+}
+
+// ListFeeds returns the feeds of the contract with the fields each of them can include
+func (s *FeedsServer) ListFeeds(ctx context.Context, _ *feedsv1.ListFeedsRequest) (*feedsv1.ListFeedsReply, error) {
+	if _, err := s.authorize(ctx); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	reply := &feedsv1.ListFeedsReply{}
+	for _, feed := range feedsV1 {
+		reply.Feeds = append(reply.Feeds, &feedsv1.FeedInfo{Feed: feed.feed, Includes: feed.includes})
+	}
+	return reply, nil
+}
+
+// Subscribe streams the notifications of the feed of the request until the client cancels the call
+func (s *FeedsServer) Subscribe(req *feedsv1.SubscribeRequest, stream feedsv1.Feeds_SubscribeServer) error {
+	account, err := s.authorize(stream.Context())
+	if err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	feedManager := s.handler.feedManager
+	request, err := s.newClientReq(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err = feedManager.AllowCalls(account, config.RateLimitSubscribe, 1); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if request.feed == types.OnBlockFeed {
+		if err = feedManager.AllowCalls(account, config.RateLimitOnBlockCall, len(*request.calls)); err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+
+	ci := types.ClientInfo{
+		AccountID:     account.AccountID,
+		Tier:          string(account.TierName),
+		MetaInfo:      types.SDKMetaFromContext(stream.Context()),
+		RemoteAddress: GetPeerAddr(stream.Context()),
+	}
+	ro := types.ReqOptions{Filters: req.GetFilters()}
+
+	sub, err := feedManager.SubscribeClient(request.feed, types.GRPCFeed, nil, ci, ro, false)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("failed to subscribe to gRPC %v feed", request.feed))
+	}
+	defer feedManager.Unsubscribe(sub.SubscriptionID, false, "")
+	feedManager.setSubscriptionBackpressure(sub.SubscriptionID, request.backpressure)
+	request.delivery = sub.delivery
+
+	send := func(payload interface{}) error {
+		notification := &feedsv1.Notification{Feed: req.GetFeed(), Time: timestamppb.Now()}
+		switch p := payload.(type) {
+		case *feedsv1.Transaction:
+			notification.Payload = &feedsv1.Notification_Transaction{Transaction: p}
+		case *feedsv1.Block:
+			notification.Payload = &feedsv1.Notification_Block{Block: p}
+		case *feedsv1.OnBlockResult:
+			notification.Payload = &feedsv1.Notification_OnBlock{OnBlock: p}
+		case *feedsv1.TxReceipt:
+			notification.Payload = &feedsv1.Notification_TxReceipt{TxReceipt: p}
+		case *feedsv1.ContractCreation:
+			notification.Payload = &feedsv1.Notification_ContractCreation{ContractCreation: p}
+		case *feedsv1.Log:
+			notification.Payload = &feedsv1.Notification_Log{Log: p}
+		}
+		if err := stream.Send(notification); err != nil {
+			return err
+		}
+		request.delivery.sent(1, 0)
+		feedManager.exporter.Export(account.AccountID, request.feed, notification)
+		return nil
+	}
+
+	ctx := stream.Context()
+	for {
+		var notification types.Notification
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case n, ok := <-sub.FeedChan:
+			if !ok {
+				return status.Error(codes.Unavailable, fmt.Sprintf("gRPC %v subscription was closed", request.feed))
+			}
+			notification = n
+		}
+		request.delivery.receive()
+
+		if err = s.sendNotification(ctx, request, ci.RemoteAddress, account.AccountID, notification, send); err != nil {
+			if ctx.Err() != nil {
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
+
+// newClientReq validates the subscription request and builds the client request from it
+func (s *FeedsServer) newClientReq(req *feedsv1.SubscribeRequest) (*clientReq, error) {
+	var feedType types.FeedType
+	for _, feed := range feedsV1 {
+		if feed.feed == req.GetFeed() {
+			feedType = feed.feedType
+		}
+	}
+	if feedType == "" {
+		return nil, fmt.Errorf("got unsupported feed %v", req.GetFeed())
+	}
+
+	includes, err := validateIncludeParam(feedType, req.GetIncludes(), s.handler.txFromFieldIncludable)
+	if err != nil {
+		return nil, err
+	}
+
+	var expr conditions.Expr
+	if req.GetFilters() != "" {
+		if feedType != types.NewTxsFeed && feedType != types.PendingTxsFeed {
+			return nil, fmt.Errorf("filters are only supported in %v and %v", types.NewTxsFeed, types.PendingTxsFeed)
+		}
+		expr, err = validateFilters(req.GetFilters(), s.handler.txFromFieldIncludable)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Filters: %w", err)
+		}
+	}
+	operations, err := newFilterOperations(expr, s.handler.feedManager.filterFiles)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Filters: %w", err)
+	}
+
+	var logs *logsOptions
+	if feedType == types.LogsFeed {
+		logs = &logsOptions{BlockOffset: int(req.GetLogs().GetBlockOffset()), Address: req.GetLogs().GetAddress()}
+		for _, topics := range req.GetLogs().GetTopics() {
+			logs.Topics = append(logs.Topics, topics.GetTopics())
+		}
+		if err = logs.validate(); err != nil {
+			return nil, err
+		}
+	} else if req.GetLogs() != nil {
+		return nil, fmt.Errorf("logs options are only supported in %v", types.LogsFeed)
+	}
+
+	calls := make(map[string]*RPCCall)
+	if feedType == types.OnBlockFeed {
+		for idx, callParams := range req.GetCallParams() {
+			if callParams == nil {
+				return nil, fmt.Errorf("call-params cannot be nil")
+			}
+			if err = fillCalls(s.handler.feedManager, calls, idx, callParams.GetParams()); err != nil {
+				return nil, err
+			}
+		}
+	} else if len(req.GetCallParams()) > 0 {
+		return nil, fmt.Errorf("call-params are only supported in %v", types.OnBlockFeed)
+	}
+
+	backpressure, ok := feedsV1Backpressure[req.GetBackpressure()]
+	if !ok {
+		return nil, fmt.Errorf("got unsupported backpressure %v", req.GetBackpressure())
+	}
+
+	return &clientReq{
+		includes:     includes,
+		feed:         feedType,
+		expr:         expr,
+		operations:   operations,
+		calls:        &calls,
+		logs:         logs,
+		backpressure: backpressure,
+	}, nil
+}
+
+// sendNotification sends the messages of the contract built from the notification of the feed
+func (s *FeedsServer) sendNotification(ctx context.Context, request *clientReq, remoteAddress string, accountID types.AccountID,
+	notification types.Notification, send func(payload interface{}) error) error {
+	feedManager := s.handler.feedManager
+	switch request.feed {
+	case types.NewTxsFeed, types.PendingTxsFeed:
+		var tx *types.NewTransactionNotification
+		if pending, ok := notification.(*types.PendingTransactionNotification); ok {
+			tx = &pending.NewTransactionNotification
+		} else {
+			tx = notification.(*types.NewTransactionNotification)
+		}
+		result := filterAndInclude(request, tx, remoteAddress, accountID, feedManager.clockSkew())
+		if result == nil {
+			return nil
+		}
+		return send(s.transaction(request, tx, result))
+	case types.NewBlocksFeed, types.BDNBlocksFeed:
+		block := notification.WithFields(request.includes).(*types.EthBlockNotification)
+		return send(feedsBlock(s.handler.generateBlockReply(block)))
+	case types.OnBlockFeed:
+		return handleEthOnBlock(ctx, feedManager, notification.(*types.EthBlockNotification), *request.calls, func(result *types.OnBlockNotification) error {
+			reply := generateEthOnBlockReply(result.WithFields(request.includes).(*types.OnBlockNotification))
+			return send(&feedsv1.OnBlockResult{Name: reply.Name, Response: reply.Response, BlockHeight: reply.BlockHeight, Tag: reply.Tag})
+		})
+	case types.TxReceiptsFeed:
+		receipts := notification.WithFields(request.includes).(*types.TxReceiptsNotification)
+		for _, receipt := range receipts.Receipts {
+			if err := send(feedsTxReceipt(generateTxReceiptReply(receipt))); err != nil {
+				return err
+			}
+		}
+	case types.ContractCreationsFeed:
+		creation := notification.WithFields(request.includes).(*types.ContractCreationNotification)
+		return send(&feedsv1.ContractCreation{
+			TxHash:          creation.TxHash,
+			From:            interfaceToString(creation.From),
+			ContractAddress: interfaceToString(creation.ContractAddress),
+			BlockHash:       creation.BlockHash,
+			BlockNumber:     creation.BlockNumber,
+			Status:          creation.Status,
+			GasUsed:         creation.GasUsed,
+		})
+	case types.LogsFeed:
+		logs, err := fetchLogs(ctx, feedManager, notification.(*types.EthBlockNotification), request.logs)
+		if err != nil {
+			log.Debugf("failed to fetch logs of block %v for gRPC %v: %v", notification.GetHash(), request.feed, err)
+		}
+		for _, l := range logs {
+			l = l.WithFields(request.includes).(*types.LogNotification)
+			if err = send(&feedsv1.Log{
+				Address:          l.Address,
+				Topics:           l.Topics,
+				Data:             l.Data,
+				BlockHash:        l.BlockHash,
+				BlockNumber:      l.BlockNumber,
+				TransactionHash:  l.TransactionHash,
+				TransactionIndex: l.TransactionIndex,
+				LogIndex:         l.LogIndex,
+				Removed:          l.Removed,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// transaction builds the transaction of the contract with the included fields of the tx result
+func (s *FeedsServer) transaction(request *clientReq, tx *types.NewTransactionNotification, result *TxResult) *feedsv1.Transaction {
+	transaction := &feedsv1.Transaction{}
+	if result.TxHash != nil {
+		transaction.TxHash = *result.TxHash
+	}
+	if result.RawTx != nil {
+		transaction.RawTx = tx.RawTx()
+	}
+	if result.LocalRegion != nil {
+		transaction.LocalRegion = *result.LocalRegion
+	}
+	if result.Time != nil {
+		transaction.Time = timestamppb.Now()
+	}
+	for _, include := range request.includes {
+		if include == txFromField && s.handler.txFromFieldIncludable {
+			if from := makeTransaction(*tx, true).From; len(from) > 0 {
+				transaction.From = hexutil.Encode(from)
+			}
+			break
+		}
+	}
+	return transaction
+}
+
+func feedsBlock(reply *pb.BlocksReply) *feedsv1.Block {
+	block := &feedsv1.Block{Hash: reply.Hash}
+	if h := reply.Header; h != nil {
+		block.Header = &feedsv1.BlockHeader{
+			ParentHash:       h.ParentHash,
+			Sha3Uncles:       h.Sha3Uncles,
+			Miner:            h.Miner,
+			StateRoot:        h.StateRoot,
+			TransactionsRoot: h.TransactionsRoot,
+			ReceiptsRoot:     h.ReceiptsRoot,
+			LogsBloom:        h.LogsBloom,
+			Difficulty:       h.Difficulty,
+			Number:           h.Number,
+			GasLimit:         h.GasLimit,
+			GasUsed:          h.GasUsed,
+			Timestamp:        h.Timestamp,
+			ExtraData:        h.ExtraData,
+			MixHash:          h.MixHash,
+			Nonce:            h.Nonce,
+			BaseFeePerGas:    h.BaseFeePerGas,
+			WithdrawalsRoot:  h.WithdrawalsRoot,
+		}
+	}
+	for _, tx := range reply.Transaction {
+		transaction := &feedsv1.Transaction{RawTx: tx.RawTx}
+		if len(tx.From) > 0 {
+			transaction.From = hexutil.Encode(tx.From)
+		}
+		block.Transactions = append(block.Transactions, transaction)
+	}
+	for _, withdrawal := range reply.Withdrawals {
+		block.Withdrawals = append(block.Withdrawals, &feedsv1.Withdrawal{
+			Address:        withdrawal.Address,
+			Amount:         withdrawal.Amount,
+			Index:          withdrawal.Index,
+			ValidatorIndex: withdrawal.ValidatorIndex,
+		})
+	}
+	return block
+}
+
+func feedsTxReceipt(reply *pb.TxReceiptsReply) *feedsv1.TxReceipt {
+	receipt := &feedsv1.TxReceipt{
+		BlockHash:         reply.BlocKHash,
+		BlockNumber:       reply.BlockNumber,
+		ContractAddress:   reply.ContractAddress,
+		CumulativeGasUsed: reply.CumulativeGasUsed,
+		EffectiveGasPrice: reply.EffectiveGasUsed,
+		From:              reply.From,
+		GasUsed:           reply.GasUsed,
+		LogsBloom:         reply.LogsBloom,
+		Status:            reply.Status,
+		To:                reply.To,
+		TransactionHash:   reply.TransactionHash,
+		TransactionIndex:  reply.TransactionIndex,
+		Type:              reply.Type,
+		TxsCount:          reply.TxsCount,
+	}
+	for _, l := range reply.Logs {
+		receipt.Logs = append(receipt.Logs, &feedsv1.Log{
+			Address:          l.Address,
+			Topics:           l.Topics,
+			Data:             l.Data,
+			BlockHash:        l.BlockHash,
+			BlockNumber:      l.BlockNumber,
+			TransactionHash:  l.TransactionHash,
+			TransactionIndex: l.TransactionIndex,
+			LogIndex:         l.LogIndex,
+			Removed:          l.Removed,
+		})
+	}
+	return receipt
+}
@@ -0,0 +1,90 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	feedsv1 "github.com/bloXroute-Labs/gateway/v2/protobuf/feeds/v1"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestFeedsServer_Subscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := make(chan types.Notification, 10)
+	fm := NewEmbeddedFeedManager(ctx, feed, 5, config.Bx{}, FeedManagerOptions{})
+	go func() { _ = fm.Start(ctx) }()
+
+	authorize := func(ctx context.Context) (sdnmessage.Account, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if auth := md.Get("authorization"); len(auth) == 0 || auth[0] != "secret" {
+			return sdnmessage.Account{}, errors.New("auth header is missing")
+		}
+		return fm.accountModel, nil
+	}
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	feedsv1.RegisterFeedsServer(server, NewFeedsServer(NewGrpcHandler(fm, false), authorize))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := feedsv1.NewFeedsClient(conn)
+
+	_, err = client.ListFeeds(ctx, &feedsv1.ListFeedsRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "secret")
+	feeds, err := client.ListFeeds(authCtx, &feedsv1.ListFeedsRequest{})
+	require.NoError(t, err)
+	require.Len(t, feeds.Feeds, 8)
+	assert.Equal(t, feedsv1.Feed_FEED_NEW_TXS, feeds.Feeds[0].Feed)
+	assert.Contains(t, feeds.Feeds[0].Includes, "raw_tx")
+
+	// invalid requests are rejected once the stream is read
+	stream, err := client.Subscribe(authCtx, &feedsv1.SubscribeRequest{Feed: feedsv1.Feed_FEED_NEW_BLOCKS, Filters: "gas_price > 1"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	streamCtx, cancelStream := context.WithCancel(authCtx)
+	stream, err = client.Subscribe(streamCtx, &feedsv1.SubscribeRequest{Feed: feedsv1.Feed_FEED_NEW_BLOCKS, Includes: []string{"hash", "header"}})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return fm.SubscriptionTypeExists(types.NewBlocksFeed) }, time.Second, 10*time.Millisecond)
+
+	block := newOrderingTestBlock(t, 10, common.Hash{})
+	block.SetNotificationType(types.NewBlocksFeed)
+	feed <- block
+
+	notification, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, feedsv1.Feed_FEED_NEW_BLOCKS, notification.Feed)
+	assert.NotNil(t, notification.Time)
+	require.NotNil(t, notification.GetBlock())
+	assert.Equal(t, block.GetHash(), notification.GetBlock().Hash)
+	assert.Equal(t, block.Header.Number, notification.GetBlock().Header.Number)
+	assert.Empty(t, notification.GetBlock().Transactions)
+
+	// the subscription is removed once the client cancels the call
+	cancelStream()
+	assert.Eventually(t, func() bool { return !fm.SubscriptionTypeExists(types.NewBlocksFeed) }, time.Second, 10*time.Millisecond)
+}
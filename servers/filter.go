@@ -46,6 +46,11 @@ func isFiltersSupportedByTxType(txType uint8, filters []string) bool {
 }
 
 func validateFilters(filters string, txFromFieldIncludable bool) (conditions.Expr, error) {
+	return validateCalldataFilters(filters, txFromFieldIncludable, nil)
+}
+
+// validateCalldataFilters validates filters which can reference the calldata arguments of the methods of the decoder
+func validateCalldataFilters(filters string, txFromFieldIncludable bool, decoder *calldataDecoder) (conditions.Expr, error) {
 	_, expr, err := parseFilter(filters)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing Filters: %v", err)
@@ -54,7 +59,7 @@ func validateFilters(filters string, txFromFieldIncludable bool) (conditions.Exp
 		return nil, nil
 	}
 
-	err = evaluateFilters(expr)
+	err = evaluateFilters(expr, decoder)
 	if err != nil {
 		return nil, fmt.Errorf("error evaluated Filters: %v", err)
 	}
@@ -68,9 +73,19 @@ func validateFilters(filters string, txFromFieldIncludable bool) (conditions.Exp
 }
 
 // evaluateFilters - evaluating if the Filters provided by the user are ok
-func evaluateFilters(expr conditions.Expr) error {
+func evaluateFilters(expr conditions.Expr, decoder *calldataDecoder) error {
+	emptyTx := make(map[string]interface{}, len(types.EmptyFilteredTransactionMap))
+	if decoder != nil {
+		emptyTx = decoder.emptyArgs()
+	}
+	for key, value := range types.EmptyFilteredTransactionMap {
+		emptyTx[key] = value
+	}
+	if err := validateFilterOperations(expr.Args(), emptyTx); err != nil {
+		return err
+	}
 	// Evaluate if we should send the tx
-	_, err := conditions.Evaluate(expr, types.EmptyFilteredTransactionMap)
+	_, err := conditions.Evaluate(expr, emptyTx)
 	return err
 }
 
@@ -94,6 +109,8 @@ func IsCorrectGasPriceFilters(filters []string) bool {
 
 // parseFilter parsing the filter
 func parseFilter(filters string) (string, conditions.Expr, error) {
+	filters = rewriteFilterOperators(filters)
+
 	// if the filters values are go-type filters, for example: {value}, parse the filters
 	// if not go-type, convert it to go-type filters
 	if strings.Contains(filters, "{") {
@@ -144,7 +161,7 @@ func parseFilter(filters string) (string, conditions.Expr, error) {
 		case utils.Exists(elem, operands):
 			newFilterString.WriteString(")")
 			newFilterString.WriteString(" " + elem + " ")
-		case utils.Exists(elem, availableFilters):
+		case utils.Exists(elem, availableFilters), strings.HasPrefix(elem, calldataArgPrefix), isFilterOperation(elem):
 			newFilterString.WriteString("({" + elem + "}")
 		default:
 			isString := false
@@ -0,0 +1,168 @@
+package servers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/zhouzhuojie/conditions"
+)
+
+// operators of the filters on the string fields, which the expression library does not support. An operation is
+// rewritten into an argument named after its field, operator and operand, e.g. to starts_with 0xdead becomes
+// ({to.starts_with.0xdead} == 1), whose value is computed from the field of the tx before the filters are evaluated.
+const (
+	filterOpStartsWith = "starts_with"
+	filterOpEndsWith   = "ends_with"
+	filterOpInFile     = "in_file"
+)
+
+var (
+	filterOpAffixRegex  = regexp.MustCompile(`(?i)\{?([a-z_][a-z0-9_.]*)\}?\s+(starts_with|ends_with)\s+['"]?([0-9a-z]+)['"]?`)
+	filterOpInFileRegex = regexp.MustCompile(`(?i)\{?([a-z_][a-z0-9_.]*)\}?\s+in_file\s*\(\s*['"]([a-z0-9_.\-]+)['"]\s*\)`)
+
+	// filterOpFields are the string fields the operators apply to, along with the calldata arguments
+	filterOpFields = []string{"to", "from", "method_id"}
+)
+
+// rewriteFilterOperators rewrites the operations of the filters into the arguments computed by filterOperations
+func rewriteFilterOperators(filters string) string {
+	// the go-type filters compare the argument, the others are converted to go-type filters by parseFilter
+	argument := func(name string) string { return name + " = 1" }
+	if strings.Contains(filters, "{") {
+		argument = func(name string) string { return "{" + name + "} == 1" }
+	}
+	filters = filterOpAffixRegex.ReplaceAllStringFunc(filters, func(match string) string {
+		parts := filterOpAffixRegex.FindStringSubmatch(match)
+		field, operator, operand := strings.ToLower(parts[1]), strings.ToLower(parts[2]), strings.ToLower(parts[3])
+		// as the values of the filters, the prefixes are hex strings with or without the 0x prefix
+		if operator == filterOpStartsWith && !strings.HasPrefix(operand, "0x") {
+			operand = "0x" + operand
+		}
+		return argument(field + "." + operator + "." + operand)
+	})
+	return filterOpInFileRegex.ReplaceAllStringFunc(filters, func(match string) string {
+		parts := filterOpInFileRegex.FindStringSubmatch(match)
+		return argument(strings.ToLower(parts[1]) + "." + filterOpInFile + "." + strings.ToLower(parts[2]))
+	})
+}
+
+// filterOperation is an operation of the filters on a field
+type filterOperation struct {
+	field    string
+	operator string
+	operand  string
+}
+
+// parseFilterOperation parses the argument of an operation, returning false if the argument is a field
+func parseFilterOperation(arg string) (filterOperation, bool) {
+	for _, operator := range []string{filterOpStartsWith, filterOpEndsWith, filterOpInFile} {
+		if i := strings.Index(arg, "."+operator+"."); i > 0 {
+			return filterOperation{field: arg[:i], operator: operator, operand: arg[i+len(operator)+2:]}, true
+		}
+	}
+	return filterOperation{}, false
+}
+
+// isFilterOperation returns true if the argument of the filters is an operation
+func isFilterOperation(arg string) bool {
+	_, ok := parseFilterOperation(arg)
+	return ok
+}
+
+// validateFilterOperations checks the operations of the filters apply to the string fields of the tx, setting their
+// arguments in the empty tx the filters are evaluated against
+func validateFilterOperations(args []string, emptyTx map[string]interface{}) error {
+	for _, arg := range args {
+		op, ok := parseFilterOperation(arg)
+		if !ok {
+			continue
+		}
+		if !strings.HasPrefix(op.field, calldataArgPrefix) && !utils.Exists(op.field, filterOpFields) {
+			return fmt.Errorf("%v is only supported on %v and the calldata arguments, not on %v", op.operator, strings.Join(filterOpFields, ", "), op.field)
+		}
+		if _, ok = emptyTx[op.field]; !ok {
+			return fmt.Errorf("argument: %v not found", op.field)
+		}
+		emptyTx[arg] = float64(0)
+	}
+	return nil
+}
+
+// filterOperations computes the arguments of the operations of the filters of a subscription. All methods can be
+// called on a nil receiver, which has no operations.
+type filterOperations struct {
+	operations map[string]filterOperation
+	sets       map[string]*services.AddressSet
+}
+
+// newFilterOperations resolves the operations of the filters, loading the address sets of their files
+func newFilterOperations(expr conditions.Expr, sets *services.AddressSets) (*filterOperations, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	var o *filterOperations
+	for _, arg := range expr.Args() {
+		op, ok := parseFilterOperation(arg)
+		if !ok {
+			continue
+		}
+		if o == nil {
+			o = &filterOperations{operations: make(map[string]filterOperation), sets: make(map[string]*services.AddressSet)}
+		}
+		o.operations[arg] = op
+		if op.operator != filterOpInFile {
+			continue
+		}
+		set, err := sets.Get(op.operand)
+		if err != nil {
+			return nil, err
+		}
+		o.sets[op.operand] = set
+	}
+	return o, nil
+}
+
+// fields returns the fields of the tx the filters need, the fields of the operations replacing them
+func (o *filterOperations) fields(args []string) []string {
+	if o == nil {
+		return args
+	}
+	fields := make([]string, 0, len(args))
+	for _, arg := range args {
+		if op, ok := o.operations[arg]; ok {
+			arg = op.field
+		}
+		if !utils.Exists(arg, fields) {
+			fields = append(fields, arg)
+		}
+	}
+	return fields
+}
+
+// apply sets the arguments of the operations from the fields of the tx, 1 if the operation matches and 0 otherwise
+func (o *filterOperations) apply(txFilters map[string]interface{}) {
+	if o == nil {
+		return
+	}
+	for arg, op := range o.operations {
+		value, _ := txFilters[op.field].(string)
+		value = strings.ToLower(value)
+		var match bool
+		switch op.operator {
+		case filterOpStartsWith:
+			match = value != "" && strings.HasPrefix(value, op.operand)
+		case filterOpEndsWith:
+			match = value != "" && strings.HasSuffix(value, op.operand)
+		case filterOpInFile:
+			match = value != "" && o.sets[op.operand].Contains(value)
+		}
+		if match {
+			txFilters[arg] = float64(1)
+		} else {
+			txFilters[arg] = float64(0)
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package servers
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhouzhuojie/conditions"
 )
 
 // pythonFiltersToGoFilters - contains available filters in python format and theirs go format filters
@@ -80,7 +85,7 @@ func TestFilter(t *testing.T) {
 		goFormatResult, exp, err := parseFilter(s.Filters)
 		assert.Equal(t, strings.ToLower(expectedGoFormat), strings.ToLower(goFormatResult))
 		assert.Nil(t, err)
-		assert.Nil(t, evaluateFilters(exp))
+		assert.Nil(t, evaluateFilters(exp, nil))
 	}
 
 	for _, invalidFilters := range invalidPythonFilters {
@@ -150,3 +155,60 @@ func TestIsCorrectGasPriceFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterOperators(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "routers.txt"), []byte("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2\n"), 0o644))
+	sets := services.NewAddressSets(dir)
+
+	filters := map[string]string{
+		"to starts_with 0xc02a and value > 1":  "({to.starts_with.0xc02a} == 1) and ({value} > 1)",
+		"method_id starts_with a905":           "({method_id.starts_with.0xa905} == 1)",
+		"to ends_with 6cc2":                    "({to.ends_with.6cc2} == 1)",
+		"to in_file('Routers.txt') or to = 0x": "({to.in_file.routers.txt} == 1) or ({to} == '0x')",
+	}
+	for filter, expected := range filters {
+		goFormat, expr, err := parseFilter(filter)
+		require.NoError(t, err, filter)
+		assert.Equal(t, expected, goFormat)
+		assert.NoError(t, evaluateFilters(expr, nil))
+	}
+
+	_, expr, err := parseFilter("{to} starts_with '0xC02A' and {from} in_file('routers.txt')")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"to.starts_with.0xc02a", "from.in_file.routers.txt"}, expr.Args())
+
+	operations, err := newFilterOperations(expr, sets)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"to", "from"}, operations.fields(expr.Args()))
+	txFilters := map[string]interface{}{"to": "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "from": "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"}
+	operations.apply(txFilters)
+	match, err := conditions.Evaluate(expr, txFilters)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	txFilters["from"] = "0x0"
+	operations.apply(txFilters)
+	match, err = conditions.Evaluate(expr, txFilters)
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	// the operators only apply to the string fields and the files of the gateway
+	_, expr, err = parseFilter("value starts_with 10")
+	require.NoError(t, err)
+	assert.Error(t, evaluateFilters(expr, nil))
+	_, expr, err = parseFilter("to in_file('missing.txt')")
+	require.NoError(t, err)
+	_, err = newFilterOperations(expr, sets)
+	assert.Error(t, err)
+	_, err = newFilterOperations(expr, nil)
+	assert.Error(t, err)
+
+	// filters without operations need no fields replaced
+	_, expr, err = parseFilter("to = 0xaa")
+	require.NoError(t, err)
+	operations, err = newFilterOperations(expr, nil)
+	require.NoError(t, err)
+	assert.Nil(t, operations)
+	assert.Equal(t, []string{"to"}, operations.fields(expr.Args()))
+}
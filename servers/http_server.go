@@ -5,13 +5,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/sourcegraph/jsonrpc2"
 )
@@ -22,11 +27,12 @@ type HTTPServer struct {
 	feedManager *FeedManager
 }
 
-// NewHTTPServer creates and returns a new websocket server managed by FeedManager
+// NewHTTPServer creates and returns a new websocket server managed by FeedManager, listening on the HTTP host of
+// its config, which is every IPv4 and IPv6 address if it is empty
 func NewHTTPServer(feedManager *FeedManager, port int) *HTTPServer {
 	return &HTTPServer{
 		server: &http.Server{
-			Addr: fmt.Sprintf(":%v", port),
+			Addr: net.JoinHostPort(feedManager.cfg.HTTPHost, strconv.Itoa(port)),
 		},
 		feedManager: feedManager,
 	}
@@ -40,7 +46,11 @@ func (s *HTTPServer) Start() error {
 	log.Infof("starting HTTP RPC server at: %v", s.server.Addr)
 	s.server.Handler = s.setupHandlers()
 
-	err := s.server.ListenAndServe()
+	listener, err := s.feedManager.listen(s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start HTTP RPC server: %v", err)
+	}
+	err = s.server.Serve(listener)
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("failed to start HTTP RPC server: %v", err)
 	}
@@ -64,11 +74,57 @@ func (s *HTTPServer) Stop() error {
 func (s *HTTPServer) setupHandlers() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.httpRPCHandler)
+	mux.HandleFunc("/readyz", s.readyzHandler)
+	mux.Handle("/metrics", s.feedManager.metrics.handler())
+
+	return s.feedManager.forwardedFor(mux)
+}
+
+// readyzHandler reports the gateway as ready unless the last canary transaction failed
+func (s *HTTPServer) readyzHandler(w http.ResponseWriter, _ *http.Request) {
+	canary := s.feedManager.node.CanaryStatus()
+	if canary == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	statusCode := http.StatusOK
+	if canary.State == blockchain.CanaryUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(canary); err != nil {
+		log.Errorf("failed to marshal canary status: %v", err)
+	}
+}
 
-	return mux
+// authenticateJWT requires a JWT bearer token of the account of the gateway once JWT authentication is enabled
+func (s *HTTPServer) authenticateJWT(r *http.Request) error {
+	if !s.feedManager.jwtVerifier.Enabled() {
+		return nil
+	}
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, services.BearerPrefix) {
+		return errors.New("missing JWT bearer token")
+	}
+	accountID, err := s.feedManager.jwtVerifier.Verify(strings.TrimPrefix(authHeader, services.BearerPrefix))
+	if err != nil {
+		s.feedManager.securityEvents.AuthFailed(s.feedManager.accountModel.AccountID, r.RemoteAddr)
+		return err
+	}
+	if accountID != s.feedManager.accountModel.AccountID {
+		return fmt.Errorf("account %v is not authorized to call the gateway of account %v", accountID, s.feedManager.accountModel.AccountID)
+	}
+	return nil
 }
 
 func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
+	if err := s.authenticateJWT(r); err != nil {
+		writeErrorJSON(w, jsonrpc2.ID{}, http.StatusUnauthorized, err)
+		return
+	}
+
 	rpcRequest := jsonrpc2.Request{}
 	err := json.NewDecoder(r.Body).Decode(&rpcRequest)
 	if err != nil {
@@ -82,6 +138,10 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := privilegedMethods[jsonrpc.RPCRequestType(rpcRequest.Method)]; ok {
+		s.feedManager.securityEvents.PrivilegedMethod(s.feedManager.accountModel.AccountID, r.RemoteAddr, rpcRequest.Method)
+	}
+
 	switch jsonrpc.RPCRequestType(rpcRequest.Method) {
 	case jsonrpc.RPCEthSendBundle, jsonrpc.RPCEthSendMegaBundle:
 		var bundlePayload []jsonrpc.RPCSendBundle
@@ -111,6 +171,11 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 			EnforcePayout:   bundlePayload[0].EnforcePayout,
 		}
 
+		if payload.UUID == "" {
+			// the flashbots API identifies the bundles to replace by replacementUuid
+			payload.UUID = bundlePayload[0].ReplacementUUID
+		}
+
 		mevBundle, bundleHash, err := mevBundleFromRequest(&payload, s.feedManager.networkNum)
 		var result interface{}
 		if payload.UUID == "" {
@@ -142,8 +207,8 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 
 		ws := connections.NewRPCConn(s.feedManager.accountModel.AccountID, r.RemoteAddr, s.feedManager.networkNum, utils.Websocket)
 
-		if err = s.feedManager.node.HandleMsg(mevBundle, ws, connections.RunForeground); err != nil {
-			// err here is not possible right now but anyway we don't want expose reason of internal error to the client
+		if err = s.feedManager.sendMEVBundle(mevBundle, ws); err != nil {
+			// we don't want expose reason of internal error to the client
 			log.Errorf("failed to process %s: %v", mevBundle, err)
 			writeErrorJSON(w, rpcRequest.ID, http.StatusInternalServerError, nil)
 			return
@@ -192,13 +257,40 @@ func (s *HTTPServer) httpRPCHandler(w http.ResponseWriter, r *http.Request) {
 
 		ws := connections.NewRPCConn(s.feedManager.accountModel.AccountID, r.RemoteAddr, s.feedManager.networkNum, utils.Websocket)
 
-		if err := s.feedManager.node.HandleMsg(mevBundle, ws, connections.RunForeground); err != nil {
-			// err here is not possible right now but anyway we don't want expose reason of internal error to the client
+		if err := s.feedManager.sendMEVBundle(mevBundle, ws); err != nil {
+			// we don't want expose reason of internal error to the client
 			log.Errorf("failed to process %s: %v", mevBundle, err)
 			writeErrorJSON(w, rpcRequest.ID, http.StatusInternalServerError, nil)
 			return
 		}
 
+		writeJSON(w, rpcRequest.ID, http.StatusOK, result)
+	case jsonrpc.RPCBundleCancellation:
+		var params jsonrpc.RPCBundleCancellationPayload
+		if err = json.Unmarshal(*rpcRequest.Params, &params); err != nil {
+			writeErrorJSON(w, rpcRequest.ID, http.StatusBadRequest, fmt.Errorf("failed to unmarshal params for %v request: %v", jsonrpc.RPCBundleCancellation, err))
+			return
+		}
+		if err = params.Validate(); err != nil {
+			writeErrorJSON(w, rpcRequest.ID, http.StatusBadRequest, err)
+			return
+		}
+
+		ws := connections.NewRPCConn(s.feedManager.accountModel.AccountID, r.RemoteAddr, s.feedManager.networkNum, utils.Websocket)
+
+		result, errCode, err := HandleMEVBundleCancellation(s.feedManager, ws, s.feedManager.accountModel, &params)
+		if err != nil {
+			switch errCode {
+			case jsonrpc2.CodeInvalidParams:
+				writeErrorJSON(w, rpcRequest.ID, http.StatusBadRequest, err)
+			case jsonrpc2.CodeInvalidRequest:
+				writeErrorJSON(w, rpcRequest.ID, http.StatusForbidden, err)
+			default:
+				writeErrorJSON(w, rpcRequest.ID, http.StatusInternalServerError, nil)
+			}
+			return
+		}
+
 		writeJSON(w, rpcRequest.ID, http.StatusOK, result)
 	default:
 		err := fmt.Errorf("got unsupported method name: %v", rpcRequest.Method)
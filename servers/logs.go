@@ -0,0 +1,104 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// maxLogTopics is the number of topics of an event log, the event signature and up to 3 indexed arguments
+const maxLogTopics = 4
+
+var validLogParams = []string{"address", "topics", "data", "block_hash", "block_number", "transaction_hash",
+	"transaction_index", "log_index", "removed"}
+
+// logsOptions are the options of a logs subscription, the logs of each block are streamed if they are not set
+type logsOptions struct {
+	// Address only streams the logs emitted by one of the contracts
+	Address []string `json:"Address"`
+	// Topics only streams the logs whose topic at each position is one of the topics of the position, an empty
+	// position matches any topic
+	Topics [][]string `json:"Topics"`
+	// BlockOffset streams the logs of the block BlockOffset blocks before each new block, it is 0 or negative
+	BlockOffset int `json:"BlockOffset"`
+}
+
+func (o *logsOptions) validate() error {
+	for _, address := range o.Address {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid logs address %v", address)
+		}
+	}
+	if len(o.Topics) > maxLogTopics {
+		return fmt.Errorf("invalid logs topics, got %v positions, expected at most %v", len(o.Topics), maxLogTopics)
+	}
+	for _, topics := range o.Topics {
+		for _, topic := range topics {
+			if decoded, err := hexutil.Decode(topic); err != nil || len(decoded) != common.HashLength {
+				return fmt.Errorf("invalid logs topic %v, expected a 32 bytes hex string", topic)
+			}
+		}
+	}
+	if o.BlockOffset > 0 {
+		return fmt.Errorf("invalid logs BlockOffset %v, must be 0 or a negative number", o.BlockOffset)
+	}
+	return nil
+}
+
+// query returns the eth_getLogs filter of the logs of the block
+func (o *logsOptions) query(block *types.EthBlockNotification) map[string]interface{} {
+	query := make(map[string]interface{})
+	if o.BlockOffset == 0 {
+		query["blockHash"] = block.GetHash()
+	} else {
+		tag := hexutil.EncodeUint64(block.Header.GetNumber() + uint64(o.BlockOffset))
+		query["fromBlock"] = tag
+		query["toBlock"] = tag
+	}
+	if len(o.Address) > 0 {
+		query["address"] = o.Address
+	}
+	if len(o.Topics) > 0 {
+		topics := make([]interface{}, len(o.Topics))
+		for i, position := range o.Topics {
+			// null matches any topic at the position
+			if len(position) > 0 {
+				topics[i] = position
+			}
+		}
+		query["topics"] = topics
+	}
+	return query
+}
+
+// fetchLogs fetches the logs of the block matching the options from the node
+func fetchLogs(ctx context.Context, feedManager *FeedManager, block *types.EthBlockNotification, options *logsOptions) ([]*types.LogNotification, error) {
+	nodeWS, ok := feedManager.getSyncedWSProvider(block.Source())
+	if !ok {
+		return nil, fmt.Errorf("node ws connection is not available")
+	}
+
+	response, err := nodeWS.CallRPC("eth_getLogs", []interface{}{options.query(block)}, blockchain.RPCOptions{RetryAttempts: bxgateway.MaxEthOnBlockCallRetries, RetryInterval: bxgateway.EthOnBlockCallRetrySleepInterval, Context: ctx})
+	if err != nil {
+		return nil, err
+	}
+	logs, ok := response.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected eth_getLogs response %v", response)
+	}
+
+	result := make([]*types.LogNotification, 0, len(logs))
+	for _, l := range logs {
+		logMap, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result = append(result, types.NewLogNotification(logMap))
+	}
+	return result, nil
+}
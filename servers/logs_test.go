@@ -0,0 +1,44 @@
+package servers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogsOptions(t *testing.T) {
+	transferTopic := "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	options := logsOptions{
+		Address: []string{"0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"},
+		Topics:  [][]string{{transferTopic}, nil},
+	}
+	assert.NoError(t, options.validate())
+
+	hash := common.HexToHash("0xb10c")
+	block := &types.EthBlockNotification{
+		BlockHash: &hash,
+		Header:    types.ConvertEthHeaderToBlockNotificationHeader(&ethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(0)}),
+	}
+	assert.Equal(t, map[string]interface{}{
+		"blockHash": hash.Hex(),
+		"address":   options.Address,
+		"topics":    []interface{}{[]string{transferTopic}, nil},
+	}, options.query(block))
+
+	options = logsOptions{BlockOffset: -2}
+	assert.Equal(t, map[string]interface{}{"fromBlock": "0x62", "toBlock": "0x62"}, options.query(block))
+
+	invalid := []logsOptions{
+		{Address: []string{"0x1"}},
+		{Topics: [][]string{{"0x01"}}},
+		{Topics: [][]string{nil, nil, nil, nil, nil}},
+		{BlockOffset: 1},
+	}
+	for _, options := range invalid {
+		assert.Error(t, options.validate())
+	}
+}
@@ -0,0 +1,239 @@
+package servers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "gateway"
+
+// filterLatencyBuckets are the buckets of the filter evaluation latency in seconds, from 1µs to 10ms
+var filterLatencyBuckets = prometheus.ExponentialBuckets(0.000001, 4, 8)
+
+// feedMetrics are the metrics of the feeds exported on the /metrics endpoint. The counters are labeled by feed, the
+// subscription counts, the bridge channel saturation and the block source arrivals are read from their source on each
+// scrape.
+type feedMetrics struct {
+	registry             *prometheus.Registry
+	notificationsSent    *prometheus.CounterVec
+	notificationsDropped *prometheus.CounterVec
+	writeErrors          *prometheus.CounterVec
+	filterLatency        *prometheus.HistogramVec
+	panics               *prometheus.CounterVec
+}
+
+// feedCounters are the metrics of a single feed, which the delivery stats of its subscriptions update. All methods do
+// nothing on a nil receiver.
+type feedCounters struct {
+	sent          prometheus.Counter
+	dropped       prometheus.Counter
+	writeErrors   prometheus.Counter
+	filterLatency prometheus.Observer
+}
+
+func newFeedMetrics(f *FeedManager, collectors ...prometheus.Collector) *feedMetrics {
+	m := &feedMetrics{
+		registry: prometheus.NewRegistry(),
+		notificationsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "feed_notifications_sent_total",
+			Help:      "Number of notifications sent to the subscriptions of the feed.",
+		}, []string{"feed"}),
+		notificationsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "feed_notifications_dropped_total",
+			Help:      "Number of notifications dropped because a subscription of the feed could not keep up.",
+		}, []string{"feed"}),
+		writeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "feed_ws_write_errors_total",
+			Help:      "Number of notifications of the feed which failed to be written to the websocket connection.",
+		}, []string{"feed"}),
+		filterLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "feed_filter_evaluation_seconds",
+			Help:      "Time spent evaluating the filters of a subscription of the feed on a notification.",
+			Buckets:   filterLatencyBuckets,
+		}, []string{"feed"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "panics_recovered_total",
+			Help:      "Number of panics recovered by scope, an RPC method, a subscription, an onBlock call or a gRPC method, and by name.",
+		}, []string{"scope", "name"}),
+	}
+	m.registry.MustRegister(m.notificationsSent, m.notificationsDropped, m.writeErrors, m.filterLatency, m.panics,
+		subscriptionCollector{feedManager: f})
+	m.registry.MustRegister(collectors...)
+	return m
+}
+
+// forFeed returns the metrics of the feed
+func (m *feedMetrics) forFeed(feed types.FeedType) *feedCounters {
+	if m == nil {
+		return nil
+	}
+	label := string(feed)
+	return &feedCounters{
+		sent:          m.notificationsSent.WithLabelValues(label),
+		dropped:       m.notificationsDropped.WithLabelValues(label),
+		writeErrors:   m.writeErrors.WithLabelValues(label),
+		filterLatency: m.filterLatency.WithLabelValues(label),
+	}
+}
+
+// addPanic counts a recovered panic
+func (m *feedMetrics) addPanic(scope, name string) {
+	if m == nil {
+		return
+	}
+	m.panics.WithLabelValues(scope, name).Inc()
+}
+
+// handler serves the metrics in the prometheus text format
+func (m *feedMetrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (c *feedCounters) addSent(notifications int) {
+	if c == nil {
+		return
+	}
+	c.sent.Add(float64(notifications))
+}
+
+func (c *feedCounters) addDropped() {
+	if c == nil {
+		return
+	}
+	c.dropped.Inc()
+}
+
+func (c *feedCounters) addWriteError() {
+	if c == nil {
+		return
+	}
+	c.writeErrors.Inc()
+}
+
+func (c *feedCounters) observeFilter(latency time.Duration) {
+	if c == nil {
+		return
+	}
+	c.filterLatency.Observe(latency.Seconds())
+}
+
+var subscriptionsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metricsNamespace, "", "feed_subscriptions"),
+	"Number of open subscriptions by account, tier and feed.",
+	[]string{"account_id", "tier", "feed"}, nil,
+)
+
+// subscriptionCollector counts the open subscriptions of the feed manager on each scrape
+type subscriptionCollector struct {
+	feedManager *FeedManager
+}
+
+func (c subscriptionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- subscriptionsDesc
+}
+
+func (c subscriptionCollector) Collect(ch chan<- prometheus.Metric) {
+	type subscriptionKey struct {
+		accountID types.AccountID
+		tier      string
+		feed      types.FeedType
+	}
+	counts := make(map[subscriptionKey]int)
+
+	c.feedManager.lock.RLock()
+	for _, sub := range c.feedManager.idToClientSubscription {
+		counts[subscriptionKey{accountID: sub.AccountID, tier: sub.Tier, feed: sub.feedType}]++
+	}
+	c.feedManager.lock.RUnlock()
+
+	for key, count := range counts {
+		ch <- prometheus.MustNewConstMetric(subscriptionsDesc, prometheus.GaugeValue, float64(count),
+			string(key.accountID), key.tier, string(key.feed))
+	}
+}
+
+var bridgeSaturationDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metricsNamespace, "", "bridge_channel_saturation"),
+	"Share of the buffer of the bridge channel which is in use, 1 is a full channel.",
+	[]string{"channel"}, nil,
+)
+
+var bridgeChannelFullDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metricsNamespace, "", "bridge_channel_full_total"),
+	"Number of messages dropped because the bridge channel was full.",
+	[]string{"channel"}, nil,
+)
+
+// bridgeCollector reads the saturation and the full counts of the bridge channels on each scrape
+type bridgeCollector struct {
+	saturation  func() map[string]float64
+	channelFull func() map[string]uint64
+}
+
+func (c bridgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- bridgeSaturationDesc
+	ch <- bridgeChannelFullDesc
+}
+
+func (c bridgeCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.saturation != nil {
+		for channel, saturation := range c.saturation() {
+			ch <- prometheus.MustNewConstMetric(bridgeSaturationDesc, prometheus.GaugeValue, saturation, channel)
+		}
+	}
+	if c.channelFull != nil {
+		for channel, count := range c.channelFull() {
+			ch <- prometheus.MustNewConstMetric(bridgeChannelFullDesc, prometheus.CounterValue, float64(count), channel)
+		}
+	}
+}
+
+var (
+	blockFirstSourceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "block_first_source_total"),
+		"Number of blocks received first from the source, the BDN or the blockchain node.",
+		[]string{"source"}, nil,
+	)
+	blockBothSourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "block_both_sources_total"),
+		"Number of blocks received first from the source which were received from the other source afterwards.",
+		[]string{"source"}, nil,
+	)
+	blockLeadTimeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "block_lead_time_seconds_total"),
+		"Total lead of the source over the other source on the blocks received from both, divided by the blocks received from both it is the average lead.",
+		[]string{"source"}, nil,
+	)
+)
+
+// blockSourceCollector reads the arrivals of the blocks from the BDN and the blockchain node on each scrape
+type blockSourceCollector struct {
+	stats func() map[string]services.BlockSourceStats
+}
+
+func (c blockSourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- blockFirstSourceDesc
+	ch <- blockBothSourcesDesc
+	ch <- blockLeadTimeDesc
+}
+
+func (c blockSourceCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.stats == nil {
+		return
+	}
+	for source, stats := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(blockFirstSourceDesc, prometheus.CounterValue, float64(stats.First), source)
+		ch <- prometheus.MustNewConstMetric(blockBothSourcesDesc, prometheus.CounterValue, float64(stats.Both), source)
+		ch <- prometheus.MustNewConstMetric(blockLeadTimeDesc, prometheus.CounterValue, stats.LeadTime.Seconds(), source)
+	}
+}
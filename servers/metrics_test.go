@@ -0,0 +1,61 @@
+package servers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedMetrics(t *testing.T) {
+	f := &FeedManager{idToClientSubscription: map[string]ClientSubscription{
+		"1": {ClientInfo: types.ClientInfo{AccountID: "a", Tier: "Enterprise"}, feedType: types.NewTxsFeed},
+		"2": {ClientInfo: types.ClientInfo{AccountID: "a", Tier: "Enterprise"}, feedType: types.NewTxsFeed},
+		"3": {ClientInfo: types.ClientInfo{AccountID: "b", Tier: "Professional"}, feedType: types.BDNBlocksFeed},
+	}}
+	metrics := newFeedMetrics(f, bridgeCollector{
+		saturation: func() map[string]float64 {
+			return map[string]float64{"transactions_from_bdn": 0.5}
+		},
+		channelFull: func() map[string]uint64 {
+			return map[string]uint64{"transactions_from_bdn": 7}
+		},
+	}, blockSourceCollector{
+		stats: func() map[string]services.BlockSourceStats {
+			return map[string]services.BlockSourceStats{services.BlockSourceBDN: {First: 3, Both: 2, LeadTime: 50 * time.Millisecond}}
+		},
+	})
+
+	delivery := newDeliveryStats(metrics.forFeed(types.NewTxsFeed))
+	delivery.sent(3, 100)
+	delivery.drop()
+	delivery.writeError()
+	delivery.filtered(time.Microsecond)
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.notificationsSent.WithLabelValues(string(types.NewTxsFeed))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.notificationsDropped.WithLabelValues(string(types.NewTxsFeed))))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.writeErrors.WithLabelValues(string(types.NewTxsFeed))))
+
+	recorder := httptest.NewRecorder()
+	metrics.handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	assert.Contains(t, body, `gateway_feed_subscriptions{account_id="a",feed="newTxs",tier="Enterprise"} 2`)
+	assert.Contains(t, body, `gateway_feed_subscriptions{account_id="b",feed="bdnBlocks",tier="Professional"} 1`)
+	assert.Contains(t, body, `gateway_bridge_channel_saturation{channel="transactions_from_bdn"} 0.5`)
+	assert.Contains(t, body, `gateway_bridge_channel_full_total{channel="transactions_from_bdn"} 7`)
+	assert.Contains(t, body, `gateway_block_first_source_total{source="bdn"} 3`)
+	assert.Contains(t, body, `gateway_block_both_sources_total{source="bdn"} 2`)
+	assert.Contains(t, body, `gateway_block_lead_time_seconds_total{source="bdn"} 0.05`)
+	assert.Contains(t, body, `gateway_feed_filter_evaluation_seconds_count{feed="newTxs"} 1`)
+
+	// subscriptions without metrics record nothing
+	var none *feedMetrics
+	newDeliveryStats(none.forFeed(types.NewTxsFeed)).sent(1, 100)
+}
@@ -0,0 +1,143 @@
+package servers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// functionSelectorLen is the byte length of the function selector prefixing the calldata
+const functionSelectorLen = 4
+
+// traceCallFrame is a frame of the callTracer of debug_traceCall, with the logs of the call and its sub calls
+type traceCallFrame struct {
+	Logs  []types.MEVShareLog `json:"logs"`
+	Calls []traceCallFrame    `json:"calls"`
+}
+
+// HandleMEVShareTransaction keeps the tx private as HandlePrivateTransaction and publishes its hint, holding only the
+// hinted data, to the mevShare feed of the BDN
+func HandleMEVShareTransaction(feedManager *FeedManager, transaction string, hints []string, conn connections.Conn, timeout time.Duration) (string, error) {
+	txHash, err := HandlePrivateTransaction(feedManager, transaction, conn, timeout)
+	if err != nil {
+		return "", err
+	}
+
+	// the tx was validated as a private tx, it is decoded again for its hint
+	txContent, err := types.DecodeHex(transaction)
+	if err != nil {
+		return "", err
+	}
+	ethTx, err := utils.ParseRawTransaction(txContent)
+	if err != nil {
+		return "", err
+	}
+	if len(hints) == 0 {
+		hints = types.MEVShareHints
+	}
+
+	var logs []types.MEVShareLog
+	if types.Exists(types.MEVShareHintLogs, hints) {
+		logs, err = simulateLogs(feedManager, ethTx)
+		if err != nil {
+			// the hint is shared without the logs rather than delaying the tx
+			log.Warnf("failed to simulate logs of mev share tx %v, sharing its hint without logs: %v", ethTx.Hash(), err)
+		}
+	}
+
+	hint, err := bxmessage.NewMEVShareHint(mevShareHint(ethTx, hints, logs), feedManager.networkNum)
+	if err != nil {
+		return "", err
+	}
+	if err = feedManager.node.HandleMsg(hint, conn, connections.RunForeground); err != nil {
+		log.Errorf("failed to publish hint of mev share tx %v: %v", ethTx.Hash(), err)
+		return "", err
+	}
+	return txHash, nil
+}
+
+// mevShareHint redacts the tx to the hints its sender chose to share, the hash is always shared
+func mevShareHint(ethTx *ethtypes.Transaction, hints []string, logs []types.MEVShareLog) types.MEVShareNotification {
+	hint := types.MEVShareNotification{Hash: ethTx.Hash().String()}
+	for _, name := range hints {
+		switch name {
+		case types.MEVShareHintContractAddress:
+			if ethTx.To() != nil {
+				hint.To = ethTx.To().String()
+			}
+		case types.MEVShareHintFunctionSelector:
+			if ethTx.To() != nil && len(ethTx.Data()) >= functionSelectorLen {
+				hint.FunctionSelector = hexutil.Encode(ethTx.Data()[:functionSelectorLen])
+			}
+		case types.MEVShareHintCalldata:
+			if len(ethTx.Data()) > 0 {
+				hint.CallData = hexutil.Encode(ethTx.Data())
+			}
+		case types.MEVShareHintLogs:
+			hint.Logs = logs
+		}
+	}
+	return hint
+}
+
+// simulateLogs returns the logs emitted by the tx on top of the latest state of a synced node, as traced by the
+// callTracer of debug_traceCall
+func simulateLogs(feedManager *FeedManager, ethTx *ethtypes.Transaction) ([]types.MEVShareLog, error) {
+	if feedManager.nodeWSManager == nil {
+		return nil, fmt.Errorf("no websocket connection to a node")
+	}
+	ws, synced := feedManager.nodeWSManager.SyncedProvider()
+	if !synced {
+		return nil, fmt.Errorf("no synced node")
+	}
+	sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(ethTx.ChainId()), ethTx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %v", err)
+	}
+
+	call := map[string]interface{}{
+		"from":  sender.String(),
+		"gas":   hexutil.Uint64(ethTx.Gas()),
+		"value": (*hexutil.Big)(ethTx.Value()),
+		"data":  hexutil.Bytes(ethTx.Data()),
+	}
+	if ethTx.To() != nil {
+		call["to"] = ethTx.To().String()
+	}
+	tracer := map[string]interface{}{
+		"tracer":       "callTracer",
+		"tracerConfig": map[string]interface{}{"withLog": true},
+	}
+	response, err := ws.CallRPC("debug_traceCall", []interface{}{call, "latest", tracer}, blockchain.DefaultRPCOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace call on node %v: %v", ws.BlockchainPeerEndpoint().IPPort(), err)
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal debug_traceCall response: %v", err)
+	}
+	var frame traceCallFrame
+	if err = json.Unmarshal(content, &frame); err != nil {
+		return nil, fmt.Errorf("unexpected debug_traceCall response of node: %v", err)
+	}
+	return frame.logs(), nil
+}
+
+// logs returns the logs of the frame followed by the logs of its sub calls
+func (f traceCallFrame) logs() []types.MEVShareLog {
+	logs := append([]types.MEVShareLog{}, f.Logs...)
+	for _, call := range f.Calls {
+		logs = append(logs, call.logs()...)
+	}
+	return logs
+}
@@ -0,0 +1,41 @@
+package servers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMEVShareHint(t *testing.T) {
+	to := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	ethTx := ethtypes.NewTx(&ethtypes.LegacyTx{
+		Nonce:    1,
+		GasPrice: big.NewInt(1),
+		Gas:      21000,
+		To:       &to,
+		Data:     common.FromHex("0xa9059cbb000000000000000000000000000000000000000000000000000000000000002a"),
+	})
+	logs := []types.MEVShareLog{{Address: to.String(), Topics: []string{"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"}}}
+
+	hint := mevShareHint(ethTx, types.MEVShareHints, logs)
+	assert.Equal(t, types.MEVShareNotification{
+		Hash:             ethTx.Hash().String(),
+		To:               to.String(),
+		FunctionSelector: "0xa9059cbb",
+		Logs:             logs,
+	}, hint)
+
+	hint = mevShareHint(ethTx, []string{types.MEVShareHintCalldata}, logs)
+	assert.Equal(t, types.MEVShareNotification{
+		Hash:     ethTx.Hash().String(),
+		CallData: "0xa9059cbb000000000000000000000000000000000000000000000000000000000000002a",
+	}, hint)
+
+	creation := ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 2, GasPrice: big.NewInt(1), Gas: 100000, Data: common.FromHex("0x6080604052")})
+	hint = mevShareHint(creation, []string{types.MEVShareHintContractAddress, types.MEVShareHintFunctionSelector}, nil)
+	assert.Equal(t, types.MEVShareNotification{Hash: creation.Hash().String()}, hint)
+}
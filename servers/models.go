@@ -1,6 +1,14 @@
 package servers
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/services/wasmfilter"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/zhouzhuojie/conditions"
@@ -25,6 +33,14 @@ type TxResult struct {
 	LocalRegion *bool       `json:"localRegion,omitempty"`
 	Time        *string     `json:"time,omitempty"`
 	RawTx       *string     `json:"rawTx,omitempty"`
+	// offset of the gateway clock when it is beyond the skew threshold, set with time as it is unreliable then
+	TimeSkewMs *float64 `json:"time_skew_ms,omitempty"`
+	// feeds of the connection the tx matched within the dedup window, set if the subscription requested a dedup window
+	SeenOn []types.FeedType `json:"seen_on,omitempty"`
+	// peer gateway the tx was received from, empty if it was received by this gateway
+	Origin string `json:"origin,omitempty"`
+	// annotations of the enrichers by name, set if the subscription includes enrich
+	Enrichment map[string]interface{} `json:"enrichment,omitempty"`
 }
 
 // TxResultWithEthTx - request of jsonrpc params with an eth type transaction
@@ -42,6 +58,24 @@ type BlockResponse struct {
 	Result       types.Notification `json:"result"`
 }
 
+// transformedResponse is the response of a subscription with a transform
+type transformedResponse struct {
+	Subscription string      `json:"subscription"`
+	Result       interface{} `json:"result"`
+}
+
+// replayedResponse is the response of a subscription of the replay, holding a captured notification
+type replayedResponse struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// blockReorgResponse is the reorg event of a subscription with StrictOrdering
+type blockReorgResponse struct {
+	Subscription string      `json:"subscription"`
+	Result       *BlockReorg `json:"result"`
+}
+
 type txReceiptResponse struct {
 	Subscription string           `json:"subscription"`
 	Result       *types.TxReceipt `json:"result"`
@@ -53,6 +87,46 @@ type clientReq struct {
 	expr     conditions.Expr
 	calls    *map[string]*RPCCall
 	MultiTxs bool
+	// maxBatchSize is the largest number of txs of a MultiTxs message
+	maxBatchSize int
+	// maxBatchDelay is the longest time the first tx of a MultiTxs message waits for more txs, 0 sends the message
+	// once no more txs are waiting
+	maxBatchDelay time.Duration
+	// a tx already delivered on another feed of the connection within the window is not delivered again
+	dedupWindow time.Duration
+	// a tx is only delivered if it matches the WASM filter module of the account
+	wasmFilter *wasmfilter.Filter
+	// backlog returns the number of notifications waiting to be sent on the subscription
+	backlog func() int
+	// the notifications are projected by the transform before they are sent
+	transform *transform
+	// annotate the txs if the subscription includes enrich
+	enrichers []TxEnricher
+	// the onBlock results of each block are sent in pages of onBlockPageSize results, 0 sends each result on its own
+	onBlockPageSize int
+	// delivery counts the notifications sent and dropped on the subscription
+	delivery *deliveryStats
+	// backpressure is applied when the subscription channel is full
+	backpressure backpressurePolicy
+	// logs selects the logs fetched for each block of a logs subscription
+	logs *logsOptions
+	// calldata decodes the arguments of the txs referenced by the filters
+	calldata *calldataDecoder
+	// operations computes the starts_with, ends_with and in_file operations of the filters
+	operations *filterOperations
+	// headerFirst delivers the header only notification of each block before its full notification
+	headerFirst bool
+	// txStatus selects the txs tracked by a transactionStatus subscription
+	txStatus *txStatusOptions
+	// ordering delivers the blocks in ascending height order if the subscription requested StrictOrdering
+	ordering *blockOrderer
+}
+
+// txStatusOptions are the txs tracked by a transactionStatus subscription
+type txStatusOptions struct {
+	hashes []types.SHA256Hash
+	// autoTrack also tracks the txs later sent with blxr_tx by the account of the subscription
+	autoTrack bool
 }
 
 type subscriptionRequest struct {
@@ -66,8 +140,90 @@ type subscriptionOptions struct {
 	Filters    string              `json:"Filters"`
 	CallParams []map[string]string `json:"Call-Params"`
 	MultiTxs   bool                `json:"MultiTxs"`
+	// largest number of txs of a MultiTxs message, 0 uses the default
+	MaxBatchSize int `json:"MaxBatchSize"`
+	// longest time in milliseconds the first tx of a MultiTxs message waits for more txs, 0 sends the txs as soon as
+	// no more are waiting
+	MaxBatchDelayMs int64 `json:"MaxBatchDelayMs"`
+	// cross-feed dedup window in milliseconds, 0 disables the dedup
+	DedupWindowMs int64 `json:"DedupWindowMs"`
+	// name of a WASM filter module uploaded by the account with blxr_wasm_filter
+	WasmFilter string `json:"WasmFilter"`
+	// output field name to the path of the notification field it is projected from
+	Transform map[string]string `json:"Transform"`
+	// number of ethOnBlock results per page, the results of a block are aggregated into pages if set
+	OnBlockPageSize int `json:"OnBlockPageSize"`
+	// what to do with notifications when the client reads slower than they are published: drop_oldest, drop_newest
+	// or disconnect, which is the default
+	Backpressure string `json:"Backpressure"`
+	// address, topics and block offset of the logs of a logs subscription
+	Logs *logsOptions `json:"Logs"`
+	// ABI fragment of the methods whose calldata arguments can be referenced by the filters as {args.<name>}
+	ABI json.RawMessage `json:"ABI"`
+	// send the header of each block as soon as it is known, followed by the full notification once the bodies are
+	// assembled
+	HeaderFirst bool `json:"HeaderFirst"`
+	// hashes of the txs whose lifecycle is tracked by a transactionStatus subscription
+	TxHashes []string `json:"TxHashes"`
+	// track the txs sent with blxr_tx by the account in a transactionStatus subscription
+	AutoTrack bool `json:"AutoTrack"`
+	// deliver the blocks in ascending height order, briefly holding back the blocks arriving early, with a blockReorg
+	// event before a block replacing the blocks sent from its height
+	StrictOrdering bool `json:"StrictOrdering"`
 }
 
 type rpcPingResponse struct {
 	Pong string `json:"pong"`
 }
+
+type rpcWasmFilterResponse struct {
+	Modules []string `json:"modules"`
+}
+
+type rpcNodeStatusResponse struct {
+	TxPoolState  blockchain.TxPoolState        `json:"tx_pool_state"`
+	TxPools      []blockchain.NodeTxPoolStatus `json:"tx_pools"`
+	TxsForwarded uint64                        `json:"txs_forwarded"`
+	TxsDropped   uint64                        `json:"txs_dropped"`
+
+	TxPoolReconciliation *blockchain.TxPoolReconciliation `json:"txpool_reconciliation,omitempty"`
+	NetworkConfigUpdates uint64                           `json:"network_config_updates"`
+
+	InvalidBDNTxs        map[string]uint64 `json:"invalid_bdn_txs,omitempty"`
+	DroppedBDNValidation uint64            `json:"dropped_bdn_tx_validations"`
+
+	Canary           *blockchain.CanaryStatus           `json:"canary,omitempty"`
+	FeedVerification *blockchain.FeedVerificationStatus `json:"feed_verification,omitempty"`
+
+	OutboundProxies []connections.ProxyHealth `json:"outbound_proxies,omitempty"`
+
+	ClockOffsetMs float64 `json:"clock_offset_ms"`
+	ClockSkewed   bool    `json:"clock_skewed"`
+}
+
+// rpcBDNStatusResponse summarizes the relay connections, with the last time each message type was received
+type rpcBDNStatusResponse struct {
+	Protocol        bxmessage.Protocol          `json:"protocol"`
+	SyncedWithRelay bool                        `json:"synced_with_relay"`
+	Relays          []connections.BDNConnStatus `json:"relays"`
+}
+
+// rpcTimeResponse omits the slot fields on networks without slots
+type rpcTimeResponse struct {
+	TimestampMs    int64   `json:"timestamp_ms"`
+	OffsetMs       float64 `json:"offset_ms"`
+	Slot           uint64  `json:"slot,omitempty"`
+	SlotDurationMs int64   `json:"slot_duration_ms,omitempty"`
+	NextSlotMs     int64   `json:"next_slot_ms,omitempty"`
+	Skewed         bool    `json:"skewed"`
+}
+
+type rpcConfigResponse struct {
+	Settings config.Settings `json:"settings"`
+	Diff     config.Settings `json:"diff"`
+}
+
+type rpcValidatorListResponse struct {
+	BlockHeight   uint64   `json:"block_height"`
+	ValidatorList []string `json:"validator_list"`
+}
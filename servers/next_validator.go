@@ -0,0 +1,159 @@
+package servers
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
+	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+)
+
+// NextValidatorStrategy sets the wallets of the next validators the next_validator txs of a network are sent to
+type NextValidatorStrategy interface {
+	// EpochLength is the number of blocks between two updates of the validator set, 0 if the next validators are not
+	// computed from the epoch blocks
+	EpochLength() uint64
+	// SetWallets sets the wallets of the tx from the next validators, ordered by block height, and returns true if the
+	// tx has to wait for its first validator to be accessible
+	SetWallets(tx *bxmessage.Tx, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool]) bool
+}
+
+var (
+	nextValidatorStrategiesLock sync.RWMutex
+	nextValidatorStrategies     = map[types.NetworkNum]NextValidatorStrategy{
+		bxgateway.BSCMainnetNum:     NewParliaStrategy(bxgateway.BSCEpochLength, bxgateway.NetworkToBlockDuration[bxgateway.BSCMainnet], true),
+		bxgateway.PolygonMainnetNum: NewBorStrategy(),
+	}
+)
+
+// RegisterNextValidatorStrategy adds or replaces the next_validator strategy of a network
+func RegisterNextValidatorStrategy(networkNum types.NetworkNum, strategy NextValidatorStrategy) {
+	nextValidatorStrategiesLock.Lock()
+	defer nextValidatorStrategiesLock.Unlock()
+	nextValidatorStrategies[networkNum] = strategy
+}
+
+// NextValidatorStrategyOf returns the next_validator strategy of a network, false if the network does not support
+// next_validator txs
+func NextValidatorStrategyOf(networkNum types.NetworkNum) (NextValidatorStrategy, bool) {
+	nextValidatorStrategiesLock.RLock()
+	defer nextValidatorStrategiesLock.RUnlock()
+	strategy, ok := nextValidatorStrategies[networkNum]
+	return strategy, ok
+}
+
+// parliaStrategy sends the txs to the validator of the next block, as on BSC
+type parliaStrategy struct {
+	epochLength        uint64
+	blockInterval      time.Duration
+	probeAccessibility bool
+}
+
+// NewParliaStrategy creates the strategy of a Parlia network, whose validator set is updated every epochLength
+// blocks. If probeAccessibility is set the txs wait for the next validator to be accessible, unless their fallback
+// is shorter than a block.
+func NewParliaStrategy(epochLength uint64, blockInterval time.Duration, probeAccessibility bool) NextValidatorStrategy {
+	return parliaStrategy{
+		epochLength:        epochLength,
+		blockInterval:      blockInterval,
+		probeAccessibility: probeAccessibility,
+	}
+}
+
+// EpochLength returns the number of blocks of the epochs
+func (s parliaStrategy) EpochLength() uint64 {
+	return s.epochLength
+}
+
+// SetWallets sets the wallet of the validator of the next block
+func (s parliaStrategy) SetWallets(tx *bxmessage.Tx, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool]) bool {
+	n1Validator := nextValidatorMap.Newest().Prev()
+	n1Wallet := ""
+	if n1Validator != nil {
+		n1Wallet = n1Validator.Value.(string)
+	}
+
+	if !s.probeAccessibility {
+		if n1Wallet != "" {
+			tx.SetWalletID(0, n1Wallet)
+		}
+		return false
+	}
+
+	n1ValidatorAccessible := false
+	if n1Wallet != "" && validatorStatusMap != nil {
+		accessible, exist := validatorStatusMap.Load(n1Wallet)
+		if exist {
+			n1ValidatorAccessible = accessible
+		}
+	}
+	if n1ValidatorAccessible {
+		tx.SetWalletID(0, n1Wallet)
+		return false
+	}
+	// a fallback shorter than a block expires before the next validator can become accessible
+	return fallback == 0 || fallback >= uint16(s.blockInterval.Milliseconds())
+}
+
+// borStrategy sends the txs to the producers of the next two blocks, as on Polygon
+type borStrategy struct{}
+
+// NewBorStrategy creates the strategy of a Bor network, whose producers are the sprint producers of the spans
+func NewBorStrategy() NextValidatorStrategy {
+	return borStrategy{}
+}
+
+// EpochLength returns 0, the producers are fetched from Heimdall
+func (borStrategy) EpochLength() uint64 {
+	return 0
+}
+
+// SetWallets sets the wallets of the producers of the next two blocks
+func (borStrategy) SetWallets(tx *bxmessage.Tx, _ uint16, nextValidatorMap *orderedmap.OrderedMap, _ *syncmap.SyncMap[string, bool]) bool {
+	n2Validator := nextValidatorMap.Newest()
+	n1Validator := n2Validator.Prev()
+	if n1Validator != nil {
+		tx.SetWalletID(0, n1Validator.Value.(string))
+		tx.SetWalletID(1, n2Validator.Value.(string))
+	} else {
+		tx.SetWalletID(0, n2Validator.Value.(string))
+	}
+	return false
+}
+
+// ProcessNextValidatorTx - sets next validator wallets using the strategy of the network and returns bool indicating if tx is pending reevaluation due to inaccessible first validator
+func ProcessNextValidatorTx(tx *bxmessage.Tx, fallback uint16, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool], networkNum types.NetworkNum, source connections.Conn, pendingNextValidatorTxHashToInfo map[string]PendingNextValidatorTxInfo, now time.Time) (bool, error) {
+	strategy, ok := NextValidatorStrategyOf(networkNum)
+	if !ok {
+		return false, errors.New("currently next_validator is only supported on BSC and Polygon networks, please contact bloXroute support")
+	}
+
+	if nextValidatorMap == nil {
+		log.Errorf("failed to process next validator tx, because next validator map is nil, tx %v", tx.Hash().String())
+		return false, errors.New("failed to send next validator tx, please contact bloXroute support")
+	}
+
+	tx.SetFallback(fallback)
+
+	// the strategies take the latest two blocks from the ordered map for updating txMsg walletID
+	if nextValidatorMap.Newest() == nil {
+		return false, errors.New("can't send tx with next_validator because the gateway encountered an issue fetching the epoch block, please try again later or contact bloXroute support")
+	}
+
+	if !strategy.SetWallets(tx, fallback, nextValidatorMap, validatorStatusMap) {
+		return false, nil
+	}
+	pendingNextValidatorTxHashToInfo[tx.Hash().String()] = PendingNextValidatorTxInfo{
+		Tx:            tx,
+		Fallback:      fallback,
+		TimeOfRequest: now,
+		Source:        source,
+	}
+	return true, nil
+}
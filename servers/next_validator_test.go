@@ -0,0 +1,83 @@
+package servers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
+	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParliaStrategy(t *testing.T) {
+	nextValidatorMap := orderedmap.New()
+	nextValidatorMap.Set(uint64(101), "0xa")
+	nextValidatorMap.Set(uint64(102), "0xb")
+	validatorStatusMap := syncmap.NewStringMapOf[bool]()
+	strategy := NewParliaStrategy(500, 3*time.Second, true)
+	assert.Equal(t, uint64(500), strategy.EpochLength())
+
+	// the tx waits for the next validator to be accessible, unless its fallback expires first
+	tx := bxmessage.NewTx(types.SHA256Hash{1}, nil, bxgateway.BSCMainnetNum, types.TFNextValidator, "")
+	assert.True(t, strategy.SetWallets(tx, 0, nextValidatorMap, validatorStatusMap))
+	assert.True(t, strategy.SetWallets(tx, 3000, nextValidatorMap, validatorStatusMap))
+	assert.False(t, strategy.SetWallets(tx, 2000, nextValidatorMap, validatorStatusMap))
+	assert.Nil(t, tx.WalletIDs())
+
+	validatorStatusMap.Store("0xa", true)
+	assert.False(t, strategy.SetWallets(tx, 0, nextValidatorMap, validatorStatusMap))
+	assert.Equal(t, []string{"0xa", ""}, tx.WalletIDs())
+
+	// without probing the tx is sent to the next validator right away
+	tx = bxmessage.NewTx(types.SHA256Hash{2}, nil, bxgateway.BSCMainnetNum, types.TFNextValidator, "")
+	assert.False(t, NewParliaStrategy(200, 3*time.Second, false).SetWallets(tx, 0, nextValidatorMap, syncmap.NewStringMapOf[bool]()))
+	assert.Equal(t, []string{"0xa", ""}, tx.WalletIDs())
+}
+
+func TestBorStrategy(t *testing.T) {
+	nextValidatorMap := orderedmap.New()
+	nextValidatorMap.Set(uint64(101), "0xa")
+	strategy := NewBorStrategy()
+	assert.Equal(t, uint64(0), strategy.EpochLength())
+
+	tx := bxmessage.NewTx(types.SHA256Hash{1}, nil, bxgateway.PolygonMainnetNum, types.TFNextValidator, "")
+	assert.False(t, strategy.SetWallets(tx, 0, nextValidatorMap, nil))
+	assert.Equal(t, []string{"0xa", ""}, tx.WalletIDs())
+
+	nextValidatorMap.Set(uint64(102), "0xb")
+	assert.False(t, strategy.SetWallets(tx, 0, nextValidatorMap, nil))
+	assert.Equal(t, []string{"0xa", "0xb"}, tx.WalletIDs())
+}
+
+func TestProcessNextValidatorTx(t *testing.T) {
+	nextValidatorMap := orderedmap.New()
+	nextValidatorMap.Set(uint64(101), "0xa")
+	nextValidatorMap.Set(uint64(102), "0xb")
+	pending := make(map[string]PendingNextValidatorTxInfo)
+	now := time.Now()
+
+	tx := bxmessage.NewTx(types.SHA256Hash{1}, nil, bxgateway.BSCTestnetNum, types.TFNextValidator, "")
+	_, err := ProcessNextValidatorTx(tx, 0, nextValidatorMap, syncmap.NewStringMapOf[bool](), bxgateway.BSCTestnetNum, nil, pending, now)
+	require.Error(t, err)
+
+	// a registered network supports next_validator without changes to the handler
+	RegisterNextValidatorStrategy(bxgateway.BSCTestnetNum, NewParliaStrategy(bxgateway.BSCEpochLength, 3*time.Second, true))
+	defer func() {
+		nextValidatorStrategiesLock.Lock()
+		delete(nextValidatorStrategies, bxgateway.BSCTestnetNum)
+		nextValidatorStrategiesLock.Unlock()
+	}()
+
+	pendingReevaluation, err := ProcessNextValidatorTx(tx, 5000, nextValidatorMap, syncmap.NewStringMapOf[bool](), bxgateway.BSCTestnetNum, nil, pending, now)
+	require.NoError(t, err)
+	assert.True(t, pendingReevaluation)
+	assert.Equal(t, uint16(5000), tx.Fallback())
+	assert.Equal(t, PendingNextValidatorTxInfo{Tx: tx, Fallback: 5000, TimeOfRequest: now}, pending[tx.Hash().String()])
+
+	_, err = ProcessNextValidatorTx(tx, 0, orderedmap.New(), syncmap.NewStringMapOf[bool](), bxgateway.BSCTestnetNum, nil, pending, now)
+	assert.Error(t, err)
+}
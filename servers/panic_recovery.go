@@ -0,0 +1,52 @@
+package servers
+
+import (
+	"context"
+	"runtime/debug"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scopes of the recovered panics, the scope label of the panics metric
+const (
+	panicScopeRPC          = "rpc"
+	panicScopeSubscription = "subscription"
+	panicScopeOnBlockCall  = "onblock_call"
+	panicScopeGRPC         = "grpc"
+)
+
+// recoverPanic recovers from a panic of the calling goroutine, so a malformed notification or request only ends the
+// call or the subscription it belongs to. The panic is logged with its stack and counted, then onPanic is called with
+// it if not nil. It must be deferred directly.
+func (f *FeedManager) recoverPanic(scope, name string, onPanic func(r interface{})) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	log.Errorf("recovered from a panic of %v %v: %v\n%s", scope, name, r, debug.Stack())
+	if f != nil {
+		f.metrics.addPanic(scope, name)
+	}
+	if onPanic != nil {
+		onPanic(r)
+	}
+}
+
+// RecoverUnary is a gRPC unary interceptor failing the call of a panicking method with an internal error
+func (f *FeedManager) RecoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer f.recoverPanic(panicScopeGRPC, info.FullMethod, func(interface{}) {
+		err = status.Error(codes.Internal, "internal error")
+	})
+	return handler(ctx, req)
+}
+
+// RecoverStream is a gRPC stream interceptor ending the stream of a panicking method with an internal error
+func (f *FeedManager) RecoverStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer f.recoverPanic(panicScopeGRPC, info.FullMethod, func(interface{}) {
+		err = status.Error(codes.Internal, "internal error")
+	})
+	return handler(srv, stream)
+}
@@ -0,0 +1,43 @@
+package servers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFeedManager_RecoverPanic(t *testing.T) {
+	f := &FeedManager{idToClientSubscription: map[string]ClientSubscription{}}
+	f.metrics = newFeedMetrics(f)
+
+	var recovered interface{}
+	func() {
+		defer f.recoverPanic(panicScopeSubscription, "newTxs", func(r interface{}) { recovered = r })
+		panic("malformed notification")
+	}()
+	assert.Equal(t, "malformed notification", recovered)
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.panics.WithLabelValues(panicScopeSubscription, "newTxs")))
+
+	// a call which does not panic is not counted
+	func() {
+		defer f.recoverPanic(panicScopeSubscription, "newTxs", func(r interface{}) { t.Fatal("no panic expected") })
+	}()
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.panics.WithLabelValues(panicScopeSubscription, "newTxs")))
+
+	_, err := f.RecoverUnary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/gateway.Gateway/BlxrTx"},
+		func(context.Context, interface{}) (interface{}, error) { panic("malformed request") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, float64(1), testutil.ToFloat64(f.metrics.panics.WithLabelValues(panicScopeGRPC, "/gateway.Gateway/BlxrTx")))
+
+	// a feed manager without metrics still recovers
+	var none *FeedManager
+	func() {
+		defer none.recoverPanic(panicScopeRPC, "blxr_tx", nil)
+		panic("malformed request")
+	}()
+}
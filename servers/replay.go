@@ -0,0 +1,170 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// ReplayOptions of a ReplayHandler
+type ReplayOptions struct {
+	// Files are the captures of the JSON file sinks or the plaintext archives, replayed one after the other
+	Files []string
+	// Speed multiplies the pace of the captures, 1 replays the original timing and 0 replays without waiting
+	Speed float64
+}
+
+// ReplayHandler serves captured feeds over the websocket subscribe API, for deterministic client tests. Each
+// subscription replays the records of its feed from the start of the captures, the notifications are sent as captured
+// so the includes and the filters of the subscription are ignored.
+type ReplayHandler struct {
+	options ReplayOptions
+	clock   utils.Clock
+	lock    sync.Mutex
+	cancels map[string]context.CancelFunc
+	log     *log.Entry
+}
+
+// NewReplayHandler creates a ReplayHandler of the captures
+func NewReplayHandler(options ReplayOptions, clock utils.Clock) (*ReplayHandler, error) {
+	if len(options.Files) == 0 {
+		return nil, errors.New("no capture to replay")
+	}
+	if options.Speed < 0 {
+		return nil, fmt.Errorf("replay speed must be greater than or equal to 0, got %v", options.Speed)
+	}
+	return &ReplayHandler{
+		options: options,
+		clock:   clock,
+		cancels: make(map[string]context.CancelFunc),
+		log:     log.WithField("component", "replay"),
+	}, nil
+}
+
+// Handle serves the subscribe and unsubscribe requests
+func (h *ReplayHandler) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	switch jsonrpc.RPCRequestType(req.Method) {
+	case jsonrpc.RPCSubscribe:
+		var params []json.RawMessage
+		var feed types.FeedType
+		if req.Params == nil || json.Unmarshal(*req.Params, &params) != nil || len(params) == 0 || json.Unmarshal(params[0], &feed) != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, "subscribe params must start with the feed name", conn, req.ID)
+			return
+		}
+
+		subscriptionID := utils.GenerateUUID()
+		replayCtx, cancel := context.WithCancel(context.Background())
+		h.lock.Lock()
+		h.cancels[subscriptionID] = cancel
+		h.lock.Unlock()
+
+		if err := conn.Reply(ctx, req.ID, subscriptionID); err != nil {
+			h.log.Errorf("error replying to subscribe request of %v feed: %v", feed, err)
+			h.unsubscribe(subscriptionID)
+			return
+		}
+		go func() {
+			if err := h.replay(replayCtx, conn, subscriptionID, feed); err != nil {
+				h.log.Errorf("failed to replay %v feed of subscription %v: %v", feed, subscriptionID, err)
+			}
+			// as the subscriptions of the gateway, the subscription lasts until it is unsubscribed or its connection closed
+			select {
+			case <-replayCtx.Done():
+			case <-conn.DisconnectNotify():
+			}
+			h.unsubscribe(subscriptionID)
+		}()
+	case jsonrpc.RPCUnsubscribe:
+		var params []string
+		if req.Params == nil || json.Unmarshal(*req.Params, &params) != nil || len(params) != 1 {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, "unsubscribe params must hold the subscription ID", conn, req.ID)
+			return
+		}
+		if !h.unsubscribe(params[0]) {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("subscription %v is not found", params[0]), conn, req.ID)
+			return
+		}
+		if err := conn.Reply(ctx, req.ID, true); err != nil {
+			h.log.Errorf("error replying to unsubscribe request of subscription %v: %v", params[0], err)
+		}
+	default:
+		SendErrorMsg(ctx, jsonrpc.MethodNotFound, fmt.Sprintf("method %v is not supported by the replay", req.Method), conn, req.ID)
+	}
+}
+
+// unsubscribe stops the replay of the subscription, returning false if it is not replayed
+func (h *ReplayHandler) unsubscribe(subscriptionID string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	cancel, ok := h.cancels[subscriptionID]
+	if ok {
+		cancel()
+		delete(h.cancels, subscriptionID)
+	}
+	return ok
+}
+
+// replay sends the records of the feed to the subscription, waiting between two records for the time between their
+// captures divided by the speed
+func (h *ReplayHandler) replay(ctx context.Context, conn *jsonrpc2.Conn, subscriptionID string, feed types.FeedType) error {
+	var previous time.Time
+	for _, path := range h.options.Files {
+		reader, err := export.OpenRecords(path)
+		if err != nil {
+			return err
+		}
+		for {
+			record, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				_ = reader.Close()
+				return fmt.Errorf("failed to read %v: %v", path, err)
+			}
+			if record.Feed != feed {
+				continue
+			}
+
+			if h.options.Speed > 0 && !previous.IsZero() && record.Time.After(previous) {
+				timer := h.clock.Timer(time.Duration(float64(record.Time.Sub(previous)) / h.options.Speed))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					_ = reader.Close()
+					return nil
+				case <-conn.DisconnectNotify():
+					timer.Stop()
+					_ = reader.Close()
+					return nil
+				case <-timer.Alert():
+				}
+			}
+			previous = record.Time
+
+			if err = conn.Notify(ctx, string(jsonrpc.RPCSubscribe), replayedResponse{Subscription: subscriptionID, Result: record.Payload}); err != nil {
+				_ = reader.Close()
+				if ctx.Err() != nil || errors.Is(err, jsonrpc2.ErrClosed) {
+					return nil
+				}
+				return err
+			}
+		}
+		if err = reader.Close(); err != nil {
+			h.log.Errorf("failed to close %v: %v", path, err)
+		}
+	}
+	h.log.Infof("replayed %v feed of subscription %v", feed, subscriptionID)
+	return nil
+}
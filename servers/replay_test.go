@@ -0,0 +1,73 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxclient"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "capture-20231016T120002.000.jsonl")
+	require.NoError(t, os.WriteFile(first, []byte(`{"time":"2023-10-16T12:00:00Z","feed":"newTxs","payload":{"txHash":"0x01"}}
+{"time":"2023-10-16T12:00:01Z","feed":"newBlocks","payload":{"hash":"0x02"}}
+`), 0o600))
+	second := filepath.Join(dir, "capture.jsonl")
+	require.NoError(t, os.WriteFile(second, []byte(`{"time":"2023-10-16T12:00:02Z","feed":"newTxs","payload":{"txHash":"0x03"}}
+`), 0o600))
+
+	// the 2 seconds between the txs are replayed in 200ms
+	handler, err := NewReplayHandler(ReplayOptions{Files: []string{first, second}, Speed: 10}, utils.RealClock{})
+	require.NoError(t, err)
+	ws := NewWebsocketRPCServer("", 0, handler)
+	server := httptest.NewServer(http.HandlerFunc(ws.serveWS))
+	defer server.Close()
+
+	client, err := bxclient.DialWS(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws", bxclient.WSOptions{})
+	require.NoError(t, err)
+	defer client.Close()
+
+	sub, err := bxclient.Subscribe[json.RawMessage](ctx, client, types.NewTxsFeed, bxclient.SubscribeOptions{})
+	require.NoError(t, err)
+
+	var received []string
+	var start time.Time
+	for len(received) < 2 {
+		select {
+		case notification := <-sub.Notifications():
+			if start.IsZero() {
+				start = time.Now()
+			}
+			received = append(received, string(notification))
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %v notifications of the capture", len(received))
+		}
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	assert.JSONEq(t, `{"txHash":"0x01"}`, received[0])
+	assert.JSONEq(t, `{"txHash":"0x03"}`, received[1])
+
+	require.NoError(t, sub.Unsubscribe(ctx))
+}
+
+func TestNewReplayHandler(t *testing.T) {
+	_, err := NewReplayHandler(ReplayOptions{}, utils.RealClock{})
+	assert.Error(t, err)
+	_, err = NewReplayHandler(ReplayOptions{Files: []string{"capture.jsonl"}, Speed: -1}, utils.RealClock{})
+	assert.Error(t, err)
+}
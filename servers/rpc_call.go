@@ -1,6 +1,7 @@
 package servers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -12,7 +13,6 @@ import (
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"golang.org/x/sync/errgroup"
 )
 
 // RPCCall represents customer call executed for onBlock feed
@@ -118,7 +118,9 @@ func fillCalls(feedManager *FeedManager, calls map[string]*RPCCall, callIdx int,
 	return err
 }
 
-func handleEthOnBlock(feedManager *FeedManager, block *types.EthBlockNotification, calls map[string]*RPCCall, sendNotification func(notification *types.OnBlockNotification) error) error {
+// handleEthOnBlock runs the onBlock calls of the block and sends their results. The calls stop and no more
+// notifications are sent once ctx is canceled, on the disconnection of the subscriber.
+func handleEthOnBlock(ctx context.Context, feedManager *FeedManager, block *types.EthBlockNotification, calls map[string]*RPCCall, sendNotification func(notification *types.OnBlockNotification) error) error {
 	if len(block.Transactions) > 0 {
 		nodeWS, ok := feedManager.getSyncedWSProvider(block.Source())
 		if !ok {
@@ -133,7 +135,11 @@ func handleEthOnBlock(feedManager *FeedManager, block *types.EthBlockNotificatio
 			wg.Add(1)
 			go func(call *RPCCall) {
 				defer wg.Done()
-				if !call.active {
+				// a call panicking on the response of the node is disabled like a failed call
+				defer feedManager.recoverPanic(panicScopeOnBlockCall, call.commandMethod, func(interface{}) {
+					call.active = false
+				})
+				if !call.active || ctx.Err() != nil {
 					return
 				}
 				tag := hexutil.EncodeUint64(block.Header.GetNumber() + uint64(call.blockOffset))
@@ -141,7 +147,11 @@ func handleEthOnBlock(feedManager *FeedManager, block *types.EthBlockNotificatio
 				if err != nil {
 					return
 				}
-				response, err := nodeWS.CallRPC(call.commandMethod, payload, blockchain.RPCOptions{RetryAttempts: bxgateway.MaxEthOnBlockCallRetries, RetryInterval: bxgateway.EthOnBlockCallRetrySleepInterval})
+				response, err := nodeWS.CallRPC(call.commandMethod, payload, blockchain.RPCOptions{RetryAttempts: bxgateway.MaxEthOnBlockCallRetries, RetryInterval: bxgateway.EthOnBlockCallRetrySleepInterval, Context: ctx})
+				if ctx.Err() != nil {
+					// a call interrupted by the disconnection is not a failed call
+					return
+				}
 				if err != nil {
 					log.Debugf("disabling failed onBlock call %v: %v", call.callName, err)
 					call.active = false
@@ -160,6 +170,10 @@ func handleEthOnBlock(feedManager *FeedManager, block *types.EthBlockNotificatio
 			}(c)
 		}
 		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			log.Debugf("stopped executing onBlock for block %v, %v: %v", block.BlockHash, block.Header.Number, err)
+			return err
+		}
 		taskCompletedNotification := types.NewOnBlockNotification(bxgateway.TaskCompletedEvent, "", blockHeightStr, blockHeightStr, hashStr)
 		err := sendNotification(taskCompletedNotification)
 		if err != nil {
@@ -171,50 +185,100 @@ func handleEthOnBlock(feedManager *FeedManager, block *types.EthBlockNotificatio
 	return nil
 }
 
-// HandleTxReceipts - fetches transaction receipts for transactions in block and sends them to the client
-func HandleTxReceipts(feedManager *FeedManager, block *types.EthBlockNotification) ([]*types.TxReceipt, error) {
+// handleEthOnBlockPages runs the onBlock calls like handleEthOnBlock, but sends the results of the block in pages of
+// pageSize results once all the calls are done, the last page replacing the TaskCompletedEvent
+func handleEthOnBlockPages(ctx context.Context, feedManager *FeedManager, block *types.EthBlockNotification, calls map[string]*RPCCall, pageSize int, sendPage func(page *types.OnBlockPageNotification) error) error {
+	var lock sync.Mutex
+	var results []*types.OnBlockNotification
+	completed := false
+	err := handleEthOnBlock(ctx, feedManager, block, calls, func(notification *types.OnBlockNotification) error {
+		lock.Lock()
+		defer lock.Unlock()
+		if notification.Name == bxgateway.TaskCompletedEvent {
+			completed = true
+			return nil
+		}
+		results = append(results, notification)
+		return nil
+	})
+	if err != nil || !completed {
+		return err
+	}
+
+	for _, page := range types.NewOnBlockPages(results, pageSize, block.Header.Number, block.BlockHash.String()) {
+		if err = sendPage(page); err != nil {
+			log.Errorf("failed to send onBlock page %v of block %v", page.Page, block.Header.Number)
+			return nil
+		}
+	}
+	return nil
+}
+
+// HandleTxReceipts - fetches transaction receipts for transactions in block and sends them to the client, until ctx is canceled
+func HandleTxReceipts(ctx context.Context, feedManager *FeedManager, block *types.EthBlockNotification) ([]*types.TxReceipt, error) {
 	nodeWS, ok := feedManager.getSyncedWSProvider(block.Source())
 	if !ok {
 		return nil, fmt.Errorf("node ws connection is not available")
 	}
 
-	var result []*types.TxReceipt
-	var mu sync.Mutex
-	g := new(errgroup.Group)
-
-	for _, t := range block.Transactions {
-		tx := t
-		g.Go(func() error {
-			hash := tx["hash"]
-			responseTxReceipt, err := nodeWS.FetchTransactionReceipt([]interface{}{hash}, blockchain.RPCOptions{RetryAttempts: bxgateway.MaxEthTxReceiptCallRetries, RetryInterval: bxgateway.EthTxReceiptCallRetrySleepInterval})
-			if err != nil || responseTxReceipt == nil {
-				log.Debugf("failed to fetch transaction receipt for %v in block %v: %v", hash, block.BlockHash, err)
-				return err
-			}
-			responseBlock, err := nodeWS.FetchBlock([]interface{}{responseTxReceipt.(map[string]interface{})["blockNumber"], false}, blockchain.RPCOptions{RetryAttempts: bxgateway.MaxEthOnBlockCallRetries, RetryInterval: bxgateway.EthOnBlockCallRetrySleepInterval})
-			var txsCount int
-			if err == nil && responseBlock != nil {
-				transactions, exist := responseBlock.(map[string]interface{})["transactions"]
-				txsCount = len(transactions.([]interface{}))
-				if !exist {
-					return fmt.Errorf("transactions field doesn't exist when querying the previous epoch block")
-				}
-			}
+	hashes := make([]interface{}, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		hashes[i] = tx["hash"]
+	}
+	responses, err := feedManager.receiptFetchers.Get(nodeWS).Fetch(ctx, nodeWS, hashes, bxgateway.EthTxReceiptCallRetrySleepInterval)
+	if err != nil {
+		log.Debugf("failed to fetch transaction receipts in block %v: %v", block.BlockHash, err)
+		return nil, err
+	}
 
-			receipt := types.NewTxReceipt(responseTxReceipt.(map[string]interface{}), fmt.Sprintf("0x%x", txsCount))
+	// the receipts are the receipts of the transactions of the block
+	txsCount := fmt.Sprintf("0x%x", len(block.Transactions))
+	result := make([]*types.TxReceipt, 0, len(responses))
+	for i, response := range responses {
+		receiptMap, ok := response.(map[string]interface{})
+		if !ok {
+			log.Debugf("failed to fetch transaction receipt for %v in block %v", hashes[i], block.BlockHash)
+			continue
+		}
+		receipt := types.NewTxReceipt(receiptMap, txsCount)
+		receipt.SetComputedFields(block.Header, feedManager.txFirstSeen(receipt.TransactionHash))
+		result = append(result, receipt)
+	}
 
-			mu.Lock()
-			result = append(result, receipt)
-			mu.Unlock()
+	log.Debugf("finished fetching transaction receipts for block %v, %v", block.BlockHash, block.Header.Number)
+	return result, nil
+}
 
-			return nil
-		})
+// HandleContractCreations fetches the receipts of the contract creations of the block, the transactions without to,
+// which hold the addresses of the created contracts
+func HandleContractCreations(ctx context.Context, feedManager *FeedManager, block *types.EthBlockNotification) ([]*types.TxReceipt, error) {
+	var hashes []interface{}
+	for _, tx := range block.Transactions {
+		if _, ok := tx["to"]; !ok {
+			hashes = append(hashes, tx["hash"])
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, nil
 	}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	nodeWS, ok := feedManager.getSyncedWSProvider(block.Source())
+	if !ok {
+		return nil, fmt.Errorf("node ws connection is not available")
 	}
 
-	log.Debugf("finished fetching transaction receipts for block %v, %v", block.BlockHash, block.Header.Number)
-	return result, nil
+	responses, err := feedManager.receiptFetchers.Get(nodeWS).Fetch(ctx, nodeWS, hashes, bxgateway.EthTxReceiptCallRetrySleepInterval)
+	// the creations whose receipts were fetched are still published
+	result := make([]*types.TxReceipt, 0, len(responses))
+	for i, response := range responses {
+		receiptMap, ok := response.(map[string]interface{})
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("failed to fetch transaction receipt for contract creation %v in block %v", hashes[i], block.BlockHash)
+			}
+			continue
+		}
+		result = append(result, types.NewTxReceipt(receiptMap, ""))
+	}
+	return result, err
 }
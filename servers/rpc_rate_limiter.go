@@ -0,0 +1,102 @@
+package servers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// defaultRPCRateLimits are the calls per second allowed to each account of a tier unless overridden by the config,
+// a full second of calls can be made at once
+var defaultRPCRateLimits = config.RPCRateLimits{
+	sdnmessage.ATierUltra:        {config.RateLimitTx: 1000, config.RateLimitBatchTx: 100, config.RateLimitSubscribe: 50, config.RateLimitOnBlockCall: 100},
+	sdnmessage.ATierElite:        {config.RateLimitTx: 500, config.RateLimitBatchTx: 50, config.RateLimitSubscribe: 30, config.RateLimitOnBlockCall: 50},
+	sdnmessage.ATierEnterprise:   {config.RateLimitTx: 200, config.RateLimitBatchTx: 20, config.RateLimitSubscribe: 20, config.RateLimitOnBlockCall: 30},
+	sdnmessage.ATierProfessional: {config.RateLimitTx: 50, config.RateLimitBatchTx: 5, config.RateLimitSubscribe: 10, config.RateLimitOnBlockCall: 10},
+	sdnmessage.ATierDeveloper:    {config.RateLimitTx: 20, config.RateLimitBatchTx: 2, config.RateLimitSubscribe: 5, config.RateLimitOnBlockCall: 5},
+	sdnmessage.ATierIntroductory: {config.RateLimitTx: 5, config.RateLimitBatchTx: 1, config.RateLimitSubscribe: 3, config.RateLimitOnBlockCall: 3},
+}
+
+type rpcRateLimitKey struct {
+	accountID types.AccountID
+	method    config.RateLimitedMethod
+}
+
+// rpcRateLimiter limits the calls of each account with a token bucket per method, sized by the tier of the account
+type rpcRateLimiter struct {
+	clock  utils.Clock
+	limits config.RPCRateLimits
+
+	lock     sync.Mutex
+	limiters map[rpcRateLimitKey]utils.RateLimiter
+}
+
+func newRPCRateLimiter(clock utils.Clock, overrides config.RPCRateLimits) *rpcRateLimiter {
+	limits := make(config.RPCRateLimits, len(defaultRPCRateLimits))
+	for tier, methods := range defaultRPCRateLimits {
+		limits[tier] = make(map[config.RateLimitedMethod]int, len(methods))
+		for method, limit := range methods {
+			limits[tier][method] = limit
+		}
+	}
+	for tier, methods := range overrides {
+		if _, ok := limits[tier]; !ok {
+			limits[tier] = make(map[config.RateLimitedMethod]int, len(methods))
+		}
+		for method, limit := range methods {
+			limits[tier][method] = limit
+		}
+	}
+
+	return &rpcRateLimiter{
+		clock:    clock,
+		limits:   limits,
+		limiters: make(map[rpcRateLimitKey]utils.RateLimiter),
+	}
+}
+
+// AllowCalls returns an error if the account exceeds the rate limit of the method by making the calls
+func (f *FeedManager) AllowCalls(account sdnmessage.Account, method config.RateLimitedMethod, calls int) error {
+	if allowed, limit := f.rateLimiter.allow(account, method, calls); !allowed {
+		return fmt.Errorf("rate limit exceeded, the %v accounts are limited to %v %v calls per second", account.TierName, limit, method)
+	}
+	return nil
+}
+
+// allow takes calls tokens from the bucket of the account and method, returning false and the limit if the bucket
+// runs out. The accounts of an unknown tier are not limited, nor are any accounts on a nil receiver.
+func (l *rpcRateLimiter) allow(account sdnmessage.Account, method config.RateLimitedMethod, calls int) (bool, int) {
+	if l == nil {
+		return true, 0
+	}
+	limit := l.limits[account.TierName][method]
+	if limit <= 0 {
+		return true, 0
+	}
+
+	limiter := l.limiter(rpcRateLimitKey{accountID: account.AccountID, method: method}, limit)
+	for i := 0; i < calls; i++ {
+		if allowed, _ := limiter.Take(); !allowed {
+			return false, limit
+		}
+	}
+	return true, limit
+}
+
+// limiter returns the bucket of the key, a new full bucket replaces it if the limit changed along with the tier
+func (l *rpcRateLimiter) limiter(key rpcRateLimitKey, limit int) utils.RateLimiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	limiter, ok := l.limiters[key]
+	if !ok || limiter.Limit() != uint64(limit) {
+		limiter = utils.NewLeakyBucketRateLimiter(l.clock, uint64(limit), time.Second)
+		l.limiters[key] = limiter
+	}
+	return limiter
+}
@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRPCRateLimiter(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	overrides, err := config.ParseRPCRateLimits([]string{"Developer:blxr_tx=2", "Developer:subscribe=0"})
+	require.NoError(t, err)
+	f := &FeedManager{rateLimiter: newRPCRateLimiter(clock, overrides)}
+
+	developer := sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "a", TierName: sdnmessage.ATierDeveloper}}
+	assert.NoError(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+	assert.NoError(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+	assert.Error(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+	// the methods and accounts have their own buckets
+	assert.NoError(t, f.AllowCalls(developer, config.RateLimitBatchTx, 1))
+	other := developer
+	other.AccountID = "b"
+	assert.NoError(t, f.AllowCalls(other, config.RateLimitTx, 1))
+
+	// the bucket refills over the second
+	clock.IncTime(500 * time.Millisecond)
+	assert.NoError(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+	assert.Error(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+
+	// every call registered by an onBlock subscription takes a token
+	limit := defaultRPCRateLimits[sdnmessage.ATierDeveloper][config.RateLimitOnBlockCall]
+	assert.Error(t, f.AllowCalls(developer, config.RateLimitOnBlockCall, limit+1))
+
+	// 0 removes the limit, as does an unknown tier
+	for i := 0; i < 100; i++ {
+		require.NoError(t, f.AllowCalls(developer, config.RateLimitSubscribe, 1))
+		require.NoError(t, f.AllowCalls(sdnmessage.Account{}, config.RateLimitTx, 1))
+	}
+
+	// a tier upgrade grants a full bucket of the new limit
+	developer.TierName = sdnmessage.ATierEnterprise
+	assert.NoError(t, f.AllowCalls(developer, config.RateLimitTx, 1))
+
+	assert.NoError(t, (&FeedManager{}).AllowCalls(developer, config.RateLimitTx, 1))
+}
+
+func TestParseRPCRateLimits(t *testing.T) {
+	limits, err := config.ParseRPCRateLimits([]string{"Enterprise:blxr_tx=10", "Enterprise:onblock_call=5"})
+	require.NoError(t, err)
+	assert.Equal(t, config.RPCRateLimits{sdnmessage.ATierEnterprise: {config.RateLimitTx: 10, config.RateLimitOnBlockCall: 5}}, limits)
+
+	for _, value := range []string{"Enterprise:blxr_tx", "blxr_tx=10", "Gold:blxr_tx=10", "Enterprise:eth_call=10", "Enterprise:blxr_tx=-1"} {
+		_, err = config.ParseRPCRateLimits([]string{value})
+		assert.Error(t, err, value)
+	}
+}
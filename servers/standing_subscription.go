@@ -0,0 +1,369 @@
+package servers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/zhouzhuojie/conditions"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// StandingSinkExport archives the notifications under the account of the gateway, it requires the feed export
+	StandingSinkExport = "export"
+	// StandingSinkWebhook posts each notification to the URL of the sink
+	StandingSinkWebhook = "webhook"
+	// StandingSinkFile appends the notifications to the rotated file of the sink, for the captures of incident analysis
+	StandingSinkFile = "file"
+
+	// StandingFormatJSON writes a JSON line per notification to a file sink, the archive record of the notification
+	// with the time it was written, which the replay serves the capture with
+	StandingFormatJSON = "json"
+	// StandingFormatProtobuf writes the gRPC message of each notification to a file sink, prefixed by its varint length
+	StandingFormatProtobuf = "protobuf"
+
+	standingWebhookTimeout = 5 * time.Second
+	standingChannelSize    = 1000
+)
+
+// StandingSubscription is a feed subscription declared by the operator, which the gateway serves from startup
+// without a connected client by delivering its notifications to a sink
+type StandingSubscription struct {
+	Name    string         `json:"name"`
+	Feed    types.FeedType `json:"feed"`
+	Include []string       `json:"include"`
+	Filters string         `json:"filters"`
+	Sink    StandingSink   `json:"sink"`
+}
+
+// StandingSink is where the notifications of a standing subscription are delivered
+type StandingSink struct {
+	// Type is either export, webhook or file
+	Type string `json:"type"`
+	// URL the notifications are posted to by a webhook sink
+	URL string `json:"url"`
+	// Path of the file the notifications are appended to by a file sink
+	Path string `json:"path"`
+	// Format of the records of a file sink, json by default or protobuf
+	Format string `json:"format"`
+	// MaxSizeMB and RotateMinutes rotate the file of a file sink once it is larger or older, 0 disables them
+	MaxSizeMB     int `json:"max_size_mb"`
+	RotateMinutes int `json:"rotate_minutes"`
+	// Compress gzips the rotated files of a file sink
+	Compress bool `json:"compress"`
+}
+
+// LoadStandingSubscriptions reads and validates the standing subscriptions of a JSON file holding a list of them
+func LoadStandingSubscriptions(path string) ([]StandingSubscription, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open standing subscriptions file: %v", err)
+	}
+	var subscriptions []StandingSubscription
+	if err = json.Unmarshal(contents, &subscriptions); err != nil {
+		return nil, fmt.Errorf("failed to decode standing subscriptions file: %v", err)
+	}
+
+	names := make(map[string]struct{}, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if _, err = subscription.request(); err != nil {
+			return nil, fmt.Errorf("invalid standing subscription %q: %v", subscription.Name, err)
+		}
+		if _, ok := names[subscription.Name]; ok {
+			return nil, fmt.Errorf("standing subscription %q is declared more than once", subscription.Name)
+		}
+		names[subscription.Name] = struct{}{}
+	}
+	return subscriptions, nil
+}
+
+// request validates the subscription and builds the client request it is served with
+func (s StandingSubscription) request() (*clientReq, error) {
+	if s.Name == "" {
+		return nil, errors.New("name is missing")
+	}
+	if _, ok := availableFeedsMap[s.Feed]; !ok {
+		return nil, fmt.Errorf("unsupported feed %v, possible feeds are: %v", s.Feed, availableFeeds)
+	}
+	// the onBlock calls, the logs filters and the tracked txs are declared by the subscriptions, which do not support
+	// them yet
+	if s.Feed == types.OnBlockFeed || s.Feed == types.LogsFeed || s.Feed == types.TransactionStatusFeed {
+		return nil, fmt.Errorf("%v feed is not supported", s.Feed)
+	}
+	switch s.Sink.Type {
+	case StandingSinkExport:
+	case StandingSinkWebhook:
+		if s.Sink.URL == "" {
+			return nil, errors.New("webhook sink URL is missing")
+		}
+	case StandingSinkFile:
+		if s.Sink.Path == "" {
+			return nil, errors.New("file sink path is missing")
+		}
+		if s.Sink.MaxSizeMB < 0 || s.Sink.RotateMinutes < 0 {
+			return nil, errors.New("file sink rotation must be greater than or equal to 0")
+		}
+		switch s.Sink.Format {
+		case "", StandingFormatJSON:
+		case StandingFormatProtobuf:
+			if _, ok := protobufStandingFeeds[s.Feed]; !ok {
+				return nil, fmt.Errorf("%v format is not supported by %v feed", StandingFormatProtobuf, s.Feed)
+			}
+		default:
+			return nil, fmt.Errorf("invalid file sink format %q, must be %v or %v", s.Sink.Format, StandingFormatJSON, StandingFormatProtobuf)
+		}
+	default:
+		return nil, fmt.Errorf("invalid sink type %q, must be %v, %v or %v", s.Sink.Type, StandingSinkExport, StandingSinkWebhook, StandingSinkFile)
+	}
+
+	includes, err := validateIncludeParam(s.Feed, s.Include, true)
+	if err != nil {
+		return nil, err
+	}
+	var expr conditions.Expr
+	if s.Filters != "" {
+		expr, err = validateFilters(s.Filters, true)
+		if err != nil {
+			return nil, fmt.Errorf("error creating Filters: %w", err)
+		}
+	}
+	return &clientReq{feed: s.Feed, includes: includes, expr: expr}, nil
+}
+
+// standingSubscription serves a StandingSubscription, dropping the notifications its sink cannot keep up with
+type standingSubscription struct {
+	StandingSubscription
+	request  *clientReq
+	feed     chan types.Notification
+	delivery *deliveryStats
+	client   *http.Client
+	file     *export.RotatingFile
+}
+
+func (f *FeedManager) newStandingSubscriptions(subscriptions []StandingSubscription) []*standingSubscription {
+	standing := make([]*standingSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		request, err := subscription.request()
+		if err != nil {
+			f.log.Errorf("not serving standing subscription %q: %v", subscription.Name, err)
+			continue
+		}
+		// the filter files of the gateway are only known to the feed manager
+		request.operations, err = newFilterOperations(request.expr, f.filterFiles)
+		if err != nil {
+			f.log.Errorf("not serving standing subscription %q: %v", subscription.Name, err)
+			continue
+		}
+		var file *export.RotatingFile
+		if subscription.Sink.Type == StandingSinkFile {
+			file, err = export.OpenRotatingFile(subscription.Sink.Path, export.RotationConfig{
+				MaxSize:  int64(subscription.Sink.MaxSizeMB) * 1024 * 1024,
+				MaxAge:   time.Duration(subscription.Sink.RotateMinutes) * time.Minute,
+				Compress: subscription.Sink.Compress,
+			}, utils.RealClock{})
+			if err != nil {
+				f.log.Errorf("not serving standing subscription %q: failed to open file sink: %v", subscription.Name, err)
+				continue
+			}
+		}
+		request.delivery = newDeliveryStats(f.metrics.forFeed(request.feed))
+		standing = append(standing, &standingSubscription{
+			StandingSubscription: subscription,
+			request:              request,
+			feed:                 make(chan types.Notification, standingChannelSize),
+			delivery:             request.delivery,
+			client:               &http.Client{Timeout: standingWebhookTimeout},
+			file:                 file,
+		})
+	}
+	return standing
+}
+
+// publishStanding offers the notification to the standing subscriptions of its feed
+func (f *FeedManager) publishStanding(notification types.Notification) {
+	for _, subscription := range f.standing {
+		if subscription.Feed != notification.NotificationType() {
+			continue
+		}
+		select {
+		case subscription.feed <- notification:
+		default:
+			subscription.delivery.drop()
+		}
+	}
+}
+
+// standingFeedExists returns true if a standing subscription is served on the feed
+func (f *FeedManager) standingFeedExists(feed types.FeedType) bool {
+	for _, subscription := range f.standing {
+		if subscription.Feed == feed {
+			return true
+		}
+	}
+	return false
+}
+
+// serveStanding delivers the notifications of the standing subscription to its sink until the context is done
+func (f *FeedManager) serveStanding(ctx context.Context, subscription *standingSubscription) {
+	f.log.Infof("serving standing subscription %q to %v with includes %v and filter [%v] to %v sink",
+		subscription.Name, subscription.Feed, subscription.request.includes, subscription.Filters, subscription.Sink.Type)
+	defer func() {
+		if subscription.file != nil {
+			if err := subscription.file.Close(); err != nil {
+				f.log.Errorf("failed to close file sink of standing subscription %q: %v", subscription.Name, err)
+			}
+		}
+		delivery := subscription.delivery.summary()
+		f.log.Infof("standing subscription %q delivered %v notifications, dropped %v, sent %v bytes",
+			subscription.Name, delivery.Delivered, delivery.Dropped, delivery.BytesSent)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-subscription.feed:
+			subscription.delivery.receive()
+			if subscription.Sink.Format == StandingFormatProtobuf {
+				for _, message := range f.standingMessages(subscription.request, notification) {
+					f.writeStanding(subscription, message)
+				}
+				continue
+			}
+			for _, content := range f.standingContents(subscription.request, notification) {
+				f.sendStanding(subscription, content)
+			}
+		}
+	}
+}
+
+// standingContents builds the contents of the notification according to the request of the standing subscription
+func (f *FeedManager) standingContents(request *clientReq, notification types.Notification) []interface{} {
+	var tx *types.NewTransactionNotification
+	switch n := notification.(type) {
+	case *types.NewTransactionNotification:
+		tx = n
+	case *types.PendingTransactionNotification:
+		tx = &n.NewTransactionNotification
+	case *types.TxReceiptsNotification:
+		content := n.WithFields(request.includes).(*types.TxReceiptsNotification)
+		contents := make([]interface{}, 0, len(content.Receipts))
+		for _, receipt := range content.Receipts {
+			contents = append(contents, receipt)
+		}
+		return contents
+	default:
+		return []interface{}{notification.WithFields(request.includes)}
+	}
+
+	if result := filterAndInclude(request, tx, "", f.accountModel.AccountID, f.clockSkew()); result != nil {
+		return []interface{}{result}
+	}
+	return nil
+}
+
+func (f *FeedManager) sendStanding(subscription *standingSubscription, content interface{}) {
+	switch subscription.Sink.Type {
+	case StandingSinkExport:
+		f.exporter.Export(f.accountModel.AccountID, subscription.Feed, content)
+		subscription.delivery.sent(1, 0)
+		return
+	case StandingSinkFile:
+		f.writeStanding(subscription, content)
+		return
+	}
+
+	body, err := json.Marshal(content)
+	if err != nil {
+		f.log.Errorf("failed to marshal %v notification of standing subscription %q: %v", subscription.Feed, subscription.Name, err)
+		return
+	}
+	resp, err := subscription.client.Post(subscription.Sink.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		subscription.delivery.drop()
+		f.log.Errorf("failed to post %v notification of standing subscription %q to the webhook: %v", subscription.Feed, subscription.Name, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		subscription.delivery.drop()
+		f.log.Errorf("failed to post %v notification of standing subscription %q to the webhook: status %v", subscription.Feed, subscription.Name, resp.Status)
+		return
+	}
+	subscription.delivery.sent(1, len(body))
+}
+
+// protobufStandingFeeds are the feeds whose notifications have a gRPC message, which a file sink can write
+var protobufStandingFeeds = map[types.FeedType]struct{}{
+	types.NewTxsFeed:     {},
+	types.PendingTxsFeed: {},
+	types.NewBlocksFeed:  {},
+	types.BDNBlocksFeed:  {},
+	types.TxReceiptsFeed: {},
+}
+
+// standingMessages builds the gRPC messages of the notification, the txs are filtered by the standing subscription
+// and the blocks and the receipts hold its includes
+func (f *FeedManager) standingMessages(request *clientReq, notification types.Notification) []proto.Message {
+	switch n := notification.(type) {
+	case *types.NewTransactionNotification, *types.PendingTransactionNotification:
+		var txs []*pb.Tx
+		processTx(request, notification, &txs, "", f.accountModel.AccountID, request.feed, true, f.clockSkew())
+		messages := make([]proto.Message, 0, len(txs))
+		for _, tx := range txs {
+			messages = append(messages, tx)
+		}
+		return messages
+	case *types.EthBlockNotification:
+		block := n.WithFields(request.includes).(*types.EthBlockNotification)
+		return []proto.Message{(&GrpcHandler{}).generateBlockReply(block)}
+	case *types.TxReceiptsNotification:
+		content := n.WithFields(request.includes).(*types.TxReceiptsNotification)
+		messages := make([]proto.Message, 0, len(content.Receipts))
+		for _, receipt := range content.Receipts {
+			messages = append(messages, generateTxReceiptReply(receipt))
+		}
+		return messages
+	}
+	return nil
+}
+
+// writeStanding appends the content to the file sink, the JSON line of its archive record or a gRPC message prefixed
+// by its varint length
+func (f *FeedManager) writeStanding(subscription *standingSubscription, content interface{}) {
+	var record []byte
+	var err error
+	if message, ok := content.(proto.Message); ok {
+		var encoded []byte
+		encoded, err = proto.Marshal(message)
+		record = append(binary.AppendUvarint(nil, uint64(len(encoded))), encoded...)
+	} else {
+		var payload []byte
+		payload, err = json.Marshal(content)
+		if err == nil {
+			record, err = json.Marshal(export.Record{Time: time.Now(), Feed: subscription.Feed, Payload: payload})
+			record = append(record, '\n')
+		}
+	}
+	if err != nil {
+		f.log.Errorf("failed to marshal %v notification of standing subscription %q: %v", subscription.Feed, subscription.Name, err)
+		return
+	}
+	if _, err = subscription.file.Write(record); err != nil {
+		subscription.delivery.drop()
+		f.log.Errorf("failed to write %v notification of standing subscription %q to the file sink: %v", subscription.Feed, subscription.Name, err)
+		return
+	}
+	subscription.delivery.sent(1, len(record))
+}
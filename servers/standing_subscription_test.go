@@ -0,0 +1,94 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	pb "github.com/bloXroute-Labs/gateway/v2/protobuf"
+	"github.com/bloXroute-Labs/gateway/v2/services/export"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLoadStandingSubscriptions(t *testing.T) {
+	write := func(contents string) string {
+		path := filepath.Join(t.TempDir(), "standing.json")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		return path
+	}
+
+	subscriptions, err := LoadStandingSubscriptions(write(`[
+		{"name": "txs", "feed": "newTxs", "include": ["tx_hash"], "filters": "value > 1", "sink": {"type": "export"}},
+		{"name": "blocks", "feed": "bdnBlocks", "sink": {"type": "webhook", "url": "http://localhost:8080"}},
+		{"name": "capture", "feed": "pendingTxs", "sink": {"type": "file", "path": "/tmp/capture.bin", "format": "protobuf", "max_size_mb": 100, "rotate_minutes": 10, "compress": true}}
+	]`))
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 3)
+	assert.Equal(t, types.NewTxsFeed, subscriptions[0].Feed)
+	assert.Equal(t, StandingSinkWebhook, subscriptions[1].Sink.Type)
+
+	request, err := subscriptions[0].request()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tx_hash"}, request.includes)
+	assert.NotNil(t, request.expr)
+
+	invalid := map[string]string{
+		"missing name":   `[{"feed": "newTxs", "sink": {"type": "export"}}]`,
+		"unknown feed":   `[{"name": "a", "feed": "oldTxs", "sink": {"type": "export"}}]`,
+		"onBlock":        `[{"name": "a", "feed": "ethOnBlock", "sink": {"type": "export"}}]`,
+		"unknown sink":   `[{"name": "a", "feed": "newTxs", "sink": {"type": "kafka"}}]`,
+		"webhook no url": `[{"name": "a", "feed": "newTxs", "sink": {"type": "webhook"}}]`,
+		"file no path":   `[{"name": "a", "feed": "newTxs", "sink": {"type": "file"}}]`,
+		"file format":    `[{"name": "a", "feed": "newTxs", "sink": {"type": "file", "path": "a.jsonl", "format": "csv"}}]`,
+		"file protobuf":  `[{"name": "a", "feed": "newBeaconBlocks", "sink": {"type": "file", "path": "a.bin", "format": "protobuf"}}]`,
+		"file rotation":  `[{"name": "a", "feed": "newTxs", "sink": {"type": "file", "path": "a.jsonl", "max_size_mb": -1}}]`,
+		"bad filters":    `[{"name": "a", "feed": "newTxs", "filters": "(from = 0xaa", "sink": {"type": "export"}}]`,
+		"duplicate":      `[{"name": "a", "feed": "newTxs", "sink": {"type": "export"}}, {"name": "a", "feed": "pendingTxs", "sink": {"type": "export"}}]`,
+	}
+	for name, contents := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadStandingSubscriptions(write(contents))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStandingFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	file, err := export.OpenRotatingFile(path, export.RotationConfig{}, utils.RealClock{})
+	require.NoError(t, err)
+	fm := &FeedManager{log: log.WithField("component", "feedManager")}
+	subscription := &standingSubscription{
+		StandingSubscription: StandingSubscription{Name: "capture", Feed: types.NewTxsFeed, Sink: StandingSink{Type: StandingSinkFile, Path: path}},
+		file:                 file,
+	}
+
+	fm.sendStanding(subscription, map[string]string{"txHash": "0x01"})
+	fm.writeStanding(subscription, &pb.Tx{RawTx: []byte{0xf8, 0x6b}})
+	require.NoError(t, file.Close())
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	line, rest, found := bytes.Cut(contents, []byte("\n"))
+	require.True(t, found)
+	var record export.Record
+	require.NoError(t, json.Unmarshal(line, &record))
+	assert.Equal(t, types.NewTxsFeed, record.Feed)
+	assert.False(t, record.Time.IsZero())
+	assert.JSONEq(t, `{"txHash": "0x01"}`, string(record.Payload))
+
+	size, n := binary.Uvarint(rest)
+	require.Positive(t, n)
+	require.Len(t, rest[n:], int(size))
+	var tx pb.Tx
+	require.NoError(t, proto.Unmarshal(rest[n:], &tx))
+	assert.Equal(t, []byte{0xf8, 0x6b}, tx.RawTx)
+}
@@ -0,0 +1,57 @@
+package servers
+
+import (
+	"fmt"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/zhouzhuojie/conditions"
+)
+
+// connectionSubscriptions returns the number of subscriptions of the websocket connection
+func (f *FeedManager) connectionSubscriptions(conn *jsonrpc2.Conn) int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	count := 0
+	for _, clientSub := range f.idToClientSubscription {
+		if clientSub.connection == conn {
+			count++
+		}
+	}
+	return count
+}
+
+// filterComplexity is the number of conditions of the filters, 0 without filters
+func filterComplexity(expr conditions.Expr) int {
+	if expr == nil {
+		return 0
+	}
+	return len(expr.Args())
+}
+
+// checkSubscriptionLimits verifies the subscriptions of a subscribe request against the limits of the tier of the
+// account, so a client cannot open unbounded subscriptions on a connection or evaluate unbounded filters
+func (h *handlerObj) checkSubscriptionLimits(conn *jsonrpc2.Conn, requests ...*clientReq) error {
+	// the limits are not applied to the customer running the gateway
+	if h.connectionAccount.AccountID == h.FeedManager.accountModel.AccountID {
+		return nil
+	}
+
+	maxSubscriptions := int(h.connectionAccount.MaxSubscriptionsPerConnection.MsgQuota.Limit)
+	if maxSubscriptions > 0 {
+		if count := h.FeedManager.connectionSubscriptions(conn); count+len(requests) > maxSubscriptions {
+			return fmt.Errorf("connection has %v subscriptions, account %v is limited to %v subscriptions per connection",
+				count, h.connectionAccount.AccountID, maxSubscriptions)
+		}
+	}
+
+	maxComplexity := int(h.connectionAccount.MaxFilterComplexity.MsgQuota.Limit)
+	if maxComplexity > 0 {
+		for _, request := range requests {
+			if complexity := filterComplexity(request.expr); complexity > maxComplexity {
+				return fmt.Errorf("filters of %v feed have %v conditions, account %v is limited to %v conditions",
+					request.feed, complexity, h.connectionAccount.AccountID, maxComplexity)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package servers
+
+import (
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSubscriptionLimits(t *testing.T) {
+	conn, otherConn := &jsonrpc2.Conn{}, &jsonrpc2.Conn{}
+	fm := &FeedManager{
+		accountModel: sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "gw"}},
+		idToClientSubscription: map[string]ClientSubscription{
+			"1": {connection: conn},
+			"2": {connection: otherConn},
+		},
+	}
+	account := sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "a"}}
+	account.MaxSubscriptionsPerConnection.MsgQuota.Limit = 2
+	account.MaxFilterComplexity.MsgQuota.Limit = 2
+	h := &handlerObj{FeedManager: fm, connectionAccount: account}
+
+	expr, err := validateFilters("to = 0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48 and value > 1000", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, filterComplexity(expr))
+	assert.NoError(t, h.checkSubscriptionLimits(conn, &clientReq{feed: types.NewTxsFeed, expr: expr}))
+	assert.Error(t, h.checkSubscriptionLimits(conn, &clientReq{feed: types.NewTxsFeed}, &clientReq{feed: types.NewBlocksFeed}))
+
+	expr, err = validateFilters("to = 0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48 or value > 1000 or gas_price > 10", false)
+	require.NoError(t, err)
+	assert.Error(t, h.checkSubscriptionLimits(conn, &clientReq{feed: types.NewTxsFeed, expr: expr}))
+
+	// 0 means no limit, and the customer running the gateway is not limited
+	h.connectionAccount = sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: "a"}}
+	assert.NoError(t, h.checkSubscriptionLimits(conn, &clientReq{feed: types.NewTxsFeed, expr: expr}, &clientReq{feed: types.NewBlocksFeed}))
+	account.AccountID = "gw"
+	h.connectionAccount = account
+	assert.NoError(t, h.checkSubscriptionLimits(conn, &clientReq{feed: types.NewTxsFeed, expr: expr}, &clientReq{feed: types.NewBlocksFeed}))
+}
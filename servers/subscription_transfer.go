@@ -0,0 +1,190 @@
+package servers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// subscriptionTransfer describes a subscription which can be adopted by another connection of the same account
+type subscriptionTransfer struct {
+	token          string
+	subscriptionID string
+	accountID      types.AccountID
+	expiry         *time.Timer
+}
+
+// setSubscriptionRequest stores the parsed client request so the subscription can be served by another connection
+func (f *FeedManager) setSubscriptionRequest(subscriptionID string, request *clientReq) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists {
+		return
+	}
+	clientSub.request = request
+	f.idToClientSubscription[subscriptionID] = clientSub
+}
+
+// CreateSubscriptionTransferToken returns a token which allows another connection of the same account to adopt the subscription
+func (f *FeedManager) CreateSubscriptionTransferToken(subscriptionID string, accountID types.AccountID) (string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists {
+		return "", fmt.Errorf("subscription %v was not found", subscriptionID)
+	}
+	if clientSub.AccountID != accountID {
+		return "", fmt.Errorf("subscription %v does not belong to account %v", subscriptionID, accountID)
+	}
+	if clientSub.feedConnectionType != types.WebSocketFeed || clientSub.request == nil {
+		return "", fmt.Errorf("subscription %v can not be transferred", subscriptionID)
+	}
+
+	return f.subscriptionTransferToken(subscriptionID, accountID), nil
+}
+
+// connectionTransferTokens returns a transfer token for each subscription served by the connection which can be
+// transferred, keyed by subscription ID
+func (f *FeedManager) connectionTransferTokens(conn *jsonrpc2.Conn) map[string]string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	tokens := make(map[string]string)
+	for subscriptionID, clientSub := range f.idToClientSubscription {
+		if clientSub.connection != conn || clientSub.feedConnectionType != types.WebSocketFeed || clientSub.request == nil {
+			continue
+		}
+		tokens[subscriptionID] = f.subscriptionTransferToken(subscriptionID, clientSub.AccountID)
+	}
+	return tokens
+}
+
+// subscriptionTransferToken returns the existing transfer token of the subscription or creates a new one, should be
+// called with the lock held
+func (f *FeedManager) subscriptionTransferToken(subscriptionID string, accountID types.AccountID) string {
+	if transfer := f.subscriptionTransferByID(subscriptionID); transfer != nil {
+		return transfer.token
+	}
+
+	token := utils.GenerateUUID()
+	f.subscriptionTransfers[token] = &subscriptionTransfer{
+		token:          token,
+		subscriptionID: subscriptionID,
+		accountID:      accountID,
+	}
+	f.log.Debugf("created transfer token for subscription %v of account %v", subscriptionID, accountID)
+
+	return token
+}
+
+// AdoptSubscription moves the subscription referenced by the transfer token to the given connection. The previous
+// owner stops reading the feed channel, so no notification is lost or delivered twice during the transfer.
+func (f *FeedManager) AdoptSubscription(token string, conn *jsonrpc2.Conn, ci types.ClientInfo) (*ClientSubscriptionHandlingInfo, *clientReq, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	transfer, ok := f.subscriptionTransfers[token]
+	if !ok {
+		return nil, nil, fmt.Errorf("transfer token %v is invalid or expired", token)
+	}
+	if transfer.accountID != ci.AccountID {
+		return nil, nil, fmt.Errorf("transfer token %v does not belong to account %v", token, ci.AccountID)
+	}
+
+	if transfer.expiry != nil {
+		transfer.expiry.Stop()
+	}
+	delete(f.subscriptionTransfers, token)
+
+	clientSub, exists := f.idToClientSubscription[transfer.subscriptionID]
+	if !exists {
+		return nil, nil, fmt.Errorf("subscription %v was not found", transfer.subscriptionID)
+	}
+
+	close(clientSub.handOff)
+	clientSub.handOff = make(chan struct{})
+	clientSub.connection = conn
+	clientSub.RemoteAddress = ci.RemoteAddress
+	clientSub.MetaInfo = ci.MetaInfo
+	f.idToClientSubscription[transfer.subscriptionID] = clientSub
+
+	f.log.Infof("%v adopted subscription %v to %v", ci.RemoteAddress, transfer.subscriptionID, clientSub.feedType)
+
+	handlingInfo := ClientSubscriptionHandlingInfo{
+		SubscriptionID: transfer.subscriptionID,
+		FeedChan:       clientSub.feed,
+		ErrMsgChan:     clientSub.errMsgChan,
+		HandOffChan:    clientSub.handOff,
+		delivery:       clientSub.delivery,
+	}
+	return &handlingInfo, clientSub.request, nil
+}
+
+// releaseSubscription is called once a connection stops serving a subscription. A subscription with a pending
+// transfer token stays alive for bxgateway.SubscriptionTransferGracePeriod, any other subscription is removed.
+func (f *FeedManager) releaseSubscription(subscriptionID string, handOff chan struct{}) {
+	f.lock.Lock()
+	clientSub, exists := f.idToClientSubscription[subscriptionID]
+	if !exists || clientSub.handOff != handOff {
+		// already removed or served by another connection
+		f.lock.Unlock()
+		return
+	}
+
+	transfer := f.subscriptionTransferByID(subscriptionID)
+	if transfer == nil {
+		f.lock.Unlock()
+		_ = f.Unsubscribe(subscriptionID, false, "")
+		return
+	}
+
+	clientSub.connection = nil
+	f.idToClientSubscription[subscriptionID] = clientSub
+	transfer.expiry = time.AfterFunc(bxgateway.SubscriptionTransferGracePeriod, func() {
+		f.expireSubscriptionTransfer(transfer.token)
+	})
+	f.lock.Unlock()
+
+	f.log.Infof("subscription %v is detached and waiting to be adopted for %v", subscriptionID, bxgateway.SubscriptionTransferGracePeriod)
+}
+
+func (f *FeedManager) expireSubscriptionTransfer(token string) {
+	f.lock.RLock()
+	transfer, ok := f.subscriptionTransfers[token]
+	f.lock.RUnlock()
+	if !ok {
+		return
+	}
+
+	f.log.Infof("transfer of subscription %v expired", transfer.subscriptionID)
+	_ = f.Unsubscribe(transfer.subscriptionID, false, "")
+}
+
+// subscriptionTransferByID should be called with the lock held
+func (f *FeedManager) subscriptionTransferByID(subscriptionID string) *subscriptionTransfer {
+	for _, transfer := range f.subscriptionTransfers {
+		if transfer.subscriptionID == subscriptionID {
+			return transfer
+		}
+	}
+	return nil
+}
+
+// removeSubscriptionTransfer should be called with the lock held
+func (f *FeedManager) removeSubscriptionTransfer(subscriptionID string) {
+	transfer := f.subscriptionTransferByID(subscriptionID)
+	if transfer == nil {
+		return
+	}
+	if transfer.expiry != nil {
+		transfer.expiry.Stop()
+	}
+	delete(f.subscriptionTransfers, transfer.token)
+}
@@ -0,0 +1,107 @@
+package servers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth"
+	"github.com/bloXroute-Labs/gateway/v2/blockchain/eth/test"
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFeedManager() *FeedManager {
+	gwAccount, _ := getMockCustomerAccountModel("gw")
+	_, blockchainPeersInfo := test.GenerateBlockchainPeersInfo(1)
+	return NewFeedManager(context.Background(), bxmock.MockBxListener{}, make(chan types.Notification), services.NewNoOpSubscriptionServices(),
+		types.NetworkNum(1), 1, types.NodeID("nodeID"),
+		eth.NewEthWSManager(blockchainPeersInfo, eth.NewMockWSProvider, bxgateway.WSProviderTimeout, false),
+		gwAccount, getMockCustomerAccountModel, "", "", config.Bx{}, statistics.NoStats{}, nil, nil, FeedManagerOptions{})
+}
+
+func TestSubscriptionTransfer(t *testing.T) {
+	fm := newTestFeedManager()
+
+	ci := types.ClientInfo{RemoteAddress: "127.0.0.1:1000", AccountID: "a"}
+	sub, err := fm.SubscribeClient(types.NewTxsFeed, types.WebSocketFeed, nil, ci, types.ReqOptions{}, false)
+	require.NoError(t, err)
+
+	// request is required in order to serve the subscription on another connection
+	_, err = fm.CreateSubscriptionTransferToken(sub.SubscriptionID, "a")
+	assert.Error(t, err)
+
+	request := &clientReq{feed: types.NewTxsFeed, includes: []string{"tx_hash"}}
+	fm.setSubscriptionRequest(sub.SubscriptionID, request)
+
+	_, err = fm.CreateSubscriptionTransferToken(sub.SubscriptionID, "b")
+	assert.Error(t, err)
+
+	token, err := fm.CreateSubscriptionTransferToken(sub.SubscriptionID, "a")
+	require.NoError(t, err)
+
+	sameToken, err := fm.CreateSubscriptionTransferToken(sub.SubscriptionID, "a")
+	require.NoError(t, err)
+	assert.Equal(t, token, sameToken)
+
+	// connection is gone, subscription waits to be adopted
+	fm.releaseSubscription(sub.SubscriptionID, sub.HandOffChan)
+	assert.True(t, fm.SubscriptionExists(sub.SubscriptionID))
+
+	_, _, err = fm.AdoptSubscription(token, nil, types.ClientInfo{RemoteAddress: "127.0.0.2:1000", AccountID: "b"})
+	assert.Error(t, err)
+
+	adopted, adoptedRequest, err := fm.AdoptSubscription(token, nil, types.ClientInfo{RemoteAddress: "127.0.0.2:1000", AccountID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, sub.SubscriptionID, adopted.SubscriptionID)
+	assert.Equal(t, sub.FeedChan, adopted.FeedChan)
+	assert.Equal(t, request, adoptedRequest)
+
+	select {
+	case <-sub.HandOffChan:
+	default:
+		assert.Fail(t, "previous owner should be notified about the hand off")
+	}
+
+	// token can be used only once
+	_, _, err = fm.AdoptSubscription(token, nil, types.ClientInfo{RemoteAddress: "127.0.0.2:1000", AccountID: "a"})
+	assert.Error(t, err)
+
+	// previous owner releasing the subscription must not affect the new owner
+	fm.releaseSubscription(sub.SubscriptionID, sub.HandOffChan)
+	assert.True(t, fm.SubscriptionExists(sub.SubscriptionID))
+
+	// no transfer token, subscription is removed
+	fm.releaseSubscription(adopted.SubscriptionID, adopted.HandOffChan)
+	assert.False(t, fm.SubscriptionExists(sub.SubscriptionID))
+}
+
+func TestConnectionTransferTokens(t *testing.T) {
+	fm := newTestFeedManager()
+
+	ci := types.ClientInfo{RemoteAddress: "127.0.0.1:1000", AccountID: "a"}
+	sub, err := fm.SubscribeClient(types.NewTxsFeed, types.WebSocketFeed, nil, ci, types.ReqOptions{}, false)
+	require.NoError(t, err)
+	fm.setSubscriptionRequest(sub.SubscriptionID, &clientReq{feed: types.NewTxsFeed, includes: []string{"tx_hash"}})
+
+	// subscriptions without a request can not be handed off
+	_, err = fm.SubscribeClient(types.NewBlocksFeed, types.WebSocketFeed, nil, ci, types.ReqOptions{}, false)
+	require.NoError(t, err)
+
+	tokens := fm.connectionTransferTokens(nil)
+	require.Len(t, tokens, 1)
+	token, err := fm.CreateSubscriptionTransferToken(sub.SubscriptionID, "a")
+	require.NoError(t, err)
+	assert.Equal(t, token, tokens[sub.SubscriptionID])
+
+	// the drained connection is closed, the subscription waits to be adopted by the new connection
+	fm.releaseSubscription(sub.SubscriptionID, sub.HandOffChan)
+	adopted, _, err := fm.AdoptSubscription(token, nil, types.ClientInfo{RemoteAddress: "127.0.0.1:1001", AccountID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, sub.SubscriptionID, adopted.SubscriptionID)
+}
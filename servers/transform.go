@@ -0,0 +1,161 @@
+package servers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// limits of a subscription transform
+const (
+	maxTransformFields     = 32
+	maxTransformPathLength = 256
+)
+
+// transformSegmentRegex matches a segment of a transform path: a field name optionally followed by [] mapping the rest
+// of the path over the elements of the array, or by [N] selecting the element at the index
+var transformSegmentRegex = regexp.MustCompile(`^([A-Za-z0-9_\-]+)(\[([0-9]*)\])?$`)
+
+type transformSegment struct {
+	name string
+	// all is true for name[] segments
+	all bool
+	// index of name[N] segments, -1 if the segment does not select an element
+	index int
+}
+
+type transformField struct {
+	name string
+	path []transformSegment
+}
+
+// transform projects the notifications of a subscription into the shape requested by the client, so fat payloads
+// like blocks are trimmed before they are sent. Each field of the output is a dotted path into the JSON payload, e.g.
+// {"number": "header.number", "hashes": "transactions[].hash", "first": "transactions[0].hash"}. Paths which are
+// not found in the payload are omitted from the output.
+type transform struct {
+	fields []transformField
+}
+
+// newTransform parses the fields of the transform, a nil transform is returned if there are none
+func newTransform(fields map[string]string) (*transform, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	if len(fields) > maxTransformFields {
+		return nil, fmt.Errorf("transform has %v fields, the maximum is %v", len(fields), maxTransformFields)
+	}
+
+	t := &transform{fields: make([]transformField, 0, len(fields))}
+	for name, path := range fields {
+		if name == "" {
+			return nil, errors.New("transform field name cannot be empty")
+		}
+		segments, err := parseTransformPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transform of field %v: %w", name, err)
+		}
+		t.fields = append(t.fields, transformField{name: name, path: segments})
+	}
+	sort.Slice(t.fields, func(i, j int) bool { return t.fields[i].name < t.fields[j].name })
+	return t, nil
+}
+
+func parseTransformPath(path string) ([]transformSegment, error) {
+	if path == "" {
+		return nil, errors.New("path cannot be empty")
+	}
+	if len(path) > maxTransformPathLength {
+		return nil, fmt.Errorf("path is longer than %v characters", maxTransformPathLength)
+	}
+
+	parts := strings.Split(path, ".")
+	segments := make([]transformSegment, 0, len(parts))
+	for _, part := range parts {
+		match := transformSegmentRegex.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported path segment %q, expected name, name[] or name[N]", part)
+		}
+
+		segment := transformSegment{name: match[1], index: -1}
+		switch {
+		case match[2] == "":
+		case match[3] == "":
+			segment.all = true
+		default:
+			index, err := strconv.Atoi(match[3])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index in path segment %q: %w", part, err)
+			}
+			segment.index = index
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// apply returns the payload projected into the fields of the transform, a nil transform returns the payload as is
+func (t *transform) apply(payload interface{}) (interface{}, error) {
+	if t == nil {
+		return payload, nil
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	// keeps the precision of large numbers
+	decoder.UseNumber()
+	var value interface{}
+	if err = decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(t.fields))
+	for _, field := range t.fields {
+		if fieldValue, ok := evaluateTransformPath(value, field.path); ok {
+			result[field.name] = fieldValue
+		}
+	}
+	return result, nil
+}
+
+func evaluateTransformPath(value interface{}, path []transformSegment) (interface{}, bool) {
+	for i, segment := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment.name]; !ok {
+			return nil, false
+		}
+
+		switch {
+		case segment.all:
+			elements, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			results := make([]interface{}, 0, len(elements))
+			for _, element := range elements {
+				if result, ok := evaluateTransformPath(element, path[i+1:]); ok {
+					results = append(results, result)
+				}
+			}
+			return results, true
+		case segment.index >= 0:
+			elements, ok := value.([]interface{})
+			if !ok || segment.index >= len(elements) {
+				return nil, false
+			}
+			value = elements[segment.index]
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,63 @@
+package servers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	block := json.RawMessage(`{
+		"hash": "0x01",
+		"header": {"number": "0x10", "gasUsed": 21000},
+		"transactions": [
+			{"hash": "0xa1", "value": 123456789012345678901234567890},
+			{"hash": "0xa2"},
+			{"from": "0xb1"}
+		]
+	}`)
+
+	blockTransform, err := newTransform(map[string]string{
+		"number":     "header.number",
+		"hashes":     "transactions[].hash",
+		"first":      "transactions[0].hash",
+		"value":      "transactions[0].value",
+		"missing":    "header.miner",
+		"outOfRange": "transactions[5].hash",
+	})
+	require.NoError(t, err)
+
+	result, err := blockTransform.apply(block)
+	require.NoError(t, err)
+	b, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Equal(t, `{"first":"0xa1","hashes":["0xa1","0xa2"],"number":"0x10","value":123456789012345678901234567890}`, string(b))
+
+	var nilTransform *transform
+	result, err = nilTransform.apply(block)
+	require.NoError(t, err)
+	assert.Equal(t, block, result)
+}
+
+func TestNewTransform(t *testing.T) {
+	tr, err := newTransform(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, tr)
+
+	for _, path := range []string{"", "header..number", "transactions[a]", "transactions[]]", "header.number()", "a b"} {
+		_, err = newTransform(map[string]string{"field": path})
+		assert.Error(t, err, path)
+	}
+
+	_, err = newTransform(map[string]string{"": "hash"})
+	assert.Error(t, err)
+
+	fields := make(map[string]string)
+	for i := 0; i <= maxTransformFields; i++ {
+		fields[string(rune('a'+i))] = "hash"
+	}
+	_, err = newTransform(fields)
+	assert.Error(t, err)
+}
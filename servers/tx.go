@@ -4,13 +4,31 @@ import (
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
 	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
 )
 
+// mirroredTx is a tx submission as mirrored to the shadow sink of its account
+type mirroredTx struct {
+	Transaction            string `json:"transaction"`
+	ValidatorsOnly         bool   `json:"validators_only,omitempty"`
+	NextValidator          bool   `json:"next_validator,omitempty"`
+	NodeValidation         bool   `json:"node_validation,omitempty"`
+	FrontRunningProtection bool   `json:"front_running_protection,omitempty"`
+	Fallback               uint16 `json:"fallback,omitempty"`
+}
+
+// mirroredPrivateTx is a private tx submission as mirrored to the shadow sink of its account
+type mirroredPrivateTx struct {
+	Transaction string `json:"transaction"`
+	Timeout     uint64 `json:"timeout,omitempty"`
+}
+
 // HandleSingleTransaction handles a single tx, returns txHash, a boolean value indicating if it was successfully or not and an error only if we need to send it back to the caller
 func HandleSingleTransaction(
 	feedManager *FeedManager,
@@ -25,6 +43,14 @@ func HandleSingleTransaction(
 	nextValidatorMap *orderedmap.OrderedMap,
 	validatorStatusMap *syncmap.SyncMap[string, bool],
 ) (string, bool, error) {
+	feedManager.mirrorSubmission(conn, string(jsonrpc.RPCTx), mirroredTx{
+		Transaction:            transaction,
+		ValidatorsOnly:         validatorsOnly,
+		NextValidator:          nextValidator,
+		NodeValidation:         nodeValidationRequested,
+		FrontRunningProtection: frontRunningProtection,
+		Fallback:               fallback,
+	})
 
 	feedManager.LockPendingNextValidatorTxs()
 
@@ -32,7 +58,7 @@ func HandleSingleTransaction(
 	if err != nil {
 		return "", false, err
 	}
-	tx, pendingReevaluation, err := validateTxFromExternalSource(transaction, txContent, validatorsOnly, feedManager.chainID, nextValidator, fallback, nextValidatorMap, validatorStatusMap, feedManager.networkNum, conn.GetAccountID(), nodeValidationRequested, feedManager.nodeWSManager, conn, feedManager.pendingBSCNextValidatorTxHashToInfo, frontRunningProtection)
+	tx, pendingReevaluation, err := validateTxFromExternalSource(transaction, txContent, validatorsOnly, feedManager.chainID, nextValidator, fallback, nextValidatorMap, validatorStatusMap, feedManager.networkNum, conn.GetAccountID(), nodeValidationRequested, feedManager.nodeWSManager, conn, feedManager.pendingBSCNextValidatorTxHashToInfo, frontRunningProtection, feedManager.denylist, feedManager.contractAllowlist, feedManager.cfg.TxPolicy, feedManager.node.SlotTime().Time)
 	feedManager.UnlockPendingNextValidatorTxs()
 	if err != nil {
 		return "", false, err
@@ -45,6 +71,9 @@ func HandleSingleTransaction(
 		tx.SetSender(sender)
 	}
 
+	// tracked before it is handled, so the transactionStatus subscriptions of the account get all its statuses
+	feedManager.txStatusTracker.Submitted(conn.GetAccountID(), tx.Hash())
+
 	if !pendingReevaluation {
 		// call the Handler. Don't invoke in a go routine
 		err = feedManager.node.HandleMsg(tx, conn, connections.RunForeground)
@@ -76,3 +105,68 @@ func HandleSingleTransaction(
 
 	return tx.Hash().String(), true, nil
 }
+
+// HandlePrivateTransaction handles a tx which is sent to the BDN without being propagated to the public mempool. If the
+// tx is not included in a block before the timeout, it falls back to the delivery of the other txs, a zero timeout keeps
+// it private. It returns the txHash, or an error to send back to the caller.
+func HandlePrivateTransaction(feedManager *FeedManager, transaction string, conn connections.Conn, timeout time.Duration) (string, error) {
+	feedManager.mirrorSubmission(conn, string(jsonrpc.RPCPrivateTx), mirroredPrivateTx{
+		Transaction: transaction,
+		Timeout:     uint64(timeout / time.Second),
+	})
+
+	txContent, err := types.DecodeHex(transaction)
+	if err != nil {
+		return "", err
+	}
+	feedManager.LockPendingNextValidatorTxs()
+	tx, _, err := validateTxFromExternalSource(transaction, txContent, false, feedManager.chainID, false, 0, feedManager.nextValidatorMap, feedManager.validatorStatusMap, feedManager.networkNum, conn.GetAccountID(), false, feedManager.nodeWSManager, conn, feedManager.pendingBSCNextValidatorTxHashToInfo, false, feedManager.denylist, feedManager.contractAllowlist, feedManager.cfg.TxPolicy, feedManager.node.SlotTime().Time)
+	feedManager.UnlockPendingNextValidatorTxs()
+	if err != nil {
+		return "", err
+	}
+
+	tx.AddFlags(types.TFPrivateTx)
+	tx.RemoveFlags(types.TFDeliverToNode)
+	// the gateway keeps the handled message, the fallback is sent as another message
+	fallback := tx.Clone()
+	if err = feedManager.node.HandleMsg(tx, conn, connections.RunForeground); err != nil {
+		log.Errorf("failed to handle private transaction %v: %v", tx.Hash(), err)
+		return "", err
+	}
+
+	if timeout > 0 {
+		go privateTxFallback(feedManager, fallback, conn, timeout)
+	}
+
+	return tx.Hash().String(), nil
+}
+
+// privateTxFallback sends the private tx to the public mempool if it was not included in a block within the timeout.
+// The tx stays private if the gateway stops before the timeout or if its inclusion is unknown.
+func privateTxFallback(feedManager *FeedManager, tx *bxmessage.Tx, conn connections.Conn, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-feedManager.context.Done():
+		return
+	case <-timer.C:
+	}
+
+	included, err := feedManager.txIncluded(feedManager.context, tx.Hash())
+	if err != nil {
+		log.Warnf("keeping private tx %v private, its inclusion within %v is unknown: %v", tx.Hash(), timeout, err)
+		return
+	}
+	if included {
+		return
+	}
+	log.Infof("sending private tx %v to the public mempool because it was not included within %v", tx.Hash(), timeout)
+
+	// the tx is reprocessed by the gateway once it is marked for the nodes
+	tx.RemoveFlags(types.TFPrivateTx)
+	tx.AddFlags(types.TFDeliverToNode)
+	if err = feedManager.node.HandleMsg(tx, conn, connections.RunForeground); err != nil {
+		log.Errorf("failed to send private tx %v to the public mempool at timeout: %v", tx.Hash(), err)
+	}
+}
@@ -30,6 +30,24 @@ func validateIncludeParam(feed types.FeedType, include []string, txFromFieldIncl
 			requestedFields = validOnBlockParams
 		case types.TxReceiptsFeed:
 			requestedFields = validTxReceiptParams
+		case types.NextSprintValidatorsFeed:
+			requestedFields = validNextSprintParams
+		case types.ProposerDutiesFeed:
+			requestedFields = validProposerDutyParams
+		case types.TopOfBlockFeed:
+			requestedFields = validTopOfBlockParams
+		case types.ContractCreationsFeed:
+			requestedFields = validContractCreationParams
+		case types.LogsFeed:
+			requestedFields = validLogParams
+		case types.TransactionStatusFeed:
+			requestedFields = validTxStatusParams
+		case types.MEVShareFeed:
+			requestedFields = validMEVShareParams
+		case types.BundleStatusFeed:
+			requestedFields = validBundleStatusParams
+		case types.ShortIDsFeed:
+			requestedFields = validShortIDParams
 		}
 
 		return requestedFields, nil
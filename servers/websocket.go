@@ -2,7 +2,9 @@ package servers
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
 	"github.com/gorilla/websocket"
@@ -28,26 +30,10 @@ func NewWebsocketRPCServer(host string, port int, handler jsonrpc2.Handler) Webs
 
 // Run starts the RPC Server
 func (ws *WebsocketRPCServer) Run() error {
-	listenAddr := fmt.Sprintf("%v:%v", ws.host, ws.port)
+	listenAddr := net.JoinHostPort(ws.host, strconv.Itoa(ws.port))
 
 	handler := http.NewServeMux()
-	handler.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		log.Info("got a connection, upgrading to websockets")
-		upgrader := websocket.Upgrader{}
-		connection, err := upgrader.Upgrade(w, r, nil)
-		if err != nil {
-			log.Errorf("error upgrading HTTP server connection to websocket protocol: %v", err)
-			return
-		}
-		handler := jsonrpc2.AsyncHandler(ws.handler)
-		jc := jsonrpc2.NewConn(r.Context(), websocketjsonrpc2.NewObjectStream(connection), handler)
-		<-jc.DisconnectNotify()
-
-		err = connection.Close()
-		if err != nil {
-			log.Errorf("error closing connection: %v", err)
-		}
-	})
+	handler.HandleFunc("/ws", ws.serveWS)
 	log.Infof("starting rpc server on %v", listenAddr)
 
 	err := http.ListenAndServe(listenAddr, handler)
@@ -56,3 +42,22 @@ func (ws *WebsocketRPCServer) Run() error {
 	}
 	return nil
 }
+
+// serveWS upgrades the connection to websockets and serves its requests until it is closed
+func (ws *WebsocketRPCServer) serveWS(w http.ResponseWriter, r *http.Request) {
+	log.Info("got a connection, upgrading to websockets")
+	upgrader := websocket.Upgrader{}
+	connection, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf("error upgrading HTTP server connection to websocket protocol: %v", err)
+		return
+	}
+	handler := jsonrpc2.AsyncHandler(ws.handler)
+	jc := jsonrpc2.NewConn(r.Context(), newBatchObjectStream(websocketjsonrpc2.NewObjectStream(connection)), handler)
+	<-jc.DisconnectNotify()
+
+	err = connection.Close()
+	if err != nil {
+		log.Errorf("error closing connection: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// WSReconnectNotification - sent to the clients of a websocket server which is re-bound before their connection is
+// closed, subscriptions can be adopted on the new connection with subscription_transfer and their transfer token
+type WSReconnectNotification struct {
+	ReconnectTo   string            `json:"reconnect_to"`
+	Subscriptions map[string]string `json:"subscriptions,omitempty"`
+}
+
+const (
+	wsShutdownReasonStopped  = "gateway stopped"
+	wsShutdownReasonUnsynced = "blockchain node unsynced"
+
+	// wsShutdownFlushInterval is the interval of checking whether the queued notifications were flushed
+	wsShutdownFlushInterval = 10 * time.Millisecond
+)
+
+// GatewayShutdownNotification - sent to the clients of the websocket server when it shuts down, no subscriptions are
+// accepted afterwards and the connection is closed at ETA at the latest
+type GatewayShutdownNotification struct {
+	Reason string    `json:"reason"`
+	ETA    time.Time `json:"eta"`
+}
+
+// wsConnections tracks the open connections of a websocket server, so they can be drained when it is re-bound
+type wsConnections struct {
+	lock  sync.Mutex
+	conns map[*jsonrpc2.Conn]struct{}
+}
+
+func newWSConnections() *wsConnections {
+	return &wsConnections{conns: make(map[*jsonrpc2.Conn]struct{})}
+}
+
+func (c *wsConnections) add(conn *jsonrpc2.Conn) {
+	c.lock.Lock()
+	c.conns[conn] = struct{}{}
+	c.lock.Unlock()
+
+	go func() {
+		<-conn.DisconnectNotify()
+		c.lock.Lock()
+		delete(c.conns, conn)
+		c.lock.Unlock()
+	}()
+}
+
+func (c *wsConnections) list() []*jsonrpc2.Conn {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	conns := make([]*jsonrpc2.Conn, 0, len(c.conns))
+	for conn := range c.conns {
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+// wsListener is the address the websocket server listens on
+type wsListener struct {
+	host string
+	port int
+}
+
+func (l wsListener) addr() string {
+	if l.host == localhost {
+		return fmt.Sprintf(":%v", l.port)
+	}
+	return net.JoinHostPort(l.host, fmt.Sprint(l.port))
+}
+
+// ReconfigureWSListener moves the websocket server to the host and port, the server is re-bound and its connections
+// are drained if the listener changed
+func (ch *ClientHandler) ReconfigureWSListener(host string, port int) {
+	ch.wsLock.Lock()
+	ch.pendingWSListener = &wsListener{host: host, port: port}
+	ch.wsLock.Unlock()
+
+	select {
+	case ch.wsListenerUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// applyWSListener replaces the listener by the pending one, returning true if the listener changed
+func (ch *ClientHandler) applyWSListener() bool {
+	ch.wsLock.Lock()
+	defer ch.wsLock.Unlock()
+
+	pending := ch.pendingWSListener
+	ch.pendingWSListener = nil
+	if pending == nil || *pending == ch.wsListener {
+		return false
+	}
+	ch.log.Infof("websocket listener changed from %v to %v", ch.wsListener.addr(), pending.addr())
+	ch.wsListener = *pending
+	return true
+}
+
+// rebindWSServer closes the listener of the websocket server and starts a new one with the current configuration.
+// The connections of the previous server are drained instead of being dropped at once.
+func (ch *ClientHandler) rebindWSServer() {
+	ch.wsLock.Lock()
+	server, connections := ch.websocketServer, ch.wsConnections
+	ch.wsLock.Unlock()
+
+	if server != nil {
+		ch.log.Infof("re-binding websocket server at %v", server.Addr)
+		// hijacked websocket connections are not closed by the shutdown, only the listener is
+		if err := server.Shutdown(ch.feedManager.context); err != nil {
+			ch.log.Errorf("encountered error shutting down websocket server %v: %v", server.Addr, err)
+		}
+	}
+	ch.startWSServer()
+
+	if connections != nil {
+		go ch.drainWSConnections(connections.list(), ch.feedManager.cfg.WSDrainWindow)
+	}
+}
+
+// drainWSConnections asks the clients to reconnect, handing off their subscriptions with transfer tokens, and closes
+// the connections evenly over the drain window
+func (ch *ClientHandler) drainWSConnections(conns []*jsonrpc2.Conn, window time.Duration) {
+	if len(conns) == 0 {
+		return
+	}
+	ch.log.Infof("draining %v websocket connections over %v", len(conns), window)
+
+	reconnectTo := ch.wsURL()
+	for _, conn := range conns {
+		notification := WSReconnectNotification{
+			ReconnectTo:   reconnectTo,
+			Subscriptions: ch.feedManager.connectionTransferTokens(conn),
+		}
+		if err := conn.Notify(ch.feedManager.context, "reconnect", notification); err != nil {
+			ch.log.Debugf("failed to send reconnect notification: %v", err)
+		}
+	}
+
+	interval := window / time.Duration(len(conns))
+	for i, conn := range conns {
+		if i > 0 && interval > 0 {
+			select {
+			case <-ch.feedManager.context.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+		_ = conn.Close()
+	}
+	ch.log.Infof("drained %v websocket connections", len(conns))
+}
+
+// notifyWSShutdown sends the gateway_shutdown notification to the clients and waits until the notifications queued
+// for the websocket subscriptions are flushed, or the deadline passes
+func (ch *ClientHandler) notifyWSShutdown(conns []*jsonrpc2.Conn, reason string, deadline time.Duration) {
+	eta := time.Now().Add(deadline)
+	ctx, cancel := context.WithDeadline(context.Background(), eta)
+	defer cancel()
+
+	notification := GatewayShutdownNotification{Reason: reason, ETA: eta.UTC()}
+	for _, conn := range conns {
+		if err := conn.Notify(ctx, "gateway_shutdown", notification); err != nil {
+			ch.log.Debugf("failed to send gateway_shutdown notification: %v", err)
+		}
+	}
+
+	ticker := time.NewTicker(wsShutdownFlushInterval)
+	defer ticker.Stop()
+	for {
+		pending := ch.feedManager.pendingWSNotifications()
+		if pending == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			ch.log.Warnf("websocket shutdown deadline %v passed, dropping %v queued notifications", deadline, pending)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ch *ClientHandler) wsURL() string {
+	scheme := "ws"
+	if ch.feedManager.cfg.WebsocketTLSEnabled {
+		scheme = "wss"
+	}
+	ch.wsLock.Lock()
+	listener := ch.wsListener
+	ch.wsLock.Unlock()
+	return fmt.Sprintf("%v://%v/ws", scheme, net.JoinHostPort(listener.host, fmt.Sprint(listener.port)))
+}
+
+// filesModTime returns the latest modification time of the files, missing files are ignored
+func filesModTime(paths ...string) time.Time {
+	var modTime time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	return modTime
+}
@@ -0,0 +1,77 @@
+package servers
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientHandler_ReconfigureWSListener(t *testing.T) {
+	ch := &ClientHandler{
+		wsListener:        wsListener{host: localhost, port: 28333},
+		wsListenerUpdated: make(chan struct{}, 1),
+		log:               log.WithField("component", "test"),
+	}
+	assert.Equal(t, ":28333", ch.wsListener.addr())
+
+	// an unchanged listener does not re-bind the server
+	ch.ReconfigureWSListener(localhost, 28333)
+	<-ch.wsListenerUpdated
+	assert.False(t, ch.applyWSListener())
+
+	// only the last pending listener is applied
+	ch.ReconfigureWSListener("10.0.0.1", 28334)
+	ch.ReconfigureWSListener("10.0.0.1", 28335)
+	<-ch.wsListenerUpdated
+	assert.Len(t, ch.wsListenerUpdated, 0)
+	assert.True(t, ch.applyWSListener())
+	assert.Equal(t, "10.0.0.1:28335", ch.wsListener.addr())
+	assert.False(t, ch.applyWSListener())
+
+	// IPv6 hosts are bracketed
+	ch.ReconfigureWSListener("::", 28336)
+	<-ch.wsListenerUpdated
+	assert.True(t, ch.applyWSListener())
+	assert.Equal(t, "[::]:28336", ch.wsListener.addr())
+}
+
+func TestClientHandler_NotifyWSShutdown(t *testing.T) {
+	fm := newTestFeedManager()
+	ch := &ClientHandler{feedManager: fm, log: log.WithField("component", "test")}
+
+	ci := types.ClientInfo{RemoteAddress: "127.0.0.1:1000", AccountID: "a"}
+	sub, err := fm.SubscribeClient(types.NewTxsFeed, types.WebSocketFeed, nil, ci, types.ReqOptions{}, false)
+	require.NoError(t, err)
+	sub.FeedChan <- &types.NewTransactionNotification{}
+
+	// new websocket subscriptions are rejected, other connection types are still served
+	fm.stopWSSubscriptions(true)
+	_, err = fm.SubscribeClient(types.NewTxsFeed, types.WebSocketFeed, nil, types.ClientInfo{RemoteAddress: "127.0.0.2:1000", AccountID: "a"}, types.ReqOptions{}, false)
+	assert.ErrorIs(t, err, ErrGatewayShuttingDown)
+	_, err = fm.SubscribeClient(types.NewTxsFeed, types.GRPCFeed, nil, types.ClientInfo{RemoteAddress: "127.0.0.3:1000", AccountID: "a"}, types.ReqOptions{}, false)
+	assert.NoError(t, err)
+
+	// the deadline passes while a notification is still queued
+	start := time.Now()
+	ch.notifyWSShutdown(nil, wsShutdownReasonStopped, 50*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, 1, fm.pendingWSNotifications())
+
+	// the shutdown completes as soon as the queued notifications are flushed
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-sub.FeedChan
+	}()
+	start = time.Now()
+	ch.notifyWSShutdown(nil, wsShutdownReasonStopped, time.Minute)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.Equal(t, 0, fm.pendingWSNotifications())
+
+	fm.stopWSSubscriptions(false)
+	_, err = fm.SubscribeClient(types.NewTxsFeed, types.WebSocketFeed, nil, types.ClientInfo{RemoteAddress: "127.0.0.2:1000", AccountID: "a"}, types.ReqOptions{}, false)
+	assert.NoError(t, err)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/config"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
@@ -20,6 +22,7 @@ import (
 var (
 	errParamsValueIsMissing = "params is missing in the request"
 	errFDifferentAccAuth    = "%s is not allowed when account authentication is different from the node account"
+	errFFeatureDisabled     = "%s is disabled on this gateway"
 )
 
 type handlerObj struct {
@@ -35,6 +38,29 @@ type handlerObj struct {
 	headers                  map[string]string
 	stats                    statistics.Stats
 	txFromFieldIncludable    bool
+	feedDedup                *feedDedup
+}
+
+// privilegedMethods change the gateway or bypass the public mempool, their calls are reported as security events
+var privilegedMethods = map[jsonrpc.RPCRequestType]struct{}{
+	jsonrpc.RPCDenylist:                   {},
+	jsonrpc.RPCConfig:                     {},
+	jsonrpc.RPCChangeNewPendingTxFromNode: {},
+	jsonrpc.RPCSubscriptionTransfer:       {},
+	jsonrpc.RPCPrivateTx:                  {},
+	jsonrpc.RPCMEVShareTx:                 {},
+	jsonrpc.RPCBundleSubmission:           {},
+	jsonrpc.RPCBundleCancellation:         {},
+	jsonrpc.RPCMEVSearcher:                {},
+	jsonrpc.RPCEthSendBundle:              {},
+	jsonrpc.RPCEthSendMegaBundle:          {},
+}
+
+// rateLimitedRPCMethods are limited per account by the tier of the account
+var rateLimitedRPCMethods = map[jsonrpc.RPCRequestType]config.RateLimitedMethod{
+	jsonrpc.RPCTx:        config.RateLimitTx,
+	jsonrpc.RPCBatchTx:   config.RateLimitBatchTx,
+	jsonrpc.RPCSubscribe: config.RateLimitSubscribe,
 }
 
 // Handle handling client requests
@@ -43,14 +69,35 @@ func (h *handlerObj) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonr
 	defer func() {
 		h.log.Debugf("websocket handling for method %v ended. Duration %v", jsonrpc.RPCRequestType(req.Method), time.Since(start))
 	}()
+	defer h.FeedManager.recoverPanic(panicScopeRPC, req.Method, func(interface{}) {
+		SendErrorMsg(ctx, jsonrpc.InternalError, "internal error", conn, req.ID)
+	})
+
+	if _, ok := privilegedMethods[jsonrpc.RPCRequestType(req.Method)]; ok {
+		h.FeedManager.securityEvents.PrivilegedMethod(h.connectionAccount.AccountID, h.remoteAddress, req.Method)
+	}
+	if method, ok := rateLimitedRPCMethods[jsonrpc.RPCRequestType(req.Method)]; ok {
+		if err := h.FeedManager.AllowCalls(h.connectionAccount, method, 1); err != nil {
+			SendErrorMsg(ctx, jsonrpc.RateLimited, err.Error(), conn, req.ID)
+			return
+		}
+	}
 
 	switch jsonrpc.RPCRequestType(req.Method) {
 	case jsonrpc.RPCSubscribe:
 		h.handleRPCSubscribe(ctx, conn, req)
 	case jsonrpc.RPCUnsubscribe:
 		h.handleRPCUnsubscribe(ctx, conn, req)
+	case jsonrpc.RPCSubscriptionTransferToken:
+		h.handleRPCSubscriptionTransferToken(ctx, conn, req)
+	case jsonrpc.RPCSubscriptionTransfer:
+		h.handleRPCSubscriptionTransfer(ctx, conn, req)
 	case jsonrpc.RPCTx:
 		h.handleRPCTx(ctx, conn, req)
+	case jsonrpc.RPCPrivateTx:
+		h.handleRPCPrivateTx(ctx, conn, req)
+	case jsonrpc.RPCMEVShareTx:
+		h.handleRPCMEVShareTx(ctx, conn, req)
 	case jsonrpc.RPCBatchTx:
 		h.handleRPCBatchTx(ctx, conn, req)
 	case jsonrpc.RPCPing:
@@ -60,6 +107,114 @@ func (h *handlerObj) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonr
 		if err := conn.Reply(ctx, req.ID, response); err != nil {
 			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
 		}
+	case jsonrpc.RPCDenylist:
+		h.handleRPCDenylist(ctx, conn, req)
+	case jsonrpc.RPCConfig:
+		h.handleRPCConfig(ctx, conn, req)
+	case jsonrpc.RPCAccountConnections:
+		h.handleRPCAccountConnections(ctx, conn, req)
+	case jsonrpc.RPCWasmFilter:
+		h.handleRPCWasmFilter(ctx, conn, req)
+	case jsonrpc.RPCNodeStatus:
+		nodeStatus := h.FeedManager.node.NodeStatus()
+		slotTime := h.FeedManager.node.SlotTime()
+		response := rpcNodeStatusResponse{
+			TxPoolState:  nodeStatus.TxPoolState,
+			TxPools:      nodeStatus.TxPools,
+			TxsForwarded: nodeStatus.TxsForwarded,
+			TxsDropped:   nodeStatus.TxsDropped,
+
+			TxPoolReconciliation: nodeStatus.TxPoolReconciliation,
+			NetworkConfigUpdates: nodeStatus.NetworkConfigUpdates,
+
+			InvalidBDNTxs:        nodeStatus.InvalidBDNTxs,
+			DroppedBDNValidation: nodeStatus.DroppedBDNValidation,
+
+			Canary:           nodeStatus.Canary,
+			FeedVerification: nodeStatus.FeedVerification,
+
+			OutboundProxies: nodeStatus.OutboundProxies,
+
+			ClockOffsetMs: float64(slotTime.Offset) / float64(time.Millisecond),
+			ClockSkewed:   slotTime.Skewed,
+		}
+		if err := conn.Reply(ctx, req.ID, response); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
+	case jsonrpc.RPCBDNStatus:
+		bdnStatus := h.FeedManager.node.BDNStatus()
+		response := rpcBDNStatusResponse{
+			Protocol:        bdnStatus.Protocol,
+			SyncedWithRelay: bdnStatus.SyncedWithRelay,
+			Relays:          bdnStatus.Relays,
+		}
+		if response.Relays == nil {
+			response.Relays = []connections.BDNConnStatus{}
+		}
+		if err := conn.Reply(ctx, req.ID, response); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
+	case jsonrpc.RPCVersion:
+		if err := conn.Reply(ctx, req.ID, bxmessage.BuildInfo()); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
+	case jsonrpc.RPCTime:
+		slotTime := h.FeedManager.node.SlotTime()
+		response := rpcTimeResponse{
+			TimestampMs:    slotTime.Time.UnixMilli(),
+			OffsetMs:       float64(slotTime.Offset) / float64(time.Millisecond),
+			Slot:           slotTime.Slot,
+			SlotDurationMs: slotTime.SlotDuration.Milliseconds(),
+			Skewed:         slotTime.Skewed,
+		}
+		if !slotTime.NextSlot.IsZero() {
+			response.NextSlotMs = slotTime.NextSlot.UnixMilli()
+		}
+		if err := conn.Reply(ctx, req.ID, response); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
+	case jsonrpc.RPCValidatorList:
+		if req.Params == nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+			return
+		}
+		var params jsonrpc.RPCValidatorListPayload
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+				jsonrpc.RPCValidatorList, err), conn, req.ID)
+			return
+		}
+		info, ok := h.FeedManager.node.ValidatorList(params.BlockHeight)
+		if !ok {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("validator list for block height %v is not available", params.BlockHeight), conn, req.ID)
+			return
+		}
+		response := rpcValidatorListResponse{
+			BlockHeight:   info.BlockHeight,
+			ValidatorList: info.ValidatorList,
+		}
+		if err := conn.Reply(ctx, req.ID, response); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
+	case jsonrpc.RPCFeeHistory:
+		if req.Params == nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+			return
+		}
+		var params jsonrpc.RPCFeeHistoryPayload
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+				jsonrpc.RPCFeeHistory, err), conn, req.ID)
+			return
+		}
+		feeHistory, err := h.FeedManager.node.FeeHistory(params.BlockCount, params.NewestBlock, params.RewardPercentiles)
+		if err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+			return
+		}
+		if err = conn.Reply(ctx, req.ID, feeHistory); err != nil {
+			h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		}
 	case jsonrpc.RPCQuotaUsage:
 		response, err := h.getQuotaUsage(string(h.connectionAccount.AccountID))
 		if err != nil {
@@ -73,6 +228,10 @@ func (h *handlerObj) Handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonr
 		h.handleRPCMevSearcher(ctx, conn, req)
 	case jsonrpc.RPCBundleSubmission:
 		h.handleRPCBundleSubmission(ctx, conn, req)
+	case jsonrpc.RPCBundleCancellation:
+		h.handleRPCBundleCancellation(ctx, conn, req)
+	case jsonrpc.RPCBundleSimulation:
+		h.handleRPCBundleSimulation(ctx, conn, req)
 	case jsonrpc.RPCChangeNewPendingTxFromNode:
 		h.handleRPCNewPendingTxsSourceFromNode(ctx, conn, req)
 	default:
@@ -130,10 +289,41 @@ func (h *handlerObj) sendNotification(ctx context.Context, subscriptionID string
 	}
 	content := notification.WithFields(clientReq.includes)
 	response.Result = content
-	err := conn.Notify(ctx, "subscribe", response)
+	return h.notifySubscription(ctx, conn, subscriptionID, clientReq, response, content)
+}
+
+// notifySubscription sends the response of the subscription, or its result projected by the transform of the client
+// request if it has one
+func (h *handlerObj) notifySubscription(ctx context.Context, conn *jsonrpc2.Conn, subscriptionID string, clientReq *clientReq, response interface{}, result interface{}) error {
+	if clientReq.transform != nil {
+		transformed, err := clientReq.transform.apply(result)
+		if err != nil {
+			h.log.Errorf("failed to transform notification of subscriptionID %v: %v", subscriptionID, err)
+			return nil
+		}
+		response = transformedResponse{Subscription: subscriptionID, Result: transformed}
+	}
+
+	err := notifyWithStats(ctx, conn, clientReq.delivery, 1, response)
 	if err != nil {
 		h.log.Errorf("error reply to subscriptionID %v: %v", subscriptionID, err.Error())
 		return err
 	}
+	h.FeedManager.exporter.Export(h.connectionAccount.AccountID, clientReq.feed, response)
+	return nil
+}
+
+// notifyWithStats sends the subscription notification, recording the number of notifications it holds and its size
+// in the delivery stats of the subscription
+func notifyWithStats(ctx context.Context, conn *jsonrpc2.Conn, delivery *deliveryStats, notifications int, response interface{}) error {
+	payload, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	if err = conn.Notify(ctx, "subscribe", json.RawMessage(payload)); err != nil {
+		delivery.writeError()
+		return err
+	}
+	delivery.sent(notifications, len(payload))
 	return nil
 }
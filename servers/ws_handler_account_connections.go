@@ -0,0 +1,25 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// handleRPCAccountConnections returns the open websocket connections and gRPC streams of each account, with the
+// concurrent connections limit of the account. gRPC is counted per stream rather than per transport connection, so
+// a client multiplexing several subscriptions over one connection uses one connection of its limit for each of them.
+func (h *handlerObj) handleRPCAccountConnections(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCAccountConnections)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	if err := conn.Reply(ctx, req.ID, h.FeedManager.AccountConnections()); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
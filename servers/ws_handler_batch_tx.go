@@ -7,6 +7,7 @@ import (
 
 	"github.com/bloXroute-Labs/gateway/v2/connections"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/sourcegraph/jsonrpc2"
@@ -25,6 +26,10 @@ func (h *handlerObj) handleRPCBatchTx(ctx context.Context, conn *jsonrpc2.Conn,
 		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
 		return
 	}
+	if !h.FeedManager.featureFlags.Enabled(services.FeatureTxBatching) {
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, fmt.Sprintf(errFFeatureDisabled, jsonrpc.RPCBatchTx), conn, req.ID)
+		return
+	}
 	if req.Params == nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
 		return
@@ -45,11 +50,17 @@ func (h *handlerObj) handleRPCBatchTx(ctx context.Context, conn *jsonrpc2.Conn,
 		ws = connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
 	}
 
+	// batches cannot tell an omitted validators_only from false, only an enabled flag overrides the account default
+	flags := h.FeedManager.ApplyTxDefaults(ws, h.connectionAccount, TxFlags{
+		ValidatorsOnly:    params.ValidatorsOnly,
+		ValidatorsOnlySet: params.ValidatorsOnly,
+	})
+
 	var txHashes []string
 
 	for _, transaction := range params.Transactions {
-		txHash, ok, err := HandleSingleTransaction(h.FeedManager, transaction, nil, ws, params.ValidatorsOnly, false,
-			false, false, 0, nil, nil)
+		txHash, ok, err := HandleSingleTransaction(h.FeedManager, transaction, nil, ws, flags.ValidatorsOnly, false,
+			false, flags.FrontRunningProtection, 0, nil, nil)
 		if err != nil {
 			h.log.WithField("method", jsonrpc.RPCBatchTx).Errorf("failed to handle transaction: %v", err)
 		}
@@ -0,0 +1,114 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// rpcBundleSimulationResponse is the result of blxr_simulate_bundle, as simulated by eth_callBundle of the node
+type rpcBundleSimulationResponse struct {
+	BundleHash        string                     `json:"bundleHash"`
+	StateBlockNumber  uint64                     `json:"stateBlockNumber"`
+	TotalGasUsed      uint64                     `json:"totalGasUsed"`
+	BundleGasPrice    string                     `json:"bundleGasPrice"`
+	CoinbaseDiff      string                     `json:"coinbaseDiff"`
+	EthSentToCoinbase string                     `json:"ethSentToCoinbase"`
+	GasFees           string                     `json:"gasFees"`
+	Results           []bundleSimulationTxResult `json:"results"`
+}
+
+// bundleSimulationTxResult is the simulation of a tx of the bundle, Error and Revert are set if the tx reverted
+type bundleSimulationTxResult struct {
+	TxHash            string `json:"txHash"`
+	GasUsed           uint64 `json:"gasUsed"`
+	GasPrice          string `json:"gasPrice"`
+	CoinbaseDiff      string `json:"coinbaseDiff"`
+	EthSentToCoinbase string `json:"ethSentToCoinbase"`
+	Error             string `json:"error,omitempty"`
+	Revert            string `json:"revert,omitempty"`
+}
+
+func (h *handlerObj) handleRPCBundleSimulation(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCBundleSimulation)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.AccountIDError, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCBundleSimulationPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCBundleSimulation, err), conn, req.ID)
+		return
+	}
+	if err := params.Validate(); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	ws, synced := h.FeedManager.nodeWSManager.SyncedProvider()
+	if !synced {
+		SendErrorMsg(ctx, jsonrpc.InternalError, fmt.Sprintf("your blockchain node is either not synced or the gateway does not "+
+			"have an active websocket connection to the node - request %v was not sent in order to prevent errors", req.Method), conn, req.ID)
+		return
+	}
+
+	result, err := simulateBundle(ws, params)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InternalError, err.Error(), conn, req.ID)
+		return
+	}
+	if err = conn.Reply(ctx, req.ID, result); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// simulateBundle simulates the bundle on top of the state of the node with eth_callBundle
+func simulateBundle(ws blockchain.WSProvider, params jsonrpc.RPCBundleSimulationPayload) (*rpcBundleSimulationResponse, error) {
+	txs := make([]string, 0, len(params.Transaction))
+	for _, tx := range params.Transaction {
+		if !strings.HasPrefix(tx, "0x") {
+			tx = "0x" + tx
+		}
+		txs = append(txs, tx)
+	}
+	stateBlockNumber := params.StateBlockNumber
+	if stateBlockNumber == "" {
+		stateBlockNumber = "latest"
+	}
+	callBundle := map[string]interface{}{
+		"txs":              txs,
+		"blockNumber":      params.BlockNumber,
+		"stateBlockNumber": stateBlockNumber,
+	}
+	if params.Timestamp > 0 {
+		callBundle["timestamp"] = params.Timestamp
+	}
+
+	response, err := ws.CallRPC(string(jsonrpc.RPCEthCallBundle), []interface{}{callBundle}, blockchain.DefaultRPCOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate bundle on node %v: %v", ws.BlockchainPeerEndpoint().IPPort(), err)
+	}
+
+	content, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %v response: %v", jsonrpc.RPCEthCallBundle, err)
+	}
+	var result rpcBundleSimulationResponse
+	if err = json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("unexpected %v response of node: %v", jsonrpc.RPCEthCallBundle, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,68 @@
+package servers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callBundleProvider answers eth_callBundle with the response, recording the payload of the call
+type callBundleProvider struct {
+	blockchain.WSProvider
+	payload  []interface{}
+	response interface{}
+	err      error
+}
+
+func (p *callBundleProvider) CallRPC(method string, payload []interface{}, _ blockchain.RPCOptions) (interface{}, error) {
+	if method != string(jsonrpc.RPCEthCallBundle) {
+		return nil, errors.New("unexpected method " + method)
+	}
+	p.payload = payload
+	return p.response, p.err
+}
+
+func (p *callBundleProvider) BlockchainPeerEndpoint() types.NodeEndpoint {
+	return types.NodeEndpoint{IP: "127.0.0.1", Port: 8546}
+}
+
+func TestSimulateBundle(t *testing.T) {
+	provider := &callBundleProvider{response: map[string]interface{}{
+		"bundleHash":       "0x73b1e258c7a42fd0230b2fd05529c5d4b6fcb66c227783f8bece8aeacdd1db2e",
+		"stateBlockNumber": float64(5221585),
+		"totalGasUsed":     float64(42000),
+		"coinbaseDiff":     "20000000000126000",
+		"results": []interface{}{
+			map[string]interface{}{"txHash": "0x01", "gasUsed": float64(21000), "coinbaseDiff": "20000000000063000"},
+			map[string]interface{}{"txHash": "0x02", "gasUsed": float64(21000), "error": "execution reverted", "revert": "too late"},
+		},
+	}}
+	params := jsonrpc.RPCBundleSimulationPayload{Transaction: []string{"f86b", "0xf86c"}, BlockNumber: "0x4fac12"}
+	require.NoError(t, params.Validate())
+
+	result, err := simulateBundle(provider, params)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"txs":              []string{"0xf86b", "0xf86c"},
+		"blockNumber":      "0x4fac12",
+		"stateBlockNumber": "latest",
+	}}, provider.payload)
+	assert.Equal(t, uint64(42000), result.TotalGasUsed)
+	assert.Equal(t, "20000000000126000", result.CoinbaseDiff)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, uint64(21000), result.Results[0].GasUsed)
+	assert.Empty(t, result.Results[0].Revert)
+	assert.Equal(t, "too late", result.Results[1].Revert)
+
+	provider.err = errors.New("the method eth_callBundle does not exist")
+	_, err = simulateBundle(provider, params)
+	assert.Error(t, err)
+
+	assert.Error(t, jsonrpc.RPCBundleSimulationPayload{BlockNumber: "0x4fac12"}.Validate())
+	assert.Error(t, jsonrpc.RPCBundleSimulationPayload{Transaction: []string{"f86b"}, BlockNumber: "123"}.Validate())
+}
@@ -54,3 +54,45 @@ func (h *handlerObj) handleRPCBundleSubmission(ctx context.Context, conn *jsonrp
 		log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
 	}
 }
+
+func (h *handlerObj) handleRPCBundleCancellation(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCBundleCancellation)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.AccountIDError, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCBundleCancellationPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCBundleCancellation, err), conn, req.ID)
+		return
+	}
+	if err := params.Validate(); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	var ws connections.RPCConn
+	if h.connectionAccount.AccountID == types.BloxrouteAccountID {
+		// Cancellation sent from cloud services, need to update account ID of the connection to be the origin sender
+		ws = connections.NewRPCConn(types.AccountID(params.OriginalSenderAccountID), h.remoteAddress, h.FeedManager.networkNum, utils.CloudAPI)
+	} else {
+		ws = connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
+	}
+
+	result, errCode, err := HandleMEVBundleCancellation(h.FeedManager, ws, h.connectionAccount, &params)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.RPCErrorCode(errCode), err.Error(), conn, req.ID)
+		return
+	}
+	if err = conn.Reply(ctx, req.ID, result); err != nil {
+		log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
@@ -0,0 +1,35 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/config"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// handleRPCConfig returns the effective configuration of the gateway, where each setting comes from and the settings
+// which differ from their default
+func (h *handlerObj) handleRPCConfig(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCConfig)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	settings := h.FeedManager.cfg.Settings.Clone()
+	settings.Set("sdn-account-id", config.Setting{Value: h.FeedManager.accountModel.AccountID, Source: config.SourceSDN})
+	settings.Set("sdn-account-tier", config.Setting{Value: h.FeedManager.accountModel.TierName, Source: config.SourceSDN})
+	settings.Set("sdn-network-num", config.Setting{Value: h.FeedManager.networkNum, Source: config.SourceSDN})
+	settings.Set("sdn-chain-id", config.Setting{Value: h.FeedManager.chainID, Source: config.SourceSDN})
+
+	response := rpcConfigResponse{
+		Settings: settings,
+		Diff:     settings.Diff(),
+	}
+	if err := conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
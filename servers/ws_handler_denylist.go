@@ -0,0 +1,60 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// handleRPCDenylist lets the gateway operator change the denylist at runtime
+func (h *handlerObj) handleRPCDenylist(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCDenylist)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+	if h.FeedManager.denylist == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, "denylist is not available", conn, req.ID)
+		return
+	}
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCDenylistPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCDenylist, err), conn, req.ID)
+		return
+	}
+
+	denylist := h.FeedManager.denylist
+	rules := services.DenylistRules{Addresses: params.Addresses, TxHashPatterns: params.TxHashPatterns}
+	var err error
+	switch params.Action {
+	case "add":
+		err = denylist.Add(rules)
+	case "remove":
+		denylist.Remove(rules)
+	case "reload":
+		err = denylist.Reload()
+	case "list":
+	default:
+		err = fmt.Errorf("unsupported action %v, possible values are: add, remove, reload, list", params.Action)
+	}
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	h.log.Infof("denylist %v request from %v handled", params.Action, h.remoteAddress)
+	if err = conn.Reply(ctx, req.ID, denylist.Rules()); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
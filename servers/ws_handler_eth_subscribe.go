@@ -74,7 +74,7 @@ func (h *handlerObj) handleEthSubscribeNewPendingTxs(ctx context.Context, conn *
 	}
 	// since we are replacing newPendingTransactions with newTxs/pendingTx, any existing newTxs/pendingTxs suppose to make newPendingTransactions a duplicate subscription.
 	// But this is used only in external gateway where gateway account id is the same with request account id, so this is avoided
-	sub, errSubscribe := h.FeedManager.Subscribe(request.feed, types.WebSocketFeed, conn, ci, ro, true)
+	sub, errSubscribe := h.FeedManager.SubscribeClient(request.feed, types.WebSocketFeed, conn, ci, ro, true)
 	if errSubscribe != nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errSubscribe.Error(), conn, req.ID)
 		return
@@ -131,7 +131,7 @@ func (h *handlerObj) handleEthSubscribeNewHeads(ctx context.Context, conn *jsonr
 	}
 	// since we are replacing newPendingTransactions with newTxs/pendingTx, any existing newTxs/pendingTxs suppose to make newPendingTransactions a duplicate subscription.
 	// But this is used only in external gateway where gateway account id is the same with request account id, so this is avoided
-	sub, errSubscribe := h.FeedManager.Subscribe(request.feed, types.WebSocketFeed, conn, ci, ro, true)
+	sub, errSubscribe := h.FeedManager.SubscribeClient(request.feed, types.WebSocketFeed, conn, ci, ro, true)
 	if errSubscribe != nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errSubscribe.Error(), conn, req.ID)
 		return
@@ -227,7 +227,7 @@ func (h *handlerObj) handleEthSubscribeFeed(ctx context.Context, feedType string
 
 // sendTxNotificationEthSubscribeFormat - build a response according to client request and notify client
 func (h *handlerObj) sendTxNotificationEthFormat(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, tx *types.NewTransactionNotification) error {
-	result := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID)
+	result := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID, h.FeedManager.clockSkew())
 	if result == nil {
 		return nil
 	}
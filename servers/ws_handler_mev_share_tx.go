@@ -0,0 +1,62 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+func (h *handlerObj) handleRPCMEVShareTx(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if h.FeedManager.accountModel.AccountID != h.connectionAccount.AccountID {
+		errDifferentAccAuth := fmt.Sprintf(errFDifferentAccAuth, jsonrpc.RPCMEVShareTx)
+		h.log.Errorf("%v. account auth: %v, node account: %v", errDifferentAccAuth, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
+		SendErrorMsg(ctx, jsonrpc.InvalidRequest, errDifferentAccAuth, conn, req.ID)
+		return
+	}
+
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCMEVShareTxPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCMEVShareTx, err), conn, req.ID)
+		return
+	}
+	if err := params.Validate(); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	if params.Timeout > uint64(maxPrivateTxTimeout/time.Second) {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("invalid timeout %v, must be at most %v seconds",
+			params.Timeout, maxPrivateTxTimeout.Seconds()), conn, req.ID)
+		return
+	}
+
+	timeout := time.Duration(params.Timeout) * time.Second
+	ws := connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
+	txHash, err := HandleMEVShareTransaction(h.FeedManager, params.Transaction, params.Hints, ws, timeout)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	response := rpcTxResponse{
+		TxHash: txHash,
+	}
+	if err = conn.Reply(ctx, req.ID, response); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		return
+	}
+
+	h.log.Infof("blxr_mev_share_tx: hash - 0x%v, hints %v, timeout %v", response.TxHash, params.Hints, timeout)
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
 	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/services"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/gorilla/websocket"
 	"github.com/sourcegraph/jsonrpc2"
@@ -24,6 +26,15 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		return
 	}
 
+	if isCombinedSubscription(req) {
+		if !h.FeedManager.featureFlags.Enabled(services.FeatureCombinedFeed) {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf(errFFeatureDisabled, types.CombinedFeed+" subscription"), conn, req.ID)
+			return
+		}
+		h.handleRPCSubscribeCombined(ctx, conn, req)
+		return
+	}
+
 	request, err := h.createClientReq(req)
 	if err != nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
@@ -54,14 +65,26 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		MetaInfo:      h.headers,
 	}
 
-	sub, errSubscribe := h.FeedManager.Subscribe(request.feed, types.WebSocketFeed, conn, ci, ro, false)
+	if err = h.checkSubscriptionLimits(conn, request); err != nil {
+		request.wasmFilter.Close()
+		SendErrorMsg(ctx, jsonrpc.SubscriptionLimit, err.Error(), conn, req.ID)
+		return
+	}
+
+	sub, errSubscribe := h.FeedManager.SubscribeClient(request.feed, types.WebSocketFeed, conn, ci, ro, false)
 	if errSubscribe != nil {
+		request.wasmFilter.Close()
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errSubscribe.Error(), conn, req.ID)
 		return
 	}
 	subscriptionID := sub.SubscriptionID
+	// the filters of the request are closed when the subscription is removed
+	h.FeedManager.setSubscriptionRequest(subscriptionID, request)
+	h.FeedManager.setSubscriptionBackpressure(subscriptionID, request.backpressure)
+	h.FeedManager.setSubscriptionHeaderFirst(subscriptionID, request.headerFirst)
+	h.FeedManager.trackTxStatus(subscriptionID, h.connectionAccount.AccountID, request.txStatus)
 
-	defer h.FeedManager.Unsubscribe(subscriptionID, false, "")
+	defer h.FeedManager.releaseSubscription(subscriptionID, sub.HandOffChan)
 
 	if err = conn.Reply(ctx, req.ID, subscriptionID); err != nil {
 		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
@@ -78,13 +101,27 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 		filters,
 		"")
 
+	h.serveSubscription(ctx, conn, req, sub, request)
+}
+
+// serveSubscription streams the subscription notifications to the connection until it is closed or handed off
+func (h *handlerObj) serveSubscription(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request, sub *ClientSubscriptionHandlingInfo, request *clientReq) {
+	subscriptionID := sub.SubscriptionID
+	feedName := request.feed
+	// a panic on a notification ends the subscription, not the connection
+	defer h.FeedManager.recoverPanic(panicScopeSubscription, string(feedName), func(interface{}) {
+		SendErrorMsg(ctx, jsonrpc.InternalError, "internal error", conn, req.ID)
+	})
+	request.backlog = func() int { return len(sub.FeedChan) }
+	request.delivery = sub.delivery
+
 	if request.MultiTxs {
 		if feedName != types.NewTxsFeed && feedName != types.PendingTxsFeed {
 			log.Debugf("multi tx support only in new txs or pending txs, subscription id %v, account id %v, remote addr %v", subscriptionID, h.connectionAccount.AccountID, h.remoteAddress)
 			SendErrorMsg(ctx, jsonrpc.InvalidParams, "multi tx support only in new txs or pending txs", conn, req.ID)
 			return
 		}
-		err = h.subscribeMultiTxs(ctx, sub.FeedChan, subscriptionID, request, conn, req, feedName)
+		err := h.subscribeMultiTxs(ctx, sub.FeedChan, sub.HandOffChan, subscriptionID, request, conn, req, feedName)
 		if err != nil {
 			log.Errorf("error while processing %v (%v) with multi tx argument: %v", feedName, subscriptionID, err)
 			return
@@ -94,16 +131,39 @@ func (h *handlerObj) handleRPCSubscribe(ctx context.Context, conn *jsonrpc2.Conn
 	h.handleRPCSubscribeNotify(ctx, conn, req.ID, sub, subscriptionID, feedName, request)
 }
 
+// connContext returns a context derived from ctx which is canceled once the connection is closed, so the node RPC
+// calls of its subscriptions stop on the disconnection of the client
+func connContext(ctx context.Context, conn *jsonrpc2.Conn) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-conn.DisconnectNotify():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc2.Conn,
 	reqID jsonrpc2.ID, sub *ClientSubscriptionHandlingInfo, subscriptionID string, feedName types.FeedType, request *clientReq) {
+	// the node calls of the subscription are also canceled once it is handed off or unsubscribed
+	callCtx, cancel := connContext(ctx, conn)
+	defer cancel()
 
 	for {
 		select {
 		case <-conn.DisconnectNotify():
 			return
+		case <-sub.HandOffChan:
+			return
 		case errMsg := <-sub.ErrMsgChan:
 			SendErrorMsg(ctx, jsonrpc.InvalidParams, errMsg, conn, reqID)
 			return
+		case <-request.ordering.alert():
+			if h.sendOrderedBlocks(ctx, subscriptionID, request, conn, request.ordering.flush()) != nil {
+				return
+			}
 		case notification, ok := <-(sub.FeedChan):
 			if !ok {
 				if h.FeedManager.SubscriptionExists(subscriptionID) {
@@ -111,6 +171,7 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 				}
 				return
 			}
+			request.delivery.receive()
 
 			switch feedName {
 			case types.NewTxsFeed:
@@ -123,22 +184,47 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 				if h.sendTxNotification(ctx, subscriptionID, request, conn, &tx.NewTransactionNotification) != nil {
 					return
 				}
-			case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed:
-				if h.sendNotification(ctx, subscriptionID, request, conn, notification) != nil {
+			case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.NextSprintValidatorsFeed,
+				types.ProposerDutiesFeed, types.TopOfBlockFeed, types.ContractCreationsFeed, types.TransactionStatusFeed, types.MEVShareFeed,
+				types.BundleStatusFeed, types.ShortIDsFeed:
+				if request.ordering != nil {
+					if h.sendOrderedBlocks(ctx, subscriptionID, request, conn, request.ordering.add(notification.(*types.EthBlockNotification))) != nil {
+						return
+					}
+				} else if h.sendNotification(ctx, subscriptionID, request, conn, notification) != nil {
 					return
 				}
 			case types.TxReceiptsFeed:
 				if h.sendTxReceiptNotification(ctx, subscriptionID, request, conn, notification) != nil {
 					return
 				}
+			case types.LogsFeed:
+				logs, err := fetchLogs(callCtx, h.FeedManager, notification.(*types.EthBlockNotification), request.logs)
+				if err != nil {
+					h.log.Debugf("failed to fetch logs of block %v for subscriptionID %v: %v", notification.GetHash(), subscriptionID, err)
+				}
+				for _, l := range logs {
+					if h.sendNotification(ctx, subscriptionID, request, conn, l) != nil {
+						return
+					}
+				}
 			case types.OnBlockFeed:
 				block := notification.(*types.EthBlockNotification)
 
-				sendEthOnBlockWsNotification := func(notification *types.OnBlockNotification) error {
-					return h.sendNotification(ctx, subscriptionID, request, conn, notification)
+				var err error
+				if request.onBlockPageSize > 0 {
+					err = handleEthOnBlockPages(callCtx, h.FeedManager, block, *request.calls, request.onBlockPageSize, func(page *types.OnBlockPageNotification) error {
+						return h.sendNotification(ctx, subscriptionID, request, conn, page)
+					})
+				} else {
+					sendEthOnBlockWsNotification := func(notification *types.OnBlockNotification) error {
+						return h.sendNotification(ctx, subscriptionID, request, conn, notification)
+					}
+					err = handleEthOnBlock(callCtx, h.FeedManager, block, *request.calls, sendEthOnBlockWsNotification)
+				}
+				if callCtx.Err() != nil {
+					return
 				}
-
-				err := handleEthOnBlock(h.FeedManager, block, *request.calls, sendEthOnBlockWsNotification)
 				if err != nil {
 					SendErrorMsg(ctx, jsonrpc.InvalidRequest, err.Error(), conn, reqID)
 					return
@@ -148,9 +234,45 @@ func (h *handlerObj) handleRPCSubscribeNotify(ctx context.Context, conn *jsonrpc
 	}
 }
 
+// sendOrderedBlocks sends the blocks of a StrictOrdering subscription with the reorg events preceding them
+func (h *handlerObj) sendOrderedBlocks(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, blocks []orderedBlock) error {
+	for _, block := range blocks {
+		if block.reorg == nil {
+			if err := h.sendNotification(ctx, subscriptionID, clientReq, conn, block.notification); err != nil {
+				return err
+			}
+			continue
+		}
+
+		response := blockReorgResponse{Subscription: subscriptionID, Result: block.reorg}
+		if err := notifyWithStats(ctx, conn, clientReq.delivery, 1, response); err != nil {
+			h.log.Errorf("error notifying subscriptionID %v: %v", subscriptionID, err)
+			return err
+		}
+		h.FeedManager.exporter.Export(h.connectionAccount.AccountID, clientReq.feed, response)
+	}
+	return nil
+}
+
+// filterIncludeAndDedup builds the tx result for the client request, returning nil if the tx is filtered out or was
+// already delivered on another feed of the connection within the dedup window of the request
+func (h *handlerObj) filterIncludeAndDedup(clientReq *clientReq, tx *types.NewTransactionNotification) *TxResult {
+	result := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID, h.FeedManager.clockSkew())
+	if result == nil || clientReq.dedupWindow <= 0 || h.feedDedup == nil {
+		return result
+	}
+
+	seenOn, deliver := h.feedDedup.check(tx.GetHash(), clientReq.feed, clientReq.dedupWindow)
+	if !deliver {
+		return nil
+	}
+	result.SeenOn = seenOn
+	return result
+}
+
 // sendTxNotification - build a response according to client request and notify client
 func (h *handlerObj) sendTxNotification(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, tx *types.NewTransactionNotification) error {
-	result := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID)
+	result := h.filterIncludeAndDedup(clientReq, tx)
 	if result == nil {
 		return nil
 	}
@@ -158,14 +280,7 @@ func (h *handlerObj) sendTxNotification(ctx context.Context, subscriptionID stri
 		Subscription: subscriptionID,
 		Result:       *result,
 	}
-
-	err := conn.Notify(ctx, "subscribe", response)
-	if err != nil {
-		h.log.Errorf("error notifying subscriptionID %v: %v", subscriptionID, err)
-		return err
-	}
-
-	return nil
+	return h.notifySubscription(ctx, conn, subscriptionID, clientReq, response, result)
 }
 
 func (h *handlerObj) sendTxReceiptNotification(ctx context.Context, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, notification types.Notification) error {
@@ -175,9 +290,7 @@ func (h *handlerObj) sendTxReceiptNotification(ctx context.Context, subscription
 	content := notification.WithFields(clientReq.includes).(*types.TxReceiptsNotification)
 	for _, receipt := range content.Receipts {
 		response.Result = receipt
-		err := conn.Notify(ctx, "subscribe", response)
-		if err != nil {
-			h.log.Errorf("error reply to subscriptionID %v: %v", subscriptionID, err.Error())
+		if err := h.notifySubscription(ctx, conn, subscriptionID, clientReq, response, receipt); err != nil {
 			return err
 		}
 	}
@@ -185,75 +298,94 @@ func (h *handlerObj) sendTxReceiptNotification(ctx context.Context, subscription
 	return nil
 }
 
-func (h *handlerObj) subscribeMultiTxs(ctx context.Context, feedChan chan types.Notification, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, req *jsonrpc2.Request, feedName types.FeedType) error {
+func (h *handlerObj) subscribeMultiTxs(ctx context.Context, feedChan chan types.Notification, handOff chan struct{}, subscriptionID string, clientReq *clientReq, conn *jsonrpc2.Conn, req *jsonrpc2.Request, feedName types.FeedType) error {
+	batch := MultiTransactions{Subscription: subscriptionID}
+	// flush fires once the first tx of the batch waited for the max batch delay, it is nil while the batch is empty
+	var flush <-chan time.Time
+	var flushTimer *time.Timer
+	defer func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+		}
+	}()
+
 	for {
-		select {
-		case <-conn.DisconnectNotify():
-			return nil
-		case notification, ok := <-feedChan:
-			if !ok {
-				if h.FeedManager.SubscriptionExists(subscriptionID) {
-					SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
+		var notification types.Notification
+		var ok bool
+		if len(batch.Result) > 0 && clientReq.maxBatchDelay == 0 {
+			// without a delay the batch is sent as soon as the subscription channel is drained
+			select {
+			case <-conn.DisconnectNotify():
+				return nil
+			case <-handOff:
+				return nil
+			case notification, ok = <-feedChan:
+			default:
+				if err := h.sendMultiTxs(ctx, conn, clientReq, &batch); err != nil {
+					return err
 				}
-				return errReadingNotification
+				continue
 			}
-
-			continueProcessing := true
-			multiTxsResponse := MultiTransactions{Subscription: subscriptionID}
-
-			switch feedName {
-			case types.NewTxsFeed:
-				tx := (notification).(*types.NewTransactionNotification)
-				response := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID)
-				if response != nil {
-					multiTxsResponse.Result = append(multiTxsResponse.Result, *response)
-				}
-			case types.PendingTxsFeed:
-				tx := (notification).(*types.PendingTransactionNotification)
-				response := filterAndInclude(clientReq, &tx.NewTransactionNotification, h.remoteAddress, h.connectionAccount.AccountID)
-				if response != nil {
-					multiTxsResponse.Result = append(multiTxsResponse.Result, *response)
+		} else {
+			select {
+			case <-conn.DisconnectNotify():
+				return nil
+			case <-handOff:
+				return nil
+			case <-flush:
+				flush = nil
+				if err := h.sendMultiTxs(ctx, conn, clientReq, &batch); err != nil {
+					return err
 				}
+				continue
+			case notification, ok = <-feedChan:
 			}
-			for continueProcessing {
-				select {
-				case <-conn.DisconnectNotify():
-					return nil
-				case notification, ok := <-feedChan:
-					if !ok {
-						if h.FeedManager.SubscriptionExists(subscriptionID) {
-							SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
-						}
-						return errReadingNotification
-					}
-					switch feedName {
-					case types.NewTxsFeed:
-						tx := (notification).(*types.NewTransactionNotification)
-						response := filterAndInclude(clientReq, tx, h.remoteAddress, h.connectionAccount.AccountID)
-						if response != nil {
-							multiTxsResponse.Result = append(multiTxsResponse.Result, *response)
-						}
-					case types.PendingTxsFeed:
-						tx := (notification).(*types.PendingTransactionNotification)
-						response := filterAndInclude(clientReq, &tx.NewTransactionNotification, h.remoteAddress, h.connectionAccount.AccountID)
-						if response != nil {
-							multiTxsResponse.Result = append(multiTxsResponse.Result, *response)
-						}
-					}
-					if len(multiTxsResponse.Result) >= 50 {
-						continueProcessing = false
-					}
-				default:
-					continueProcessing = false
-				}
+		}
+		if !ok {
+			if h.FeedManager.SubscriptionExists(subscriptionID) {
+				SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
 			}
-			if len(multiTxsResponse.Result) > 0 {
-				err := conn.Notify(ctx, "subscribe", multiTxsResponse)
-				if err != nil {
-					h.log.Errorf("error notifying subscriptionID %v: %v", subscriptionID, err)
-					return err
-				}
+			return errReadingNotification
+		}
+
+		if len(batch.Result) == 0 {
+			clientReq.delivery.receive()
+		}
+		var response *TxResult
+		switch feedName {
+		case types.NewTxsFeed:
+			response = h.filterIncludeAndDedup(clientReq, notification.(*types.NewTransactionNotification))
+		case types.PendingTxsFeed:
+			response = h.filterIncludeAndDedup(clientReq, &notification.(*types.PendingTransactionNotification).NewTransactionNotification)
+		}
+		if response == nil {
+			continue
+		}
+		batch.Result = append(batch.Result, *response)
+
+		if len(batch.Result) >= clientReq.maxBatchSize {
+			if flushTimer != nil {
+				flushTimer.Stop()
+			}
+			flush = nil
+			if err := h.sendMultiTxs(ctx, conn, clientReq, &batch); err != nil {
+				return err
 			}
+		} else if len(batch.Result) == 1 && clientReq.maxBatchDelay > 0 {
+			flushTimer = time.NewTimer(clientReq.maxBatchDelay)
+			flush = flushTimer.C
 		}
 	}
 }
+
+// sendMultiTxs sends the batch of txs as a single message and starts a new batch
+func (h *handlerObj) sendMultiTxs(ctx context.Context, conn *jsonrpc2.Conn, clientReq *clientReq, batch *MultiTransactions) error {
+	err := notifyWithStats(ctx, conn, clientReq.delivery, len(batch.Result), *batch)
+	if err != nil {
+		h.log.Errorf("error notifying subscriptionID %v: %v", batch.Subscription, err)
+		return err
+	}
+	h.FeedManager.exporter.Export(h.connectionAccount.AccountID, clientReq.feed, *batch)
+	batch.Result = nil
+	return nil
+}
@@ -0,0 +1,345 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// CombinedEvent - event of a combined subscription, with the feed it was published on
+type CombinedEvent struct {
+	Feed  types.FeedType `json:"feed"`
+	Event interface{}    `json:"event"`
+}
+
+// CombinedResponse - response of a combined subscription
+type CombinedResponse struct {
+	Subscription string        `json:"subscription"`
+	Result       CombinedEvent `json:"result"`
+}
+
+type combinedSubscriptionOptions struct {
+	Feeds map[types.FeedType]subscriptionOptions `json:"Feeds"`
+	// send a blockComplete event after the block and all the notifications derived from it were sent
+	BlockBarrier bool `json:"BlockBarrier"`
+}
+
+type combinedFeedSubscription struct {
+	sub     *ClientSubscriptionHandlingInfo
+	request *clientReq
+}
+
+type combinedNotification struct {
+	request      *clientReq
+	notification types.Notification
+}
+
+type combinedFeedClosed struct {
+	subscriptionID string
+	errMsg         string
+}
+
+// isCombinedSubscription returns true if the subscribe request is for the combined feed
+func isCombinedSubscription(req *jsonrpc2.Request) bool {
+	var rpcParams []json.RawMessage
+	if req.Params == nil || json.Unmarshal(*req.Params, &rpcParams) != nil || len(rpcParams) == 0 {
+		return false
+	}
+	var feed types.FeedType
+	return json.Unmarshal(rpcParams[0], &feed) == nil && feed == types.CombinedFeed
+}
+
+// createCombinedClientReqs validates the options of each feed of a combined subscription request, and returns the
+// block barrier of the subscription if requested
+func (h *handlerObj) createCombinedClientReqs(req *jsonrpc2.Request) ([]*clientReq, *blockBarrier, error) {
+	var rpcParams []json.RawMessage
+	if err := json.Unmarshal(*req.Params, &rpcParams); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+	if len(rpcParams) < 2 {
+		return nil, nil, fmt.Errorf("received invalid number of params: expected 2, got %d, params %s", len(rpcParams), string(*req.Params))
+	}
+
+	var options combinedSubscriptionOptions
+	if err := json.Unmarshal(rpcParams[1], &options); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal options: %w", err)
+	}
+	if len(options.Feeds) == 0 {
+		return nil, nil, errors.New("combined subscription requires at least one feed in Feeds")
+	}
+
+	feeds := make([]types.FeedType, 0, len(options.Feeds))
+	for feed := range options.Feeds {
+		feeds = append(feeds, feed)
+	}
+	sort.Slice(feeds, func(i, j int) bool { return feeds[i] < feeds[j] })
+
+	requests := make([]*clientReq, 0, len(feeds))
+	for _, feed := range feeds {
+		feedOptions := options.Feeds[feed]
+		if feed == types.CombinedFeed {
+			return nil, nil, fmt.Errorf("%v feed is not supported in a combined subscription", feed)
+		}
+		if feedOptions.MultiTxs {
+			return nil, nil, errors.New("combined subscription does not support MultiTxs")
+		}
+		if feedOptions.WasmFilter != "" {
+			return nil, nil, errors.New("combined subscription does not support WasmFilter")
+		}
+		if feedOptions.StrictOrdering {
+			return nil, nil, errors.New("combined subscription does not support StrictOrdering")
+		}
+
+		request, err := h.newClientReq(req, subscriptionRequest{feed: feed, options: feedOptions})
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", feed, err)
+		}
+		requests = append(requests, request)
+	}
+
+	if !options.BlockBarrier {
+		return requests, nil, nil
+	}
+
+	_, newBlocks := options.Feeds[types.NewBlocksFeed]
+	_, bdnBlocks := options.Feeds[types.BDNBlocksFeed]
+	_, txReceipts := options.Feeds[types.TxReceiptsFeed]
+	_, onBlock := options.Feeds[types.OnBlockFeed]
+	if newBlocks == bdnBlocks || (!txReceipts && !onBlock) {
+		return nil, nil, fmt.Errorf("BlockBarrier requires exactly one of %v or %v and at least one of %v or %v",
+			types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed, types.OnBlockFeed)
+	}
+	return requests, newBlockBarrier(feeds), nil
+}
+
+// handleRPCSubscribeCombined subscribes to several feeds and multiplexes their notifications into a single stream,
+// the subscription ID of the first feed identifies the combined subscription
+func (h *handlerObj) handleRPCSubscribeCombined(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	requests, barrier, err := h.createCombinedClientReqs(req)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+	if err = h.checkSubscriptionLimits(conn, requests...); err != nil {
+		SendErrorMsg(ctx, jsonrpc.SubscriptionLimit, err.Error(), conn, req.ID)
+		return
+	}
+
+	ci := types.ClientInfo{
+		RemoteAddress: h.remoteAddress,
+		AccountID:     h.connectionAccount.AccountID,
+		Tier:          string(h.connectionAccount.TierName),
+		MetaInfo:      h.headers,
+	}
+
+	subscriptions := make([]combinedFeedSubscription, 0, len(requests))
+	defer func() {
+		for _, subscription := range subscriptions {
+			h.FeedManager.releaseSubscription(subscription.sub.SubscriptionID, subscription.sub.HandOffChan)
+		}
+	}()
+
+	for _, request := range requests {
+		var filters string
+		if request.expr != nil {
+			filters = request.expr.String()
+		}
+		ro := types.ReqOptions{
+			Filters:  filters,
+			Includes: strings.Join(request.includes, ","),
+		}
+
+		sub, errSubscribe := h.FeedManager.SubscribeClient(request.feed, types.WebSocketFeed, conn, ci, ro, false)
+		if errSubscribe != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("%v: %v", request.feed, errSubscribe), conn, req.ID)
+			return
+		}
+		request.backlog = func() int { return len(sub.FeedChan) }
+		request.delivery = sub.delivery
+		h.FeedManager.setSubscriptionBackpressure(sub.SubscriptionID, request.backpressure)
+		h.FeedManager.setSubscriptionHeaderFirst(sub.SubscriptionID, request.headerFirst)
+		h.FeedManager.trackTxStatus(sub.SubscriptionID, h.connectionAccount.AccountID, request.txStatus)
+		subscriptions = append(subscriptions, combinedFeedSubscription{sub: sub, request: request})
+
+		h.FeedManager.stats.LogSubscribeStats(sub.SubscriptionID,
+			h.connectionAccount.AccountID,
+			request.feed,
+			h.connectionAccount.TierName,
+			h.remoteAddress,
+			h.FeedManager.networkNum,
+			request.includes,
+			filters,
+			"")
+	}
+
+	subscriptionID := subscriptions[0].sub.SubscriptionID
+	if err = conn.Reply(ctx, req.ID, subscriptionID); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
+		return
+	}
+
+	h.serveCombinedSubscription(ctx, conn, req.ID, subscriptionID, subscriptions, barrier)
+}
+
+// serveCombinedSubscription streams the notifications of all the feeds in the order they are received until the
+// connection is closed or any of the feeds is unsubscribed
+func (h *handlerObj) serveCombinedSubscription(ctx context.Context, conn *jsonrpc2.Conn, reqID jsonrpc2.ID, subscriptionID string, subscriptions []combinedFeedSubscription, barrier *blockBarrier) {
+	notifications := make(chan combinedNotification, bxgateway.BxNotificationChannelSize)
+	closed := make(chan combinedFeedClosed, len(subscriptions))
+	done := make(chan struct{})
+	defer close(done)
+	defer h.FeedManager.recoverPanic(panicScopeSubscription, "combined", func(interface{}) {
+		SendErrorMsg(ctx, jsonrpc.InternalError, "internal error", conn, reqID)
+	})
+	callCtx, cancel := connContext(ctx, conn)
+	defer cancel()
+
+	for _, subscription := range subscriptions {
+		go func(subscription combinedFeedSubscription) {
+			for {
+				select {
+				case <-done:
+					return
+				case notification, ok := <-subscription.sub.FeedChan:
+					if !ok {
+						feedClosed := combinedFeedClosed{subscriptionID: subscription.sub.SubscriptionID}
+						select {
+						case feedClosed.errMsg = <-subscription.sub.ErrMsgChan:
+						default:
+						}
+						closed <- feedClosed
+						return
+					}
+					select {
+					case notifications <- combinedNotification{request: subscription.request, notification: notification}:
+					case <-done:
+						return
+					}
+				}
+			}
+		}(subscription)
+	}
+
+	for {
+		select {
+		case <-conn.DisconnectNotify():
+			return
+		case feedClosed := <-closed:
+			if feedClosed.errMsg != "" {
+				SendErrorMsg(ctx, jsonrpc.InvalidParams, feedClosed.errMsg, conn, reqID)
+			} else if h.FeedManager.SubscriptionExists(feedClosed.subscriptionID) {
+				SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, reqID)
+			}
+			return
+		case n := <-notifications:
+			n.request.delivery.receive()
+			for _, event := range h.combinedEvents(callCtx, n.request, n.notification) {
+				event, err := n.request.transform.apply(event)
+				if err != nil {
+					h.log.Errorf("failed to transform %v notification of subscriptionID %v: %v", n.request.feed, subscriptionID, err)
+					continue
+				}
+				if h.sendCombinedEvent(ctx, conn, subscriptionID, n.request.feed, n.request.delivery, event) != nil {
+					return
+				}
+			}
+
+			if barrier == nil {
+				continue
+			}
+			if blockComplete, ok := barrier.complete(n.request.feed, n.notification); ok {
+				if h.sendCombinedEvent(ctx, conn, subscriptionID, bxgateway.BlockCompleteEvent, nil, blockComplete) != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendCombinedEvent sends the event of the feed, recording it in the delivery stats of the feed subscription if any
+func (h *handlerObj) sendCombinedEvent(ctx context.Context, conn *jsonrpc2.Conn, subscriptionID string, feed types.FeedType, delivery *deliveryStats, event interface{}) error {
+	response := CombinedResponse{
+		Subscription: subscriptionID,
+		Result: CombinedEvent{
+			Feed:  feed,
+			Event: event,
+		},
+	}
+	err := notifyWithStats(ctx, conn, delivery, 1, response)
+	if err != nil {
+		h.log.Errorf("error notifying subscriptionID %v: %v", subscriptionID, err)
+		return err
+	}
+	h.FeedManager.exporter.Export(h.connectionAccount.AccountID, feed, response)
+	return nil
+}
+
+// combinedEvents builds the events of the notification according to the feed request
+func (h *handlerObj) combinedEvents(ctx context.Context, request *clientReq, notification types.Notification) []interface{} {
+	switch request.feed {
+	case types.NewTxsFeed:
+		if result := h.filterIncludeAndDedup(request, notification.(*types.NewTransactionNotification)); result != nil {
+			return []interface{}{result}
+		}
+		return nil
+	case types.PendingTxsFeed:
+		tx := notification.(*types.PendingTransactionNotification)
+		if result := h.filterIncludeAndDedup(request, &tx.NewTransactionNotification); result != nil {
+			return []interface{}{result}
+		}
+		return nil
+	case types.TxReceiptsFeed:
+		content := notification.WithFields(request.includes).(*types.TxReceiptsNotification)
+		events := make([]interface{}, 0, len(content.Receipts))
+		for _, receipt := range content.Receipts {
+			events = append(events, receipt)
+		}
+		return events
+	case types.LogsFeed:
+		logs, err := fetchLogs(ctx, h.FeedManager, notification.(*types.EthBlockNotification), request.logs)
+		if err != nil {
+			h.log.Debugf("failed to handle %v for block %v: %v", request.feed, notification.GetHash(), err)
+		}
+		events := make([]interface{}, 0, len(logs))
+		for _, l := range logs {
+			events = append(events, l.WithFields(request.includes))
+		}
+		return events
+	case types.OnBlockFeed:
+		var lock sync.Mutex
+		var events []interface{}
+		if request.onBlockPageSize > 0 {
+			err := handleEthOnBlockPages(ctx, h.FeedManager, notification.(*types.EthBlockNotification), *request.calls, request.onBlockPageSize, func(page *types.OnBlockPageNotification) error {
+				events = append(events, page.WithFields(request.includes))
+				return nil
+			})
+			if err != nil {
+				h.log.Debugf("failed to handle %v for block %v: %v", request.feed, notification.GetHash(), err)
+			}
+			return events
+		}
+		err := handleEthOnBlock(ctx, h.FeedManager, notification.(*types.EthBlockNotification), *request.calls, func(notification *types.OnBlockNotification) error {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, notification.WithFields(request.includes))
+			return nil
+		})
+		if err != nil {
+			h.log.Debugf("failed to handle %v for block %v: %v", request.feed, notification.GetHash(), err)
+		}
+		return events
+	default:
+		return []interface{}{notification.WithFields(request.includes)}
+	}
+}
@@ -7,16 +7,34 @@ import (
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2"
+	"github.com/bloXroute-Labs/gateway/v2/config"
 	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/services/wasmfilter"
 	"github.com/bloXroute-Labs/gateway/v2/types"
 	"github.com/bloXroute-Labs/gateway/v2/utils"
 	"github.com/sourcegraph/jsonrpc2"
 	"github.com/zhouzhuojie/conditions"
 )
 
+const (
+	// maxDedupWindow is the longest cross-feed dedup window a subscription can request
+	maxDedupWindow = time.Minute
+	// maxOnBlockPageSize is the largest page of ethOnBlock results a subscription can request
+	maxOnBlockPageSize = 1000
+	// defaultMultiTxsBatchSize is the largest number of txs of a MultiTxs message unless the subscription sets it
+	defaultMultiTxsBatchSize = 50
+	// maxMultiTxsBatchSize is the largest MaxBatchSize a MultiTxs subscription can request
+	maxMultiTxsBatchSize = 1000
+	// maxMultiTxsBatchDelay is the longest MaxBatchDelayMs a MultiTxs subscription can request
+	maxMultiTxsBatchDelay = time.Second
+)
+
 var (
 	availableFeeds = []types.FeedType{types.NewTxsFeed, types.NewBlocksFeed, types.BDNBlocksFeed, types.PendingTxsFeed,
-		types.OnBlockFeed, types.TxReceiptsFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed}
+		types.OnBlockFeed, types.TxReceiptsFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.NextSprintValidatorsFeed,
+		types.ProposerDutiesFeed, types.TopOfBlockFeed, types.ContractCreationsFeed, types.LogsFeed, types.TransactionStatusFeed,
+		types.MEVShareFeed, types.BundleStatusFeed, types.ShortIDsFeed}
 
 	txContentFields = []string{"tx_contents.nonce", "tx_contents.tx_hash",
 		"tx_contents.gas_price", "tx_contents.gas", "tx_contents.to", "tx_contents.value", "tx_contents.input",
@@ -27,13 +45,23 @@ var (
 
 	txContentFieldsWithFrom = append(txContentFields, "tx_contents.from")
 
-	validTxParams        = append(txContentFields, "tx_contents", "tx_contents.from", "tx_hash", "local_region", "time", "raw_tx")
-	validBlockParams     = append(txContentFields, "tx_contents.from", "hash", "header", "transactions", "uncles", "future_validator_info", "withdrawals")
+	validTxParams        = append(txContentFields, "tx_contents", "tx_contents.from", "tx_hash", "local_region", "time", "raw_tx", enrichInclude)
+	validBlockParams     = append(txContentFields, "tx_contents.from", "hash", "header", "transactions", "uncles", "future_validator_info", "withdrawals", "withdrawals_root")
 	validTxReceiptParams = []string{"block_hash", "block_number", "contract_address",
 		"cumulative_gas_used", "effective_gas_price", "from", "gas_used", "logs", "logs_bloom",
-		"status", "to", "transaction_hash", "transaction_index", "type", "txs_count"}
-	validOnBlockParams     = []string{"name", "response", "block_height", "tag"}
-	validBeaconBlockParams = []string{"hash", "header", "slot", "body"}
+		"status", "to", "transaction_hash", "transaction_index", "type", "txs_count",
+		"effective_priority_fee", "inclusion_delay_ms", "position_in_block"}
+	validOnBlockParams      = []string{"name", "response", "block_height", "tag"}
+	validBeaconBlockParams  = []string{"hash", "header", "slot", "body"}
+	validNextSprintParams   = []string{"block_height", "validator_list"}
+	validProposerDutyParams = []string{"slot", "validator_index", "pubkey", "relays"}
+	validTopOfBlockParams   = []string{"hash", "block_height", "base_fee_per_gas", "txs"}
+
+	validContractCreationParams = []string{"tx_hash", "from", "contract_address", "block_hash", "block_number", "status", "gas_used"}
+	validTxStatusParams         = []string{"transaction_hash", "status", "block_number", "block_hash"}
+	validMEVShareParams         = []string{"hash", "to", "function_selector", "calldata", "logs"}
+	validBundleStatusParams     = []string{"uuid", "bundle_hash", "status", "builders", "simulation_error", "block_number", "block_hash"}
+	validShortIDParams          = []string{"tx_hash", "short_id", "network_num"}
 
 	availableFeedsMap = make(map[types.FeedType]struct{})
 	validParamsMap    = make(map[types.FeedType]map[string]struct{})
@@ -54,6 +82,16 @@ func init() {
 		types.TxReceiptsFeed:      stringSliceToSet(validTxReceiptParams),
 		types.NewBeaconBlocksFeed: stringSliceToSet(validBeaconBlockParams),
 		types.BDNBeaconBlocksFeed: stringSliceToSet(validBeaconBlockParams),
+
+		types.NextSprintValidatorsFeed: stringSliceToSet(validNextSprintParams),
+		types.ProposerDutiesFeed:       stringSliceToSet(validProposerDutyParams),
+		types.TopOfBlockFeed:           stringSliceToSet(validTopOfBlockParams),
+		types.ContractCreationsFeed:    stringSliceToSet(validContractCreationParams),
+		types.LogsFeed:                 stringSliceToSet(validLogParams),
+		types.TransactionStatusFeed:    stringSliceToSet(validTxStatusParams),
+		types.MEVShareFeed:             stringSliceToSet(validMEVShareParams),
+		types.BundleStatusFeed:         stringSliceToSet(validBundleStatusParams),
+		types.ShortIDsFeed:             stringSliceToSet(validShortIDParams),
 	}
 }
 
@@ -78,26 +116,33 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal feed name: %w", err)
 	}
+	err = json.Unmarshal(rpcParams[1], &request.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal options: %w", err)
+	}
+	return h.newClientReq(req, request)
+}
+
+// newClientReq validates the feed subscription request and builds the client request from it
+func (h *handlerObj) newClientReq(req *jsonrpc2.Request, request subscriptionRequest) (*clientReq, error) {
 	if _, ok := availableFeedsMap[request.feed]; !ok {
 		h.log.Debugf("invalid request feed param from request id: %v, method: %v, params: %s. remote address: %v account id: %v.",
 			req.ID, req.Method, *req.Params, h.remoteAddress, h.connectionAccount.AccountID)
 		return nil, fmt.Errorf("got unsupported feed name %v, possible feeds are: %v", request.feed, availableFeeds)
 	}
 	if h.connectionAccount.AccountID != h.FeedManager.accountModel.AccountID &&
-		(request.feed == types.OnBlockFeed || request.feed == types.TxReceiptsFeed) {
-		err = fmt.Errorf("%v feed is not available via cloud services. %v feed is only supported on gateways", request.feed, request.feed)
+		(request.feed == types.OnBlockFeed || request.feed == types.TxReceiptsFeed || request.feed == types.ContractCreationsFeed ||
+			request.feed == types.LogsFeed || request.feed == types.TransactionStatusFeed || request.feed == types.BundleStatusFeed ||
+			request.feed == types.ShortIDsFeed) {
+		err := fmt.Errorf("%v feed is not available via cloud services. %v feed is only supported on gateways", request.feed, request.feed)
 		h.log.Errorf("%v. caller account ID: %v, node account ID: %v", err, h.connectionAccount.AccountID, h.FeedManager.accountModel.AccountID)
 		return nil, err
 	}
 
-	err = json.Unmarshal(rpcParams[1], &request.options)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal options: %w", err)
-	}
 	if request.options.Include == nil {
 		h.log.Debugf("invalid param from request id: %v. method: %v. params: %s. remote address: %v account id: %v.",
 			req.ID, req.Method, *req.Params, h.remoteAddress, h.connectionAccount.AccountID)
-		return nil, fmt.Errorf("got unsupported params: %v", string(rpcParams[1]))
+		return nil, errors.New("got unsupported params: Include is missing")
 	}
 
 	requestedFields, err := validateIncludeParam(request.feed, request.options.Include, h.txFromFieldIncludable)
@@ -107,15 +152,30 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 
 	request.options.Include = requestedFields
 
+	var calldata *calldataDecoder
+	if len(request.options.ABI) > 0 {
+		if request.feed != types.NewTxsFeed && request.feed != types.PendingTxsFeed {
+			return nil, fmt.Errorf("ABI is only supported in %v and %v", types.NewTxsFeed, types.PendingTxsFeed)
+		}
+		calldata, err = newCalldataDecoder(request.options.ABI)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var expr conditions.Expr
 	if request.options.Filters != "" {
-		expr, err = validateFilters(request.options.Filters, h.txFromFieldIncludable)
+		expr, err = validateCalldataFilters(request.options.Filters, h.txFromFieldIncludable, calldata)
 		if err != nil {
 			h.log.Debugf("error when creating filters. request id: %v. method: %v. params: %s. remote address: %v account id: %v error - %v",
 				req.ID, req.Method, *req.Params, h.remoteAddress, h.connectionAccount.AccountID, err.Error())
 			return nil, fmt.Errorf("error creating Filters: %w", err)
 		}
 	}
+	operations, err := newFilterOperations(expr, h.FeedManager.filterFiles)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Filters: %w", err)
+	}
 
 	// check if valid feed
 	var filters []string
@@ -125,15 +185,17 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 
 	feedStreaming := sdnmessage.BDNFeedService{}
 	switch request.feed {
-	case types.NewTxsFeed:
+	case types.NewTxsFeed, types.MEVShareFeed:
 		feedStreaming = h.connectionAccount.NewTransactionStreaming
 	case types.PendingTxsFeed:
 		feedStreaming = h.connectionAccount.PendingTransactionStreaming
-	case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed:
+	case types.BDNBlocksFeed, types.NewBlocksFeed, types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.NextSprintValidatorsFeed,
+		types.ProposerDutiesFeed, types.TopOfBlockFeed:
 		feedStreaming = h.connectionAccount.NewBlockStreaming
 	case types.OnBlockFeed:
 		feedStreaming = h.connectionAccount.OnBlockFeed
-	case types.TxReceiptsFeed:
+	case types.TxReceiptsFeed, types.ContractCreationsFeed, types.LogsFeed, types.TransactionStatusFeed, types.BundleStatusFeed,
+		types.ShortIDsFeed:
 		feedStreaming = h.connectionAccount.TransactionReceiptFeed
 	}
 
@@ -142,6 +204,86 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 		return nil, err
 	}
 
+	if request.options.DedupWindowMs < 0 || request.options.DedupWindowMs > maxDedupWindow.Milliseconds() {
+		return nil, fmt.Errorf("invalid DedupWindowMs %v, must be between 0 and %v", request.options.DedupWindowMs, maxDedupWindow.Milliseconds())
+	}
+	if request.options.DedupWindowMs > 0 && request.feed != types.NewTxsFeed && request.feed != types.PendingTxsFeed {
+		return nil, fmt.Errorf("dedup window is only supported in %v and %v", types.NewTxsFeed, types.PendingTxsFeed)
+	}
+
+	if request.options.OnBlockPageSize < 0 || request.options.OnBlockPageSize > maxOnBlockPageSize {
+		return nil, fmt.Errorf("invalid OnBlockPageSize %v, must be between 0 and %v", request.options.OnBlockPageSize, maxOnBlockPageSize)
+	}
+	if request.options.OnBlockPageSize > 0 && request.feed != types.OnBlockFeed {
+		return nil, fmt.Errorf("OnBlockPageSize is only supported in %v", types.OnBlockFeed)
+	}
+
+	if request.options.MaxBatchSize < 0 || request.options.MaxBatchSize > maxMultiTxsBatchSize {
+		return nil, fmt.Errorf("invalid MaxBatchSize %v, must be between 0 and %v", request.options.MaxBatchSize, maxMultiTxsBatchSize)
+	}
+	if request.options.MaxBatchDelayMs < 0 || request.options.MaxBatchDelayMs > maxMultiTxsBatchDelay.Milliseconds() {
+		return nil, fmt.Errorf("invalid MaxBatchDelayMs %v, must be between 0 and %v", request.options.MaxBatchDelayMs, maxMultiTxsBatchDelay.Milliseconds())
+	}
+	if (request.options.MaxBatchSize > 0 || request.options.MaxBatchDelayMs > 0) && !request.options.MultiTxs {
+		return nil, errors.New("MaxBatchSize and MaxBatchDelayMs are only supported with MultiTxs")
+	}
+	maxBatchSize := request.options.MaxBatchSize
+	if maxBatchSize == 0 {
+		maxBatchSize = defaultMultiTxsBatchSize
+	}
+
+	var logs *logsOptions
+	if request.feed == types.LogsFeed {
+		logs = &logsOptions{}
+		if request.options.Logs != nil {
+			logs = request.options.Logs
+		}
+		if err = logs.validate(); err != nil {
+			return nil, err
+		}
+	} else if request.options.Logs != nil {
+		return nil, fmt.Errorf("Logs is only supported in %v", types.LogsFeed)
+	}
+
+	backpressure, err := parseBackpressurePolicy(request.options.Backpressure)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.options.HeaderFirst && request.feed != types.NewBlocksFeed {
+		return nil, fmt.Errorf("HeaderFirst is only supported in %v", types.NewBlocksFeed)
+	}
+
+	var ordering *blockOrderer
+	if request.options.StrictOrdering {
+		if request.feed != types.NewBlocksFeed && request.feed != types.BDNBlocksFeed {
+			return nil, fmt.Errorf("StrictOrdering is only supported in %v and %v", types.NewBlocksFeed, types.BDNBlocksFeed)
+		}
+		ordering = newBlockOrderer(utils.RealClock{})
+	}
+
+	var txStatus *txStatusOptions
+	if request.feed == types.TransactionStatusFeed {
+		txStatus, err = newTxStatusOptions(request.options.TxHashes, request.options.AutoTrack)
+		if err != nil {
+			return nil, err
+		}
+	} else if len(request.options.TxHashes) > 0 || request.options.AutoTrack {
+		return nil, fmt.Errorf("TxHashes and AutoTrack are only supported in %v", types.TransactionStatusFeed)
+	}
+
+	if request.options.WasmFilter != "" && request.feed != types.NewTxsFeed && request.feed != types.PendingTxsFeed {
+		return nil, fmt.Errorf("wasm filter is only supported in %v and %v", types.NewTxsFeed, types.PendingTxsFeed)
+	}
+
+	notificationTransform, err := newTransform(request.options.Transform)
+	if err != nil {
+		return nil, err
+	}
+	if notificationTransform != nil && request.options.MultiTxs {
+		return nil, errors.New("transform does not support MultiTxs")
+	}
+
 	calls := make(map[string]*RPCCall)
 	if request.feed == types.OnBlockFeed {
 		for idx, callParams := range request.options.CallParams {
@@ -153,6 +295,18 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 				return nil, err
 			}
 		}
+		if err = h.FeedManager.AllowCalls(h.connectionAccount, config.RateLimitOnBlockCall, len(calls)); err != nil {
+			return nil, err
+		}
+	}
+
+	// created last since the filter must be closed once it is not used
+	var wasmFilter *wasmfilter.Filter
+	if request.options.WasmFilter != "" {
+		wasmFilter, err = h.FeedManager.wasmFilters.NewFilter(h.connectionAccount.AccountID, request.options.WasmFilter)
+		if err != nil {
+			return nil, fmt.Errorf("error creating WasmFilter %v: %w", request.options.WasmFilter, err)
+		}
 	}
 
 	return &clientReq{
@@ -161,9 +315,46 @@ func (h *handlerObj) createClientReq(req *jsonrpc2.Request) (*clientReq, error)
 		expr:     expr,
 		calls:    &calls,
 		MultiTxs: request.options.MultiTxs,
+
+		maxBatchSize:  maxBatchSize,
+		maxBatchDelay: time.Duration(request.options.MaxBatchDelayMs) * time.Millisecond,
+
+		dedupWindow: time.Duration(request.options.DedupWindowMs) * time.Millisecond,
+		wasmFilter:  wasmFilter,
+		transform:   notificationTransform,
+		enrichers:   h.FeedManager.enrichers,
+
+		onBlockPageSize: request.options.OnBlockPageSize,
+		backpressure:    backpressure,
+		logs:            logs,
+		calldata:        calldata,
+		operations:      operations,
+		headerFirst:     request.options.HeaderFirst,
+		txStatus:        txStatus,
+		ordering:        ordering,
 	}, nil
 }
 
+// newTxStatusOptions validates the txs tracked by a transactionStatus subscription
+func newTxStatusOptions(txHashes []string, autoTrack bool) (*txStatusOptions, error) {
+	if len(txHashes) == 0 && !autoTrack {
+		return nil, fmt.Errorf("%v requires TxHashes or AutoTrack", types.TransactionStatusFeed)
+	}
+	if len(txHashes) > services.MaxTrackedTxs {
+		return nil, fmt.Errorf("%v TxHashes, expected at most %v", len(txHashes), services.MaxTrackedTxs)
+	}
+
+	options := &txStatusOptions{hashes: make([]types.SHA256Hash, 0, len(txHashes)), autoTrack: autoTrack}
+	for _, txHash := range txHashes {
+		hash, err := types.NewSHA256HashFromString(txHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx hash %v: %v", txHash, err)
+		}
+		options.hashes = append(options.hashes, hash)
+	}
+	return options, nil
+}
+
 func (h *handlerObj) validateFeed(feedName types.FeedType, feedStreaming sdnmessage.BDNFeedService, includes, filters []string) error {
 	expireDateTime, _ := time.Parse(bxgateway.TimeDateLayoutISO, feedStreaming.ExpireDate)
 	if time.Now().UTC().After(expireDateTime) {
@@ -173,6 +364,10 @@ func (h *handlerObj) validateFeed(feedName types.FeedType, feedStreaming sdnmess
 		return nil
 	}
 	for _, include := range includes {
+		// the enrichment is provided by the gateway, it is not a field of the account feed
+		if include == enrichInclude {
+			continue
+		}
 		if !utils.Exists(include, feedStreaming.Feed.AvailableFields) {
 			return fmt.Errorf("including %v field in %v is not allowed", include, feedName)
 		}
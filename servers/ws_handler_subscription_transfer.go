@@ -0,0 +1,79 @@
+package servers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/gorilla/websocket"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// handleRPCSubscriptionTransferToken issues a transfer token for a subscription owned by the connection account
+func (h *handlerObj) handleRPCSubscriptionTransferToken(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	subscriptionID, err := h.singleStringParam(req)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	token, err := h.FeedManager.CreateSubscriptionTransferToken(subscriptionID, h.connectionAccount.AccountID)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	if err = conn.Reply(ctx, req.ID, token); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
+
+// handleRPCSubscriptionTransfer adopts a subscription of the same account using a transfer token and keeps
+// streaming it on this connection
+func (h *handlerObj) handleRPCSubscriptionTransfer(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	token, err := h.singleStringParam(req)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	ci := types.ClientInfo{
+		RemoteAddress: h.remoteAddress,
+		AccountID:     h.connectionAccount.AccountID,
+		Tier:          string(h.connectionAccount.TierName),
+		MetaInfo:      h.headers,
+	}
+	sub, request, err := h.FeedManager.AdoptSubscription(token, conn, ci)
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	defer h.FeedManager.releaseSubscription(sub.SubscriptionID, sub.HandOffChan)
+
+	if err = conn.Reply(ctx, req.ID, sub.SubscriptionID); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+		SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
+		return
+	}
+
+	h.serveSubscription(ctx, conn, req, sub, request)
+}
+
+func (h *handlerObj) singleStringParam(req *jsonrpc2.Request) (string, error) {
+	if req.Params == nil {
+		return "", fmt.Errorf(errParamsValueIsMissing)
+	}
+
+	var params []string
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		return "", fmt.Errorf("failed to unmarshal params for %v request: %v", req.Method, err)
+	}
+	if len(params) != 1 {
+		return "", fmt.Errorf("received invalid number of params: expected 1, got %v", len(params))
+	}
+
+	return params[0], nil
+}
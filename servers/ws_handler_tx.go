@@ -57,8 +57,16 @@ func (h *handlerObj) handleRPCTx(ctx context.Context, conn *jsonrpc2.Conn, req *
 		ws = connections.NewRPCConn(h.connectionAccount.AccountID, h.remoteAddress, h.FeedManager.networkNum, utils.Websocket)
 	}
 
-	txHash, ok, err := HandleSingleTransaction(h.FeedManager, params.Transaction, nil, ws, params.ValidatorsOnly,
-		params.NextValidator, params.NodeValidation, params.FrontRunningProtection, params.Fallback,
+	flags := h.FeedManager.ApplyTxDefaults(ws, h.connectionAccount, TxFlags{
+		ValidatorsOnly:            params.ValidatorsOnly,
+		ValidatorsOnlySet:         params.ValidatorsOnlySet,
+		NextValidator:             params.NextValidator,
+		FrontRunningProtection:    params.FrontRunningProtection,
+		FrontRunningProtectionSet: params.FrontRunningProtectionSet,
+	})
+
+	txHash, ok, err := HandleSingleTransaction(h.FeedManager, params.Transaction, nil, ws, flags.ValidatorsOnly,
+		params.NextValidator, params.NodeValidation, flags.FrontRunningProtection, params.Fallback,
 		h.FeedManager.nextValidatorMap, h.FeedManager.validatorStatusMap)
 	if err != nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
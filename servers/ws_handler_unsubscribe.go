@@ -6,17 +6,29 @@ import (
 	"fmt"
 
 	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/bloXroute-Labs/gateway/v2/services/statistics"
 	"github.com/gorilla/websocket"
 	"github.com/sourcegraph/jsonrpc2"
 )
 
+// unsubscribeOptions is the optional second param of the unsubscribe request
+type unsubscribeOptions struct {
+	// Stats replies with the delivery statistics of the subscription instead of "true"
+	Stats bool `json:"Stats"`
+}
+
+type unsubscribeResponse struct {
+	Unsubscribed bool                                 `json:"unsubscribed"`
+	Stats        statistics.SubscriptionDeliveryStats `json:"stats"`
+}
+
 func (h *handlerObj) handleRPCUnsubscribe(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
 	if req.Params == nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
 		return
 	}
 
-	var params []string
+	var params []json.RawMessage
 	err := json.Unmarshal(*req.Params, &params)
 	if err != nil {
 		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
@@ -24,13 +36,27 @@ func (h *handlerObj) handleRPCUnsubscribe(ctx context.Context, conn *jsonrpc2.Co
 		return
 	}
 
-	if len(params) != 1 {
-		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("received invalid number of params: expected 1, got %v",
+	if len(params) != 1 && len(params) != 2 {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("received invalid number of params: expected 1 or 2, got %v",
 			len(params)), conn, req.ID)
 		return
 	}
 
-	uid := params[0]
+	var uid string
+	if err = json.Unmarshal(params[0], &uid); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal subscription id: %v", err), conn, req.ID)
+		return
+	}
+	var options unsubscribeOptions
+	if len(params) == 2 {
+		if err = json.Unmarshal(params[1], &options); err != nil {
+			SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal unsubscribe options: %v", err), conn, req.ID)
+			return
+		}
+	}
+
+	// the stats are read before the subscription is removed
+	delivery, _ := h.FeedManager.SubscriptionDeliveryStats(uid)
 	if err = h.FeedManager.Unsubscribe(uid, false, ""); err != nil {
 		h.log.Warnf("subscription id %v was not found", uid)
 
@@ -43,7 +69,11 @@ func (h *handlerObj) handleRPCUnsubscribe(ctx context.Context, conn *jsonrpc2.Co
 		return
 	}
 
-	if err = conn.Reply(ctx, req.ID, "true"); err != nil {
+	var reply interface{} = "true"
+	if options.Stats {
+		reply = unsubscribeResponse{Unsubscribed: true, Stats: delivery}
+	}
+	if err = conn.Reply(ctx, req.ID, reply); err != nil {
 		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
 		SendErrorMsg(ctx, jsonrpc.InternalError, string(rune(websocket.CloseMessage)), conn, req.ID)
 		return
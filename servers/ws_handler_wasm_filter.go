@@ -0,0 +1,55 @@
+package servers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bloXroute-Labs/gateway/v2/jsonrpc"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// handleRPCWasmFilter manages the WASM filter modules of the account, which can then be used by its tx subscriptions
+// with the WasmFilter option
+func (h *handlerObj) handleRPCWasmFilter(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) {
+	if req.Params == nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, errParamsValueIsMissing, conn, req.ID)
+		return
+	}
+
+	var params jsonrpc.RPCWasmFilterPayload
+	if err := json.Unmarshal(*req.Params, &params); err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, fmt.Sprintf("failed to unmarshal params for %v request: %v",
+			jsonrpc.RPCWasmFilter, err), conn, req.ID)
+		return
+	}
+
+	accountID := h.connectionAccount.AccountID
+	store := h.FeedManager.wasmFilters
+	var err error
+	switch params.Action {
+	case "upload":
+		var module []byte
+		module, err = base64.StdEncoding.DecodeString(params.Module)
+		if err != nil {
+			err = fmt.Errorf("module must be base64 encoded: %v", err)
+			break
+		}
+		err = store.Upload(accountID, params.Name, module)
+	case "remove":
+		err = store.Remove(accountID, params.Name)
+	case "list":
+	default:
+		err = fmt.Errorf("unsupported action %v, possible values are: upload, remove, list", params.Action)
+	}
+	if err != nil {
+		SendErrorMsg(ctx, jsonrpc.InvalidParams, err.Error(), conn, req.ID)
+		return
+	}
+
+	h.log.Infof("wasm filter %v request from %v, account id %v handled", params.Action, h.remoteAddress, accountID)
+	if err = conn.Reply(ctx, req.ID, rpcWasmFilterResponse{Modules: store.Names(accountID)}); err != nil {
+		h.log.Errorf("error replying to %v, method %v: %v", h.remoteAddress, req.Method, err)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// accountCacheSweepSize is the number of cached accounts above which the expired entries are removed
+const accountCacheSweepSize = 10000
+
+// AccountCacheConfig tunes the cache of the account models of the clients connecting with a foreign account
+type AccountCacheConfig struct {
+	// TTL is how long an account model is served before it is refreshed in the background, 0 disables the cache
+	TTL time.Duration
+	// NegativeTTL is how long a failure to fetch an account model is served before the account is fetched again
+	NegativeTTL time.Duration
+	// MaxStale is how long an account model is still served past its TTL while it cannot be refreshed, the account is
+	// then fetched again before it is served
+	MaxStale time.Duration
+}
+
+// AccountErrorStatus returns the HTTP status of the SDN error telling the account is invalid: unauthorized, not found
+// or bad request. It returns 0 for the other errors, such as an unavailable SDN.
+func AccountErrorStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	for _, status := range []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusBadRequest} {
+		if strings.Contains(err.Error(), strconv.Itoa(status)) {
+			return status
+		}
+	}
+	return 0
+}
+
+// AccountCache caches the account models fetched from the SDN, so that the connections of the clients are not held
+// by a slow SDN. An account model past its TTL is still served while it is refreshed in the background, and kept up
+// to MaxStale if the SDN is unavailable. An account the SDN reports as invalid is evicted at once. The concurrent
+// fetches of an account are merged into a single request.
+type AccountCache struct {
+	cfg     AccountCacheConfig
+	clock   utils.Clock
+	fetch   func(types.AccountID) (sdnmessage.Account, error)
+	lock    sync.Mutex
+	entries map[types.AccountID]*accountCacheEntry
+	fetches singleflight.Group
+	log     *log.Entry
+}
+
+type accountCacheEntry struct {
+	account    sdnmessage.Account
+	err        error
+	expiresAt  time.Time
+	staleAt    time.Time
+	refreshing bool
+}
+
+// served returns true if the entry can still be served, expired failures and account models past their max stale
+// are fetched again
+func (e *accountCacheEntry) served(now time.Time) bool {
+	if e.err != nil {
+		return now.Before(e.expiresAt)
+	}
+	return now.Before(e.staleAt)
+}
+
+// NewAccountCache creates an AccountCache of the account models returned by fetch
+func NewAccountCache(cfg AccountCacheConfig, clock utils.Clock, fetch func(types.AccountID) (sdnmessage.Account, error)) *AccountCache {
+	return &AccountCache{
+		cfg:     cfg,
+		clock:   clock,
+		fetch:   fetch,
+		entries: make(map[types.AccountID]*accountCacheEntry),
+		log:     log.WithField("component", "accountCache"),
+	}
+}
+
+// Get returns the account model of the account, as fetch does
+func (c *AccountCache) Get(accountID types.AccountID) (sdnmessage.Account, error) {
+	if c.cfg.TTL == 0 {
+		return c.fetch(accountID)
+	}
+
+	now := c.clock.Now()
+	c.lock.Lock()
+	entry, ok := c.entries[accountID]
+	if ok && entry.served(now) {
+		if entry.err == nil && !now.Before(entry.expiresAt) && !entry.refreshing {
+			entry.refreshing = true
+			go c.refresh(accountID)
+		}
+		account, err := entry.account, entry.err
+		c.lock.Unlock()
+		return account, err
+	}
+	c.lock.Unlock()
+
+	result, err, _ := c.fetches.Do(string(accountID), func() (interface{}, error) {
+		account, err := c.fetch(accountID)
+		c.store(accountID, account, err)
+		return account, err
+	})
+	return result.(sdnmessage.Account), err
+}
+
+// refresh fetches the account model of an expired entry
+func (c *AccountCache) refresh(accountID types.AccountID) {
+	account, err := c.fetch(accountID)
+
+	c.lock.Lock()
+	entry, ok := c.entries[accountID]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+	entry.refreshing = false
+	if err != nil && AccountErrorStatus(err) == 0 {
+		// the SDN may be unavailable, the cached account model is served until the next attempt or its max stale
+		c.log.Debugf("failed to refresh account model of %v, retrying in %v: %v", accountID, c.cfg.NegativeTTL, err)
+		entry.expiresAt = c.clock.Now().Add(c.cfg.NegativeTTL)
+		c.lock.Unlock()
+		return
+	}
+	c.lock.Unlock()
+
+	if err != nil {
+		// the account was deleted, suspended or its secret changed, the cached model must not authenticate anymore
+		c.log.Debugf("evicting account model of %v: %v", accountID, err)
+	}
+	c.store(accountID, account, err)
+}
+
+func (c *AccountCache) store(accountID types.AccountID, account sdnmessage.Account, err error) {
+	now := c.clock.Now()
+	entry := &accountCacheEntry{account: account, err: err, expiresAt: now.Add(c.cfg.TTL)}
+	if err != nil {
+		entry.expiresAt = now.Add(c.cfg.NegativeTTL)
+	}
+	entry.staleAt = entry.expiresAt.Add(c.cfg.MaxStale)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.entries) >= accountCacheSweepSize {
+		c.sweep(now)
+	}
+	c.entries[accountID] = entry
+}
+
+// sweep removes the entries which cannot be served anymore, must be called with the lock held
+func (c *AccountCache) sweep(now time.Time) {
+	for id, entry := range c.entries {
+		if !entry.served(now) && !entry.refreshing {
+			delete(c.entries, id)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountCache(t *testing.T) {
+	clock := &utils.MockClock{}
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	cache := NewAccountCache(AccountCacheConfig{TTL: time.Minute, NegativeTTL: 10 * time.Second, MaxStale: 5 * time.Minute}, clock,
+		func(accountID types.AccountID) (sdnmessage.Account, error) {
+			count := fetches.Add(1)
+			if failing.Load() {
+				return sdnmessage.Account{}, errors.New("sdn unavailable")
+			}
+			return sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: accountID}, SecretHash: string(rune('0' + count))}, nil
+		})
+
+	account, err := cache.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", account.SecretHash)
+	account, err = cache.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", account.SecretHash)
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// the expired account is served while it is refreshed
+	clock.IncTime(time.Minute)
+	account, err = cache.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "1", account.SecretHash)
+	assert.Eventually(t, func() bool {
+		account, _ = cache.Get("a")
+		return account.SecretHash == "2"
+	}, time.Second, time.Millisecond)
+
+	// a failed refresh keeps the cached account
+	failing.Store(true)
+	clock.IncTime(time.Minute)
+	_, _ = cache.Get("a")
+	assert.Eventually(t, func() bool { return fetches.Load() == 3 }, time.Second, time.Millisecond)
+	account, err = cache.Get("a")
+	require.NoError(t, err)
+	assert.Equal(t, "2", account.SecretHash)
+
+	// past its max stale, the account is fetched before it is served
+	clock.IncTime(5 * time.Minute)
+	_, err = cache.Get("a")
+	assert.EqualError(t, err, "sdn unavailable")
+	assert.Equal(t, int32(4), fetches.Load())
+
+	// the failures are cached for the negative TTL
+	_, err = cache.Get("b")
+	assert.EqualError(t, err, "sdn unavailable")
+	_, err = cache.Get("b")
+	assert.EqualError(t, err, "sdn unavailable")
+	assert.Equal(t, int32(5), fetches.Load())
+
+	failing.Store(false)
+	clock.IncTime(10 * time.Second)
+	account, err = cache.Get("b")
+	require.NoError(t, err)
+	assert.Equal(t, types.AccountID("b"), account.AccountID)
+}
+
+func TestAccountCache_Disabled(t *testing.T) {
+	var fetches int
+	cache := NewAccountCache(AccountCacheConfig{}, &utils.MockClock{}, func(accountID types.AccountID) (sdnmessage.Account, error) {
+		fetches++
+		return sdnmessage.Account{}, nil
+	})
+	_, _ = cache.Get("a")
+	_, _ = cache.Get("a")
+	assert.Equal(t, 2, fetches)
+}
+
+func TestAccountCache_AccountDeletedUpstream(t *testing.T) {
+	clock := &utils.MockClock{}
+	var deleted atomic.Bool
+	cache := NewAccountCache(AccountCacheConfig{TTL: time.Minute, NegativeTTL: 10 * time.Second, MaxStale: time.Hour}, clock,
+		func(accountID types.AccountID) (sdnmessage.Account, error) {
+			if deleted.Load() {
+				return sdnmessage.Account{}, errors.New("failed to fetch account model: 404 Not Found")
+			}
+			return sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: accountID}}, nil
+		})
+
+	_, err := cache.Get("a")
+	require.NoError(t, err)
+
+	// the expired account is served once more while it is refreshed, then the next authorization fails
+	deleted.Store(true)
+	clock.IncTime(time.Minute)
+	_, err = cache.Get("a")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		_, err = cache.Get("a")
+		return err != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, http.StatusNotFound, AccountErrorStatus(err))
+}
+
+func TestAccountCache_Sweep(t *testing.T) {
+	clock := &utils.MockClock{}
+	cache := NewAccountCache(AccountCacheConfig{TTL: time.Minute, NegativeTTL: 10 * time.Second, MaxStale: time.Minute}, clock,
+		func(accountID types.AccountID) (sdnmessage.Account, error) {
+			if accountID == "failing" {
+				return sdnmessage.Account{}, errors.New("sdn unavailable")
+			}
+			return sdnmessage.Account{AccountInfo: sdnmessage.AccountInfo{AccountID: accountID}}, nil
+		})
+
+	_, _ = cache.Get("failing")
+	for i := 1; i < accountCacheSweepSize; i++ {
+		_, _ = cache.Get(types.AccountID(strconv.Itoa(i)))
+	}
+	require.Len(t, cache.entries, accountCacheSweepSize)
+
+	// the expired failures and the account models past their max stale are removed
+	clock.IncTime(2 * time.Minute)
+	_, _ = cache.Get("new")
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, types.AccountID("new"))
+}
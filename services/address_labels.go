@@ -0,0 +1,72 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// addressLabelsEnrichment is the name of the address labels in the enrichment of the tx notifications
+const addressLabelsEnrichment = "labels"
+
+// TxAddressLabels are the labels of the sender and the recipient of a transaction
+type TxAddressLabels struct {
+	From []string `json:"from,omitempty"`
+	To   []string `json:"to,omitempty"`
+}
+
+// AddressLabels are the labels of known addresses (e.g. exchange, known bot, contract deployer), loaded from a dataset
+// provided by the operator. It enriches the tx notifications with the labels of their from and to addresses.
+type AddressLabels struct {
+	labels map[string][]string
+}
+
+// NewAddressLabels creates AddressLabels from the labels of each address
+func NewAddressLabels(labels map[string][]string) *AddressLabels {
+	l := &AddressLabels{labels: make(map[string][]string, len(labels))}
+	for address, names := range labels {
+		if len(names) > 0 {
+			l.labels[strings.ToLower(address)] = names
+		}
+	}
+	return l
+}
+
+// LoadAddressLabels reads AddressLabels from a JSON file mapping addresses to their labels
+func LoadAddressLabels(path string) (*AddressLabels, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read address labels file %v: %v", path, err)
+	}
+	var labels map[string][]string
+	if err = json.Unmarshal(contents, &labels); err != nil {
+		return nil, fmt.Errorf("failed to decode address labels file %v: %v", path, err)
+	}
+	return NewAddressLabels(labels), nil
+}
+
+// Labels returns the labels of the address, nil if it is not labeled
+func (l *AddressLabels) Labels(address string) []string {
+	return l.labels[strings.ToLower(address)]
+}
+
+// Len returns the number of labeled addresses
+func (l *AddressLabels) Len() int {
+	return len(l.labels)
+}
+
+// EnrichTx returns the labels of the from and to addresses of the tx, nil if neither is labeled
+func (l *AddressLabels) EnrichTx(tx *types.NewTransactionNotification) map[string]interface{} {
+	addresses := tx.Filters([]string{"from", "to"})
+	from, _ := addresses["from"].(string)
+	to, _ := addresses["to"].(string)
+
+	txLabels := TxAddressLabels{From: l.Labels(from), To: l.Labels(to)}
+	if txLabels.From == nil && txLabels.To == nil {
+		return nil
+	}
+	return map[string]interface{}{addressLabelsEnrichment: txLabels}
+}
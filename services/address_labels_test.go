@@ -0,0 +1,52 @@
+package services
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressLabels_EnrichTx(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	tx, err := ethtypes.SignTx(ethtypes.NewTransaction(1, recipient, big.NewInt(1), 21000, big.NewInt(1), nil),
+		ethtypes.LatestSignerForChainID(big.NewInt(1)), privateKey)
+	require.NoError(t, err)
+	content, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	newNotification := func() *types.NewTransactionNotification {
+		bxTx := types.NewBxTransaction(types.GenerateSHA256Hash(), types.NetworkNum(5), types.TFPaidTx, time.Now())
+		bxTx.SetContent(content)
+		return types.CreateNewTransactionNotification(bxTx)
+	}
+
+	path := filepath.Join(t.TempDir(), "labels.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"`+recipient.Hex()+`": ["exchange"], "0x2222222222222222222222222222222222222222": []}`), 0o644))
+	loaded, err := LoadAddressLabels(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loaded.Len())
+	assert.Equal(t, []string{"exchange"}, loaded.Labels(strings.ToLower(recipient.Hex())))
+
+	labels := NewAddressLabels(map[string][]string{
+		recipient.Hex(): {"exchange"},
+		sender.Hex():    {"known bot", "deployer"},
+	})
+	assert.Equal(t, map[string]interface{}{
+		addressLabelsEnrichment: TxAddressLabels{From: []string{"known bot", "deployer"}, To: []string{"exchange"}},
+	}, labels.EnrichTx(newNotification()))
+
+	assert.Nil(t, NewAddressLabels(map[string][]string{"0x2222222222222222222222222222222222222222": {"bot"}}).EnrichTx(newNotification()))
+}
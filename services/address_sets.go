@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// AddressSets are the address sets of the files of a directory, which the filters of the subscriptions reference with
+// in_file('name'). A file holds an address per line, blank lines and lines starting with # are ignored. A file is
+// loaded on its first reference and reloaded when modified, so the subscriptions follow the changes of the file.
+type AddressSets struct {
+	dir   string
+	clock utils.Clock
+	lock  sync.Mutex
+	sets  map[string]*AddressSet
+}
+
+// AddressSet is the set of lower case addresses of a file. All methods are safe for concurrent use.
+type AddressSet struct {
+	path      string
+	lock      sync.RWMutex
+	addresses map[string]struct{}
+	modTime   time.Time
+}
+
+// NewAddressSets creates the address sets of the files of the directory, the sets are disabled if dir is empty
+func NewAddressSets(dir string) *AddressSets {
+	return newAddressSets(dir, utils.RealClock{})
+}
+
+func newAddressSets(dir string, clock utils.Clock) *AddressSets {
+	return &AddressSets{dir: dir, clock: clock, sets: make(map[string]*AddressSet)}
+}
+
+// Get returns the set of the file of the directory, loading it on its first reference. The name is matched case
+// insensitively, as the filters are lower case, and cannot reference a file outside the directory.
+func (s *AddressSets) Get(name string) (*AddressSet, error) {
+	if s == nil || s.dir == "" {
+		return nil, errors.New("filter files are not enabled on this gateway")
+	}
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid filter file %v", name)
+	}
+	key := strings.ToLower(name)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if set, ok := s.sets[key]; ok {
+		return set, nil
+	}
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read filter files directory %v: %v", s.dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.EqualFold(entry.Name(), name) {
+			continue
+		}
+		set := &AddressSet{path: filepath.Join(s.dir, entry.Name())}
+		if err = set.reload(); err != nil {
+			return nil, err
+		}
+		s.sets[key] = set
+		return set, nil
+	}
+	return nil, fmt.Errorf("filter file %v not found", name)
+}
+
+// Watch reloads the files of the sets when they are modified, until the context is done
+func (s *AddressSets) Watch(ctx context.Context, interval time.Duration) {
+	if s == nil || s.dir == "" {
+		return
+	}
+
+	ticker := s.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			s.lock.Lock()
+			sets := make([]*AddressSet, 0, len(s.sets))
+			for _, set := range s.sets {
+				sets = append(sets, set)
+			}
+			s.lock.Unlock()
+
+			for _, set := range sets {
+				info, err := os.Stat(set.path)
+				if err != nil {
+					log.Warnf("failed to check filter file %v: %v", set.path, err)
+					continue
+				}
+				set.lock.RLock()
+				modified := !info.ModTime().Equal(set.modTime)
+				set.lock.RUnlock()
+
+				if modified {
+					if err = set.reload(); err != nil {
+						log.Errorf("failed to reload filter file, keeping the previous addresses: %v", err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Contains returns true if the address is in the set
+func (set *AddressSet) Contains(address string) bool {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+	_, ok := set.addresses[strings.ToLower(address)]
+	return ok
+}
+
+// Len returns the number of addresses of the set
+func (set *AddressSet) Len() int {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+	return len(set.addresses)
+}
+
+// reload replaces the addresses with the content of the file
+func (set *AddressSet) reload() error {
+	file, err := os.Open(set.path)
+	if err != nil {
+		return fmt.Errorf("failed to read filter file %v: %v", set.path, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read filter file %v: %v", set.path, err)
+	}
+
+	addresses := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addresses[strings.ToLower(line)] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read filter file %v: %v", set.path, err)
+	}
+
+	set.lock.Lock()
+	set.addresses = addresses
+	set.modTime = info.ModTime()
+	set.lock.Unlock()
+
+	log.Infof("loaded %v addresses from filter file %v", len(addresses), set.path)
+	return nil
+}
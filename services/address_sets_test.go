@@ -0,0 +1,49 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressSets(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Routers.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# routers\n0xAAAA\n\n 0xbbbb \n"), 0o644))
+
+	sets := newAddressSets(dir, &utils.MockClock{})
+	set, err := sets.Get("routers.txt")
+	require.NoError(t, err)
+	assert.Equal(t, 2, set.Len())
+	assert.True(t, set.Contains("0xaaaa"))
+	assert.True(t, set.Contains("0xBBBB"))
+	assert.False(t, set.Contains("# routers"))
+
+	// the set is loaded once and shared by the filters referencing the file
+	same, err := sets.Get("ROUTERS.TXT")
+	require.NoError(t, err)
+	assert.Same(t, set, same)
+
+	require.NoError(t, os.WriteFile(path, []byte("0xcccc\n"), 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, set.reload())
+	assert.Equal(t, 1, set.Len())
+	assert.False(t, set.Contains("0xaaaa"))
+	assert.True(t, set.Contains("0xcccc"))
+
+	_, err = sets.Get("missing.txt")
+	assert.Error(t, err)
+	_, err = sets.Get("../routers.txt")
+	assert.Error(t, err)
+
+	var disabled *AddressSets
+	_, err = disabled.Get("routers.txt")
+	assert.Error(t, err)
+	_, err = NewAddressSets("").Get("routers.txt")
+	assert.Error(t, err)
+}
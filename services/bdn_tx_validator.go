@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+type bdnTxValidationJob struct {
+	tx      *types.BxTransaction
+	source  string
+	forward func()
+}
+
+// BDNTxValidator validates the content of transactions received from the BDN in a bounded pool of workers before
+// they are forwarded to the blockchain node, and counts the invalid transactions per relay
+type BDNTxValidator struct {
+	chainID types.NetworkID
+	workers int
+	jobs    chan bdnTxValidationJob
+	dropped atomic.Uint64
+
+	lock    sync.Mutex
+	invalid map[string]uint64
+}
+
+// NewBDNTxValidator creates a validator for transactions of the chain ID, with the number of workers and a queue of
+// queueSize pending transactions
+func NewBDNTxValidator(chainID types.NetworkID, workers int, queueSize int) *BDNTxValidator {
+	return &BDNTxValidator{
+		chainID: chainID,
+		workers: workers,
+		jobs:    make(chan bdnTxValidationJob, queueSize),
+		invalid: make(map[string]uint64),
+	}
+}
+
+// Run starts the workers until the context is done
+func (v *BDNTxValidator) Run(ctx context.Context) {
+	if v == nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < v.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-v.jobs:
+					v.validate(job)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Submit queues the transaction received from the source relay for validation, forward is called if it is valid.
+// Returns false if the queue is full and the transaction was dropped.
+func (v *BDNTxValidator) Submit(tx *types.BxTransaction, source string, forward func()) bool {
+	select {
+	case v.jobs <- bdnTxValidationJob{tx: tx, source: source, forward: forward}:
+		return true
+	default:
+		v.dropped.Add(1)
+		return false
+	}
+}
+
+// InvalidTxs returns the number of invalid transactions received from each relay
+func (v *BDNTxValidator) InvalidTxs() map[string]uint64 {
+	if v == nil {
+		return nil
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	invalid := make(map[string]uint64, len(v.invalid))
+	for source, count := range v.invalid {
+		invalid[source] = count
+	}
+	return invalid
+}
+
+// Dropped returns the number of transactions dropped because the validation queue was full
+func (v *BDNTxValidator) Dropped() uint64 {
+	if v == nil {
+		return 0
+	}
+	return v.dropped.Load()
+}
+
+func (v *BDNTxValidator) validate(job bdnTxValidationJob) {
+	if err := ValidateTxContent(job.tx.Hash(), job.tx.Content(), v.chainID); err != nil {
+		v.lock.Lock()
+		v.invalid[job.source]++
+		v.lock.Unlock()
+		log.Debugf("not sending invalid tx %v from relay %v to nodes: %v", job.tx.Hash(), job.source, err)
+		return
+	}
+	job.forward()
+}
+
+// ValidateTxContent decodes the transaction content and verifies its hash, chain ID and signature
+func ValidateTxContent(hash types.SHA256Hash, content types.TxContent, chainID types.NetworkID) error {
+	var ethTx ethtypes.Transaction
+	if err := rlp.DecodeBytes(content, &ethTx); err != nil {
+		// typed transactions can also be sent in their binary encoding
+		if e := ethTx.UnmarshalBinary(content); e != nil {
+			return fmt.Errorf("could not decode transaction: %v", err)
+		}
+	}
+
+	if hash != types.SHA256Hash(ethTx.Hash()) {
+		return fmt.Errorf("content hash %v does not match the transaction hash", ethTx.Hash())
+	}
+
+	txChainID := ethTx.ChainId()
+	if txChainID.Sign() != 0 && chainID != 0 && txChainID.Cmp(big.NewInt(int64(chainID))) != 0 {
+		return fmt.Errorf("chain ID %v does not match the gateway chain ID %v", txChainID, chainID)
+	}
+
+	if _, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(txChainID), &ethTx); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTxContent(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	for _, txType := range []uint8{ethtypes.LegacyTxType, ethtypes.AccessListTxType, ethtypes.DynamicFeeTxType} {
+		ethTx, content := bxmock.NewSignedEthTxBytes(txType, 1, privateKey, big.NewInt(1))
+		hash := types.SHA256Hash(ethTx.Hash())
+
+		assert.NoError(t, ValidateTxContent(hash, content, 1))
+		assert.NoError(t, ValidateTxContent(hash, content, 0))
+		assert.Error(t, ValidateTxContent(hash, content, 56))
+		assert.Error(t, ValidateTxContent(types.SHA256Hash{1}, content, 1))
+
+		wrapped, err := rlp.EncodeToBytes(ethTx)
+		require.NoError(t, err)
+		assert.NoError(t, ValidateTxContent(hash, wrapped, 1))
+	}
+
+	assert.Error(t, ValidateTxContent(types.SHA256Hash{1}, types.TxContent{1, 2, 3}, 1))
+
+	// signature with a zero S value
+	ethTx := bxmock.NewSignedEthTx(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	_, r, _ := ethTx.RawSignatureValues()
+	signature := make([]byte, crypto.SignatureLength)
+	r.FillBytes(signature[:32])
+	tampered, err := ethTx.WithSignature(ethtypes.LatestSignerForChainID(big.NewInt(1)), signature)
+	require.NoError(t, err)
+	content, err := tampered.MarshalBinary()
+	require.NoError(t, err)
+	assert.Error(t, ValidateTxContent(types.SHA256Hash(tampered.Hash()), content, 1))
+}
+
+func TestBDNTxValidator(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	validator := NewBDNTxValidator(1, 2, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go validator.Run(ctx)
+
+	ethTx, content := bxmock.NewSignedEthTxBytes(ethtypes.DynamicFeeTxType, 1, privateKey, big.NewInt(1))
+	validTx := types.NewRawBxTransaction(types.SHA256Hash(ethTx.Hash()), content)
+	invalidTx := types.NewRawBxTransaction(types.SHA256Hash{1}, types.TxContent{1, 2, 3})
+
+	forwarded := make(chan types.SHA256Hash, 2)
+	submit := func(tx *types.BxTransaction) {
+		require.Eventually(t, func() bool {
+			return validator.Submit(tx, "1.1.1.1", func() { forwarded <- tx.Hash() })
+		}, time.Second, time.Millisecond)
+	}
+
+	submit(invalidTx)
+	submit(validTx)
+
+	select {
+	case hash := <-forwarded:
+		assert.Equal(t, validTx.Hash(), hash)
+	case <-time.After(time.Second):
+		assert.Fail(t, "valid tx was not forwarded")
+	}
+	assert.Eventually(t, func() bool {
+		return validator.InvalidTxs()["1.1.1.1"] == 1
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, forwarded)
+}
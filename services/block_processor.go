@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -19,6 +23,13 @@ var (
 	ErrNotCompitableBeaconBlock = errors.New("not compitable beacon block")
 )
 
+// minDecompressionChunkSize is the smallest number of short IDs or transactions of a block given to a decompression
+// worker, smaller chunks cost more to schedule than to process
+const minDecompressionChunkSize = 64
+
+// decompressionWorkers is the maximum number of goroutines decompressing a block
+var decompressionWorkers = runtime.NumCPU()
+
 // BxBlockConverter is the service interface for converting broadcast messages to/from bx blocks
 type BxBlockConverter interface {
 	BxBlockToBroadcast(*types.BxBlock, types.NetworkNum, time.Duration) (*bxmessage.Broadcast, types.ShortIDList, error)
@@ -122,25 +133,12 @@ func (bp *blockProcessor) BxBlockFromBroadcast(broadcast *bxmessage.Broadcast) (
 		return nil, nil, ErrUnknownBlockType
 	}
 
-	shortIDs := broadcast.ShortIDs()
-	var bxTransactions []*types.BxTransaction
-	var missingShortIDs types.ShortIDList
-	var err error
-
-	// looking for missing sids
-	for _, sid := range shortIDs {
-		bxTransaction, err := bp.txStore.GetTxByShortID(sid)
-		if err == nil { // sid exists in TxStore
-			bxTransactions = append(bxTransactions, bxTransaction)
-		} else {
-			missingShortIDs = append(missingShortIDs, sid)
-		}
-	}
-
+	bxTransactions, missingShortIDs := bp.resolveShortIDs(broadcast.ShortIDs())
 	if len(missingShortIDs) > 0 {
 		return nil, missingShortIDs, ErrMissingShortIDs
 	}
 
+	var err error
 	var block *types.BxBlock
 	switch broadcast.BlockType() {
 	case types.BxBlockTypeEth:
@@ -163,34 +161,67 @@ func (bp *blockProcessor) BxBlockFromBroadcast(broadcast *bxmessage.Broadcast) (
 	return block, missingShortIDs, err
 }
 
+// resolveShortIDs looks up the transactions of the short IDs in the TxStore across the decompression workers, it returns
+// them in the order of the short IDs and the short IDs missing from the TxStore
+func (bp *blockProcessor) resolveShortIDs(shortIDs types.ShortIDList) ([]*types.BxTransaction, types.ShortIDList) {
+	bxTransactions := make([]*types.BxTransaction, len(shortIDs))
+	runDecompressionWorkers(len(shortIDs), func(start, end int) {
+		for i := start; i < end; i++ {
+			if bxTransaction, err := bp.txStore.GetTxByShortID(shortIDs[i]); err == nil {
+				bxTransactions[i] = bxTransaction
+			}
+		}
+	})
+
+	var missingShortIDs types.ShortIDList
+	for i, bxTransaction := range bxTransactions {
+		if bxTransaction == nil {
+			missingShortIDs = append(missingShortIDs, shortIDs[i])
+		}
+	}
+	return bxTransactions, missingShortIDs
+}
+
 func (bp *blockProcessor) ShouldProcess(hash types.SHA256Hash) bool {
 	return !bp.processedBlocks.Exists(hash.String())
 }
 
-func (bp *blockProcessor) newBxBlockFromRLPBroadcast(broadcast *bxmessage.Broadcast, bxTransactions []*types.BxTransaction) (*types.BxBlock, error) {
+// EthBlockHeaderFromBroadcast decodes the header of the ETH block of the broadcast, which is known even if the
+// transactions of the block cannot be decompressed yet
+func EthBlockHeaderFromBroadcast(broadcast *bxmessage.Broadcast) (*ethtypes.Header, error) {
+	if broadcast.BlockType() != types.BxBlockTypeEth {
+		return nil, ErrUnknownBlockType
+	}
 	var rlpBlock bxBlockRLP
 	if err := rlp.DecodeBytes(broadcast.Block(), &rlpBlock); err != nil {
 		return nil, err
 	}
+	var header ethtypes.Header
+	if err := rlp.DecodeBytes(rlpBlock.Header, &header); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
 
-	compressedTransactionCount := 0
-	txs := make([]*types.BxBlockTransaction, 0, len(rlpBlock.Txs))
+func (bp *blockProcessor) newBxBlockFromRLPBroadcast(broadcast *bxmessage.Broadcast, bxTransactions []*types.BxTransaction) (*types.BxBlock, error) {
+	var rlpBlock bxBlockRLP
+	if err := rlp.DecodeBytes(broadcast.Block(), &rlpBlock); err != nil {
+		return nil, err
+	}
 
-	var txsBytes uint64
-	for _, tx := range rlpBlock.Txs {
-		if !tx.IsFullTransaction {
-			if compressedTransactionCount >= len(bxTransactions) {
-				return nil, fmt.Errorf("could not decompress bad block: more empty transactions than short IDs provided")
-			}
-			txs = append(txs, types.NewBxBlockTransaction(bxTransactions[compressedTransactionCount].Hash(), bxTransactions[compressedTransactionCount].Content()))
-			txsBytes += uint64(len(bxTransactions[compressedTransactionCount].Content()))
-			compressedTransactionCount++
-		} else {
-			txs = append(txs, types.NewRawBxBlockTransaction(tx.Transaction))
-			txsBytes += uint64(len(tx.Transaction))
-		}
+	compressedTxs := make([]*bxCompressedTransaction, len(rlpBlock.Txs))
+	for i := range rlpBlock.Txs {
+		compressedTxs[i] = &rlpBlock.Txs[i]
 	}
-	blockSize := int(rlp.ListSize(uint64(len(rlpBlock.Header)) + rlp.ListSize(txsBytes) + uint64(len(rlpBlock.Trailer))))
+	txs, txsBytes, err := decompressTransactions(compressedTxs, bxTransactions, func(bxTransaction *types.BxTransaction) *types.BxBlockTransaction {
+		return types.NewBxBlockTransaction(bxTransaction.Hash(), bxTransaction.Content())
+	}, func(rawTx []byte) int {
+		return len(rawTx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	blockSize := int(rlp.ListSize(uint64(len(rlpBlock.Header)) + rlp.ListSize(uint64(txsBytes)) + uint64(len(rlpBlock.Trailer))))
 
 	return types.NewRawBxBlock(broadcast.Hash(), types.EmptyHash, broadcast.BlockType(), rlpBlock.Header, txs, rlpBlock.Trailer, rlpBlock.TotalDifficulty, rlpBlock.Number, blockSize), nil
 }
@@ -201,27 +232,82 @@ func (bp *blockProcessor) newBxBlockFromSSZBroadcast(broadcast *bxmessage.Broadc
 		return nil, err
 	}
 
+	txs, txsBytes, err := decompressTransactions(sszBlock.Txs, bxTransactions, func(bxTransaction *types.BxTransaction) *types.BxBlockTransaction {
+		return types.NewRawBxBlockTransaction(bxTransaction.Content())
+	}, calcBeaconTransactionLength)
+	if err != nil {
+		return nil, err
+	}
+
+	blockSize := len(sszBlock.Block) + txsBytes
+
+	return types.NewRawBxBlock(broadcast.Hash(), broadcast.BeaconHash(), broadcast.BlockType(), nil, txs, sszBlock.Block, nil, big.NewInt(int64(sszBlock.Number)), int(blockSize)), nil
+}
+
+// decompressTransactions rebuilds the transactions of a block across the decompression workers, the transactions sent
+// as short IDs are created from the resolved transactions of the short IDs in order. It returns them with the sum of
+// their lengths.
+func decompressTransactions(compressedTxs []*bxCompressedTransaction, bxTransactions []*types.BxTransaction,
+	fromShortID func(*types.BxTransaction) *types.BxBlockTransaction, txLength func([]byte) int) ([]*types.BxBlockTransaction, int, error) {
+	// the position of each transaction in the short IDs is known before the transactions are split across the workers
+	shortIDIndexes := make([]int, len(compressedTxs))
 	compressedTransactionCount := 0
-	txs := make([]*types.BxBlockTransaction, 0, len(sszBlock.Txs))
+	for i, tx := range compressedTxs {
+		if tx.IsFullTransaction {
+			continue
+		}
+		if compressedTransactionCount >= len(bxTransactions) {
+			return nil, 0, fmt.Errorf("could not decompress bad block: more empty transactions than short IDs provided")
+		}
+		shortIDIndexes[i] = compressedTransactionCount
+		compressedTransactionCount++
+	}
 
-	var txsBytes int
-	for _, tx := range sszBlock.Txs {
-		if !tx.IsFullTransaction {
-			if compressedTransactionCount >= len(bxTransactions) {
-				return nil, fmt.Errorf("could not decompress bad block: more empty transactions than short IDs provided")
+	txs := make([]*types.BxBlockTransaction, len(compressedTxs))
+	var txsBytes atomic.Int64
+	runDecompressionWorkers(len(compressedTxs), func(start, end int) {
+		var chunkBytes int
+		for i := start; i < end; i++ {
+			if compressedTxs[i].IsFullTransaction {
+				txs[i] = types.NewRawBxBlockTransaction(compressedTxs[i].Transaction)
+				chunkBytes += txLength(compressedTxs[i].Transaction)
+			} else {
+				bxTransaction := bxTransactions[shortIDIndexes[i]]
+				txs[i] = fromShortID(bxTransaction)
+				chunkBytes += txLength(bxTransaction.Content())
 			}
-			txs = append(txs, types.NewRawBxBlockTransaction(bxTransactions[compressedTransactionCount].Content()))
-			txsBytes += calcBeaconTransactionLength(bxTransactions[compressedTransactionCount].Content())
-			compressedTransactionCount++
-		} else {
-			txs = append(txs, types.NewRawBxBlockTransaction(tx.Transaction))
-			txsBytes += calcBeaconTransactionLength(tx.Transaction)
 		}
-	}
+		txsBytes.Add(int64(chunkBytes))
+	})
+	return txs, int(txsBytes.Load()), nil
+}
 
-	blockSize := len(sszBlock.Block) + txsBytes
+// runDecompressionWorkers splits the items [0, count) of a block into consecutive chunks processed concurrently by up
+// to decompressionWorkers goroutines, the blocks too small to benefit are processed by the calling goroutine
+func runDecompressionWorkers(count int, process func(start, end int)) {
+	workers := count / minDecompressionChunkSize
+	if workers > decompressionWorkers {
+		workers = decompressionWorkers
+	}
+	if workers <= 1 {
+		process(0, count)
+		return
+	}
 
-	return types.NewRawBxBlock(broadcast.Hash(), broadcast.BeaconHash(), broadcast.BlockType(), nil, txs, sszBlock.Block, nil, big.NewInt(int64(sszBlock.Number)), int(blockSize)), nil
+	chunkSize := (count + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < count; start += chunkSize {
+		end := start + chunkSize
+		if end > count {
+			end = count
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			process(start, end)
+		}(start, end)
+	}
+	wg.Wait()
 }
 
 func calcBeaconTransactionLength(rawTx []byte) int {
@@ -300,7 +386,9 @@ func (bp *blockProcessor) newRLPBlockBroadcast(block *types.BxBlock, networkNum
 		return nil, usedShortIDs, err
 	}
 
-	return bxmessage.NewBlockBroadcast(block.Hash(), types.EmptyHash, block.Type, encodedBlock, usedShortIDs, networkNum), usedShortIDs, nil
+	broadcast := bxmessage.NewBlockBroadcast(block.Hash(), types.EmptyHash, block.Type, encodedBlock, usedShortIDs, networkNum)
+	broadcast.Compress()
+	return broadcast, usedShortIDs, nil
 }
 
 func (bp *blockProcessor) newSSZBlockBroadcast(block *types.BxBlock, networkNum types.NetworkNum, minTxAge time.Duration) (*bxmessage.Broadcast, types.ShortIDList, error) {
@@ -342,7 +430,9 @@ func (bp *blockProcessor) newSSZBlockBroadcast(block *types.BxBlock, networkNum
 		return nil, usedShortIDs, err
 	}
 
-	return bxmessage.NewBlockBroadcast(block.Hash(), block.BeaconHash(), block.Type, encodedBlock, usedShortIDs, networkNum), usedShortIDs, nil
+	broadcast := bxmessage.NewBlockBroadcast(block.Hash(), block.BeaconHash(), block.Type, encodedBlock, usedShortIDs, networkNum)
+	broadcast.Compress()
+	return broadcast, usedShortIDs, nil
 }
 
 func (bp *blockProcessor) markProcessed(hash types.SHA256Hash) {
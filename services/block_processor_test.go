@@ -1,6 +1,7 @@
 package services
 
 import (
+	"fmt"
 	"math/big"
 	"testing"
 	"time"
@@ -214,3 +215,83 @@ func TestRLPBlockProcessor_ProcessBroadcast(t *testing.T) {
 	_, _, err = bp.BxBlockFromBroadcast(broadcast)
 	assert.Equal(t, ErrAlreadyProcessed, err)
 }
+
+// newTestCompressedBroadcast returns the broadcast of a block of txCount transactions, every other one of them sent as
+// a short ID of the store
+func newTestCompressedBroadcast(tb testing.TB, bp BlockProcessor, store *BxTxStore, txCount int) (*bxmessage.Broadcast, []*types.BxBlockTransaction) {
+	header, _ := rlp.EncodeToBytes(test.GenerateBytes(300))
+	trailer, _ := rlp.EncodeToBytes(test.GenerateBytes(350))
+
+	txs := make([]*types.BxBlockTransaction, 0, txCount)
+	for i := 0; i < txCount; i++ {
+		tx := types.NewBxBlockTransaction(types.GenerateSHA256Hash(), test.GenerateBytes(200))
+		if i%2 == 0 {
+			store.Add(tx.Hash(), tx.Content(), types.ShortID(i+1), testNetworkNum, false, 0, time.Now().Add(-time.Minute), 0, types.EmptySender)
+		}
+		txs = append(txs, tx)
+	}
+
+	bxBlock, err := types.NewBxBlock(types.GenerateSHA256Hash(), types.EmptyHash, types.BxBlockTypeEth, header, txs, trailer, big.NewInt(10000), big.NewInt(10), 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	broadcast, shortIDs, err := bp.BxBlockToBroadcast(bxBlock, testNetworkNum, time.Second)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if len(shortIDs) != (txCount+1)/2 {
+		tb.Fatalf("expected %v short IDs, got %v", (txCount+1)/2, len(shortIDs))
+	}
+
+	// the block may be decompressed by the same processor
+	bp.(*blockProcessor).processedBlocks.Remove(broadcast.Hash().String())
+	return broadcast, txs
+}
+
+func TestRLPBlockProcessor_BroadcastToBxBlockWorkers(t *testing.T) {
+	store := newTestBxTxStore()
+	bp := NewBlockProcessor(&store)
+
+	// the transactions are split across the workers in order
+	broadcast, txs := newTestCompressedBroadcast(t, bp, &store, 10*minDecompressionChunkSize+3)
+	bxBlock, missingShortIDs, err := bp.BxBlockFromBroadcast(broadcast)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(missingShortIDs))
+	assert.Equal(t, len(txs), len(bxBlock.Txs))
+	for i, tx := range bxBlock.Txs {
+		assert.Equal(t, txs[i].Content(), tx.Content())
+	}
+
+	// the missing short IDs are reported in order
+	broadcast, _ = newTestCompressedBroadcast(t, bp, &store, 4*minDecompressionChunkSize)
+	shortIDs := broadcast.ShortIDs()
+	store.RemoveShortIDs(&types.ShortIDList{shortIDs[10], shortIDs[100]}, FullReEntryProtection, "test")
+	_, missingShortIDs, err = bp.BxBlockFromBroadcast(broadcast)
+	assert.Equal(t, ErrMissingShortIDs, err)
+	assert.Equal(t, types.ShortIDList{shortIDs[10], shortIDs[100]}, missingShortIDs)
+}
+
+func BenchmarkRLPBlockProcessor_BxBlockFromBroadcast(b *testing.B) {
+	for _, txCount := range []int{100, 300, 1000} {
+		for _, workers := range []int{1, decompressionWorkers} {
+			b.Run(fmt.Sprintf("txs=%v/workers=%v", txCount, workers), func(b *testing.B) {
+				defer func(workers int) { decompressionWorkers = workers }(decompressionWorkers)
+				decompressionWorkers = workers
+
+				store := newTestBxTxStore()
+				bp := NewBlockProcessor(&store)
+				broadcast, _ := newTestCompressedBroadcast(b, bp, &store, txCount)
+				hash := broadcast.Hash().String()
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, _, err := bp.BxBlockFromBroadcast(broadcast); err != nil {
+						b.Fatal(err)
+					}
+					bp.(*blockProcessor).processedBlocks.Remove(hash)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// the sources a block is received from
+const (
+	BlockSourceBDN  = "bdn"
+	BlockSourceNode = "node"
+)
+
+// maxTrackedBlockSources is the number of blocks whose arrival is remembered, a block received from the second source
+// after it was forgotten is taken for a new block
+const maxTrackedBlockSources = 1024
+
+// BlockArrival is the source a block was received from first and, once it was received from the other source too, how
+// much earlier
+type BlockArrival struct {
+	Source      string
+	LeadTime    time.Duration
+	BothSources bool
+}
+
+// BlockSourceStats are the aggregate arrivals of the blocks received first from a source
+type BlockSourceStats struct {
+	// First is the number of blocks received first from the source
+	First uint64
+	// Both is the number of these blocks which were received from the other source afterwards
+	Both uint64
+	// LeadTime is the total lead of the source over the other source on these blocks
+	LeadTime time.Duration
+}
+
+type blockSources struct {
+	first    string
+	received time.Time
+	both     bool
+	lead     time.Duration
+}
+
+// BlockSourceTracker records which of the BDN and the blockchain node delivers each block first and by how much,
+// instead of only suppressing the block received second. All methods do nothing on a nil receiver.
+type BlockSourceTracker struct {
+	clock utils.Clock
+
+	lock   sync.Mutex
+	blocks map[string]*blockSources
+	order  []string
+	stats  map[string]*BlockSourceStats
+}
+
+// NewBlockSourceTracker creates the tracker timing the arrivals with clock
+func NewBlockSourceTracker(clock utils.Clock) *BlockSourceTracker {
+	return &BlockSourceTracker{
+		clock:  clock,
+		blocks: make(map[string]*blockSources),
+		stats: map[string]*BlockSourceStats{
+			BlockSourceBDN:  {},
+			BlockSourceNode: {},
+		},
+	}
+}
+
+// Observe records the block received from the source, the arrivals after the first one from each source are ignored
+func (t *BlockSourceTracker) Observe(hash string, source string) {
+	if t == nil {
+		return
+	}
+
+	now := t.clock.Now()
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	block, ok := t.blocks[hash]
+	if !ok {
+		if len(t.order) >= maxTrackedBlockSources {
+			delete(t.blocks, t.order[0])
+			t.order = t.order[1:]
+		}
+		t.blocks[hash] = &blockSources{first: source, received: now}
+		t.order = append(t.order, hash)
+		t.stats[source].First++
+		return
+	}
+	if block.both || block.first == source {
+		return
+	}
+
+	block.both = true
+	block.lead = now.Sub(block.received)
+	t.stats[block.first].Both++
+	t.stats[block.first].LeadTime += block.lead
+}
+
+// Arrival returns the arrival of the block, false if it was not observed
+func (t *BlockSourceTracker) Arrival(hash string) (BlockArrival, bool) {
+	if t == nil {
+		return BlockArrival{}, false
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	block, ok := t.blocks[hash]
+	if !ok {
+		return BlockArrival{}, false
+	}
+	return BlockArrival{Source: block.first, LeadTime: block.lead, BothSources: block.both}, true
+}
+
+// Stats returns the aggregate arrivals by the source the blocks were received from first
+func (t *BlockSourceTracker) Stats() map[string]BlockSourceStats {
+	if t == nil {
+		return nil
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats := make(map[string]BlockSourceStats, len(t.stats))
+	for source, s := range t.stats {
+		stats[source] = *s
+	}
+	return stats
+}
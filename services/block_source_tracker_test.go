@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSourceTracker(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1000, 0))
+	tracker := NewBlockSourceTracker(clock)
+
+	_, ok := tracker.Arrival("a")
+	assert.False(t, ok)
+
+	tracker.Observe("a", BlockSourceBDN)
+	arrival, ok := tracker.Arrival("a")
+	require.True(t, ok)
+	assert.Equal(t, BlockArrival{Source: BlockSourceBDN}, arrival)
+
+	// the second arrival from the same source is ignored
+	clock.IncTime(10 * time.Millisecond)
+	tracker.Observe("a", BlockSourceBDN)
+	clock.IncTime(30 * time.Millisecond)
+	tracker.Observe("a", BlockSourceNode)
+	arrival, _ = tracker.Arrival("a")
+	assert.Equal(t, BlockArrival{Source: BlockSourceBDN, LeadTime: 40 * time.Millisecond, BothSources: true}, arrival)
+
+	tracker.Observe("b", BlockSourceNode)
+	clock.IncTime(5 * time.Millisecond)
+	tracker.Observe("b", BlockSourceBDN)
+	tracker.Observe("c", BlockSourceNode)
+
+	assert.Equal(t, map[string]BlockSourceStats{
+		BlockSourceBDN:  {First: 1, Both: 1, LeadTime: 40 * time.Millisecond},
+		BlockSourceNode: {First: 2, Both: 1, LeadTime: 5 * time.Millisecond},
+	}, tracker.Stats())
+
+	// the oldest blocks are forgotten
+	for i := 0; i < maxTrackedBlockSources; i++ {
+		tracker.Observe(strconv.Itoa(i), BlockSourceBDN)
+	}
+	_, ok = tracker.Arrival("a")
+	assert.False(t, ok)
+
+	var none *BlockSourceTracker
+	none.Observe("a", BlockSourceBDN)
+	assert.Nil(t, none.Stats())
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// bundleStatusTTL is how long a bundle is tracked, the bundles target the next few blocks
+const bundleStatusTTL = 30 * time.Minute
+
+// bundleStatusRanks orders the statuses of the lifecycle, a bundle does not go back to a lower status
+var bundleStatusRanks = map[types.BundleStatus]int{
+	types.BundleSubmitted:        1,
+	types.BundleDispatched:       1,
+	types.BundleSimulated:        2,
+	types.BundleSimulationFailed: 2,
+	types.BundleLanded:           3,
+}
+
+type trackedBundle struct {
+	uuid            string
+	bundleHash      string
+	status          types.BundleStatus
+	builders        map[string]string
+	simulationError string
+	blockNumber     uint64
+	blockHash       string
+	// txHashes are the txs of the bundle which are not allowed to revert, the bundle landed once they are all included
+	txHashes []types.SHA256Hash
+	expiry   time.Time
+}
+
+// BundleStatusTracker follows the lifecycle of the MEV bundles submitted through the gateway or dispatched by it to
+// the builders: received by each builder, simulated on the blockchain node and landed in a block. The bundles are
+// identified by their UUID, or by their hash if they have none, a bundle with the UUID of a previous one supersedes
+// it. Each change is published as a notification of the bundleStatus feed.
+type BundleStatusTracker struct {
+	clock  utils.Clock
+	notify func(types.Notification)
+
+	lock    sync.Mutex
+	bundles map[string]*trackedBundle
+}
+
+// NewBundleStatusTracker creates the tracker publishing the status notifications with notify
+func NewBundleStatusTracker(clock utils.Clock, notify func(types.Notification)) *BundleStatusTracker {
+	return &BundleStatusTracker{
+		clock:   clock,
+		notify:  notify,
+		bundles: make(map[string]*trackedBundle),
+	}
+}
+
+// Submitted tracks the bundle submitted through the gateway, superseding the previous bundle of its UUID
+func (t *BundleStatusTracker) Submitted(bundle *bxmessage.MEVBundle) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	tracked := t.track(bundle, types.BundleSubmitted)
+	notification := tracked.notification()
+	t.lock.Unlock()
+
+	t.notify(notification)
+}
+
+// Cancelled reports the cancellation of the bundle of the UUID and stops tracking it
+func (t *BundleStatusTracker) Cancelled(uuid string) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	tracked, ok := t.bundles[uuid]
+	if !ok {
+		t.lock.Unlock()
+		return
+	}
+	delete(t.bundles, uuid)
+	tracked.status = types.BundleCancelled
+	notification := tracked.notification()
+	t.lock.Unlock()
+
+	t.notify(notification)
+}
+
+// Simulated records the simulation of the bundle on the blockchain node, err is set if it failed or a tx reverted
+func (t *BundleStatusTracker) Simulated(bundle *bxmessage.MEVBundle, err error) {
+	if t == nil {
+		return
+	}
+
+	status := types.BundleSimulated
+	if err != nil {
+		status = types.BundleSimulationFailed
+	}
+
+	t.lock.Lock()
+	tracked, ok := t.bundles[bundleKey(bundle)]
+	if !ok || tracked.bundleHash != bundle.BundleHash || bundleStatusRanks[status] <= bundleStatusRanks[tracked.status] {
+		t.lock.Unlock()
+		return
+	}
+	tracked.status = status
+	if err != nil {
+		tracked.simulationError = err.Error()
+	}
+	notification := tracked.notification()
+	t.lock.Unlock()
+
+	t.notify(notification)
+}
+
+// BuilderResponse records the response of the builder the bundle was dispatched to, err is set if the builder did
+// not accept it. The bundles dispatched by the gateway are tracked from their first response.
+func (t *BundleStatusTracker) BuilderResponse(bundle *bxmessage.MEVBundle, builder string, err error) {
+	if t == nil || bundle.IsCancellation() {
+		return
+	}
+
+	response := types.BuilderReceived
+	if err != nil {
+		response = err.Error()
+	}
+
+	t.lock.Lock()
+	tracked, ok := t.bundles[bundleKey(bundle)]
+	if !ok || tracked.bundleHash != bundle.BundleHash {
+		tracked = t.track(bundle, types.BundleDispatched)
+	}
+	tracked.builders[builder] = response
+	notification := tracked.notification()
+	t.lock.Unlock()
+
+	t.notify(notification)
+}
+
+// ObserveBlock records the landing of the tracked bundles whose txs the block includes
+func (t *BundleStatusTracker) ObserveBlock(block *types.BxBlock) {
+	if t == nil || block == nil || block.Number == nil || !block.Number.IsUint64() {
+		return
+	}
+
+	var notifications []types.Notification
+	t.lock.Lock()
+	t.expire()
+	if len(t.bundles) == 0 {
+		t.lock.Unlock()
+		return
+	}
+
+	included := make(map[types.SHA256Hash]struct{}, len(block.Txs))
+	for _, blockTx := range block.Txs {
+		included[blockTx.Hash()] = struct{}{}
+	}
+	for _, tracked := range t.bundles {
+		if tracked.status == types.BundleLanded || len(tracked.txHashes) == 0 {
+			continue
+		}
+		landed := true
+		for _, hash := range tracked.txHashes {
+			if _, ok := included[hash]; !ok {
+				landed = false
+				break
+			}
+		}
+		if !landed {
+			continue
+		}
+		tracked.status = types.BundleLanded
+		tracked.blockNumber = block.Number.Uint64()
+		tracked.blockHash = block.Hash().Format(true)
+		notifications = append(notifications, tracked.notification())
+	}
+	t.lock.Unlock()
+
+	for _, notification := range notifications {
+		t.notify(notification)
+	}
+}
+
+// track starts tracking the bundle with the status, replacing the bundle tracked with its key
+func (t *BundleStatusTracker) track(bundle *bxmessage.MEVBundle, status types.BundleStatus) *trackedBundle {
+	tracked := &trackedBundle{
+		uuid:       bundle.UUID,
+		bundleHash: bundle.BundleHash,
+		status:     status,
+		builders:   make(map[string]string),
+		txHashes:   bundleTxHashes(bundle),
+		expiry:     t.clock.Now().Add(bundleStatusTTL),
+	}
+	t.bundles[bundleKey(bundle)] = tracked
+	return tracked
+}
+
+// expire stops tracking the bundles tracked for longer than the TTL
+func (t *BundleStatusTracker) expire() {
+	now := t.clock.Now()
+	for key, tracked := range t.bundles {
+		if now.After(tracked.expiry) {
+			delete(t.bundles, key)
+		}
+	}
+}
+
+func (b *trackedBundle) notification() *types.BundleStatusNotification {
+	notification := &types.BundleStatusNotification{
+		UUID:            b.uuid,
+		BundleHash:      b.bundleHash,
+		Status:          b.status,
+		SimulationError: b.simulationError,
+	}
+	if len(b.builders) > 0 {
+		notification.Builders = make(map[string]string, len(b.builders))
+		for builder, response := range b.builders {
+			notification.Builders[builder] = response
+		}
+	}
+	if b.status == types.BundleLanded {
+		notification.BlockNumber = b.blockNumber
+		notification.BlockHash = b.blockHash
+	}
+	return notification
+}
+
+// bundleKey identifies the bundle by its UUID, or by its hash if it has none
+func bundleKey(bundle *bxmessage.MEVBundle) string {
+	if bundle.UUID != "" {
+		return bundle.UUID
+	}
+	return bundle.BundleHash
+}
+
+// bundleTxHashes returns the hashes of the txs of the bundle which are not allowed to revert, the txs which cannot be
+// decoded are skipped
+func bundleTxHashes(bundle *bxmessage.MEVBundle) []types.SHA256Hash {
+	hashes := make([]types.SHA256Hash, 0, len(bundle.Transactions))
+	for _, rawTx := range bundle.Transactions {
+		txContent, err := types.DecodeHex(rawTx)
+		if err != nil {
+			continue
+		}
+		ethTx, err := utils.ParseRawTransaction(txContent)
+		if err != nil {
+			continue
+		}
+		reverting := false
+		for _, revertingHash := range bundle.RevertingHashes {
+			if strings.EqualFold(revertingHash, ethTx.Hash().String()) {
+				reverting = true
+				break
+			}
+		}
+		if !reverting {
+			hashes = append(hashes, types.SHA256Hash(ethTx.Hash()))
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundleStatusTestTx(t *testing.T, nonce uint64) (string, types.SHA256Hash) {
+	ethTx := ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000})
+	rawTx, err := ethTx.MarshalBinary()
+	require.NoError(t, err)
+	return hexutil.Encode(rawTx), types.SHA256Hash(ethTx.Hash())
+}
+
+func TestBundleStatusTracker(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	rawTx1, hash1 := bundleStatusTestTx(t, 1)
+	rawTx2, hash2 := bundleStatusTestTx(t, 2)
+
+	var notifications []*types.BundleStatusNotification
+	tracker := NewBundleStatusTracker(clock, func(notification types.Notification) {
+		notifications = append(notifications, notification.(*types.BundleStatusNotification))
+	})
+
+	bundle := &bxmessage.MEVBundle{UUID: "c40df8ec-844d-4887-8129-27bb80812680", BundleHash: "0x01", Transactions: []string{rawTx1}}
+	tracker.Submitted(bundle)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, types.BundleSubmitted, notifications[0].Status)
+
+	// the bundle submitted with the same UUID supersedes the first one, whose late results are ignored
+	superseding := &bxmessage.MEVBundle{UUID: bundle.UUID, BundleHash: "0x02", Transactions: []string{rawTx1, rawTx2}}
+	tracker.Submitted(superseding)
+	tracker.Simulated(bundle, nil)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, "0x02", notifications[1].BundleHash)
+
+	tracker.Simulated(superseding, errors.New("tx reverted"))
+	tracker.BuilderResponse(superseding, "flashbots", nil)
+	tracker.BuilderResponse(superseding, "beaverbuild", errors.New("builder error: bundle too late"))
+	require.Len(t, notifications, 5)
+	assert.Equal(t, types.BundleSimulationFailed, notifications[2].Status)
+	assert.Equal(t, "tx reverted", notifications[2].SimulationError)
+	assert.Equal(t, map[string]string{"flashbots": types.BuilderReceived, "beaverbuild": "builder error: bundle too late"}, notifications[4].Builders)
+
+	// the bundle lands once all its txs are included
+	notifications = nil
+	tracker.ObserveBlock(txStatusTestBlock(t, 100, types.SHA256Hash{0xa}, hash1))
+	assert.Empty(t, notifications)
+	tracker.ObserveBlock(txStatusTestBlock(t, 101, types.SHA256Hash{0xb}, hash2, hash1))
+	require.Len(t, notifications, 1)
+	assert.Equal(t, types.BundleLanded, notifications[0].Status)
+	assert.Equal(t, uint64(101), notifications[0].BlockNumber)
+	assert.Equal(t, types.SHA256Hash{0xb}.Format(true), notifications[0].BlockHash)
+
+	// a dispatched bundle is tracked from the response of its first builder
+	notifications = nil
+	dispatched := &bxmessage.MEVBundle{BundleHash: "0x03", Transactions: []string{rawTx2}}
+	tracker.BuilderResponse(dispatched, "flashbots", nil)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, types.BundleDispatched, notifications[0].Status)
+
+	// the cancellation stops tracking the bundle of the UUID
+	notifications = nil
+	tracker.Cancelled(bundle.UUID)
+	tracker.Cancelled(bundle.UUID)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, types.BundleCancelled, notifications[0].Status)
+
+	// the bundles are no longer tracked after the TTL
+	notifications = nil
+	clock.IncTime(bundleStatusTTL + time.Second)
+	tracker.ObserveBlock(txStatusTestBlock(t, 102, types.SHA256Hash{0xc}, hash2))
+	assert.Empty(t, notifications)
+}
@@ -32,6 +32,7 @@ type BxTxStore struct {
 	assigner               ShortIDAssigner
 	cleanedShortIDsChannel chan types.ShortIDsByNetwork
 	bloom                  BloomFilter
+	persistence            TxStorePersistence
 }
 
 // NewBxTxStore creates a new BxTxStore to store and processes all relevant transactions
@@ -70,9 +71,42 @@ func (t *BxTxStore) Start() error {
 func (t *BxTxStore) Stop() {
 	t.quit <- true
 	<-t.quit
+	if t.persistence != nil {
+		if err := t.persistence.Close(); err != nil {
+			log.Errorf("failed to close the persisted TxStore: %v", err)
+		}
+	}
 }
 
-// Clear removes all elements from txs and shortIDToHash
+// Recover loads the transactions persisted before the restart, pruning the ones the cleanup would have removed by now,
+// and persists the changes of the transactions from now on. Recover should be called before Start.
+func (t *BxTxStore) Recover(persistence TxStorePersistence) error {
+	currTime := t.clock.Now()
+	timeStart := currTime
+	loaded, pruned, err := persistence.Load(func(bxTransaction *types.BxTransaction) bool {
+		txAge := currTime.Sub(bxTransaction.AddTime())
+		if txAge > t.maxTxAge || (txAge > t.noSIDAge && len(bxTransaction.ShortIDs()) == 0) {
+			return false
+		}
+
+		t.hashToContent.Store(string(bxTransaction.Hash().Bytes()), bxTransaction)
+		for _, shortID := range bxTransaction.ShortIDs() {
+			t.shortIDToHash.Store(shortID, bxTransaction.Hash())
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to recover the persisted TxStore: %v", err)
+	}
+
+	t.persistence = persistence
+	log.Infof("TxStore recovered %v transactions with %v short IDs in %v, pruned %v expired transactions",
+		loaded, t.shortIDToHash.Size(), t.clock.Now().Sub(timeStart), pruned)
+	return nil
+}
+
+// Clear removes all elements from txs and shortIDToHash. The persisted transactions are kept, as the ones which
+// expire by the next restart are pruned by Recover.
 func (t *BxTxStore) Clear() {
 	t.hashToContent.Clear()
 	t.shortIDToHash.Clear()
@@ -91,6 +125,9 @@ func (t *BxTxStore) remove(hash string, reEntryProtection ReEntryProtectionFlags
 		for _, shortID := range bxTransaction.ShortIDs() {
 			t.shortIDToHash.Delete(shortID)
 		}
+		if t.persistence != nil {
+			t.persistence.Delete(bxTransaction.Hash())
+		}
 		// if asked, add the hash to the history map so we remember this transaction for some time
 		// and prevent if from being added back to the TxStore
 		switch reEntryProtection {
@@ -233,6 +270,9 @@ func (t *BxTxStore) Add(hash types.SHA256Hash, content types.TxContent, shortID
 	if result.NewSID {
 		t.shortIDToHash.Store(shortID, bxTransaction.Hash())
 	}
+	if t.persistence != nil && (result.NewSID || result.NewContent || result.Reprocess) {
+		t.persistence.Store(bxTransaction)
+	}
 
 	return result
 }
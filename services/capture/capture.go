@@ -0,0 +1,304 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// record header: timestamp (8) + protocol (4) + source (1) + message length (4)
+const recordHeaderLen = 17
+
+// Source is the connection type a captured message was received from
+type Source uint8
+
+// Source types
+const (
+	SourceRelay Source = iota + 1
+	SourceBlockchain
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceRelay:
+		return "relay"
+	case SourceBlockchain:
+		return "blockchain"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// Record is a captured inbound message
+type Record struct {
+	Time     time.Time
+	Source   Source
+	Protocol bxmessage.Protocol
+	Msg      []byte
+}
+
+// Recorder captures inbound messages for later replay
+type Recorder interface {
+	// Record captures an encoded message received from the source
+	Record(source Source, protocol bxmessage.Protocol, msg []byte)
+	// RecordMessage captures a message received from the source, packing it only if capture is enabled
+	RecordMessage(source Source, msg bxmessage.Message)
+	Close() error
+}
+
+type noCapture struct {
+}
+
+func (noCapture) Record(Source, bxmessage.Protocol, []byte) {
+}
+
+func (noCapture) RecordMessage(Source, bxmessage.Message) {
+}
+
+func (noCapture) Close() error {
+	return nil
+}
+
+const (
+	// recordBufferSize is the number of records waiting to be written, records are dropped once it is full
+	recordBufferSize   = 10000
+	droppedLogInterval = time.Minute
+)
+
+// fileRecorder writes records to a ring of two files, the current file is rotated to path.1 once it exceeds maxSize.
+// Records are written by a writer goroutine, so capture never blocks message handling on the disk.
+type fileRecorder struct {
+	path    string
+	maxSize int64
+	clock   utils.Clock
+	file    *os.File
+	size    int64
+
+	lock    sync.RWMutex
+	closed  bool
+	records chan Record
+	done    chan struct{}
+	dropped atomic.Uint64
+	err     error
+}
+
+// NewRecorder creates a Recorder writing to the capture file at path, capture is disabled if path is empty
+func NewRecorder(path string, maxSize int64, clock utils.Clock) (Recorder, error) {
+	if path == "" {
+		return noCapture{}, nil
+	}
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("invalid capture file size %v", maxSize)
+	}
+
+	r := &fileRecorder{
+		path:    path,
+		maxSize: maxSize,
+		clock:   clock,
+		records: make(chan Record, recordBufferSize),
+		done:    make(chan struct{}),
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	go r.run()
+	return r, nil
+}
+
+func (r *fileRecorder) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open capture file %v: %v", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat capture file %v: %v", r.path, err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *fileRecorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// Record queues the message to be written to the capture file, dropping it if the queue is full
+func (r *fileRecorder) Record(source Source, protocol bxmessage.Protocol, msg []byte) {
+	record := Record{
+		Time:     r.clock.Now(),
+		Source:   source,
+		Protocol: protocol,
+		Msg:      msg,
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if r.closed {
+		return
+	}
+	select {
+	case r.records <- record:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// RecordMessage packs the message with the current protocol and queues it to be written to the capture file. The
+// message is packed by the caller, so later changes to the message are not captured.
+func (r *fileRecorder) RecordMessage(source Source, msg bxmessage.Message) {
+	buf, err := msg.Pack(bxmessage.CurrentProtocol)
+	if err != nil {
+		log.Errorf("failed to pack %v for capture: %v", msg, err)
+		return
+	}
+	r.Record(source, bxmessage.CurrentProtocol, buf)
+}
+
+func (r *fileRecorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(droppedLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case record, ok := <-r.records:
+			if !ok {
+				if r.file != nil {
+					r.err = r.file.Close()
+					r.file = nil
+				}
+				return
+			}
+			r.write(record)
+		case <-ticker.C:
+			if dropped := r.dropped.Swap(0); dropped > 0 {
+				log.Warnf("dropped %v messages in the last %v, the capture queue of %v was full", dropped, droppedLogInterval, r.path)
+			}
+		}
+	}
+}
+
+func (r *fileRecorder) write(record Record) {
+	if r.file == nil {
+		return
+	}
+
+	buf := encodeRecord(record)
+	if r.size > 0 && r.size+int64(len(buf)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			log.Errorf("failed to rotate capture file %v, disabling capture: %v", r.path, err)
+			r.file = nil
+			return
+		}
+	}
+	n, err := r.file.Write(buf)
+	r.size += int64(n)
+	if err != nil {
+		log.Errorf("failed to write to capture file %v: %v", r.path, err)
+	}
+}
+
+// Close writes the queued records and closes the capture file
+func (r *fileRecorder) Close() error {
+	r.lock.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.records)
+	}
+	r.lock.Unlock()
+
+	<-r.done
+	return r.err
+}
+
+func encodeRecord(record Record) []byte {
+	buf := make([]byte, recordHeaderLen+len(record.Msg))
+	binary.LittleEndian.PutUint64(buf, uint64(record.Time.UnixNano()))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(record.Protocol))
+	buf[12] = byte(record.Source)
+	binary.LittleEndian.PutUint32(buf[13:], uint32(len(record.Msg)))
+	copy(buf[recordHeaderLen:], record.Msg)
+	return buf
+}
+
+// readRecords reads all complete records from r, a truncated record at the end is ignored
+func readRecords(r io.Reader, from, to time.Time) ([]Record, error) {
+	reader := bufio.NewReader(r)
+	header := make([]byte, recordHeaderLen)
+	var records []Record
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return records, nil
+			}
+			return records, err
+		}
+
+		msg := make([]byte, binary.LittleEndian.Uint32(header[13:]))
+		if _, err := io.ReadFull(reader, msg); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return records, nil
+			}
+			return records, err
+		}
+
+		record := Record{
+			Time:     time.Unix(0, int64(binary.LittleEndian.Uint64(header))),
+			Protocol: bxmessage.Protocol(binary.LittleEndian.Uint32(header[8:])),
+			Source:   Source(header[12]),
+			Msg:      msg,
+		}
+		if (!from.IsZero() && record.Time.Before(from)) || (!to.IsZero() && record.Time.After(to)) {
+			continue
+		}
+		records = append(records, record)
+	}
+}
+
+// ReadWindow returns the records of the capture file at path, including the rotated path.1 file, captured between
+// from and to. A zero from or to leaves the window open on that side.
+func ReadWindow(path string, from, to time.Time) ([]Record, error) {
+	var records []Record
+	found := false
+	for _, name := range []string{path + ".1", path} {
+		file, err := os.Open(name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+
+		fileRecords, err := readRecords(file, from, to)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read capture file %v: %v", name, err)
+		}
+		records = append(records, fileRecords...)
+	}
+	if !found {
+		return nil, fmt.Errorf("capture file %v does not exist", path)
+	}
+	return records, nil
+}
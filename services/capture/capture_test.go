@@ -0,0 +1,134 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetworkNum types.NetworkNum = 5
+
+func testTx(i byte) *bxmessage.Tx {
+	tx := bxmessage.NewTx(types.SHA256Hash{i}, types.TxContent{i, i}, testNetworkNum, types.TFLocalRegion, types.EmptyAccountID)
+	tx.SetShortID(types.ShortID(i))
+	return tx
+}
+
+func TestRecorder_ReadWindow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	clock := &utils.MockClock{}
+	start := time.Unix(1700000000, 0)
+	clock.SetTime(start)
+
+	recorder, err := NewRecorder(path, 1<<20, clock)
+	require.NoError(t, err)
+
+	for i := byte(1); i <= 3; i++ {
+		recorder.RecordMessage(SourceRelay, testTx(i))
+		clock.IncTime(time.Second)
+	}
+	require.NoError(t, recorder.Close())
+
+	records, err := ReadWindow(path, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, SourceRelay, records[0].Source)
+	assert.Equal(t, bxmessage.Protocol(bxmessage.CurrentProtocol), records[0].Protocol)
+	assert.Equal(t, start, records[0].Time)
+
+	records, err = ReadWindow(path, start.Add(time.Second), start.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, start.Add(time.Second), records[0].Time)
+}
+
+func TestRecorder_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+
+	buf, err := testTx(1).Pack(bxmessage.CurrentProtocol)
+	require.NoError(t, err)
+	recordSize := int64(recordHeaderLen + len(buf))
+
+	recorder, err := NewRecorder(path, 2*recordSize, clock)
+	require.NoError(t, err)
+	for i := byte(1); i <= 5; i++ {
+		recorder.RecordMessage(SourceBlockchain, testTx(i))
+		clock.IncTime(time.Second)
+	}
+	require.NoError(t, recorder.Close())
+
+	// records 1 and 2 were dropped with the first rotated file
+	records, err := ReadWindow(path, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	sandbox := NewSandbox(testNetworkNum)
+	results := sandbox.Replay(records)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.Equal(t, bxmessage.TxType, result.Type)
+	}
+	assert.Equal(t, 3, sandbox.TxStore().Count())
+}
+
+func TestReadWindow_TruncatedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+
+	recorder, err := NewRecorder(path, 1<<20, clock)
+	require.NoError(t, err)
+	recorder.RecordMessage(SourceRelay, testTx(1))
+	recorder.RecordMessage(SourceRelay, testTx(2))
+	require.NoError(t, recorder.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-1))
+
+	records, err := ReadWindow(path, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestNewRecorder_Disabled(t *testing.T) {
+	recorder, err := NewRecorder("", 0, utils.RealClock{})
+	require.NoError(t, err)
+	recorder.RecordMessage(SourceRelay, testTx(1))
+	assert.NoError(t, recorder.Close())
+}
+
+func TestRecorder_DropsWhenQueueFull(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+
+	// without writer goroutine the queue is never drained
+	recorder := &fileRecorder{clock: clock, records: make(chan Record, 1)}
+	recorder.RecordMessage(SourceRelay, testTx(1))
+	recorder.RecordMessage(SourceRelay, testTx(2))
+	assert.Len(t, recorder.records, 1)
+	assert.Equal(t, uint64(1), recorder.dropped.Load())
+}
+
+func TestRecorder_RecordAfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture")
+	recorder, err := NewRecorder(path, 1<<20, utils.RealClock{})
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+
+	recorder.RecordMessage(SourceRelay, testTx(1))
+	assert.NoError(t, recorder.Close())
+
+	records, err := ReadWindow(path, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
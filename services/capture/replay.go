@@ -0,0 +1,132 @@
+package capture
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/services"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+// ReplayResult is the outcome of re-injecting a captured message
+type ReplayResult struct {
+	Record Record
+	Type   string
+	Result string
+	Err    error
+}
+
+func (r ReplayResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%v %v %v: error: %v", r.Record.Time.UTC().Format(time.RFC3339Nano), r.Record.Source, r.Type, r.Err)
+	}
+	return fmt.Sprintf("%v %v %v: %v", r.Record.Time.UTC().Format(time.RFC3339Nano), r.Record.Source, r.Type, r.Result)
+}
+
+// Sandbox is a tx store and block processor isolated from any running gateway that captured messages are
+// re-injected into, to reproduce how they were processed
+type Sandbox struct {
+	networkNum     types.NetworkNum
+	txStore        services.TxStore
+	blockProcessor services.BlockProcessor
+}
+
+// NewSandbox creates an empty sandbox for the network
+func NewSandbox(networkNum types.NetworkNum) *Sandbox {
+	txStore := services.NewBxTxStore(time.Hour, time.Hour, time.Hour, services.NewEmptyShortIDAssigner(),
+		services.NewHashHistory("seenTxs", time.Hour), nil, time.Hour, services.NoOpBloomFilter{})
+	return &Sandbox{
+		networkNum:     networkNum,
+		txStore:        &txStore,
+		blockProcessor: services.NewBlockProcessor(&txStore),
+	}
+}
+
+// TxStore returns the tx store of the sandbox
+func (s *Sandbox) TxStore() services.TxStore {
+	return s.txStore
+}
+
+// Replay re-injects the records in order and returns the outcome of each one
+func (s *Sandbox) Replay(records []Record) []ReplayResult {
+	results := make([]ReplayResult, 0, len(records))
+	for _, record := range records {
+		results = append(results, s.replay(record))
+	}
+	return results
+}
+
+func (s *Sandbox) replay(record Record) ReplayResult {
+	result := ReplayResult{Record: record}
+	if len(record.Msg) < bxmessage.HeaderLen {
+		result.Err = fmt.Errorf("message of %v bytes is shorter than the header", len(record.Msg))
+		return result
+	}
+	result.Type = bxmessage.NewMessageBytes(record.Msg, record.Time).BxType()
+
+	switch result.Type {
+	case bxmessage.TxType:
+		tx := &bxmessage.Tx{}
+		if result.Err = tx.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		txResult := s.txStore.Add(tx.Hash(), tx.Content(), tx.ShortID(), s.networkNum, false, tx.Flags(), tx.Timestamp(), 0, tx.Sender())
+		result.Result = fmt.Sprintf("tx %v short ID %v new tx %v new content %v new short ID %v", tx.Hash(), tx.ShortID(), txResult.NewTx, txResult.NewContent, txResult.NewSID)
+	case bxmessage.TransactionsType:
+		txs := &bxmessage.Txs{}
+		if result.Err = txs.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		for _, item := range txs.Items() {
+			s.txStore.Add(item.Hash, item.Content, item.ShortID, s.networkNum, false, 0, record.Time, 0, types.EmptySender)
+		}
+		result.Result = fmt.Sprintf("added %v txs", len(txs.Items()))
+	case bxmessage.SyncTxsType:
+		syncTxs := &bxmessage.SyncTxsMessage{}
+		if result.Err = syncTxs.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		for _, csi := range syncTxs.ContentShortIds {
+			if len(csi.ShortIDs) == 0 {
+				continue
+			}
+			s.txStore.Add(csi.Hash, csi.Content, csi.ShortIDs[0], s.networkNum, false, csi.ShortIDFlags[0], csi.Timestamp(), 0, types.EmptySender)
+		}
+		result.Result = fmt.Sprintf("synced %v txs", len(syncTxs.ContentShortIds))
+	case bxmessage.BroadcastType:
+		broadcast := &bxmessage.Broadcast{}
+		if result.Err = broadcast.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		var bxBlock *types.BxBlock
+		var missingShortIDs types.ShortIDList
+		bxBlock, missingShortIDs, result.Err = s.blockProcessor.BxBlockFromBroadcast(broadcast)
+		if result.Err == services.ErrMissingShortIDs {
+			result.Err = fmt.Errorf("block %v is missing %v short IDs: %v", broadcast.Hash(), len(missingShortIDs), missingShortIDs)
+			break
+		}
+		if result.Err != nil {
+			result.Err = fmt.Errorf("could not decompress block %v: %v", broadcast.Hash(), result.Err)
+			break
+		}
+		result.Result = fmt.Sprintf("decompressed %v with %v txs from %v short IDs", bxBlock, len(bxBlock.Txs), len(broadcast.ShortIDs()))
+	case bxmessage.TxCleanupType:
+		cleanup := &bxmessage.TxCleanup{}
+		if result.Err = cleanup.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		s.txStore.RemoveShortIDs(&cleanup.ShortIDs, services.FullReEntryProtection, "replayed TxCleanup message")
+		result.Result = fmt.Sprintf("removed %v short IDs", len(cleanup.ShortIDs))
+	case bxmessage.BlockConfirmationType:
+		confirmation := &bxmessage.BlockConfirmation{}
+		if result.Err = confirmation.Unpack(record.Msg, record.Protocol); result.Err != nil {
+			break
+		}
+		s.txStore.RemoveHashes(&confirmation.Hashes, services.ShortReEntryProtection, "replayed BlockConfirmation message")
+		result.Result = fmt.Sprintf("removed %v hashes", len(confirmation.Hashes))
+	default:
+		result.Result = "not processed"
+	}
+	return result
+}
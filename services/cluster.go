@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	clusterGossipPath    = "/cluster/gossip"
+	clusterMaxGossipSize = 64 * 1024
+	// clusterSignatureHeader holds the HMAC-SHA256 of the gossip body with the cluster secret
+	clusterSignatureHeader = "X-Cluster-Signature"
+)
+
+// ClusterConfig describes the cluster membership of a gateway
+type ClusterConfig struct {
+	NodeID         types.NodeID
+	ListenAddress  string
+	Peers          []string
+	GossipInterval time.Duration
+	PeerTimeout    time.Duration
+	// Secret is shared by the members to sign their gossip, unsigned gossip is rejected
+	Secret string
+}
+
+// Cluster shares the ownership of the transactions and the feeds between gateways of one operator publishing to a
+// shared sink, the ownership moves to the other members when one dies. Only the published export is deduplicated and
+// failed over, the subscriptions of each gateway are always served by that gateway.
+type Cluster interface {
+	Start(ctx context.Context) error
+	// ShouldDeliver reports if this gateway is the one to deliver the notification to the shared sink
+	ShouldDeliver(notification types.Notification) bool
+	// OwnsFeed reports if this gateway currently publishes the feed on behalf of the cluster
+	OwnsFeed(feed types.FeedType) bool
+	// Members returns the IDs of the gateways currently alive in the cluster, including this one
+	Members() []types.NodeID
+}
+
+// NoOpCluster is used when cluster mode is disabled, every notification is delivered
+type NoOpCluster struct{}
+
+// Start does nothing
+func (NoOpCluster) Start(context.Context) error { return nil }
+
+// ShouldDeliver always delivers
+func (NoOpCluster) ShouldDeliver(types.Notification) bool { return true }
+
+// OwnsFeed always owns the feed
+func (NoOpCluster) OwnsFeed(types.FeedType) bool { return true }
+
+// Members returns no members
+func (NoOpCluster) Members() []types.NodeID { return nil }
+
+// clusterGossip is the heartbeat of a member, a member which does not gossip for the peer timeout is considered dead
+type clusterGossip struct {
+	NodeID types.NodeID `json:"node_id"`
+}
+
+type gossipCluster struct {
+	cfg       ClusterConfig
+	clock     utils.Clock
+	client    *http.Client
+	lock      sync.Mutex
+	peersSeen map[types.NodeID]time.Time
+	log       *log.Entry
+}
+
+// NewCluster creates a cluster member which gossips over HTTP with the configured peers
+func NewCluster(cfg ClusterConfig, clock utils.Clock) Cluster {
+	return newGossipCluster(cfg, clock)
+}
+
+func newGossipCluster(cfg ClusterConfig, clock utils.Clock) *gossipCluster {
+	return &gossipCluster{
+		cfg:       cfg,
+		clock:     clock,
+		client:    &http.Client{Timeout: cfg.GossipInterval * 2},
+		peersSeen: make(map[types.NodeID]time.Time),
+		log: log.WithFields(log.Fields{
+			"component": "cluster",
+			"nodeID":    cfg.NodeID,
+		}),
+	}
+}
+
+// Start runs the gossip server and the gossip loop until the context is done
+func (c *gossipCluster) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(clusterGossipPath, c.handleGossip)
+	server := &http.Server{Addr: c.cfg.ListenAddress, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	go c.gossipLoop(ctx)
+
+	c.log.Infof("starting cluster gossip at %v with peers %v", c.cfg.ListenAddress, c.cfg.Peers)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("cluster gossip server failed: %v", err)
+	}
+	return nil
+}
+
+// ShouldDeliver delivers each transaction from the member owning its hash, and all other feeds from their owner.
+// The members receive the same transactions from the BDN, so no coordination is needed to deduplicate them.
+func (c *gossipCluster) ShouldDeliver(notification types.Notification) bool {
+	feed := notification.NotificationType()
+	switch feed {
+	case types.NewTxsFeed, types.PendingTxsFeed:
+		return c.owns(notification.GetHash())
+	default:
+		return c.OwnsFeed(feed)
+	}
+}
+
+// OwnsFeed reports if this gateway currently publishes the feed on behalf of the cluster
+func (c *gossipCluster) OwnsFeed(feed types.FeedType) bool {
+	return c.owns(string(feed))
+}
+
+// owns uses rendezvous hashing of the key over the alive members, so the key moves to another member once its owner
+// dies, and only the keys of the dead member move
+func (c *gossipCluster) owns(key string) bool {
+	var owner types.NodeID
+	var ownerScore uint64
+	for _, member := range c.Members() {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(string(member) + "/" + key))
+		if score := h.Sum64(); owner == "" || score > ownerScore {
+			owner = member
+			ownerScore = score
+		}
+	}
+	return owner == c.cfg.NodeID
+}
+
+// Members returns the IDs of the gateways currently alive in the cluster, including this one
+func (c *gossipCluster) Members() []types.NodeID {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	members := []types.NodeID{c.cfg.NodeID}
+	for nodeID, lastSeen := range c.peersSeen {
+		if now.Sub(lastSeen) > c.cfg.PeerTimeout {
+			continue
+		}
+		members = append(members, nodeID)
+	}
+	return members
+}
+
+func (c *gossipCluster) handleGossip(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, clusterMaxGossipSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !c.validSignature(body, r.Header.Get(clusterSignatureHeader)) {
+		c.log.Debugf("rejecting gossip from %v with invalid signature", r.RemoteAddr)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var gossip clusterGossip
+	if err = json.Unmarshal(body, &gossip); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if gossip.NodeID == "" || gossip.NodeID == c.cfg.NodeID {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	c.receiveGossip(gossip)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *gossipCluster) receiveGossip(gossip clusterGossip) {
+	c.lock.Lock()
+	_, known := c.peersSeen[gossip.NodeID]
+	c.peersSeen[gossip.NodeID] = c.clock.Now()
+	c.lock.Unlock()
+
+	if !known {
+		c.log.Infof("cluster member %v joined", gossip.NodeID)
+	}
+}
+
+func (c *gossipCluster) gossipLoop(ctx context.Context) {
+	ticker := c.clock.Ticker(c.cfg.GossipInterval)
+	defer ticker.Stop()
+
+	body, err := json.Marshal(clusterGossip{NodeID: c.cfg.NodeID})
+	if err != nil {
+		c.log.Errorf("failed to marshal gossip: %v", err)
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			for _, peer := range c.cfg.Peers {
+				go c.sendGossip(peer, body)
+			}
+			c.removeDeadPeers()
+		}
+	}
+}
+
+func (c *gossipCluster) sendGossip(peer string, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://%v%v", peer, clusterGossipPath), bytes.NewReader(body))
+	if err != nil {
+		c.log.Errorf("failed to create gossip request for %v: %v", peer, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(clusterSignatureHeader, c.sign(body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.log.Tracef("failed to gossip with %v: %v", peer, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.log.Warnf("gossip rejected by %v, check that the cluster members share the same secret", peer)
+	}
+}
+
+func (c *gossipCluster) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(c.cfg.Secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *gossipCluster) validSignature(body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(c.cfg.Secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+func (c *gossipCluster) removeDeadPeers() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.clock.Now()
+	for nodeID, lastSeen := range c.peersSeen {
+		if now.Sub(lastSeen) > c.cfg.PeerTimeout {
+			c.log.Warnf("cluster member %v did not gossip since %v, failing over its feeds", nodeID, lastSeen)
+			delete(c.peersSeen, nodeID)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCluster(nodeID types.NodeID, clock utils.Clock) *gossipCluster {
+	return newGossipCluster(ClusterConfig{
+		NodeID:         nodeID,
+		GossipInterval: 100 * time.Millisecond,
+		PeerTimeout:    time.Second,
+		Secret:         "secret",
+	}, clock)
+}
+
+func TestCluster_ShouldDeliverDeduplicatesTxs(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	first := newTestCluster("first", &clock)
+	second := newTestCluster("second", &clock)
+	first.receiveGossip(clusterGossip{NodeID: "second"})
+	second.receiveGossip(clusterGossip{NodeID: "first"})
+
+	// both members receive the same transactions, each one is delivered by a single member
+	var firstDeliveries, secondDeliveries int
+	for i := 0; i < 100; i++ {
+		tx := types.CreateNewTransactionNotification(types.NewBxTransaction(types.GenerateSHA256Hash(), 1, types.TFPaidTx, clock.Now()))
+		deliveries := 0
+		for _, member := range []*gossipCluster{first, second} {
+			if member.ShouldDeliver(tx) {
+				deliveries++
+			}
+		}
+		assert.Equal(t, 1, deliveries)
+		if first.ShouldDeliver(tx) {
+			firstDeliveries++
+		} else {
+			secondDeliveries++
+		}
+	}
+	assert.Greater(t, firstDeliveries, 0)
+	assert.Greater(t, secondDeliveries, 0)
+
+	// second member stops gossiping, first member delivers all the transactions
+	clock.IncTime(2 * time.Second)
+	first.removeDeadPeers()
+	for i := 0; i < 100; i++ {
+		tx := types.CreateNewTransactionNotification(types.NewBxTransaction(types.GenerateSHA256Hash(), 1, types.TFPaidTx, clock.Now()))
+		assert.True(t, first.ShouldDeliver(tx))
+	}
+}
+
+func TestCluster_FeedFailover(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	first := newTestCluster("first", &clock)
+	second := newTestCluster("second", &clock)
+
+	first.receiveGossip(clusterGossip{NodeID: "second"})
+	second.receiveGossip(clusterGossip{NodeID: "first"})
+	assert.Len(t, first.Members(), 2)
+
+	// every feed is owned by exactly one member
+	for _, feed := range []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed, types.OnBlockFeed} {
+		assert.NotEqual(t, first.OwnsFeed(feed), second.OwnsFeed(feed), feed)
+	}
+
+	// second member stops gossiping, first member takes over all the feeds
+	clock.IncTime(2 * time.Second)
+	first.removeDeadPeers()
+	assert.Len(t, first.Members(), 1)
+	for _, feed := range []types.FeedType{types.NewBlocksFeed, types.BDNBlocksFeed, types.TxReceiptsFeed, types.OnBlockFeed} {
+		assert.True(t, first.OwnsFeed(feed), feed)
+	}
+}
+
+func TestCluster_HandleGossipRequiresSignature(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	first := newTestCluster("first", &clock)
+	second := newTestCluster("second", &clock)
+
+	body, err := json.Marshal(clusterGossip{NodeID: "first"})
+	assert.NoError(t, err)
+
+	send := func(signature string) int {
+		req := httptest.NewRequest(http.MethodPost, clusterGossipPath, bytes.NewReader(body))
+		if signature != "" {
+			req.Header.Set(clusterSignatureHeader, signature)
+		}
+		rec := httptest.NewRecorder()
+		second.handleGossip(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(""))
+	assert.Equal(t, http.StatusUnauthorized, send("not hex"))
+
+	other := newGossipCluster(ClusterConfig{NodeID: "other", Secret: "other secret"}, &clock)
+	assert.Equal(t, http.StatusUnauthorized, send(other.sign(body)))
+	assert.Len(t, second.Members(), 1)
+
+	assert.Equal(t, http.StatusOK, send(first.sign(body)))
+	assert.Len(t, second.Members(), 2)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ContractAllowlist restricts the destination contracts of the transactions submitted by an account. The local
+// configuration of an account has priority over the allowed contracts of its account model.
+type ContractAllowlist struct {
+	lock     sync.RWMutex
+	local    map[types.AccountID]map[string]struct{}
+	accounts map[types.AccountID]map[string]struct{}
+}
+
+// NewContractAllowlist creates an allowlist with the locally configured contracts per account
+func NewContractAllowlist(local map[types.AccountID][]string) *ContractAllowlist {
+	allowlist := &ContractAllowlist{
+		local:    make(map[types.AccountID]map[string]struct{}),
+		accounts: make(map[types.AccountID]map[string]struct{}),
+	}
+	for accountID, contracts := range local {
+		allowlist.local[accountID] = contractSet(contracts)
+	}
+	return allowlist
+}
+
+// UpdateAccount refreshes the allowed contracts of the account from its account model
+func (allowlist *ContractAllowlist) UpdateAccount(account sdnmessage.Account) {
+	if allowlist == nil {
+		return
+	}
+
+	allowlist.lock.Lock()
+	defer allowlist.lock.Unlock()
+	if len(account.AllowedContracts) == 0 {
+		delete(allowlist.accounts, account.AccountID)
+		return
+	}
+	allowlist.accounts[account.AccountID] = contractSet(account.AllowedContracts)
+}
+
+// Validate returns an error if the account is restricted and the transaction is not sent to an allowed contract
+func (allowlist *ContractAllowlist) Validate(accountID types.AccountID, ethTx *ethtypes.Transaction) error {
+	if allowlist == nil {
+		return nil
+	}
+
+	allowlist.lock.RLock()
+	contracts, ok := allowlist.local[accountID]
+	if !ok {
+		contracts, ok = allowlist.accounts[accountID]
+	}
+	allowlist.lock.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	if ethTx.To() == nil {
+		return fmt.Errorf("account %v is not allowed to deploy contracts", accountID)
+	}
+	if _, allowed := contracts[normalizeAddress(ethTx.To().Hex())]; !allowed {
+		return fmt.Errorf("account %v is not allowed to send transactions to %v", accountID, ethTx.To().Hex())
+	}
+	return nil
+}
+
+func contractSet(contracts []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(contracts))
+	for _, contract := range contracts {
+		set[normalizeAddress(contract)] = struct{}{}
+	}
+	return set
+}
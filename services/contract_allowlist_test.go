@@ -0,0 +1,45 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractAllowlist(t *testing.T) {
+	allowed := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	other := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	txTo := func(to *common.Address) *ethtypes.Transaction {
+		return ethtypes.NewTx(&ethtypes.LegacyTx{To: to, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(1)})
+	}
+
+	allowlist := NewContractAllowlist(map[types.AccountID][]string{"local": {allowed.Hex()}})
+
+	assert.NoError(t, allowlist.Validate("local", txTo(&allowed)))
+	assert.Error(t, allowlist.Validate("local", txTo(&other)))
+	assert.Error(t, allowlist.Validate("local", txTo(nil)))
+
+	// accounts without restrictions can send anywhere
+	assert.NoError(t, allowlist.Validate("unrestricted", txTo(&other)))
+
+	account := sdnmessage.Account{AllowedContracts: []string{other.Hex()}}
+	account.AccountID = "remote"
+	allowlist.UpdateAccount(account)
+	assert.NoError(t, allowlist.Validate("remote", txTo(&other)))
+	assert.Error(t, allowlist.Validate("remote", txTo(&allowed)))
+
+	// local configuration has priority over the account model
+	account.AccountID = "local"
+	allowlist.UpdateAccount(account)
+	assert.Error(t, allowlist.Validate("local", txTo(&other)))
+
+	account.AccountID = "remote"
+	account.AllowedContracts = nil
+	allowlist.UpdateAccount(account)
+	assert.NoError(t, allowlist.Validate("remote", txTo(&allowed)))
+}
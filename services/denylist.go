@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DenylistRules are the denied addresses and tx hash patterns, as stored in the denylist file
+type DenylistRules struct {
+	Addresses      []string `json:"addresses"`
+	TxHashPatterns []string `json:"tx_hash_patterns"`
+}
+
+// Denylist rejects transactions sent from or to denied addresses, or with a hash matching a denied pattern.
+// Rules are loaded from a file that is reloaded when modified, and can be changed at runtime with the admin RPC.
+// Runtime changes are kept across reloads, they are applied over the rules of the file.
+type Denylist struct {
+	path      string
+	lock      sync.RWMutex
+	addresses map[string]struct{}
+	patterns  map[string]*regexp.Regexp
+	modTime   time.Time
+	clock     utils.Clock
+
+	addedAddresses   map[string]struct{}
+	addedPatterns    map[string]*regexp.Regexp
+	removedAddresses map[string]struct{}
+	removedPatterns  map[string]struct{}
+}
+
+// NewDenylist creates a denylist and loads the rules from the file if the path is set
+func NewDenylist(path string) (*Denylist, error) {
+	return newDenylist(path, utils.RealClock{})
+}
+
+func newDenylist(path string, clock utils.Clock) (*Denylist, error) {
+	denylist := &Denylist{
+		path:             path,
+		addresses:        make(map[string]struct{}),
+		patterns:         make(map[string]*regexp.Regexp),
+		clock:            clock,
+		addedAddresses:   make(map[string]struct{}),
+		addedPatterns:    make(map[string]*regexp.Regexp),
+		removedAddresses: make(map[string]struct{}),
+		removedPatterns:  make(map[string]struct{}),
+	}
+	if path != "" {
+		if err := denylist.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	return denylist, nil
+}
+
+// Reload replaces the rules with the content of the denylist file, then applies the runtime changes again
+func (denylist *Denylist) Reload() error {
+	if denylist.path == "" {
+		return fmt.Errorf("denylist file is not configured")
+	}
+
+	info, err := os.Stat(denylist.path)
+	if err != nil {
+		return fmt.Errorf("failed to read denylist file %v: %v", denylist.path, err)
+	}
+	content, err := os.ReadFile(denylist.path)
+	if err != nil {
+		return fmt.Errorf("failed to read denylist file %v: %v", denylist.path, err)
+	}
+
+	var rules DenylistRules
+	if err = json.Unmarshal(content, &rules); err != nil {
+		return fmt.Errorf("failed to decode denylist file %v: %v", denylist.path, err)
+	}
+	addresses, patterns, err := parseDenylistRules(rules)
+	if err != nil {
+		return err
+	}
+
+	denylist.lock.Lock()
+	for address := range denylist.addedAddresses {
+		addresses[address] = struct{}{}
+	}
+	for pattern, re := range denylist.addedPatterns {
+		patterns[pattern] = re
+	}
+	for address := range denylist.removedAddresses {
+		delete(addresses, address)
+	}
+	for pattern := range denylist.removedPatterns {
+		delete(patterns, pattern)
+	}
+	runtimeChanges := len(denylist.addedAddresses) + len(denylist.addedPatterns) + len(denylist.removedAddresses) + len(denylist.removedPatterns)
+	denylist.addresses = addresses
+	denylist.patterns = patterns
+	denylist.modTime = info.ModTime()
+	denylist.lock.Unlock()
+
+	log.Infof("loaded denylist from %v: %v addresses, %v tx hash patterns, including %v runtime changes", denylist.path, len(addresses), len(patterns), runtimeChanges)
+	return nil
+}
+
+// Watch reloads the denylist file when it is modified, until the context is done
+func (denylist *Denylist) Watch(ctx context.Context, interval time.Duration) {
+	if denylist == nil || denylist.path == "" {
+		return
+	}
+
+	ticker := denylist.clock.Ticker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			info, err := os.Stat(denylist.path)
+			if err != nil {
+				log.Warnf("failed to check denylist file %v: %v", denylist.path, err)
+				continue
+			}
+
+			denylist.lock.RLock()
+			modified := !info.ModTime().Equal(denylist.modTime)
+			denylist.lock.RUnlock()
+
+			if modified {
+				if err = denylist.Reload(); err != nil {
+					log.Errorf("failed to reload denylist, keeping the previous rules: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Add adds addresses and tx hash patterns to the denylist
+func (denylist *Denylist) Add(rules DenylistRules) error {
+	addresses, patterns, err := parseDenylistRules(rules)
+	if err != nil {
+		return err
+	}
+
+	denylist.lock.Lock()
+	defer denylist.lock.Unlock()
+	for address := range addresses {
+		denylist.addresses[address] = struct{}{}
+		denylist.addedAddresses[address] = struct{}{}
+		delete(denylist.removedAddresses, address)
+	}
+	for pattern, re := range patterns {
+		denylist.patterns[pattern] = re
+		denylist.addedPatterns[pattern] = re
+		delete(denylist.removedPatterns, pattern)
+	}
+	return nil
+}
+
+// Remove removes addresses and tx hash patterns from the denylist
+func (denylist *Denylist) Remove(rules DenylistRules) {
+	denylist.lock.Lock()
+	defer denylist.lock.Unlock()
+	for _, address := range rules.Addresses {
+		address = normalizeAddress(address)
+		delete(denylist.addresses, address)
+		delete(denylist.addedAddresses, address)
+		denylist.removedAddresses[address] = struct{}{}
+	}
+	for _, pattern := range rules.TxHashPatterns {
+		delete(denylist.patterns, pattern)
+		delete(denylist.addedPatterns, pattern)
+		denylist.removedPatterns[pattern] = struct{}{}
+	}
+}
+
+// Rules returns the current rules of the denylist
+func (denylist *Denylist) Rules() DenylistRules {
+	rules := DenylistRules{Addresses: []string{}, TxHashPatterns: []string{}}
+	if denylist == nil {
+		return rules
+	}
+
+	denylist.lock.RLock()
+	defer denylist.lock.RUnlock()
+	for address := range denylist.addresses {
+		rules.Addresses = append(rules.Addresses, address)
+	}
+	for pattern := range denylist.patterns {
+		rules.TxHashPatterns = append(rules.TxHashPatterns, pattern)
+	}
+	return rules
+}
+
+// Empty returns true if there are no rules, so callers can avoid decoding transactions
+func (denylist *Denylist) Empty() bool {
+	if denylist == nil {
+		return true
+	}
+
+	denylist.lock.RLock()
+	defer denylist.lock.RUnlock()
+	return len(denylist.addresses) == 0 && len(denylist.patterns) == 0
+}
+
+// Validate returns an error if the transaction is denied
+func (denylist *Denylist) Validate(ethTx *ethtypes.Transaction) error {
+	if denylist.Empty() {
+		return nil
+	}
+
+	hash := ethTx.Hash().Hex()
+	var from, to string
+	if sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(ethTx.ChainId()), ethTx); err == nil {
+		from = normalizeAddress(sender.Hex())
+	}
+	if ethTx.To() != nil {
+		to = normalizeAddress(ethTx.To().Hex())
+	}
+
+	denylist.lock.RLock()
+	defer denylist.lock.RUnlock()
+
+	if _, ok := denylist.addresses[from]; ok && from != "" {
+		return fmt.Errorf("transaction %v is denied: sender %v is in the denylist", hash, from)
+	}
+	if _, ok := denylist.addresses[to]; ok && to != "" {
+		return fmt.Errorf("transaction %v is denied: recipient %v is in the denylist", hash, to)
+	}
+	for pattern, re := range denylist.patterns {
+		if re.MatchString(hash) {
+			return fmt.Errorf("transaction %v is denied: hash matches denylist pattern %v", hash, pattern)
+		}
+	}
+	return nil
+}
+
+func parseDenylistRules(rules DenylistRules) (map[string]struct{}, map[string]*regexp.Regexp, error) {
+	addresses := make(map[string]struct{}, len(rules.Addresses))
+	for _, address := range rules.Addresses {
+		addresses[normalizeAddress(address)] = struct{}{}
+	}
+
+	patterns := make(map[string]*regexp.Regexp, len(rules.TxHashPatterns))
+	for _, pattern := range rules.TxHashPatterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid denylist tx hash pattern %v: %v", pattern, err)
+		}
+		patterns[pattern] = re
+	}
+	return addresses, patterns, nil
+}
+
+func normalizeAddress(address string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(address), "0x"))
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDenylist(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := crypto.PubkeyToAddress(privateKey.PublicKey)
+	recipient := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	chainID := big.NewInt(1)
+	tx, err := ethtypes.SignTx(ethtypes.NewTransaction(1, recipient, big.NewInt(1), 21000, big.NewInt(1), nil),
+		ethtypes.LatestSignerForChainID(chainID), privateKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "denylist.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"addresses": ["`+recipient.Hex()+`"]}`), 0o644))
+
+	denylist, err := newDenylist(path, &utils.MockClock{})
+	require.NoError(t, err)
+	assert.Error(t, denylist.Validate(tx))
+
+	denylist.Remove(DenylistRules{Addresses: []string{recipient.Hex()}})
+	assert.True(t, denylist.Empty())
+	assert.NoError(t, denylist.Validate(tx))
+
+	require.NoError(t, denylist.Add(DenylistRules{Addresses: []string{sender.Hex()}}))
+	assert.Error(t, denylist.Validate(tx))
+
+	// reload keeps the rules added and removed at runtime
+	require.NoError(t, os.WriteFile(path, []byte(`{"addresses": ["`+recipient.Hex()+`"], "tx_hash_patterns": ["^`+tx.Hash().Hex()[:6]+`"]}`), 0o644))
+	require.NoError(t, denylist.Reload())
+	assert.Equal(t, []string{normalizeAddress(sender.Hex())}, denylist.Rules().Addresses)
+	assert.Len(t, denylist.Rules().TxHashPatterns, 1)
+
+	denylist.Remove(DenylistRules{Addresses: []string{sender.Hex()}})
+	require.NoError(t, denylist.Reload())
+	assert.Empty(t, denylist.Rules().Addresses)
+	assert.Error(t, denylist.Validate(tx))
+
+	assert.Error(t, denylist.Add(DenylistRules{TxHashPatterns: []string{"("}}))
+
+	var disabled *Denylist
+	assert.NoError(t, disabled.Validate(tx))
+}
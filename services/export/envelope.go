@@ -0,0 +1,175 @@
+package export
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+)
+
+const dataKeyLen = 32
+
+// KeyProvider wraps the data keys of the envelopes with the key of an account. It is implemented by a client of
+// the KMS holding the account keys, or by a Keyring of local keys.
+type KeyProvider interface {
+	// WrapKey encrypts the data key with the key of the account, returning the ID of the key used
+	WrapKey(accountID types.AccountID, dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key wrapped with the key keyID of the account
+	UnwrapKey(accountID types.AccountID, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Envelope is a payload encrypted with a random data key, stored along with the data key encrypted with the key of
+// the account. The payload can only be decrypted with the key of the account it was exported for.
+type Envelope struct {
+	AccountID  types.AccountID `json:"account_id"`
+	KeyID      string          `json:"key_id"`
+	WrappedKey []byte          `json:"wrapped_key"`
+	Nonce      []byte          `json:"nonce"`
+	Ciphertext []byte          `json:"ciphertext"`
+}
+
+// Seal encrypts the payload of the account with a new data key wrapped by the provider
+func Seal(provider KeyProvider, accountID types.AccountID, payload []byte) (*Envelope, error) {
+	dataKey := make([]byte, dataKeyLen)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := provider.WrapKey(accountID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, ciphertext, err := encrypt(dataKey, payload, []byte(accountID))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		AccountID:  accountID,
+		KeyID:      keyID,
+		WrappedKey: wrapped,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Open decrypts the payload of the envelope with the data key unwrapped by the provider
+func Open(provider KeyProvider, envelope *Envelope) ([]byte, error) {
+	dataKey, err := provider.UnwrapKey(envelope.AccountID, envelope.KeyID, envelope.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(dataKey, envelope.Nonce, envelope.Ciphertext, []byte(envelope.AccountID))
+}
+
+// encrypt seals the plaintext with AES-GCM, the additional data binds the ciphertext to its account
+func encrypt(key, plaintext, additionalData []byte) (nonce, ciphertext []byte, err error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce = make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return nonce, aead.Seal(nil, nonce, plaintext, additionalData), nil
+}
+
+func decrypt(key, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce size")
+	}
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Keyring is a KeyProvider holding an AES-256 key per account
+type Keyring struct {
+	keys map[types.AccountID][]byte
+}
+
+// NewKeyring creates a Keyring from the keys of the accounts
+func NewKeyring(keys map[types.AccountID][]byte) (*Keyring, error) {
+	for accountID, key := range keys {
+		if len(key) != dataKeyLen {
+			return nil, fmt.Errorf("key of account %v must be %v bytes long, got %v", accountID, dataKeyLen, len(key))
+		}
+	}
+	return &Keyring{keys: keys}, nil
+}
+
+// LoadKeyring reads a Keyring from a JSON file mapping account IDs to hex encoded keys
+func LoadKeyring(path string) (*Keyring, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open export keys file: %v", err)
+	}
+	var hexKeys map[types.AccountID]string
+	if err = json.Unmarshal(contents, &hexKeys); err != nil {
+		return nil, fmt.Errorf("failed to decode export keys file: %v", err)
+	}
+
+	keys := make(map[types.AccountID][]byte, len(hexKeys))
+	for accountID, hexKey := range hexKeys {
+		if keys[accountID], err = hex.DecodeString(hexKey); err != nil {
+			return nil, fmt.Errorf("invalid key of account %v in export keys file: %v", accountID, err)
+		}
+	}
+	return NewKeyring(keys)
+}
+
+// keyID identifies the key without revealing it, so envelopes record which key to use after a key rotation
+func keyID(key []byte) string {
+	hash := sha256.Sum256(key)
+	return "local:" + hex.EncodeToString(hash[:8])
+}
+
+// WrapKey encrypts the data key with the key of the account
+func (k *Keyring) WrapKey(accountID types.AccountID, dataKey []byte) (string, []byte, error) {
+	key, ok := k.keys[accountID]
+	if !ok {
+		return "", nil, fmt.Errorf("no export key for account %v", accountID)
+	}
+	nonce, ciphertext, err := encrypt(key, dataKey, []byte(accountID))
+	if err != nil {
+		return "", nil, err
+	}
+	return keyID(key), append(nonce, ciphertext...), nil
+}
+
+// UnwrapKey decrypts a data key wrapped with the key of the account
+func (k *Keyring) UnwrapKey(accountID types.AccountID, id string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[accountID]
+	if !ok {
+		return nil, fmt.Errorf("no export key for account %v", accountID)
+	}
+	if keyID(key) != id {
+		return nil, fmt.Errorf("data key of account %v was wrapped with key %v, which is not in the keyring", accountID, id)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key")
+	}
+	return decrypt(key, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(accountID))
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T) *Keyring {
+	keyring, err := NewKeyring(map[types.AccountID][]byte{
+		"account-a": bytes.Repeat([]byte{1}, dataKeyLen),
+		"account-b": bytes.Repeat([]byte{2}, dataKeyLen),
+	})
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestSealOpen(t *testing.T) {
+	keyring := testKeyring(t)
+	payload := []byte(`{"tx_hash":"0x01"}`)
+
+	envelope, err := Seal(keyring, "account-a", payload)
+	require.NoError(t, err)
+	assert.NotContains(t, string(envelope.Ciphertext), "tx_hash")
+
+	opened, err := Open(keyring, envelope)
+	require.NoError(t, err)
+	assert.Equal(t, payload, opened)
+
+	// the envelope of an account cannot be opened as another account
+	envelope.AccountID = "account-b"
+	_, err = Open(keyring, envelope)
+	assert.Error(t, err)
+
+	_, err = Seal(keyring, "account-c", payload)
+	assert.Error(t, err)
+}
+
+func TestSealOpen_RotatedKey(t *testing.T) {
+	envelope, err := Seal(testKeyring(t), "account-a", []byte("payload"))
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring(map[types.AccountID][]byte{"account-a": bytes.Repeat([]byte{3}, dataKeyLen)})
+	require.NoError(t, err)
+	_, err = Open(rotated, envelope)
+	assert.ErrorContains(t, err, "not in the keyring")
+}
+
+func TestLoadKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	key := hex.EncodeToString(bytes.Repeat([]byte{1}, dataKeyLen))
+	require.NoError(t, os.WriteFile(path, []byte(`{"account-a": "`+key+`"}`), 0o600))
+
+	keyring, err := LoadKeyring(path)
+	require.NoError(t, err)
+	_, _, err = keyring.WrapKey("account-a", make([]byte, dataKeyLen))
+	assert.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"account-a": "0102"}`), 0o600))
+	_, err = LoadKeyring(path)
+	assert.Error(t, err)
+}
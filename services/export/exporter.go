@@ -0,0 +1,184 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	defaultBufferSize  = 10000
+	droppedLogInterval = time.Minute
+)
+
+// Config of the feed archives
+type Config struct {
+	// Dir is the directory of the archives, one file per account. Export is disabled if it is empty.
+	Dir string
+	// Keys encrypt the payloads of each account in an Envelope. Payloads are exported in plaintext if it is nil.
+	Keys KeyProvider
+	// BufferSize is the number of notifications waiting to be written, notifications are dropped once it is full
+	BufferSize int
+	// Published archives every notification published by the gateway under its own account. It is the sink shared by
+	// the gateways of a cluster, each notification is archived by a single gateway.
+	Published bool
+}
+
+// Record is a line of an archive, holding either the payload or its Envelope if the archive is encrypted
+type Record struct {
+	Time     time.Time       `json:"time"`
+	Feed     types.FeedType  `json:"feed"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Envelope *Envelope       `json:"envelope,omitempty"`
+}
+
+// Exporter archives the notifications delivered to the subscriptions of each account
+type Exporter interface {
+	// Export queues the payload delivered to the account, without blocking the caller
+	Export(accountID types.AccountID, feed types.FeedType, payload interface{})
+}
+
+type noExport struct {
+}
+
+func (noExport) Export(types.AccountID, types.FeedType, interface{}) {
+}
+
+type exportRequest struct {
+	time      time.Time
+	accountID types.AccountID
+	feed      types.FeedType
+	payload   interface{}
+}
+
+// fileExporter appends the records of each account to its own file in the archive directory
+type fileExporter struct {
+	cfg      Config
+	clock    utils.Clock
+	requests chan exportRequest
+	files    map[types.AccountID]*os.File
+	// accounts without export key are reported once, their payloads are never written in plaintext
+	missingKeys map[types.AccountID]struct{}
+	dropped     atomic.Uint64
+	log         *log.Entry
+}
+
+// NewExporter creates an Exporter writing until the context is done, export is disabled if cfg.Dir is empty
+func NewExporter(ctx context.Context, cfg Config, clock utils.Clock) Exporter {
+	if cfg.Dir == "" {
+		return noExport{}
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+
+	e := &fileExporter{
+		cfg:         cfg,
+		clock:       clock,
+		requests:    make(chan exportRequest, cfg.BufferSize),
+		files:       make(map[types.AccountID]*os.File),
+		missingKeys: make(map[types.AccountID]struct{}),
+		log:         log.WithField("component", "feedExporter"),
+	}
+	go e.run(ctx)
+	return e
+}
+
+// Export queues the payload to be written by the exporter goroutine, dropping it if the queue is full
+func (e *fileExporter) Export(accountID types.AccountID, feed types.FeedType, payload interface{}) {
+	select {
+	case e.requests <- exportRequest{time: e.clock.Now(), accountID: accountID, feed: feed, payload: payload}:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+func (e *fileExporter) run(ctx context.Context) {
+	ticker := time.NewTicker(droppedLogInterval)
+	defer ticker.Stop()
+	defer e.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-e.requests:
+			e.write(req)
+		case <-ticker.C:
+			if dropped := e.dropped.Swap(0); dropped > 0 {
+				e.log.Warnf("dropped %v notifications in the last %v, the export queue was full", dropped, droppedLogInterval)
+			}
+		}
+	}
+}
+
+func (e *fileExporter) write(req exportRequest) {
+	payload, err := json.Marshal(req.payload)
+	if err != nil {
+		e.log.Errorf("failed to marshal %v notification of account %v: %v", req.feed, req.accountID, err)
+		return
+	}
+
+	record := Record{Time: req.time, Feed: req.feed}
+	if e.cfg.Keys != nil {
+		record.Envelope, err = Seal(e.cfg.Keys, req.accountID, payload)
+		if err != nil {
+			if _, ok := e.missingKeys[req.accountID]; !ok {
+				e.missingKeys[req.accountID] = struct{}{}
+				e.log.Errorf("not exporting notifications of account %v, failed to encrypt them: %v", req.accountID, err)
+			}
+			return
+		}
+	} else {
+		record.Payload = payload
+	}
+
+	file, err := e.file(req.accountID)
+	if err != nil {
+		e.log.Errorf("failed to open archive of account %v: %v", req.accountID, err)
+		return
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		e.log.Errorf("failed to marshal archive record of account %v: %v", req.accountID, err)
+		return
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		e.log.Errorf("failed to write archive of account %v: %v", req.accountID, err)
+	}
+}
+
+func (e *fileExporter) file(accountID types.AccountID) (*os.File, error) {
+	if file, ok := e.files[accountID]; ok {
+		return file, nil
+	}
+	// account IDs are used as file names, they cannot point outside the archive directory
+	if accountID == "" || filepath.Base(string(accountID)) != string(accountID) {
+		return nil, fmt.Errorf("invalid account ID %q", accountID)
+	}
+	if err := os.MkdirAll(e.cfg.Dir, 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(e.cfg.Dir, string(accountID)+".jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	e.files[accountID] = file
+	return file, nil
+}
+
+func (e *fileExporter) close() {
+	for accountID, file := range e.files {
+		if err := file.Close(); err != nil {
+			e.log.Errorf("failed to close archive of account %v: %v", accountID, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readArchive(t *testing.T, path string, count int) []Record {
+	var records []Record
+	require.Eventually(t, func() bool {
+		file, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		defer file.Close()
+
+		records = records[:0]
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record Record
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		return len(records) == count
+	}, time.Second, time.Millisecond)
+	return records
+}
+
+func TestExporter_Plaintext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+
+	exporter := NewExporter(ctx, Config{Dir: dir}, clock)
+	// account IDs cannot point outside the archive directory
+	exporter.Export("../account-c", types.NewBlocksFeed, map[string]string{"hash": "0x03"})
+	exporter.Export("account-a", types.NewTxsFeed, map[string]string{"tx_hash": "0x01"})
+	exporter.Export("account-b", types.NewBlocksFeed, map[string]string{"hash": "0x02"})
+
+	records := readArchive(t, filepath.Join(dir, "account-a.jsonl"), 1)
+	assert.Equal(t, types.NewTxsFeed, records[0].Feed)
+	assert.JSONEq(t, `{"tx_hash": "0x01"}`, string(records[0].Payload))
+	assert.Nil(t, records[0].Envelope)
+	assert.True(t, clock.Now().Equal(records[0].Time))
+
+	records = readArchive(t, filepath.Join(dir, "account-b.jsonl"), 1)
+	assert.Equal(t, types.NewBlocksFeed, records[0].Feed)
+
+	_, err := os.Stat(filepath.Join(filepath.Dir(dir), "account-c.jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExporter_Encrypted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	keyring := testKeyring(t)
+
+	exporter := NewExporter(ctx, Config{Dir: dir, Keys: keyring}, utils.RealClock{})
+	// an account without key is not exported in plaintext
+	exporter.Export("account-c", types.NewTxsFeed, map[string]string{"tx_hash": "0x03"})
+	exporter.Export("account-a", types.NewTxsFeed, map[string]string{"tx_hash": "0x01"})
+
+	records := readArchive(t, filepath.Join(dir, "account-a.jsonl"), 1)
+	assert.Empty(t, records[0].Payload)
+	require.NotNil(t, records[0].Envelope)
+
+	payload, err := Open(keyring, records[0].Envelope)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tx_hash": "0x01"}`, string(payload))
+
+	_, err = os.Stat(filepath.Join(dir, "account-c.jsonl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExporter_Disabled(t *testing.T) {
+	assert.Equal(t, noExport{}, NewExporter(context.Background(), Config{}, utils.RealClock{}))
+}
@@ -0,0 +1,222 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	// MirrorNotification is the kind of the records of the notifications delivered to the account
+	MirrorNotification = "notification"
+	// MirrorSubmission is the kind of the records of the txs and bundles submitted by the account
+	MirrorSubmission = "submission"
+)
+
+// MirrorConfig of the shadow sink duplicating the traffic of a single account, to troubleshoot the discrepancies
+// reported by the customer
+type MirrorConfig struct {
+	// AccountID is the account whose traffic is mirrored. Mirroring is disabled if it is empty.
+	AccountID types.AccountID
+	// File is the JSON lines file the mirrored records are appended to
+	File string
+	// Redact lists the fields removed from the mirrored records at any depth, e.g. from or remote_address
+	Redact []string
+	// HashRedacted replaces the redacted fields with their SHA-256 instead of removing them, so the records can
+	// still be correlated
+	HashRedacted bool
+	// BufferSize is the number of records waiting to be written, records are dropped once it is full
+	BufferSize int
+}
+
+// MirrorRecord is a line of the mirror file
+type MirrorRecord struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	// Feed of a notification
+	Feed types.FeedType `json:"feed,omitempty"`
+	// Method of a submission, e.g. blxr_tx
+	Method        string          `json:"method,omitempty"`
+	RemoteAddress string          `json:"remote_address,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Mirror duplicates the notifications and the submissions of the mirrored account. It is an Exporter of the
+// notifications, so it is chained with the archive by Tee.
+type Mirror interface {
+	Exporter
+	// Submit queues the payload submitted by the account, without blocking the caller
+	Submit(accountID types.AccountID, method string, remoteAddress string, payload interface{})
+}
+
+type noMirror struct {
+	noExport
+}
+
+func (noMirror) Submit(types.AccountID, string, string, interface{}) {
+}
+
+// fileMirror appends the redacted records of the mirrored account to the mirror file
+type fileMirror struct {
+	cfg     MirrorConfig
+	clock   utils.Clock
+	redact  map[string]struct{}
+	records chan MirrorRecord
+	dropped atomic.Uint64
+	log     *log.Entry
+}
+
+// NewMirror creates a Mirror writing until the context is done, mirroring is disabled if cfg.AccountID is empty
+func NewMirror(ctx context.Context, cfg MirrorConfig, clock utils.Clock) (Mirror, error) {
+	if cfg.AccountID == "" {
+		return noMirror{}, nil
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.File), 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &fileMirror{
+		cfg:     cfg,
+		clock:   clock,
+		redact:  make(map[string]struct{}, len(cfg.Redact)),
+		records: make(chan MirrorRecord, cfg.BufferSize),
+		log:     log.WithField("component", "trafficMirror"),
+	}
+	for _, field := range cfg.Redact {
+		m.redact[field] = struct{}{}
+	}
+	go m.run(ctx, file)
+	return m, nil
+}
+
+// Export mirrors the notification if it is delivered to the mirrored account
+func (m *fileMirror) Export(accountID types.AccountID, feed types.FeedType, payload interface{}) {
+	if accountID != m.cfg.AccountID {
+		return
+	}
+	m.queue(MirrorRecord{Kind: MirrorNotification, Feed: feed}, payload)
+}
+
+// Submit mirrors the submission if it is sent by the mirrored account
+func (m *fileMirror) Submit(accountID types.AccountID, method string, remoteAddress string, payload interface{}) {
+	if accountID != m.cfg.AccountID {
+		return
+	}
+	m.queue(MirrorRecord{Kind: MirrorSubmission, Method: method, RemoteAddress: remoteAddress}, payload)
+}
+
+func (m *fileMirror) queue(record MirrorRecord, payload interface{}) {
+	var err error
+	// marshaled before queueing, the caller can modify the payload once it returns
+	record.Payload, err = json.Marshal(payload)
+	if err != nil {
+		m.log.Errorf("failed to marshal mirrored %v %v%v: %v", record.Kind, record.Feed, record.Method, err)
+		return
+	}
+	record.Time = m.clock.Now()
+
+	select {
+	case m.records <- record:
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+func (m *fileMirror) run(ctx context.Context, file *os.File) {
+	ticker := time.NewTicker(droppedLogInterval)
+	defer ticker.Stop()
+	defer func() {
+		if err := file.Close(); err != nil {
+			m.log.Errorf("failed to close mirror file: %v", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case record := <-m.records:
+			line, err := m.marshal(record)
+			if err != nil {
+				m.log.Errorf("failed to marshal mirror record: %v", err)
+				continue
+			}
+			if _, err = file.Write(append(line, '\n')); err != nil {
+				m.log.Errorf("failed to write mirror file: %v", err)
+			}
+		case <-ticker.C:
+			if dropped := m.dropped.Swap(0); dropped > 0 {
+				m.log.Warnf("dropped %v mirrored records of account %v in the last %v, the mirror queue was full", dropped, m.cfg.AccountID, droppedLogInterval)
+			}
+		}
+	}
+}
+
+// marshal encodes the record with its redacted fields removed or hashed
+func (m *fileMirror) marshal(record MirrorRecord) ([]byte, error) {
+	if len(m.redact) == 0 {
+		return json.Marshal(record)
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var fields interface{}
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(m.redacted(fields))
+}
+
+func (m *fileMirror) redacted(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if _, ok := m.redact[key]; !ok {
+				v[key] = m.redacted(field)
+				continue
+			}
+			if !m.cfg.HashRedacted {
+				delete(v, key)
+				continue
+			}
+			encoded, _ := json.Marshal(field)
+			hash := sha256.Sum256(encoded)
+			v[key] = hex.EncodeToString(hash[:])
+		}
+	case []interface{}:
+		for i, field := range v {
+			v[i] = m.redacted(field)
+		}
+	}
+	return value
+}
+
+type teeExporter []Exporter
+
+func (t teeExporter) Export(accountID types.AccountID, feed types.FeedType, payload interface{}) {
+	for _, exporter := range t {
+		exporter.Export(accountID, feed, payload)
+	}
+}
+
+// Tee returns an Exporter passing each notification to all the exporters
+func Tee(exporters ...Exporter) Exporter {
+	return teeExporter(exporters)
+}
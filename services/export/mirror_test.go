@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readMirror(t *testing.T, path string, count int) []map[string]interface{} {
+	var records []map[string]interface{}
+	require.Eventually(t, func() bool {
+		file, err := os.Open(path)
+		if err != nil {
+			return false
+		}
+		defer file.Close()
+
+		records = records[:0]
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			records = append(records, record)
+		}
+		return len(records) == count
+	}, time.Second, time.Millisecond)
+	return records
+}
+
+func TestMirror(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "mirror", "account-a.jsonl")
+
+	mirror, err := NewMirror(ctx, MirrorConfig{AccountID: "account-a", File: path, Redact: []string{"from", "remote_address"}}, utils.RealClock{})
+	require.NoError(t, err)
+
+	// the traffic of the other accounts is not mirrored
+	mirror.Export("account-b", types.NewTxsFeed, map[string]string{"tx_hash": "0x02"})
+	mirror.Submit("account-b", "blxr_tx", "10.0.0.2:4000", map[string]string{"transaction": "f802"})
+	mirror.Export("account-a", types.NewTxsFeed, map[string]interface{}{"tx_hash": "0x01", "tx_contents": map[string]string{"from": "0xaa", "to": "0xbb"}})
+	mirror.Submit("account-a", "blxr_tx", "10.0.0.1:4000", map[string]string{"transaction": "f801"})
+
+	records := readMirror(t, path, 2)
+	assert.Equal(t, MirrorNotification, records[0]["kind"])
+	assert.Equal(t, string(types.NewTxsFeed), records[0]["feed"])
+	assert.Equal(t, map[string]interface{}{"tx_hash": "0x01", "tx_contents": map[string]interface{}{"to": "0xbb"}}, records[0]["payload"])
+
+	assert.Equal(t, MirrorSubmission, records[1]["kind"])
+	assert.Equal(t, "blxr_tx", records[1]["method"])
+	assert.NotContains(t, records[1], "remote_address")
+	assert.Equal(t, map[string]interface{}{"transaction": "f801"}, records[1]["payload"])
+}
+
+func TestMirror_HashRedacted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "account-a.jsonl")
+
+	mirror, err := NewMirror(ctx, MirrorConfig{AccountID: "account-a", File: path, Redact: []string{"from"}, HashRedacted: true}, utils.RealClock{})
+	require.NoError(t, err)
+
+	// the same value is always hashed the same way, so the records can be correlated
+	Tee(noExport{}, mirror).Export("account-a", types.NewTxsFeed, map[string]string{"from": "0xaa"})
+	mirror.Export("account-a", types.PendingTxsFeed, map[string]string{"from": "0xaa"})
+
+	records := readMirror(t, path, 2)
+	from := records[0]["payload"].(map[string]interface{})["from"]
+	assert.Len(t, from, 64)
+	assert.NotEqual(t, "0xaa", from)
+	assert.Equal(t, from, records[1]["payload"].(map[string]interface{})["from"])
+}
+
+func TestMirror_Disabled(t *testing.T) {
+	mirror, err := NewMirror(context.Background(), MirrorConfig{}, utils.RealClock{})
+	require.NoError(t, err)
+	assert.Equal(t, noMirror{}, mirror)
+}
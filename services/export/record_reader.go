@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxRecordSize bounds a line of a capture, the blocks with their txs are the largest records
+const maxRecordSize = 64 * 1024 * 1024
+
+// RecordReader reads the records of an archive or of a JSON file sink capture, gzipped if its name ends with .gz
+type RecordReader struct {
+	file    *os.File
+	gzip    *gzip.Reader
+	scanner *bufio.Scanner
+	line    int
+}
+
+// OpenRecords opens the records of the file
+func OpenRecords(path string) (*RecordReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &RecordReader{file: file}
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		if r.gzip, err = gzip.NewReader(file); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to decompress %v: %v", path, err)
+		}
+		reader = r.gzip
+	}
+	r.scanner = bufio.NewScanner(reader)
+	r.scanner.Buffer(make([]byte, 0, 64*1024), maxRecordSize)
+	return r, nil
+}
+
+// Next returns the next record, io.EOF once all the records are read. The encrypted records cannot be read.
+func (r *RecordReader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return Record{}, fmt.Errorf("invalid record at line %v: %v", r.line, err)
+		}
+		if record.Envelope != nil {
+			return Record{}, fmt.Errorf("record at line %v is encrypted", r.line)
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// Close closes the file
+func (r *RecordReader) Close() error {
+	if r.gzip != nil {
+		_ = r.gzip.Close()
+	}
+	return r.file.Close()
+}
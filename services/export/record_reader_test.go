@@ -0,0 +1,62 @@
+package export
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRecords = `{"time":"2023-10-16T12:00:00Z","feed":"newTxs","payload":{"txHash":"0x01"}}
+
+{"time":"2023-10-16T12:00:01Z","feed":"newBlocks","payload":{"hash":"0x02"}}
+`
+
+func TestRecordReader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(testRecords), 0o600))
+	require.NoError(t, compressFile(path))
+	require.NoError(t, os.WriteFile(path, []byte(testRecords), 0o600))
+
+	for _, file := range []string{path, path + ".gz"} {
+		reader, err := OpenRecords(file)
+		require.NoError(t, err)
+
+		record, err := reader.Next()
+		require.NoError(t, err)
+		assert.Equal(t, types.NewTxsFeed, record.Feed)
+		assert.JSONEq(t, `{"txHash":"0x01"}`, string(record.Payload))
+
+		// the empty lines are skipped
+		record, err = reader.Next()
+		require.NoError(t, err)
+		assert.Equal(t, types.NewBlocksFeed, record.Feed)
+		assert.Equal(t, 1, record.Time.Second())
+
+		_, err = reader.Next()
+		assert.Equal(t, io.EOF, err)
+		require.NoError(t, reader.Close())
+	}
+}
+
+func TestRecordReader_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	encrypted := filepath.Join(dir, "encrypted.jsonl")
+	require.NoError(t, os.WriteFile(encrypted, []byte(`{"time":"2023-10-16T12:00:00Z","feed":"newTxs","envelope":{"key_id":"local:00"}}`+"\n"), 0o600))
+	reader, err := OpenRecords(encrypted)
+	require.NoError(t, err)
+	_, err = reader.Next()
+	assert.ErrorContains(t, err, "encrypted")
+	require.NoError(t, reader.Close())
+
+	notGzipped := filepath.Join(dir, "capture.jsonl.gz")
+	require.NoError(t, os.WriteFile(notGzipped, []byte(testRecords), 0o600))
+	_, err = OpenRecords(notGzipped)
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package export
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const rotatedTimeLayout = "20060102T150405.000"
+
+// RotationConfig of a RotatingFile
+type RotationConfig struct {
+	// MaxSize is the size in bytes the file is rotated at, 0 disables the rotation by size
+	MaxSize int64
+	// MaxAge is the age the file is rotated at, 0 disables the rotation by time
+	MaxAge time.Duration
+	// Compress gzips the rotated files in the background
+	Compress bool
+}
+
+// RotatingFile appends to the file at its path, which is renamed with the time of the rotation once it grows over
+// MaxSize or gets older than MaxAge, e.g. capture.jsonl becomes capture-20231016T120000.000.jsonl. The rotation is
+// checked before each write, so a record is never split across two files. It is not safe for concurrent use.
+type RotatingFile struct {
+	path        string
+	cfg         RotationConfig
+	clock       utils.Clock
+	file        *os.File
+	size        int64
+	opened      time.Time
+	compressing sync.WaitGroup
+	log         *log.Entry
+}
+
+// OpenRotatingFile opens the file at path for appending, creating its directory if needed
+func OpenRotatingFile(path string, cfg RotationConfig, clock utils.Clock) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:  path,
+		cfg:   cfg,
+		clock: clock,
+		log:   log.WithField("component", "rotatingFile"),
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends the record to the file, rotating it first if it is due
+func (f *RotatingFile) Write(record []byte) (int, error) {
+	if f.due(len(record)) {
+		if err := f.rotate(); err != nil {
+			return 0, fmt.Errorf("failed to rotate %v: %v", f.path, err)
+		}
+	}
+	n, err := f.file.Write(record)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file, waiting for the rotated files to be compressed
+func (f *RotatingFile) Close() error {
+	err := f.file.Close()
+	f.compressing.Wait()
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.clock.Now()
+	return nil
+}
+
+// due returns true if the file is rotated before the record is written, an empty file is never rotated
+func (f *RotatingFile) due(recordSize int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.cfg.MaxSize > 0 && f.size+int64(recordSize) > f.cfg.MaxSize {
+		return true
+	}
+	return f.cfg.MaxAge > 0 && f.clock.Now().Sub(f.opened) >= f.cfg.MaxAge
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	rotated := f.rotatedPath()
+	if err := os.Rename(f.path, rotated); err != nil {
+		// the records keep being appended to the file, which is rotated by the next write
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if f.cfg.Compress {
+		f.compressing.Add(1)
+		go func() {
+			defer f.compressing.Done()
+			if err := compressFile(rotated); err != nil {
+				f.log.Errorf("failed to compress rotated file %v: %v", rotated, err)
+			}
+		}()
+	}
+	return f.open()
+}
+
+// rotatedPath inserts the time of the rotation before the extension of the path
+func (f *RotatingFile) rotatedPath() string {
+	ext := filepath.Ext(f.path)
+	return fmt.Sprintf("%v-%v%v", strings.TrimSuffix(f.path, ext), f.clock.Now().UTC().Format(rotatedTimeLayout), ext)
+}
+
+// compressFile replaces the file with its gzip, e.g. capture.jsonl with capture.jsonl.gz
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.jsonl")
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC))
+
+	file, err := OpenRotatingFile(path, RotationConfig{MaxSize: 10, MaxAge: time.Minute}, clock)
+	require.NoError(t, err)
+
+	// rotated by size, a record is never split
+	_, err = file.Write([]byte("record-1\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("record-2\n"))
+	require.NoError(t, err)
+	rotated, err := os.ReadFile(filepath.Join(dir, "capture-20231016T120000.000.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, "record-1\n", string(rotated))
+
+	// rotated by age
+	clock.IncTime(time.Minute)
+	_, err = file.Write([]byte("3\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	rotated, err = os.ReadFile(filepath.Join(dir, "capture-20231016T120100.000.jsonl"))
+	require.NoError(t, err)
+	assert.Equal(t, "record-2\n", string(rotated))
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", string(current))
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "capture.jsonl")
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Date(2023, 10, 16, 12, 0, 0, 0, time.UTC))
+
+	file, err := OpenRotatingFile(path, RotationConfig{MaxSize: 10, Compress: true}, clock)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("record-1\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("record-2\n"))
+	require.NoError(t, err)
+	// waits for the rotated file to be compressed
+	require.NoError(t, file.Close())
+
+	rotated := filepath.Join(dir, "capture-20231016T120000.000.jsonl")
+	_, err = os.Stat(rotated)
+	assert.True(t, os.IsNotExist(err))
+
+	compressed, err := os.Open(rotated + ".gz")
+	require.NoError(t, err)
+	defer compressed.Close()
+	zr, err := gzip.NewReader(compressed)
+	require.NoError(t, err)
+	contents, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "record-1\n", string(contents))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+)
+
+// FeatureFlag is the name of a rollout flag, enabling a feature on a subset of the gateways without a release
+type FeatureFlag string
+
+// FeatureFlag types enumeration
+const (
+	// FeatureTxBatching enables blxr_batch_tx submissions
+	FeatureTxBatching FeatureFlag = "tx_batching"
+	// FeatureCombinedFeed enables combined subscriptions
+	FeatureCombinedFeed FeatureFlag = "combined_feed"
+	// FeatureBDNTxValidation enables the validation of BDN transactions before they are sent to the node
+	FeatureBDNTxValidation FeatureFlag = "bdn_tx_validation"
+)
+
+// defaultFeatureFlags are the values of the flags which are neither set by the SDN nor overridden. Features which are
+// already released default to enabled so the SDN can turn them off, new features should default to disabled until
+// they are rolled out.
+var defaultFeatureFlags = map[FeatureFlag]bool{
+	FeatureTxBatching:      true,
+	FeatureCombinedFeed:    true,
+	FeatureBDNTxValidation: true,
+}
+
+// FeatureFlagSource is where the value of a feature flag comes from
+type FeatureFlagSource string
+
+// FeatureFlagSource types enumeration
+const (
+	FeatureFlagDefault  FeatureFlagSource = "default"
+	FeatureFlagSDN      FeatureFlagSource = "sdn"
+	FeatureFlagOverride FeatureFlagSource = "override"
+)
+
+// FeatureFlagState is the value of a feature flag and where it comes from
+type FeatureFlagState struct {
+	Enabled bool              `json:"enabled"`
+	Source  FeatureFlagSource `json:"source"`
+}
+
+// FeatureFlags are evaluated where the features are handled. The values are received from the SDN and can be
+// overridden locally, the local overrides take precedence.
+type FeatureFlags struct {
+	lock      sync.RWMutex
+	remote    map[FeatureFlag]bool
+	overrides map[FeatureFlag]bool
+}
+
+// NewFeatureFlags creates the feature flags with the local overrides
+func NewFeatureFlags(overrides map[string]bool) (*FeatureFlags, error) {
+	flags := &FeatureFlags{
+		remote:    make(map[FeatureFlag]bool),
+		overrides: make(map[FeatureFlag]bool),
+	}
+	for name, enabled := range overrides {
+		flag := FeatureFlag(name)
+		if _, ok := defaultFeatureFlags[flag]; !ok {
+			return nil, fmt.Errorf("unknown feature flag %v, supported flags are %v", name, FeatureFlagNames())
+		}
+		flags.overrides[flag] = enabled
+	}
+	return flags, nil
+}
+
+// FeatureFlagNames returns the names of the supported feature flags
+func FeatureFlagNames() []string {
+	names := make([]string, 0, len(defaultFeatureFlags))
+	for flag := range defaultFeatureFlags {
+		names = append(names, string(flag))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UpdateRemote replaces the values received from the SDN, unknown flags are ignored since the SDN may roll out
+// features of newer gateway versions
+func (f *FeatureFlags) UpdateRemote(remote map[string]bool) {
+	if f == nil {
+		return
+	}
+
+	flags := make(map[FeatureFlag]bool, len(remote))
+	for name, enabled := range remote {
+		flag := FeatureFlag(name)
+		if _, ok := defaultFeatureFlags[flag]; !ok {
+			log.Debugf("ignoring unknown feature flag %v received from the SDN", name)
+			continue
+		}
+		flags[flag] = enabled
+	}
+
+	f.lock.Lock()
+	f.remote = flags
+	f.lock.Unlock()
+}
+
+// Enabled returns true if the feature is enabled. A nil FeatureFlags uses the default values.
+func (f *FeatureFlags) Enabled(flag FeatureFlag) bool {
+	return f.State(flag).Enabled
+}
+
+// State returns the value of the feature flag and where it comes from
+func (f *FeatureFlags) State(flag FeatureFlag) FeatureFlagState {
+	if f != nil {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+
+		if enabled, ok := f.overrides[flag]; ok {
+			return FeatureFlagState{Enabled: enabled, Source: FeatureFlagOverride}
+		}
+		if enabled, ok := f.remote[flag]; ok {
+			return FeatureFlagState{Enabled: enabled, Source: FeatureFlagSDN}
+		}
+	}
+	return FeatureFlagState{Enabled: defaultFeatureFlags[flag], Source: FeatureFlagDefault}
+}
+
+// States returns the state of all the feature flags
+func (f *FeatureFlags) States() map[FeatureFlag]FeatureFlagState {
+	states := make(map[FeatureFlag]FeatureFlagState, len(defaultFeatureFlags))
+	for flag := range defaultFeatureFlags {
+		states[flag] = f.State(flag)
+	}
+	return states
+}
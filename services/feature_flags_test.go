@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureFlags(t *testing.T) {
+	_, err := NewFeatureFlags(map[string]bool{"unknown": true})
+	require.Error(t, err)
+
+	flags, err := NewFeatureFlags(map[string]bool{string(FeatureTxBatching): true})
+	require.NoError(t, err)
+	assert.Equal(t, FeatureFlagState{Enabled: true, Source: FeatureFlagDefault}, flags.State(FeatureCombinedFeed))
+
+	// the values of the SDN replace the defaults, unknown flags are ignored
+	flags.UpdateRemote(map[string]bool{
+		string(FeatureTxBatching):   false,
+		string(FeatureCombinedFeed): false,
+		"unknown":                   true,
+	})
+	assert.False(t, flags.Enabled(FeatureCombinedFeed))
+	assert.Equal(t, FeatureFlagSDN, flags.State(FeatureCombinedFeed).Source)
+	assert.Len(t, flags.States(), len(FeatureFlagNames()))
+
+	// local overrides take precedence over the SDN
+	assert.Equal(t, FeatureFlagState{Enabled: true, Source: FeatureFlagOverride}, flags.State(FeatureTxBatching))
+
+	// values which are no longer sent by the SDN are back to their default
+	flags.UpdateRemote(nil)
+	assert.True(t, flags.Enabled(FeatureCombinedFeed))
+}
+
+func TestFeatureFlags_Nil(t *testing.T) {
+	var flags *FeatureFlags
+	flags.UpdateRemote(map[string]bool{string(FeatureTxBatching): false})
+	assert.True(t, flags.Enabled(FeatureTxBatching))
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const feedAlertWebhookTimeout = 5 * time.Second
+
+// FeedRateBounds are the expected rates of a feed in notifications per second, a zero bound is not checked
+type FeedRateBounds struct {
+	Min float64
+	Max float64
+}
+
+// FeedAlertsConfig defines the expected rates of the feeds and where the alerts are sent
+type FeedAlertsConfig struct {
+	Bounds   map[types.FeedType]FeedRateBounds
+	Interval time.Duration
+	// Webhook is the URL the alerts are posted to, the alerts are only logged if it is empty
+	Webhook string
+}
+
+// rateTrackedFeeds are the feeds published by the gateway, whose rates can be bounded
+var rateTrackedFeeds = []types.FeedType{
+	types.NewTxsFeed, types.PendingTxsFeed, types.BDNBlocksFeed, types.NewBlocksFeed, types.OnBlockFeed,
+	types.TxReceiptsFeed, types.TransactionStatusFeed, types.TopOfBlockFeed, types.NextSprintValidatorsFeed,
+	types.NewBeaconBlocksFeed, types.BDNBeaconBlocksFeed, types.ProposerDutiesFeed, types.ContractCreationsFeed,
+	types.LogsFeed, types.MEVShareFeed,
+}
+
+// ParseFeedRateBounds parses feed=min-max rate bounds, either bound can be omitted
+func ParseFeedRateBounds(values []string) (map[types.FeedType]FeedRateBounds, error) {
+	bounds := make(map[types.FeedType]FeedRateBounds, len(values))
+	for _, value := range values {
+		feed, rates, found := strings.Cut(value, "=")
+		minRate, maxRate, rangeFound := strings.Cut(rates, "-")
+		if !found || !rangeFound || feed == "" {
+			return nil, fmt.Errorf("invalid feed rate bounds %v, expected feed=min-max", value)
+		}
+		if !types.Exists(types.FeedType(feed), rateTrackedFeeds) {
+			return nil, fmt.Errorf("invalid feed rate bounds %v, unknown feed %v", value, feed)
+		}
+
+		var feedBounds FeedRateBounds
+		var err error
+		if minRate != "" {
+			if feedBounds.Min, err = strconv.ParseFloat(minRate, 64); err != nil || feedBounds.Min < 0 {
+				return nil, fmt.Errorf("invalid min rate in feed rate bounds %v", value)
+			}
+		}
+		if maxRate != "" {
+			if feedBounds.Max, err = strconv.ParseFloat(maxRate, 64); err != nil || feedBounds.Max < 0 {
+				return nil, fmt.Errorf("invalid max rate in feed rate bounds %v", value)
+			}
+		}
+		if feedBounds.Max > 0 && feedBounds.Min > feedBounds.Max {
+			return nil, fmt.Errorf("min rate is greater than the max rate in feed rate bounds %v", value)
+		}
+		bounds[types.FeedType(feed)] = feedBounds
+	}
+	return bounds, nil
+}
+
+// FeedRateAlertState is the state of the rate of a feed
+type FeedRateAlertState string
+
+// FeedRateAlertState types enumeration
+const (
+	FeedRateNormal FeedRateAlertState = "normal"
+	FeedRateLow    FeedRateAlertState = "low"
+	FeedRateHigh   FeedRateAlertState = "high"
+)
+
+// FeedRateAlert is sent when the rate of a feed goes out of its bounds or back within them
+type FeedRateAlert struct {
+	Feed  types.FeedType     `json:"feed"`
+	State FeedRateAlertState `json:"state"`
+	Rate  float64            `json:"rate"`
+	Min   float64            `json:"min,omitempty"`
+	Max   float64            `json:"max,omitempty"`
+	Time  time.Time          `json:"time"`
+}
+
+// FeedRateAlerter watches the rate of the feeds and alerts when it drops below or spikes above the configured bounds,
+// catching silent upstream failures like newTxs falling to zero
+type FeedRateAlerter struct {
+	config FeedAlertsConfig
+	clock  utils.Clock
+	// the low rate alerts are only fired while synced returns true
+	synced func() bool
+	client *http.Client
+
+	lock      sync.Mutex
+	counts    map[types.FeedType]uint64
+	states    map[types.FeedType]FeedRateAlertState
+	lastCheck time.Time
+}
+
+// NewFeedRateAlerter creates the alerter, it returns nil if no bounds are configured
+func NewFeedRateAlerter(config FeedAlertsConfig, clock utils.Clock, synced func() bool) *FeedRateAlerter {
+	if len(config.Bounds) == 0 {
+		return nil
+	}
+
+	a := &FeedRateAlerter{
+		config:    config,
+		clock:     clock,
+		synced:    synced,
+		client:    &http.Client{Timeout: feedAlertWebhookTimeout},
+		counts:    make(map[types.FeedType]uint64, len(config.Bounds)),
+		states:    make(map[types.FeedType]FeedRateAlertState, len(config.Bounds)),
+		lastCheck: clock.Now(),
+	}
+	for feed := range config.Bounds {
+		a.states[feed] = FeedRateNormal
+	}
+	return a
+}
+
+// Track counts a notification published to the feed
+func (a *FeedRateAlerter) Track(feed types.FeedType) {
+	if a == nil {
+		return
+	}
+
+	a.lock.Lock()
+	if _, ok := a.config.Bounds[feed]; ok {
+		a.counts[feed]++
+	}
+	a.lock.Unlock()
+}
+
+// Run checks the rates of the feeds every interval until the context is done
+func (a *FeedRateAlerter) Run(ctx context.Context) {
+	if a == nil {
+		return
+	}
+
+	ticker := a.clock.Ticker(a.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			for _, alert := range a.check() {
+				a.send(alert)
+			}
+		}
+	}
+}
+
+// check measures the rates since the last check, and returns the alerts of the feeds whose state changed
+func (a *FeedRateAlerter) check() []FeedRateAlert {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	now := a.clock.Now()
+	elapsed := now.Sub(a.lastCheck).Seconds()
+	a.lastCheck = now
+	if elapsed <= 0 {
+		return nil
+	}
+	synced := a.synced == nil || a.synced()
+
+	var alerts []FeedRateAlert
+	for feed, bounds := range a.config.Bounds {
+		rate := float64(a.counts[feed]) / elapsed
+		a.counts[feed] = 0
+
+		state := FeedRateNormal
+		switch {
+		case bounds.Max > 0 && rate > bounds.Max:
+			state = FeedRateHigh
+		case bounds.Min > 0 && rate < bounds.Min:
+			if !synced {
+				// a low rate is expected while the node is syncing, the previous state is kept
+				continue
+			}
+			state = FeedRateLow
+		}
+
+		if state == a.states[feed] {
+			continue
+		}
+		a.states[feed] = state
+		alerts = append(alerts, FeedRateAlert{Feed: feed, State: state, Rate: rate, Min: bounds.Min, Max: bounds.Max, Time: now})
+	}
+	return alerts
+}
+
+func (a *FeedRateAlerter) send(alert FeedRateAlert) {
+	if alert.State == FeedRateNormal {
+		log.Infof("%v feed rate is back to normal at %.3f notifications per second", alert.Feed, alert.Rate)
+	} else {
+		log.Warnf("%v feed rate is %v at %.3f notifications per second, expected between %v and %v", alert.Feed, alert.State, alert.Rate, alert.Min, alert.Max)
+	}
+
+	if a.config.Webhook == "" {
+		return
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.Errorf("failed to marshal %v feed rate alert: %v", alert.Feed, err)
+		return
+	}
+	resp, err := a.client.Post(a.config.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("failed to post %v feed rate alert to the webhook: %v", alert.Feed, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.Errorf("failed to post %v feed rate alert to the webhook: status %v", alert.Feed, resp.Status)
+	}
+}
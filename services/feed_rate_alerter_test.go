@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeedRateBounds(t *testing.T) {
+	bounds, err := ParseFeedRateBounds([]string{"newTxs=1-5000", "bdnBlocks=0.01-", "pendingTxs=-100"})
+	require.NoError(t, err)
+	assert.Equal(t, map[types.FeedType]FeedRateBounds{
+		types.NewTxsFeed:     {Min: 1, Max: 5000},
+		types.BDNBlocksFeed:  {Min: 0.01},
+		types.PendingTxsFeed: {Max: 100},
+	}, bounds)
+
+	for _, value := range []string{"newTxs", "newTxs=1", "=1-2", "newTxs=a-2", "newTxs=1-b", "newTxs=5-1", "newtxs=1-2", "combined=1-2"} {
+		_, err = ParseFeedRateBounds([]string{value})
+		assert.Error(t, err, value)
+	}
+}
+
+func TestFeedRateAlerter(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	synced := true
+	alerter := NewFeedRateAlerter(FeedAlertsConfig{
+		Bounds:   map[types.FeedType]FeedRateBounds{types.NewTxsFeed: {Min: 1, Max: 10}},
+		Interval: 10 * time.Second,
+	}, clock, func() bool { return synced })
+
+	track := func(feed types.FeedType, count int) {
+		for i := 0; i < count; i++ {
+			alerter.Track(feed)
+		}
+		clock.IncTime(10 * time.Second)
+	}
+
+	// within the bounds
+	track(types.NewTxsFeed, 50)
+	assert.Empty(t, alerter.check())
+
+	// feeds without bounds are not tracked
+	track(types.PendingTxsFeed, 1000)
+	alerts := alerter.check()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, FeedRateLow, alerts[0].State)
+	assert.Equal(t, 0.0, alerts[0].Rate)
+
+	// the alert fires once while the state does not change
+	track(types.NewTxsFeed, 0)
+	assert.Empty(t, alerter.check())
+
+	track(types.NewTxsFeed, 200)
+	alerts = alerter.check()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, FeedRateHigh, alerts[0].State)
+	assert.Equal(t, 20.0, alerts[0].Rate)
+
+	track(types.NewTxsFeed, 50)
+	alerts = alerter.check()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, FeedRateNormal, alerts[0].State)
+
+	// a low rate is ignored while the node is not synced
+	synced = false
+	track(types.NewTxsFeed, 0)
+	assert.Empty(t, alerter.check())
+}
+
+func TestFeedRateAlerter_Webhook(t *testing.T) {
+	received := make(chan FeedRateAlert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert FeedRateAlert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+	}))
+	defer server.Close()
+
+	alerter := NewFeedRateAlerter(FeedAlertsConfig{
+		Bounds:   map[types.FeedType]FeedRateBounds{types.NewTxsFeed: {Min: 1}},
+		Interval: time.Second,
+		Webhook:  server.URL,
+	}, &utils.MockClock{}, nil)
+
+	alerter.send(FeedRateAlert{Feed: types.NewTxsFeed, State: FeedRateLow, Min: 1})
+	alert := <-received
+	assert.Equal(t, types.NewTxsFeed, alert.Feed)
+	assert.Equal(t, FeedRateLow, alert.State)
+
+	assert.Nil(t, NewFeedRateAlerter(FeedAlertsConfig{}, &utils.MockClock{}, nil))
+	var nilAlerter *FeedRateAlerter
+	nilAlerter.Track(types.NewTxsFeed)
+}
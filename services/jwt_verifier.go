@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	jwksFetchTimeout = 10 * time.Second
+	// jwksMinRefreshInterval is the shortest time between two fetches of the JWKS, a token signed with an unknown key
+	// triggers a fetch so the keys of the identity provider can be rotated before the refresh interval
+	jwksMinRefreshInterval = time.Minute
+	// BearerPrefix is the prefix of the Authorization header holding a JWT
+	BearerPrefix = "Bearer "
+)
+
+// jwtSigningMethods are the algorithms the tokens can be signed with
+var jwtSigningMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}
+
+// JWTConfig defines how the JWT bearer tokens of the clients are verified
+type JWTConfig struct {
+	// JWKSURL serves the public keys the tokens are signed with, the JWT authentication is disabled if it is empty
+	JWKSURL string
+	// Issuer and Audience are the expected iss and aud claims of the tokens, they are not checked if empty
+	Issuer   string
+	Audience string
+	// AccountClaim is the claim holding the account ID of the client
+	AccountClaim string
+	// RefreshInterval is the time between two fetches of the JWKS
+	RefreshInterval time.Duration
+}
+
+// JWTVerifier verifies the RS256 and ES256 JWT bearer tokens of the clients against the keys of a JWKS URL, which is
+// refreshed periodically and whenever a token is signed with an unknown key. All methods do nothing on a nil receiver.
+type JWTVerifier struct {
+	cfg    JWTConfig
+	clock  utils.Clock
+	client *http.Client
+
+	lock      sync.RWMutex
+	keys      map[string]interface{}
+	lastFetch time.Time
+}
+
+// NewJWTVerifier creates the verifier and fetches the keys, it returns nil if no JWKS URL is configured
+func NewJWTVerifier(cfg JWTConfig, clock utils.Clock) (*JWTVerifier, error) {
+	if cfg.JWKSURL == "" {
+		return nil, nil
+	}
+
+	v := &JWTVerifier{
+		cfg:    cfg,
+		clock:  clock,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		keys:   make(map[string]interface{}),
+	}
+	if err := v.refresh(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Run refreshes the keys every refresh interval until the context is done
+func (v *JWTVerifier) Run(ctx context.Context) {
+	if v == nil {
+		return
+	}
+
+	ticker := v.clock.Ticker(v.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+			if err := v.refresh(); err != nil {
+				log.Errorf("failed to refresh the JWT keys, the previous keys are kept: %v", err)
+			}
+		}
+	}
+}
+
+// Enabled returns whether the clients can authenticate with a JWT
+func (v *JWTVerifier) Enabled() bool {
+	return v != nil
+}
+
+// Verify verifies the token and returns the account ID of its account claim
+func (v *JWTVerifier) Verify(token string) (types.AccountID, error) {
+	if v == nil {
+		return "", errors.New("JWT authentication is not enabled")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, v.key, jwt.WithValidMethods(jwtSigningMethods))
+	if err != nil {
+		return "", fmt.Errorf("invalid JWT: %v", err)
+	}
+	if !claims.VerifyExpiresAt(v.clock.Now().Unix(), true) {
+		return "", errors.New("invalid JWT: the token has no expiry or is expired")
+	}
+	if v.cfg.Issuer != "" && !claims.VerifyIssuer(v.cfg.Issuer, true) {
+		return "", errors.New("invalid JWT: unexpected issuer")
+	}
+	if v.cfg.Audience != "" && !claims.VerifyAudience(v.cfg.Audience, true) {
+		return "", errors.New("invalid JWT: unexpected audience")
+	}
+	accountID, ok := claims[v.cfg.AccountClaim].(string)
+	if !ok || accountID == "" {
+		return "", fmt.Errorf("invalid JWT: missing %v claim", v.cfg.AccountClaim)
+	}
+	return types.AccountID(accountID), nil
+}
+
+// key returns the key the token is signed with, fetching the keys again if it is unknown
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	v.lock.RLock()
+	recent := v.clock.Now().Sub(v.lastFetch) < jwksMinRefreshInterval
+	v.lock.RUnlock()
+	if !recent {
+		if err := v.refresh(); err != nil {
+			return nil, err
+		}
+		if key, ok := v.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (v *JWTVerifier) lookup(kid string) (interface{}, bool) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// refresh replaces the keys with the keys of the JWKS URL
+func (v *JWTVerifier) refresh() error {
+	v.lock.Lock()
+	v.lastFetch = v.clock.Now()
+	v.lock.Unlock()
+
+	resp, err := v.client.Get(v.cfg.JWKSURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch the JWKS from %v: %v", v.cfg.JWKSURL, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch the JWKS from %v: status %v", v.cfg.JWKSURL, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the JWKS from %v: %v", v.cfg.JWKSURL, err)
+	}
+	keys, err := parseJWKS(body)
+	if err != nil {
+		return fmt.Errorf("invalid JWKS from %v: %v", v.cfg.JWKSURL, err)
+	}
+
+	v.lock.Lock()
+	v.keys = keys
+	v.lock.Unlock()
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS returns the RSA and P-256 signing keys of the JWKS by their key ID, the other keys are skipped
+func parseJWKS(body []byte) (map[string]interface{}, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		switch {
+		case key.Kty == "RSA":
+			n, err := decodeJWKInt(key.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid n of key %q: %v", key.Kid, err)
+			}
+			e, err := decodeJWKInt(key.E)
+			if err != nil || !e.IsInt64() {
+				return nil, fmt.Errorf("invalid e of key %q", key.Kid)
+			}
+			keys[key.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case key.Kty == "EC" && key.Crv == "P-256":
+			x, err := decodeJWKInt(key.X)
+			if err != nil {
+				return nil, fmt.Errorf("invalid x of key %q: %v", key.Kid, err)
+			}
+			y, err := decodeJWKInt(key.Y)
+			if err != nil {
+				return nil, fmt.Errorf("invalid y of key %q: %v", key.Kid, err)
+			}
+			if !elliptic.P256().IsOnCurve(x, y) {
+				return nil, fmt.Errorf("key %q is not on the P-256 curve", key.Kid)
+			}
+			keys[key.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA or P-256 signing key")
+	}
+	return keys, nil
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
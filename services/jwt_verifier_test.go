@@ -0,0 +1,125 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWKS serves the public keys it holds as a JWKS
+type testJWKS struct {
+	lock    sync.Mutex
+	keys    []map[string]string
+	fetches int
+}
+
+func (s *testJWKS) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.fetches++
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+}
+
+func (s *testJWKS) add(key map[string]string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.keys = append(s.keys, key)
+}
+
+func jwkInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func signJWT(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks := &testJWKS{}
+	jwks.add(map[string]string{"kty": "RSA", "kid": "rsa", "use": "sig", "n": jwkInt(rsaKey.N), "e": jwkInt(big.NewInt(int64(rsaKey.E)))})
+	server := httptest.NewServer(jwks)
+	defer server.Close()
+
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Now())
+	verifier, err := NewJWTVerifier(JWTConfig{
+		JWKSURL:         server.URL,
+		Issuer:          "idp",
+		Audience:        "gateway",
+		AccountClaim:    "sub",
+		RefreshInterval: time.Hour,
+	}, clock)
+	require.NoError(t, err)
+	require.True(t, verifier.Enabled())
+
+	claims := jwt.MapClaims{"sub": "account", "iss": "idp", "aud": "gateway", "exp": clock.Now().Add(time.Minute).Unix()}
+	accountID, err := verifier.Verify(signJWT(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims))
+	require.NoError(t, err)
+	assert.Equal(t, types.AccountID("account"), accountID)
+
+	// the claims must match the config
+	for _, invalid := range []jwt.MapClaims{
+		{"sub": "account", "iss": "other", "aud": "gateway", "exp": clock.Now().Add(time.Minute).Unix()},
+		{"sub": "account", "iss": "idp", "aud": "other", "exp": clock.Now().Add(time.Minute).Unix()},
+		{"sub": "account", "iss": "idp", "aud": "gateway"},
+		{"iss": "idp", "aud": "gateway", "exp": clock.Now().Add(time.Minute).Unix()},
+	} {
+		_, err = verifier.Verify(signJWT(t, jwt.SigningMethodRS256, "rsa", rsaKey, invalid))
+		assert.Error(t, err, invalid)
+	}
+
+	// only RS256 and ES256 are accepted
+	_, err = verifier.Verify(signJWT(t, jwt.SigningMethodHS256, "rsa", []byte("secret"), claims))
+	assert.Error(t, err)
+
+	// a token signed with an unknown key fetches the keys again, at most once per jwksMinRefreshInterval
+	ecToken := signJWT(t, jwt.SigningMethodES256, "ec", ecKey, claims)
+	_, err = verifier.Verify(ecToken)
+	assert.Error(t, err)
+	jwks.add(map[string]string{"kty": "EC", "kid": "ec", "crv": "P-256", "x": jwkInt(ecKey.X), "y": jwkInt(ecKey.Y)})
+	// the keys are not fetched again right away
+	_, err = verifier.Verify(ecToken)
+	assert.Error(t, err)
+	clock.IncTime(jwksMinRefreshInterval)
+	accountID, err = verifier.Verify(ecToken)
+	require.NoError(t, err)
+	assert.Equal(t, types.AccountID("account"), accountID)
+	assert.Equal(t, 2, jwks.fetches)
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{}, &utils.MockClock{})
+	require.NoError(t, err)
+	assert.False(t, verifier.Enabled())
+	_, err = verifier.Verify("token")
+	assert.Error(t, err)
+
+	server := httptest.NewServer(&testJWKS{})
+	defer server.Close()
+	_, err = NewJWTVerifier(JWTConfig{JWKSURL: server.URL, AccountClaim: "sub", RefreshInterval: time.Hour}, &utils.MockClock{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// LeaderElectionMode defines how the gateway decides if it publishes a feed to a sink shared with other gateways
+type LeaderElectionMode string
+
+// LeaderElectionMode enumeration
+const (
+	LeaderElectionNone     LeaderElectionMode = ""
+	LeaderElectionCluster  LeaderElectionMode = "cluster"
+	LeaderElectionLockFile LeaderElectionMode = "lockfile"
+)
+
+// FeedLeaderElector decides which gateway publishes a feed when several gateways publish to the same sink
+type FeedLeaderElector interface {
+	Start(ctx context.Context) error
+	IsLeader(feed types.FeedType) bool
+}
+
+// AlwaysLeader is used when leader election is disabled, the gateway publishes every feed
+type AlwaysLeader struct{}
+
+// Start does nothing
+func (AlwaysLeader) Start(context.Context) error { return nil }
+
+// IsLeader is always true
+func (AlwaysLeader) IsLeader(types.FeedType) bool { return true }
+
+type clusterLeaderElector struct {
+	cluster Cluster
+}
+
+// NewClusterLeaderElector elects the feed leader among the alive members of the cluster
+func NewClusterLeaderElector(cluster Cluster) FeedLeaderElector {
+	return clusterLeaderElector{cluster: cluster}
+}
+
+// Start does nothing, the cluster is started by the gateway
+func (c clusterLeaderElector) Start(context.Context) error { return nil }
+
+// IsLeader reports if this gateway owns the feed in the cluster
+func (c clusterLeaderElector) IsLeader(feed types.FeedType) bool {
+	return c.cluster.OwnsFeed(feed)
+}
+
+type feedLease struct {
+	NodeID  types.NodeID `json:"node_id"`
+	Expires time.Time    `json:"expires"`
+}
+
+// feedLeadership is the lease generation held by the gateway and its expiration
+type feedLeadership struct {
+	generation uint64
+	expires    time.Time
+}
+
+// lockFileLeaderElector holds a lease file per feed generation, named <feed>.<generation>.lock. The leader is the
+// gateway which created the highest generation, it renews the lease until it stops. An expired lease is taken over
+// by creating the next generation with O_CREATE|O_EXCL, so exactly one contender wins each generation.
+type lockFileLeaderElector struct {
+	dir    string
+	nodeID types.NodeID
+	lease  time.Duration
+	clock  utils.Clock
+	lock   sync.RWMutex
+	feeds  map[types.FeedType]feedLeadership
+	log    *log.Entry
+}
+
+// NewLockFileLeaderElector elects the feed leader using lease files in a directory shared by the gateways (e.g. NFS mount)
+func NewLockFileLeaderElector(dir string, nodeID types.NodeID, lease time.Duration, feeds []types.FeedType, clock utils.Clock) FeedLeaderElector {
+	return newLockFileLeaderElector(dir, nodeID, lease, feeds, clock)
+}
+
+func newLockFileLeaderElector(dir string, nodeID types.NodeID, lease time.Duration, feeds []types.FeedType, clock utils.Clock) *lockFileLeaderElector {
+	l := &lockFileLeaderElector{
+		dir:    dir,
+		nodeID: nodeID,
+		lease:  lease,
+		clock:  clock,
+		feeds:  make(map[types.FeedType]feedLeadership),
+		log: log.WithFields(log.Fields{
+			"component": "feedLeaderElector",
+			"nodeID":    nodeID,
+		}),
+	}
+	for _, feed := range feeds {
+		l.feeds[feed] = feedLeadership{}
+	}
+	return l
+}
+
+// Start renews or acquires the leases until the context is done, leadership is released on exit
+func (l *lockFileLeaderElector) Start(ctx context.Context) error {
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create feed leader lock directory %v: %v", l.dir, err)
+	}
+
+	l.elect()
+	ticker := l.clock.Ticker(l.lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.release()
+			return nil
+		case <-ticker.Alert():
+			l.elect()
+		}
+	}
+}
+
+// IsLeader reports if this gateway holds an unexpired lease of the feed. Feeds unknown to the elector are contended
+// from the next election on.
+func (l *lockFileLeaderElector) IsLeader(feed types.FeedType) bool {
+	l.lock.RLock()
+	leadership, ok := l.feeds[feed]
+	l.lock.RUnlock()
+
+	if !ok {
+		l.lock.Lock()
+		if _, ok = l.feeds[feed]; !ok {
+			l.feeds[feed] = feedLeadership{}
+		}
+		l.lock.Unlock()
+		return false
+	}
+	return l.clock.Now().Before(leadership.expires)
+}
+
+func (l *lockFileLeaderElector) elect() {
+	l.lock.RLock()
+	feeds := make([]types.FeedType, 0, len(l.feeds))
+	for feed := range l.feeds {
+		feeds = append(feeds, feed)
+	}
+	l.lock.RUnlock()
+
+	for _, feed := range feeds {
+		leadership, err := l.tryAcquire(feed)
+		if err != nil {
+			l.log.Errorf("failed to acquire lease for feed %v: %v", feed, err)
+		}
+		isLeader := leadership.expires.After(l.clock.Now())
+
+		l.lock.Lock()
+		if wasLeader := l.feeds[feed].expires.After(l.clock.Now()); wasLeader != isLeader {
+			l.log.Infof("leadership of feed %v changed, leader: %v", feed, isLeader)
+		}
+		l.feeds[feed] = leadership
+		l.lock.Unlock()
+	}
+}
+
+func (l *lockFileLeaderElector) tryAcquire(feed types.FeedType) (feedLeadership, error) {
+	now := l.clock.Now()
+	generation, current, err := l.currentLease(feed)
+	if err != nil {
+		return feedLeadership{}, err
+	}
+	renewed := feedLeadership{generation: generation, expires: now.Add(l.lease)}
+
+	if current != nil && now.Before(current.Expires) {
+		if current.NodeID != l.nodeID {
+			return feedLeadership{}, nil
+		}
+		// only the leader writes to its generation, the lease is renewed in place
+		if err = writeFeedLease(l.leasePath(feed, generation), feedLease{NodeID: l.nodeID, Expires: renewed.expires}); err != nil {
+			return feedLeadership{}, err
+		}
+		return renewed, nil
+	}
+
+	// the lease is free or expired, the contender creating the next generation takes it over
+	renewed.generation++
+	file, err := os.OpenFile(l.leasePath(feed, renewed.generation), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return feedLeadership{}, nil
+	}
+	if err != nil {
+		return feedLeadership{}, err
+	}
+	content, err := json.Marshal(feedLease{NodeID: l.nodeID, Expires: renewed.expires})
+	if err == nil {
+		_, err = file.Write(content)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return feedLeadership{}, err
+	}
+
+	// the previous generation is kept, so contenders listing the directory meanwhile still see an unexpired lease
+	l.removeLeases(feed, renewed.generation-1)
+	return renewed, nil
+}
+
+// currentLease returns the highest generation of the feed lease and its content, nil if there is no lease
+func (l *lockFileLeaderElector) currentLease(feed types.FeedType) (uint64, *feedLease, error) {
+	generations, err := l.generations(feed)
+	if err != nil || len(generations) == 0 {
+		return 0, nil, err
+	}
+	generation := generations[len(generations)-1]
+
+	path := l.leasePath(feed, generation)
+	lease, err := readFeedLease(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	if lease == nil {
+		// the lease is being written or its writer died, it expires a lease duration after its creation
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return generation, nil, nil
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		lease = &feedLease{}
+		// the file times come from the file system clock, the age is compared with the lease duration only
+		if time.Since(info.ModTime()) < l.lease {
+			lease.Expires = l.clock.Now().Add(l.lease)
+		}
+	}
+	return generation, lease, nil
+}
+
+// generations returns the generations of the feed lease files in increasing order
+func (l *lockFileLeaderElector) generations(feed types.FeedType) ([]uint64, error) {
+	paths, err := filepath.Glob(filepath.Join(l.dir, string(feed)+".*.lock"))
+	if err != nil {
+		return nil, err
+	}
+	generations := make([]uint64, 0, len(paths))
+	for _, path := range paths {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), string(feed)+"."), ".lock")
+		generation, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		generations = append(generations, generation)
+	}
+	sort.Slice(generations, func(i, j int) bool { return generations[i] < generations[j] })
+	return generations, nil
+}
+
+// removeLeases removes the lease files of the feed older than the generation
+func (l *lockFileLeaderElector) removeLeases(feed types.FeedType, generation uint64) {
+	generations, err := l.generations(feed)
+	if err != nil {
+		return
+	}
+	for _, old := range generations {
+		if old >= generation {
+			break
+		}
+		if err = os.Remove(l.leasePath(feed, old)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			l.log.Debugf("failed to remove expired lease %v of feed %v: %v", old, feed, err)
+		}
+	}
+}
+
+// release expires the leases held by the gateway, so they are taken over without waiting for the lease duration
+func (l *lockFileLeaderElector) release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.clock.Now()
+	for feed, leadership := range l.feeds {
+		if !now.Before(leadership.expires) {
+			continue
+		}
+		if err := writeFeedLease(l.leasePath(feed, leadership.generation), feedLease{NodeID: l.nodeID, Expires: now}); err != nil {
+			l.log.Warnf("failed to release lease for feed %v: %v", feed, err)
+		}
+		l.feeds[feed] = feedLeadership{}
+	}
+}
+
+func (l *lockFileLeaderElector) leasePath(feed types.FeedType, generation uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%v.%v.lock", feed, generation))
+}
+
+func readFeedLease(path string) (*feedLease, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lease feedLease
+	if err = json.Unmarshal(content, &lease); err != nil {
+		return nil, nil
+	}
+	return &lease, nil
+}
+
+func writeFeedLease(path string, lease feedLease) error {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := fmt.Sprintf("%v.%v.tmp", path, lease.NodeID)
+	if err = os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
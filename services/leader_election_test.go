@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFileLeaderElector(t *testing.T) {
+	dir := t.TempDir()
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	feeds := []types.FeedType{types.NewTxsFeed, types.NewBlocksFeed}
+
+	first := newLockFileLeaderElector(dir, "first", time.Second, feeds, &clock)
+	second := newLockFileLeaderElector(dir, "second", time.Second, feeds, &clock)
+
+	first.elect()
+	second.elect()
+	for _, feed := range feeds {
+		assert.True(t, first.IsLeader(feed))
+		assert.False(t, second.IsLeader(feed))
+	}
+
+	// leader renews the lease
+	clock.IncTime(500 * time.Millisecond)
+	first.elect()
+	clock.IncTime(700 * time.Millisecond)
+	second.elect()
+	assert.False(t, second.IsLeader(types.NewTxsFeed))
+
+	// leader stopped renewing the lease
+	clock.IncTime(2 * time.Second)
+	second.elect()
+	first.elect()
+	for _, feed := range feeds {
+		assert.False(t, first.IsLeader(feed))
+		assert.True(t, second.IsLeader(feed))
+	}
+
+	// released lease is taken over immediately
+	second.release()
+	first.elect()
+	assert.True(t, first.IsLeader(types.NewTxsFeed))
+}
+
+func TestLockFileLeaderElector_ConcurrentContenders(t *testing.T) {
+	dir := t.TempDir()
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	feeds := []types.FeedType{types.NewTxsFeed, types.NewBlocksFeed}
+
+	contenders := make([]*lockFileLeaderElector, 8)
+	for i := range contenders {
+		contenders[i] = newLockFileLeaderElector(dir, types.NodeID(fmt.Sprintf("gateway-%v", i)), time.Second, feeds, &clock)
+	}
+
+	electConcurrently := func() {
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for _, contender := range contenders {
+			wg.Add(1)
+			go func(contender *lockFileLeaderElector) {
+				defer wg.Done()
+				<-start
+				contender.elect()
+			}(contender)
+		}
+		close(start)
+		wg.Wait()
+	}
+	leaders := func(feed types.FeedType) []types.NodeID {
+		var nodeIDs []types.NodeID
+		for _, contender := range contenders {
+			if contender.IsLeader(feed) {
+				nodeIDs = append(nodeIDs, contender.nodeID)
+			}
+		}
+		return nodeIDs
+	}
+
+	// every contender races for the free leases
+	electConcurrently()
+	for _, feed := range feeds {
+		assert.Len(t, leaders(feed), 1, feed)
+	}
+
+	// every contender races for the expired leases
+	clock.IncTime(2 * time.Second)
+	electConcurrently()
+	for _, feed := range feeds {
+		assert.Len(t, leaders(feed), 1, feed)
+	}
+
+	// the leader renews its lease while the others keep contending
+	leader := leaders(types.NewTxsFeed)[0]
+	clock.IncTime(500 * time.Millisecond)
+	electConcurrently()
+	assert.Equal(t, []types.NodeID{leader}, leaders(types.NewTxsFeed))
+}
+
+func TestLockFileLeaderElector_UnknownFeed(t *testing.T) {
+	clock := utils.MockClock{}
+	clock.SetTime(time.Now())
+	elector := newLockFileLeaderElector(t.TempDir(), "first", time.Second, nil, &clock)
+
+	// unknown feeds are contended from the next election on
+	assert.False(t, elector.IsLeader(types.TopOfBlockFeed))
+	elector.elect()
+	assert.True(t, elector.IsLeader(types.TopOfBlockFeed))
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	securityWebhookTimeout = 5 * time.Second
+	// securityEventBacklog is the number of events waiting to be posted, the events beyond it are dropped
+	securityEventBacklog = 100
+	// maxIPRangesPerAccount bounds the IP ranges remembered for each account
+	maxIPRangesPerAccount = 1024
+)
+
+// SecurityEventType is the kind of a security event
+type SecurityEventType string
+
+// SecurityEventType types enumeration
+const (
+	SecurityEventAuthFailures     SecurityEventType = "auth_failures"
+	SecurityEventNewIPRange       SecurityEventType = "new_ip_range"
+	SecurityEventQuotaExhausted   SecurityEventType = "quota_exhausted"
+	SecurityEventPrivilegedMethod SecurityEventType = "privileged_method"
+)
+
+// SecurityEventsConfig defines where the security events of the accounts are posted and when they are fired
+type SecurityEventsConfig struct {
+	// Webhooks are the URLs the events of each account are posted to
+	Webhooks map[types.AccountID]string
+	// DefaultWebhook is the URL the events of the accounts without their own webhook are posted to, the events of
+	// these accounts are not tracked if it is empty
+	DefaultWebhook string
+	// AuthFailures is the number of failed authentications of an account within the window which fires an event
+	AuthFailures int
+	// Window is the period the auth failures are counted over, an event is not fired again within it
+	Window time.Duration
+}
+
+// ParseSecurityWebhooks parses [account-id=]url webhooks, the webhook without account-id is the default webhook
+func ParseSecurityWebhooks(values []string) (map[types.AccountID]string, string, error) {
+	webhooks := make(map[types.AccountID]string, len(values))
+	var defaultWebhook string
+	for _, value := range values {
+		accountID, url, found := strings.Cut(value, "=")
+		// the query of a URL without account-id can hold =
+		if !found || strings.Contains(accountID, "://") {
+			accountID, url = "", value
+		}
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return nil, "", fmt.Errorf("invalid security webhook %v, expected [account-id=]url with an http or https url", value)
+		}
+		if accountID == "" {
+			if defaultWebhook != "" {
+				return nil, "", fmt.Errorf("invalid security webhook %v, the default webhook is already set", value)
+			}
+			defaultWebhook = url
+			continue
+		}
+		if _, ok := webhooks[types.AccountID(accountID)]; ok {
+			return nil, "", fmt.Errorf("invalid security webhook %v, the webhook of account %v is already set", value, accountID)
+		}
+		webhooks[types.AccountID(accountID)] = url
+	}
+	return webhooks, defaultWebhook, nil
+}
+
+// SecurityEvent is posted to the webhook of the account on a security relevant event
+type SecurityEvent struct {
+	AccountID     types.AccountID   `json:"account_id"`
+	Type          SecurityEventType `json:"type"`
+	RemoteAddress string            `json:"remote_address,omitempty"`
+	Detail        string            `json:"detail"`
+	Time          time.Time         `json:"time"`
+}
+
+type securityEventKey struct {
+	accountID types.AccountID
+	eventType SecurityEventType
+	subject   string
+}
+
+// SecurityEventNotifier posts the security events of the accounts to their webhooks, so they can be fed to a SIEM:
+// repeated auth failures, connections from new IP ranges, quota exhaustion and the use of privileged methods. The same
+// event is posted at most once per window. All methods do nothing on a nil receiver.
+type SecurityEventNotifier struct {
+	cfg    SecurityEventsConfig
+	clock  utils.Clock
+	client *http.Client
+	events chan SecurityEvent
+
+	lock         sync.Mutex
+	authFailures map[types.AccountID][]time.Time
+	ipRanges     map[types.AccountID]map[string]struct{}
+	lastFired    map[securityEventKey]time.Time
+}
+
+// NewSecurityEventNotifier creates the notifier, it returns nil if no webhook is configured
+func NewSecurityEventNotifier(cfg SecurityEventsConfig, clock utils.Clock) *SecurityEventNotifier {
+	if len(cfg.Webhooks) == 0 && cfg.DefaultWebhook == "" {
+		return nil
+	}
+
+	return &SecurityEventNotifier{
+		cfg:          cfg,
+		clock:        clock,
+		client:       &http.Client{Timeout: securityWebhookTimeout},
+		events:       make(chan SecurityEvent, securityEventBacklog),
+		authFailures: make(map[types.AccountID][]time.Time),
+		ipRanges:     make(map[types.AccountID]map[string]struct{}),
+		lastFired:    make(map[securityEventKey]time.Time),
+	}
+}
+
+// AuthFailed records a failed authentication of the account, firing an event once the failures within the window
+// reach the threshold
+func (n *SecurityEventNotifier) AuthFailed(accountID types.AccountID, remoteAddress string) {
+	if n == nil || !n.tracked(accountID) {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	now := n.clock.Now()
+	// the failures out of the window are forgotten, along with the accounts without recent failures
+	for id, failures := range n.authFailures {
+		recent := failures[:0]
+		for _, failure := range failures {
+			if now.Sub(failure) < n.cfg.Window {
+				recent = append(recent, failure)
+			}
+		}
+		if len(recent) == 0 {
+			delete(n.authFailures, id)
+			continue
+		}
+		n.authFailures[id] = recent
+	}
+	failures := append(n.authFailures[accountID], now)
+	n.authFailures[accountID] = failures
+	if len(failures) < n.cfg.AuthFailures {
+		return
+	}
+	n.fire(SecurityEvent{
+		AccountID:     accountID,
+		Type:          SecurityEventAuthFailures,
+		RemoteAddress: remoteAddress,
+		Detail:        fmt.Sprintf("%v failed authentications within %v", len(failures), n.cfg.Window),
+	}, "")
+}
+
+// Connected records an authenticated connection of the account, firing an event if it comes from an IP range the
+// account did not connect from before. The first range of the account is not reported.
+func (n *SecurityEventNotifier) Connected(accountID types.AccountID, remoteAddress string) {
+	if n == nil || !n.tracked(accountID) {
+		return
+	}
+	ipRange, ok := ipRangeOf(remoteAddress)
+	if !ok {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	ranges, ok := n.ipRanges[accountID]
+	if !ok {
+		n.ipRanges[accountID] = map[string]struct{}{ipRange: {}}
+		return
+	}
+	if _, ok = ranges[ipRange]; ok {
+		return
+	}
+	if len(ranges) < maxIPRangesPerAccount {
+		ranges[ipRange] = struct{}{}
+	}
+	n.fire(SecurityEvent{
+		AccountID:     accountID,
+		Type:          SecurityEventNewIPRange,
+		RemoteAddress: remoteAddress,
+		Detail:        fmt.Sprintf("first connection from %v", ipRange),
+	}, ipRange)
+}
+
+// QuotaExhausted fires an event when the account runs out of the quota
+func (n *SecurityEventNotifier) QuotaExhausted(accountID types.AccountID, quota string) {
+	if n == nil || !n.tracked(accountID) {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.fire(SecurityEvent{
+		AccountID: accountID,
+		Type:      SecurityEventQuotaExhausted,
+		Detail:    fmt.Sprintf("%v exhausted", quota),
+	}, quota)
+}
+
+// PrivilegedMethod fires an event when the account calls a privileged method
+func (n *SecurityEventNotifier) PrivilegedMethod(accountID types.AccountID, remoteAddress string, method string) {
+	if n == nil || !n.tracked(accountID) {
+		return
+	}
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.fire(SecurityEvent{
+		AccountID:     accountID,
+		Type:          SecurityEventPrivilegedMethod,
+		RemoteAddress: remoteAddress,
+		Detail:        fmt.Sprintf("called %v", method),
+	}, method)
+}
+
+// Run posts the events to the webhooks until the context is done
+func (n *SecurityEventNotifier) Run(ctx context.Context) {
+	if n == nil {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.events:
+			n.send(event)
+		}
+	}
+}
+
+// tracked returns whether the events of the account are posted to a webhook
+func (n *SecurityEventNotifier) tracked(accountID types.AccountID) bool {
+	return n.webhook(accountID) != ""
+}
+
+func (n *SecurityEventNotifier) webhook(accountID types.AccountID) string {
+	if webhook, ok := n.cfg.Webhooks[accountID]; ok {
+		return webhook
+	}
+	return n.cfg.DefaultWebhook
+}
+
+// fire queues the event unless the same event was fired within the window, it must be called with the lock held
+func (n *SecurityEventNotifier) fire(event SecurityEvent, subject string) {
+	now := n.clock.Now()
+	key := securityEventKey{accountID: event.AccountID, eventType: event.Type, subject: subject}
+	if last, ok := n.lastFired[key]; ok && now.Sub(last) < n.cfg.Window {
+		return
+	}
+	n.lastFired[key] = now
+	for k, last := range n.lastFired {
+		if now.Sub(last) >= n.cfg.Window {
+			delete(n.lastFired, k)
+		}
+	}
+
+	event.Time = now
+	select {
+	case n.events <- event:
+	default:
+		log.Warnf("dropping %v security event of account %v, the webhook cannot keep up", event.Type, event.AccountID)
+	}
+}
+
+func (n *SecurityEventNotifier) send(event SecurityEvent) {
+	log.Warnf("security event %v of account %v: %v", event.Type, event.AccountID, event.Detail)
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("failed to marshal %v security event: %v", event.Type, err)
+		return
+	}
+	resp, err := n.client.Post(n.webhook(event.AccountID), "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("failed to post %v security event of account %v to the webhook: %v", event.Type, event.AccountID, err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		log.Errorf("failed to post %v security event of account %v to the webhook: status %v", event.Type, event.AccountID, resp.Status)
+	}
+}
+
+// ipRangeOf returns the /24 range of an IPv4 address or the /48 range of an IPv6 address
+func ipRangeOf(remoteAddress string) (string, bool) {
+	host, _, err := net.SplitHostPort(remoteAddress)
+	if err != nil {
+		host = remoteAddress
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String(), true
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String(), true
+}
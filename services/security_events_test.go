@@ -0,0 +1,102 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// firedSecurityEvents drains the events queued by the notifier
+func firedSecurityEvents(n *SecurityEventNotifier) []SecurityEvent {
+	var events []SecurityEvent
+	for {
+		select {
+		case event := <-n.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestSecurityEventNotifier(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	n := NewSecurityEventNotifier(SecurityEventsConfig{
+		Webhooks:     map[types.AccountID]string{"a": "http://localhost/a"},
+		AuthFailures: 3,
+		Window:       time.Minute,
+	}, clock)
+	require.NotNil(t, n)
+
+	// the failures are only reported once they reach the threshold within the window
+	n.AuthFailed("a", "1.2.3.4:1000")
+	n.AuthFailed("a", "1.2.3.4:1000")
+	assert.Empty(t, firedSecurityEvents(n))
+	clock.IncTime(time.Minute)
+	n.AuthFailed("a", "1.2.3.4:1000")
+	n.AuthFailed("a", "1.2.3.4:1000")
+	assert.Empty(t, firedSecurityEvents(n))
+	n.AuthFailed("a", "1.2.3.4:1000")
+	events := firedSecurityEvents(n)
+	require.Len(t, events, 1)
+	assert.Equal(t, SecurityEventAuthFailures, events[0].Type)
+	assert.Equal(t, types.AccountID("a"), events[0].AccountID)
+	// the event is not repeated within the window
+	n.AuthFailed("a", "1.2.3.4:1000")
+	assert.Empty(t, firedSecurityEvents(n))
+
+	// the first range is the baseline of the account
+	n.Connected("a", "1.2.3.4:1000")
+	n.Connected("a", "1.2.3.200:1000")
+	assert.Empty(t, firedSecurityEvents(n))
+	n.Connected("a", "5.6.7.8:1000")
+	events = firedSecurityEvents(n)
+	require.Len(t, events, 1)
+	assert.Equal(t, SecurityEventNewIPRange, events[0].Type)
+	assert.Equal(t, "first connection from 5.6.7.0/24", events[0].Detail)
+	n.Connected("a", "5.6.7.9:1000")
+	assert.Empty(t, firedSecurityEvents(n))
+
+	n.PrivilegedMethod("a", "1.2.3.4:1000", "blxr_denylist")
+	n.PrivilegedMethod("a", "1.2.3.4:1000", "blxr_denylist")
+	n.QuotaExhausted("a", "paid transaction burst limit")
+	events = firedSecurityEvents(n)
+	require.Len(t, events, 2)
+	assert.Equal(t, SecurityEventPrivilegedMethod, events[0].Type)
+	assert.Equal(t, SecurityEventQuotaExhausted, events[1].Type)
+	clock.IncTime(time.Minute)
+	n.PrivilegedMethod("a", "1.2.3.4:1000", "blxr_denylist")
+	assert.Len(t, firedSecurityEvents(n), 1)
+
+	// the accounts without a webhook are not tracked
+	n.QuotaExhausted("b", "paid transaction burst limit")
+	assert.Empty(t, firedSecurityEvents(n))
+
+	var none *SecurityEventNotifier
+	none.AuthFailed("a", "")
+	none.Connected("a", "1.2.3.4:1000")
+	none.QuotaExhausted("a", "")
+	none.PrivilegedMethod("a", "", "blxr_denylist")
+	assert.Nil(t, NewSecurityEventNotifier(SecurityEventsConfig{}, clock))
+}
+
+func TestParseSecurityWebhooks(t *testing.T) {
+	webhooks, defaultWebhook, err := ParseSecurityWebhooks([]string{"a=https://siem/a?key=1", "https://siem/default?key=2"})
+	require.NoError(t, err)
+	assert.Equal(t, map[types.AccountID]string{"a": "https://siem/a?key=1"}, webhooks)
+	assert.Equal(t, "https://siem/default?key=2", defaultWebhook)
+
+	for _, values := range [][]string{
+		{"a=siem"},
+		{"https://siem/1", "https://siem/2"},
+		{"a=https://siem/1", "a=https://siem/2"},
+	} {
+		_, _, err = ParseSecurityWebhooks(values)
+		assert.Error(t, err, values)
+	}
+}
@@ -28,6 +28,14 @@ const (
 	DateFormat = "2006-01-02T15:04:05.000000"
 )
 
+// SubscriptionDeliveryStats summarizes the notifications of a feed subscription
+type SubscriptionDeliveryStats struct {
+	Delivered    uint64  `json:"delivered"`
+	Dropped      uint64  `json:"dropped"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	BytesSent    uint64  `json:"bytes_sent"`
+}
+
 // Stats is used to generate STATS record for transactions
 type Stats interface {
 	AddBlockEvent(name string, source connections.Conn, blockHash, beaconBlockHash types.SHA256Hash, networkNum types.NetworkNum,
@@ -43,9 +51,10 @@ type Stats interface {
 		mevBuilderNames []string, frontrunning bool, uuid string, targetBlockNumber uint64, minTimestamp int, maxTimestamp int, bundlePrice int64, enforcePayout bool, sentPeers int, sentGatewayPeers int)
 	AddGatewayBundleEvent(name string, source connections.Conn, startTime time.Time, bundleHash string, networkNum types.NetworkNum,
 		mevBuilderNames []string, frontrunning bool, uuid string, targetBlockNumber uint64, minTimestamp int, maxTimestamp int, bundlePrice int64, enforcePayout bool)
-	LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier)
+	LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier, delivery SubscriptionDeliveryStats)
 	LogSDKInfo(blockchain, method, sourceCode, version string, accountID types.AccountID, feed types.FeedConnectionType, start, end time.Time)
 	BundleSentToRsyncStats(timestamp time.Time, bundleHash string, blockNumber string, uuid string, bundlePrice int64, enforcePayout bool)
+	LogClockSkew(offset time.Duration)
 }
 
 // NoStats is used to generate empty stats
@@ -82,7 +91,7 @@ func (NoStats) LogSubscribeStats(subscriptionID string, accountID types.AccountI
 }
 
 // LogUnsubscribeStats does nothing
-func (NoStats) LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier) {
+func (NoStats) LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier, delivery SubscriptionDeliveryStats) {
 }
 
 // LogSDKInfo does nothing
@@ -93,6 +102,10 @@ func (NoStats) LogSDKInfo(_, _, _, _ string, _ types.AccountID, _ types.FeedConn
 func (NoStats) BundleSentToRsyncStats(_ time.Time, _ string, _ string, _ string, _ int64, _ bool) {
 }
 
+// LogClockSkew does nothing
+func (NoStats) LogClockSkew(time.Duration) {
+}
+
 // FluentdStats struct that represents fluentd stats info
 type FluentdStats struct {
 	NodeID            types.NodeID
@@ -407,7 +420,7 @@ func (s FluentdStats) LogSubscribeStats(subscriptionID string, accountID types.A
 }
 
 // LogUnsubscribeStats generates a fluentd STATS event
-func (s FluentdStats) LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier) {
+func (s FluentdStats) LogUnsubscribeStats(subscriptionID string, feedName types.FeedType, networkNum types.NetworkNum, accountID types.AccountID, tierName sdnmessage.AccountTier, delivery SubscriptionDeliveryStats) {
 	now := time.Now()
 	record := unsubscribeRecord{
 		Type:           "subscriptions",
@@ -417,6 +430,7 @@ func (s FluentdStats) LogUnsubscribeStats(subscriptionID string, feedName types.
 		FeedName:       feedName,
 		AccountID:      accountID,
 		Tier:           tierName,
+		Delivery:       delivery,
 	}
 	s.LogToFluentD(record, now, "stats.subscriptions.events")
 }
@@ -458,3 +472,14 @@ func (s FluentdStats) BundleSentToRsyncStats(timestamp time.Time, bundleHash str
 
 	s.LogToFluentD(record, timestamp, "stats.bundles_sent_to_rsync")
 }
+
+// LogClockSkew generates a fluentd STATS event with the offset of the local clock beyond the skew threshold
+func (s FluentdStats) LogClockSkew(offset time.Duration) {
+	record := Record{
+		Type: "clockSkew",
+		Data: clockSkewRecord{
+			OffsetMs: float64(offset) / float64(time.Millisecond),
+		},
+	}
+	s.LogToFluentD(record, time.Now(), "stats.clock_skew")
+}
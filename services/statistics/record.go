@@ -137,13 +137,14 @@ type subscribeRecord struct {
 }
 
 type unsubscribeRecord struct {
-	SubscriptionID string                 `json:"subscription_id"`
-	Type           string                 `json:"type"`
-	Event          string                 `json:"event"`
-	FeedName       types.FeedType         `json:"feed_name"`
-	NetworkNum     types.NetworkNum       `json:"network_num"`
-	AccountID      types.AccountID        `json:"account_id"`
-	Tier           sdnmessage.AccountTier `json:"tier"`
+	SubscriptionID string                    `json:"subscription_id"`
+	Type           string                    `json:"type"`
+	Event          string                    `json:"event"`
+	FeedName       types.FeedType            `json:"feed_name"`
+	NetworkNum     types.NetworkNum          `json:"network_num"`
+	AccountID      types.AccountID           `json:"account_id"`
+	Tier           sdnmessage.AccountTier    `json:"tier"`
+	Delivery       SubscriptionDeliveryStats `json:"delivery"`
 }
 
 type sdkInfoRecord struct {
@@ -156,3 +157,7 @@ type sdkInfoRecord struct {
 	Start      string          `json:"start"`
 	End        string          `json:"end"`
 }
+
+type clockSkewRecord struct {
+	OffsetMs float64 `json:"offset_ms"`
+}
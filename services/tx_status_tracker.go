@@ -0,0 +1,254 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+const (
+	// MaxTrackedTxs is the number of txs a transactionStatus subscription can track at once
+	MaxTrackedTxs = 1000
+	// txStatusTTL is how long a tx is tracked, its reorg is only detected within it
+	txStatusTTL = time.Hour
+)
+
+// txStatusRanks orders the statuses of the lifecycle, a tx does not go back to a lower status unless it is reorged out
+var txStatusRanks = map[types.Status]int{
+	types.ReceivedByBDN: 1,
+	types.Propagated:    2,
+	types.Included:      3,
+}
+
+type trackedTx struct {
+	subscriptions map[string]struct{}
+	status        types.Status
+	blockNumber   uint64
+	blockHash     string
+	expiry        time.Time
+}
+
+// TxStatusTracker follows the lifecycle of the txs tracked by the transactionStatus subscriptions: received by the
+// BDN, propagated to the blockchain node, included in a block and reorged out of it. Each change is published as a
+// notification addressed to the subscriptions tracking the tx.
+type TxStatusTracker struct {
+	txStore TxStore
+	clock   utils.Clock
+	notify  func(types.Notification)
+
+	lock          sync.Mutex
+	txs           map[types.SHA256Hash]*trackedTx
+	subscriptions map[string]map[types.SHA256Hash]struct{}
+	// autoTrack are the subscriptions tracking the txs sent by each account
+	autoTrack map[types.AccountID]map[string]struct{}
+}
+
+// NewTxStatusTracker creates the tracker publishing the status notifications with notify
+func NewTxStatusTracker(txStore TxStore, clock utils.Clock, notify func(types.Notification)) *TxStatusTracker {
+	return &TxStatusTracker{
+		txStore:       txStore,
+		clock:         clock,
+		notify:        notify,
+		txs:           make(map[types.SHA256Hash]*trackedTx),
+		subscriptions: make(map[string]map[types.SHA256Hash]struct{}),
+		autoTrack:     make(map[types.AccountID]map[string]struct{}),
+	}
+}
+
+// Track tracks the txs for the subscription, and the txs later sent by the account if autoTrack is set. The txs
+// already known to the gateway are reported as received by the BDN to the subscription.
+func (t *TxStatusTracker) Track(subscriptionID string, accountID types.AccountID, hashes []types.SHA256Hash, autoTrack bool) {
+	if t == nil {
+		return
+	}
+
+	var notifications []types.Notification
+	t.lock.Lock()
+	for _, hash := range hashes {
+		if !t.track(subscriptionID, hash) {
+			continue
+		}
+		if t.txStore != nil && t.txStore.HasContent(hash) {
+			notifications = append(notifications, &types.TransactionStatusNotification{
+				SubscriptionIDs: []string{subscriptionID},
+				TransactionHash: hash.Format(true),
+				Status:          types.ReceivedByBDN,
+			})
+		}
+	}
+	if autoTrack {
+		if t.autoTrack[accountID] == nil {
+			t.autoTrack[accountID] = make(map[string]struct{})
+		}
+		t.autoTrack[accountID][subscriptionID] = struct{}{}
+	}
+	t.lock.Unlock()
+
+	t.publish(notifications)
+}
+
+// track adds the tx to the subscription, it returns false if the subscription already tracks as many txs as it can
+func (t *TxStatusTracker) track(subscriptionID string, hash types.SHA256Hash) bool {
+	hashes, ok := t.subscriptions[subscriptionID]
+	if !ok {
+		hashes = make(map[types.SHA256Hash]struct{})
+		t.subscriptions[subscriptionID] = hashes
+	}
+	if _, ok = hashes[hash]; !ok && len(hashes) >= MaxTrackedTxs {
+		return false
+	}
+	hashes[hash] = struct{}{}
+
+	tx, ok := t.txs[hash]
+	if !ok {
+		tx = &trackedTx{subscriptions: make(map[string]struct{})}
+		t.txs[hash] = tx
+	}
+	tx.subscriptions[subscriptionID] = struct{}{}
+	tx.expiry = t.clock.Now().Add(txStatusTTL)
+	return true
+}
+
+// Untrack stops tracking the txs of the subscription
+func (t *TxStatusTracker) Untrack(subscriptionID string) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for hash := range t.subscriptions[subscriptionID] {
+		tx := t.txs[hash]
+		delete(tx.subscriptions, subscriptionID)
+		if len(tx.subscriptions) == 0 {
+			delete(t.txs, hash)
+		}
+	}
+	delete(t.subscriptions, subscriptionID)
+	for accountID, subscriptions := range t.autoTrack {
+		delete(subscriptions, subscriptionID)
+		if len(subscriptions) == 0 {
+			delete(t.autoTrack, accountID)
+		}
+	}
+}
+
+// Submitted tracks the tx sent by the account for the subscriptions auto tracking its txs
+func (t *TxStatusTracker) Submitted(accountID types.AccountID, hash types.SHA256Hash) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for subscriptionID := range t.autoTrack[accountID] {
+		t.track(subscriptionID, hash)
+	}
+}
+
+// ObserveTx records that the tx reached the status, which is either received by the BDN or propagated
+func (t *TxStatusTracker) ObserveTx(hash types.SHA256Hash, status types.Status) {
+	if t == nil {
+		return
+	}
+
+	var notifications []types.Notification
+	t.lock.Lock()
+	if tx, ok := t.txs[hash]; ok && txStatusRanks[status] > txStatusRanks[tx.status] {
+		tx.status = status
+		notifications = append(notifications, t.notification(hash, tx))
+	}
+	t.lock.Unlock()
+
+	t.publish(notifications)
+}
+
+// ObserveBlock records the inclusion of the tracked txs in the block, and reorgs out the txs of a previous block at
+// the same height which the block does not include
+func (t *TxStatusTracker) ObserveBlock(block *types.BxBlock) {
+	if t == nil || block == nil || block.Number == nil || !block.Number.IsUint64() {
+		return
+	}
+
+	number := block.Number.Uint64()
+	blockHash := block.Hash().Format(true)
+
+	var notifications []types.Notification
+	t.lock.Lock()
+	t.expire()
+	if len(t.txs) == 0 {
+		t.lock.Unlock()
+		return
+	}
+
+	included := make(map[types.SHA256Hash]struct{})
+	for _, blockTx := range block.Txs {
+		hash := blockTx.Hash()
+		tx, ok := t.txs[hash]
+		if !ok {
+			continue
+		}
+		included[hash] = struct{}{}
+		if tx.status == types.Included && tx.blockHash == blockHash {
+			continue
+		}
+		tx.status = types.Included
+		tx.blockNumber = number
+		tx.blockHash = blockHash
+		notifications = append(notifications, t.notification(hash, tx))
+	}
+
+	for hash, tx := range t.txs {
+		if _, ok := included[hash]; ok || tx.status != types.Included || tx.blockNumber != number || tx.blockHash == blockHash {
+			continue
+		}
+		tx.status = types.ReorgedOut
+		notifications = append(notifications, t.notification(hash, tx))
+	}
+	t.lock.Unlock()
+
+	t.publish(notifications)
+}
+
+// expire stops tracking the txs tracked for longer than the TTL
+func (t *TxStatusTracker) expire() {
+	now := t.clock.Now()
+	for hash, tx := range t.txs {
+		if now.Before(tx.expiry) {
+			continue
+		}
+		for subscriptionID := range tx.subscriptions {
+			delete(t.subscriptions[subscriptionID], hash)
+		}
+		delete(t.txs, hash)
+	}
+}
+
+func (t *TxStatusTracker) notification(hash types.SHA256Hash, tx *trackedTx) *types.TransactionStatusNotification {
+	subscriptionIDs := make([]string, 0, len(tx.subscriptions))
+	for subscriptionID := range tx.subscriptions {
+		subscriptionIDs = append(subscriptionIDs, subscriptionID)
+	}
+	sort.Strings(subscriptionIDs)
+
+	notification := &types.TransactionStatusNotification{
+		SubscriptionIDs: subscriptionIDs,
+		TransactionHash: hash.Format(true),
+		Status:          tx.status,
+	}
+	if tx.status == types.Included || tx.status == types.ReorgedOut {
+		notification.BlockNumber = tx.blockNumber
+		notification.BlockHash = tx.blockHash
+	}
+	return notification
+}
+
+// publish sends the notifications once the lock is released
+func (t *TxStatusTracker) publish(notifications []types.Notification) {
+	for _, notification := range notifications {
+		t.notify(notification)
+	}
+}
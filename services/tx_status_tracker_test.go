@@ -0,0 +1,123 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contentTxStore only knows the content of its hashes
+type contentTxStore struct {
+	TxStore
+	hashes map[types.SHA256Hash]struct{}
+}
+
+func (s contentTxStore) HasContent(hash types.SHA256Hash) bool {
+	_, ok := s.hashes[hash]
+	return ok
+}
+
+func txStatusTestBlock(t *testing.T, number int64, hash types.SHA256Hash, txs ...types.SHA256Hash) *types.BxBlock {
+	blockTxs := make([]*types.BxBlockTransaction, 0, len(txs))
+	for _, tx := range txs {
+		blockTxs = append(blockTxs, types.NewBxBlockTransaction(tx, nil))
+	}
+	block, err := types.NewBxBlock(hash, types.SHA256Hash{}, types.BxBlockTypeEth, nil, blockTxs, nil, big.NewInt(1), big.NewInt(number), 0)
+	require.NoError(t, err)
+	return block
+}
+
+func TestTxStatusTracker(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	known := types.SHA256Hash{1}
+	submitted := types.SHA256Hash{2}
+
+	var notifications []*types.TransactionStatusNotification
+	tracker := NewTxStatusTracker(contentTxStore{hashes: map[types.SHA256Hash]struct{}{known: {}}}, clock, func(notification types.Notification) {
+		notifications = append(notifications, notification.(*types.TransactionStatusNotification))
+	})
+
+	// the known tx is reported as received by the BDN right away
+	tracker.Track("sub1", "account", []types.SHA256Hash{known}, true)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, []string{"sub1"}, notifications[0].SubscriptionIDs)
+	assert.Equal(t, known.Format(true), notifications[0].TransactionHash)
+	assert.Equal(t, types.ReceivedByBDN, notifications[0].Status)
+
+	// the txs sent by the account are auto tracked, the txs of other accounts are not
+	tracker.Submitted("account", submitted)
+	tracker.Submitted("other", types.SHA256Hash{3})
+	tracker.ObserveTx(submitted, types.ReceivedByBDN)
+	tracker.ObserveTx(types.SHA256Hash{3}, types.ReceivedByBDN)
+	tracker.ObserveTx(submitted, types.Propagated)
+	// a lower status is not reported again
+	tracker.ObserveTx(submitted, types.ReceivedByBDN)
+	require.Len(t, notifications, 3)
+	assert.Equal(t, types.ReceivedByBDN, notifications[1].Status)
+	assert.Equal(t, types.Propagated, notifications[2].Status)
+
+	notifications = nil
+	tracker.ObserveBlock(txStatusTestBlock(t, 100, types.SHA256Hash{0xa}, known, submitted))
+	require.Len(t, notifications, 2)
+	for _, notification := range notifications {
+		assert.Equal(t, types.Included, notification.Status)
+		assert.Equal(t, uint64(100), notification.BlockNumber)
+		assert.Equal(t, types.SHA256Hash{0xa}.Format(true), notification.BlockHash)
+	}
+
+	// the block is replaced by a block including only one of the txs
+	notifications = nil
+	tracker.ObserveBlock(txStatusTestBlock(t, 100, types.SHA256Hash{0xb}, known))
+	require.Len(t, notifications, 2)
+	statuses := map[string]*types.TransactionStatusNotification{}
+	for _, notification := range notifications {
+		statuses[notification.TransactionHash] = notification
+	}
+	assert.Equal(t, types.Included, statuses[known.Format(true)].Status)
+	assert.Equal(t, types.SHA256Hash{0xb}.Format(true), statuses[known.Format(true)].BlockHash)
+	assert.Equal(t, types.ReorgedOut, statuses[submitted.Format(true)].Status)
+	assert.Equal(t, types.SHA256Hash{0xa}.Format(true), statuses[submitted.Format(true)].BlockHash)
+
+	// the next block includes the reorged out tx again
+	notifications = nil
+	tracker.ObserveBlock(txStatusTestBlock(t, 101, types.SHA256Hash{0xc}, submitted))
+	require.Len(t, notifications, 1)
+	assert.Equal(t, types.Included, notifications[0].Status)
+	assert.Equal(t, uint64(101), notifications[0].BlockNumber)
+
+	// the untracked txs are not reported
+	tracker.Untrack("sub1")
+	notifications = nil
+	tracker.ObserveBlock(txStatusTestBlock(t, 101, types.SHA256Hash{0xd}))
+	tracker.Submitted("account", types.SHA256Hash{4})
+	tracker.ObserveTx(types.SHA256Hash{4}, types.ReceivedByBDN)
+	assert.Empty(t, notifications)
+}
+
+func TestTxStatusTrackerExpiry(t *testing.T) {
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+	var notifications []types.Notification
+	tracker := NewTxStatusTracker(nil, clock, func(notification types.Notification) {
+		notifications = append(notifications, notification)
+	})
+
+	tracker.Track("sub1", "account", []types.SHA256Hash{{1}}, false)
+	assert.Empty(t, notifications)
+
+	clock.IncTime(txStatusTTL)
+	tracker.ObserveBlock(txStatusTestBlock(t, 1, types.SHA256Hash{0xa}, types.SHA256Hash{1}))
+	assert.Empty(t, notifications)
+
+	var nilTracker *TxStatusTracker
+	nilTracker.Track("sub1", "account", nil, true)
+	nilTracker.ObserveTx(types.SHA256Hash{1}, types.Propagated)
+	nilTracker.ObserveBlock(nil)
+	nilTracker.Untrack("sub1")
+}
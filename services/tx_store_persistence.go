@@ -0,0 +1,223 @@
+package services
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/cockroachdb/pebble"
+)
+
+const (
+	txStoreDirName = "txstore"
+	// txStorePersistenceQueueSize bounds the writes waiting for the disk, the writes of a full queue are dropped as the
+	// persistence is a best effort to shorten the re-sync after a restart
+	txStorePersistenceQueueSize = 100000
+	// txStorePersistenceBatchSize is the largest number of writes committed to the disk at once
+	txStorePersistenceBatchSize = 1000
+)
+
+// txStoreKeyPrefix prefixes the keys of the transactions, by their hash
+var txStoreKeyPrefix = []byte("tx/")
+
+// TxStorePersistence keeps the transactions of a BxTxStore on disk, so the mappings of their short IDs survive a
+// restart of the gateway. The writes are asynchronous.
+type TxStorePersistence interface {
+	// Store writes the current state of the transaction
+	Store(tx *types.BxTransaction)
+	// Delete removes the transaction of the hash
+	Delete(hash types.SHA256Hash)
+	// Load calls keep with each stored transaction, the transactions it rejects are pruned from the disk, then it
+	// compacts the disk
+	Load(keep func(tx *types.BxTransaction) bool) (loaded int, pruned int, err error)
+	// Close flushes the pending writes and closes the disk
+	Close() error
+}
+
+type txStoreWrite struct {
+	hash types.SHA256Hash
+	// tx is nil for a deletion
+	tx *types.BxTransaction
+}
+
+// pebbleTxStorePersistence implements TxStorePersistence with pebble
+type pebbleTxStorePersistence struct {
+	db      *pebble.DB
+	queue   chan txStoreWrite
+	stopped chan struct{}
+}
+
+// NewPebbleTxStorePersistence opens the persisted TxStore in the data directory
+func NewPebbleTxStorePersistence(datadir string) (TxStorePersistence, error) {
+	db, err := pebble.Open(path.Join(datadir, txStoreDirName), &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the persisted TxStore: %v", err)
+	}
+
+	p := &pebbleTxStorePersistence{
+		db:      db,
+		queue:   make(chan txStoreWrite, txStorePersistenceQueueSize),
+		stopped: make(chan struct{}),
+	}
+	go p.writeWorker()
+	return p, nil
+}
+
+// Store queues the write of the transaction
+func (p *pebbleTxStorePersistence) Store(tx *types.BxTransaction) {
+	p.enqueue(txStoreWrite{hash: tx.Hash(), tx: tx})
+}
+
+// Delete queues the deletion of the transaction
+func (p *pebbleTxStorePersistence) Delete(hash types.SHA256Hash) {
+	p.enqueue(txStoreWrite{hash: hash})
+}
+
+func (p *pebbleTxStorePersistence) enqueue(write txStoreWrite) {
+	select {
+	case p.queue <- write:
+	default:
+		log.Tracef("TxStore persistence queue is full, dropping the write of transaction %v", write.hash)
+	}
+}
+
+// writeWorker commits the queued writes in batches until the queue is closed
+func (p *pebbleTxStorePersistence) writeWorker() {
+	defer close(p.stopped)
+
+	for write := range p.queue {
+		batch := p.db.NewBatch()
+		p.apply(batch, write)
+		for pending := len(p.queue); pending > 0 && batch.Count() < txStorePersistenceBatchSize; pending-- {
+			p.apply(batch, <-p.queue)
+		}
+		if err := batch.Commit(pebble.NoSync); err != nil {
+			log.Errorf("failed to persist %v TxStore writes: %v", batch.Count(), err)
+		}
+		_ = batch.Close()
+	}
+}
+
+func (p *pebbleTxStorePersistence) apply(batch *pebble.Batch, write txStoreWrite) {
+	key := txStoreKey(write.hash)
+	if write.tx == nil {
+		_ = batch.Delete(key, nil)
+		return
+	}
+	// the transaction is encoded when written, so the latest of its queued states is persisted
+	_ = batch.Set(key, encodeTxStoreValue(write.tx), nil)
+}
+
+// Load calls keep with each stored transaction, prunes the rejected ones and compacts the disk
+func (p *pebbleTxStorePersistence) Load(keep func(tx *types.BxTransaction) bool) (loaded int, pruned int, err error) {
+	iter := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: txStoreKeyPrefix,
+		UpperBound: txStoreKeyUpperBound(),
+	})
+
+	batch := p.db.NewBatch()
+	defer func() { _ = batch.Close() }()
+	for iter.First(); iter.Valid(); iter.Next() {
+		tx, decodeErr := decodeTxStoreValue(iter.Key()[len(txStoreKeyPrefix):], iter.Value())
+		if decodeErr == nil && keep(tx) {
+			loaded++
+			continue
+		}
+		if decodeErr != nil {
+			log.Debugf("pruning undecodable persisted transaction: %v", decodeErr)
+		}
+		if err = batch.Delete(append([]byte(nil), iter.Key()...), nil); err != nil {
+			_ = iter.Close()
+			return loaded, pruned, err
+		}
+		pruned++
+	}
+	if err = iter.Close(); err != nil {
+		return loaded, pruned, err
+	}
+	if err = batch.Commit(pebble.Sync); err != nil {
+		return loaded, pruned, err
+	}
+
+	// the pruned transactions are dropped from the disk before the new ones are written
+	err = p.db.Compact(txStoreKeyPrefix, txStoreKeyUpperBound(), true)
+	return loaded, pruned, err
+}
+
+// Close flushes the queued writes and closes the disk
+func (p *pebbleTxStorePersistence) Close() error {
+	close(p.queue)
+	<-p.stopped
+	return p.db.Close()
+}
+
+func txStoreKey(hash types.SHA256Hash) []byte {
+	key := make([]byte, 0, len(txStoreKeyPrefix)+types.SHA256HashLen)
+	key = append(key, txStoreKeyPrefix...)
+	return append(key, hash[:]...)
+}
+
+func txStoreKeyUpperBound() []byte {
+	upperBound := append([]byte(nil), txStoreKeyPrefix...)
+	upperBound[len(upperBound)-1]++
+	return upperBound
+}
+
+// encodeTxStoreValue encodes the network, flags, add time, sender, short IDs and content of the transaction
+func encodeTxStoreValue(tx *types.BxTransaction) []byte {
+	shortIDs := tx.ShortIDs()
+	content := tx.Content()
+	sender := tx.Sender()
+
+	value := make([]byte, 0, types.UInt32Len+types.UInt16Len+types.UInt64Len+len(sender)+types.UInt32Len+types.UInt32Len*len(shortIDs)+len(content))
+	value = binary.LittleEndian.AppendUint32(value, uint32(tx.NetworkNum()))
+	value = binary.LittleEndian.AppendUint16(value, uint16(tx.Flags()))
+	value = binary.LittleEndian.AppendUint64(value, uint64(tx.AddTime().UnixNano()))
+	value = append(value, sender[:]...)
+	value = binary.LittleEndian.AppendUint32(value, uint32(len(shortIDs)))
+	for _, shortID := range shortIDs {
+		value = binary.LittleEndian.AppendUint32(value, uint32(shortID))
+	}
+	return append(value, content...)
+}
+
+func decodeTxStoreValue(key []byte, value []byte) (*types.BxTransaction, error) {
+	var hash types.SHA256Hash
+	if len(key) != len(hash) {
+		return nil, fmt.Errorf("invalid hash length %v", len(key))
+	}
+	copy(hash[:], key)
+
+	var sender types.Sender
+	headerLen := types.UInt32Len + types.UInt16Len + types.UInt64Len + len(sender) + types.UInt32Len
+	if len(value) < headerLen {
+		return nil, errors.New("value is too short")
+	}
+	networkNum := types.NetworkNum(binary.LittleEndian.Uint32(value))
+	offset := types.UInt32Len
+	flags := types.TxFlags(binary.LittleEndian.Uint16(value[offset:]))
+	offset += types.UInt16Len
+	addTime := time.Unix(0, int64(binary.LittleEndian.Uint64(value[offset:])))
+	offset += types.UInt64Len
+	copy(sender[:], value[offset:])
+	offset += len(sender)
+	shortIDsLen := int(binary.LittleEndian.Uint32(value[offset:]))
+	offset += types.UInt32Len
+	if len(value) < offset+types.UInt32Len*shortIDsLen {
+		return nil, fmt.Errorf("value is too short for %v short IDs", shortIDsLen)
+	}
+
+	tx := types.NewBxTransaction(hash, networkNum, flags, addTime)
+	tx.SetSender(sender)
+	for i := 0; i < shortIDsLen; i++ {
+		tx.AddShortID(types.ShortID(binary.LittleEndian.Uint32(value[offset:])))
+		offset += types.UInt32Len
+	}
+	// SetContent copies the content out of the value owned by the iterator
+	tx.SetContent(value[offset:])
+	return tx, nil
+}
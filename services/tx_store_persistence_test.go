@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBxTxStore_Recover(t *testing.T) {
+	datadir := t.TempDir()
+	clock := utils.MockClock{}
+	clock.SetTime(time.Unix(1000, 0))
+	newStore := func() BxTxStore {
+		return newBxTxStore(&clock, 30*time.Second, 30*time.Second, 10*time.Second,
+			NewEmptyShortIDAssigner(), NewHashHistory("seenTxs", 30*time.Minute), nil, 30*time.Minute, NoOpBloomFilter{})
+	}
+
+	persistence, err := NewPebbleTxStorePersistence(datadir)
+	require.NoError(t, err)
+	store := newStore()
+	require.NoError(t, store.Recover(persistence))
+
+	sender := types.Sender{7}
+	hash1 := types.SHA256Hash{1}
+	store.Add(hash1, types.TxContent{1, 2, 3}, 1, testNetworkNum, false, types.TFPaidTx, clock.Now(), testChainID, sender)
+	store.Add(hash1, nil, 11, testNetworkNum, false, types.TFPaidTx, clock.Now(), testChainID, sender)
+	// the transaction without a short ID expires before the ones with short IDs
+	hash2 := types.SHA256Hash{2}
+	store.Add(hash2, types.TxContent{2}, types.ShortIDEmpty, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	// the removed transaction is deleted from the disk
+	hash3 := types.SHA256Hash{3}
+	store.Add(hash3, types.TxContent{3}, 3, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	store.RemoveHashes(&types.SHA256HashList{hash3}, NoReEntryProtection, "test")
+	// the transaction added before the last one expires first
+	clock.IncTime(5 * time.Second)
+	hash4 := types.SHA256Hash{4}
+	store.Add(hash4, nil, 4, testNetworkNum, false, 0, clock.Now(), testChainID, types.EmptySender)
+	require.NoError(t, persistence.Close())
+
+	// the short IDs survive the restart
+	clock.IncTime(15 * time.Second)
+	persistence, err = NewPebbleTxStorePersistence(datadir)
+	require.NoError(t, err)
+	store = newStore()
+	require.NoError(t, store.Recover(persistence))
+	assert.Equal(t, 2, store.Count())
+
+	tx, err := store.GetTxByShortID(11)
+	require.NoError(t, err)
+	assert.Equal(t, hash1, tx.Hash())
+	assert.Equal(t, types.TxContent{1, 2, 3}, tx.Content())
+	assert.Equal(t, types.ShortIDList{1, 11}, tx.ShortIDs())
+	assert.Equal(t, types.TFPaidTx, tx.Flags())
+	assert.Equal(t, testNetworkNum, tx.NetworkNum())
+	assert.Equal(t, sender, tx.Sender())
+	assert.True(t, clock.Now().Add(-20*time.Second).Equal(tx.AddTime()))
+
+	_, exists := store.Get(hash2)
+	assert.False(t, exists)
+	_, err = store.GetTxByShortID(3)
+	assert.Error(t, err)
+	_, err = store.GetTxByShortID(4)
+	assert.NoError(t, err)
+	require.NoError(t, persistence.Close())
+
+	// the expired transactions were pruned from the disk at the last recovery
+	clock.IncTime(12 * time.Second)
+	persistence, err = NewPebbleTxStorePersistence(datadir)
+	require.NoError(t, err)
+	loaded, pruned, err := persistence.Load(func(tx *types.BxTransaction) bool { return true })
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded)
+	assert.Equal(t, 0, pruned)
+	store = newStore()
+	require.NoError(t, store.Recover(persistence))
+	assert.Equal(t, 1, store.Count())
+	require.NoError(t, persistence.Close())
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
+	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+)
+
+const validatorSnapshotFileName = "validators.json"
+
+// ValidatorSnapshot persists the next validators and the validator status of the gateway in its data dir, so that
+// after a restart the next_validator txs are accepted before a new block and a new validator update are received
+type ValidatorSnapshot struct {
+	path               string
+	maxAge             time.Duration
+	clock              utils.Clock
+	nextValidatorMap   *orderedmap.OrderedMap
+	validatorStatusMap *syncmap.SyncMap[string, bool]
+	log                *log.Entry
+}
+
+type validatorSnapshotFile struct {
+	SavedAt         time.Time           `json:"saved_at"`
+	NextValidators  []snapshotValidator `json:"next_validators"`
+	ValidatorStatus map[string]bool     `json:"validator_status"`
+}
+
+type snapshotValidator struct {
+	BlockHeight uint64 `json:"block_height"`
+	WalletID    string `json:"wallet_id"`
+}
+
+// NewValidatorSnapshot creates a ValidatorSnapshot of the maps, the snapshots older than maxAge are not loaded as the
+// next validators they hold are outdated
+func NewValidatorSnapshot(datadir string, maxAge time.Duration, clock utils.Clock, nextValidatorMap *orderedmap.OrderedMap, validatorStatusMap *syncmap.SyncMap[string, bool]) *ValidatorSnapshot {
+	return &ValidatorSnapshot{
+		path:               path.Join(datadir, validatorSnapshotFileName),
+		maxAge:             maxAge,
+		clock:              clock,
+		nextValidatorMap:   nextValidatorMap,
+		validatorStatusMap: validatorStatusMap,
+		log:                log.WithField("component", "validatorSnapshot"),
+	}
+}
+
+// Load fills the maps from the last snapshot, returning false if there is no recent enough snapshot
+func (s *ValidatorSnapshot) Load() (bool, error) {
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read validator snapshot: %v", err)
+	}
+
+	var snapshot validatorSnapshotFile
+	if err = json.Unmarshal(content, &snapshot); err != nil {
+		return false, fmt.Errorf("failed to decode validator snapshot %v: %v", s.path, err)
+	}
+	if age := s.clock.Now().Sub(snapshot.SavedAt); age > s.maxAge {
+		s.log.Infof("ignoring validator snapshot saved %v ago", age)
+		return false, nil
+	}
+
+	for _, validator := range snapshot.NextValidators {
+		s.nextValidatorMap.Set(validator.BlockHeight, validator.WalletID)
+	}
+	for wallet, accessible := range snapshot.ValidatorStatus {
+		s.validatorStatusMap.Store(wallet, accessible)
+	}
+	s.log.Infof("loaded %v next validators and the status of %v validators saved at %v", len(snapshot.NextValidators), len(snapshot.ValidatorStatus), snapshot.SavedAt)
+	return true, nil
+}
+
+// Store writes the current content of the maps
+func (s *ValidatorSnapshot) Store() error {
+	snapshot := validatorSnapshotFile{
+		SavedAt:         s.clock.Now(),
+		ValidatorStatus: make(map[string]bool),
+	}
+	for pair := s.nextValidatorMap.Oldest(); pair != nil; pair = pair.Next() {
+		snapshot.NextValidators = append(snapshot.NextValidators, snapshotValidator{
+			BlockHeight: pair.Key.(uint64),
+			WalletID:    pair.Value.(string),
+		})
+	}
+	s.validatorStatusMap.Range(func(wallet string, accessible bool) bool {
+		snapshot.ValidatorStatus[wallet] = accessible
+		return true
+	})
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	// the snapshot is replaced at once, a restart during the write keeps the previous one
+	tmpPath := s.path + ".tmp"
+	if err = os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// Run stores the maps every interval and once more when the context is done
+func (s *ValidatorSnapshot) Run(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Store(); err != nil {
+				s.log.Errorf("failed to store validator snapshot: %v", err)
+			}
+			return
+		case <-ticker.Alert():
+			if err := s.Store(); err != nil {
+				s.log.Errorf("failed to store validator snapshot: %v", err)
+			}
+		}
+	}
+}
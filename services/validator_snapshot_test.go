@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/bloXroute-Labs/gateway/v2/utils/orderedmap"
+	"github.com/bloXroute-Labs/gateway/v2/utils/syncmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	clock := &utils.MockClock{}
+	clock.SetTime(time.Unix(1700000000, 0))
+
+	nextValidatorMap := orderedmap.New()
+	nextValidatorMap.Set(uint64(101), "0xa")
+	nextValidatorMap.Set(uint64(102), "0xb")
+	validatorStatusMap := syncmap.NewStringMapOf[bool]()
+	validatorStatusMap.Store("0xa", true)
+	require.NoError(t, NewValidatorSnapshot(dir, time.Minute, clock, nextValidatorMap, validatorStatusMap).Store())
+
+	loadedNextValidatorMap := orderedmap.New()
+	loadedValidatorStatusMap := syncmap.NewStringMapOf[bool]()
+	snapshot := NewValidatorSnapshot(dir, time.Minute, clock, loadedNextValidatorMap, loadedValidatorStatusMap)
+	clock.IncTime(30 * time.Second)
+	loaded, err := snapshot.Load()
+	require.NoError(t, err)
+	assert.True(t, loaded)
+
+	assert.Equal(t, uint64(102), loadedNextValidatorMap.Newest().Key)
+	assert.Equal(t, "0xb", loadedNextValidatorMap.Newest().Value)
+	assert.Equal(t, "0xa", loadedNextValidatorMap.Newest().Prev().Value)
+	accessible, ok := loadedValidatorStatusMap.Load("0xa")
+	assert.True(t, ok)
+	assert.True(t, accessible)
+
+	// the next validators of an old snapshot are outdated
+	clock.IncTime(time.Minute)
+	loaded, err = NewValidatorSnapshot(dir, time.Minute, clock, orderedmap.New(), syncmap.NewStringMapOf[bool]()).Load()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+
+	loaded, err = NewValidatorSnapshot(t.TempDir(), time.Minute, clock, orderedmap.New(), syncmap.NewStringMapOf[bool]()).Load()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+}
@@ -0,0 +1,299 @@
+package wasmfilter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// limits of the filter modules of an account, the CPU time of a filter is limited by the timeout of each call
+const (
+	MaxModuleSize        = 64 * 1024
+	MaxModulesPerAccount = 5
+	memoryLimitPages     = 16 // 1 MiB
+	callTimeout          = 5 * time.Millisecond
+	// MaxBacklog is the number of notifications a subscription can be behind before its filter skips transactions,
+	// below the size of the subscription channels so the subscription is not closed for being too slow
+	MaxBacklog = 500
+)
+
+// exports of a filter module: the memory, alloc(size i32) ptr i32 returning a buffer for the input and
+// filter(ptr i32, len i32) i32 evaluating the JSON encoded field map of a transaction, a non zero result is a match
+const (
+	exportMemory = "memory"
+	exportAlloc  = "alloc"
+	exportFilter = "filter"
+)
+
+// ErrModuleNotFound is returned when the account did not upload a module with the name
+var ErrModuleNotFound = errors.New("wasm filter module not found")
+
+// Module is a compiled filter module uploaded by an account. Modules have no imports, so they run sandboxed without
+// access to the host.
+type Module struct {
+	name     string
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// Compile validates and compiles a filter module
+func Compile(ctx context.Context, name string, wasm []byte) (*Module, error) {
+	if len(wasm) > MaxModuleSize {
+		return nil, fmt.Errorf("wasm filter module is %v bytes, the maximum size is %v bytes", len(wasm), MaxModuleSize)
+	}
+
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(memoryLimitPages).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+
+	compiled, err := runtime.CompileModule(ctx, wasm)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("invalid wasm filter module: %v", err)
+	}
+
+	if err = validateModule(compiled); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	return &Module{name: name, runtime: runtime, compiled: compiled}, nil
+}
+
+func validateModule(compiled wazero.CompiledModule) error {
+	if len(compiled.ImportedFunctions()) > 0 || len(compiled.ImportedMemories()) > 0 {
+		return errors.New("wasm filter module cannot have imports")
+	}
+	if _, ok := compiled.ExportedMemories()[exportMemory]; !ok {
+		return fmt.Errorf("wasm filter module must export its %v", exportMemory)
+	}
+
+	functions := compiled.ExportedFunctions()
+	alloc, ok := functions[exportAlloc]
+	if !ok || !hasSignature(alloc, []api.ValueType{api.ValueTypeI32}) {
+		return fmt.Errorf("wasm filter module must export %v(size i32) i32", exportAlloc)
+	}
+	filter, ok := functions[exportFilter]
+	if !ok || !hasSignature(filter, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}) {
+		return fmt.Errorf("wasm filter module must export %v(ptr i32, len i32) i32", exportFilter)
+	}
+	return nil
+}
+
+func hasSignature(function api.FunctionDefinition, params []api.ValueType) bool {
+	results := function.ResultTypes()
+	if len(results) != 1 || results[0] != api.ValueTypeI32 || len(function.ParamTypes()) != len(params) {
+		return false
+	}
+	for i, param := range function.ParamTypes() {
+		if param != params[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Name returns the name of the module
+func (m *Module) Name() string {
+	return m.name
+}
+
+// Close releases the module, the filters of the module stop matching
+func (m *Module) Close() error {
+	return m.runtime.Close(context.Background())
+}
+
+// NewFilter creates an instance of the module, each subscription has its own instance
+func (m *Module) NewFilter() (*Filter, error) {
+	filter := &Filter{module: m}
+	if err := filter.instantiate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// Filter is an instance of a filter module evaluating transactions
+type Filter struct {
+	module   *Module
+	lock     sync.Mutex
+	instance api.Module
+	closed   bool
+	skipped  atomic.Uint64
+}
+
+func (f *Filter) instantiate() error {
+	// anonymous instances so the module can be instantiated for each subscription
+	instance, err := f.module.runtime.InstantiateModule(context.Background(), f.module.compiled, wazero.NewModuleConfig().WithName(""))
+	if err != nil {
+		return fmt.Errorf("failed to instantiate wasm filter module %v: %v", f.module.name, err)
+	}
+	f.instance = instance
+	return nil
+}
+
+// Shed returns true if the transaction should be skipped because the subscription is behind by more than MaxBacklog
+// notifications
+func (f *Filter) Shed(backlog int) bool {
+	if f == nil || backlog <= MaxBacklog {
+		return false
+	}
+	f.skipped.Add(1)
+	return true
+}
+
+// Skipped returns the number of transactions skipped by Shed
+func (f *Filter) Skipped() uint64 {
+	if f == nil {
+		return 0
+	}
+	return f.skipped.Load()
+}
+
+// reset closes the instance after a failed call, its state is unknown so the next call creates a new instance
+func (f *Filter) reset() {
+	if f.instance != nil {
+		_ = f.instance.Close(context.Background())
+		f.instance = nil
+	}
+}
+
+// Match evaluates the filter over the field map of the transaction
+func (f *Filter) Match(fields map[string]interface{}) (bool, error) {
+	input, err := json.Marshal(fields)
+	if err != nil {
+		return false, err
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		return false, fmt.Errorf("wasm filter %v is closed", f.module.name)
+	}
+	// the instance is reset when a call fails, a new instance is created for the next transaction
+	if f.instance == nil || f.instance.IsClosed() {
+		if err = f.instantiate(); err != nil {
+			return false, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	ptr, err := f.instance.ExportedFunction(exportAlloc).Call(ctx, uint64(len(input)))
+	if err != nil {
+		f.reset()
+		return false, fmt.Errorf("wasm filter %v alloc failed: %v", f.module.name, err)
+	}
+	if !f.instance.Memory().Write(uint32(ptr[0]), input) {
+		f.reset()
+		return false, fmt.Errorf("wasm filter %v returned an out of range buffer", f.module.name)
+	}
+
+	result, err := f.instance.ExportedFunction(exportFilter).Call(ctx, ptr[0], uint64(len(input)))
+	if err != nil {
+		f.reset()
+		return false, fmt.Errorf("wasm filter %v failed: %v", f.module.name, err)
+	}
+	return uint32(result[0]) != 0, nil
+}
+
+// Close releases the instance of the module
+func (f *Filter) Close() {
+	if f == nil {
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.closed = true
+	f.reset()
+}
+
+// Store keeps the filter modules uploaded by the accounts
+type Store struct {
+	lock    sync.RWMutex
+	modules map[types.AccountID]map[string]*Module
+}
+
+// NewStore creates an empty store
+func NewStore() *Store {
+	return &Store{modules: make(map[types.AccountID]map[string]*Module)}
+}
+
+// Upload compiles the module and stores it for the account, replacing the module with the same name
+func (s *Store) Upload(accountID types.AccountID, name string, wasm []byte) error {
+	if name == "" {
+		return errors.New("wasm filter module name cannot be empty")
+	}
+
+	module, err := Compile(context.Background(), name, wasm)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	modules, ok := s.modules[accountID]
+	if !ok {
+		modules = make(map[string]*Module)
+		s.modules[accountID] = modules
+	}
+	previous, replaced := modules[name]
+	if !replaced && len(modules) >= MaxModulesPerAccount {
+		_ = module.Close()
+		return fmt.Errorf("an account can upload up to %v wasm filter modules", MaxModulesPerAccount)
+	}
+	if replaced {
+		_ = previous.Close()
+	}
+	modules[name] = module
+	return nil
+}
+
+// Remove deletes the module of the account, the subscriptions using it stop receiving transactions
+func (s *Store) Remove(accountID types.AccountID, name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	module, ok := s.modules[accountID][name]
+	if !ok {
+		return ErrModuleNotFound
+	}
+	delete(s.modules[accountID], name)
+	return module.Close()
+}
+
+// Names returns the names of the modules of the account
+func (s *Store) Names(accountID types.AccountID) []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	names := make([]string, 0, len(s.modules[accountID]))
+	for name := range s.modules[accountID] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewFilter creates a filter from the module of the account
+func (s *Store) NewFilter(accountID types.AccountID, name string) (*Filter, error) {
+	s.lock.RLock()
+	module, ok := s.modules[accountID][name]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, ErrModuleNotFound
+	}
+	return module.NewFilter()
+}
@@ -0,0 +1,159 @@
+package wasmfilter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// function bodies of the test modules, without the local declarations
+var (
+	// alloc returns a buffer at offset 1024
+	allocBody = []byte{0x41, 0x80, 0x08, 0x0b}
+	// filter matches inputs longer than 20 bytes
+	lengthFilterBody = []byte{0x20, 0x01, 0x41, 0x14, 0x4b, 0x0b}
+	// filter never returns
+	loopFilterBody = []byte{0x03, 0x40, 0x0c, 0x00, 0x0b, 0x41, 0x00, 0x0b}
+)
+
+// testModule encodes a module exporting a memory of the pages, alloc and filter functions
+func testModule(memoryPages byte, filterBody []byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	code := func(body []byte) []byte {
+		return append([]byte{byte(len(body) + 1), 0x00}, body...)
+	}
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	// types: (i32) -> i32, (i32, i32) -> i32
+	module = append(module, section(0x01, 0x02, 0x60, 0x01, 0x7f, 0x01, 0x7f, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f)...)
+	// functions: alloc, filter
+	module = append(module, section(0x03, 0x02, 0x00, 0x01)...)
+	module = append(module, section(0x05, 0x01, 0x00, memoryPages)...)
+	exports := []byte{0x03}
+	exports = append(exports, append([]byte{0x06}, "memory"...)...)
+	exports = append(exports, 0x02, 0x00)
+	exports = append(exports, append([]byte{0x05}, "alloc"...)...)
+	exports = append(exports, 0x00, 0x00)
+	exports = append(exports, append([]byte{0x06}, "filter"...)...)
+	exports = append(exports, 0x00, 0x01)
+	module = append(module, section(0x07, exports...)...)
+	codes := append([]byte{0x02}, code(allocBody)...)
+	codes = append(codes, code(filterBody)...)
+	return append(module, section(0x0a, codes...)...)
+}
+
+func TestFilter_Match(t *testing.T) {
+	module, err := Compile(context.Background(), "length", testModule(1, lengthFilterBody))
+	require.NoError(t, err)
+	defer func() { _ = module.Close() }()
+
+	filter, err := module.NewFilter()
+	require.NoError(t, err)
+	defer filter.Close()
+
+	match, err := filter.Match(map[string]interface{}{"to": "0x0"})
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	match, err = filter.Match(map[string]interface{}{"to": "0xb877c7e556d50b0027053336b90f36becf67b3dd"})
+	require.NoError(t, err)
+	assert.True(t, match)
+}
+
+func TestFilter_CPULimit(t *testing.T) {
+	module, err := Compile(context.Background(), "loop", testModule(1, loopFilterBody))
+	require.NoError(t, err)
+	defer func() { _ = module.Close() }()
+
+	filter, err := module.NewFilter()
+	require.NoError(t, err)
+	defer filter.Close()
+
+	_, err = filter.Match(map[string]interface{}{"to": "0x0"})
+	assert.Error(t, err)
+
+	// a new instance is created after the call was interrupted
+	assert.Nil(t, filter.instance)
+	_, err = filter.Match(map[string]interface{}{"to": "0x0"})
+	assert.Error(t, err)
+}
+
+func TestFilter_Shed(t *testing.T) {
+	module, err := Compile(context.Background(), "length", testModule(1, lengthFilterBody))
+	require.NoError(t, err)
+	defer func() { _ = module.Close() }()
+
+	filter, err := module.NewFilter()
+	require.NoError(t, err)
+	defer filter.Close()
+
+	assert.False(t, filter.Shed(MaxBacklog))
+	assert.True(t, filter.Shed(MaxBacklog+1))
+	assert.Equal(t, uint64(1), filter.Skipped())
+
+	var nilFilter *Filter
+	assert.False(t, nilFilter.Shed(MaxBacklog+1))
+	assert.Zero(t, nilFilter.Skipped())
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	_, err := Compile(context.Background(), "invalid", []byte{1, 2, 3})
+	assert.Error(t, err)
+
+	_, err = Compile(context.Background(), "large", make([]byte, MaxModuleSize+1))
+	assert.Error(t, err)
+
+	// the memory of the module exceeds the memory limit
+	_, err = Compile(context.Background(), "memory", testModule(memoryLimitPages+1, lengthFilterBody))
+	assert.Error(t, err)
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	accountID := types.AccountID("account")
+
+	_, err := store.NewFilter(accountID, "length")
+	assert.Equal(t, ErrModuleNotFound, err)
+
+	require.NoError(t, store.Upload(accountID, "length", testModule(1, lengthFilterBody)))
+	require.NoError(t, store.Upload(accountID, "length", testModule(1, lengthFilterBody)))
+	assert.Equal(t, []string{"length"}, store.Names(accountID))
+	assert.Empty(t, store.Names("other"))
+
+	filter, err := store.NewFilter(accountID, "length")
+	require.NoError(t, err)
+	defer filter.Close()
+
+	for i := 1; i < MaxModulesPerAccount; i++ {
+		require.NoError(t, store.Upload(accountID, string(rune('a'+i)), testModule(1, lengthFilterBody)))
+	}
+	assert.Error(t, store.Upload(accountID, "extra", testModule(1, lengthFilterBody)))
+
+	require.NoError(t, store.Remove(accountID, "length"))
+	assert.Equal(t, ErrModuleNotFound, store.Remove(accountID, "length"))
+
+	// the filters of a removed module stop matching
+	_, err = filter.Match(map[string]interface{}{"to": "0xb877c7e556d50b0027053336b90f36becf67b3dd"})
+	assert.Error(t, err)
+}
+
+func TestFilter_Close(t *testing.T) {
+	module, err := Compile(context.Background(), "length", testModule(1, lengthFilterBody))
+	require.NoError(t, err)
+	defer func() { _ = module.Close() }()
+
+	filter, err := module.NewFilter()
+	require.NoError(t, err)
+	filter.Close()
+
+	_, err = filter.Match(map[string]interface{}{"to": "0xb877c7e556d50b0027053336b90f36becf67b3dd"})
+	assert.Error(t, err)
+
+	var nilFilter *Filter
+	nilFilter.Close()
+}
@@ -1,8 +1,13 @@
 package bxmock
 
 import (
+	"errors"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
 	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
 	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
 )
 
 // MockBxListener is a flexible struct that implements connections.BxListener
@@ -13,6 +18,36 @@ func (m MockBxListener) NodeStatus() connections.NodeStatus {
 	return connections.NodeStatus{}
 }
 
+// CanaryStatus returns no canary status
+func (m MockBxListener) CanaryStatus() *blockchain.CanaryStatus {
+	return nil
+}
+
+// BDNStatus returns an empty status
+func (m MockBxListener) BDNStatus() connections.BDNStatus {
+	return connections.BDNStatus{}
+}
+
+// ValidatorList returns no validator list
+func (m MockBxListener) ValidatorList(blockHeight uint64) (*blockchain.ValidatorListInfo, bool) {
+	return nil, false
+}
+
+// SlotTime returns an empty slot time
+func (m MockBxListener) SlotTime() utils.SlotTime {
+	return utils.SlotTime{}
+}
+
+// ClockSkew returns no skew
+func (m MockBxListener) ClockSkew() time.Duration {
+	return 0
+}
+
+// FeeHistory returns no fee history
+func (m MockBxListener) FeeHistory(blockCount, newestBlock uint64, rewardPercentiles []float64) (*blockchain.FeeHistory, error) {
+	return nil, errors.New("fee history is not available")
+}
+
 // HandleMsg does nothing
 func (m MockBxListener) HandleMsg(msg bxmessage.Message, conn connections.Conn, background connections.MsgHandlingOptions) error {
 	return nil
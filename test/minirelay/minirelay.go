@@ -0,0 +1,304 @@
+package minirelay
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/blockchain"
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/connections/handler"
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+)
+
+// receivedChannelSize - number of received messages buffered for the test, older messages are dropped when it is full
+const receivedChannelSize = 1000
+
+// MiniRelay is a minimal relay for integration tests. It accepts gateway connections, assigns short IDs to the
+// transactions it receives and propagates transactions and broadcasts to the connected gateways, so the compression
+// and propagation logic of gateways can be tested end to end without the BDN.
+type MiniRelay struct {
+	sslCerts *utils.SSLCerts
+	nodeID   types.NodeID
+	listener net.Listener
+
+	lock        sync.Mutex
+	conns       []*gatewayConn
+	txs         map[types.SHA256Hash]*bxmessage.Tx
+	shortIDs    map[types.ShortID]types.SHA256Hash
+	nextShortID types.ShortID
+
+	received chan bxmessage.Message
+}
+
+// New creates a mini relay presenting the provided certificates (e.g. utils.TestCerts) to the gateways
+func New(sslCerts *utils.SSLCerts, nodeID types.NodeID) *MiniRelay {
+	return &MiniRelay{
+		sslCerts:    sslCerts,
+		nodeID:      nodeID,
+		txs:         make(map[types.SHA256Hash]*bxmessage.Tx),
+		shortIDs:    make(map[types.ShortID]types.SHA256Hash),
+		nextShortID: 1,
+		received:    make(chan bxmessage.Message, receivedChannelSize),
+	}
+}
+
+// Start listens for gateway connections on the address, use port 0 to pick a free port
+func (r *MiniRelay) Start(addr string) error {
+	config, err := r.sslCerts.LoadPrivateConfig()
+	if err != nil {
+		return err
+	}
+	// gateway certificates are not verified, the node ID is still read from the certificate
+	config.ClientAuth = tls.RequireAnyClientCert
+
+	r.listener, err = tls.Listen("tcp", addr, config)
+	if err != nil {
+		return err
+	}
+	go r.accept()
+	return nil
+}
+
+// Addr returns the address the mini relay is listening on
+func (r *MiniRelay) Addr() *net.TCPAddr {
+	return r.listener.Addr().(*net.TCPAddr)
+}
+
+// Received returns the messages received from the gateways, except the handshake messages
+func (r *MiniRelay) Received() <-chan bxmessage.Message {
+	return r.received
+}
+
+// Connections returns the number of connected gateways
+func (r *MiniRelay) Connections() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.conns)
+}
+
+// ShortID returns the short ID assigned to the transaction, or types.ShortIDEmpty if it was not received
+func (r *MiniRelay) ShortID(hash types.SHA256Hash) types.ShortID {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	tx, ok := r.txs[hash]
+	if !ok {
+		return types.ShortIDEmpty
+	}
+	return tx.ShortID()
+}
+
+// Close stops accepting connections and closes the connections of the gateways
+func (r *MiniRelay) Close() error {
+	err := r.listener.Close()
+
+	r.lock.Lock()
+	conns := r.conns
+	r.conns = nil
+	r.lock.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.Close("mini relay closed")
+	}
+	return err
+}
+
+func (r *MiniRelay) accept() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		go r.handleConn(conn.(*tls.Conn))
+	}
+}
+
+func (r *MiniRelay) handleConn(tlsConn *tls.Conn) {
+	if err := tlsConn.Handshake(); err != nil {
+		log.Debugf("mini relay: TLS handshake with %v failed: %v", tlsConn.RemoteAddr(), err)
+		_ = tlsConn.Close()
+		return
+	}
+	socket := connections.NewTLSFromConn(tlsConn)
+	ip, _, _ := net.SplitHostPort(tlsConn.RemoteAddr().String())
+
+	conn := &gatewayConn{}
+	conn.BxConn = handler.NewBxConn(r,
+		func() (connections.Socket, error) {
+			return socket, nil
+		},
+		conn, r.sslCerts, ip, connections.RemoteInitiatedPort, r.nodeID, utils.ExternalGateway, true, false, true, false,
+		connections.RemoteInitiatedPort, utils.RealClock{}, true)
+
+	r.lock.Lock()
+	r.conns = append(r.conns, conn)
+	r.lock.Unlock()
+
+	_ = conn.Start()
+}
+
+// NodeStatus returns an empty status
+func (r *MiniRelay) NodeStatus() connections.NodeStatus {
+	return connections.NodeStatus{}
+}
+
+// CanaryStatus returns no canary status
+func (r *MiniRelay) CanaryStatus() *blockchain.CanaryStatus {
+	return nil
+}
+
+// BDNStatus returns an empty status
+func (r *MiniRelay) BDNStatus() connections.BDNStatus {
+	return connections.BDNStatus{}
+}
+
+// ValidatorList returns no validator list
+func (r *MiniRelay) ValidatorList(uint64) (*blockchain.ValidatorListInfo, bool) {
+	return nil, false
+}
+
+// SlotTime returns an empty slot time
+func (r *MiniRelay) SlotTime() utils.SlotTime {
+	return utils.SlotTime{}
+}
+
+// ClockSkew returns no skew
+func (r *MiniRelay) ClockSkew() time.Duration {
+	return 0
+}
+
+// FeeHistory returns no fee history
+func (r *MiniRelay) FeeHistory(uint64, uint64, []float64) (*blockchain.FeeHistory, error) {
+	return nil, errors.New("fee history is not available")
+}
+
+// HandleMsg handles the messages of the gateways
+func (r *MiniRelay) HandleMsg(msg bxmessage.Message, source connections.Conn, _ connections.MsgHandlingOptions) error {
+	switch typedMsg := msg.(type) {
+	case *bxmessage.Hello:
+		return nil
+	case *bxmessage.SyncReq:
+		// the mini relay does not keep transactions for new gateways, the sync is done right away
+		syncDone := &bxmessage.SyncDone{}
+		syncDone.SetNetworkNum(typedMsg.GetNetworkNum())
+		_ = source.Send(syncDone)
+	case *bxmessage.Tx:
+		r.handleTx(typedMsg)
+	case *bxmessage.GetTxs:
+		r.handleGetTxs(typedMsg, source)
+	case *bxmessage.Broadcast:
+		r.broadcast(typedMsg, source)
+	}
+
+	select {
+	case r.received <- msg:
+	default:
+	}
+	return nil
+}
+
+// handleTx assigns a short ID to a new transaction and sends it to all the gateways, including the source which
+// learns the short ID of its transaction this way
+func (r *MiniRelay) handleTx(tx *bxmessage.Tx) {
+	r.lock.Lock()
+	if _, ok := r.txs[tx.Hash()]; ok {
+		r.lock.Unlock()
+		return
+	}
+	relayTx := tx.Clone()
+	relayTx.SetShortID(r.nextShortID)
+	r.txs[tx.Hash()] = relayTx
+	r.shortIDs[r.nextShortID] = tx.Hash()
+	r.nextShortID++
+	r.lock.Unlock()
+
+	r.broadcast(relayTx, nil)
+}
+
+func (r *MiniRelay) handleGetTxs(getTxs *bxmessage.GetTxs, source connections.Conn) {
+	r.lock.Lock()
+	items := make([]bxmessage.TxsItem, 0, len(getTxs.ShortIDs))
+	for _, shortID := range getTxs.ShortIDs {
+		hash, ok := r.shortIDs[shortID]
+		if !ok {
+			continue
+		}
+		items = append(items, bxmessage.TxsItem{Hash: hash, Content: r.txs[hash].Content(), ShortID: shortID})
+	}
+	r.lock.Unlock()
+
+	_ = source.Send(bxmessage.NewTxs(items))
+}
+
+// broadcast sends the message to all the gateways except the source
+func (r *MiniRelay) broadcast(msg bxmessage.Message, source connections.Conn) {
+	r.lock.Lock()
+	conns := make([]*gatewayConn, len(r.conns))
+	copy(conns, r.conns)
+	r.lock.Unlock()
+
+	for _, conn := range conns {
+		if !conn.IsOpen() || source != nil && conn.ID() == source.ID() {
+			continue
+		}
+		_ = conn.Send(msg)
+	}
+}
+
+// OnConnEstablished does nothing
+func (r *MiniRelay) OnConnEstablished(connections.Conn) error {
+	return nil
+}
+
+// OnConnClosed removes the connection of the gateway
+func (r *MiniRelay) OnConnClosed(conn connections.Conn) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for i, c := range r.conns {
+		if c.ID() == conn.ID() {
+			r.conns = append(r.conns[:i], r.conns[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ValidateConnection accepts all the gateways
+func (r *MiniRelay) ValidateConnection(connections.Conn) error {
+	return nil
+}
+
+// gatewayConn is the connection of a gateway to the mini relay
+type gatewayConn struct {
+	*handler.BxConn
+}
+
+// ProcessMessage handles the messages that handler.BxConn does not pass to the mini relay
+func (c *gatewayConn) ProcessMessage(msgBytes bxmessage.MessageBytes) {
+	msg := msgBytes.Raw()
+	switch msgBytes.BxType() {
+	case bxmessage.TxType:
+		tx := &bxmessage.Tx{}
+		if err := tx.Unpack(msg, c.Protocol()); err != nil {
+			c.Log().Errorf("could not unpack tx message: %v", err)
+			return
+		}
+		_ = c.Node.HandleMsg(tx, c, connections.RunForeground)
+	case bxmessage.GetTransactionsType:
+		getTxs := &bxmessage.GetTxs{}
+		if err := getTxs.Unpack(msg, c.Protocol()); err != nil {
+			c.Log().Errorf("could not unpack gettxs message: %v", err)
+			return
+		}
+		_ = c.Node.HandleMsg(getTxs, c, connections.RunForeground)
+	default:
+		c.BxConn.ProcessMessage(msgBytes)
+	}
+}
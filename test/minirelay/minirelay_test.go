@@ -0,0 +1,106 @@
+package minirelay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bloXroute-Labs/gateway/v2/bxmessage"
+	"github.com/bloXroute-Labs/gateway/v2/connections"
+	"github.com/bloXroute-Labs/gateway/v2/connections/handler"
+	"github.com/bloXroute-Labs/gateway/v2/sdnmessage"
+	"github.com/bloXroute-Labs/gateway/v2/test/bxmock"
+	"github.com/bloXroute-Labs/gateway/v2/types"
+	"github.com/bloXroute-Labs/gateway/v2/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetworkNum types.NetworkNum = 5
+
+type testGateway struct {
+	bxmock.MockBxListener
+	msgs chan bxmessage.Message
+}
+
+func (g testGateway) HandleMsg(msg bxmessage.Message, _ connections.Conn, _ connections.MsgHandlingOptions) error {
+	g.msgs <- msg
+	return nil
+}
+
+func (g testGateway) expect(t *testing.T, match func(bxmessage.Message) bool) bxmessage.Message {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg := <-g.msgs:
+			if match(msg) {
+				return msg
+			}
+		case <-timeout:
+			require.FailNow(t, "expected message was not received")
+		}
+	}
+}
+
+func connectGateway(t *testing.T, relay *MiniRelay, certs *utils.SSLCerts, nodeID types.NodeID) (testGateway, *handler.Relay) {
+	gateway := testGateway{msgs: make(chan bxmessage.Message, 100)}
+	networks := sdnmessage.BlockchainNetworks{testNetworkNum: {NetworkNum: testNetworkNum}}
+	conn := handler.NewOutboundRelay(gateway, certs, "127.0.0.1", int64(relay.Addr().Port), nodeID, utils.RelayTransaction,
+		false, &networks, true, false, utils.RealClock{}, false, true)
+	conn.SetNetworkNum(testNetworkNum)
+	require.NoError(t, conn.Start())
+	t.Cleanup(func() { _ = conn.Close("test done") })
+
+	// the relay answers the tx store sync request of the gateway
+	gateway.expect(t, func(msg bxmessage.Message) bool {
+		_, ok := msg.(*bxmessage.SyncDone)
+		return ok
+	})
+	require.True(t, conn.IsOpen())
+	return gateway, conn
+}
+
+func TestMiniRelay(t *testing.T) {
+	certs := utils.TestCerts()
+	relay := New(&certs, "8c2d3b9e-5a0f-4d6b-9e7c-1f2a3b4c5d6e")
+	require.NoError(t, relay.Start("127.0.0.1:0"))
+	defer func() { _ = relay.Close() }()
+
+	gateway1, conn1 := connectGateway(t, relay, &certs, "0f54c509-06f0-4bdd-8fc0-3bdf1ac119ed")
+	gateway2, conn2 := connectGateway(t, relay, &certs, "6a1e2f3d-4c5b-4a69-8d7e-9f0a1b2c3d4e")
+	assert.Eventually(t, func() bool { return relay.Connections() == 2 }, time.Second, 10*time.Millisecond)
+
+	// transactions are assigned a short ID and propagated to all the gateways
+	hash := types.GenerateSHA256Hash()
+	require.NoError(t, conn1.Send(bxmessage.NewTx(hash, types.TxContent{1, 2, 3}, testNetworkNum, types.TFLocalRegion, types.EmptyAccountID)))
+
+	isTx := func(msg bxmessage.Message) bool {
+		tx, ok := msg.(*bxmessage.Tx)
+		return ok && tx.Hash() == hash
+	}
+	for _, gateway := range []testGateway{gateway1, gateway2} {
+		tx := gateway.expect(t, isTx).(*bxmessage.Tx)
+		assert.Equal(t, types.ShortID(1), tx.ShortID())
+		assert.Equal(t, []byte{1, 2, 3}, tx.Content())
+	}
+	assert.Equal(t, types.ShortID(1), relay.ShortID(hash))
+
+	// missing transactions of a compressed block are served by short ID
+	require.NoError(t, conn2.Send(&bxmessage.GetTxs{ShortIDs: types.ShortIDList{1, 2}}))
+	txs := gateway2.expect(t, func(msg bxmessage.Message) bool {
+		_, ok := msg.(*bxmessage.Txs)
+		return ok
+	}).(*bxmessage.Txs)
+	require.Len(t, txs.Items(), 1)
+	assert.Equal(t, hash, txs.Items()[0].Hash)
+	assert.Equal(t, types.ShortID(1), txs.Items()[0].ShortID)
+
+	// broadcasts are propagated to the other gateways
+	blockHash := types.GenerateSHA256Hash()
+	require.NoError(t, conn2.Send(bxmessage.NewBlockBroadcast(blockHash, types.EmptyHash, types.BxBlockTypeEth, []byte{4, 5, 6}, types.ShortIDList{1}, testNetworkNum)))
+	broadcast := gateway1.expect(t, func(msg bxmessage.Message) bool {
+		_, ok := msg.(*bxmessage.Broadcast)
+		return ok
+	}).(*bxmessage.Broadcast)
+	assert.Equal(t, blockHash, broadcast.Hash())
+	assert.Equal(t, types.ShortIDList{1}, broadcast.ShortIDs())
+}
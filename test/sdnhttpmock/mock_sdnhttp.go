@@ -132,6 +132,21 @@ func (mr *MockSDNHTTPMockRecorder) FetchCustomerAccountModel(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchCustomerAccountModel", reflect.TypeOf((*MockSDNHTTP)(nil).FetchCustomerAccountModel), arg0)
 }
 
+// FetchFeatureFlags mocks base method.
+func (m *MockSDNHTTP) FetchFeatureFlags() (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchFeatureFlags")
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchFeatureFlags indicates an expected call of FetchFeatureFlags.
+func (mr *MockSDNHTTPMockRecorder) FetchFeatureFlags() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchFeatureFlags", reflect.TypeOf((*MockSDNHTTP)(nil).FetchFeatureFlags))
+}
+
 // FindNetwork mocks base method.
 func (m *MockSDNHTTP) FindNetwork(arg0 types.NetworkNum) (*sdnmessage.BlockchainNetwork, error) {
 	m.ctrl.T.Helper()
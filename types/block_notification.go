@@ -256,7 +256,12 @@ func (beaconBlockNotification *BellatrixBlockNotification) Clone() BlockNotifica
 	return &n
 }
 
-// EthBlockNotification - represents a single block
+// EthBlockNotification - represents a single block. HeaderOnly is set on the early notification of a block whose
+// bodies are not known yet, which is followed by its full notification. Divergences are set while the feed
+// verification finds the BDN diverging from a secondary source. Backfilled is set on a block the gateway missed and
+// published after the blocks following it. FirstSource is the source the block was received from first, the BDN or
+// the blockchain node, and LeadTimeMs how much earlier once the block was received from the other source too.
+// Withdrawals and WithdrawalsRoot are set on the post-Shanghai blocks.
 type EthBlockNotification struct {
 	BlockHash        *ethcommon.Hash          `json:"hash,omitempty"`
 	Header           *Header                  `json:"header,omitempty"`
@@ -264,6 +269,12 @@ type EthBlockNotification struct {
 	Uncles           []Header                 `json:"uncles,omitempty"`
 	ValidatorInfo    []*FutureValidatorInfo   `json:"future_validator_info,omitempty"`
 	Withdrawals      ethtypes.Withdrawals     `json:"withdrawals,omitempty"`
+	WithdrawalsRoot  *ethcommon.Hash          `json:"withdrawals_root,omitempty"`
+	HeaderOnly       bool                     `json:"header_only,omitempty"`
+	Divergences      []*FeedDivergence        `json:"divergences,omitempty"`
+	Backfilled       bool                     `json:"backfilled,omitempty"`
+	FirstSource      string                   `json:"source,omitempty"`
+	LeadTimeMs       *int64                   `json:"lead_time_ms,omitempty"`
 	rawTransactions  [][]byte
 	notificationType FeedType
 	source           *NodeEndpoint
@@ -322,10 +333,28 @@ func NewEthBlockNotification(hash ethcommon.Hash, block *ethtypes.Block, info []
 		Uncles:          ethUncles,
 		ValidatorInfo:   info,
 		Withdrawals:     block.Withdrawals(),
+		WithdrawalsRoot: block.Header().WithdrawalsHash,
 		rawTransactions: rawTransactions,
 	}, nil
 }
 
+// NewEthBlockHeaderNotification creates the header only notification of an ETH block
+func NewEthBlockHeaderNotification(hash ethcommon.Hash, header *ethtypes.Header) (*EthBlockNotification, error) {
+	if hash == (ethcommon.Hash{}) {
+		return nil, errors.New("empty block hash")
+	}
+	if header == nil {
+		return nil, errors.New("empty block header")
+	}
+
+	return &EthBlockNotification{
+		BlockHash:       &hash,
+		Header:          ConvertEthHeaderToBlockNotificationHeader(header),
+		WithdrawalsRoot: header.WithdrawalsHash,
+		HeaderOnly:      true,
+	}, nil
+}
+
 // FutureValidatorInfo - represents information about the validator information of the second block after the current block
 type FutureValidatorInfo struct {
 	BlockHeight uint64 `json:"block_height"`
@@ -390,7 +419,8 @@ func ConvertEthHeaderToBlockNotificationHeader(ethHeader *ethtypes.Header) *Head
 
 // WithFields returns notification with specified fields
 func (ethBlockNotification *EthBlockNotification) WithFields(fields []string) Notification {
-	block := EthBlockNotification{}
+	block := EthBlockNotification{HeaderOnly: ethBlockNotification.HeaderOnly, Divergences: ethBlockNotification.Divergences, Backfilled: ethBlockNotification.Backfilled,
+		FirstSource: ethBlockNotification.FirstSource, LeadTimeMs: ethBlockNotification.LeadTimeMs}
 
 	for _, param := range fields {
 		switch param {
@@ -407,6 +437,8 @@ func (ethBlockNotification *EthBlockNotification) WithFields(fields []string) No
 			block.ValidatorInfo = ethBlockNotification.ValidatorInfo
 		case "withdrawals":
 			block.Withdrawals = ethBlockNotification.Withdrawals
+		case "withdrawals_root":
+			block.WithdrawalsRoot = ethBlockNotification.WithdrawalsRoot
 		}
 	}
 	return &block
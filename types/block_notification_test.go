@@ -2,10 +2,14 @@ package types
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
+	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -28,3 +32,22 @@ func TestCheckNonce(t *testing.T) {
 	nonce = fmt.Sprintf("0x%016s", hexutil.EncodeUint64(0)[2:])
 	assert.Equal(t, "0x0000000000000000", nonce)
 }
+
+func TestEthBlockHeaderNotification(t *testing.T) {
+	header := &ethtypes.Header{Number: big.NewInt(100), Difficulty: big.NewInt(1)}
+
+	_, err := NewEthBlockHeaderNotification(ethcommon.Hash{}, header)
+	assert.Error(t, err)
+
+	notification, err := NewEthBlockHeaderNotification(header.Hash(), header)
+	require.NoError(t, err)
+	assert.True(t, notification.HeaderOnly)
+	assert.Equal(t, "0x64", notification.Header.Number)
+	assert.Empty(t, notification.Transactions)
+
+	// the header only flag is kept whatever the included fields
+	withFields := notification.WithFields([]string{"hash"}).(*EthBlockNotification)
+	assert.True(t, withFields.HeaderOnly)
+	assert.Equal(t, header.Hash(), *withFields.BlockHash)
+	assert.Nil(t, withFields.Header)
+}
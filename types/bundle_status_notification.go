@@ -0,0 +1,79 @@
+package types
+
+// BundleStatus types of MEV bundle state
+type BundleStatus string
+
+// BundleStatus enumeration
+const (
+	// BundleSubmitted is the status of a bundle submitted through the gateway and sent to the BDN
+	BundleSubmitted BundleStatus = "submitted"
+	// BundleDispatched is the status of a bundle received from the BDN and sent to the builders by the gateway
+	BundleDispatched BundleStatus = "dispatched"
+	// BundleSimulated is the status of a bundle whose txs succeeded in its simulation on the blockchain node
+	BundleSimulated BundleStatus = "simulated"
+	// BundleSimulationFailed is the status of a bundle whose simulation failed or one of its txs reverted
+	BundleSimulationFailed BundleStatus = "simulation_failed"
+	// BundleLanded is the status of a bundle whose txs are included in a block
+	BundleLanded BundleStatus = "landed"
+	// BundleCancelled is the status of a bundle retracted from the builders with its UUID
+	BundleCancelled BundleStatus = "cancelled"
+)
+
+// BuilderReceived is the builder response of a bundle the builder accepted
+const BuilderReceived = "received"
+
+// BundleStatusNotification - represents the status of a MEV bundle. Builders holds the response of each builder the
+// bundle was dispatched to, received or the error of the builder. The block is set on the landed status.
+type BundleStatusNotification struct {
+	UUID            string            `json:"uuid,omitempty"`
+	BundleHash      string            `json:"bundle_hash,omitempty"`
+	Status          BundleStatus      `json:"status,omitempty"`
+	Builders        map[string]string `json:"builders,omitempty"`
+	SimulationError string            `json:"simulation_error,omitempty"`
+	BlockNumber     uint64            `json:"block_number,omitempty"`
+	BlockHash       string            `json:"block_hash,omitempty"`
+}
+
+// WithFields -
+func (n *BundleStatusNotification) WithFields(fields []string) Notification {
+	notification := BundleStatusNotification{}
+	for _, param := range fields {
+		switch param {
+		case "uuid":
+			notification.UUID = n.UUID
+		case "bundle_hash":
+			notification.BundleHash = n.BundleHash
+		case "status":
+			notification.Status = n.Status
+		case "builders":
+			notification.Builders = n.Builders
+		case "simulation_error":
+			notification.SimulationError = n.SimulationError
+		case "block_number":
+			notification.BlockNumber = n.BlockNumber
+		case "block_hash":
+			notification.BlockHash = n.BlockHash
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *BundleStatusNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *BundleStatusNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *BundleStatusNotification) GetHash() string {
+	return n.BundleHash
+}
+
+// NotificationType - feed name
+func (n *BundleStatusNotification) NotificationType() FeedType {
+	return BundleStatusFeed
+}
@@ -0,0 +1,70 @@
+package types
+
+// ContractCreationNotification - represents a contract deployment, a transaction without recipient, enriched after its
+// inclusion with the address of the created contract from its receipt
+type ContractCreationNotification struct {
+	TxHash          string      `json:"tx_hash,omitempty"`
+	From            interface{} `json:"from,omitempty"`
+	ContractAddress interface{} `json:"contract_address,omitempty"`
+	BlockHash       string      `json:"block_hash,omitempty"`
+	BlockNumber     string      `json:"block_number,omitempty"`
+	Status          string      `json:"status,omitempty"`
+	GasUsed         string      `json:"gas_used,omitempty"`
+}
+
+// NewContractCreationNotification returns a new ContractCreationNotification from the receipt of the deployment
+func NewContractCreationNotification(receipt *TxReceipt) *ContractCreationNotification {
+	return &ContractCreationNotification{
+		TxHash:          receipt.TransactionHash,
+		From:            receipt.From,
+		ContractAddress: receipt.ContractAddress,
+		BlockHash:       receipt.BlockHash,
+		BlockNumber:     receipt.BlockNumber,
+		Status:          receipt.Status,
+		GasUsed:         receipt.GasUsed,
+	}
+}
+
+// WithFields -
+func (n *ContractCreationNotification) WithFields(fields []string) Notification {
+	notification := ContractCreationNotification{}
+	for _, param := range fields {
+		switch param {
+		case "tx_hash":
+			notification.TxHash = n.TxHash
+		case "from":
+			notification.From = n.From
+		case "contract_address":
+			notification.ContractAddress = n.ContractAddress
+		case "block_hash":
+			notification.BlockHash = n.BlockHash
+		case "block_number":
+			notification.BlockNumber = n.BlockNumber
+		case "status":
+			notification.Status = n.Status
+		case "gas_used":
+			notification.GasUsed = n.GasUsed
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *ContractCreationNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *ContractCreationNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *ContractCreationNotification) GetHash() string {
+	return n.TxHash
+}
+
+// NotificationType - feed name
+func (n *ContractCreationNotification) NotificationType() FeedType {
+	return ContractCreationsFeed
+}
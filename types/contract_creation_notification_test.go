@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractCreationNotification(t *testing.T) {
+	receipt := NewTxReceipt(map[string]interface{}{
+		"blockHash":       "0xb10c",
+		"blockNumber":     "0x10",
+		"contractAddress": "0xc0de",
+		"from":            "0xf00d",
+		"gasUsed":         "0x5208",
+		"status":          "0x1",
+		"transactionHash": "0x7a",
+	}, "")
+
+	notification := NewContractCreationNotification(receipt)
+	assert.Equal(t, ContractCreationsFeed, notification.NotificationType())
+	assert.Equal(t, "0x7a", notification.GetHash())
+	assert.Equal(t, "0xc0de", notification.ContractAddress)
+	assert.Equal(t, "0xf00d", notification.From)
+
+	assert.Equal(t, &ContractCreationNotification{TxHash: "0x7a", ContractAddress: "0xc0de"},
+		notification.WithFields([]string{"tx_hash", "contract_address"}))
+}
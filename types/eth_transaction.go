@@ -131,6 +131,11 @@ func (et *EthTransaction) Type() uint8 {
 	return et.tx.Type()
 }
 
+// Data provides the calldata of the transaction
+func (et *EthTransaction) Data() []byte {
+	return et.tx.Data()
+}
+
 // Hash provides the transaction hash
 func (et *EthTransaction) Hash() SHA256Hash {
 	var hash SHA256Hash
@@ -0,0 +1,17 @@
+package types
+
+// FeedDivergence is a disagreement between the blocks of the BDN and a secondary source, the block notifications
+// published while it lasts are annotated with it
+type FeedDivergence struct {
+	// Source is the secondary source, either the blockchain node or the public RPC
+	Source string `json:"source"`
+	// Reason is either height, if the heights are further apart than the threshold, or hash, if the sources
+	// disagree on the hash of a block
+	Reason       string `json:"reason"`
+	BDNHeight    uint64 `json:"bdn_height"`
+	SourceHeight uint64 `json:"source_height"`
+	// BlockNumber, BDNHash and SourceHash are the block whose hashes differ
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	BDNHash     string `json:"bdn_hash,omitempty"`
+	SourceHash  string `json:"source_hash,omitempty"`
+}
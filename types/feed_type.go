@@ -12,6 +12,25 @@ const (
 	OnBlockFeed           FeedType = "ethOnBlock"
 	TxReceiptsFeed        FeedType = "txReceipts"
 	TransactionStatusFeed FeedType = "transactionStatus"
+	// TopOfBlockFeed publishes the first transactions of each block as soon as the block is received
+	TopOfBlockFeed FeedType = "topOfBlock"
+	// ContractCreationsFeed publishes the contract deployments of each block with the addresses of the created contracts
+	ContractCreationsFeed FeedType = "contractCreations"
+	// LogsFeed publishes the event logs of each block matching the address and topics of the subscription
+	LogsFeed FeedType = "logs"
+	// MEVShareFeed publishes the hints of the txs submitted with blxr_mev_share_tx, the txs themselves stay private
+	MEVShareFeed FeedType = "mevShare"
+	// BundleStatusFeed publishes the status of the MEV bundles submitted or dispatched by the gateway
+	BundleStatusFeed FeedType = "bundleStatus"
+	// ShortIDsFeed publishes the short IDs the relays assign to the txs, for debugging the compression of the blocks
+	ShortIDsFeed FeedType = "shortIDs"
+	// CombinedFeed multiplexes several feeds into a single websocket subscription
+	CombinedFeed FeedType = "combined"
+)
+
+// Polygon validators
+const (
+	NextSprintValidatorsFeed FeedType = "nextSprintValidators"
 )
 
 // FeedConnectionType types of feeds
@@ -21,12 +40,14 @@ type FeedConnectionType string
 const (
 	WebSocketFeed FeedConnectionType = "ws"
 	GRPCFeed      FeedConnectionType = "grpc"
+	InProcessFeed FeedConnectionType = "in-process"
 )
 
 // Beacon blocks
 const (
 	NewBeaconBlocksFeed FeedType = "newBeaconBlocks"
 	BDNBeaconBlocksFeed FeedType = "bdnBeaconBlocks"
+	ProposerDutiesFeed  FeedType = "proposerDuties"
 )
 
 // RPCStreamToFeedType maps gRPC stream to feed type
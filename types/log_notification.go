@@ -0,0 +1,85 @@
+package types
+
+// LogNotification - represents an event log of a block, as returned by eth_getLogs
+type LogNotification struct {
+	Address          string   `json:"address,omitempty"`
+	Topics           []string `json:"topics,omitempty"`
+	Data             string   `json:"data,omitempty"`
+	BlockHash        string   `json:"block_hash,omitempty"`
+	BlockNumber      string   `json:"block_number,omitempty"`
+	TransactionHash  string   `json:"transaction_hash,omitempty"`
+	TransactionIndex string   `json:"transaction_index,omitempty"`
+	LogIndex         string   `json:"log_index,omitempty"`
+	Removed          bool     `json:"removed,omitempty"`
+}
+
+// NewLogNotification returns a new LogNotification created from the log map of the node response
+func NewLogNotification(logMap map[string]interface{}) *LogNotification {
+	notification := LogNotification{}
+	notification.Address, _ = logMap["address"].(string)
+	notification.Data, _ = logMap["data"].(string)
+	notification.BlockHash, _ = logMap["blockHash"].(string)
+	notification.BlockNumber, _ = logMap["blockNumber"].(string)
+	notification.TransactionHash, _ = logMap["transactionHash"].(string)
+	notification.TransactionIndex, _ = logMap["transactionIndex"].(string)
+	notification.LogIndex, _ = logMap["logIndex"].(string)
+	notification.Removed, _ = logMap["removed"].(bool)
+
+	if topics, ok := logMap["topics"].([]interface{}); ok {
+		notification.Topics = make([]string, 0, len(topics))
+		for _, topic := range topics {
+			if topicStr, ok := topic.(string); ok {
+				notification.Topics = append(notification.Topics, topicStr)
+			}
+		}
+	}
+	return &notification
+}
+
+// WithFields -
+func (n *LogNotification) WithFields(fields []string) Notification {
+	notification := LogNotification{}
+	for _, param := range fields {
+		switch param {
+		case "address":
+			notification.Address = n.Address
+		case "topics":
+			notification.Topics = n.Topics
+		case "data":
+			notification.Data = n.Data
+		case "block_hash":
+			notification.BlockHash = n.BlockHash
+		case "block_number":
+			notification.BlockNumber = n.BlockNumber
+		case "transaction_hash":
+			notification.TransactionHash = n.TransactionHash
+		case "transaction_index":
+			notification.TransactionIndex = n.TransactionIndex
+		case "log_index":
+			notification.LogIndex = n.LogIndex
+		case "removed":
+			notification.Removed = n.Removed
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *LogNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *LogNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *LogNotification) GetHash() string {
+	return n.TransactionHash
+}
+
+// NotificationType - feed name
+func (n *LogNotification) NotificationType() FeedType {
+	return LogsFeed
+}
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogNotification(t *testing.T) {
+	notification := NewLogNotification(map[string]interface{}{
+		"address":          "0xc0de",
+		"topics":           []interface{}{"0xddf2", "0x01"},
+		"data":             "0x",
+		"blockHash":        "0xb10c",
+		"blockNumber":      "0x10",
+		"transactionHash":  "0x7a",
+		"transactionIndex": "0x0",
+		"logIndex":         "0x2",
+		"removed":          false,
+	})
+	assert.Equal(t, LogsFeed, notification.NotificationType())
+	assert.Equal(t, "0x7a", notification.GetHash())
+	assert.Equal(t, []string{"0xddf2", "0x01"}, notification.Topics)
+	assert.Equal(t, "0x2", notification.LogIndex)
+
+	assert.Equal(t, &LogNotification{Address: "0xc0de", Topics: []string{"0xddf2", "0x01"}},
+		notification.WithFields([]string{"address", "topics"}))
+}
@@ -0,0 +1,68 @@
+package types
+
+// MEVShare hint names, the hash of the tx is always shared
+const (
+	MEVShareHintCalldata         = "calldata"
+	MEVShareHintContractAddress  = "contract_address"
+	MEVShareHintFunctionSelector = "function_selector"
+	MEVShareHintLogs             = "logs"
+)
+
+// MEVShareHints are the hints shared unless the submission selects others
+var MEVShareHints = []string{MEVShareHintContractAddress, MEVShareHintFunctionSelector, MEVShareHintLogs}
+
+// MEVShareLog - represents an event log of a simulated private tx
+type MEVShareLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data,omitempty"`
+}
+
+// MEVShareNotification - represents the hint of a private tx, holding only the data its sender chose to share
+type MEVShareNotification struct {
+	Hash             string        `json:"hash,omitempty"`
+	To               string        `json:"to,omitempty"`
+	FunctionSelector string        `json:"function_selector,omitempty"`
+	CallData         string        `json:"calldata,omitempty"`
+	Logs             []MEVShareLog `json:"logs,omitempty"`
+}
+
+// WithFields -
+func (n *MEVShareNotification) WithFields(fields []string) Notification {
+	notification := MEVShareNotification{}
+	for _, param := range fields {
+		switch param {
+		case "hash":
+			notification.Hash = n.Hash
+		case "to":
+			notification.To = n.To
+		case "function_selector":
+			notification.FunctionSelector = n.FunctionSelector
+		case "calldata":
+			notification.CallData = n.CallData
+		case "logs":
+			notification.Logs = n.Logs
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *MEVShareNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *MEVShareNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *MEVShareNotification) GetHash() string {
+	return n.Hash
+}
+
+// NotificationType - feed name
+func (n *MEVShareNotification) NotificationType() FeedType {
+	return MEVShareFeed
+}
@@ -27,6 +27,8 @@ type NewTransactionNotification struct {
 	// lock is used to prevent parallel extract of sender address
 	// while not locking the other unrelated go routines.
 	lock *sync.Mutex
+	// origin is the peer gateway the transaction was received from, empty for local sources
+	origin string
 }
 
 // CreateNewTransactionNotification -  creates NewTransactionNotification object which contains bxTransaction and local region
@@ -36,6 +38,7 @@ func CreateNewTransactionNotification(bxTx *BxTransaction) *NewTransactionNotifi
 		nil,
 		TxPendingValidation,
 		&sync.Mutex{},
+		"",
 	}
 }
 
@@ -84,6 +87,16 @@ func (newTransactionNotification *NewTransactionNotification) WithFields(fields
 	return nil
 }
 
+// SetOrigin - sets the peer gateway the transaction was received from
+func (newTransactionNotification *NewTransactionNotification) SetOrigin(origin string) {
+	newTransactionNotification.origin = origin
+}
+
+// Origin - returns the peer gateway the transaction was received from, empty for local sources
+func (newTransactionNotification *NewTransactionNotification) Origin() string {
+	return newTransactionNotification.origin
+}
+
 // LocalRegion - returns the local region of the ethereum transaction
 func (newTransactionNotification *NewTransactionNotification) LocalRegion() bool {
 	return TFLocalRegion&newTransactionNotification.BxTransaction.Flags() != 0
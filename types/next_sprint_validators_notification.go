@@ -0,0 +1,51 @@
+package types
+
+import "strconv"
+
+// NextSprintValidatorsNotification - represents the producer set of an upcoming sprint
+type NextSprintValidatorsNotification struct {
+	BlockHeight   uint64   `json:"block_height,omitempty"`
+	ValidatorList []string `json:"validator_list,omitempty"`
+}
+
+// NewNextSprintValidatorsNotification returns a new NextSprintValidatorsNotification for the sprint starting at blockHeight
+func NewNextSprintValidatorsNotification(blockHeight uint64, validatorList []string) *NextSprintValidatorsNotification {
+	return &NextSprintValidatorsNotification{
+		BlockHeight:   blockHeight,
+		ValidatorList: validatorList,
+	}
+}
+
+// WithFields -
+func (n *NextSprintValidatorsNotification) WithFields(fields []string) Notification {
+	notification := NextSprintValidatorsNotification{}
+	for _, param := range fields {
+		switch param {
+		case "block_height":
+			notification.BlockHeight = n.BlockHeight
+		case "validator_list":
+			notification.ValidatorList = n.ValidatorList
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *NextSprintValidatorsNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *NextSprintValidatorsNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *NextSprintValidatorsNotification) GetHash() string {
+	return strconv.FormatUint(n.BlockHeight, 10)
+}
+
+// NotificationType - feed name
+func (n *NextSprintValidatorsNotification) NotificationType() FeedType {
+	return NextSprintValidatorsFeed
+}
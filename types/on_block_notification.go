@@ -1,5 +1,7 @@
 package types
 
+import "sort"
+
 // OnBlockNotification - represents the result of an RPC call on published block
 type OnBlockNotification struct {
 	Name        string `json:"name,omitempty"`
@@ -57,3 +59,82 @@ func (n *OnBlockNotification) GetHash() string {
 func (n *OnBlockNotification) NotificationType() FeedType {
 	return OnBlockFeed
 }
+
+// OnBlockPageNotification - represents a page of the results of the RPC calls on a published block. The results of a
+// block are ordered by call name and tag, Complete is set on the last page of the block.
+type OnBlockPageNotification struct {
+	BlockHeight string                 `json:"block_height,omitempty"`
+	Page        int                    `json:"page"`
+	Pages       int                    `json:"pages"`
+	Complete    bool                   `json:"complete"`
+	Results     []*OnBlockNotification `json:"results"`
+	hash        string
+}
+
+// NewOnBlockPages splits the results of the RPC calls on a block into pages of pageSize results. A single empty page
+// is returned if there are no results, so the completion of the block is always notified.
+func NewOnBlockPages(results []*OnBlockNotification, pageSize int, blockHeight string, hash string) []*OnBlockPageNotification {
+	sorted := make([]*OnBlockNotification, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Tag < sorted[j].Tag
+	})
+
+	if pageSize <= 0 || pageSize > len(sorted) {
+		pageSize = len(sorted)
+	}
+	pageCount := 1
+	if pageSize > 0 {
+		pageCount = (len(sorted) + pageSize - 1) / pageSize
+	}
+	pages := make([]*OnBlockPageNotification, 0, pageCount)
+	for page := 0; page < pageCount; page++ {
+		start := page * pageSize
+		end := start + pageSize
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		pages = append(pages, &OnBlockPageNotification{
+			BlockHeight: blockHeight,
+			Page:        page,
+			Pages:       pageCount,
+			Complete:    page == pageCount-1,
+			Results:     sorted[start:end],
+			hash:        hash,
+		})
+	}
+	return pages
+}
+
+// WithFields - the fields are applied to the results, the page fields are always included
+func (n *OnBlockPageNotification) WithFields(fields []string) Notification {
+	page := *n
+	page.Results = make([]*OnBlockNotification, 0, len(n.Results))
+	for _, result := range n.Results {
+		page.Results = append(page.Results, result.WithFields(fields).(*OnBlockNotification))
+	}
+	return &page
+}
+
+// Filters -
+func (n *OnBlockPageNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *OnBlockPageNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *OnBlockPageNotification) GetHash() string {
+	return n.hash
+}
+
+// NotificationType - feed name
+func (n *OnBlockPageNotification) NotificationType() FeedType {
+	return OnBlockFeed
+}
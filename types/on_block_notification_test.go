@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOnBlockPages(t *testing.T) {
+	results := []*OnBlockNotification{
+		NewOnBlockNotification("c", "0x3", "0x10", "0x10", "hash"),
+		NewOnBlockNotification("a", "0x1", "0x10", "0x11", "hash"),
+		NewOnBlockNotification("b", "0x2", "0x10", "0x10", "hash"),
+		NewOnBlockNotification("a", "0x0", "0x10", "0x10", "hash"),
+		NewOnBlockNotification("d", "0x4", "0x10", "0x10", "hash"),
+	}
+
+	pages := NewOnBlockPages(results, 2, "0x10", "hash")
+	require.Len(t, pages, 3)
+	var order []string
+	for i, page := range pages {
+		assert.Equal(t, i, page.Page)
+		assert.Equal(t, 3, page.Pages)
+		assert.Equal(t, i == 2, page.Complete)
+		assert.Equal(t, "hash", page.GetHash())
+		for _, result := range page.Results {
+			order = append(order, result.Response)
+		}
+	}
+	assert.Equal(t, []string{"0x0", "0x1", "0x2", "0x3", "0x4"}, order)
+
+	// the completion of a block without results is notified
+	pages = NewOnBlockPages(nil, 2, "0x10", "hash")
+	require.Len(t, pages, 1)
+	assert.True(t, pages[0].Complete)
+	assert.Empty(t, pages[0].Results)
+
+	page := pages[0].WithFields([]string{"name"}).(*OnBlockPageNotification)
+	assert.True(t, page.Complete)
+	page = NewOnBlockPages(results, 0, "0x10", "hash")[0].WithFields([]string{"name"}).(*OnBlockPageNotification)
+	require.Len(t, page.Results, 5)
+	assert.Equal(t, &OnBlockNotification{Name: "a"}, page.Results[0])
+}
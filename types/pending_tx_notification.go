@@ -15,6 +15,7 @@ func CreatePendingTransactionNotification(bxTx *BxTransaction) Notification {
 			nil,
 			TxPendingValidation,
 			&sync.Mutex{},
+			"",
 		},
 	}
 }
@@ -0,0 +1,59 @@
+package types
+
+import "strconv"
+
+// ProposerDutyNotification - represents the proposer of an upcoming beacon slot
+type ProposerDutyNotification struct {
+	Slot           uint64   `json:"slot,omitempty"`
+	ValidatorIndex uint64   `json:"validator_index,omitempty"`
+	Pubkey         string   `json:"pubkey,omitempty"`
+	Relays         []string `json:"relays,omitempty"`
+}
+
+// NewProposerDutyNotification returns a new ProposerDutyNotification
+func NewProposerDutyNotification(slot uint64, validatorIndex uint64, pubkey string, relays []string) *ProposerDutyNotification {
+	return &ProposerDutyNotification{
+		Slot:           slot,
+		ValidatorIndex: validatorIndex,
+		Pubkey:         pubkey,
+		Relays:         relays,
+	}
+}
+
+// WithFields -
+func (n *ProposerDutyNotification) WithFields(fields []string) Notification {
+	notification := ProposerDutyNotification{}
+	for _, param := range fields {
+		switch param {
+		case "slot":
+			notification.Slot = n.Slot
+		case "validator_index":
+			notification.ValidatorIndex = n.ValidatorIndex
+		case "pubkey":
+			notification.Pubkey = n.Pubkey
+		case "relays":
+			notification.Relays = n.Relays
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *ProposerDutyNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *ProposerDutyNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *ProposerDutyNotification) GetHash() string {
+	return strconv.FormatUint(n.Slot, 10)
+}
+
+// NotificationType - feed name
+func (n *ProposerDutyNotification) NotificationType() FeedType {
+	return ProposerDutiesFeed
+}
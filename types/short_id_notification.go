@@ -0,0 +1,53 @@
+package types
+
+// ShortIDNotification - represents the assignment of a short ID to a tx by the relay
+type ShortIDNotification struct {
+	TxHash     string     `json:"tx_hash,omitempty"`
+	ShortID    ShortID    `json:"short_id,omitempty"`
+	NetworkNum NetworkNum `json:"network_num,omitempty"`
+}
+
+// NewShortIDNotification returns a new ShortIDNotification
+func NewShortIDNotification(hash SHA256Hash, shortID ShortID, networkNum NetworkNum) *ShortIDNotification {
+	return &ShortIDNotification{
+		TxHash:     hash.Format(true),
+		ShortID:    shortID,
+		NetworkNum: networkNum,
+	}
+}
+
+// WithFields -
+func (n *ShortIDNotification) WithFields(fields []string) Notification {
+	notification := ShortIDNotification{}
+	for _, param := range fields {
+		switch param {
+		case "tx_hash":
+			notification.TxHash = n.TxHash
+		case "short_id":
+			notification.ShortID = n.ShortID
+		case "network_num":
+			notification.NetworkNum = n.NetworkNum
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *ShortIDNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *ShortIDNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *ShortIDNotification) GetHash() string {
+	return n.TxHash
+}
+
+// NotificationType - feed name
+func (n *ShortIDNotification) NotificationType() FeedType {
+	return ShortIDsFeed
+}
@@ -0,0 +1,18 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortIDNotification(t *testing.T) {
+	notification := NewShortIDNotification(SHA256Hash{1}, 42, 5)
+	assert.Equal(t, ShortIDsFeed, notification.NotificationType())
+	assert.Equal(t, SHA256Hash{1}.Format(true), notification.GetHash())
+	assert.Equal(t, ShortID(42), notification.ShortID)
+	assert.Equal(t, NetworkNum(5), notification.NetworkNum)
+
+	assert.Equal(t, &ShortIDNotification{TxHash: SHA256Hash{1}.Format(true), ShortID: 42},
+		notification.WithFields([]string{"tx_hash", "short_id"}))
+}
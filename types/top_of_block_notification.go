@@ -0,0 +1,119 @@
+package types
+
+import (
+	"math/big"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// TopOfBlockTx - represents a transaction at the top of a block
+type TopOfBlockTx struct {
+	Position int                `json:"position"`
+	TxHash   string             `json:"tx_hash"`
+	From     *ethcommon.Address `json:"from,omitempty"`
+	To       *ethcommon.Address `json:"to,omitempty"`
+	Type     uint8              `json:"type"`
+	Gas      uint64             `json:"gas"`
+	// GasPrice is the effective gas price paid in the block
+	GasPrice             *hexutil.Big `json:"gas_price"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"max_priority_fee_per_gas,omitempty"`
+	MaxFeePerGas         *hexutil.Big `json:"max_fee_per_gas,omitempty"`
+}
+
+// TopOfBlockNotification - represents the first transactions of a block, published as soon as the block is
+// received, before its receipts are available
+type TopOfBlockNotification struct {
+	Hash          string         `json:"hash,omitempty"`
+	BlockHeight   uint64         `json:"block_height,omitempty"`
+	BaseFeePerGas *hexutil.Big   `json:"base_fee_per_gas,omitempty"`
+	Txs           []TopOfBlockTx `json:"txs,omitempty"`
+}
+
+// NewTopOfBlockNotification returns a new TopOfBlockNotification with the first txCount transactions of the block
+func NewTopOfBlockNotification(hash ethcommon.Hash, block *ethtypes.Block, txCount int) *TopOfBlockNotification {
+	header := block.Header()
+	notification := &TopOfBlockNotification{
+		Hash:          hash.String(),
+		BlockHeight:   block.NumberU64(),
+		BaseFeePerGas: (*hexutil.Big)(header.BaseFee),
+	}
+
+	transactions := block.Transactions()
+	if txCount > len(transactions) {
+		txCount = len(transactions)
+	} else if txCount < 0 {
+		txCount = 0
+	}
+	notification.Txs = make([]TopOfBlockTx, 0, txCount)
+	for i, tx := range transactions[:txCount] {
+		topTx := TopOfBlockTx{
+			Position: i,
+			TxHash:   tx.Hash().String(),
+			To:       tx.To(),
+			Type:     tx.Type(),
+			Gas:      tx.Gas(),
+			GasPrice: (*hexutil.Big)(effectiveGasPrice(tx, header.BaseFee)),
+		}
+		if from, err := ethtypes.Sender(ethtypes.NewLondonSigner(tx.ChainId()), tx); err == nil {
+			topTx.From = &from
+		}
+		if tx.Type() == ethtypes.DynamicFeeTxType {
+			topTx.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+			topTx.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		notification.Txs = append(notification.Txs, topTx)
+	}
+	return notification
+}
+
+// effectiveGasPrice returns the gas price paid by the transaction in a block with the base fee
+func effectiveGasPrice(tx *ethtypes.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.GasPrice()
+	}
+	price := new(big.Int).Add(tx.GasTipCap(), baseFee)
+	if price.Cmp(tx.GasFeeCap()) > 0 {
+		return tx.GasFeeCap()
+	}
+	return price
+}
+
+// WithFields -
+func (n *TopOfBlockNotification) WithFields(fields []string) Notification {
+	notification := TopOfBlockNotification{}
+	for _, param := range fields {
+		switch param {
+		case "hash":
+			notification.Hash = n.Hash
+		case "block_height":
+			notification.BlockHeight = n.BlockHeight
+		case "base_fee_per_gas":
+			notification.BaseFeePerGas = n.BaseFeePerGas
+		case "txs":
+			notification.Txs = n.Txs
+		}
+	}
+	return &notification
+}
+
+// Filters -
+func (n *TopOfBlockNotification) Filters(filters []string) map[string]interface{} {
+	return nil
+}
+
+// LocalRegion -
+func (n *TopOfBlockNotification) LocalRegion() bool {
+	return false
+}
+
+// GetHash -
+func (n *TopOfBlockNotification) GetHash() string {
+	return n.Hash
+}
+
+// NotificationType - feed name
+func (n *TopOfBlockNotification) NotificationType() FeedType {
+	return TopOfBlockFeed
+}
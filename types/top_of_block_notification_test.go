@@ -0,0 +1,57 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTopOfBlockNotification(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := ethtypes.NewLondonSigner(big.NewInt(1))
+	to := ethcommon.HexToAddress("0x01")
+
+	dynamicTx, err := ethtypes.SignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+		ChainID: big.NewInt(1), Nonce: 0, Gas: 21000, To: &to, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(200),
+	})
+	require.NoError(t, err)
+	cappedTx, err := ethtypes.SignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+		ChainID: big.NewInt(1), Nonce: 1, Gas: 21000, To: &to, GasTipCap: big.NewInt(50), GasFeeCap: big.NewInt(120),
+	})
+	require.NoError(t, err)
+	legacyTx, err := ethtypes.SignNewTx(key, signer, &ethtypes.LegacyTx{Nonce: 2, Gas: 21000, To: &to, GasPrice: big.NewInt(300)})
+	require.NoError(t, err)
+
+	header := &ethtypes.Header{Number: big.NewInt(100), BaseFee: big.NewInt(100)}
+	block := ethtypes.NewBlockWithHeader(header).WithBody([]*ethtypes.Transaction{dynamicTx, cappedTx, legacyTx}, nil)
+
+	notification := NewTopOfBlockNotification(block.Hash(), block, 2)
+	assert.Equal(t, block.Hash().String(), notification.Hash)
+	assert.Equal(t, uint64(100), notification.BlockHeight)
+	require.Len(t, notification.Txs, 2)
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	assert.Equal(t, 0, notification.Txs[0].Position)
+	assert.Equal(t, dynamicTx.Hash().String(), notification.Txs[0].TxHash)
+	assert.Equal(t, &from, notification.Txs[0].From)
+	assert.Equal(t, int64(102), notification.Txs[0].GasPrice.ToInt().Int64())
+	assert.Equal(t, int64(2), notification.Txs[0].MaxPriorityFeePerGas.ToInt().Int64())
+	// the effective gas price is capped by the fee cap
+	assert.Equal(t, 1, notification.Txs[1].Position)
+	assert.Equal(t, int64(120), notification.Txs[1].GasPrice.ToInt().Int64())
+
+	notification = NewTopOfBlockNotification(block.Hash(), block, 10)
+	require.Len(t, notification.Txs, 3)
+	assert.Equal(t, int64(300), notification.Txs[2].GasPrice.ToInt().Int64())
+	assert.Nil(t, notification.Txs[2].MaxFeePerGas)
+
+	withFields := notification.WithFields([]string{"block_height"}).(*TopOfBlockNotification)
+	assert.Equal(t, uint64(100), withFields.BlockHeight)
+	assert.Empty(t, withFields.Txs)
+}
@@ -1,10 +1,13 @@
 package types
 
-// TransactionStatusNotification - represents a transaction status notification
+// TransactionStatusNotification - represents a transaction status notification, it is only delivered to the
+// subscriptions of SubscriptionIDs. The block is set on the included and reorged out statuses.
 type TransactionStatusNotification struct {
 	SubscriptionIDs []string `json:"subscription_ids,omitempty"`
 	TransactionHash string   `json:"transaction_hash,omitempty"`
 	Status          Status   `json:"status,omitempty"`
+	BlockNumber     uint64   `json:"block_number,omitempty"`
+	BlockHash       string   `json:"block_hash,omitempty"`
 }
 
 // Status types of transaction state
@@ -25,6 +28,18 @@ const Canceled Status = "canceled"
 // Replaced is transaction status for replaced status
 const Replaced Status = "replaced"
 
+// ReceivedByBDN is transaction status for a transaction sent to or received from the BDN
+const ReceivedByBDN Status = "received_by_bdn"
+
+// Propagated is transaction status for a transaction delivered to or announced by the blockchain node
+const Propagated Status = "propagated"
+
+// Included is transaction status for a transaction included in a block
+const Included Status = "included"
+
+// ReorgedOut is transaction status for a transaction whose block was replaced by another block without it
+const ReorgedOut Status = "reorged_out"
+
 // UpdateSource is the source for updating transaction status
 type UpdateSource string
 
@@ -43,6 +58,10 @@ func (tn *TransactionStatusNotification) WithFields(fields []string) Notificatio
 			txStatusNotification.TransactionHash = tn.TransactionHash
 		case "status":
 			txStatusNotification.Status = tn.Status
+		case "block_number":
+			txStatusNotification.BlockNumber = tn.BlockNumber
+		case "block_hash":
+			txStatusNotification.BlockHash = tn.BlockHash
 		case "subscription_id":
 			txStatusNotification.SubscriptionIDs = tn.SubscriptionIDs
 		}
@@ -56,6 +56,11 @@ func (f TxFlags) IsDeliverToNode() bool {
 	return f&TFDeliverToNode != 0
 }
 
+// IsPrivateTx return true if transaction should not be propagated to the public mempool
+func (f TxFlags) IsPrivateTx() bool {
+	return f&TFPrivateTx != 0
+}
+
 // IsNextValidator return true if transaction marked for next validator
 func (f TxFlags) IsNextValidator() bool {
 	return f&TFNextValidator != 0
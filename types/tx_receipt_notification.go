@@ -3,6 +3,10 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const nullAddressStr = "0x"
@@ -10,7 +14,9 @@ const nullAddressStr = "0x"
 // TxReceiptsNotification - represents a transaction receipt feed entry
 // to avoid deserializing/reserializing the message from Ethereum RPC, no conversion work is done
 type TxReceiptsNotification struct {
-	Receipts []*TxReceipt
+	BlockHash   string
+	BlockNumber string
+	Receipts    []*TxReceipt
 }
 
 // NewTxReceiptsNotification returns a new tx receipts notification object
@@ -35,6 +41,11 @@ type TxReceipt struct {
 	TransactionIndex  string        `json:"transaction_index,omitempty"`
 	TxType            string        `json:"type,omitempty"`
 	TxsCount          string        `json:"txs_count,omitempty"`
+
+	// computed by the gateway, see SetComputedFields
+	EffectivePriorityFee string  `json:"effective_priority_fee,omitempty"`
+	InclusionDelayMs     *int64  `json:"inclusion_delay_ms,omitempty"`
+	PositionInBlock      *uint64 `json:"position_in_block,omitempty"`
 }
 
 // NewTxReceipt returns a new tx receipt object created from a map
@@ -116,6 +127,31 @@ func NewTxReceipt(receiptMap map[string]interface{}, txsCount string) *TxReceipt
 	return &txReceipt
 }
 
+// SetComputedFields sets the fields computed from the block of the receipt: the priority fee paid above the base fee
+// of the block, the delay between the time the gateway first saw the tx and the block timestamp, if the gateway saw
+// the tx, and the position of the tx in the block
+func (r *TxReceipt) SetComputedFields(header *Header, firstSeen time.Time) {
+	if index, err := hexutil.DecodeUint64(r.TransactionIndex); err == nil {
+		r.PositionInBlock = &index
+	}
+	if header == nil {
+		return
+	}
+
+	if price, err := hexutil.DecodeBig(r.EffectiveGasPrice); err == nil {
+		// the whole gas price is a priority fee before the base fee was introduced
+		if header.BaseFee != nil {
+			price.Sub(price, big.NewInt(int64(*header.BaseFee)))
+		}
+		r.EffectivePriorityFee = hexutil.EncodeBig(price)
+	}
+
+	if timestamp, err := hexutil.DecodeUint64(header.Timestamp); err == nil && !firstSeen.IsZero() {
+		delay := time.Unix(int64(timestamp), 0).Sub(firstSeen).Milliseconds()
+		r.InclusionDelayMs = &delay
+	}
+}
+
 // MarshalJSON formats txReceiptNotification, including nil "to" field if requested
 func (r *TxReceipt) marshalJSON() ([]byte, error) {
 	marshalled, err := json.Marshal(r)
@@ -151,7 +187,7 @@ func (r *TxReceiptsNotification) MarshalJSON() ([]byte, error) {
 
 // WithFields -
 func (r *TxReceiptsNotification) WithFields(fields []string) Notification {
-	txReceiptsNotification := TxReceiptsNotification{Receipts: []*TxReceipt{}}
+	txReceiptsNotification := TxReceiptsNotification{BlockHash: r.BlockHash, BlockNumber: r.BlockNumber, Receipts: []*TxReceipt{}}
 
 	for _, receipt := range r.Receipts {
 		newReceipt := &TxReceipt{}
@@ -191,6 +227,12 @@ func (r *TxReceiptsNotification) WithFields(fields []string) Notification {
 				newReceipt.TxType = receipt.TxType
 			case "txs_count":
 				newReceipt.TxsCount = receipt.TxsCount
+			case "effective_priority_fee":
+				newReceipt.EffectivePriorityFee = receipt.EffectivePriorityFee
+			case "inclusion_delay_ms":
+				newReceipt.InclusionDelayMs = receipt.InclusionDelayMs
+			case "position_in_block":
+				newReceipt.PositionInBlock = receipt.PositionInBlock
 			}
 		}
 
@@ -212,6 +254,9 @@ func (r *TxReceiptsNotification) LocalRegion() bool {
 
 // GetHash -
 func (r *TxReceiptsNotification) GetHash() string {
+	if r.BlockHash != "" {
+		return r.BlockHash
+	}
 	if len(r.Receipts) == 0 {
 		return ""
 	}
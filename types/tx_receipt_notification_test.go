@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/bloXroute-Labs/gateway/v2/test"
 	"github.com/stretchr/testify/assert"
@@ -109,3 +110,27 @@ func TestContractCreationTxReceipt(t *testing.T) {
 	assert.Equal(t, nil, to)
 	assert.Equal(t, "0x13cf158e1766ca6bdbe2719dace440121b4603b1", receiptJSON[0]["from"])
 }
+
+func TestTxReceiptComputedFields(t *testing.T) {
+	txReceipt := NewTxReceipt(txReceiptMap, "0x0")
+	baseFee := 100000000000
+	header := &Header{Timestamp: "0x6553f100", BaseFee: &baseFee}
+	firstSeen := time.Unix(0x6553f100, 0).Add(-1500 * time.Millisecond)
+
+	txReceipt.SetComputedFields(header, firstSeen)
+	// 0x1c298e1cb9 - 100 gwei
+	assert.Equal(t, "0x4e11734b9", txReceipt.EffectivePriorityFee)
+	assert.Equal(t, int64(1500), *txReceipt.InclusionDelayMs)
+	assert.Equal(t, uint64(100), *txReceipt.PositionInBlock)
+
+	receipt := NewTxReceiptsNotification([]*TxReceipt{txReceipt}).WithFields([]string{"position_in_block"}).(*TxReceiptsNotification).Receipts[0]
+	assert.Empty(t, receipt.EffectivePriorityFee)
+	assert.Nil(t, receipt.InclusionDelayMs)
+	assert.Equal(t, uint64(100), *receipt.PositionInBlock)
+
+	// the tx was not seen by the gateway, and the block has no base fee
+	txReceipt = NewTxReceipt(txReceiptMap, "0x0")
+	txReceipt.SetComputedFields(&Header{Timestamp: "0x6553f100"}, time.Time{})
+	assert.Equal(t, "0x1c298e1cb9", txReceipt.EffectivePriorityFee)
+	assert.Nil(t, txReceipt.InclusionDelayMs)
+}
@@ -30,6 +30,7 @@ type Builder struct {
 }
 
 type request struct {
+	builder     string
 	bundleHash  string
 	blockNumber string
 	method      string
@@ -47,6 +48,14 @@ type Dispatcher struct {
 	builders            map[string]*Builder
 	mevMaxProfitBuilder bool
 	processMegaBundle   bool
+	onResponse          func(bundle *bxmessage.MEVBundle, builder string, err error)
+}
+
+// builderResponse is the JSON-RPC response of a builder, only its error is inspected
+type builderResponse struct {
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
 }
 
 // NewDispatcher creates a new NewDispatcher
@@ -71,6 +80,12 @@ func NewDispatcher(builders map[string]*Builder, mevMaxProfitBuilder bool, proce
 	}
 }
 
+// OnResponse sets the handler of the response of each builder a bundle is dispatched to, err is set if the builder
+// could not be reached or did not accept the bundle
+func (d *Dispatcher) OnResponse(handler func(bundle *bxmessage.MEVBundle, builder string, err error)) {
+	d.onResponse = handler
+}
+
 // Dispatch dispatches the MEV bundle to the MEV builders
 func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
 	if len(d.builders) == 0 {
@@ -78,7 +93,7 @@ func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
 		return nil
 	}
 
-	if !d.mevMaxProfitBuilder && bundle.Frontrunning {
+	if !d.mevMaxProfitBuilder && bundle.Frontrunning && !bundle.IsCancellation() {
 		log.Warnf("MEV bundle %v is frontrunning, but max profit builder is not enabled. Skipping.", bundle.BundleHash)
 		return nil
 	}
@@ -98,6 +113,7 @@ func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
 			resp, err := d.client.Do(req.request)
 			if err != nil {
 				log.Errorf("failed to forward mevBundle (%s, json: '%s'), err: %v", req, json, err)
+				d.respond(bundle, req.builder, fmt.Errorf("failed to forward bundle: %v", err))
 				return
 			}
 			defer resp.Body.Close()
@@ -105,16 +121,37 @@ func (d *Dispatcher) Dispatch(bundle *bxmessage.MEVBundle) error {
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
 				log.Errorf("failed to read mevBundle (%s, json: '%s') response, err: %v", req, json, err)
+				d.respond(bundle, req.builder, fmt.Errorf("failed to read response: %v", err))
 				return
 			}
 
 			log.Tracef("sent mevBundle (%s, json: '%s') got response: %v, status code: %v", req, json, string(respBody), resp.StatusCode)
+			d.respond(bundle, req.builder, responseError(resp.StatusCode, respBody))
 		}(req)
 	}
 
 	return nil
 }
 
+// respond passes the response of the builder to the response handler, if any
+func (d *Dispatcher) respond(bundle *bxmessage.MEVBundle, builder string, err error) {
+	if d.onResponse != nil {
+		d.onResponse(bundle, builder, err)
+	}
+}
+
+// responseError returns the error of the builder response, nil if the builder accepted the request
+func responseError(statusCode int, body []byte) error {
+	var response builderResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.Error != nil {
+		return fmt.Errorf("builder error: %v", response.Error.Message)
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %v", statusCode)
+	}
+	return nil
+}
+
 func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
 	params := []jsonrpc.RPCSendBundle{
 		{
@@ -126,6 +163,7 @@ func (d *Dispatcher) bundleJSON(bundle *bxmessage.MEVBundle) ([]byte, error) {
 			RevertingTxHashes: bundle.RevertingHashes,
 			BundlePrice:       bundle.BundlePrice,
 			EnforcePayout:     bundle.EnforcePayout,
+			ReplacementUUID:   bundle.UUID,
 		},
 	}
 
@@ -157,7 +195,7 @@ func (d *Dispatcher) makeRequests(bundle *bxmessage.MEVBundle, json []byte) []*r
 		}
 
 		for _, endpoint := range builder.Endpoints {
-			if builder.Name == bxgateway.FlashbotsBuilderName && len(bundle.Transactions) == 0 {
+			if bundle.IsCancellation() {
 				req, err := d.cancelRequest(endpoint, bundle)
 				if err != nil {
 					log.Errorf("failed to create cancel request for mev builder %v, bundleHash: %v, err: %v", endpoint, bundle.BundleHash, err)
@@ -165,6 +203,7 @@ func (d *Dispatcher) makeRequests(bundle *bxmessage.MEVBundle, json []byte) []*r
 				}
 
 				requests = append(requests, &request{
+					builder:     builderName,
 					request:     req,
 					bundleHash:  bundle.BundleHash,
 					blockNumber: bundle.BlockNumber,
@@ -180,6 +219,7 @@ func (d *Dispatcher) makeRequests(bundle *bxmessage.MEVBundle, json []byte) []*r
 			}
 
 			requests = append(requests, &request{
+				builder:     builderName,
 				request:     req,
 				bundleHash:  bundle.BundleHash,
 				blockNumber: bundle.BlockNumber,
@@ -67,6 +67,7 @@ func TestDispatcher(t *testing.T) {
 				MinTimestamp:      1686120664,
 				MaxTimestamp:      1686120884,
 				RevertingTxHashes: []string{testTx1Hash},
+				ReplacementUUID:   "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8",
 			},
 			expBuilders: []string{"builder1"},
 		},
@@ -93,6 +94,7 @@ func TestDispatcher(t *testing.T) {
 				MinTimestamp:      1686120664,
 				MaxTimestamp:      1686120884,
 				RevertingTxHashes: []string{testTx1Hash},
+				ReplacementUUID:   "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8",
 			},
 			expBuilders: []string{"builder1", "builder2"},
 		},
@@ -119,6 +121,7 @@ func TestDispatcher(t *testing.T) {
 				MinTimestamp:      1686120664,
 				MaxTimestamp:      1686120884,
 				RevertingTxHashes: []string{testTx1Hash},
+				ReplacementUUID:   "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8",
 			},
 			expBuilders: []string{"builder2"},
 		},
@@ -202,6 +205,7 @@ func TestDispatcher(t *testing.T) {
 				MinTimestamp:      1686120664,
 				MaxTimestamp:      1686120884,
 				RevertingTxHashes: []string{testTx1Hash},
+				ReplacementUUID:   "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8",
 			},
 			expBuilders: []string{"builder1"},
 		},
@@ -268,3 +272,61 @@ func TestDispatcher(t *testing.T) {
 		})
 	}
 }
+
+func TestDispatcher_Cancellation(t *testing.T) {
+	cancellation, err := bxmessage.NewMEVBundleCancellation("e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8", "", bxmessage.MEVBundleBuilders{"builder1": "", "builder2": ""})
+	assert.NoError(t, err)
+	assert.True(t, cancellation.IsCancellation())
+
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer wg.Done()
+
+		var req jsonrpc2.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, string(jsonrpc.RPCEthCancelBundle), req.Method)
+		assert.NotEmpty(t, r.Header.Get(flashbotAuthHeader))
+
+		var payload []jsonrpc.RPCCancelBundlePayload
+		assert.NoError(t, json.Unmarshal(*req.Params, &payload))
+		assert.Equal(t, "e2a1c984-b31c-4bc6-a2eb-d2d903aab6d8", payload[0].ReplacementUUID)
+	}))
+	defer server.Close()
+
+	// the cancellations are dispatched to every builder, even without max profit builder
+	d := NewDispatcher(makeBuildersMap(fmt.Sprintf("%s/", server.URL), []string{"builder1", "builder2"}), false, true)
+	assert.NoError(t, d.Dispatch(&cancellation))
+	wg.Wait()
+}
+
+func TestDispatcher_OnResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "builder2") {
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bundle too late"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(makeBuildersMap(fmt.Sprintf("%s/", server.URL), []string{"builder1", "builder2"}), false, true)
+
+	lock := sync.Mutex{}
+	responses := make(map[string]error)
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	d.OnResponse(func(bundle *bxmessage.MEVBundle, builder string, err error) {
+		defer wg.Done()
+		lock.Lock()
+		defer lock.Unlock()
+		responses[builder] = err
+	})
+
+	bundle := &bxmessage.MEVBundle{BundleHash: "0x01", BlockNumber: "0x1", Transactions: []string{"0x00"}, MEVBuilders: bxmessage.MEVBundleBuilders{"builder1": "", "builder2": ""}}
+	assert.NoError(t, d.Dispatch(bundle))
+	wg.Wait()
+
+	assert.NoError(t, responses["builder1"])
+	assert.EqualError(t, responses["builder2"], "builder error: bundle too late")
+}
@@ -92,7 +92,7 @@ var (
 	}
 	WSHostFlag = &cli.StringFlag{
 		Name:  "ws-host",
-		Usage: "host address for RPC server to run on",
+		Usage: "host address for RPC server to run on, an IPv6 address like ::1 is supported and :: listens on every IPv4 and IPv6 address",
 		Value: "127.0.0.1",
 	}
 	WSPortFlag = &cli.IntFlag{
@@ -101,6 +101,10 @@ var (
 		Aliases: []string{"wsp", "rpc-port"},
 		Value:   28333,
 	}
+	HTTPHostFlag = &cli.StringFlag{
+		Name:  "http-host",
+		Usage: "host address for HTTP server to run on, an IPv6 address like ::1 is supported and the server listens on every IPv4 and IPv6 address if not set",
+	}
 	HTTPPortFlag = &cli.IntFlag{
 		Name:  "http-port",
 		Usage: "port for HTTP server to run on",
@@ -220,7 +224,7 @@ var (
 	}
 	GRPCHostFlag = &cli.StringFlag{
 		Name:  "grpc-host",
-		Usage: "host address for GRPC server to run on",
+		Usage: "host address for GRPC server to run on, an IPv6 address like ::1 is supported and :: listens on every IPv4 and IPv6 address",
 		Value: "127.0.0.1",
 	}
 	GRPCPortFlag = &cli.IntFlag{
@@ -497,4 +501,368 @@ var (
 		Hidden: true,
 		Value:  true,
 	}
+	ClusterListenFlag = &cli.StringFlag{
+		Name:  "cluster-listen",
+		Usage: "address the gateway listens on for cluster gossip of other gateways publishing to the same sink (e.g. 0.0.0.0:1811)",
+		Value: "",
+	}
+	ClusterPeersFlag = &cli.StringSliceFlag{
+		Name:  "cluster-peers",
+		Usage: "cluster gossip addresses of the other gateways in the cluster, enables cluster mode (e.g. 10.0.0.2:1811,10.0.0.3:1811)",
+	}
+	FeedLeaderElectionFlag = &cli.StringFlag{
+		Name:  "feed-leader-election",
+		Usage: "elect a single gateway to publish each feed when several gateways publish to the same sink (cluster, lockfile)",
+		Value: "",
+	}
+	FeedLeaderLockDirFlag = &cli.StringFlag{
+		Name:  "feed-leader-lock-dir",
+		Usage: "directory shared by the gateways holding the feed leases, used with --feed-leader-election lockfile",
+		Value: "",
+	}
+	FeedLeaderLeaseFlag = &cli.DurationFlag{
+		Name:  "feed-leader-lease",
+		Usage: "duration of the feed lease, a new leader is elected if the lease is not renewed in time",
+		Value: 10 * time.Second,
+	}
+	ClusterSecretFlag = &cli.StringFlag{
+		Name:  "cluster-secret",
+		Usage: "secret shared by the gateways of the cluster to sign their gossip, required with --cluster-peers",
+		Value: "",
+	}
+	ClusterPeerTimeoutFlag = &cli.DurationFlag{
+		Name:  "cluster-peer-timeout",
+		Usage: "duration without gossip after which a cluster member is considered dead and its feeds fail over",
+		Value: 5 * time.Second,
+	}
+	NodeTxRateLimitFlag = &cli.Float64Flag{
+		Name:  "node-tx-rate-limit",
+		Usage: "maximum number of transactions per second sent from the BDN to the blockchain node, 0 means no limit",
+	}
+	NodeTxBytesRateLimitFlag = &cli.Float64Flag{
+		Name:  "node-tx-bytes-rate-limit",
+		Usage: "maximum number of transaction bytes per second sent from the BDN to the blockchain node, 0 means no limit",
+	}
+	NodeTxBurstFlag = &cli.DurationFlag{
+		Name:  "node-tx-burst",
+		Usage: "amount of time worth of transactions that can be sent to the blockchain node at once when rate limits are set",
+		Value: time.Second,
+	}
+	NodeTxPoolCapacityFlag = &cli.Uint64Flag{
+		Name:  "node-txpool-capacity",
+		Usage: "number of pending and queued transactions considered as a full node tx pool, forwarding of transactions to the node is throttled or paused as the pool fills up and the transactions which are not forwarded are dropped, 0 disables the check",
+	}
+	NodeTxPoolThrottleThresholdFlag = &cli.Float64Flag{
+		Name:  "node-txpool-throttle-threshold",
+		Usage: "fraction of the node tx pool capacity at which forwarding of transactions to the node is throttled",
+		Value: 0.8,
+	}
+	NodeTxPoolPauseThresholdFlag = &cli.Float64Flag{
+		Name:  "node-txpool-pause-threshold",
+		Usage: "fraction of the node tx pool capacity at which forwarding of transactions to the node is paused",
+		Value: 0.95,
+	}
+	NodeTxPoolThrottledRateFlag = &cli.IntFlag{
+		Name:  "node-txpool-throttled-rate",
+		Usage: "number of transactions per second forwarded to the node while forwarding is throttled, the transactions beyond this rate are dropped",
+		Value: 100,
+	}
+	TxPoolReconciliationIntervalFlag = &cli.DurationFlag{
+		Name:  "txpool-reconciliation-interval",
+		Usage: "interval of comparing the node txpool_content with the recent gateway transactions, missing transactions are forwarded to the node, 0 disables the reconciliation",
+	}
+	NodeRPCSlowThresholdFlag = &cli.DurationFlag{
+		Name:  "node-rpc-slow-threshold",
+		Usage: "duration above which the RPC calls to the blockchain node are logged with their method, payload digest and node, 0 disables the logs (the latency of all the calls is exported on /metrics)",
+		Value: time.Second,
+	}
+	AccountCacheTTLFlag = &cli.DurationFlag{
+		Name:  "account-cache-ttl",
+		Usage: "duration the account models of the clients connecting with another account are cached, they are refreshed in the background once expired, 0 fetches them from the SDN on each connection",
+		Value: 5 * time.Minute,
+	}
+	AccountCacheNegativeTTLFlag = &cli.DurationFlag{
+		Name:  "account-cache-negative-ttl",
+		Usage: "duration a failure to fetch an account model from the SDN is cached before the account is fetched again",
+		Value: 30 * time.Second,
+	}
+	AccountCacheMaxStaleFlag = &cli.DurationFlag{
+		Name:  "account-cache-max-stale",
+		Usage: "duration an account model is still served past its TTL while the SDN is unavailable, the account is then fetched again before the connection is authorized",
+		Value: 10 * time.Minute,
+	}
+	AccountAllowedContractsFileFlag = &cli.StringFlag{
+		Name:  "account-allowed-contracts-file",
+		Usage: "JSON file mapping account IDs to the contracts their submitted transactions can be sent to, overrides the allowed contracts of the account model",
+	}
+	AccountTxDefaultsFileFlag = &cli.StringFlag{
+		Name:  "account-tx-defaults-file",
+		Usage: "JSON file mapping account IDs to the front_running_protection and validators_only flags applied when a blxr_tx request omits them, overrides the defaults of the account model",
+	}
+	NetworkTxPolicyFileFlag = &cli.StringFlag{
+		Name:  "network-tx-policy-file",
+		Usage: "JSON file mapping blockchain networks to the tx flag policy of the gateway on them (e.g. {\"BSC-Mainnet\": {\"validators_only\": true, \"deliver_to_node\": false, \"forbidden_flags\": [\"next_validator\"]}}), the submitted txs with a forbidden flag are rejected",
+	}
+	DenylistFileFlag = &cli.StringFlag{
+		Name:  "denylist-file",
+		Usage: "JSON file with denied addresses and tx hash patterns ({\"addresses\": [], \"tx_hash_patterns\": []}), transactions matching it are rejected and not sent to the node, the file is reloaded when modified",
+	}
+	FilterFilesDirFlag = &cli.StringFlag{
+		Name:  "filter-files-dir",
+		Usage: "directory of the address files (one address per line) the filters of the tx subscriptions reference with in_file('name'), the files are reloaded when modified",
+	}
+	AddressLabelsFileFlag = &cli.StringFlag{
+		Name:  "address-labels-file",
+		Usage: "JSON file mapping addresses to their labels (e.g. {\"0xabc...\": [\"exchange\"]}), added to the from and to addresses of the tx notifications of subscriptions which include enrich",
+	}
+	StandingSubscriptionsFileFlag = &cli.StringFlag{
+		Name:  "standing-subscriptions-file",
+		Usage: "JSON file with a list of subscriptions (e.g. [{\"name\": \"swaps\", \"feed\": \"newTxs\", \"include\": [], \"filters\": \"\", \"sink\": {\"type\": \"webhook\", \"url\": \"...\"}}]) served from startup without a connected client, delivering the notifications to an export, webhook or file sink (e.g. {\"type\": \"file\", \"path\": \"capture.jsonl\", \"max_size_mb\": 100, \"rotate_minutes\": 60, \"compress\": true})",
+	}
+	ProposerDutiesFlag = &cli.BoolFlag{
+		Name:  "proposer-duties",
+		Usage: "poll the beacon API for the proposers of the current and next epochs and publish them on the proposerDuties feed",
+	}
+	ProposerDutiesRelaysFlag = &cli.StringSliceFlag{
+		Name:  "proposer-duties-relays",
+		Usage: "MEV relay URLs queried for the validator registrations of the upcoming proposers (e.g. https://boost-relay.flashbots.net)",
+	}
+	NTPServerFlag = &cli.StringFlag{
+		Name:  "ntp-server",
+		Usage: "NTP server (e.g. pool.ntp.org) used to correct the drift of the slot clock used for scheduling and blxr_time, empty disables the correction",
+	}
+	CaptureFileFlag = &cli.StringFlag{
+		Name:  "capture-file",
+		Usage: "for gateways only, records the messages received from the relays and the blockchain node to the file for replay with bxcli replay, empty disables the capture",
+	}
+	CaptureFileSizeFlag = &cli.IntFlag{
+		Name:  "capture-file-size",
+		Usage: "for gateways only, max size of the capture file (megabytes), the file is rotated to <capture-file>.1 when it is exceeded",
+		Value: 100,
+	}
+	ValidateBDNTxsFlag = &cli.BoolFlag{
+		Name:  "validate-bdn-txs",
+		Usage: "for gateways only, decode and verify the signature and chain ID of transactions received from the BDN before sending them to the blockchain node",
+	}
+	BDNTxValidationWorkersFlag = &cli.IntFlag{
+		Name:  "bdn-tx-validation-workers",
+		Usage: "for gateways only, number of workers validating transactions received from the BDN when --validate-bdn-txs is set",
+		Value: 4,
+	}
+	FeedPeersFlag = &cli.StringSliceFlag{
+		Name:  "feed-peers",
+		Usage: "for gateways only, gRPC addresses (host:port) of other gateways whose newTxs feed is merged into the local feeds, transactions received locally are not published twice",
+	}
+	FeedPeerAuthHeaderFlag = &cli.StringFlag{
+		Name:  "feed-peer-auth-header",
+		Usage: "authorization header used to subscribe to the gRPC feeds of the --feed-peers gateways, requires --feed-peer-tls unless the peers are on the loopback interface",
+	}
+	FeedPeerTLSFlag = &cli.BoolFlag{
+		Name:  "feed-peer-tls",
+		Usage: "subscribe to the gRPC feeds of the --feed-peers gateways over TLS (e.g. behind a TLS terminating proxy), verified with the system certificates",
+	}
+	WSDrainWindowFlag = &cli.DurationFlag{
+		Name:  "ws-drain-window",
+		Usage: "when the websocket server is re-bound (e.g. the TLS certificate changed), existing connections are asked to reconnect and closed gradually over this window",
+		Value: 10 * time.Second,
+	}
+	WSShutdownDeadlineFlag = &cli.DurationFlag{
+		Name:  "ws-shutdown-deadline",
+		Usage: "when the websocket server shuts down, clients are sent a gateway_shutdown notification and their connections are closed once the queued notifications are flushed, or after this deadline",
+		Value: 5 * time.Second,
+	}
+	LocalModeFlag = &cli.BoolFlag{
+		Name:  "local-mode",
+		Usage: "for development only, run the gateway without SDN and relay connectivity: feeds are served from the blockchain node only and blxr_tx transactions are sent to the node, clients do not need an authorization header so --ws-host and --grpc-host must be loopback addresses",
+	}
+	FeatureFlagsFlag = &cli.StringSliceFlag{
+		Name:  "feature-flags",
+		Usage: "local overrides of the feature flags received from the SDN, as name=true or name=false (e.g. tx_batching=false,combined_feed=true)",
+	}
+	FeedExportDirFlag = &cli.StringFlag{
+		Name:  "feed-export-dir",
+		Usage: "for gateways only, directory where the notifications delivered to the subscriptions of each account are archived, one file per account",
+	}
+	FeedExportPublishedFlag = &cli.BoolFlag{
+		Name:  "feed-export-published",
+		Usage: "archive every notification published by the gateway under its own account, the shared sink deduplicated by --cluster-peers and --feed-leader-election",
+	}
+	FeedExportKeysFileFlag = &cli.StringFlag{
+		Name:  "feed-export-keys-file",
+		Usage: "JSON file of per-account AES-256 keys (account ID to hex key), the archived notifications of each account are envelope encrypted with its key and accounts without key are not archived",
+	}
+	TxReceiptsFetchFlag = &cli.StringSliceFlag{
+		Name:  "tx-receipts-fetch",
+		Usage: "receipt fetching of the txReceipts feed as [ws-uri=]concurrency/batch-size/retries/latency-target, the config without ws-uri applies to the nodes without their own (e.g. 16/20/3/500ms,ws://127.0.0.1:8546=4/10/5/1s). The concurrency of a node is halved while its average latency is above the latency target, 0s disables the throttling",
+	}
+	TxReceiptsRetryConcurrencyFlag = &cli.IntFlag{
+		Name:  "tx-receipts-retry-concurrency",
+		Usage: "max number of retries of the failed receipt calls in flight to all the nodes, the retries are apart from the concurrency of --tx-receipts-fetch",
+		Value: 16,
+	}
+	TxReceiptsRetryRateFlag = &cli.Uint64Flag{
+		Name:  "tx-receipts-retry-rate",
+		Usage: "max number of retries of the failed receipt calls per second to a node",
+		Value: 50,
+	}
+	TrafficMirrorAccountIDFlag = &cli.StringFlag{
+		Name:  "traffic-mirror-account-id",
+		Usage: "for gateways only, account whose notifications and tx and bundle submissions are duplicated to --traffic-mirror-file, to troubleshoot the discrepancies reported by the customer",
+	}
+	TrafficMirrorFileFlag = &cli.StringFlag{
+		Name:  "traffic-mirror-file",
+		Usage: "JSON lines file the traffic of --traffic-mirror-account-id is appended to",
+	}
+	TrafficMirrorRedactFlag = &cli.StringSliceFlag{
+		Name:  "traffic-mirror-redact",
+		Usage: "fields removed from the mirrored records at any depth (e.g. from,remote_address)",
+	}
+	TrafficMirrorHashRedactedFlag = &cli.BoolFlag{
+		Name:  "traffic-mirror-hash-redacted",
+		Usage: "replace the fields of --traffic-mirror-redact with their SHA-256 instead of removing them, so the mirrored records can still be correlated",
+	}
+	FeedAlertsFlag = &cli.StringSliceFlag{
+		Name:  "feed-alerts",
+		Usage: "for gateways only, expected rate of the feeds in notifications per second as feed=min-max, either bound can be omitted (e.g. newTxs=1-5000,bdnBlocks=0.01-), an alert fires when a rate is out of bounds. The min bound is only checked while the blockchain node is synced",
+	}
+	FeedAlertIntervalFlag = &cli.DurationFlag{
+		Name:  "feed-alert-interval",
+		Usage: "interval over which the rates of the --feed-alerts feeds are measured",
+		Value: time.Minute,
+	}
+	FeedAlertWebhookFlag = &cli.StringFlag{
+		Name:  "feed-alert-webhook",
+		Usage: "URL the --feed-alerts and --feed-verification alerts are posted to as JSON, the alerts are logged if not set",
+	}
+	CanaryPrivateKeyFlag = &cli.StringFlag{
+		Name:  "canary-private-key",
+		Usage: "for gateways on testnets only, hex encoded private key of an account sending self-addressed zero-value canary transactions to check the end-to-end health of the gateway, reported by blxr_node_status and /readyz",
+	}
+	CanaryIntervalFlag = &cli.DurationFlag{
+		Name:  "canary-interval",
+		Usage: "interval between the --canary-private-key canary transactions",
+		Value: time.Minute,
+	}
+	CanaryTimeoutFlag = &cli.DurationFlag{
+		Name:  "canary-timeout",
+		Usage: "how long a canary transaction can take to be included in a block before the gateway is reported unhealthy",
+		Value: 5 * time.Minute,
+	}
+	FeedVerificationFlag = &cli.BoolFlag{
+		Name:  "feed-verification",
+		Usage: "for gateways only, compare the blocks from the BDN with the blockchain node, and with --feed-verification-rpc if set, alerting through --feed-alert-webhook and annotating the block notifications while they diverge",
+	}
+	FeedVerificationRPCFlag = &cli.StringFlag{
+		Name:  "feed-verification-rpc",
+		Usage: "URL of a public HTTP JSON-RPC endpoint the --feed-verification blocks are also compared with",
+	}
+	FeedVerificationThresholdFlag = &cli.Uint64Flag{
+		Name:  "feed-verification-threshold",
+		Usage: "number of blocks the BDN can be ahead or behind a --feed-verification source before it diverges, the block hashes are compared this many blocks below the heads",
+		Value: 3,
+	}
+	FeedVerificationIntervalFlag = &cli.DurationFlag{
+		Name:  "feed-verification-interval",
+		Usage: "interval between the --feed-verification comparisons",
+		Value: 30 * time.Second,
+	}
+	ClockSkewThresholdFlag = &cli.DurationFlag{
+		Name:  "clock-skew-threshold",
+		Usage: "offset of the local clock against --ntp-server beyond which warnings are logged and the time fields of the notifications are annotated with the skew, 0 disables the check",
+		Value: 50 * time.Millisecond,
+	}
+	SecurityWebhookFlag = &cli.StringSliceFlag{
+		Name:  "security-webhook",
+		Usage: "[account-id=]url the security events of the account are posted to as JSON: repeated auth failures, connections from new IP ranges, quota exhaustion and calls of privileged methods. The webhook without account-id receives the events of the other accounts",
+	}
+	SecurityAuthFailuresFlag = &cli.IntFlag{
+		Name:  "security-auth-failures",
+		Usage: "number of failed authentications of an account within --security-event-window which fires a --security-webhook event",
+		Value: 5,
+	}
+	SecurityEventWindowFlag = &cli.DurationFlag{
+		Name:  "security-event-window",
+		Usage: "period the auth failures are counted over, the same --security-webhook event is not posted again within it",
+		Value: time.Minute,
+	}
+	JWTJWKSURLFlag = &cli.StringFlag{
+		Name:  "jwt-jwks-url",
+		Usage: "URL of the JWKS of an identity provider, the websocket clients can then authenticate with an RS256 or ES256 JWT in an \"Authorization: Bearer <token>\" header, which the HTTP server requires",
+	}
+	JWTIssuerFlag = &cli.StringFlag{
+		Name:  "jwt-issuer",
+		Usage: "expected iss claim of the --jwt-jwks-url tokens, not checked if not set",
+	}
+	JWTAudienceFlag = &cli.StringFlag{
+		Name:  "jwt-audience",
+		Usage: "expected aud claim of the --jwt-jwks-url tokens, not checked if not set",
+	}
+	JWTAccountClaimFlag = &cli.StringFlag{
+		Name:  "jwt-account-claim",
+		Usage: "claim of the --jwt-jwks-url tokens holding the account ID of the client",
+		Value: "sub",
+	}
+	JWTRefreshIntervalFlag = &cli.DurationFlag{
+		Name:  "jwt-refresh-interval",
+		Usage: "interval between the fetches of the --jwt-jwks-url keys, a token signed with an unknown key also fetches them",
+		Value: time.Hour,
+	}
+	TrustedProxiesFlag = &cli.StringSliceFlag{
+		Name:  "trusted-proxies",
+		Usage: "IPs and CIDRs of the load balancers in front of the websocket and HTTP servers, the client address of the X-Forwarded-For header of their requests is used for logging, rate limiting and bans",
+	}
+	ProxyProtocolFlag = &cli.BoolFlag{
+		Name:  "proxy-protocol",
+		Usage: "read a PROXY protocol v2 header at the start of the websocket, HTTP and gRPC connections from the --trusted-proxies, to get the client address behind an L4 load balancer",
+	}
+	RPCRateLimitFlag = &cli.StringSliceFlag{
+		Name:  "rpc-rate-limit",
+		Usage: "tier:method=calls-per-second overriding the default rate limit of each account of the tier, 0 removes the limit. The methods are blxr_tx, blxr_batch_tx, subscribe and onblock_call, which counts the calls registered by onBlock subscriptions",
+	}
+	OutboundProxyFlag = &cli.StringSliceFlag{
+		Name:  "outbound-proxy",
+		Usage: "[destination=]url of the socks5:// or http:// proxy the SDN and relay connections to the destination are opened through, or direct. The destination is a host, a *.domain wildcard, an IP or a CIDR, the proxy without destination applies to the other destinations",
+	}
+	RelayDNSFlag = &cli.StringFlag{
+		Name:  "relay-dns",
+		Usage: "DNS name whose SRV records, or TXT records holding comma separated ip:port relays if it has no SRV record, direct the relay connections instead of --relays. The connections are migrated when the records change",
+	}
+	RelayDNSIntervalFlag = &cli.DurationFlag{
+		Name:  "relay-dns-interval",
+		Usage: "interval between the resolutions of the --relay-dns records",
+		Value: time.Minute,
+	}
+	BlockchainHeartbeatTimeoutFlag = &cli.DurationFlag{
+		Name:  "blockchain-heartbeat-timeout",
+		Usage: "restart the blockchain client if it does not acknowledge the heartbeats sent over the bridge within the timeout, 0 disables the supervision",
+		Value: 0,
+	}
+	BlockBackfillMaxBlocksFlag = &cli.IntFlag{
+		Name:  "block-backfill-max-blocks",
+		Usage: "largest gap of block heights from the node which is backfilled from the node and published to the block feeds marked as backfilled, 0 disables the backfill",
+		Value: 32,
+	}
+	TxStorePersistenceFlag = &cli.BoolFlag{
+		Name:  "tx-store-persistence",
+		Usage: "persists the transactions of the TxStore in the data directory, so their short IDs are recovered after a restart while the TxStore re-syncs with the relay",
+		Value: false,
+	}
+	BlockchainBridgeGRPCListenFlag = &cli.StringFlag{
+		Name:  "blockchain-bridge-grpc-listen",
+		Usage: "address the gateway listens on for a blockchain client running in another process or host with --blockchain-bridge-grpc-connect, instead of running the Ethereum p2p client in-process",
+	}
+	BlockchainBridgeGRPCConnectFlag = &cli.StringFlag{
+		Name:  "blockchain-bridge-grpc-connect",
+		Usage: "runs only the Ethereum p2p client, without dynamic peers, connected to the bridge of the gateway listening on the address with --blockchain-bridge-grpc-listen",
+	}
+	BridgeAdaptiveBufferCapFlag = &cli.IntFlag{
+		Name:  "bridge-adaptive-buffer-cap",
+		Usage: "largest number of transaction messages queued beyond the buffer of each bridge transaction channel while it is full, instead of dropping them (0 disables the adaptive buffers)",
+		Value: 0,
+	}
+	TopOfBlockTxsFlag = &cli.IntFlag{
+		Name:  "top-of-block-txs",
+		Usage: "number of transactions at the top of each block, with their positions and gas prices, published to the topOfBlock feed as soon as the block is received from the BDN or the node",
+		Value: 10,
+	}
 )
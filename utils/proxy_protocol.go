@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// proxyProtocolHeaderTimeout bounds the time a trusted proxy has to send the PROXY protocol header
+	proxyProtocolHeaderTimeout = 5 * time.Second
+
+	proxyProtocolVersion2    = 0x2
+	proxyProtocolCmdLocal    = 0x0
+	proxyProtocolCmdProxy    = 0x1
+	proxyProtocolTCPOverIPv4 = 0x11
+	proxyProtocolTCPOverIPv6 = 0x21
+)
+
+// proxyProtocolSignature starts every PROXY protocol v2 header
+var proxyProtocolSignature = []byte{0x0d, 0x0a, 0x0d, 0x0a, 0x00, 0x0d, 0x0a, 0x51, 0x55, 0x49, 0x54, 0x0a}
+
+// TrustedProxies are the networks of the load balancers in front of the gateway, their PROXY protocol headers and
+// X-Forwarded-For headers are trusted to hold the address of the client
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses a list of IPs and CIDRs
+func ParseTrustedProxies(values []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %v, expected an IP or a CIDR", value)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %v, expected an IP or a CIDR", value)
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+// Contains returns whether the IP belongs to a trusted proxy
+func (p TrustedProxies) Contains(ip net.IP) bool {
+	for _, ipNet := range p {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientAddress returns the address of the client of the request: the right-most address of the X-Forwarded-For
+// header which is not a trusted proxy if the request comes from a trusted proxy, its remote address otherwise. The
+// port of a forwarded address is unknown and reported as 0.
+func (p TrustedProxies) ClientAddress(r *http.Request) string {
+	ip := net.ParseIP(RemoteIP(r.RemoteAddr))
+	if ip == nil || !p.Contains(ip) {
+		return r.RemoteAddr
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	var client net.IP
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			// the addresses left of a malformed one cannot be trusted
+			break
+		}
+		client = hop
+		if !p.Contains(hop) {
+			break
+		}
+	}
+	if client == nil {
+		return r.RemoteAddr
+	}
+	return net.JoinHostPort(client.String(), "0")
+}
+
+// NewProxyProtocolListener wraps the listener so the connections from the trusted proxies report the client address
+// of their PROXY protocol v2 header as remote address. The connections from the trusted proxies must start with the
+// header, the other connections are used as they are.
+func NewProxyProtocolListener(listener net.Listener, trusted TrustedProxies) net.Listener {
+	return &proxyProtocolListener{Listener: listener, trusted: trusted}
+}
+
+type proxyProtocolListener struct {
+	net.Listener
+	trusted TrustedProxies
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	// the header is read by the goroutine serving the connection, a slow proxy must not block the accept loop
+	return &proxyProtocolConn{Conn: conn, trusted: l.trusted}, nil
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+	trusted TrustedProxies
+
+	once   sync.Once
+	reader io.Reader
+	remote net.Addr
+	err    error
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(b)
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	return c.remote
+}
+
+func (c *proxyProtocolConn) readHeader() {
+	c.reader = c.Conn
+	c.remote = c.Conn.RemoteAddr()
+	tcpAddr, ok := c.remote.(*net.TCPAddr)
+	if !ok || !c.trusted.Contains(tcpAddr.IP) {
+		return
+	}
+
+	_ = c.Conn.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout))
+	defer func() { _ = c.Conn.SetReadDeadline(time.Time{}) }()
+
+	reader := bufio.NewReader(c.Conn)
+	c.reader = reader
+	remote, err := readProxyProtocolHeader(reader)
+	if err != nil {
+		c.err = fmt.Errorf("invalid PROXY protocol header from %v: %v", c.remote, err)
+		return
+	}
+	if remote != nil {
+		c.remote = remote
+	}
+}
+
+// readProxyProtocolHeader reads a PROXY protocol v2 header, returning the source address it holds. It returns nil for
+// the LOCAL command of the health checks of the proxy and for the address families other than TCP over IPv4 and IPv6.
+func readProxyProtocolHeader(r io.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:12], proxyProtocolSignature) {
+		return nil, errors.New("missing PROXY protocol v2 signature")
+	}
+	if version := header[12] >> 4; version != proxyProtocolVersion2 {
+		return nil, fmt.Errorf("unsupported version %v", version)
+	}
+	command := header[12] & 0x0f
+	if command != proxyProtocolCmdLocal && command != proxyProtocolCmdProxy {
+		return nil, fmt.Errorf("unsupported command %v", command)
+	}
+	// the addresses are followed by TLVs, which are skipped
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	if command == proxyProtocolCmdLocal {
+		return nil, nil
+	}
+
+	switch header[13] {
+	case proxyProtocolTCPOverIPv4:
+		if len(payload) < 2*net.IPv4len+4 {
+			return nil, errors.New("truncated IPv4 addresses")
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(payload[:net.IPv4len]),
+			Port: int(binary.BigEndian.Uint16(payload[2*net.IPv4len:])),
+		}, nil
+	case proxyProtocolTCPOverIPv6:
+		if len(payload) < 2*net.IPv6len+4 {
+			return nil, errors.New("truncated IPv6 addresses")
+		}
+		return &net.TCPAddr{
+			IP:   net.IP(payload[:net.IPv6len]),
+			Port: int(binary.BigEndian.Uint16(payload[2*net.IPv6len:])),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func proxyProtocolHeader(command byte, family byte, addresses []byte) []byte {
+	header := append([]byte{}, proxyProtocolSignature...)
+	header = append(header, proxyProtocolVersion2<<4|command, family, 0, 0)
+	binary.BigEndian.PutUint16(header[14:], uint16(len(addresses)))
+	return append(header, addresses...)
+}
+
+func TestProxyProtocolListener(t *testing.T) {
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	trusted, err := ParseTrustedProxies([]string{"127.0.0.1"})
+	require.NoError(t, err)
+	listener := NewProxyProtocolListener(inner, trusted)
+	defer func() { _ = listener.Close() }()
+
+	// 203.0.113.7:4000 -> 10.0.0.1:443 followed by a TLV
+	addresses := []byte{203, 0, 113, 7, 10, 0, 0, 1, 0x0f, 0xa0, 0x01, 0xbb, 0x04, 0x00, 0x01, 0x00}
+	for _, test := range []struct {
+		header []byte
+		remote string
+	}{
+		{proxyProtocolHeader(proxyProtocolCmdProxy, proxyProtocolTCPOverIPv4, addresses), "203.0.113.7:4000"},
+		{proxyProtocolHeader(proxyProtocolCmdLocal, 0, nil), ""},
+	} {
+		client, err := net.Dial("tcp", inner.Addr().String())
+		require.NoError(t, err)
+		_, err = client.Write(append(test.header, []byte("hello")...))
+		require.NoError(t, err)
+
+		conn, err := listener.Accept()
+		require.NoError(t, err)
+		remote := test.remote
+		if remote == "" {
+			remote = client.LocalAddr().String()
+		}
+		assert.Equal(t, remote, conn.RemoteAddr().String())
+		payload := make([]byte, 5)
+		_, err = io.ReadFull(conn, payload)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(payload))
+		_ = client.Close()
+		_ = conn.Close()
+	}
+
+	// a trusted proxy must send the header
+	client, err := net.Dial("tcp", inner.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+	_, err = client.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+	require.NoError(t, err)
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestTrustedProxiesClientAddress(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "fd00::1"})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		remote    string
+		forwarded []string
+		client    string
+	}{
+		{"10.0.0.1:1000", []string{"203.0.113.7"}, "203.0.113.7:0"},
+		// the addresses added by the trusted proxies are skipped
+		{"10.0.0.1:1000", []string{"198.51.100.1, 203.0.113.7", "10.0.0.2"}, "203.0.113.7:0"},
+		{"[fd00::1]:1000", []string{"2001:db8::7"}, "[2001:db8::7]:0"},
+		// the header of an untrusted client is ignored
+		{"203.0.113.8:1000", []string{"203.0.113.7"}, "203.0.113.8:1000"},
+		{"10.0.0.1:1000", nil, "10.0.0.1:1000"},
+		{"10.0.0.1:1000", []string{"unknown"}, "10.0.0.1:1000"},
+		{"10.0.0.1:1000", []string{"10.0.0.3"}, "10.0.0.3:0"},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = test.remote
+		for _, forwarded := range test.forwarded {
+			r.Header.Add("X-Forwarded-For", forwarded)
+		}
+		assert.Equal(t, test.client, trusted.ClientAddress(r), test)
+	}
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy"})
+	assert.Error(t, err)
+}
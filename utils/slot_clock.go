@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	log "github.com/bloXroute-Labs/gateway/v2/logger"
+)
+
+const (
+	ntpPacketSize = 48
+	ntpTimeout    = 5 * time.Second
+	// seconds between the NTP epoch (1900) and the unix epoch (1970)
+	ntpEpochOffset = 2208988800
+)
+
+// SlotTime is a snapshot of the slot clock, the slot fields are zero if the network has no slots
+type SlotTime struct {
+	Time         time.Time
+	Offset       time.Duration
+	Slot         uint64
+	SlotDuration time.Duration
+	NextSlot     time.Time
+	// Skewed is true if the offset of the local clock is beyond the skew threshold
+	Skewed bool
+}
+
+// SlotClock is a Clock corrected by the offset measured against an NTP server, which maps times to the
+// slots of the network. Timers and tickers are delegated to the underlying clock.
+type SlotClock struct {
+	Clock
+	genesis      time.Time
+	slotDuration time.Duration
+	ntpServer    string
+	// skewThreshold is the offset beyond which the local clock is reported as skewed, zero disables the check
+	skewThreshold time.Duration
+	offset        atomic.Int64
+	skewed        atomic.Bool
+	queryOffset   func(server string) (time.Duration, error)
+}
+
+// NewSlotClock creates a slot clock for the network genesis time and slot duration, which is corrected with
+// the NTP server if it is set. A zero slot duration creates a clock without slots, only correcting the time. The local clock is reported as skewed while its offset is beyond the skew threshold.
+func NewSlotClock(clock Clock, genesis time.Time, slotDuration time.Duration, ntpServer string, skewThreshold time.Duration) *SlotClock {
+	return &SlotClock{
+		Clock:         clock,
+		genesis:       genesis,
+		slotDuration:  slotDuration,
+		ntpServer:     ntpServer,
+		skewThreshold: skewThreshold,
+		queryOffset:   queryNTPOffset,
+	}
+}
+
+// Now returns the current time corrected by the measured offset
+func (c *SlotClock) Now() time.Time {
+	return c.Clock.Now().Add(c.Offset())
+}
+
+// Offset returns the measured offset of the local clock
+func (c *SlotClock) Offset() time.Duration {
+	return time.Duration(c.offset.Load())
+}
+
+// Skew returns the offset of the local clock if it is beyond the skew threshold, zero otherwise
+func (c *SlotClock) Skew() time.Duration {
+	if !c.skewed.Load() {
+		return 0
+	}
+	return c.Offset()
+}
+
+// SlotDuration returns the duration of a slot
+func (c *SlotClock) SlotDuration() time.Duration {
+	return c.slotDuration
+}
+
+// SlotAt returns the slot of the time
+func (c *SlotClock) SlotAt(t time.Time) uint64 {
+	if c.slotDuration <= 0 || t.Before(c.genesis) {
+		return 0
+	}
+	return uint64(t.Sub(c.genesis) / c.slotDuration)
+}
+
+// SlotStart returns the start time of the slot
+func (c *SlotClock) SlotStart(slot uint64) time.Time {
+	return c.genesis.Add(time.Duration(slot) * c.slotDuration)
+}
+
+// CurrentSlot returns the current slot
+func (c *SlotClock) CurrentSlot() uint64 {
+	return c.SlotAt(c.Now())
+}
+
+// SlotTime returns a snapshot of the slot clock
+func (c *SlotClock) SlotTime() SlotTime {
+	now := c.Now()
+	slotTime := SlotTime{
+		Time:   now,
+		Offset: c.Offset(),
+		Skewed: c.skewed.Load(),
+	}
+	if c.slotDuration > 0 {
+		slotTime.Slot = c.SlotAt(now)
+		slotTime.SlotDuration = c.slotDuration
+		slotTime.NextSlot = c.SlotStart(slotTime.Slot + 1)
+	}
+	return slotTime
+}
+
+// Sync measures the offset of the local clock against the NTP server
+func (c *SlotClock) Sync() error {
+	if c.ntpServer == "" {
+		return nil
+	}
+
+	offset, err := c.queryOffset(c.ntpServer)
+	if err != nil {
+		return err
+	}
+	previous := time.Duration(c.offset.Swap(int64(offset)))
+	if drift := offset - previous; drift > time.Millisecond || drift < -time.Millisecond {
+		log.Debugf("slot clock offset against %v changed from %v to %v", c.ntpServer, previous, offset)
+	}
+
+	skewed := c.skewThreshold > 0 && (offset > c.skewThreshold || offset < -c.skewThreshold)
+	wasSkewed := c.skewed.Swap(skewed)
+	switch {
+	case skewed:
+		log.Warnf("local clock is off by %v against %v, beyond the threshold of %v: the times of the notifications are unreliable, check the time synchronization of the host", offset, c.ntpServer, c.skewThreshold)
+	case wasSkewed:
+		log.Infof("local clock offset against %v is back within the threshold of %v at %v", c.ntpServer, c.skewThreshold, offset)
+	}
+	return nil
+}
+
+// Run syncs the slot clock with the NTP server on the interval until the context is done
+func (c *SlotClock) Run(ctx context.Context, interval time.Duration) {
+	if c == nil || c.ntpServer == "" {
+		return
+	}
+
+	ticker := c.Clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Sync(); err != nil {
+			log.Warnf("failed to sync slot clock with %v: %v", c.ntpServer, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Alert():
+		}
+	}
+}
+
+// queryNTPOffset sends a SNTP request to the server and returns the offset of the local clock
+func queryNTPOffset(server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", server, ntpTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to NTP server %v: %v", server, err)
+	}
+	defer conn.Close()
+	if err = conn.SetDeadline(time.Now().Add(ntpTimeout)); err != nil {
+		return 0, err
+	}
+
+	request := make([]byte, ntpPacketSize)
+	request[0] = 0x1B // leap indicator 0, version 3, client mode
+	sent := time.Now()
+	if _, err = conn.Write(request); err != nil {
+		return 0, fmt.Errorf("failed to send NTP request to %v: %v", server, err)
+	}
+
+	response := make([]byte, ntpPacketSize)
+	if _, err = conn.Read(response); err != nil {
+		return 0, fmt.Errorf("failed to read NTP response from %v: %v", server, err)
+	}
+	received := time.Now()
+
+	serverReceived := ntpTime(response[32:40])
+	serverSent := ntpTime(response[40:48])
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[:4])
+	fraction := binary.BigEndian.Uint32(b[4:])
+	nanos := (int64(fraction) * int64(time.Second)) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, nanos)
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotClock(t *testing.T) {
+	mockClock := &MockClock{}
+	genesis := time.Unix(1000, 0)
+	mockClock.SetTime(genesis.Add(25 * time.Second))
+
+	clock := NewSlotClock(mockClock, genesis, 12*time.Second, "ntp.test", time.Second)
+	assert.Equal(t, uint64(2), clock.CurrentSlot())
+	assert.Equal(t, genesis.Add(36*time.Second), clock.SlotTime().NextSlot)
+	assert.Equal(t, uint64(0), clock.SlotAt(genesis.Add(-time.Second)))
+
+	clock.queryOffset = func(server string) (time.Duration, error) {
+		assert.Equal(t, "ntp.test", server)
+		return 12 * time.Second, nil
+	}
+	require.NoError(t, clock.Sync())
+	assert.Equal(t, 12*time.Second, clock.Offset())
+	assert.Equal(t, genesis.Add(37*time.Second), clock.Now())
+	assert.Equal(t, uint64(3), clock.CurrentSlot())
+	assert.True(t, clock.SlotTime().Skewed)
+	assert.Equal(t, 12*time.Second, clock.Skew())
+
+	clock.queryOffset = func(string) (time.Duration, error) { return -500 * time.Millisecond, nil }
+	require.NoError(t, clock.Sync())
+	assert.False(t, clock.SlotTime().Skewed)
+	assert.Zero(t, clock.Skew())
+}
+
+func TestNTPTime(t *testing.T) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[:4], ntpEpochOffset+10)
+	binary.BigEndian.PutUint32(b[4:], 1<<31)
+	assert.Equal(t, time.Unix(10, int64(time.Second/2)), ntpTime(b))
+}
+
+func TestSlotClock_NoSlots(t *testing.T) {
+	mockClock := &MockClock{}
+	mockClock.SetTime(time.Unix(1000, 0))
+
+	clock := NewSlotClock(mockClock, time.Time{}, 0, "", 0)
+	slotTime := clock.SlotTime()
+	assert.Equal(t, time.Unix(1000, 0), slotTime.Time)
+	assert.Zero(t, slotTime.Slot)
+	assert.Zero(t, slotTime.SlotDuration)
+	assert.True(t, slotTime.NextSlot.IsZero())
+}
@@ -42,6 +42,15 @@ func GenerateValidEnode(ip string, tcp int, udp int) *enode.Node {
 	return enode.NewV4(&key.PublicKey, net.IPv4(byte(ipByte1), byte(ipByte2), byte(ipByte3), byte(ipByte4)), tcp, udp)
 }
 
+// RemoteIP returns the IP of a host:port remote address, IPv6 addresses are returned without their brackets
+func RemoteIP(remoteAddress string) string {
+	host, _, err := net.SplitHostPort(remoteAddress)
+	if err != nil {
+		return remoteAddress
+	}
+	return host
+}
+
 // GetIP checks the existence of and returns the IP address for a host name
 func GetIP(host string) (string, error) {
 	addr := net.ParseIP(host)
@@ -30,3 +30,9 @@ func TestExtractArgsToMap(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, value4, "")
 }
+
+func TestRemoteIP(t *testing.T) {
+	assert.Equal(t, "10.0.0.1", RemoteIP("10.0.0.1:28333"))
+	assert.Equal(t, "2001:db8::1", RemoteIP("[2001:db8::1]:28333"))
+	assert.Equal(t, "10.0.0.1", RemoteIP("10.0.0.1"))
+}
@@ -0,0 +1,49 @@
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Info is the build information of the gateway, reported to the SDN and to the clients so fleets can be audited
+type Info struct {
+	Version            string   `json:"version"`
+	GitCommit          string   `json:"git_commit"`
+	BuildDate          string   `json:"build_date"`
+	GoVersion          string   `json:"go_version"`
+	MinProtocolVersion int64    `json:"min_protocol_version"`
+	ProtocolVersion    int64    `json:"protocol_version"`
+	ProtocolFeatures   []string `json:"protocol_features"`
+	FeatureFlags       []string `json:"feature_flags"`
+}
+
+// NewInfo returns the build information of the gateway supporting the provided wire protocol versions and features.
+// The feature flags are the build tags the gateway was compiled with.
+func NewInfo(minProtocolVersion, protocolVersion int64, protocolFeatures []string) Info {
+	info := Info{
+		Version:            BuildVersion,
+		GitCommit:          GitCommit,
+		BuildDate:          BuildDate,
+		MinProtocolVersion: minProtocolVersion,
+		ProtocolVersion:    protocolVersion,
+		ProtocolFeatures:   protocolFeatures,
+		FeatureFlags:       make([]string, 0),
+	}
+
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = buildInfo.GoVersion
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "-tags":
+			info.FeatureFlags = strings.Split(setting.Value, ",")
+		case "vcs.revision":
+			if info.GitCommit == "" {
+				info.GitCommit = setting.Value
+			}
+		}
+	}
+	return info
+}
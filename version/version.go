@@ -5,4 +5,6 @@ var (
 	BuildVersion = "2.112.5.0"
 	// BuildDate - date and time of build
 	BuildDate = "06-01-2022 08:08:18"
+	// GitCommit - git commit of the build, read from the Go build information if not set at build time
+	GitCommit = ""
 )